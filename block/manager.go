@@ -122,6 +122,9 @@ type manager struct {
 	finalizationCBs []finalizationCB
 	timestamper     module.Timestamper
 
+	// blocks above haltHeight are neither proposed nor imported
+	haltHeight int64
+
 	// pcm for last finalized block verification
 	pcmForLastBlock module.BTPProofContextMap
 	// next pcm in the last finalized block's result
@@ -309,6 +312,9 @@ func (m *manager) _import(
 	if bn == nil {
 		return nil, errors.Errorf("InvalidPreviousID(%x)", block.PrevID())
 	}
+	if err := m.checkHalt(bn.block.Height() + 1); err != nil {
+		return nil, err
+	}
 	csi, err := m.verifyNewBlock(block, bn.block)
 	if err != nil {
 		return nil, err
@@ -450,6 +456,9 @@ func (m *manager) _propose(
 	if bn == nil {
 		return nil, errors.Errorf("NoParentBlock(id=<%x>)", parentID)
 	}
+	if err := m.checkHalt(bn.block.Height() + 1); err != nil {
+		return nil, err
+	}
 	csi, _, err := m.verifyProofForLastBlock(bn.block, votes)
 	if err != nil {
 		return nil, err
@@ -964,6 +973,23 @@ func (m *manager) bucketFor(id db.BucketID) (*db.CodedBucket, error) {
 	return db.NewCodedBucket(m.db(), id, nil)
 }
 
+// SetHaltHeight makes the manager refuse to propose and to import blocks
+// above the height. Zero means no limit.
+func (m *manager) SetHaltHeight(height int64) {
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	m.haltHeight = height
+}
+
+func (m *manager) checkHalt(height int64) error {
+	if m.haltHeight > 0 && height > m.haltHeight {
+		return errors.InvalidStateError.Errorf(
+			"ScheduledHalt(height=%d,halt=%d)", height, m.haltHeight)
+	}
+	return nil
+}
+
 func (m *manager) Finalize(block module.BlockCandidate) error {
 	m.syncer.begin()
 	defer m.syncer.end()
@@ -1219,6 +1245,27 @@ func (m *manager) getTransactionInfo(id []byte) (module.TransactionInfo, error)
 	}, nil
 }
 
+func (m *manager) GetReceiptsByHeight(height int64) (module.ReceiptList, error) {
+	m.syncer.begin()
+	defer m.syncer.end()
+
+	if !m.running {
+		return nil, errors.New("not running")
+	}
+
+	if height > m.finalized.block.Height() {
+		return nil, errors.NotFoundError.Errorf("no block for %d", height)
+	}
+	if height+1 > m.finalized.block.Height() {
+		return nil, ErrResultNotFinalized
+	}
+	rblock, err := m.getBlockByHeight(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	return m.sm.ReceiptListFromResult(rblock.Result(), module.TransactionGroupNormal)
+}
+
 func (m *manager) getTransactionLocator(id []byte) (*transactionLocator, error) {
 	tlb, err := m.bucketFor(db.TransactionLocatorByHash)
 	if err != nil {
@@ -28,6 +28,7 @@ import (
 	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/platform/basic"
@@ -556,3 +557,75 @@ func TestManager_GetTransactionInfo(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(module.StatusSuccess, rct.Status())
 }
+
+func TestManager_GetReceiptsByHeight(t *testing.T) {
+	assert := assert.New(t)
+	nd := test.NewNode(t)
+	defer nd.Close()
+	tx := nd.NewTx()
+	nd.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(), tx.String())
+
+	_, err := nd.BM.GetReceiptsByHeight(1)
+	assert.True(block.ResultNotFinalizedError.Equals(err))
+	_, err = nd.BM.GetReceiptsByHeight(2)
+	assert.True(errors.NotFoundError.Equals(err))
+
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	rl, err := nd.BM.GetReceiptsByHeight(1)
+	assert.NoError(err)
+	itr := rl.Iterator()
+	assert.True(itr.Has())
+	rct, err := itr.Get()
+	assert.NoError(err)
+	assert.EqualValues(module.StatusSuccess, rct.Status())
+	assert.NoError(itr.Next())
+	assert.False(itr.Has())
+}
+
+func TestManager_SetHaltHeight(t *testing.T) {
+	assert := assert.New(t)
+	nd := test.NewNode(t)
+	defer nd.Close()
+
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	blk := nd.GetLastBlock()
+
+	hs, ok := nd.BM.(interface{ SetHaltHeight(height int64) })
+	assert.True(ok)
+	hs.SetHaltHeight(1)
+
+	// neither propose nor import blocks above the height
+	_, err := nd.BM.Propose(blk.ID(), consensus.NewEmptyCommitVoteList(),
+		func(module.BlockCandidate, error) {},
+	)
+	assert.True(errors.InvalidStateError.Equals(err))
+
+	nd2 := test.NewNode(t)
+	defer nd2.Close()
+	nd2.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd2.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	nd3 := test.NewNode(t)
+	defer nd3.Close()
+	nd3.BM.(interface{ SetHaltHeight(height int64) }).SetHaltHeight(1)
+	for height := int64(1); height <= 2; height++ {
+		blk2, err := nd2.BM.GetBlockByHeight(height)
+		assert.NoError(err)
+		ch := make(chan module.BlockCandidate, 1)
+		_, err = nd3.BM.ImportBlock(blk2, 0, func(bc module.BlockCandidate, err error) {
+			assert.NoError(err)
+			ch <- bc
+		})
+		if height > 1 {
+			assert.True(errors.InvalidStateError.Equals(err))
+			break
+		}
+		assert.NoError(err)
+		assert.NoError(nd3.BM.Finalize(<-ch))
+	}
+
+	// zero cancels the limit
+	hs.SetHaltHeight(0)
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	assert.EqualValues(2, nd.GetLastBlock().Height())
+}
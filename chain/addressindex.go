@@ -0,0 +1,39 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/addressindex"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func (c *singleChain) startAddressIndex() {
+	if !c.cfg.AddressIndex {
+		return
+	}
+	c.aiLock.Lock()
+	defer c.aiLock.Unlock()
+
+	c.ai = addressindex.New(c)
+	c.ai.Start()
+}
+
+func (c *singleChain) stopAddressIndex() {
+	c.aiLock.Lock()
+	defer c.aiLock.Unlock()
+
+	if c.ai != nil {
+		c.ai.Stop()
+		c.ai = nil
+	}
+}
+
+// AddressIndex returns the index of addresses if it's enabled with
+// address_index of the chain configuration.
+func (c *singleChain) AddressIndex() (*addressindex.Index, error) {
+	c.aiLock.Lock()
+	defer c.aiLock.Unlock()
+
+	if c.ai == nil {
+		return nil, errors.UnsupportedError.New("AddressIndexDisabled")
+	}
+	return c.ai, nil
+}
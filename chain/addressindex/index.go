@@ -0,0 +1,846 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressindex
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	keyCursor = "address_index.cursor"
+	keySize   = "address_index.size."
+	keyItem   = "address_index.item."
+)
+
+const (
+	EventLogIScoreClaimedV2 = "IScoreClaimedV2(Address,int,int)"
+)
+
+// ScanLimit is the max number of references looked up by Transactions.
+const ScanLimit = 10000
+
+// Types of items in statements.
+const (
+	TypeTransfer = "transfer"
+	TypeInternal = "internal"
+	TypeFee      = "fee"
+	TypeReward   = "reward"
+)
+
+// Provider is implemented by chains with the index of addresses.
+type Provider interface {
+	AddressIndex() (*Index, error)
+}
+
+// Flags of references. FlagFrom and FlagTo are set if the address is the
+// sender or the receiver of the transaction. References added before flags
+// were introduced don't have FlagKnown, and Reindex replaces them.
+const (
+	FlagFrom = 1 << iota
+	FlagTo
+	FlagKnown
+)
+
+// Ref is the reference to the transaction related to the address.
+type Ref struct {
+	Height  int64
+	Group   module.TransactionGroup
+	TxIndex int
+	Flags   int
+}
+
+// Item is a value movement of the address in the statement. Amount is
+// negative for the value going out of the address. Counterparty is nil
+// for fees.
+type Item struct {
+	Height       int64
+	Timestamp    int64
+	TxHash       []byte
+	Type         string
+	Counterparty module.Address
+	Amount       *big.Int
+}
+
+// Index has transactions related to each address in order of height. It
+// follows finalized blocks and records references to transactions sending
+// or receiving value, paying fees, transferring value internally or
+// claiming rewards, so that statements of addresses can be made without
+// scanning all blocks.
+type Index struct {
+	chain module.Chain
+	dbase db.Database
+	log   log.Logger
+
+	// lock serializes writers of the index.
+	lock sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (idx *Index) bucket() (db.Bucket, error) {
+	return idx.dbase.GetBucket(db.ChainProperty)
+}
+
+func sizeKey(addr []byte) []byte {
+	return append([]byte(keySize), addr...)
+}
+
+func itemKey(addr []byte, n int64) []byte {
+	key := make([]byte, len(keyItem)+len(addr)+8)
+	copy(key, keyItem)
+	copy(key[len(keyItem):], addr)
+	binary.BigEndian.PutUint64(key[len(keyItem)+len(addr):], uint64(n))
+	return key
+}
+
+func getInt64(bk db.Bucket, key []byte) (int64, error) {
+	bs, err := bk.Get(key)
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var v int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// Cursor returns the height of the next block to be indexed. Transactions
+// in the blocks lower than the cursor are in the index.
+func (idx *Index) Cursor() (int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return 0, err
+	}
+	return getInt64(bk, []byte(keyCursor))
+}
+
+// Size returns the number of transactions related to the address.
+func (idx *Index) Size(addr module.Address) (int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return 0, err
+	}
+	return getInt64(bk, sizeKey(addr.Bytes()))
+}
+
+func (idx *Index) get(bk db.Bucket, addr []byte, n int64) (*Ref, error) {
+	bs, err := bk.Get(itemKey(addr, n))
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.NotFoundError.Errorf("NoRef(index=%d)", n)
+	}
+	ref := new(Ref)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, ref); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// Add adds references of transactions in the block at the height for each
+// address, and moves the cursor to the next block. Addresses already having
+// references of the block are ignored, so that it's safe to add the block
+// again.
+func (idx *Index) Add(height int64, refs map[string][]*Ref) error {
+	return idx.add(height, refs, 0, nil)
+}
+
+// add adds references like Add. If truncated is not nil, references of the
+// addresses from the height of base are removed before adding ones, and the
+// addresses are recorded in truncated so that it's done only once.
+func (idx *Index) add(height int64, refs map[string][]*Ref, base int64, truncated map[string]bool) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	bk, err := idx.bucket()
+	if err != nil {
+		return err
+	}
+	for key, rs := range refs {
+		addr := []byte(key)
+		var size int64
+		if truncated != nil && !truncated[key] {
+			size, err = idx.search(bk, addr, func(ref *Ref) (bool, error) {
+				return ref.Height >= base, nil
+			})
+			truncated[key] = true
+		} else {
+			size, err = getInt64(bk, sizeKey(addr))
+		}
+		if err != nil {
+			return err
+		}
+		if size > 0 {
+			last, err := idx.get(bk, addr, size-1)
+			if err != nil {
+				return err
+			}
+			if last.Height >= height {
+				continue
+			}
+		}
+		for _, ref := range rs {
+			if err := bk.Set(itemKey(addr, size), codec.BC.MustMarshalToBytes(ref)); err != nil {
+				return err
+			}
+			size += 1
+		}
+		if err := bk.Set(sizeKey(addr), codec.BC.MustMarshalToBytes(size)); err != nil {
+			return err
+		}
+	}
+	return bk.Set([]byte(keyCursor), codec.BC.MustMarshalToBytes(height+1))
+}
+
+// Refs returns at most limit references of transactions related to the
+// address from the start.
+func (idx *Index) Refs(addr module.Address, start int64, limit int) ([]*Ref, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return nil, err
+	}
+	size, err := getInt64(bk, sizeKey(addr.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	refs := []*Ref{}
+	for n := start; n < size && len(refs) < limit; n++ {
+		ref, err := idx.get(bk, addr.Bytes(), n)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// Search returns the index of the first reference of the address for which
+// f returns true, or the number of references if there is none. f must be
+// false for references before ones for which it's true.
+func (idx *Index) Search(addr module.Address, f func(ref *Ref) (bool, error)) (int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return 0, err
+	}
+	return idx.search(bk, addr.Bytes(), f)
+}
+
+func (idx *Index) search(bk db.Bucket, addr []byte, f func(ref *Ref) (bool, error)) (int64, error) {
+	size, err := getInt64(bk, sizeKey(addr))
+	if err != nil {
+		return 0, err
+	}
+	var serr error
+	n := sort.Search(int(size), func(i int) bool {
+		if serr != nil {
+			return true
+		}
+		ref, err := idx.get(bk, addr, int64(i))
+		if err == nil {
+			var ok bool
+			if ok, err = f(ref); err == nil {
+				return ok
+			}
+		}
+		serr = err
+		return true
+	})
+	if serr != nil {
+		return 0, serr
+	}
+	return int64(n), nil
+}
+
+// Statement returns items of transactions related to the address in the
+// blocks with the timestamp from the timestamp to the timestamp in
+// micro-second. Zero for the timestamp means no limit. It returns items of
+// at most limit transactions from the start, and the index of the next
+// transaction. The index is -1 if there are no more transactions.
+func (idx *Index) Statement(addr module.Address, from, to int64, start int64, limit int) ([]*Item, int64, error) {
+	bm := idx.chain.BlockManager()
+	sm := idx.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, 0, errors.InvalidStateError.New("Stopped")
+	}
+	timestampOf := func(height int64) (int64, error) {
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			return 0, err
+		}
+		return blk.Timestamp(), nil
+	}
+	if from > 0 {
+		n, err := idx.Search(addr, func(ref *Ref) (bool, error) {
+			ts, err := timestampOf(ref.Height)
+			return ts >= from, err
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if n > start {
+			start = n
+		}
+	}
+	end, err := idx.Size(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if to > 0 {
+		if end, err = idx.Search(addr, func(ref *Ref) (bool, error) {
+			ts, err := timestampOf(ref.Height)
+			return ts > to, err
+		}); err != nil {
+			return nil, 0, err
+		}
+	}
+	if int64(limit) > end-start {
+		limit = int(end - start)
+	}
+	if limit <= 0 {
+		return []*Item{}, -1, nil
+	}
+	refs, err := idx.Refs(addr, start, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	items := []*Item{}
+	for _, ref := range refs {
+		ri, err := idx.itemsOf(addr, ref)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, ri...)
+	}
+	next := start + int64(len(refs))
+	if next >= end {
+		next = -1
+	}
+	return items, next, nil
+}
+
+// Transaction is the transaction related to the address with the block
+// including it and the receipt.
+type Transaction struct {
+	Ref
+	Block       module.Block
+	Transaction module.Transaction
+	Receipt     module.Receipt
+}
+
+// Transactions returns at most limit transactions of the address from the
+// start, which have any of flags like FlagFrom. They are in the reverse
+// order if reverse is true. It returns the index of the next reference to
+// look up, which is -1 if there are no more references. At most ScanLimit
+// references are looked up at once, so it may return fewer transactions
+// than limit before the end.
+func (idx *Index) Transactions(addr module.Address, flags int, start int64, limit int, reverse bool) ([]*Transaction, int64, error) {
+	if idx.chain.BlockManager() == nil || idx.chain.ServiceManager() == nil {
+		return nil, 0, errors.InvalidStateError.New("Stopped")
+	}
+	bk, err := idx.bucket()
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := getInt64(bk, sizeKey(addr.Bytes()))
+	if err != nil {
+		return nil, 0, err
+	}
+	step := int64(1)
+	if reverse {
+		step = -1
+		if start >= size {
+			start = size - 1
+		}
+	}
+	txs := []*Transaction{}
+	n := start
+	for scanned := 0; n >= 0 && n < size && len(txs) < limit && scanned < ScanLimit; n, scanned = n+step, scanned+1 {
+		ref, err := idx.get(bk, addr.Bytes(), n)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ref.Group != module.TransactionGroupNormal {
+			continue
+		}
+		if (ref.Flags&FlagKnown) != 0 && (ref.Flags&flags) == 0 {
+			continue
+		}
+		blk, tx, r, err := idx.transactionOf(ref)
+		if err != nil {
+			return nil, 0, err
+		}
+		if (ref.Flags & FlagKnown) == 0 {
+			ref.Flags = flagsOf(addr, tx, r)
+			if (ref.Flags & flags) == 0 {
+				continue
+			}
+		}
+		txs = append(txs, &Transaction{
+			Ref:         *ref,
+			Block:       blk,
+			Transaction: tx,
+			Receipt:     r,
+		})
+	}
+	if n < 0 || n >= size {
+		n = -1
+	}
+	return txs, n, nil
+}
+
+// transactionOf returns the block, the transaction and the receipt of the
+// reference.
+func (idx *Index) transactionOf(ref *Ref) (module.Block, module.Transaction, module.Receipt, error) {
+	bm := idx.chain.BlockManager()
+	sm := idx.chain.ServiceManager()
+	blk, err := bm.GetBlockByHeight(ref.Height)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	next, err := bm.GetBlockByHeight(ref.Height + 1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	txs := blk.NormalTransactions()
+	if ref.Group == module.TransactionGroupPatch {
+		txs = blk.PatchTransactions()
+	}
+	tx, err := txs.Get(ref.TxIndex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rl, err := sm.ReceiptListFromResult(next.Result(), ref.Group)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r, err := rl.Get(ref.TxIndex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return blk, tx, r, nil
+}
+
+// itemsOf returns value movements of the address by the transaction.
+func (idx *Index) itemsOf(addr module.Address, ref *Ref) ([]*Item, error) {
+	blk, tx, r, err := idx.transactionOf(ref)
+	if err != nil {
+		return nil, err
+	}
+	items, err := ItemsOf(addr, tx, r)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.Height = ref.Height
+		item.Timestamp = blk.Timestamp()
+		item.TxHash = tx.ID()
+	}
+	return items, nil
+}
+
+func valueOf(tx module.Transaction) (module.Address, *big.Int) {
+	js, err := tx.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, nil
+	}
+	jso, ok := js.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var to module.Address
+	if s, ok := jso["to"].(string); ok {
+		to, _ = common.NewAddressFromString(s)
+	}
+	var value common.HexInt
+	if s, ok := jso["value"].(string); ok {
+		if _, ok := value.SetString(s, 0); !ok {
+			return to, nil
+		}
+	}
+	return to, &value.Int
+}
+
+// ItemsOf returns value movements of the address by the transaction with
+// the receipt. Height, Timestamp and TxHash of items are not set.
+func ItemsOf(addr module.Address, tx module.Transaction, r module.Receipt) ([]*Item, error) {
+	var items []*Item
+	from := tx.From()
+	if r.Status() == module.StatusSuccess {
+		if to, value := valueOf(tx); value != nil && value.Sign() > 0 && from != nil && to != nil {
+			if from.Equal(addr) {
+				items = append(items, &Item{
+					Type:         TypeTransfer,
+					Counterparty: to,
+					Amount:       new(big.Int).Neg(value),
+				})
+			}
+			if to.Equal(addr) {
+				items = append(items, &Item{
+					Type:         TypeTransfer,
+					Counterparty: from,
+					Amount:       value,
+				})
+			}
+		}
+	}
+
+	fee := new(big.Int)
+	if it := r.FeePaymentIterator(); it.Has() {
+		for ; it.Has(); it.Next() {
+			p, err := it.Get()
+			if err != nil {
+				return nil, err
+			}
+			if p.Payer().Equal(addr) {
+				fee.Add(fee, p.Amount())
+			}
+		}
+	} else if from != nil && from.Equal(addr) {
+		fee.Set(r.StepUsed())
+	}
+	if fee.Sign() > 0 {
+		fee.Mul(fee, r.StepPrice())
+		items = append(items, &Item{
+			Type:   TypeFee,
+			Amount: fee.Neg(fee),
+		})
+	}
+
+	for it := r.EventLogIterator(); it.Has(); it.Next() {
+		el, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		indexed := el.Indexed()
+		if len(indexed) == 0 {
+			continue
+		}
+		switch string(indexed[0]) {
+		case txresult.EventLogICXTransfer:
+			src, dst := transferOf(el)
+			if src == nil {
+				continue
+			}
+			value := intconv.BigIntSetBytes(new(big.Int), indexed[3])
+			if src.Equal(addr) {
+				items = append(items, &Item{
+					Type:         TypeInternal,
+					Counterparty: dst,
+					Amount:       new(big.Int).Neg(value),
+				})
+			}
+			if dst.Equal(addr) {
+				items = append(items, &Item{
+					Type:         TypeInternal,
+					Counterparty: src,
+					Amount:       value,
+				})
+			}
+		case EventLogIScoreClaimedV2:
+			data := el.Data()
+			if claimer := claimerOf(el); claimer != nil && claimer.Equal(addr) && len(data) == 2 {
+				items = append(items, &Item{
+					Type:         TypeReward,
+					Counterparty: el.Address(),
+					Amount:       intconv.BigIntSetBytes(new(big.Int), data[1]),
+				})
+			}
+		}
+	}
+	return items, nil
+}
+
+// transferOf returns the sender and the receiver of the ICXTransfer event.
+// Only the event emitted by the sender is accepted.
+func transferOf(el module.EventLog) (module.Address, module.Address) {
+	indexed := el.Indexed()
+	if len(indexed) != 4 {
+		return nil, nil
+	}
+	src, err := common.NewAddress(indexed[1])
+	if err != nil || !src.Equal(el.Address()) {
+		return nil, nil
+	}
+	dst, err := common.NewAddress(indexed[2])
+	if err != nil {
+		return nil, nil
+	}
+	return src, dst
+}
+
+// claimerOf returns the address claiming rewards with the IScoreClaimedV2
+// event of the system contract.
+func claimerOf(el module.EventLog) module.Address {
+	indexed := el.Indexed()
+	if len(indexed) != 2 || !state.SystemAddress.Equal(el.Address()) {
+		return nil
+	}
+	addr, err := common.NewAddress(indexed[1])
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+// addressesOf returns addresses related to the transaction with the
+// receipt.
+func addressesOf(tx module.Transaction, r module.Receipt) ([]module.Address, error) {
+	var addrs []module.Address
+	if from := tx.From(); from != nil {
+		addrs = append(addrs, from)
+	}
+	if to := r.To(); to != nil {
+		addrs = append(addrs, to)
+	}
+	for it := r.FeePaymentIterator(); it.Has(); it.Next() {
+		p, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, p.Payer())
+	}
+	for it := r.EventLogIterator(); it.Has(); it.Next() {
+		el, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		indexed := el.Indexed()
+		if len(indexed) == 0 {
+			continue
+		}
+		switch string(indexed[0]) {
+		case txresult.EventLogICXTransfer:
+			if src, dst := transferOf(el); src != nil {
+				addrs = append(addrs, src, dst)
+			}
+		case EventLogIScoreClaimedV2:
+			if claimer := claimerOf(el); claimer != nil {
+				addrs = append(addrs, claimer)
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// flagsOf returns flags of the reference of the address to the transaction
+// with the receipt.
+func flagsOf(addr module.Address, tx module.Transaction, r module.Receipt) int {
+	flags := FlagKnown
+	if from := tx.From(); from != nil && from.Equal(addr) {
+		flags |= FlagFrom
+	}
+	if to := r.To(); to != nil && to.Equal(addr) {
+		flags |= FlagTo
+	}
+	return flags
+}
+
+// refsIn returns references of transactions in the block for each address,
+// and receipts of the block are in the result of the next block.
+func (idx *Index) refsIn(blk, next module.Block) (map[string][]*Ref, error) {
+	refs := make(map[string][]*Ref)
+	sm := idx.chain.ServiceManager()
+	for _, g := range []module.TransactionGroup{
+		module.TransactionGroupPatch, module.TransactionGroupNormal,
+	} {
+		txs := blk.NormalTransactions()
+		if g == module.TransactionGroupPatch {
+			txs = blk.PatchTransactions()
+		}
+		rl, err := sm.ReceiptListFromResult(next.Result(), g)
+		if err != nil {
+			return nil, err
+		}
+		txIndex := 0
+		for itr := rl.Iterator(); itr.Has(); _, txIndex = itr.Next(), txIndex+1 {
+			r, err := itr.Get()
+			if err != nil {
+				return nil, err
+			}
+			tx, err := txs.Get(txIndex)
+			if err != nil {
+				return nil, err
+			}
+			addrs, err := addressesOf(tx, r)
+			if err != nil {
+				return nil, err
+			}
+			for _, addr := range addrs {
+				key := string(addr.Bytes())
+				rs := refs[key]
+				if n := len(rs); n > 0 && rs[n-1].Group == g && rs[n-1].TxIndex == txIndex {
+					continue
+				}
+				refs[key] = append(rs, &Ref{
+					Height:  blk.Height(),
+					Group:   g,
+					TxIndex: txIndex,
+					Flags:   flagsOf(addr, tx, r),
+				})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// startHeight returns the height of the first block to be indexed.
+func (idx *Index) startHeight() (int64, error) {
+	height, err := idx.Cursor()
+	if err != nil {
+		return 0, err
+	}
+	if height == 0 {
+		// blocks before the pruned genesis are not available.
+		if gs := idx.chain.GenesisStorage(); gs != nil {
+			height = gs.Height()
+		}
+	}
+	return height, nil
+}
+
+// Reindex rebuilds the index from the height to the last block having
+// receipts, and calls f with the height of each block indexed. References
+// of the addresses in the blocks are replaced from the height, so that
+// references added by old versions are updated. It starts from the cursor
+// if the cursor is lower than the height. It must not be called while the
+// index is running.
+func (idx *Index) Reindex(from int64, f func(height int64) error) error {
+	bm := idx.chain.BlockManager()
+	if bm == nil || idx.chain.ServiceManager() == nil {
+		return errors.InvalidStateError.New("Stopped")
+	}
+	height, err := idx.startHeight()
+	if err != nil {
+		return err
+	}
+	if from < height {
+		height = from
+		if gs := idx.chain.GenesisStorage(); gs != nil && height < gs.Height() {
+			height = gs.Height()
+		}
+	}
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	idx.log.Infof("Reindex addresses from=%d to=%d", height, last.Height()-1)
+	base := height
+	truncated := make(map[string]bool)
+	for ; height < last.Height(); height++ {
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		next, err := bm.GetBlockByHeight(height + 1)
+		if err != nil {
+			return err
+		}
+		refs, err := idx.refsIn(blk, next)
+		if err != nil {
+			return err
+		}
+		if err := idx.add(height, refs, base, truncated); err != nil {
+			return err
+		}
+		if err := f(height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Index) waitBlock(height int64) (module.Block, bool) {
+	bch, err := idx.chain.BlockManager().WaitForBlock(height)
+	if err != nil {
+		idx.log.Warnf("Fail to wait block height=%d err=%+v", height, err)
+		return nil, false
+	}
+	select {
+	case blk, ok := <-bch:
+		return blk, ok
+	case <-idx.stop:
+		return nil, false
+	}
+}
+
+func (idx *Index) run() {
+	defer close(idx.done)
+
+	height, err := idx.startHeight()
+	if err != nil {
+		idx.log.Errorf("Fail to get cursor err=%+v", err)
+		return
+	}
+	bm := idx.chain.BlockManager()
+	idx.log.Infof("Address index started cursor=%d", height)
+	for {
+		next, ok := idx.waitBlock(height + 1)
+		if !ok {
+			return
+		}
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			idx.log.Errorf("Fail to get block height=%d err=%+v", height, err)
+			return
+		}
+		refs, err := idx.refsIn(blk, next)
+		if err != nil {
+			idx.log.Errorf("Fail to get transactions height=%d err=%+v", height, err)
+			return
+		}
+		if err := idx.Add(height, refs); err != nil {
+			idx.log.Errorf("Fail to add transactions height=%d err=%+v", height, err)
+			return
+		}
+		height += 1
+	}
+}
+
+func (idx *Index) Start() {
+	go idx.run()
+}
+
+func (idx *Index) Stop() {
+	close(idx.stop)
+	<-idx.done
+}
+
+func New(c module.Chain) *Index {
+	return &Index{
+		chain: c,
+		dbase: c.Database(),
+		log:   c.Logger().WithFields(log.Fields{log.FieldKeyModule: "AI"}),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
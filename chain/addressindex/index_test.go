@@ -0,0 +1,191 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressindex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type testTransaction struct {
+	module.Transaction
+	from  module.Address
+	to    module.Address
+	value *big.Int
+}
+
+func (tx *testTransaction) From() module.Address {
+	return tx.from
+}
+
+func (tx *testTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{
+		"from":  tx.from.String(),
+		"to":    tx.to.String(),
+		"value": intconv.FormatBigInt(tx.value),
+	}, nil
+}
+
+func TestIndex_AddSearch(t *testing.T) {
+	idx := &Index{dbase: db.NewMapDB()}
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+
+	ref1 := &Ref{Height: 1, Group: module.TransactionGroupNormal, TxIndex: 0}
+	ref2 := &Ref{Height: 3, Group: module.TransactionGroupNormal, TxIndex: 1}
+	assert.NoError(t, idx.Add(1, map[string][]*Ref{
+		string(addr1.Bytes()): {ref1},
+		string(addr2.Bytes()): {ref1},
+	}))
+	assert.NoError(t, idx.Add(2, nil))
+	assert.NoError(t, idx.Add(3, map[string][]*Ref{string(addr1.Bytes()): {ref2}}))
+	// added again after restart
+	assert.NoError(t, idx.Add(3, map[string][]*Ref{string(addr1.Bytes()): {ref2}}))
+
+	cursor, err := idx.Cursor()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, cursor)
+
+	size, err := idx.Size(addr1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, size)
+	size, err = idx.Size(addr2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, size)
+
+	refs, err := idx.Refs(addr1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Ref{ref2}, refs)
+
+	n, err := idx.Search(addr1, func(ref *Ref) (bool, error) {
+		return ref.Height >= 2, nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	n, err = idx.Search(addr1, func(ref *Ref) (bool, error) {
+		return ref.Height >= 4, nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+}
+
+func TestIndex_AddTruncated(t *testing.T) {
+	idx := &Index{dbase: db.NewMapDB()}
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	key := string(addr1.Bytes())
+
+	for h := int64(1); h <= 3; h++ {
+		assert.NoError(t, idx.Add(h, map[string][]*Ref{
+			key: {{Height: h, Group: module.TransactionGroupNormal}},
+		}))
+	}
+
+	// references from the base are replaced once
+	truncated := make(map[string]bool)
+	ref2 := &Ref{Height: 2, Group: module.TransactionGroupNormal, Flags: FlagKnown | FlagFrom}
+	ref3 := &Ref{Height: 3, Group: module.TransactionGroupNormal, Flags: FlagKnown | FlagTo}
+	assert.NoError(t, idx.add(2, map[string][]*Ref{key: {ref2}}, 2, truncated))
+	assert.NoError(t, idx.add(3, map[string][]*Ref{key: {ref3}}, 2, truncated))
+
+	size, err := idx.Size(addr1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, size)
+	refs, err := idx.Refs(addr1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, refs[0].Flags)
+	assert.Equal(t, []*Ref{ref2, ref3}, refs[1:])
+}
+
+func TestRef_Compatibility(t *testing.T) {
+	// references added before flags were introduced
+	old := struct {
+		Height  int64
+		Group   module.TransactionGroup
+		TxIndex int
+	}{3, module.TransactionGroupNormal, 2}
+	ref := new(Ref)
+	_, err := codec.BC.UnmarshalFromBytes(codec.BC.MustMarshalToBytes(&old), ref)
+	assert.NoError(t, err)
+	assert.Equal(t, &Ref{Height: 3, Group: module.TransactionGroupNormal, TxIndex: 2}, ref)
+}
+
+func TestItemsOf(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+
+	tx := &testTransaction{from: addr1, to: score, value: big.NewInt(100)}
+	r := txresult.NewReceipt(db.NewMapDB(), module.UseMPTOnEvents, score)
+	r.AddLog(score, [][]byte{
+		[]byte(txresult.EventLogICXTransfer),
+		score.Bytes(), addr2.Bytes(), intconv.BigIntToBytes(big.NewInt(30)),
+	}, nil)
+	// transfers by other contracts are ignored
+	r.AddLog(score, [][]byte{
+		[]byte(txresult.EventLogICXTransfer),
+		addr1.Bytes(), addr2.Bytes(), intconv.BigIntToBytes(big.NewInt(50)),
+	}, nil)
+	r.AddLog(state.SystemAddress, [][]byte{
+		[]byte(EventLogIScoreClaimedV2), addr2.Bytes(),
+	}, [][]byte{
+		intconv.BigIntToBytes(big.NewInt(1000)), intconv.BigIntToBytes(big.NewInt(1)),
+	})
+	r.SetResult(module.StatusSuccess, big.NewInt(10), big.NewInt(2), nil)
+
+	addrs, err := addressesOf(tx, r)
+	assert.NoError(t, err)
+	assert.Len(t, addrs, 5)
+	assert.Equal(t, FlagKnown|FlagFrom, flagsOf(addr1, tx, r))
+	assert.Equal(t, FlagKnown|FlagTo, flagsOf(score, tx, r))
+	assert.Equal(t, FlagKnown, flagsOf(addr2, tx, r))
+
+	items, err := ItemsOf(addr1, tx, r)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, TypeTransfer, items[0].Type)
+	assert.True(t, score.Equal(items[0].Counterparty))
+	assert.EqualValues(t, -100, items[0].Amount.Int64())
+	assert.Equal(t, TypeFee, items[1].Type)
+	assert.EqualValues(t, -20, items[1].Amount.Int64())
+
+	items, err = ItemsOf(addr2, tx, r)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, TypeInternal, items[0].Type)
+	assert.True(t, score.Equal(items[0].Counterparty))
+	assert.EqualValues(t, 30, items[0].Amount.Int64())
+	assert.Equal(t, TypeReward, items[1].Type)
+	assert.EqualValues(t, 1, items[1].Amount.Int64())
+
+	// failed transaction only pays the fee
+	r = txresult.NewReceipt(db.NewMapDB(), module.UseMPTOnEvents, score)
+	r.SetResult(module.StatusUnknownFailure, big.NewInt(10), big.NewInt(2), nil)
+	items, err = ItemsOf(addr1, tx, r)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, TypeFee, items[0].Type)
+}
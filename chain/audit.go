@@ -0,0 +1,57 @@
+package chain
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// AuditLogFile is the file in the chain directory recording the operations
+// changing the data of the chain, in JSON lines.
+const AuditLogFile = "audit.log"
+
+// Results of audited operations
+const (
+	AuditStarted = "started"
+	AuditDone    = "done"
+	AuditFailed  = "failed"
+)
+
+type auditRecord struct {
+	Time      time.Time   `json:"time"`
+	Operation string      `json:"operation"`
+	Params    interface{} `json:"params"`
+	Result    string      `json:"result"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// audit records the operation to the audit log. Failures are logged only,
+// so they don't break the operation.
+func (c *singleChain) audit(op string, params interface{}, result string, err error) {
+	c.logger.Warnf("AUDIT op=%s params=%+v result=%s err=%v", op, params, result, err)
+
+	bs, err2 := json.Marshal(&auditRecord{
+		Time:      time.Now(),
+		Operation: op,
+		Params:    params,
+		Result:    result,
+		Error:     errors.ToString(err),
+	})
+	if err2 != nil {
+		c.logger.Errorf("FAIL to marshal audit record err=%+v", err2)
+		return
+	}
+	p := path.Join(c.cfg.AbsBaseDir(), AuditLogFile)
+	fd, err2 := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err2 != nil {
+		c.logger.Errorf("FAIL to open audit log file=%s err=%+v", p, err2)
+		return
+	}
+	defer fd.Close()
+	if _, err2 := fd.Write(append(bs, '\n')); err2 != nil {
+		c.logger.Errorf("FAIL to write audit log file=%s err=%+v", p, err2)
+	}
+}
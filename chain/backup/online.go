@@ -0,0 +1,455 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	StateIdle    = "idle"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateFailed  = "failed"
+	StateStopped = "stopped"
+)
+
+// Phases of an online backup.
+const (
+	PhaseBarrier  = "barrier"
+	PhaseDatabase = "database"
+	PhaseFiles    = "files"
+	PhaseVerify   = "verify"
+)
+
+// ManifestFile is the name of the file describing the backup in the
+// target directory.
+const ManifestFile = "backup.json"
+
+// Barrier calls the function while WALs and the database are not updated.
+type Barrier interface {
+	Barrier(f func() error) error
+}
+
+// Source is the chain to take backups of. Names of directories and files
+// are relative to BaseDir, and they are copied to the same place in the
+// target directory.
+type Source struct {
+	NID      int
+	CID      int
+	Channel  string
+	DBType   string
+	DBName   string
+	DBDir    string
+	Database db.Database
+	Barrier  Barrier
+
+	// Height returns the height of the last block in the database.
+	Height func() int64
+
+	BaseDir string
+	// Synced is the list of names copied in the barrier with the
+	// database checkpoint, such as WAL directory.
+	Synced []string
+	// Files is the list of names copied after the barrier.
+	Files []string
+}
+
+// FileInfo is the size and the checksum (SHA3-256) of a copied file.
+type FileInfo struct {
+	Name     string          `json:"name"`
+	Size     int64           `json:"size"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+// Manifest describes the backup, and it's written to ManifestFile.
+type Manifest struct {
+	NID      common.HexInt32 `json:"nid"`
+	CID      common.HexInt32 `json:"cid"`
+	Channel  string          `json:"channel"`
+	Height   int64           `json:"height"`
+	Codec    string          `json:"codec"`
+	DBType   string          `json:"dbType"`
+	Database string          `json:"database"`
+	Entries  int64           `json:"entries"`
+	Digest   common.HexBytes `json:"digest"`
+	Files    []*FileInfo     `json:"files"`
+	Finished time.Time       `json:"finished"`
+}
+
+// Status is the status of the last online backup.
+type Status struct {
+	State   string `json:"state"`
+	Dir     string `json:"dir,omitempty"`
+	Phase   string `json:"phase,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Entries int64  `json:"entries,omitempty"`
+	Files   int    `json:"files,omitempty"`
+	Bytes   int64  `json:"bytes,omitempty"`
+	Elapsed string `json:"elapsed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Online takes point-in-time consistent backups of the running chain
+// in background.
+type Online struct {
+	lock   sync.Mutex
+	log    log.Logger
+	status Status
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func New(logger log.Logger) *Online {
+	return &Online{
+		log:    logger,
+		status: Status{State: StateIdle},
+	}
+}
+
+// Start starts a backup to the directory, which shouldn't exist or should
+// be empty.
+func (o *Online) Start(src *Source, dir string) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.status.State == StateRunning {
+		return errors.InvalidStateError.New("AlreadyRunning")
+	}
+	if fis, err := ioutil.ReadDir(dir); err == nil {
+		if len(fis) > 0 {
+			return errors.IllegalArgumentError.Errorf("NotEmptyDirectory(dir=%s)", dir)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	o.status = Status{
+		State: StateRunning,
+		Dir:   dir,
+	}
+	o.stop = make(chan struct{})
+	o.done = make(chan struct{})
+	go o.run(src, dir, o.stop, o.done)
+	return nil
+}
+
+// Stop stops the running backup and waits for it.
+func (o *Online) Stop() {
+	o.lock.Lock()
+	stop, done := o.stop, o.done
+	o.stop, o.done = nil, nil
+	o.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (o *Online) Status() *Status {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	s := o.status
+	return &s
+}
+
+func (o *Online) update(f func(s *Status)) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	f(&o.status)
+}
+
+func checkStop(stop <-chan struct{}) error {
+	select {
+	case <-stop:
+		return errors.ErrInterrupted
+	default:
+		return nil
+	}
+}
+
+func (o *Online) run(src *Source, dir string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	start := time.Now()
+	err := o.backup(src, dir, stop)
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.status.Elapsed = time.Since(start).String()
+	switch {
+	case err == nil:
+		o.status.State = StateDone
+	case errors.InterruptedError.Equals(err):
+		o.status.State = StateStopped
+	default:
+		o.log.Warnf("Fail to backup dir=%s err=%+v", dir, err)
+		o.status.State = StateFailed
+		o.status.Error = err.Error()
+	}
+}
+
+func (o *Online) backup(src *Source, dir string, stop <-chan struct{}) error {
+	m := &Manifest{
+		NID:      common.HexInt32{Value: int32(src.NID)},
+		CID:      common.HexInt32{Value: int32(src.CID)},
+		Channel:  src.Channel,
+		Codec:    codec.BC.Name(),
+		DBType:   src.DBType,
+		Database: path.Join(src.DBDir, src.DBName),
+	}
+	onFile := func(fi *FileInfo) error {
+		m.Files = append(m.Files, fi)
+		o.update(func(s *Status) {
+			s.Files += 1
+			s.Bytes += fi.Size
+		})
+		return checkStop(stop)
+	}
+
+	// copy WALs and capture the database at the same moment.
+	o.update(func(s *Status) { s.Phase = PhaseBarrier })
+	var cp db.Checkpoint
+	err := src.Barrier.Barrier(func() error {
+		for _, name := range src.Synced {
+			if err := copyAll(src.BaseDir, dir, name, onFile); err != nil {
+				return err
+			}
+		}
+		var err error
+		if cp, err = db.NewCheckpoint(src.Database); err != nil {
+			return err
+		}
+		m.Height = src.Height()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer cp.Release()
+	o.update(func(s *Status) {
+		s.Phase = PhaseDatabase
+		s.Height = m.Height
+	})
+
+	dbDir := path.Join(dir, src.DBDir)
+	if err := os.MkdirAll(dbDir, 0700); err != nil {
+		return err
+	}
+	res, err := cp.Export(dbDir, src.DBName, func(n int64) error {
+		o.update(func(s *Status) { s.Entries = n })
+		return checkStop(stop)
+	})
+	if err != nil {
+		return err
+	}
+	m.Entries, m.Digest = res.Entries, res.Digest
+
+	// files referred by the database are made before the checkpoint,
+	// so they are copied after the checkpoint.
+	o.update(func(s *Status) { s.Phase = PhaseFiles })
+	for _, name := range src.Files {
+		if err := copyAll(src.BaseDir, dir, name, onFile); err != nil {
+			return err
+		}
+	}
+
+	o.update(func(s *Status) { s.Phase = PhaseVerify })
+	if err := Verify(dir, m.Files, stop); err != nil {
+		return err
+	}
+
+	m.Finished = time.Now()
+	bs, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(dir, ManifestFile), bs, 0644)
+}
+
+// ReadManifest reads the manifest of the backup in the directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	bs, err := ioutil.ReadFile(path.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := new(Manifest)
+	if err := json.Unmarshal(bs, m); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidManifest(dir=%s)", dir)
+	}
+	return m, nil
+}
+
+// VerifyManifest checks the files and the database in the directory with
+// the manifest.
+func VerifyManifest(dir string, m *Manifest, stop <-chan struct{}) error {
+	if err := Verify(dir, m.Files, stop); err != nil {
+		return err
+	}
+	res, err := db.Digest(db.BackendType(m.DBType),
+		path.Join(dir, path.Dir(m.Database)), path.Base(m.Database))
+	if err != nil {
+		return err
+	}
+	if res.Entries != m.Entries || !bytes.Equal(res.Digest, m.Digest) {
+		return errors.CriticalFormatError.Errorf(
+			"DatabaseMismatch(entries=%d,digest=%#x,exp_entries=%d,exp_digest=%#x)",
+			res.Entries, res.Digest, m.Entries, m.Digest)
+	}
+	return nil
+}
+
+// Copy copies the backup in the directory to dst except the manifest. on
+// is called for each copied file, and copying stops if it returns an error.
+func Copy(dir, dst string, on func(fi *FileInfo) error) error {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if fi.Name() == ManifestFile {
+			continue
+		}
+		if err := copyAll(dir, dst, fi.Name(), on); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks the sizes and the checksums of the files in the
+// directory.
+func Verify(dir string, files []*FileInfo, stop <-chan struct{}) error {
+	for _, fi := range files {
+		if err := checkStop(stop); err != nil {
+			return err
+		}
+		size, sum, err := checksumOf(path.Join(dir, fi.Name))
+		if err != nil {
+			return err
+		}
+		if size != fi.Size || !bytes.Equal(sum, fi.Checksum) {
+			return errors.CriticalFormatError.Errorf(
+				"FileMismatch(name=%s,size=%d,checksum=%#x,exp_size=%d,exp_checksum=%#x)",
+				fi.Name, size, sum, fi.Size, fi.Checksum)
+		}
+	}
+	return nil
+}
+
+func checksumOf(p string) (int64, []byte, error) {
+	fd, err := os.Open(p)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer fd.Close()
+
+	h := sha3.New256()
+	size, err := io.Copy(h, fd)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, h.Sum(nil), nil
+}
+
+// copyAll copies the file or the directory with the name under src to the
+// same place under dst. Files removed while copying are ignored.
+func copyAll(src, dst, name string, on func(fi *FileInfo) error) error {
+	st, err := os.Stat(path.Join(src, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if st.Mode().IsRegular() {
+		fi, err := copyFile(path.Join(src, name), path.Join(dst, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fi.Name = name
+		return on(fi)
+	} else if !st.IsDir() {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Join(dst, name), 0700); err != nil {
+		return err
+	}
+	fis, err := ioutil.ReadDir(path.Join(src, name))
+	if err != nil {
+		return err
+	}
+	sort.Slice(fis, func(i, j int) bool {
+		return fis[i].Name() < fis[j].Name()
+	})
+	for _, fi := range fis {
+		if err := copyAll(src, dst, path.Join(name, fi.Name()), on); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) (*FileInfo, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	h := sha3.New256()
+	size, err := io.Copy(io.MultiWriter(out, h), in)
+	if err2 := out.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{
+		Size:     size,
+		Checksum: h.Sum(nil),
+	}, nil
+}
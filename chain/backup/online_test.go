@@ -0,0 +1,131 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+type testBarrier struct {
+	called int
+}
+
+func (b *testBarrier) Barrier(f func() error) error {
+	b.called += 1
+	return f()
+}
+
+func writeFile(t *testing.T, p string, data string) {
+	assert.NoError(t, os.MkdirAll(path.Dir(p), 0700))
+	assert.NoError(t, ioutil.WriteFile(p, []byte(data), 0644))
+}
+
+func waitDone(t *testing.T, o *Online) *Status {
+	for i := 0; i < 100; i++ {
+		if s := o.Status(); s.State != StateRunning {
+			return s
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("backup is not finished")
+	return nil
+}
+
+func TestOnline_Backup(t *testing.T) {
+	base := t.TempDir()
+	writeFile(t, path.Join(base, "wal", "commit", "0000.log"), "wal")
+	writeFile(t, path.Join(base, "contract", "0x01", "code"), "code")
+	writeFile(t, path.Join(base, "config.json"), "{}")
+
+	database, err := db.NewGoLevelDB("1", path.Join(base, "db"))
+	assert.NoError(t, err)
+	defer database.Close()
+	bk, _ := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, bk.Set([]byte("key"), []byte("value")))
+
+	barrier := new(testBarrier)
+	src := &Source{
+		NID:      1,
+		CID:      2,
+		Channel:  "test",
+		DBType:   string(db.GoLevelDBBackend),
+		DBName:   "1",
+		DBDir:    "db",
+		Database: database,
+		Barrier:  barrier,
+		Height:   func() int64 { return 10 },
+		BaseDir:  base,
+		Synced:   []string{"wal"},
+		Files:    []string{"contract", "config.json", "genesis.zip"},
+	}
+
+	o := New(log.New())
+	assert.Equal(t, StateIdle, o.Status().State)
+
+	dir := path.Join(t.TempDir(), "backup")
+	assert.NoError(t, o.Start(src, dir))
+	s := waitDone(t, o)
+	assert.Equal(t, StateDone, s.State, s.Error)
+	assert.Equal(t, 1, barrier.called)
+	assert.EqualValues(t, 10, s.Height)
+	assert.EqualValues(t, 1, s.Entries)
+	assert.Equal(t, 3, s.Files)
+
+	m, err := ReadManifest(dir)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, m.Height)
+	assert.EqualValues(t, 1, m.Entries)
+	assert.Equal(t, "db/1", m.Database)
+	assert.Len(t, m.Files, 3)
+	assert.NoError(t, VerifyManifest(dir, m, nil))
+
+	restored, err := db.NewGoLevelDB("1", path.Join(dir, "db"))
+	assert.NoError(t, err)
+	defer restored.Close()
+	rbk, _ := restored.GetBucket(db.MerkleTrie)
+	v, err := rbk.Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), v)
+
+	// copied backup is verified with the manifest.
+	copied := t.TempDir()
+	var copiedFiles int
+	assert.NoError(t, Copy(dir, copied, func(fi *FileInfo) error {
+		copiedFiles += 1
+		return nil
+	}))
+	assert.NoFileExists(t, path.Join(copied, ManifestFile))
+	assert.NoError(t, VerifyManifest(copied, m, nil))
+	assert.Less(t, len(m.Files), copiedFiles)
+
+	// modified files fail verification
+	writeFile(t, path.Join(dir, "config.json"), "[]")
+	assert.True(t, errors.CriticalFormatError.Equals(VerifyManifest(dir, m, nil)))
+
+	// target directory should be empty
+	assert.True(t, errors.IllegalArgumentError.Equals(o.Start(src, dir)))
+}
@@ -77,4 +77,5 @@ type Chain interface {
 	Regulator() module.Regulator
 	Wallet() module.Wallet
 	WalletFor(dsa string) module.BaseWallet
+	WALRetention() int
 }
@@ -11,8 +11,20 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain/addressindex"
+	"github.com/icon-project/goloop/chain/backup"
 	"github.com/icon-project/goloop/chain/base"
+	"github.com/icon-project/goloop/chain/endpoint"
+	"github.com/icon-project/goloop/chain/eventindex"
+	"github.com/icon-project/goloop/chain/exporter"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/gssync"
+	"github.com/icon-project/goloop/chain/light"
+	"github.com/icon-project/goloop/chain/profile"
+	"github.com/icon-project/goloop/chain/recovery"
+	"github.com/icon-project/goloop/chain/scoreindex"
+	"github.com/icon-project/goloop/chain/snapsync"
+	"github.com/icon-project/goloop/chain/watch"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -87,6 +99,9 @@ type singleChain struct {
 	srv      *server.Manager
 	nt       module.NetworkTransport
 	nm       module.NetworkManager
+	ls       *light.Server
+	gss      *gssync.Server
+	sss      *snapsync.Server
 	plt      base.Platform
 
 	cid int
@@ -96,6 +111,40 @@ type singleChain struct {
 	logger log.Logger
 
 	regulator *regulator
+	quota     *quota
+
+	expLock    sync.Mutex
+	expRunning bool
+	exporters  []*exporter.Exporter
+	webhooks   map[string]*exporter.Exporter
+
+	watchLock  sync.Mutex
+	watchLists map[string]*watch.AddressSet
+
+	epLock    sync.Mutex
+	epWatcher *endpoint.Watcher
+
+	profLock sync.Mutex
+	prof     *profile.Profiler
+
+	obLock sync.Mutex
+	ob     *backup.Online
+
+	siLock sync.Mutex
+	si     *scoreindex.Index
+
+	eiLock sync.Mutex
+	ei     *eventindex.Index
+
+	aiLock sync.Mutex
+	ai     *addressindex.Index
+
+	compLock sync.Mutex
+	paused   map[string]bool
+
+	haltLock sync.Mutex
+
+	re *recovery.Engine
 
 	state      State
 	lastErr    error
@@ -175,6 +224,17 @@ func (c *singleChain) Regulator() module.Regulator {
 	return c.regulator
 }
 
+func (c *singleChain) Quota() module.Quota {
+	return c.quota
+}
+
+func (c *singleChain) SetQuota(cpu, goroutines int, dbIO int64) {
+	c.cfg.QuotaCPU = cpu
+	c.cfg.QuotaGoroutines = goroutines
+	c.cfg.QuotaDBIO = dbIO
+	c.quota.SetQuota(cpu, goroutines, dbIO)
+}
+
 func (c *singleChain) MetricContext() context.Context {
 	return c.metricCtx
 }
@@ -201,6 +261,13 @@ func (c *singleChain) PatchTxPoolSize() int {
 	return ConfigDefaultPatchTxPoolSize
 }
 
+func (c *singleChain) PriorityTxPoolSize() int {
+	if c.cfg.PriorityTxPoolSize > 0 {
+		return c.cfg.PriorityTxPoolSize
+	}
+	return ConfigDefaultPriorityTxPoolSize
+}
+
 func (c *singleChain) MaxBlockTxBytes() int {
 	if c.cfg.MaxBlockTxBytes > 0 {
 		return c.cfg.MaxBlockTxBytes
@@ -246,10 +313,32 @@ func (c *singleChain) NephewsLimit() int {
 	return ConfigDefaultNephewLimit
 }
 
+func (c *singleChain) WALRetention() int {
+	if c.cfg.WALRetention != nil && *c.cfg.WALRetention >= 0 {
+		return *c.cfg.WALRetention
+	}
+	return ConfigDefaultWALRetention
+}
+
+func (c *singleChain) TxTimestampWindow() time.Duration {
+	if c.cfg.TxTimestampWindow > 0 {
+		return time.Duration(c.cfg.TxTimestampWindow) * time.Millisecond
+	}
+	return 0
+}
+
 func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
 
+func (c *singleChain) LocalTxFirst() bool {
+	return c.cfg.LocalTxFirst
+}
+
+func (c *singleChain) AccessIndex() bool {
+	return c.cfg.AccessIndex
+}
+
 func (c *singleChain) State() (string, int64, error) {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
@@ -371,15 +460,19 @@ func (c *singleChain) openDatabase(dbDir, dbType string) (db.Database, error) {
 			return nil, errors.Wrapf(err, "fail to make directory dir=%s", dbDir)
 		}
 	}
-	DBName := strconv.FormatInt(int64(c.cfg.NID), 16)
+	DBName := c.dbName()
 	if cdb, err := db.Open(dbDir, dbType, DBName); err != nil {
 		return nil, errors.Wrapf(err,
 			"fail to open database dir=%s type=%s name=%s", dbDir, c.cfg.DBType, DBName)
 	} else {
-		return cdb, nil
+		return &quotaDatabase{cdb, c.quota}, nil
 	}
 }
 
+func (c *singleChain) dbName() string {
+	return strconv.FormatInt(int64(c.cfg.NID), 16)
+}
+
 func (c *singleChain) ensureDatabase() {
 	c.dbLock.Lock()
 	defer c.dbLock.Unlock()
@@ -455,6 +548,14 @@ func (c *singleChain) _init() error {
 	}
 	c.pd = consensus.DecodePatch
 	c.metricCtx = metric.GetMetricContextByCID(c.CID())
+
+	if c.cfg.Recovery != nil {
+		re, err := recovery.New(c.cfg.Recovery, c, c.logger)
+		if err != nil {
+			return err
+		}
+		c.re = re
+	}
 	return nil
 }
 
@@ -482,7 +583,141 @@ func (c *singleChain) prepareManagers() error {
 	return nil
 }
 
+func (c *singleChain) startExporters() error {
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+
+	for i := range c.cfg.Exporters {
+		e, err := exporter.New(c, &c.cfg.Exporters[i])
+		if err != nil {
+			c._stopExporters()
+			return err
+		}
+		e.Start()
+		c.exporters = append(c.exporters, e)
+	}
+	webhooks, err := c.loadWebhooks()
+	if err != nil {
+		c._stopExporters()
+		return err
+	}
+	c.webhooks = make(map[string]*exporter.Exporter)
+	for _, cfg := range webhooks {
+		e, err := c.newWebhookExporter(cfg)
+		if err != nil {
+			c._stopExporters()
+			return err
+		}
+		e.Start()
+		c.webhooks[cfg.Name] = e
+	}
+	c.expRunning = true
+	return nil
+}
+
+func (c *singleChain) _stopExporters() {
+	for _, e := range c.exporters {
+		e.Stop()
+	}
+	c.exporters = nil
+	for _, e := range c.webhooks {
+		e.Stop()
+	}
+	c.webhooks = nil
+	c.expRunning = false
+}
+
+func (c *singleChain) stopExporters() {
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+
+	c._stopExporters()
+}
+
+// ExporterInspector reports the status of running exporters of the chain.
+type ExporterInspector interface {
+	InspectExporters() map[string]interface{}
+}
+
+func (c *singleChain) InspectExporters() map[string]interface{} {
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+
+	if !c.expRunning {
+		return nil
+	}
+	m := make(map[string]interface{})
+	for _, e := range c.exporters {
+		s := e.Status()
+		m[s.Name] = s
+	}
+	for _, e := range c.webhooks {
+		s := e.Status()
+		m[s.Name] = s
+	}
+	return m
+}
+
+func (c *singleChain) startLightServer() error {
+	if !c.cfg.LightServer {
+		return nil
+	}
+	ls, err := light.NewServer(c)
+	if err != nil {
+		return err
+	}
+	c.ls = ls
+	return nil
+}
+
+// startGenesisServer serves the genesis storage to the peers joining the
+// chain if the file of it is known.
+func (c *singleChain) startGenesisServer() error {
+	if c.cfg.GenesisFile == "" {
+		return nil
+	}
+	gss, err := gssync.NewServer(c, c.cfg.GenesisFile)
+	if err != nil {
+		return err
+	}
+	c.gss = gss
+	return nil
+}
+
+// startSnapshotServer serves the latest snapshot of the chain in the
+// snapshot directory to the peers bootstrapping the chain.
+func (c *singleChain) startSnapshotServer() error {
+	if !c.cfg.SnapshotServer || c.cfg.SnapshotDir == "" {
+		return nil
+	}
+	sss, err := snapsync.NewServer(c, c.cfg.SnapshotDir)
+	if err != nil {
+		return err
+	}
+	c.sss = sss
+	return nil
+}
+
 func (c *singleChain) releaseManagers() {
+	c.stopExporters()
+	c.stopEndpointWatcher()
+	c.StopProfile()
+	c.StopOnlineBackup()
+	c.stopSCOREIndex()
+	c.stopEventIndex()
+	c.stopAddressIndex()
+	if c.ls != nil {
+		c.ls.Term()
+		c.ls = nil
+	}
+	if c.gss != nil {
+		c.gss.Term()
+		c.gss = nil
+	}
+	if c.sss != nil {
+		c.sss.Term()
+		c.sss = nil
+	}
 	if c.cs != nil {
 		c.cs.Term()
 		c.cs = nil
@@ -657,10 +892,11 @@ func (c *singleChain) Verify() error {
 	return errors.UnsupportedError.New("UnsupportedFeatureVerify")
 }
 
+const chainGenesisZipFileName = "genesis.zip"
+
 func (c *singleChain) Reset(gs string, height int64, blockHash []byte) error {
 	if len(gs) == 0 {
 		chainDir := c.cfg.AbsBaseDir()
-		const chainGenesisZipFileName = "genesis.zip"
 		gs = path.Join(chainDir, chainGenesisZipFileName)
 	}
 	task := newTaskReset(c, gs, height, blockHash)
@@ -724,7 +960,9 @@ func NewChain(
 		pm:        pm,
 		logger:    chainLogger,
 		regulator: NewRegulator(chainLogger),
+		paused:    make(map[string]bool),
 		metricCtx: metric.GetMetricContextByCID(cid),
 	}
+	c.quota = newQuota(cfg, c.metricCtx)
 	return c
 }
@@ -0,0 +1,77 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+// maxRevisionPlatform is implemented by platforms knowing the max revision
+// supported by this build.
+type maxRevisionPlatform interface {
+	MaxRevision() int
+}
+
+type revisionValuer interface {
+	RevisionValue() int64
+}
+
+// revisionOf returns the revision of the chain at the last block and the
+// max revision supported by the platform. Zero is returned for unknown
+// values.
+func (c *singleChain) revisionOf() (revision int64, max int) {
+	if mp, ok := c.plt.(maxRevisionPlatform); ok {
+		max = mp.MaxRevision()
+	}
+	if c.bm == nil || c.sm == nil {
+		return
+	}
+	blk, err := c.bm.GetLastBlock()
+	if err != nil {
+		return
+	}
+	cc, err := c.sm.GetChainConfig(blk.Result())
+	if err != nil {
+		return
+	}
+	if rv, ok := cc.(revisionValuer); ok {
+		revision = rv.RevisionValue()
+	}
+	return
+}
+
+// checkRevision warns if the chain is running with the revision higher than
+// the max revision supported by this build, so that the operator may
+// upgrade the node before it fails in the middle of the consensus.
+func (c *singleChain) checkRevision() {
+	revision, max := c.revisionOf()
+	if max > 0 && revision > int64(max) {
+		c.logger.Warnf("chain requires revision not supported by this build revision=%d max=%d",
+			revision, max)
+	}
+}
+
+// CompatibilityInspector reports compatibility of the chain with this
+// build.
+type CompatibilityInspector interface {
+	// InspectCompatibility returns features supported by this build, and
+	// the revision of the chain with the max revision supported by the
+	// platform.
+	InspectCompatibility() map[string]interface{}
+}
+
+func (c *singleChain) InspectCompatibility() map[string]interface{} {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	m := make(map[string]interface{})
+	m["features"] = module.Features()
+	revision, max := c.revisionOf()
+	if max > 0 {
+		m["maxRevision"] = intconv.FormatInt(int64(max))
+	}
+	if revision > 0 {
+		m["revision"] = intconv.FormatInt(revision)
+		m["revisionSupported"] = max == 0 || revision <= int64(max)
+	}
+	return m
+}
@@ -0,0 +1,107 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	ComponentRunning = "running"
+	ComponentPaused  = "paused"
+)
+
+// ComponentManager pauses and resumes components of the chain without
+// stopping the chain. Paused components are resumed on restart of the
+// node.
+type ComponentManager interface {
+	Components() []*Component
+	PauseComponent(name string) error
+	ResumeComponent(name string) error
+}
+
+type Component struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// syncServer is implemented by the managers serving synchronization data
+// to the peers.
+type syncServer interface {
+	SetSyncServing(serving bool)
+}
+
+func (c *singleChain) IsPaused(name string) bool {
+	c.compLock.Lock()
+	defer c.compLock.Unlock()
+
+	return c.paused[name]
+}
+
+func (c *singleChain) Components() []*Component {
+	c.compLock.Lock()
+	defer c.compLock.Unlock()
+
+	comps := make([]*Component, 0, len(module.Components))
+	for _, name := range module.Components {
+		state := ComponentRunning
+		if c.paused[name] {
+			state = ComponentPaused
+		}
+		comps = append(comps, &Component{Name: name, State: state})
+	}
+	return comps
+}
+
+func (c *singleChain) PauseComponent(name string) error {
+	return c.setPaused(name, true)
+}
+
+func (c *singleChain) ResumeComponent(name string) error {
+	return c.setPaused(name, false)
+}
+
+func (c *singleChain) setPaused(name string, paused bool) error {
+	known := false
+	for _, comp := range module.Components {
+		if comp == name {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return errors.NotFoundError.Errorf("UnknownComponent(name=%s)", name)
+	}
+
+	c.compLock.Lock()
+	if c.paused[name] == paused {
+		c.compLock.Unlock()
+		return nil
+	}
+	if paused {
+		c.paused[name] = true
+	} else {
+		delete(c.paused, name)
+	}
+	c.compLock.Unlock()
+
+	if paused {
+		c.logger.Infof("PAUSE component=%s", name)
+	} else {
+		c.logger.Infof("RESUME component=%s", name)
+	}
+	if name == module.ComponentSync {
+		c.applySyncServing()
+	}
+	return nil
+}
+
+// applySyncServing applies the state of the sync component to the
+// running managers.
+func (c *singleChain) applySyncServing() {
+	serving := !c.IsPaused(module.ComponentSync)
+	for _, m := range []interface{}{c.cs, c.sm} {
+		if s, ok := m.(syncServer); ok {
+			s.SetSyncServing(serving)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+type testSyncConsensus struct {
+	module.Consensus
+	serving []bool
+}
+
+func (cs *testSyncConsensus) SetSyncServing(serving bool) {
+	cs.serving = append(cs.serving, serving)
+}
+
+type testSyncServiceManager struct {
+	module.ServiceManager
+	serving []bool
+}
+
+func (sm *testSyncServiceManager) SetSyncServing(serving bool) {
+	sm.serving = append(sm.serving, serving)
+}
+
+func TestSingleChain_PauseComponent(t *testing.T) {
+	cs := &testSyncConsensus{}
+	sm := &testSyncServiceManager{}
+	c := &singleChain{
+		cs:     cs,
+		sm:     sm,
+		logger: log.New(),
+		paused: make(map[string]bool),
+	}
+
+	assert.Equal(t, []*Component{
+		{Name: module.ComponentRPC, State: ComponentRunning},
+		{Name: module.ComponentTxPool, State: ComponentRunning},
+		{Name: module.ComponentSync, State: ComponentRunning},
+	}, c.Components())
+
+	err := c.PauseComponent("unknown")
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	assert.NoError(t, c.PauseComponent(module.ComponentRPC))
+	assert.True(t, module.IsComponentPaused(c, module.ComponentRPC))
+	assert.False(t, module.IsComponentPaused(c, module.ComponentTxPool))
+	assert.Equal(t, ComponentPaused, c.Components()[0].State)
+	assert.Empty(t, cs.serving)
+
+	// sync serving is applied to the managers only on change
+	assert.NoError(t, c.PauseComponent(module.ComponentSync))
+	assert.NoError(t, c.PauseComponent(module.ComponentSync))
+	assert.NoError(t, c.ResumeComponent(module.ComponentSync))
+	assert.Equal(t, []bool{false, true}, cs.serving)
+	assert.Equal(t, []bool{false, true}, sm.serving)
+
+	assert.NoError(t, c.ResumeComponent(module.ComponentRPC))
+	assert.False(t, module.IsComponentPaused(c, module.ComponentRPC))
+	assert.Empty(t, c.paused)
+}
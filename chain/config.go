@@ -8,18 +8,23 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/icon-project/goloop/chain/endpoint"
+	"github.com/icon-project/goloop/chain/exporter"
+	"github.com/icon-project/goloop/chain/recovery"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
 
 const (
-	ConfigDefaultNormalTxPoolSize = 5000
-	ConfigDefaultPatchTxPoolSize  = 1000
-	ConfigDefaultMaxBlockTxBytes  = 1024 * 1024
-	ConfigDefaultTxTimeout        = 5000 * time.Millisecond
-	ConfigDefaultChildrenLimit    = 10
-	ConfigDefaultNephewLimit      = 10
+	ConfigDefaultNormalTxPoolSize   = 5000
+	ConfigDefaultPatchTxPoolSize    = 1000
+	ConfigDefaultPriorityTxPoolSize = 100
+	ConfigDefaultMaxBlockTxBytes    = 1024 * 1024
+	ConfigDefaultTxTimeout          = 5000 * time.Millisecond
+	ConfigDefaultChildrenLimit      = 10
+	ConfigDefaultNephewLimit        = 10
+	ConfigDefaultWALRetention       = 2
 )
 
 const (
@@ -37,17 +42,47 @@ type Config struct {
 	Platform string `json:"platform,omitempty"`
 
 	// static
-	SeedAddr         string `json:"seed_addr"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrency_level,omitempty"`
-	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
-	PatchTxPoolSize  int    `json:"patch_tx_pool,omitempty"`
-	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
-	NodeCache        string `json:"node_cache,omitempty"`
-	AutoStart        bool   `json:"auto_start,omitempty"`
-	ChildrenLimit    *int   `json:"children_limit,omitempty"`
-	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
-	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	SeedAddr           string `json:"seed_addr"`
+	Role               uint   `json:"role"`
+	ConcurrencyLevel   int    `json:"concurrency_level,omitempty"`
+	NormalTxPoolSize   int    `json:"normal_tx_pool,omitempty"`
+	PatchTxPoolSize    int    `json:"patch_tx_pool,omitempty"`
+	PriorityTxPoolSize int    `json:"priority_tx_pool,omitempty"`
+	MaxBlockTxBytes    int    `json:"max_block_tx_bytes,omitempty"`
+	NodeCache          string `json:"node_cache,omitempty"`
+	AutoStart          bool   `json:"auto_start,omitempty"`
+	ChildrenLimit      *int   `json:"children_limit,omitempty"`
+	NephewsLimit       *int   `json:"nephews_limit,omitempty"`
+	WALRetention       *int   `json:"wal_retention,omitempty"`
+	ValidateTxOnSend   bool   `json:"validate_tx_on_send,omitempty"`
+	LightServer        bool   `json:"light_server,omitempty"`
+	SCOREIndex         bool   `json:"score_index,omitempty"`
+	EventIndex         bool   `json:"event_index,omitempty"`
+	AddressIndex       bool   `json:"address_index,omitempty"`
+	AccessIndex        bool   `json:"access_index,omitempty"`
+	SnapshotServer     bool   `json:"snapshot_server,omitempty"`
+	LocalTxFirst       bool   `json:"local_tx_first,omitempty"`
+
+	// TxTimestampWindow is the maximum difference of the timestamp of
+	// a new transaction from the current time in millisecond.
+	TxTimestampWindow int64 `json:"tx_timestamp_window,omitempty"`
+
+	// Soft quotas of resources for the chain. QuotaCPU is the percentage
+	// of a CPU core for execution, QuotaGoroutines is the number of
+	// concurrent executors and QuotaDBIO is bytes of database I/O per
+	// second. Zero means no limit.
+	QuotaCPU        int   `json:"quota_cpu,omitempty"`
+	QuotaGoroutines int   `json:"quota_goroutines,omitempty"`
+	QuotaDBIO       int64 `json:"quota_db_io,omitempty"`
+
+	// HaltHeight is the height of the last block for coordinated upgrades.
+	// The chain stops after the block is finalized. Zero means no halt.
+	HaltHeight int64 `json:"halt_height,omitempty"`
+
+	Exporters       []exporter.Config `json:"exporters,omitempty"`
+	WatchLists      map[string]string `json:"watch_lists,omitempty"`
+	EndpointWatcher *endpoint.Config  `json:"endpoint_watcher,omitempty"`
+	Recovery        *recovery.Config  `json:"recovery,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -58,11 +93,15 @@ type Config struct {
 	TxTimeout      int64  `json:"txTimeout"`
 
 	GenesisStorage module.GenesisStorage `json:"-"`
+	GenesisFile    string                `json:"-"` // absolute path
 	Genesis        json.RawMessage       `json:"genesis"`
 
 	BaseDir  string `json:"chain_dir"`
 	FilePath string `json:"-"` // absolute path
 
+	// SnapshotDir is the directory having snapshots taken by online backup.
+	SnapshotDir string `json:"-"` // absolute path
+
 	NIDForP2P bool `json:"-"`
 }
 
@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/endpoint"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// EndpointManager shows the status of the watcher of the P2P endpoint of
+// the P-Rep, and submits the prepared update on the confirmation.
+type EndpointManager interface {
+	EndpointStatus() (*endpoint.Status, error)
+	ConfirmEndpointUpdate() ([]byte, error)
+}
+
+func (c *singleChain) startEndpointWatcher() error {
+	if c.cfg.EndpointWatcher == nil {
+		return nil
+	}
+	c.epLock.Lock()
+	defer c.epLock.Unlock()
+
+	w, err := endpoint.New(c, c.cfg.EndpointWatcher, c.nt.Address())
+	if err != nil {
+		return err
+	}
+	w.Start()
+	c.epWatcher = w
+	return nil
+}
+
+func (c *singleChain) stopEndpointWatcher() {
+	c.epLock.Lock()
+	defer c.epLock.Unlock()
+
+	if c.epWatcher != nil {
+		c.epWatcher.Stop()
+		c.epWatcher = nil
+	}
+}
+
+func (c *singleChain) endpointWatcher() (*endpoint.Watcher, error) {
+	c.epLock.Lock()
+	defer c.epLock.Unlock()
+
+	if c.epWatcher == nil {
+		return nil, errors.InvalidStateError.New("EndpointWatcherNotRunning")
+	}
+	return c.epWatcher, nil
+}
+
+func (c *singleChain) EndpointStatus() (*endpoint.Status, error) {
+	w, err := c.endpointWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return w.Status(), nil
+}
+
+func (c *singleChain) ConfirmEndpointUpdate() ([]byte, error) {
+	w, err := c.endpointWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return w.Confirm()
+}
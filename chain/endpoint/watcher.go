@@ -0,0 +1,313 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package endpoint
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	DefaultInterval  = time.Minute
+	DefaultStepLimit = 1000000
+)
+
+// Config is the configuration of the watcher in the chain configuration.
+type Config struct {
+	// Owner is the address of the P-Rep. The wallet of the node is used
+	// if it's not set.
+	Owner *common.Address `json:"owner,omitempty"`
+	// Interval is the interval of checks in second.
+	Interval int64 `json:"interval,omitempty"`
+	// StepLimit is the step limit of the update transaction.
+	StepLimit int64 `json:"step_limit,omitempty"`
+}
+
+// Update is the prepared transaction to update the endpoint of the P-Rep.
+// The transaction has no timestamp and signature, which are filled on
+// submission.
+type Update struct {
+	Registered  string                 `json:"registered"`
+	Endpoint    string                 `json:"endpoint"`
+	Height      common.HexInt64        `json:"height"`
+	Transaction map[string]interface{} `json:"transaction"`
+}
+
+// Status is the status of the watcher.
+type Status struct {
+	Owner      *common.Address `json:"owner"`
+	Endpoint   string          `json:"endpoint"`
+	Registered string          `json:"registered,omitempty"`
+	Signable   bool            `json:"signable"`
+	Update     *Update         `json:"update,omitempty"`
+	Submitted  common.HexBytes `json:"submitted,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// Watcher watches the P2P endpoint of the P-Rep registered in the chain,
+// and prepares the transaction to update it if it's different from the
+// endpoint advertised by the node. The transaction is submitted only on
+// the confirmation of the operator.
+type Watcher struct {
+	chain     module.Chain
+	owner     *common.Address
+	endpoint  string
+	interval  time.Duration
+	stepLimit int64
+	log       log.Logger
+
+	lock       sync.Mutex
+	registered string
+	update     *Update
+	submitted  []byte
+	lastErr    error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (w *Watcher) signable() bool {
+	return w.owner.Equal(w.chain.Wallet().Address())
+}
+
+// prepareUpdate returns the transaction to set the endpoint of the P-Rep
+// with setPRep of the system SCORE.
+func prepareUpdate(owner module.Address, endpoint string, nid int, stepLimit int64) map[string]interface{} {
+	return map[string]interface{}{
+		"version":   "0x3",
+		"from":      owner.String(),
+		"to":        state.SystemAddress.String(),
+		"nid":       fmt.Sprintf("%#x", nid),
+		"stepLimit": fmt.Sprintf("%#x", stepLimit),
+		"dataType":  "call",
+		"data": map[string]interface{}{
+			"method": "setPRep",
+			"params": map[string]interface{}{
+				"p2pEndpoint": endpoint,
+			},
+		},
+	}
+}
+
+// onRegistered updates the status with the endpoint registered at the
+// height, and returns whether an update is prepared newly.
+func (w *Watcher) onRegistered(registered string, height int64) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.registered = registered
+	w.lastErr = nil
+	if registered == w.endpoint {
+		w.update = nil
+		return false
+	}
+	if w.update != nil && w.update.Registered == registered {
+		return false
+	}
+	w.update = &Update{
+		Registered: registered,
+		Endpoint:   w.endpoint,
+		Height:     common.HexInt64{Value: height},
+		Transaction: prepareUpdate(w.owner, w.endpoint,
+			w.chain.NID(), w.stepLimit),
+	}
+	return true
+}
+
+func (w *Watcher) onError(err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.lastErr = err
+}
+
+func (w *Watcher) check() {
+	blk, err := w.chain.BlockManager().GetLastBlock()
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	js, err := json.Marshal(map[string]interface{}{
+		"to":       state.SystemAddress,
+		"dataType": "call",
+		"data": map[string]interface{}{
+			"method": "getPRep",
+			"params": map[string]interface{}{
+				"address": w.owner,
+			},
+		},
+	})
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	ret, err := w.chain.ServiceManager().Call(blk.Result(), blk.NextValidators(), js, bi)
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	prep, ok := ret.(map[string]interface{})
+	if !ok {
+		w.onError(errors.UnsupportedError.Errorf("InvalidPRep(%T)", ret))
+		return
+	}
+	registered, _ := prep["p2pEndpoint"].(string)
+	if w.onRegistered(registered, blk.Height()) {
+		w.log.Warnf("P2P endpoint of P-Rep differs registered=%s advertised=%s; "+
+			"confirm the update with the admin API", registered, w.endpoint)
+	}
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.check()
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) Start() {
+	go w.run()
+}
+
+func (w *Watcher) Stop() {
+	close(w.stop)
+	<-w.done
+}
+
+func (w *Watcher) Status() *Status {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	s := &Status{
+		Owner:      w.owner,
+		Endpoint:   w.endpoint,
+		Registered: w.registered,
+		Signable:   w.signable(),
+		Update:     w.update,
+		Submitted:  w.submitted,
+	}
+	if w.lastErr != nil {
+		s.Error = w.lastErr.Error()
+	}
+	return s
+}
+
+// signUpdate fills the timestamp and the signature of the transaction.
+func signUpdate(tx map[string]interface{}, wallet module.Wallet, ts int64) ([]byte, error) {
+	param := make(map[string]interface{}, len(tx)+2)
+	for k, v := range tx {
+		param[k] = v
+	}
+	param["timestamp"] = fmt.Sprintf("%#x", ts)
+	bs, err := transaction.SerializeMap(param, nil, map[string]bool{"signature": true})
+	if err != nil {
+		return nil, err
+	}
+	bs = append([]byte("icx_sendTransaction."), bs...)
+	sig, err := wallet.Sign(crypto.SHA3Sum256(bs))
+	if err != nil {
+		return nil, err
+	}
+	param["signature"] = base64.StdEncoding.EncodeToString(sig)
+	return json.Marshal(param)
+}
+
+// Confirm signs the prepared transaction with the wallet of the node, and
+// submits it. It returns the hash of the transaction.
+func (w *Watcher) Confirm() ([]byte, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.update == nil {
+		return nil, errors.NotFoundError.New("NoUpdate")
+	}
+	if !w.signable() {
+		return nil, errors.InvalidStateError.Errorf(
+			"NotSignable(owner=%s)", w.owner)
+	}
+	blk, err := w.chain.BlockManager().GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	ts := time.Now().UnixNano() / int64(time.Microsecond)
+	tx, err := signUpdate(w.update.Transaction, w.chain.Wallet(), ts)
+	if err != nil {
+		return nil, err
+	}
+	id, err := w.chain.ServiceManager().SendTransaction(blk.Result(), blk.Height(), tx)
+	if err != nil {
+		return nil, err
+	}
+	w.log.Infof("Submit P2P endpoint update tx=%#x endpoint=%s", id, w.endpoint)
+	w.submitted = id
+	w.update = nil
+	return id, nil
+}
+
+// New returns the watcher for the endpoint advertised by the node.
+func New(c module.Chain, cfg *Config, endpoint string) (*Watcher, error) {
+	if endpoint == "" {
+		return nil, errors.IllegalArgumentError.New("NoEndpoint")
+	}
+	owner := cfg.Owner
+	if owner == nil {
+		owner = common.AddressToPtr(c.Wallet().Address())
+	}
+	if owner.IsContract() {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidOwner(%s)", owner)
+	}
+	interval := DefaultInterval
+	if cfg.Interval > 0 {
+		interval = time.Duration(cfg.Interval) * time.Second
+	}
+	stepLimit := int64(DefaultStepLimit)
+	if cfg.StepLimit > 0 {
+		stepLimit = cfg.StepLimit
+	}
+	return &Watcher{
+		chain:     c,
+		owner:     owner,
+		endpoint:  endpoint,
+		interval:  interval,
+		stepLimit: stepLimit,
+		log: c.Logger().WithFields(log.Fields{
+			log.FieldKeyModule: "EP",
+		}),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
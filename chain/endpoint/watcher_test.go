@@ -0,0 +1,40 @@
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+func TestSignUpdate(t *testing.T) {
+	w := wallet.New()
+	tx := prepareUpdate(w.Address(), "127.0.0.1:8080", 3, 1000)
+
+	bs, err := signUpdate(tx, w, 1234)
+	assert.NoError(t, err)
+	_, ok := tx["signature"]
+	assert.False(t, ok, "prepared transaction shouldn't be modified")
+
+	stx, err := transaction.NewTransactionFromJSON(bs)
+	assert.NoError(t, err)
+	assert.NoError(t, stx.Verify())
+	assert.True(t, stx.From().Equal(w.Address()))
+	assert.True(t, stx.To().Equal(state.SystemAddress))
+	assert.Equal(t, int64(1234), stx.Timestamp())
+}
+
+func TestSignUpdate_OtherWallet(t *testing.T) {
+	owner := common.MustNewAddressFromString("hx1234567890123456789012345678901234567890")
+	tx := prepareUpdate(owner, "127.0.0.1:8080", 3, 1000)
+
+	bs, err := signUpdate(tx, wallet.New(), 1234)
+	assert.NoError(t, err)
+	stx, err := transaction.NewTransactionFromJSON(bs)
+	assert.NoError(t, err)
+	assert.Error(t, stx.Verify())
+}
@@ -0,0 +1,39 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/eventindex"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func (c *singleChain) startEventIndex() {
+	if !c.cfg.EventIndex {
+		return
+	}
+	c.eiLock.Lock()
+	defer c.eiLock.Unlock()
+
+	c.ei = eventindex.New(c)
+	c.ei.Start()
+}
+
+func (c *singleChain) stopEventIndex() {
+	c.eiLock.Lock()
+	defer c.eiLock.Unlock()
+
+	if c.ei != nil {
+		c.ei.Stop()
+		c.ei = nil
+	}
+}
+
+// EventIndex returns the index of events if it's enabled with
+// event_index of the chain configuration.
+func (c *singleChain) EventIndex() (*eventindex.Index, error) {
+	c.eiLock.Lock()
+	defer c.eiLock.Unlock()
+
+	if c.ei == nil {
+		return nil, errors.UnsupportedError.New("EventIndexDisabled")
+	}
+	return c.ei, nil
+}
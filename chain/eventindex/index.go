@@ -0,0 +1,413 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventindex
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	keyCursor = "event_index.cursor"
+	keyBlock  = "event_index.block."
+)
+
+// Provider is implemented by chains with the index of events.
+type Provider interface {
+	EventIndex() (*Index, error)
+}
+
+// Event is the reference to the event log in the index.
+type Event struct {
+	Group     module.TransactionGroup
+	TxIndex   int
+	LogIndex  int
+	Address   *common.Address
+	Signature []byte
+}
+
+// entry has events of transactions in a block. Bloom is the merged bloom of
+// receipts of them in compressed form.
+type entry struct {
+	Bloom  []byte
+	Events []*Event
+}
+
+// Log is the event log matched with the filter.
+type Log struct {
+	Height    int64
+	BlockHash []byte
+	Group     module.TransactionGroup
+	TxIndex   int
+	TxHash    []byte
+	LogIndex  int
+	EventLog  module.EventLog
+}
+
+// Filter selects event logs. Empty Addresses matches any contract. Indexed
+// has values of indexed parameters following the signature, and nil value
+// matches any. Indexed requires Signature.
+type Filter struct {
+	Addresses []module.Address
+	Signature string
+	Indexed   [][]byte
+
+	lb *txresult.LogsBloom
+}
+
+func (f *Filter) compile() error {
+	if f.Signature == "" && len(f.Indexed) > 0 {
+		return errors.IllegalArgumentError.New("IndexedWithoutSignature")
+	}
+	lb := txresult.NewLogsBloom(nil)
+	if len(f.Addresses) == 1 {
+		lb.AddAddressOfLog(f.Addresses[0])
+	}
+	if f.Signature != "" {
+		lb.AddIndexedOfLog(0, []byte(f.Signature))
+	}
+	for i, v := range f.Indexed {
+		if v != nil {
+			lb.AddIndexedOfLog(i+1, v)
+		}
+	}
+	f.lb = lb
+	return nil
+}
+
+func (f *Filter) matchEvent(e *Event) bool {
+	if f.Signature != "" && string(e.Signature) != f.Signature {
+		return false
+	}
+	if len(f.Addresses) == 0 {
+		return true
+	}
+	for _, addr := range f.Addresses {
+		if addr.Equal(e.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchLog(el module.EventLog) bool {
+	indexed := el.Indexed()
+	if len(f.Indexed) > 0 && len(indexed) <= len(f.Indexed) {
+		return false
+	}
+	for i, v := range f.Indexed {
+		if v != nil && !bytes.Equal(v, indexed[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Index has events of blocks by height. It follows finalized blocks and
+// records references to event logs in receipts, so that logs can be found
+// without replaying blocks or scanning all receipts.
+type Index struct {
+	chain module.Chain
+	dbase db.Database
+	log   log.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (idx *Index) bucket() (db.Bucket, error) {
+	return idx.dbase.GetBucket(db.ChainProperty)
+}
+
+func blockKey(height int64) []byte {
+	key := make([]byte, len(keyBlock)+8)
+	copy(key, keyBlock)
+	binary.BigEndian.PutUint64(key[len(keyBlock):], uint64(height))
+	return key
+}
+
+// Cursor returns the height of the next block to be indexed. Events in the
+// blocks lower than the cursor are in the index.
+func (idx *Index) Cursor() (int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(keyCursor))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var v int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func (idx *Index) get(bk db.Bucket, height int64) (*entry, error) {
+	bs, err := bk.Get(blockKey(height))
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	e := new(entry)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Add adds events of the block at the height, and moves the cursor to the
+// next block. It's safe to add the events of the block again.
+func (idx *Index) Add(height int64, bloom module.LogsBloom, events []*Event) error {
+	bk, err := idx.bucket()
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		e := &entry{Bloom: bloom.CompressedBytes(), Events: events}
+		if err := bk.Set(blockKey(height), codec.BC.MustMarshalToBytes(e)); err != nil {
+			return err
+		}
+	}
+	return bk.Set([]byte(keyCursor), codec.BC.MustMarshalToBytes(height+1))
+}
+
+// Query returns event logs matched with the filter in the blocks from the
+// height to the height. It stops after the block making the number of logs
+// reach the limit, and it returns the height of the next block to query.
+// So the number of logs may exceed the limit to have all logs of the block.
+func (idx *Index) Query(from, to int64, f *Filter, limit int) ([]*Log, int64, error) {
+	if err := f.compile(); err != nil {
+		return nil, 0, err
+	}
+	bk, err := idx.bucket()
+	if err != nil {
+		return nil, 0, err
+	}
+	logs := []*Log{}
+	height := from
+	for ; height <= to && len(logs) < limit; height++ {
+		e, err := idx.get(bk, height)
+		if err != nil {
+			return nil, 0, err
+		}
+		if e == nil || !txresult.NewLogsBloomFromCompressed(e.Bloom).Contain(f.lb) {
+			continue
+		}
+		var events []*Event
+		for _, ev := range e.Events {
+			if f.matchEvent(ev) {
+				events = append(events, ev)
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+		bl, err := idx.logsOf(height, events, f)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, bl...)
+	}
+	return logs, height, nil
+}
+
+// logsOf returns event logs of the events in the block at the height, which
+// are matched with indexed parameters of the filter.
+func (idx *Index) logsOf(height int64, events []*Event, f *Filter) ([]*Log, error) {
+	bm := idx.chain.BlockManager()
+	sm := idx.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, errors.InvalidStateError.New("Stopped")
+	}
+	blk, err := bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	next, err := bm.GetBlockByHeight(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	rls := make(map[module.TransactionGroup]module.ReceiptList)
+	var logs []*Log
+	for _, ev := range events {
+		rl, ok := rls[ev.Group]
+		if !ok {
+			if rl, err = sm.ReceiptListFromResult(next.Result(), ev.Group); err != nil {
+				return nil, err
+			}
+			rls[ev.Group] = rl
+		}
+		r, err := rl.Get(ev.TxIndex)
+		if err != nil {
+			return nil, err
+		}
+		el, err := eventLogOf(r, ev.LogIndex)
+		if err != nil {
+			return nil, err
+		}
+		if !f.matchLog(el) {
+			continue
+		}
+		txs := blk.NormalTransactions()
+		if ev.Group == module.TransactionGroupPatch {
+			txs = blk.PatchTransactions()
+		}
+		tx, err := txs.Get(ev.TxIndex)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, &Log{
+			Height:    height,
+			BlockHash: blk.ID(),
+			Group:     ev.Group,
+			TxIndex:   ev.TxIndex,
+			TxHash:    tx.ID(),
+			LogIndex:  ev.LogIndex,
+			EventLog:  el,
+		})
+	}
+	return logs, nil
+}
+
+func eventLogOf(r module.Receipt, n int) (module.EventLog, error) {
+	for it, i := r.EventLogIterator(), 0; it.Has(); _, i = it.Next(), i+1 {
+		if i == n {
+			return it.Get()
+		}
+	}
+	return nil, errors.NotFoundError.Errorf("NoEventLog(index=%d)", n)
+}
+
+// eventsIn returns events of transactions in the previous block of next,
+// whose receipts are in the result of next, with the merged bloom of them.
+func (idx *Index) eventsIn(next module.Block) (module.LogsBloom, []*Event, error) {
+	lb := txresult.NewLogsBloom(nil)
+	var events []*Event
+	sm := idx.chain.ServiceManager()
+	for _, g := range []module.TransactionGroup{
+		module.TransactionGroupPatch, module.TransactionGroupNormal,
+	} {
+		rl, err := sm.ReceiptListFromResult(next.Result(), g)
+		if err != nil {
+			return nil, nil, err
+		}
+		txIndex := 0
+		for itr := rl.Iterator(); itr.Has(); _, txIndex = itr.Next(), txIndex+1 {
+			r, err := itr.Get()
+			if err != nil {
+				return nil, nil, err
+			}
+			logIndex := 0
+			for it := r.EventLogIterator(); it.Has(); _, logIndex = it.Next(), logIndex+1 {
+				el, err := it.Get()
+				if err != nil {
+					return nil, nil, err
+				}
+				var sig []byte
+				if indexed := el.Indexed(); len(indexed) > 0 {
+					sig = indexed[0]
+				}
+				events = append(events, &Event{
+					Group:     g,
+					TxIndex:   txIndex,
+					LogIndex:  logIndex,
+					Address:   common.AddressToPtr(el.Address()),
+					Signature: sig,
+				})
+			}
+			if logIndex > 0 {
+				lb.Merge(r.LogsBloom())
+			}
+		}
+	}
+	return lb, events, nil
+}
+
+func (idx *Index) waitBlock(height int64) (module.Block, bool) {
+	bch, err := idx.chain.BlockManager().WaitForBlock(height)
+	if err != nil {
+		idx.log.Warnf("Fail to wait block height=%d err=%+v", height, err)
+		return nil, false
+	}
+	select {
+	case blk, ok := <-bch:
+		return blk, ok
+	case <-idx.stop:
+		return nil, false
+	}
+}
+
+func (idx *Index) run() {
+	defer close(idx.done)
+
+	height, err := idx.Cursor()
+	if err != nil {
+		idx.log.Errorf("Fail to get cursor err=%+v", err)
+		return
+	}
+	if height == 0 {
+		// blocks before the pruned genesis are not available.
+		if gs := idx.chain.GenesisStorage(); gs != nil {
+			height = gs.Height()
+		}
+	}
+	idx.log.Infof("Event index started cursor=%d", height)
+	for {
+		next, ok := idx.waitBlock(height + 1)
+		if !ok {
+			return
+		}
+		lb, events, err := idx.eventsIn(next)
+		if err != nil {
+			idx.log.Errorf("Fail to get events height=%d err=%+v", height, err)
+			return
+		}
+		if err := idx.Add(height, lb, events); err != nil {
+			idx.log.Errorf("Fail to add events height=%d err=%+v", height, err)
+			return
+		}
+		height += 1
+	}
+}
+
+func (idx *Index) Start() {
+	go idx.run()
+}
+
+func (idx *Index) Stop() {
+	close(idx.stop)
+	<-idx.done
+}
+
+func New(c module.Chain) *Index {
+	return &Index{
+		chain: c,
+		dbase: c.Database(),
+		log:   c.Logger().WithFields(log.Fields{log.FieldKeyModule: "EI"}),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+}
+
+func (l *testEventLog) Address() module.Address {
+	return l.addr
+}
+
+func (l *testEventLog) Indexed() [][]byte {
+	return l.indexed
+}
+
+func (l *testEventLog) Data() [][]byte {
+	return nil
+}
+
+func TestFilter(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	sig := "Transfer(Address,Address,int)"
+
+	f := &Filter{Indexed: [][]byte{nil}}
+	assert.Error(t, f.compile())
+
+	f = &Filter{Addresses: []module.Address{addr1}, Signature: sig, Indexed: [][]byte{nil, []byte("to")}}
+	assert.NoError(t, f.compile())
+	assert.True(t, f.matchEvent(&Event{Address: addr1, Signature: []byte(sig)}))
+	assert.False(t, f.matchEvent(&Event{Address: addr2, Signature: []byte(sig)}))
+	assert.False(t, f.matchEvent(&Event{Address: addr1, Signature: []byte("Other()")}))
+
+	el := &testEventLog{addr1, [][]byte{[]byte(sig), []byte("from"), []byte("to")}}
+	assert.True(t, f.matchLog(el))
+	el.indexed[2] = []byte("other")
+	assert.False(t, f.matchLog(el))
+	assert.False(t, f.matchLog(&testEventLog{addr1, [][]byte{[]byte(sig), []byte("from")}}))
+
+	f = &Filter{Addresses: []module.Address{addr1, addr2}}
+	assert.NoError(t, f.compile())
+	assert.True(t, f.matchEvent(&Event{Address: addr2, Signature: []byte(sig)}))
+}
+
+func TestIndex_AddQuery(t *testing.T) {
+	idx := &Index{dbase: db.NewMapDB()}
+	addr := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	sig := "Transfer(Address,Address,int)"
+
+	cursor, err := idx.Cursor()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, cursor)
+
+	lb := txresult.NewLogsBloom(nil)
+	lb.AddLog(addr, [][]byte{[]byte(sig), []byte("from")})
+	events := []*Event{{
+		Group:     module.TransactionGroupNormal,
+		Address:   addr,
+		Signature: []byte(sig),
+	}}
+	assert.NoError(t, idx.Add(0, txresult.NewLogsBloom(nil), nil))
+	assert.NoError(t, idx.Add(1, lb, events))
+	// added again after restart
+	assert.NoError(t, idx.Add(1, lb, events))
+	assert.NoError(t, idx.Add(2, txresult.NewLogsBloom(nil), nil))
+
+	cursor, err = idx.Cursor()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, cursor)
+
+	bk, err := idx.bucket()
+	assert.NoError(t, err)
+	e, err := idx.get(bk, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+	e, err = idx.get(bk, 1)
+	assert.NoError(t, err)
+	assert.Len(t, e.Events, 1)
+	assert.True(t, addr.Equal(e.Events[0].Address))
+	assert.True(t, txresult.NewLogsBloomFromCompressed(e.Bloom).Equal(lb))
+
+	// blocks are skipped by the bloom and the events without receipts
+	logs, next, err := idx.Query(0, 2, &Filter{Signature: "Approval(Address,int)"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 0)
+	assert.EqualValues(t, 3, next)
+
+	logs, next, err = idx.Query(0, 2, &Filter{Signature: sig, Indexed: [][]byte{[]byte("to")}}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 0)
+	assert.EqualValues(t, 3, next)
+
+	_, _, err = idx.Query(0, 2, &Filter{Indexed: [][]byte{[]byte("to")}}, 10)
+	assert.Error(t, err)
+}
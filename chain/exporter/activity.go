@@ -0,0 +1,174 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/chain/watch"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	TypeActivity = "activity"
+)
+
+// ActivityConfig is the configuration of the activity sink. Addresses to
+// watch are the addresses and ones in the watch list of the chain.
+type ActivityConfig struct {
+	WebhookConfig
+	Addresses []*common.Address `json:"addresses,omitempty"`
+	WatchList string            `json:"watchList,omitempty"`
+}
+
+type ActivityTx struct {
+	TxIndex     common.HexInt32  `json:"txIndex"`
+	TxHash      common.HexBytes  `json:"txHash"`
+	Addresses   []module.Address `json:"addresses"`
+	Transaction interface{}      `json:"transaction"`
+	Receipt     interface{}      `json:"receipt"`
+}
+
+// Activity is the data posted to the webhook. It includes only
+// transactions related to the addresses and their events.
+type Activity struct {
+	Height       common.HexInt64 `json:"height"`
+	Hash         common.HexBytes `json:"hash"`
+	Transactions []*ActivityTx   `json:"transactions"`
+	Events       []*Event        `json:"events"`
+}
+
+// activitySink posts transactions related to the addresses.
+type activitySink struct {
+	*webhookSink
+	addresses *watch.AddressSet
+	watchList *watch.AddressSet
+}
+
+func (s *activitySink) related(tx module.Transaction, r module.Receipt) ([]module.Address, error) {
+	var related []module.Address
+	for _, as := range []*watch.AddressSet{s.addresses, s.watchList} {
+		if as == nil {
+			continue
+		}
+		addrs, err := as.Related(tx, r)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if !containsAddress(related, addr) {
+				related = append(related, addr)
+			}
+		}
+	}
+	return related, nil
+}
+
+func containsAddress(addrs []module.Address, addr module.Address) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *activitySink) Export(d *BlockData) error {
+	act := &Activity{
+		Height:       d.Height,
+		Hash:         d.Hash,
+		Transactions: []*ActivityTx{},
+		Events:       []*Event{},
+	}
+	matched := make(map[int32]bool)
+	for i, tx := range d.Transactions {
+		r, err := d.ReceiptList.Get(i)
+		if err != nil {
+			return err
+		}
+		addrs, err := s.related(tx, r)
+		if err != nil {
+			return err
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		tjs, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return err
+		}
+		act.Transactions = append(act.Transactions, &ActivityTx{
+			TxIndex:     common.HexInt32{Value: int32(i)},
+			TxHash:      tx.ID(),
+			Addresses:   addrs,
+			Transaction: tjs,
+			Receipt:     d.Receipts[i],
+		})
+		matched[int32(i)] = true
+	}
+	if len(act.Transactions) == 0 {
+		return nil
+	}
+	for _, ev := range d.Events {
+		if matched[ev.TxIndex.Value] {
+			act.Events = append(act.Events, ev)
+		}
+	}
+	return s.post(act)
+}
+
+func newActivitySink(c module.Chain, cfg json.RawMessage, l log.Logger) (Sink, error) {
+	var ac ActivityConfig
+	if err := json.Unmarshal(cfg, &ac); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidActivityConfig")
+	}
+	if len(ac.Addresses) == 0 && ac.WatchList == "" {
+		return nil, errors.IllegalArgumentError.New("NoAddresses")
+	}
+	ws, err := newWebhook(&ac.WebhookConfig)
+	if err != nil {
+		return nil, err
+	}
+	s := &activitySink{webhookSink: ws}
+	if len(ac.Addresses) > 0 {
+		addrs := make([]module.Address, 0, len(ac.Addresses))
+		for _, a := range ac.Addresses {
+			if a == nil {
+				return nil, errors.IllegalArgumentError.New("InvalidAddress")
+			}
+			addrs = append(addrs, a)
+		}
+		s.addresses = watch.NewAddressSet(addrs)
+	}
+	if ac.WatchList != "" {
+		wp, ok := c.(watch.Provider)
+		if !ok {
+			return nil, errors.UnsupportedError.New("WatchListNotSupported")
+		}
+		if s.watchList, err = wp.WatchList(ac.WatchList); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func init() {
+	Register(TypeActivity, newActivitySink)
+}
@@ -0,0 +1,167 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+type testTransaction struct {
+	module.Transaction
+	id   []byte
+	from module.Address
+}
+
+func (tx *testTransaction) ID() []byte {
+	return tx.id
+}
+
+func (tx *testTransaction) From() module.Address {
+	return tx.from
+}
+
+func (tx *testTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{"from": tx.from}, nil
+}
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+}
+
+func (e *testEventLog) Address() module.Address {
+	return e.addr
+}
+
+func (e *testEventLog) Indexed() [][]byte {
+	return e.indexed
+}
+
+func (e *testEventLog) Data() [][]byte {
+	return nil
+}
+
+type testEventLogIterator struct {
+	logs []module.EventLog
+}
+
+func (i *testEventLogIterator) Has() bool {
+	return len(i.logs) > 0
+}
+
+func (i *testEventLogIterator) Next() error {
+	i.logs = i.logs[1:]
+	return nil
+}
+
+func (i *testEventLogIterator) Get() (module.EventLog, error) {
+	return i.logs[0], nil
+}
+
+type testReceipt struct {
+	module.Receipt
+	to   module.Address
+	logs []module.EventLog
+}
+
+func (r *testReceipt) To() module.Address {
+	return r.to
+}
+
+func (r *testReceipt) EventLogIterator() module.EventLogIterator {
+	return &testEventLogIterator{logs: r.logs}
+}
+
+type testReceiptList struct {
+	module.ReceiptList
+	receipts []module.Receipt
+}
+
+func (l *testReceiptList) Get(i int) (module.Receipt, error) {
+	return l.receipts[i], nil
+}
+
+func TestActivitySink_Export(t *testing.T) {
+	watched := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	other := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	var received []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(HeaderSignature)
+	}))
+	defer srv.Close()
+
+	cfg, _ := json.Marshal(&ActivityConfig{
+		WebhookConfig: WebhookConfig{URL: srv.URL, Secret: "secret"},
+		Addresses:     []*common.Address{watched},
+	})
+	sink, err := newActivitySink(nil, cfg, log.New())
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	d := &BlockData{
+		Height:   common.HexInt64{Value: 10},
+		Hash:     []byte{0x01},
+		Receipts: []interface{}{"r0", "r1", "r2"},
+		Events: []*Event{
+			{TxIndex: common.HexInt32{Value: 2}, Address: score, Name: "Transfer"},
+		},
+		Transactions: []module.Transaction{
+			&testTransaction{id: []byte{0x10}, from: watched},
+			&testTransaction{id: []byte{0x11}, from: other},
+			&testTransaction{id: []byte{0x12}, from: other},
+		},
+		ReceiptList: &testReceiptList{receipts: []module.Receipt{
+			&testReceipt{to: other},
+			&testReceipt{to: other},
+			&testReceipt{to: score, logs: []module.EventLog{
+				&testEventLog{addr: score, indexed: [][]byte{
+					[]byte("Transfer(Address,Address,int)"),
+					other.Bytes(),
+					watched.Bytes(),
+				}},
+			}},
+		}},
+	}
+	assert.NoError(t, sink.Export(d))
+	assert.Equal(t, SignWebhookBody([]byte("secret"), received), signature)
+
+	var act struct {
+		Transactions []struct {
+			TxIndex   string   `json:"txIndex"`
+			Receipt   string   `json:"receipt"`
+			Addresses []string `json:"addresses"`
+		} `json:"transactions"`
+		Events []interface{} `json:"events"`
+	}
+	assert.NoError(t, json.Unmarshal(received, &act))
+	assert.Len(t, act.Transactions, 2)
+	assert.Equal(t, "0x0", act.Transactions[0].TxIndex)
+	assert.Equal(t, "r0", act.Transactions[0].Receipt)
+	assert.Equal(t, "0x2", act.Transactions[1].TxIndex)
+	assert.Equal(t, []string{watched.String()}, act.Transactions[1].Addresses)
+	assert.Len(t, act.Events, 1)
+
+	// no post for blocks without related transactions
+	received = nil
+	d.Transactions = d.Transactions[1:2]
+	d.ReceiptList = &testReceiptList{receipts: []module.Receipt{&testReceipt{to: other}}}
+	assert.NoError(t, sink.Export(d))
+	assert.Nil(t, received)
+}
+
+func TestActivitySink_InvalidConfig(t *testing.T) {
+	_, err := newActivitySink(nil, []byte(`{"url":"http://localhost"}`), log.New())
+	assert.Error(t, err)
+}
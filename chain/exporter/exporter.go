@@ -0,0 +1,451 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+const (
+	keyCursorPrefix = "exporter."
+	retryDelayMin   = time.Second
+	retryDelayMax   = time.Minute
+)
+
+// States of exporters
+const (
+	StateRunning  = "running"
+	StateRetrying = "retrying"
+	StateStopped  = "stopped"
+)
+
+// Config is the configuration of an exporter in the chain configuration.
+type Config struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Start  int64           `json:"start,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Event is an event log with decoded parameters if the API of the SCORE
+// has the event.
+type Event struct {
+	TxIndex common.HexInt32        `json:"txIndex"`
+	Address module.Address         `json:"scoreAddress"`
+	Name    string                 `json:"name"`
+	Params  map[string]interface{} `json:"params"`
+}
+
+// BlockData is the finalized data delivered to the sink.
+type BlockData struct {
+	Height   common.HexInt64 `json:"height"`
+	Hash     common.HexBytes `json:"hash"`
+	Block    interface{}     `json:"block"`
+	Receipts []interface{}   `json:"receipts"`
+	Events   []*Event        `json:"events"`
+
+	// Transactions and ReceiptList are for sinks filtering data.
+	Transactions []module.Transaction `json:"-"`
+	ReceiptList  module.ReceiptList   `json:"-"`
+}
+
+// Sink ships exported data to the external system. Export may be called
+// again with the same data if the node restarts before the cursor is
+// stored, so it should handle duplicates.
+type Sink interface {
+	Export(d *BlockData) error
+	Close() error
+}
+
+type Factory func(c module.Chain, cfg json.RawMessage, l log.Logger) (Sink, error)
+
+var factories = struct {
+	lock sync.Mutex
+	m    map[string]Factory
+}{
+	m: make(map[string]Factory),
+}
+
+// Register registers a factory for sinks of the type.
+func Register(typ string, f Factory) {
+	factories.lock.Lock()
+	defer factories.lock.Unlock()
+
+	if _, ok := factories.m[typ]; ok {
+		log.Panicf("DuplicateExporterType(type=%s)", typ)
+	}
+	factories.m[typ] = f
+}
+
+func factoryOf(typ string) (Factory, bool) {
+	factories.lock.Lock()
+	defer factories.lock.Unlock()
+
+	f, ok := factories.m[typ]
+	return f, ok
+}
+
+// Status is the state of the exporter. Failures is the number of
+// consecutive failures, and it's reset on success.
+type Status struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	State     string `json:"state"`
+	Cursor    int64  `json:"cursor"`
+	Failures  int    `json:"failures"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+type Exporter struct {
+	chain module.Chain
+	name  string
+	start int64
+	sink  Sink
+	log   log.Logger
+
+	retryMin time.Duration
+	retryMax time.Duration
+
+	lock   sync.Mutex
+	status Status
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (e *Exporter) cursorKey() []byte {
+	return []byte(keyCursorPrefix + e.name)
+}
+
+// Cursor returns the height of the next block to be exported.
+func (e *Exporter) Cursor() (int64, error) {
+	bk, err := e.chain.Database().GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get(e.cursorKey())
+	if err != nil {
+		return 0, err
+	}
+	if bs == nil {
+		if e.start > 0 {
+			return e.start, nil
+		}
+		blk, err := e.chain.BlockManager().GetLastBlock()
+		if err != nil {
+			return 0, err
+		}
+		return blk.Height(), nil
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// ClearCursor removes the stored cursor of the exporter. It's used when
+// the exporter is removed, so that the exporter registered later with the
+// same name doesn't continue from the cursor.
+func ClearCursor(dbase db.Database, name string) error {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Delete([]byte(keyCursorPrefix + name))
+}
+
+func (e *Exporter) setCursor(height int64) error {
+	bk, err := e.chain.Database().GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set(e.cursorKey(), codec.BC.MustMarshalToBytes(height))
+}
+
+func (e *Exporter) decodeEvents(result []byte, rl module.ReceiptList) ([]*Event, error) {
+	sm := e.chain.ServiceManager()
+	apis := make(map[string]*scoreapi.Info)
+	events := []*Event{}
+	idx := int32(0)
+	for itr := rl.Iterator(); itr.Has(); itr.Next() {
+		r, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		for eitr := r.EventLogIterator(); eitr.Has(); eitr.Next() {
+			ev, err := eitr.Get()
+			if err != nil {
+				return nil, err
+			}
+			key := string(ev.Address().Bytes())
+			info, ok := apis[key]
+			if !ok {
+				if api, err := sm.GetAPIInfo(result, ev.Address()); err == nil {
+					info, _ = api.(*scoreapi.Info)
+				}
+				apis[key] = info
+			}
+			if info == nil {
+				continue
+			}
+			if m, params, err := info.DecodeEvent(ev.Indexed(), ev.Data()); err == nil {
+				events = append(events, &Event{
+					TxIndex: common.HexInt32{Value: idx},
+					Address: ev.Address(),
+					Name:    m.Name,
+					Params:  params,
+				})
+			}
+		}
+		idx++
+	}
+	return events, nil
+}
+
+// dataOf returns the data of blk with receipts in the result of next.
+func (e *Exporter) dataOf(blk, next module.Block) (*BlockData, error) {
+	bjs, err := blk.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	rl, err := e.chain.ServiceManager().ReceiptListFromResult(
+		next.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	receipts := []interface{}{}
+	for itr := rl.Iterator(); itr.Has(); itr.Next() {
+		r, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		rjs, err := r.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rjs)
+	}
+	events, err := e.decodeEvents(next.Result(), rl)
+	if err != nil {
+		return nil, err
+	}
+	var txs []module.Transaction
+	for itr := blk.NormalTransactions().Iterator(); itr.Has(); itr.Next() {
+		tx, _, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return &BlockData{
+		Height:       common.HexInt64{Value: blk.Height()},
+		Hash:         blk.ID(),
+		Block:        bjs,
+		Receipts:     receipts,
+		Events:       events,
+		Transactions: txs,
+		ReceiptList:  rl,
+	}, nil
+}
+
+// waitBlock returns the block at the height. It returns ErrInterrupted
+// if the exporter stops.
+func (e *Exporter) waitBlock(height int64) (module.Block, error) {
+	bch, err := e.chain.BlockManager().WaitForBlock(height)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case blk, ok := <-bch:
+		if !ok {
+			return nil, errors.InvalidStateError.Errorf("NoBlock(height=%d)", height)
+		}
+		return blk, nil
+	case <-e.stop:
+		return nil, errors.ErrInterrupted
+	}
+}
+
+// export delivers the block at the height to the sink.
+func (e *Exporter) export(height int64) error {
+	// receipts of the block are in the result of the next block.
+	next, err := e.waitBlock(height + 1)
+	if err != nil {
+		return err
+	}
+	blk, err := e.chain.BlockManager().GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	d, err := e.dataOf(blk, next)
+	if err != nil {
+		return err
+	}
+	return e.sink.Export(d)
+}
+
+func (e *Exporter) onResult(err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if err == nil {
+		e.status.State = StateRunning
+		e.status.Failures = 0
+	} else {
+		e.status.State = StateRetrying
+		e.status.Failures += 1
+		e.status.LastError = err.Error()
+	}
+}
+
+func (e *Exporter) setStatus(state string, cursor int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.status.State = state
+	if cursor >= 0 {
+		e.status.Cursor = cursor
+	}
+}
+
+// Status returns the current status of the exporter.
+func (e *Exporter) Status() *Status {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	s := e.status
+	return &s
+}
+
+// retry calls f for the operation until it succeeds with exponential
+// backoff. It returns false if the exporter stops.
+func (e *Exporter) retry(op string, f func() error) bool {
+	delay := e.retryMin
+	for {
+		err := f()
+		if err == nil {
+			e.onResult(nil)
+			return true
+		}
+		if errors.Is(err, errors.ErrInterrupted) {
+			return false
+		}
+		e.onResult(err)
+		e.log.Warnf("Fail to %s err=%+v retry after %s", op, err, delay)
+		select {
+		case <-e.stop:
+			return false
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > e.retryMax {
+			delay = e.retryMax
+		}
+	}
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	defer e.setStatus(StateStopped, -1)
+
+	var height int64
+	if !e.retry("get cursor", func() (err error) {
+		height, err = e.Cursor()
+		return err
+	}) {
+		return
+	}
+	e.setStatus(StateRunning, height)
+	e.log.Infof("Exporter started cursor=%d", height)
+	for {
+		select {
+		case <-e.stop:
+			return
+		default:
+		}
+		if !e.retry(fmt.Sprintf("export height=%d", height), func() error {
+			return e.export(height)
+		}) {
+			return
+		}
+		if !e.retry(fmt.Sprintf("set cursor height=%d", height+1), func() error {
+			return e.setCursor(height + 1)
+		}) {
+			return
+		}
+		height += 1
+		e.setStatus(StateRunning, height)
+	}
+}
+
+func (e *Exporter) Start() {
+	go e.run()
+}
+
+func (e *Exporter) Stop() {
+	close(e.stop)
+	<-e.done
+	if err := e.sink.Close(); err != nil {
+		e.log.Warnf("Fail to close sink err=%+v", err)
+	}
+}
+
+func New(c module.Chain, cfg *Config) (*Exporter, error) {
+	if cfg.Name == "" {
+		return nil, errors.IllegalArgumentError.New("NoExporterName")
+	}
+	f, ok := factoryOf(cfg.Type)
+	if !ok {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"UnknownExporterType(type=%s)", cfg.Type)
+	}
+	logger := c.Logger().WithFields(log.Fields{
+		log.FieldKeyModule: "EXP",
+		"exporter":         cfg.Name,
+	})
+	sink, err := f(c, cfg.Config, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		chain:    c,
+		name:     cfg.Name,
+		start:    cfg.Start,
+		sink:     sink,
+		log:      logger,
+		retryMin: retryDelayMin,
+		retryMax: retryDelayMax,
+		status: Status{
+			Name:  cfg.Name,
+			Type:  cfg.Type,
+			State: StateStopped,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func newTestExporter() *Exporter {
+	return &Exporter{
+		name:     "test",
+		log:      log.New(),
+		retryMin: time.Millisecond,
+		retryMax: 2 * time.Millisecond,
+		status: Status{
+			Name:  "test",
+			Type:  TypeWebhook,
+			State: StateStopped,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+func TestExporter_Retry(t *testing.T) {
+	e := newTestExporter()
+
+	calls := 0
+	assert.True(t, e.retry("test", func() error {
+		calls += 1
+		if calls < 3 {
+			s := e.Status()
+			assert.Equal(t, calls-1, s.Failures)
+			return errors.New("SinkFailure")
+		}
+		s := e.Status()
+		assert.Equal(t, StateRetrying, s.State)
+		assert.Equal(t, 2, s.Failures)
+		return nil
+	}))
+	assert.Equal(t, 3, calls)
+
+	s := e.Status()
+	assert.Equal(t, StateRunning, s.State)
+	assert.Equal(t, 0, s.Failures)
+	assert.Equal(t, "SinkFailure", s.LastError)
+}
+
+func TestExporter_RetryStop(t *testing.T) {
+	e := newTestExporter()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(e.stop)
+	}()
+	assert.False(t, e.retry("test", func() error {
+		return errors.New("DBFailure")
+	}))
+	s := e.Status()
+	assert.Equal(t, StateRetrying, s.State)
+	assert.True(t, s.Failures > 0)
+
+	assert.False(t, e.retry("test", func() error {
+		return errors.ErrInterrupted
+	}))
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	TypeWebhook           = "webhook"
+	DefaultWebhookTimeout = 10 * time.Second
+
+	// HeaderSignature is the header for HMAC-SHA256 of the body signed
+	// with the secret of the webhook.
+	HeaderSignature = "X-Goloop-Signature"
+)
+
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Timeout int64             `json:"timeout,omitempty"` // in millisecond
+	Secret  string            `json:"secret,omitempty"`
+}
+
+// webhookSink posts exported data as JSON to the URL. Data is delivered
+// only when the server responds with 2xx status.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	secret  []byte
+	client  *http.Client
+}
+
+// SignWebhookBody returns the value of HeaderSignature for the body.
+func SignWebhookBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookSink) Export(d *BlockData) error {
+	return s.post(d)
+}
+
+func (s *webhookSink) post(v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	if len(s.secret) > 0 {
+		req.Header.Set(HeaderSignature, SignWebhookBody(s.secret, bs))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("InvalidResponse(status=%s)", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func newWebhook(wc *WebhookConfig) (*webhookSink, error) {
+	if wc.URL == "" {
+		return nil, errors.IllegalArgumentError.New("NoWebhookURL")
+	}
+	timeout := DefaultWebhookTimeout
+	if wc.Timeout > 0 {
+		timeout = time.Duration(wc.Timeout) * time.Millisecond
+	}
+	return &webhookSink{
+		url:     wc.URL,
+		headers: wc.Headers,
+		secret:  []byte(wc.Secret),
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func newWebhookSink(c module.Chain, cfg json.RawMessage, l log.Logger) (Sink, error) {
+	var wc WebhookConfig
+	if err := json.Unmarshal(cfg, &wc); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidWebhookConfig")
+	}
+	return newWebhook(&wc)
+}
+
+func init() {
+	Register(TypeWebhook, newWebhookSink)
+}
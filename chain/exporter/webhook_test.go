@@ -0,0 +1,57 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func TestWebhookSink_Export(t *testing.T) {
+	var received []byte
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("X-Token"))
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	f, ok := factoryOf(TypeWebhook)
+	assert.True(t, ok)
+
+	cfg, _ := json.Marshal(&WebhookConfig{
+		URL:     srv.URL,
+		Headers: map[string]string{"X-Token": "token"},
+	})
+	sink, err := f(nil, cfg, log.New())
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	d := &BlockData{
+		Height:   common.HexInt64{Value: 10},
+		Hash:     []byte{0x01, 0x02},
+		Receipts: []interface{}{},
+		Events:   []*Event{},
+	}
+	assert.NoError(t, sink.Export(d))
+
+	var jso map[string]interface{}
+	assert.NoError(t, json.Unmarshal(received, &jso))
+	assert.Equal(t, "0xa", jso["height"])
+	assert.Equal(t, "0x0102", jso["hash"])
+
+	status = http.StatusInternalServerError
+	assert.Error(t, sink.Export(d))
+}
+
+func TestWebhookSink_InvalidConfig(t *testing.T) {
+	_, err := newWebhookSink(nil, []byte(`{}`), log.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package gssync
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	MaxGenesisSize = 1 << 30
+
+	configRequestTimeout = 10 * time.Second
+	configPeerWait       = time.Second
+	configLimitedWait    = 100 * time.Millisecond
+)
+
+type peerResponse struct {
+	id   module.PeerID
+	resp *ChunkResponse
+}
+
+// Fetcher fetches the genesis storage by chunks from the peers serving it.
+type Fetcher struct {
+	nm  module.NetworkManager
+	ph  module.ProtocolHandler
+	log log.Logger
+
+	mtx     sync.Mutex
+	lastID  uint32
+	pending map[uint32]chan<- *peerResponse
+}
+
+// NewFetcher returns the fetcher using the network manager, which is for
+// the network of the chain to be joined.
+func NewFetcher(nm module.NetworkManager, l log.Logger) (*Fetcher, error) {
+	f := &Fetcher{
+		nm:      nm,
+		log:     l.WithFields(log.Fields{log.FieldKeyModule: "gssync"}),
+		pending: make(map[uint32]chan<- *peerResponse),
+	}
+	ph, err := nm.RegisterReactorForStreams("genesis", module.ProtoGenesis, f, protocols, configGenesisPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		return nil, err
+	}
+	f.ph = ph
+	return f, nil
+}
+
+func (f *Fetcher) Term() {
+	if err := f.nm.UnregisterReactor(f); err != nil {
+		f.log.Warnf("fail to unregister reactor err=%+v", err)
+	}
+}
+
+// Fetch returns the genesis storage fetched from the peers in turn. Peers
+// failing to serve a chunk aren't used again. It fails if the genesis
+// storage isn't fetched until the timeout.
+func (f *Fetcher) Fetch(timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	failed := make(map[string]bool)
+	next := 0
+	size := int64(-1)
+	var data []byte
+	for size < 0 || int64(len(data)) < size {
+		if time.Now().After(deadline) {
+			return nil, errors.TimeoutError.Errorf(
+				"FetchTimeout(received=%d,size=%d)", len(data), size)
+		}
+		peer := f.pickPeer(failed, &next)
+		if peer == nil {
+			time.Sleep(configPeerWait)
+			continue
+		}
+		resp, err := f.request(peer, int64(len(data)))
+		if err == nil {
+			err = checkResponse(resp, size, int64(len(data)))
+		}
+		if err != nil {
+			if resp != nil && resp.Status == StatusLimited {
+				time.Sleep(configLimitedWait)
+				continue
+			}
+			f.log.Infof("fail to fetch chunk peer=%v offset=%d err=%+v", peer, len(data), err)
+			failed[peer.String()] = true
+			continue
+		}
+		if size < 0 {
+			size = resp.Size
+			data = make([]byte, 0, size)
+		}
+		data = append(data, resp.Data...)
+	}
+	return data, nil
+}
+
+func checkResponse(resp *ChunkResponse, size, offset int64) error {
+	if resp.Status != StatusOK {
+		return errors.InvalidStateError.Errorf("InvalidStatus(status=%d)", resp.Status)
+	}
+	if resp.Size <= 0 || resp.Size > MaxGenesisSize || (size >= 0 && resp.Size != size) {
+		return errors.InvalidStateError.Errorf("InvalidSize(size=%d)", resp.Size)
+	}
+	if len(resp.Data) == 0 || offset+int64(len(resp.Data)) > resp.Size {
+		return errors.InvalidStateError.Errorf("InvalidData(len=%d)", len(resp.Data))
+	}
+	return nil
+}
+
+func (f *Fetcher) pickPeer(failed map[string]bool, next *int) module.PeerID {
+	var peers []module.PeerID
+	for _, id := range f.ph.GetPeers() {
+		if !failed[id.String()] {
+			peers = append(peers, id)
+		}
+	}
+	if len(peers) == 0 {
+		return nil
+	}
+	*next = (*next + 1) % len(peers)
+	return peers[*next]
+}
+
+func (f *Fetcher) request(peer module.PeerID, offset int64) (*ChunkResponse, error) {
+	ch := make(chan *peerResponse, 1)
+
+	f.mtx.Lock()
+	f.lastID += 1
+	reqID := f.lastID
+	f.pending[reqID] = ch
+	f.mtx.Unlock()
+
+	defer func() {
+		f.mtx.Lock()
+		delete(f.pending, reqID)
+		f.mtx.Unlock()
+	}()
+
+	req := codec.MustMarshalToBytes(&ChunkRequest{
+		RequestID: reqID,
+		Offset:    offset,
+	})
+	if err := f.ph.Unicast(ProtoChunkRequest, req, peer); err != nil {
+		return nil, err
+	}
+	after := time.After(configRequestTimeout)
+	for {
+		select {
+		case pr := <-ch:
+			if pr.id.Equal(peer) {
+				return pr.resp, nil
+			}
+		case <-after:
+			return nil, errors.TimeoutError.New("RequestTimeout")
+		}
+	}
+}
+
+func (f *Fetcher) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	if pi != ProtoChunkResponse {
+		return false, nil
+	}
+	var msg ChunkResponse
+	if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+		return false, err
+	}
+	f.mtx.Lock()
+	ch, ok := f.pending[msg.RequestID]
+	f.mtx.Unlock()
+
+	if ok {
+		select {
+		case ch <- &peerResponse{id, &msg}:
+		default:
+		}
+	}
+	return false, nil
+}
+
+func (f *Fetcher) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
+	f.log.Debugf("OnFailure pi=%v err=%+v", pi, err)
+}
+
+func (f *Fetcher) OnJoin(id module.PeerID) {
+}
+
+func (f *Fetcher) OnLeave(id module.PeerID) {
+}
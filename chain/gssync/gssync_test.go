@@ -0,0 +1,77 @@
+package gssync
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+type testChain struct {
+	module.Chain
+	nm  module.NetworkManager
+	log log.Logger
+}
+
+func (c *testChain) NetworkManager() module.NetworkManager {
+	return c.nm
+}
+
+func (c *testChain) Logger() log.Logger {
+	return c.log
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	data := bytes.Repeat([]byte("genesis"), ChunkSize/3)
+	file := path.Join(t.TempDir(), "genesis.zip")
+	assert.NoError(t, os.WriteFile(file, data, 0644))
+
+	snm := test.NewNetworkManager(t, wallet.New().Address())
+	s, err := NewServer(&testChain{nm: snm, log: log.New()}, file)
+	assert.NoError(t, err)
+	defer s.Term()
+
+	fnm := test.NewNetworkManager(t, wallet.New().Address())
+	f, err := NewFetcher(fnm, log.New())
+	assert.NoError(t, err)
+	defer f.Term()
+
+	_, err = f.Fetch(time.Millisecond)
+	assert.Error(t, err)
+
+	snm.Connect(fnm)
+	bs, err := f.Fetch(5 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, data, bs)
+}
+
+func TestServer_InvalidOffset(t *testing.T) {
+	file := path.Join(t.TempDir(), "genesis.zip")
+	assert.NoError(t, os.WriteFile(file, []byte("genesis"), 0644))
+
+	nm := test.NewNetworkManager(t, wallet.New().Address())
+	s, err := NewServer(&testChain{nm: nm, log: log.New()}, file)
+	assert.NoError(t, err)
+	defer s.Term()
+
+	_, h := nm.NewPeerFor(module.ProtoGenesis)
+	h.Unicast(ProtoChunkRequest, &ChunkRequest{RequestID: 1, Offset: 7}, nil)
+	var resp ChunkResponse
+	h.Receive(ProtoChunkResponse, nil, &resp)
+	assert.EqualValues(t, 1, resp.RequestID)
+	assert.Equal(t, StatusInvalidRequest, resp.Status)
+	assert.EqualValues(t, 7, resp.Size)
+
+	h.Unicast(ProtoChunkRequest, &ChunkRequest{RequestID: 2, Offset: 3}, nil)
+	h.Receive(ProtoChunkResponse, nil, &resp)
+	assert.Equal(t, StatusOK, resp.Status)
+	assert.Equal(t, []byte("esis"), resp.Data)
+}
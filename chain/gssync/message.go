@@ -0,0 +1,39 @@
+package gssync
+
+import (
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	ProtoChunkRequest module.ProtocolInfo = iota << 8
+	ProtoChunkResponse
+)
+
+var protocols = []module.ProtocolInfo{
+	ProtoChunkRequest,
+	ProtoChunkResponse,
+}
+
+// Status of responses
+const (
+	StatusOK int32 = iota
+	StatusNotFound
+	StatusInvalidRequest
+	StatusLimited
+	StatusFailure
+)
+
+// ChunkRequest requests the chunk of the genesis storage from Offset.
+type ChunkRequest struct {
+	RequestID uint32
+	Offset    int64
+}
+
+// ChunkResponse has the chunk of the genesis storage from the requested
+// offset, and Size is the size of the whole genesis storage.
+type ChunkResponse struct {
+	RequestID uint32
+	Status    int32
+	Size      int64
+	Data      []byte
+}
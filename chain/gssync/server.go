@@ -0,0 +1,167 @@
+package gssync
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configGenesisPriority = 6
+	configRequestRate     = rate.Limit(20)
+	configRequestBurst    = 20
+	configMaxConcurrency  = 4
+	ChunkSize             = 256 * 1024
+)
+
+// Server serves the genesis storage of the chain to the peers joining the
+// chain.
+type Server struct {
+	nm   module.NetworkManager
+	ph   module.ProtocolHandler
+	file string
+	log  log.Logger
+
+	mtx      sync.Mutex
+	limiters map[string]*rate.Limiter
+	sem      chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer returns the server of the genesis storage in the file.
+func NewServer(c module.Chain, file string) (*Server, error) {
+	s := &Server{
+		nm:       c.NetworkManager(),
+		file:     file,
+		log:      c.Logger().WithFields(log.Fields{log.FieldKeyModule: "gssync"}),
+		limiters: make(map[string]*rate.Limiter),
+		sem:      make(chan struct{}, configMaxConcurrency),
+	}
+	ph, err := s.nm.RegisterReactorForStreams("genesis", module.ProtoGenesis, s, protocols, configGenesisPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		return nil, err
+	}
+	s.ph = ph
+	return s, nil
+}
+
+// Term unregisters the server and waits for requests being served.
+func (s *Server) Term() {
+	if err := s.nm.UnregisterReactor(s); err != nil {
+		s.log.Warnf("fail to unregister reactor err=%+v", err)
+	}
+	s.wg.Wait()
+}
+
+func (s *Server) limiterOf(id module.PeerID) *rate.Limiter {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	l, ok := s.limiters[id.String()]
+	if !ok {
+		l = rate.NewLimiter(configRequestRate, configRequestBurst)
+		s.limiters[id.String()] = l
+	}
+	return l
+}
+
+// acquire returns true if the request of the peer can be served now.
+func (s *Server) acquire(id module.PeerID) bool {
+	if !s.limiterOf(id).Allow() {
+		return false
+	}
+	select {
+	case s.sem <- struct{}{}:
+		s.wg.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Server) release() {
+	<-s.sem
+	s.wg.Done()
+}
+
+func (s *Server) send(resp *ChunkResponse, id module.PeerID) {
+	if err := s.ph.Unicast(ProtoChunkResponse, codec.MustMarshalToBytes(resp), id); err != nil {
+		s.log.Debugf("fail to send response peer=%v err=%+v", id, err)
+	}
+}
+
+func (s *Server) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	if pi != ProtoChunkRequest {
+		return false, nil
+	}
+	var msg ChunkRequest
+	if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+		return false, err
+	}
+	if !s.acquire(id) {
+		s.send(&ChunkResponse{
+			RequestID: msg.RequestID,
+			Status:    StatusLimited,
+		}, id)
+		return false, nil
+	}
+	go func() {
+		defer s.release()
+		s.send(s.serveChunk(&msg), id)
+	}()
+	return false, nil
+}
+
+func (s *Server) serveChunk(req *ChunkRequest) *ChunkResponse {
+	resp := &ChunkResponse{RequestID: req.RequestID}
+	fd, err := os.Open(s.file)
+	if err != nil {
+		s.log.Warnf("fail to open genesis file=%s err=%+v", s.file, err)
+		resp.Status = StatusNotFound
+		return resp
+	}
+	defer fd.Close()
+
+	st, err := fd.Stat()
+	if err != nil {
+		resp.Status = StatusFailure
+		return resp
+	}
+	resp.Size = st.Size()
+	if req.Offset < 0 || req.Offset >= resp.Size {
+		resp.Status = StatusInvalidRequest
+		return resp
+	}
+	size := resp.Size - req.Offset
+	if size > ChunkSize {
+		size = ChunkSize
+	}
+	data := make([]byte, size)
+	if _, err := fd.ReadAt(data, req.Offset); err != nil && err != io.EOF {
+		s.log.Warnf("fail to read genesis file=%s err=%+v", s.file, err)
+		resp.Status = StatusFailure
+		return resp
+	}
+	resp.Data = data
+	return resp
+}
+
+func (s *Server) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
+	s.log.Debugf("OnFailure pi=%v err=%+v", pi, err)
+}
+
+func (s *Server) OnJoin(id module.PeerID) {
+}
+
+func (s *Server) OnLeave(id module.PeerID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.limiters, id.String())
+}
@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+// HaltScheduler schedules to halt the chain at the height for coordinated
+// upgrades. The chain doesn't propose nor import blocks above the height,
+// and it stops after the block at the height is finalized.
+type HaltScheduler interface {
+	HaltHeight() int64
+
+	// SetHaltHeight sets the height of the last block. Zero cancels the
+	// schedule.
+	SetHaltHeight(height int64) error
+}
+
+type haltHeightSetter interface {
+	SetHaltHeight(height int64)
+}
+
+func (c *singleChain) HaltHeight() int64 {
+	c.haltLock.Lock()
+	defer c.haltLock.Unlock()
+
+	return c.cfg.HaltHeight
+}
+
+func (c *singleChain) SetHaltHeight(height int64) error {
+	if height < 0 {
+		return errors.IllegalArgumentError.Errorf("NegativeHaltHeight(%d)", height)
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	t, started := c.task.(*taskConsensus)
+	started = started && (c.state == Starting || c.state == Started) && c.bm != nil
+	if started && height > 0 {
+		if blk, err := c.bm.GetLastBlock(); err == nil && blk.Height() > height {
+			return errors.InvalidStateError.Errorf(
+				"PassedHaltHeight(height=%d,last=%d)", height, blk.Height())
+		}
+	}
+
+	c.haltLock.Lock()
+	c.cfg.HaltHeight = height
+	c.haltLock.Unlock()
+
+	if started {
+		return c.watchHalt(t)
+	}
+	return nil
+}
+
+// watchHalt applies the halt height to the block manager, and halts the
+// consensus task after the block at the height is finalized.
+func (c *singleChain) watchHalt(t *taskConsensus) error {
+	height := c.HaltHeight()
+	metric.RecordHaltHeight(c.metricCtx, height)
+	metric.RecordHalted(c.metricCtx, false)
+	if hs, ok := c.bm.(haltHeightSetter); ok {
+		hs.SetHaltHeight(height)
+	}
+	if height == 0 {
+		return nil
+	}
+	bch, err := c.bm.WaitForBlock(height)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if blk, ok := <-bch; ok && blk != nil {
+			c.haltAt(t, blk.Height())
+		}
+	}()
+	return nil
+}
+
+func (c *singleChain) haltAt(t *taskConsensus, height int64) {
+	// the schedule may be changed while it's waiting for the block
+	if c.HaltHeight() != height {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.task != t || (c.state != Starting && c.state != Started) {
+		return
+	}
+	c.logger.Warnf("HALT at height=%d by schedule", height)
+	metric.RecordHalted(c.metricCtx, true)
+	t.halt(height)
+}
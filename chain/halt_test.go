@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type testHaltBlock struct {
+	module.Block
+	height int64
+}
+
+func (b *testHaltBlock) Height() int64 {
+	return b.height
+}
+
+type testHaltBlockManager struct {
+	module.BlockManager
+	lock       sync.Mutex
+	last       int64
+	haltHeight int64
+	waits      map[int64]chan module.Block
+	terminated bool
+}
+
+func (bm *testHaltBlockManager) GetLastBlock() (module.Block, error) {
+	return &testHaltBlock{height: bm.last}, nil
+}
+
+func (bm *testHaltBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+	ch := make(chan module.Block)
+	bm.waits[height] = ch
+	return ch, nil
+}
+
+func (bm *testHaltBlockManager) finalize(height int64) {
+	bm.lock.Lock()
+	ch := bm.waits[height]
+	bm.lock.Unlock()
+	ch <- &testHaltBlock{height: height}
+}
+
+func (bm *testHaltBlockManager) SetHaltHeight(height int64) {
+	bm.haltHeight = height
+}
+
+func (bm *testHaltBlockManager) Term() {
+	bm.terminated = true
+}
+
+func TestSingleChain_SetHaltHeight(t *testing.T) {
+	c := &singleChain{
+		srv:       server.NewManager(&server.Config{}, nil, log.New()),
+		logger:    log.New(),
+		metricCtx: metric.GetMetricContextByCID(1),
+	}
+	c.cfg.Channel = "test"
+
+	err := c.SetHaltHeight(-1)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// it's applied on start if the chain isn't started
+	assert.NoError(t, c.SetHaltHeight(5))
+	assert.Equal(t, int64(5), c.HaltHeight())
+
+	bm := &testHaltBlockManager{last: 3, waits: make(map[int64]chan module.Block)}
+	task := &taskConsensus{chain: c}
+	c.bm = bm
+	c.task = task
+	c.state = Started
+
+	err = c.SetHaltHeight(2)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.Equal(t, int64(5), c.HaltHeight())
+
+	assert.NoError(t, c.SetHaltHeight(4))
+	assert.Equal(t, int64(4), bm.haltHeight)
+
+	// the chain isn't halted by the old schedule
+	assert.NoError(t, c.SetHaltHeight(6))
+	assert.Equal(t, int64(6), bm.haltHeight)
+	bm.finalize(4)
+	time.Sleep(10 * time.Millisecond)
+	c.mtx.RLock()
+	assert.Zero(t, task.halted)
+	assert.False(t, bm.terminated)
+	c.mtx.RUnlock()
+
+	bm.finalize(6)
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, int64(6), task.halted)
+	assert.True(t, bm.terminated)
+	assert.Equal(t, "halted at 6", task.DetailOf(Finished))
+
+	// zero cancels the schedule
+	assert.NoError(t, c.SetHaltHeight(0))
+	assert.Zero(t, c.HaltHeight())
+}
@@ -0,0 +1,72 @@
+package light
+
+import (
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	ProtoBlocksRequest module.ProtocolInfo = iota << 8
+	ProtoBlocksResponse
+	ProtoProofRequest
+	ProtoProofResponse
+)
+
+var protocols = []module.ProtocolInfo{
+	ProtoBlocksRequest,
+	ProtoBlocksResponse,
+	ProtoProofRequest,
+	ProtoProofResponse,
+}
+
+// Options of BlocksRequest
+const (
+	OptionHeader int32 = 1 << iota
+	OptionVotes
+)
+
+// Status of responses
+const (
+	StatusOK int32 = iota
+	StatusNotFound
+	StatusInvalidRequest
+	StatusLimited
+	StatusFailure
+)
+
+// BlocksRequest requests headers and/or votes of blocks from Height.
+// Count is limited by the server, so the client may get fewer blocks.
+type BlocksRequest struct {
+	RequestID uint32
+	Height    int64
+	Count     int32
+	Option    int32
+}
+
+type BlockItem struct {
+	Height int64
+	Header []byte
+	Votes  []byte
+}
+
+type BlocksResponse struct {
+	RequestID uint32
+	Status    int32
+	Blocks    []*BlockItem
+}
+
+// ProofRequest requests the proof of the receipt at Index in the block at
+// Height and the proofs of the events of the receipt.
+type ProofRequest struct {
+	RequestID uint32
+	Height    int64
+	Index     int32
+	Events    []int32
+}
+
+// ProofResponse has the proof of the receipt followed by the proofs of the
+// events in the requested order.
+type ProofResponse struct {
+	RequestID uint32
+	Status    int32
+	Proofs    [][][]byte
+}
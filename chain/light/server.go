@@ -0,0 +1,384 @@
+package light
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configLightPriority       = 5
+	configRequestRate         = rate.Limit(10)
+	configRequestBurst        = 20
+	configMaxConcurrency      = 8
+	configMaxBlocksPerRequest = 64
+	configMaxEventsPerRequest = 64
+	configMaxResponseSize     = 512 * 1024
+)
+
+// Server serves headers, votes and proofs to light peers over p2p.
+type Server struct {
+	c   module.Chain
+	nm  module.NetworkManager
+	ph  module.ProtocolHandler
+	log log.Logger
+
+	mtx      sync.Mutex
+	limiters map[string]*rate.Limiter
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	lastID  uint32
+	pending map[uint32]chan<- *peerResponse
+}
+
+type peerResponse struct {
+	id   module.PeerID
+	resp *BlocksResponse
+}
+
+// PeerHeader is the header of the block reported by the peer. Header is
+// nil if the peer doesn't have the block.
+type PeerHeader struct {
+	Peer   module.PeerID
+	Status int32
+	Header []byte
+}
+
+func NewServer(c module.Chain) (*Server, error) {
+	s := &Server{
+		c:        c,
+		nm:       c.NetworkManager(),
+		log:      c.Logger().WithFields(log.Fields{log.FieldKeyModule: "light"}),
+		limiters: make(map[string]*rate.Limiter),
+		sem:      make(chan struct{}, configMaxConcurrency),
+		pending:  make(map[uint32]chan<- *peerResponse),
+	}
+	ph, err := s.nm.RegisterReactorForStreams("light", module.ProtoLight, s, protocols, configLightPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		return nil, err
+	}
+	s.ph = ph
+	return s, nil
+}
+
+// Term unregisters the server and waits for requests being served.
+func (s *Server) Term() {
+	if err := s.nm.UnregisterReactor(s); err != nil {
+		s.log.Warnf("fail to unregister reactor err=%+v", err)
+	}
+	s.wg.Wait()
+}
+
+func (s *Server) limiterOf(id module.PeerID) *rate.Limiter {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	l, ok := s.limiters[id.String()]
+	if !ok {
+		l = rate.NewLimiter(configRequestRate, configRequestBurst)
+		s.limiters[id.String()] = l
+	}
+	return l
+}
+
+func (s *Server) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	var serve func() (module.ProtocolInfo, interface{})
+	switch pi {
+	case ProtoBlocksRequest:
+		var msg BlocksRequest
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		serve = func() (module.ProtocolInfo, interface{}) {
+			return ProtoBlocksResponse, s.serveBlocks(&msg)
+		}
+		if !s.acquire(id) {
+			s.send(ProtoBlocksResponse, &BlocksResponse{
+				RequestID: msg.RequestID,
+				Status:    StatusLimited,
+			}, id)
+			return false, nil
+		}
+	case ProtoProofRequest:
+		var msg ProofRequest
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		serve = func() (module.ProtocolInfo, interface{}) {
+			return ProtoProofResponse, s.serveProof(&msg)
+		}
+		if !s.acquire(id) {
+			s.send(ProtoProofResponse, &ProofResponse{
+				RequestID: msg.RequestID,
+				Status:    StatusLimited,
+			}, id)
+			return false, nil
+		}
+	case ProtoBlocksResponse:
+		var msg BlocksResponse
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		s.onResponse(&msg, id)
+		return false, nil
+	default:
+		return false, nil
+	}
+	go func() {
+		defer s.release()
+		rpi, resp := serve()
+		s.send(rpi, resp, id)
+	}()
+	return false, nil
+}
+
+// acquire returns true if the request of the peer can be served now.
+func (s *Server) acquire(id module.PeerID) bool {
+	if !s.limiterOf(id).Allow() {
+		return false
+	}
+	select {
+	case s.sem <- struct{}{}:
+		s.wg.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Server) release() {
+	<-s.sem
+	s.wg.Done()
+}
+
+func (s *Server) send(pi module.ProtocolInfo, msg interface{}, id module.PeerID) {
+	if err := s.ph.Unicast(pi, codec.MustMarshalToBytes(msg), id); err != nil {
+		s.log.Debugf("fail to send response pi=%v peer=%v err=%+v", pi, id, err)
+	}
+}
+
+func statusOf(err error) int32 {
+	if errors.NotFoundError.Equals(err) {
+		return StatusNotFound
+	}
+	return StatusFailure
+}
+
+func (s *Server) checkHeight(height int64) error {
+	if height < 0 {
+		return errors.NotFoundError.Errorf("NegativeHeight(height=%d)", height)
+	}
+	if base := s.c.GenesisStorage().Height(); height < base {
+		return errors.NotFoundError.Errorf("PrunedBlock(height=%d,base=%d)", height, base)
+	}
+	return nil
+}
+
+func (s *Server) blockItem(height int64, option int32) (*BlockItem, error) {
+	bi := &BlockItem{Height: height}
+	if option&OptionHeader != 0 {
+		blk, err := s.c.BlockManager().GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := blk.MarshalHeader(buf); err != nil {
+			return nil, err
+		}
+		bi.Header = buf.Bytes()
+	}
+	if option&OptionVotes != 0 {
+		votes, err := s.c.Consensus().GetVotesByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		bi.Votes = votes.Bytes()
+	}
+	return bi, nil
+}
+
+func (s *Server) serveBlocks(req *BlocksRequest) *BlocksResponse {
+	resp := &BlocksResponse{RequestID: req.RequestID}
+	if req.Count <= 0 || req.Option&(OptionHeader|OptionVotes) == 0 {
+		resp.Status = StatusInvalidRequest
+		return resp
+	}
+	if err := s.checkHeight(req.Height); err != nil {
+		resp.Status = statusOf(err)
+		return resp
+	}
+	count := req.Count
+	if count > configMaxBlocksPerRequest {
+		count = configMaxBlocksPerRequest
+	}
+	size := 0
+	for h := req.Height; h < req.Height+int64(count); h++ {
+		bi, err := s.blockItem(h, req.Option)
+		if err != nil {
+			// return blocks got so far, it fails only for the first one.
+			if len(resp.Blocks) == 0 {
+				resp.Status = statusOf(err)
+				if resp.Status == StatusFailure {
+					s.log.Warnf("fail to get block height=%d err=%+v", h, err)
+				}
+			}
+			break
+		}
+		size += len(bi.Header) + len(bi.Votes)
+		if size > configMaxResponseSize && len(resp.Blocks) > 0 {
+			break
+		}
+		resp.Blocks = append(resp.Blocks, bi)
+	}
+	return resp
+}
+
+func (s *Server) serveProof(req *ProofRequest) *ProofResponse {
+	resp := &ProofResponse{RequestID: req.RequestID}
+	if req.Index < 0 || len(req.Events) > configMaxEventsPerRequest {
+		resp.Status = StatusInvalidRequest
+		return resp
+	}
+	proofs, err := s.proofOf(req)
+	if err != nil {
+		resp.Status = statusOf(err)
+		if resp.Status == StatusFailure {
+			s.log.Warnf("fail to get proof height=%d index=%d err=%+v",
+				req.Height, req.Index, err)
+		}
+		return resp
+	}
+	resp.Proofs = proofs
+	return resp
+}
+
+func (s *Server) proofOf(req *ProofRequest) ([][][]byte, error) {
+	if err := s.checkHeight(req.Height); err != nil {
+		return nil, err
+	}
+	blk, err := s.c.BlockManager().GetBlockByHeight(req.Height)
+	if err != nil {
+		return nil, err
+	}
+	rl, err := s.c.ServiceManager().ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	rct, err := rl.Get(int(req.Index))
+	if err != nil {
+		return nil, errors.NotFoundError.Wrapf(err, "fail to get a receipt for index=%d", req.Index)
+	}
+	proof, err := rl.GetProof(int(req.Index))
+	if err != nil {
+		return nil, err
+	}
+	proofs := [][][]byte{proof}
+	for _, idx := range req.Events {
+		proof, err := rct.GetProofOfEvent(int(idx))
+		if errors.InvalidStateError.Equals(err) {
+			// receipt without proofs of events
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, nil
+}
+
+func (s *Server) onResponse(resp *BlocksResponse, id module.PeerID) {
+	s.mtx.Lock()
+	ch, ok := s.pending[resp.RequestID]
+	s.mtx.Unlock()
+
+	if ok {
+		select {
+		case ch <- &peerResponse{id, resp}:
+		default:
+		}
+	}
+}
+
+// QueryHeaders requests the header of the block at the height to the
+// connected peers, and returns the headers reported until the timeout.
+func (s *Server) QueryHeaders(height int64, timeout time.Duration) []*PeerHeader {
+	peers := s.ph.GetPeers()
+	if len(peers) == 0 {
+		return nil
+	}
+	ch := make(chan *peerResponse, len(peers))
+
+	s.mtx.Lock()
+	s.lastID += 1
+	reqID := s.lastID
+	s.pending[reqID] = ch
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		delete(s.pending, reqID)
+		s.mtx.Unlock()
+	}()
+
+	req := codec.MustMarshalToBytes(&BlocksRequest{
+		RequestID: reqID,
+		Height:    height,
+		Count:     1,
+		Option:    OptionHeader,
+	})
+	waiting := make(map[string]bool)
+	for _, id := range peers {
+		if err := s.ph.Unicast(ProtoBlocksRequest, req, id); err != nil {
+			s.log.Debugf("fail to send request peer=%v err=%+v", id, err)
+			continue
+		}
+		waiting[id.String()] = true
+	}
+
+	var headers []*PeerHeader
+	after := time.After(timeout)
+	for len(waiting) > 0 {
+		select {
+		case pr := <-ch:
+			if !waiting[pr.id.String()] {
+				continue
+			}
+			delete(waiting, pr.id.String())
+			ph := &PeerHeader{Peer: pr.id, Status: pr.resp.Status}
+			if pr.resp.Status == StatusOK {
+				if len(pr.resp.Blocks) == 0 || pr.resp.Blocks[0].Height != height {
+					ph.Status = StatusFailure
+				} else {
+					ph.Header = pr.resp.Blocks[0].Header
+				}
+			}
+			headers = append(headers, ph)
+		case <-after:
+			return headers
+		}
+	}
+	return headers
+}
+
+func (s *Server) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
+	s.log.Debugf("OnFailure pi=%v err=%+v", pi, err)
+}
+
+func (s *Server) OnJoin(id module.PeerID) {
+}
+
+func (s *Server) OnLeave(id module.PeerID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.limiters, id.String())
+}
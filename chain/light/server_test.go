@@ -0,0 +1,197 @@
+package light
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+func newServerTestNode(t *testing.T) (*test.Node, *test.SimplePeerHandler) {
+	f, _, h := newTestServer(t)
+	return f, h
+}
+
+func newTestServer(t *testing.T) (*test.Node, *Server, *test.SimplePeerHandler) {
+	f := test.NewNode(t)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	assert.NoError(t, f.CS.Start())
+
+	s, err := NewServer(f.Chain)
+	assert.NoError(t, err)
+	t.Cleanup(s.Term)
+
+	_, h := f.NM.NewPeerFor(module.ProtoLight)
+	return f, s, h
+}
+
+func TestServer_Blocks(t *testing.T) {
+	f, h := newServerTestNode(t)
+	defer f.Close()
+
+	blk, err := f.BM.GetBlockByHeight(0)
+	assert.NoError(t, err)
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, blk.MarshalHeader(buf))
+
+	h.Unicast(ProtoBlocksRequest, &BlocksRequest{
+		RequestID: 1,
+		Height:    0,
+		Count:     configMaxBlocksPerRequest + 1,
+		Option:    OptionHeader | OptionVotes,
+	}, nil)
+	var resp BlocksResponse
+	h.Receive(ProtoBlocksResponse, nil, &resp)
+	assert.EqualValues(t, 1, resp.RequestID)
+	assert.Equal(t, StatusOK, resp.Status)
+	if assert.NotEmpty(t, resp.Blocks) {
+		assert.EqualValues(t, 0, resp.Blocks[0].Height)
+		assert.Equal(t, buf.Bytes(), resp.Blocks[0].Header)
+		assert.Equal(t, consensus.NewEmptyCommitVoteList().Bytes(), resp.Blocks[0].Votes)
+	}
+
+	h.Unicast(ProtoBlocksRequest, &BlocksRequest{
+		RequestID: 2,
+		Height:    0,
+		Count:     1,
+		Option:    OptionVotes,
+	}, nil)
+	h.Receive(ProtoBlocksResponse, &BlocksResponse{
+		RequestID: 2,
+		Status:    StatusOK,
+		Blocks: []*BlockItem{{
+			Height: 0,
+			Votes:  consensus.NewEmptyCommitVoteList().Bytes(),
+		}},
+	}, &resp)
+}
+
+func TestServer_BlocksFail(t *testing.T) {
+	f, h := newServerTestNode(t)
+	defer f.Close()
+
+	h.Unicast(ProtoBlocksRequest, &BlocksRequest{
+		RequestID: 1,
+		Height:    100,
+		Count:     1,
+		Option:    OptionHeader,
+	}, nil)
+	var resp BlocksResponse
+	h.Receive(ProtoBlocksResponse, &BlocksResponse{
+		RequestID: 1,
+		Status:    StatusNotFound,
+	}, &resp)
+
+	h.Unicast(ProtoBlocksRequest, &BlocksRequest{
+		RequestID: 2,
+		Height:    0,
+		Count:     0,
+		Option:    OptionHeader,
+	}, nil)
+	h.Receive(ProtoBlocksResponse, &BlocksResponse{
+		RequestID: 2,
+		Status:    StatusInvalidRequest,
+	}, &resp)
+}
+
+func TestServer_Proof(t *testing.T) {
+	f, h := newServerTestNode(t)
+	defer f.Close()
+
+	// result of the block 1 has the receipt of the genesis transaction
+	blk, err := f.BM.GetBlockByHeight(1)
+	assert.NoError(t, err)
+	rl, err := f.SM.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	assert.NoError(t, err)
+	proof, err := rl.GetProof(0)
+	assert.NoError(t, err)
+
+	h.Unicast(ProtoProofRequest, &ProofRequest{
+		RequestID: 1,
+		Height:    1,
+		Index:     0,
+	}, nil)
+	var resp ProofResponse
+	h.Receive(ProtoProofResponse, &ProofResponse{
+		RequestID: 1,
+		Status:    StatusOK,
+		Proofs:    [][][]byte{proof},
+	}, &resp)
+
+	h.Unicast(ProtoProofRequest, &ProofRequest{
+		RequestID: 2,
+		Height:    1,
+		Index:     1,
+	}, nil)
+	h.Receive(ProtoProofResponse, &ProofResponse{
+		RequestID: 2,
+		Status:    StatusNotFound,
+	}, &resp)
+
+	h.Unicast(ProtoProofRequest, &ProofRequest{
+		RequestID: 3,
+		Height:    1,
+		Index:     -1,
+	}, nil)
+	h.Receive(ProtoProofResponse, &ProofResponse{
+		RequestID: 3,
+		Status:    StatusInvalidRequest,
+	}, &resp)
+}
+
+func TestServer_Limit(t *testing.T) {
+	f, h := newServerTestNode(t)
+	defer f.Close()
+
+	const count = configRequestBurst + configMaxConcurrency
+	for i := 0; i < count; i++ {
+		h.Unicast(ProtoBlocksRequest, &BlocksRequest{
+			RequestID: uint32(i),
+			Height:    0,
+			Count:     1,
+			Option:    OptionHeader,
+		}, nil)
+	}
+	limited := 0
+	for i := 0; i < count; i++ {
+		var resp BlocksResponse
+		h.Receive(ProtoBlocksResponse, nil, &resp)
+		if resp.Status == StatusLimited {
+			limited++
+		}
+	}
+	assert.NotZero(t, limited)
+}
+
+func TestServer_QueryHeaders(t *testing.T) {
+	f, s, h := newTestServer(t)
+	defer f.Close()
+
+	go func() {
+		var req BlocksRequest
+		h.Receive(ProtoBlocksRequest, nil, &req)
+		assert.EqualValues(t, 1, req.Height)
+		assert.Equal(t, OptionHeader, req.Option)
+		h.Unicast(ProtoBlocksResponse, &BlocksResponse{
+			RequestID: req.RequestID,
+			Status:    StatusOK,
+			Blocks:    []*BlockItem{{Height: 1, Header: []byte("header")}},
+		}, nil)
+	}()
+	headers := s.QueryHeaders(1, 5*time.Second)
+	if assert.Len(t, headers, 1) {
+		assert.Equal(t, StatusOK, headers[0].Status)
+		assert.Equal(t, []byte("header"), headers[0].Header)
+	}
+
+	// peers not responding are ignored.
+	headers = s.QueryHeaders(1, 100*time.Millisecond)
+	assert.Empty(t, headers)
+	var req BlocksRequest
+	h.Receive(ProtoBlocksRequest, nil, &req)
+}
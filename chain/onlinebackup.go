@@ -0,0 +1,55 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// OnlineBackupManager takes point-in-time consistent backups of the running
+// chain to directories without stopping it.
+type OnlineBackupManager interface {
+	StartOnlineBackup(dir string, extra []string) error
+	StopOnlineBackup()
+	OnlineBackupStatus() *backup.Status
+}
+
+func (c *singleChain) onlineBackup() *backup.Online {
+	c.obLock.Lock()
+	defer c.obLock.Unlock()
+
+	if c.ob == nil {
+		c.ob = backup.New(c.logger)
+	}
+	return c.ob
+}
+
+func (c *singleChain) StartOnlineBackup(dir string, extra []string) error {
+	barrier, ok := c.cs.(backup.Barrier)
+	database := c.database
+	if !c.IsStarted() || !ok || database == nil {
+		return errors.InvalidStateError.New("ChainNotStarted")
+	}
+	src := &backup.Source{
+		NID:      c.NID(),
+		CID:      c.CID(),
+		Channel:  c.Channel(),
+		DBType:   c.cfg.DBType,
+		DBName:   c.dbName(),
+		DBDir:    DefaultDBDir,
+		Database: database,
+		Barrier:  barrier,
+		Height:   c.lastBlockHeight,
+		BaseDir:  c.cfg.AbsBaseDir(),
+		Synced:   []string{DefaultWALDir},
+		Files:    append([]string{DefaultContractDir}, extra...),
+	}
+	return c.onlineBackup().Start(src, dir)
+}
+
+func (c *singleChain) StopOnlineBackup() {
+	c.onlineBackup().Stop()
+}
+
+func (c *singleChain) OnlineBackupStatus() *backup.Status {
+	return c.onlineBackup().Status()
+}
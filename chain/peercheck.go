@@ -0,0 +1,62 @@
+package chain
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/icon-project/goloop/chain/light"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// PeerChecker compares the last block of the chain with the blocks of the
+// connected peers.
+type PeerChecker interface {
+	CheckLastBlockWithPeers(timeout time.Duration) (*PeerCheck, error)
+}
+
+// PeerCheck is the result of the comparison. Peers is the number of peers
+// answered, and peers without the block are counted in NotFound.
+type PeerCheck struct {
+	Height     int64           `json:"height"`
+	BlockID    common.HexBytes `json:"blockID"`
+	Peers      int             `json:"peers"`
+	Matched    int             `json:"matched"`
+	Mismatched int             `json:"mismatched"`
+	NotFound   int             `json:"notFound"`
+}
+
+func (c *singleChain) CheckLastBlockWithPeers(timeout time.Duration) (*PeerCheck, error) {
+	bm, ls := c.bm, c.ls
+	if !c.IsStarted() || bm == nil {
+		return nil, errors.InvalidStateError.New("ChainNotStarted")
+	}
+	if ls == nil {
+		return nil, errors.UnsupportedError.New("LightServerDisabled")
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := blk.MarshalHeader(buf); err != nil {
+		return nil, err
+	}
+	pc := &PeerCheck{
+		Height:  blk.Height(),
+		BlockID: blk.ID(),
+	}
+	for _, ph := range ls.QueryHeaders(blk.Height(), timeout) {
+		pc.Peers += 1
+		switch {
+		case ph.Status == light.StatusOK && bytes.Equal(ph.Header, buf.Bytes()):
+			pc.Matched += 1
+		case ph.Status == light.StatusOK:
+			c.logger.Warnf("MISMATCHED block height=%d peer=%v", blk.Height(), ph.Peer)
+			pc.Mismatched += 1
+		default:
+			pc.NotFound += 1
+		}
+	}
+	return pc, nil
+}
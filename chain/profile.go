@@ -0,0 +1,40 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/profile"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// ProfileManager measures the size of the data of the chain in background,
+// and shows the result.
+type ProfileManager interface {
+	StartProfile(param *profile.Param) error
+	StopProfile()
+	ProfileStatus() *profile.Status
+}
+
+func (c *singleChain) profiler() *profile.Profiler {
+	c.profLock.Lock()
+	defer c.profLock.Unlock()
+
+	if c.prof == nil {
+		c.prof = profile.New(c.logger)
+	}
+	return c.prof
+}
+
+func (c *singleChain) StartProfile(param *profile.Param) error {
+	bm, sm := c.bm, c.sm
+	if bm == nil || sm == nil {
+		return errors.InvalidStateError.New("ChainNotStarted")
+	}
+	return c.profiler().Start(bm, sm, param)
+}
+
+func (c *singleChain) StopProfile() {
+	c.profiler().Stop()
+}
+
+func (c *singleChain) ProfileStatus() *profile.Status {
+	return c.profiler().Status()
+}
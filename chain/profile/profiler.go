@@ -0,0 +1,220 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profile
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const DefaultTop = 20
+
+const (
+	StateIdle    = "idle"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateFailed  = "failed"
+	StateStopped = "stopped"
+)
+
+// Param is the parameter of a profiling.
+type Param struct {
+	// Top is the number of the largest accounts to report.
+	Top int `json:"top,omitempty"`
+	// ReceiptsFrom is the height of the first block whose result is
+	// measured for receipts. Receipts are not measured if it's zero.
+	ReceiptsFrom int64 `json:"receiptsFrom,omitempty"`
+}
+
+// Receipts is the size of the receipts in the results of the blocks
+// from From to To.
+type Receipts struct {
+	From  int64 `json:"from"`
+	To    int64 `json:"to"`
+	Bytes int64 `json:"bytes"`
+}
+
+// Status is the status of the profiler with the result of the last
+// profiling.
+type Status struct {
+	State    string              `json:"state"`
+	Height   int64               `json:"height,omitempty"`
+	Progress int64               `json:"progress,omitempty"`
+	Elapsed  string              `json:"elapsed,omitempty"`
+	Error    string              `json:"error,omitempty"`
+	Profile  *state.StateProfile `json:"profile,omitempty"`
+	Receipts *Receipts           `json:"receipts,omitempty"`
+}
+
+// Profiler measures the size of the data of the chain in background.
+// Measured sizes of accounts and receipts are kept, so the next profiling
+// measures only the changes after the previous one.
+type Profiler struct {
+	lock     sync.Mutex
+	log      log.Logger
+	sp       *state.StateProfiler
+	status   Status
+	receipts *Receipts
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func New(logger log.Logger) *Profiler {
+	return &Profiler{
+		log:    logger,
+		sp:     state.NewStateProfiler(),
+		status: Status{State: StateIdle},
+	}
+}
+
+// Start starts profiling the state of the last block.
+func (p *Profiler) Start(bm module.BlockManager, sm module.ServiceManager, param *Param) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.status.State == StateRunning {
+		return errors.InvalidStateError.New("AlreadyRunning")
+	}
+	if param.Top <= 0 {
+		param.Top = DefaultTop
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	p.status = Status{
+		State:    StateRunning,
+		Height:   blk.Height(),
+		Receipts: p.receipts,
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(bm, sm, blk, *param, p.stop, p.done)
+	return nil
+}
+
+// Stop stops the running profiling and waits for it.
+func (p *Profiler) Stop() {
+	p.lock.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (p *Profiler) Status() *Status {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s := p.status
+	return &s
+}
+
+func (p *Profiler) setProgress(n int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.status.Progress = n
+}
+
+func checkStop(stop <-chan struct{}) error {
+	select {
+	case <-stop:
+		return errors.ErrInterrupted
+	default:
+		return nil
+	}
+}
+
+func (p *Profiler) run(bm module.BlockManager, sm module.ServiceManager, blk module.Block, param Param, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	start := time.Now()
+	sp, err := service.ProfileState(sm, p.sp, blk.Result(), param.Top, func(n int64) error {
+		p.setProgress(n)
+		return checkStop(stop)
+	})
+	var receipts *Receipts
+	if err == nil && param.ReceiptsFrom > 0 {
+		receipts, err = p.measureReceipts(bm, sm, param.ReceiptsFrom, blk.Height(), stop)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.status.Elapsed = time.Since(start).String()
+	p.status.Profile = sp
+	if receipts != nil {
+		p.status.Receipts = receipts
+	}
+	switch {
+	case err == nil:
+		p.status.State = StateDone
+	case errors.InterruptedError.Equals(err):
+		p.status.State = StateStopped
+	default:
+		p.log.Warnf("Fail to profile height=%d err=%+v", blk.Height(), err)
+		p.status.State = StateFailed
+		p.status.Error = err.Error()
+	}
+}
+
+// measureReceipts measures the receipts in the results of the blocks
+// up to the height. It continues from the previous measurement if it
+// started from the same height.
+func (p *Profiler) measureReceipts(bm module.BlockManager, sm module.ServiceManager, from, height int64, stop <-chan struct{}) (*Receipts, error) {
+	p.lock.Lock()
+	var r Receipts
+	if p.receipts != nil && p.receipts.From == from {
+		r = *p.receipts
+	} else {
+		r = Receipts{From: from, To: from - 1}
+	}
+	p.lock.Unlock()
+
+	var err error
+	for h := r.To + 1; h <= height; h++ {
+		if err = checkStop(stop); err != nil {
+			break
+		}
+		var blk module.Block
+		if blk, err = bm.GetBlockByHeight(h); err != nil {
+			break
+		}
+		var size int64
+		if size, err = service.ReceiptsSize(sm, blk.Result()); err != nil {
+			break
+		}
+		r.Bytes += size
+		r.To = h
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.receipts = &r
+	return &r, err
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2021 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+// quotaBurst is the period of the budget which can be used at once
+// after idle.
+const quotaBurst = time.Second
+
+// QuotaManager changes the quota of the running chain.
+type QuotaManager interface {
+	// SetQuota sets the percentage of a CPU core for execution, the
+	// number of concurrent executors and bytes of database I/O per
+	// second. Zero means no limit.
+	SetQuota(cpu, goroutines int, dbIO int64)
+}
+
+// throttle is a token bucket allowing debts. Usage exceeding the budget
+// is allowed, and the caller is delayed to pay the debt back.
+type throttle struct {
+	rate int64 // units per second, zero for no limit
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func (t *throttle) setRate(rate int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	atomic.StoreInt64(&t.rate, rate)
+	t.tokens = float64(rate) * quotaBurst.Seconds()
+	t.last = time.Now()
+}
+
+func (t *throttle) enabled() bool {
+	return atomic.LoadInt64(&t.rate) > 0
+}
+
+// consume uses n units and returns the delay to keep the rate.
+func (t *throttle) consume(n float64) time.Duration {
+	if !t.enabled() {
+		return 0
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rate := float64(t.rate)
+	if rate <= 0 {
+		return 0
+	}
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * rate
+	if burst := rate * quotaBurst.Seconds(); t.tokens > burst {
+		t.tokens = burst
+	}
+	t.last = now
+	t.tokens -= n
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / rate * float64(time.Second))
+}
+
+type quota struct {
+	lock       sync.Mutex
+	cond       *sync.Cond
+	goroutines int
+	running    int
+
+	cpu  throttle
+	dbIO throttle
+
+	metric *metric.QuotaMetric
+}
+
+func (q *quota) SetQuota(cpu, goroutines int, dbIO int64) {
+	q.lock.Lock()
+	q.goroutines = goroutines
+	q.lock.Unlock()
+	q.cond.Broadcast()
+
+	// budget of CPU is micro-seconds of execution per second.
+	q.cpu.setRate(int64(cpu) * int64(time.Second/time.Microsecond) / 100)
+	q.dbIO.setRate(dbIO)
+}
+
+func (q *quota) AcquireExecutor() func() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.goroutines > 0 && q.running >= q.goroutines {
+		start := time.Now()
+		for q.goroutines > 0 && q.running >= q.goroutines {
+			q.cond.Wait()
+		}
+		q.metric.OnThrottle(metric.QuotaTypeGoroutine, time.Since(start))
+	}
+	q.running += 1
+	return q.release
+}
+
+func (q *quota) release() {
+	q.lock.Lock()
+	q.running -= 1
+	q.lock.Unlock()
+	q.cond.Signal()
+}
+
+func (q *quota) OnExecution(d time.Duration) {
+	q.metric.OnExecution(d)
+	q.delay(metric.QuotaTypeCPU, q.cpu.consume(float64(d/time.Microsecond)))
+}
+
+func (q *quota) onDBIO(n int) {
+	if !q.dbIO.enabled() {
+		return
+	}
+	q.metric.OnDBIO(n)
+	q.delay(metric.QuotaTypeDBIO, q.dbIO.consume(float64(n)))
+}
+
+func (q *quota) delay(t string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	q.metric.OnThrottle(t, d)
+	time.Sleep(d)
+}
+
+func newQuota(cfg *Config, ctx context.Context) *quota {
+	q := &quota{
+		metric: metric.NewQuotaMetric(ctx),
+	}
+	q.cond = sync.NewCond(&q.lock)
+	q.SetQuota(cfg.QuotaCPU, cfg.QuotaGoroutines, cfg.QuotaDBIO)
+	return q
+}
+
+// quotaDatabase consumes the database I/O quota with the size of keys
+// and values read from and written to the buckets.
+type quotaDatabase struct {
+	db.Database
+	quota *quota
+}
+
+func (d *quotaDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaBucket{bk, d.quota}, nil
+}
+
+func (d *quotaDatabase) Checkpoint() (db.Checkpoint, error) {
+	return db.NewCheckpoint(d.Database)
+}
+
+type quotaBucket struct {
+	db.Bucket
+	quota *quota
+}
+
+func (b *quotaBucket) Get(key []byte) ([]byte, error) {
+	value, err := b.Bucket.Get(key)
+	b.quota.onDBIO(len(key) + len(value))
+	return value, err
+}
+
+func (b *quotaBucket) Has(key []byte) (bool, error) {
+	b.quota.onDBIO(len(key))
+	return b.Bucket.Has(key)
+}
+
+func (b *quotaBucket) Set(key []byte, value []byte) error {
+	b.quota.onDBIO(len(key) + len(value))
+	return b.Bucket.Set(key, value)
+}
+
+func (b *quotaBucket) Delete(key []byte) error {
+	b.quota.onDBIO(len(key))
+	return b.Bucket.Delete(key)
+}
@@ -0,0 +1,140 @@
+package chain
+
+import (
+	"path"
+	"time"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain/recovery"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const configSettleTimeout = time.Minute
+
+// RecoveryManager shows the faults of the chain with the actions taken by
+// the recovery policy in the configuration.
+type RecoveryManager interface {
+	RecoveryEvents() ([]*recovery.Event, error)
+}
+
+// faultKindOf returns the kind of the fault causing the error, or an empty
+// string if it's not caused by a fault.
+func faultKindOf(err error) string {
+	if f := module.FaultOf(err); f != nil {
+		return f.Kind
+	}
+	switch errors.CodeOf(err) {
+	case errors.CriticalIOError, errors.CriticalFormatError:
+		return module.FaultDatabase
+	}
+	if db.IsCorrupted(err) {
+		return module.FaultDatabase
+	}
+	return ""
+}
+
+func (c *singleChain) HandleFault(kind string, err error) bool {
+	if c.re == nil {
+		return false
+	}
+	if k := faultKindOf(err); k != "" {
+		kind = k
+	}
+	return c.re.HandleFault(kind, err)
+}
+
+// handleStartFault passes the failure on start to the recovery policy if
+// it's caused by a fault.
+func (c *singleChain) handleStartFault(err error) {
+	if kind := faultKindOf(err); kind != "" {
+		c.HandleFault(kind, err)
+	}
+}
+
+func (c *singleChain) RecoveryEvents() ([]*recovery.Event, error) {
+	if c.re == nil {
+		return nil, errors.InvalidStateError.New("NoRecoveryPolicy")
+	}
+	return c.re.Events(), nil
+}
+
+// stopAndWait stops the running task, and waits until the chain is
+// stopped.
+func (c *singleChain) stopAndWait() error {
+	after := time.After(configSettleTimeout)
+	for {
+		c.mtx.RLock()
+		state := c.state
+		c.mtx.RUnlock()
+
+		switch state {
+		case Stopped:
+			return nil
+		case Started, Failed, Finished:
+			if err := c.Stop(); err != nil && !errors.InvalidStateError.Equals(err) {
+				return err
+			}
+		case Starting, Stopping:
+		default:
+			return errors.InvalidStateError.Errorf("InvalidState(state=%s)", state)
+		}
+		select {
+		case <-after:
+			return errors.TimeoutError.Errorf("NotStopped(state=%s)", state)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (c *singleChain) Restart() error {
+	c.logger.Warnf("RESTART by recovery policy")
+	if err := c.stopAndWait(); err != nil {
+		return err
+	}
+	return c.Start()
+}
+
+// Rollback resets the chain to the block before the blocks from the last,
+// then it starts the chain to sync blocks from the peers.
+func (c *singleChain) Rollback(blocks int64) error {
+	c.logger.Warnf("ROLLBACK blocks=%d by recovery policy", blocks)
+	if err := c.stopAndWait(); err != nil {
+		return err
+	}
+	height, hash, err := c.rollbackTarget(blocks)
+	if err != nil {
+		return err
+	}
+	gs := path.Join(c.cfg.AbsBaseDir(), chainGenesisZipFileName)
+	if err := c._runTask(newTaskReset(c, gs, height, hash), true); err != nil {
+		return err
+	}
+	return c.Start()
+}
+
+func (c *singleChain) rollbackTarget(blocks int64) (int64, []byte, error) {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.database == nil {
+		return 0, nil, errors.InvalidStateError.New("NoDatabase")
+	}
+	height := block.GetLastHeightOf(c.database) - blocks
+	if height < 2 {
+		return 0, nil, errors.InvalidStateError.Errorf(
+			"NoBlockToRollback(height=%d)", height)
+	}
+	hash, err := block.GetBlockHeaderHashByHeight(c.database, nil, height)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, hash, nil
+}
+
+// Halt stops the chain until the operator starts it again.
+func (c *singleChain) Halt() error {
+	c.logger.Errorf("HALT by recovery policy")
+	return c.stopAndWait()
+}
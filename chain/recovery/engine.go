@@ -0,0 +1,281 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Actions for faults
+const (
+	ActionRestart  = "restart"
+	ActionRollback = "rollback"
+	ActionHalt     = "halt"
+)
+
+// Results of actions
+const (
+	ResultRunning = "running"
+	ResultDone    = "done"
+	ResultFailed  = "failed"
+	ResultIgnored = "ignored"
+)
+
+const (
+	DefaultMaxAttempts = 3
+	DefaultWindow      = 10 * time.Minute
+
+	configMaxEvents    = 32
+	configAlertTimeout = 5 * time.Second
+)
+
+// Rule is the action for a kind of faults. Blocks is the number of blocks
+// to roll back for ActionRollback. If faults of the kind happen more than
+// MaxAttempts times in Window seconds, then the chain is halted instead.
+type Rule struct {
+	Action      string `json:"action"`
+	Blocks      int64  `json:"blocks,omitempty"`
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	Window      int64  `json:"window,omitempty"`
+}
+
+func (r *Rule) maxAttempts() int {
+	if r.MaxAttempts > 0 {
+		return r.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+func (r *Rule) window() time.Duration {
+	if r.Window > 0 {
+		return time.Duration(r.Window) * time.Second
+	}
+	return DefaultWindow
+}
+
+// Config is the recovery policy in the chain configuration. Rules are
+// keyed by the kinds of faults, and faults without rules are not handled.
+// Alerts are posted to AlertURL in JSON when the chain is halted.
+type Config struct {
+	Rules    map[string]*Rule `json:"rules"`
+	AlertURL string           `json:"alert_url,omitempty"`
+}
+
+var faultKinds = []string{
+	module.FaultDatabase,
+	module.FaultWAL,
+	module.FaultExecution,
+}
+
+func (c *Config) Validate() error {
+	for kind, rule := range c.Rules {
+		known := false
+		for _, k := range faultKinds {
+			if k == kind {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.IllegalArgumentError.Errorf("UnknownFault(kind=%s)", kind)
+		}
+		if rule == nil {
+			return errors.IllegalArgumentError.Errorf("NoRule(kind=%s)", kind)
+		}
+		switch rule.Action {
+		case ActionRestart, ActionHalt:
+		case ActionRollback:
+			if rule.Blocks <= 0 {
+				return errors.IllegalArgumentError.Errorf(
+					"InvalidBlocks(kind=%s,blocks=%d)", kind, rule.Blocks)
+			}
+		default:
+			return errors.IllegalArgumentError.Errorf(
+				"UnknownAction(kind=%s,action=%s)", kind, rule.Action)
+		}
+	}
+	return nil
+}
+
+// Target is the chain recovering from faults. Methods are called one by
+// one, and they return after the action is finished.
+type Target interface {
+	Restart() error
+	Rollback(blocks int64) error
+	Halt() error
+}
+
+// Event is the fault with the action taken for it.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Error  string    `json:"error"`
+	Action string    `json:"action"`
+	Result string    `json:"result"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Engine takes actions for the faults of the chain by the policy.
+type Engine struct {
+	cfg    *Config
+	target Target
+	log    log.Logger
+
+	lock    sync.Mutex
+	faults  map[string][]time.Time
+	running bool
+	events  []*Event
+}
+
+func New(cfg *Config, target Target, logger log.Logger) (*Engine, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Engine{
+		cfg:    cfg,
+		target: target,
+		log:    logger,
+		faults: make(map[string][]time.Time),
+	}, nil
+}
+
+func (e *Engine) _addEvent(ev *Event) {
+	e.events = append(e.events, ev)
+	if len(e.events) > configMaxEvents {
+		e.events = e.events[len(e.events)-configMaxEvents:]
+	}
+}
+
+// HandleFault takes the action for the fault in background. It returns
+// false if there is no rule for the kind. Faults are ignored while an
+// action is running.
+func (e *Engine) HandleFault(kind string, err error) bool {
+	rule, ok := e.cfg.Rules[kind]
+	if !ok {
+		return false
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	now := time.Now()
+	ev := &Event{
+		Time:   now,
+		Kind:   kind,
+		Error:  err.Error(),
+		Action: rule.Action,
+	}
+	if e.running {
+		ev.Result = ResultIgnored
+		e._addEvent(ev)
+		return true
+	}
+
+	since := now.Add(-rule.window())
+	faults := []time.Time{now}
+	for _, t := range e.faults[kind] {
+		if t.After(since) {
+			faults = append(faults, t)
+		}
+	}
+	e.faults[kind] = faults
+	if len(faults) > rule.maxAttempts() && ev.Action != ActionHalt {
+		ev.Action = ActionHalt
+		ev.Reason = "TooManyFaults"
+	}
+
+	e.log.Warnf("FAULT kind=%s action=%s err=%+v", kind, ev.Action, err)
+	ev.Result = ResultRunning
+	e._addEvent(ev)
+	e.running = true
+	go e.run(ev, rule)
+	return true
+}
+
+func (e *Engine) run(ev *Event, rule *Rule) {
+	var err error
+	switch ev.Action {
+	case ActionRestart:
+		err = e.target.Restart()
+	case ActionRollback:
+		err = e.target.Rollback(rule.Blocks)
+	case ActionHalt:
+		err = e.target.Halt()
+	}
+	result, reason, halted := ResultDone, ev.Reason, ev.Action == ActionHalt
+	if err != nil {
+		e.log.Errorf("FAIL to recover action=%s err=%+v", ev.Action, err)
+		result, reason = ResultFailed, err.Error()
+		if !halted {
+			if err := e.target.Halt(); err != nil {
+				e.log.Errorf("FAIL to halt err=%+v", err)
+			}
+			halted = true
+		}
+	}
+
+	e.lock.Lock()
+	ev.Result = result
+	ev.Reason = reason
+	e.running = false
+	alert := *ev
+	e.lock.Unlock()
+
+	if halted {
+		e.log.Errorf("HALTED kind=%s err=%s", ev.Kind, ev.Error)
+		e.alert(&alert)
+	}
+}
+
+func (e *Engine) alert(ev *Event) {
+	if e.cfg.AlertURL == "" {
+		return
+	}
+	bs, err := json.Marshal(ev)
+	if err != nil {
+		e.log.Warnf("FAIL to marshal alert err=%+v", err)
+		return
+	}
+	client := &http.Client{Timeout: configAlertTimeout}
+	resp, err := client.Post(e.cfg.AlertURL, "application/json", bytes.NewReader(bs))
+	if err != nil {
+		e.log.Warnf("FAIL to send alert url=%s err=%+v", e.cfg.AlertURL, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Events returns the recent faults with the actions taken for them.
+func (e *Engine) Events() []*Event {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	events := make([]*Event, len(e.events))
+	for i, ev := range e.events {
+		evCopy := *ev
+		events[i] = &evCopy
+	}
+	return events
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+type testTarget struct {
+	lock    sync.Mutex
+	actions []string
+	fail    bool
+}
+
+func (t *testTarget) do(action string) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.actions = append(t.actions, action)
+	if t.fail && action != ActionHalt {
+		return errors.InvalidStateError.New("Failure")
+	}
+	return nil
+}
+
+func (t *testTarget) Restart() error {
+	return t.do(ActionRestart)
+}
+
+func (t *testTarget) Rollback(blocks int64) error {
+	return t.do(ActionRollback)
+}
+
+func (t *testTarget) Halt() error {
+	return t.do(ActionHalt)
+}
+
+func (t *testTarget) Actions() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]string{}, t.actions...)
+}
+
+func waitIdle(t *testing.T, e *Engine) []*Event {
+	for i := 0; i < 100; i++ {
+		events := e.Events()
+		if len(events) > 0 && events[len(events)-1].Result != ResultRunning {
+			return events
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("action is not finished")
+	return nil
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := []struct {
+		rules map[string]*Rule
+		valid bool
+	}{
+		{map[string]*Rule{module.FaultWAL: {Action: ActionRestart}}, true},
+		{map[string]*Rule{module.FaultDatabase: {Action: ActionRollback, Blocks: 10}}, true},
+		{map[string]*Rule{module.FaultDatabase: {Action: ActionRollback}}, false},
+		{map[string]*Rule{module.FaultExecution: {Action: "unknown"}}, false},
+		{map[string]*Rule{"unknown": {Action: ActionHalt}}, false},
+		{map[string]*Rule{module.FaultWAL: nil}, false},
+	}
+	for i, c := range cases {
+		cfg := &Config{Rules: c.rules}
+		assert.Equal(t, c.valid, cfg.Validate() == nil, "case %d", i)
+	}
+}
+
+func TestEngine_HandleFault(t *testing.T) {
+	target := new(testTarget)
+	e, err := New(&Config{
+		Rules: map[string]*Rule{
+			module.FaultExecution: {Action: ActionRestart, MaxAttempts: 2},
+		},
+	}, target, log.New())
+	assert.NoError(t, err)
+
+	// faults without rules are not handled
+	assert.False(t, e.HandleFault(module.FaultWAL, errors.New("wal")))
+
+	for i := 0; i < 2; i++ {
+		assert.True(t, e.HandleFault(module.FaultExecution, errors.New("exec")))
+		waitIdle(t, e)
+	}
+	assert.Equal(t, []string{ActionRestart, ActionRestart}, target.Actions())
+
+	// it halts the chain on too many faults
+	assert.True(t, e.HandleFault(module.FaultExecution, errors.New("exec")))
+	events := waitIdle(t, e)
+	assert.Len(t, events, 3)
+	assert.Equal(t, ActionHalt, events[2].Action)
+	assert.Equal(t, ResultDone, events[2].Result)
+	assert.Equal(t, []string{ActionRestart, ActionRestart, ActionHalt}, target.Actions())
+}
+
+func TestEngine_HaltOnFailure(t *testing.T) {
+	alerts := make(chan *Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev := new(Event)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(ev))
+		alerts <- ev
+	}))
+	defer srv.Close()
+
+	target := &testTarget{fail: true}
+	e, err := New(&Config{
+		Rules: map[string]*Rule{
+			module.FaultDatabase: {Action: ActionRollback, Blocks: 10},
+		},
+		AlertURL: srv.URL,
+	}, target, log.New())
+	assert.NoError(t, err)
+
+	assert.True(t, e.HandleFault(module.FaultDatabase, errors.New("corrupted")))
+	events := waitIdle(t, e)
+	assert.Equal(t, ResultFailed, events[0].Result)
+	assert.Equal(t, []string{ActionRollback, ActionHalt}, target.Actions())
+
+	select {
+	case ev := <-alerts:
+		assert.Equal(t, module.FaultDatabase, ev.Kind)
+		assert.Equal(t, ResultFailed, ev.Result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert")
+	}
+}
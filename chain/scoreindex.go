@@ -0,0 +1,39 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/scoreindex"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func (c *singleChain) startSCOREIndex() {
+	if !c.cfg.SCOREIndex {
+		return
+	}
+	c.siLock.Lock()
+	defer c.siLock.Unlock()
+
+	c.si = scoreindex.New(c)
+	c.si.Start()
+}
+
+func (c *singleChain) stopSCOREIndex() {
+	c.siLock.Lock()
+	defer c.siLock.Unlock()
+
+	if c.si != nil {
+		c.si.Stop()
+		c.si = nil
+	}
+}
+
+// SCOREIndex returns the index of contracts if it's enabled with
+// score_index of the chain configuration.
+func (c *singleChain) SCOREIndex() (*scoreindex.Index, error) {
+	c.siLock.Lock()
+	defer c.siLock.Unlock()
+
+	if c.si == nil {
+		return nil, errors.UnsupportedError.New("SCOREIndexDisabled")
+	}
+	return c.si, nil
+}
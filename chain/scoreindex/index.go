@@ -0,0 +1,415 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scoreindex
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	keyCursor  = "score_index.cursor"
+	keySize    = "score_index.size"
+	keyItem    = "score_index.item."
+	keyAddress = "score_index.addr."
+	keyPending = "score_index.pending"
+)
+
+// Provider is implemented by chains with the index of contracts.
+type Provider interface {
+	SCOREIndex() (*Index, error)
+}
+
+// Entry is a contract in the index. Height is the height of the block
+// including the transaction deploying the contract.
+type Entry struct {
+	Address *common.Address
+	Height  int64
+}
+
+// Index is the list of contracts in order of deployment. It follows
+// finalized blocks and records the addresses of deployed contracts in
+// receipts, so that they can be enumerated without scanning transactions.
+type Index struct {
+	chain module.Chain
+	dbase db.Database
+	log   log.Logger
+
+	// lock serializes writers of the index.
+	lock sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (idx *Index) bucket() (db.Bucket, error) {
+	return idx.dbase.GetBucket(db.ChainProperty)
+}
+
+func (idx *Index) getInt64(key string) (int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(key))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var v int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func (idx *Index) setInt64(key string, v int64) error {
+	bk, err := idx.bucket()
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(key), codec.BC.MustMarshalToBytes(v))
+}
+
+func itemKey(n int64) []byte {
+	key := make([]byte, len(keyItem)+8)
+	copy(key, keyItem)
+	binary.BigEndian.PutUint64(key[len(keyItem):], uint64(n))
+	return key
+}
+
+func addressKey(addr module.Address) []byte {
+	return append([]byte(keyAddress), addr.Bytes()...)
+}
+
+// Cursor returns the height of the next block to be indexed. Contracts
+// deployed in the blocks lower than the cursor are in the index.
+func (idx *Index) Cursor() (int64, error) {
+	return idx.getInt64(keyCursor)
+}
+
+// Size returns the number of contracts in the index.
+func (idx *Index) Size() (int64, error) {
+	return idx.getInt64(keySize)
+}
+
+func (idx *Index) get(bk db.Bucket, n int64) (*Entry, error) {
+	bs, err := bk.Get(itemKey(n))
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.NotFoundError.Errorf("NoEntry(index=%d)", n)
+	}
+	e := new(Entry)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Add adds contracts deployed in the block at the height, and moves the
+// cursor to the next block. Contracts already in the index are ignored,
+// so that it's safe to add the contracts of the block again.
+func (idx *Index) Add(height int64, addrs []module.Address) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	bk, err := idx.bucket()
+	if err != nil {
+		return err
+	}
+	size, err := idx.Size()
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		bs, err := bk.Get(addressKey(addr))
+		if err != nil {
+			return err
+		}
+		if bs != nil {
+			var n int64
+			if _, err := codec.BC.UnmarshalFromBytes(bs, &n); err != nil {
+				return err
+			}
+			if n < size {
+				continue
+			}
+		}
+		e := &Entry{Address: common.AddressToPtr(addr), Height: height}
+		if err := bk.Set(itemKey(size), codec.BC.MustMarshalToBytes(e)); err != nil {
+			return err
+		}
+		if err := bk.Set(addressKey(addr), codec.BC.MustMarshalToBytes(size)); err != nil {
+			return err
+		}
+		size += 1
+		if err := idx.setInt64(keySize, size); err != nil {
+			return err
+		}
+	}
+	return idx.setInt64(keyCursor, height+1)
+}
+
+// List returns at most limit contracts deployed before the height from
+// the start in order of deployment, and the number of all of them.
+func (idx *Index) List(height int64, start int64, limit int) ([]*Entry, int64, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := idx.Size()
+	if err != nil {
+		return nil, 0, err
+	}
+	// entries are in order of height, so find the first one deployed at
+	// the height or later.
+	var serr error
+	total := int64(sort.Search(int(size), func(i int) bool {
+		if serr != nil {
+			return true
+		}
+		e, err := idx.get(bk, int64(i))
+		if err != nil {
+			serr = err
+			return true
+		}
+		return e.Height >= height
+	}))
+	if serr != nil {
+		return nil, 0, serr
+	}
+	entries := []*Entry{}
+	for n := start; n < total && len(entries) < limit; n++ {
+		e, err := idx.get(bk, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, nil
+}
+
+// Pending returns contracts whose deployments are waiting for audit at the
+// cursor in order of deployment. Height of the entry is the height of the
+// block including the deployment.
+func (idx *Index) Pending() ([]*Entry, error) {
+	bk, err := idx.bucket()
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get([]byte(keyPending))
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	var entries []*Entry
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// updatePending updates contracts waiting for audit with contracts deployed
+// in the block at the height. The result is the state after the block, and
+// contracts without pending deployments in the state are removed.
+func (idx *Index) updatePending(height int64, deployed []module.Address, result []byte) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	pending, err := idx.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 && len(deployed) == 0 {
+		return nil
+	}
+	for _, addr := range deployed {
+		for i, e := range pending {
+			if e.Address.Equal(addr) {
+				pending = append(pending[:i], pending[i+1:]...)
+				break
+			}
+		}
+		pending = append(pending, &Entry{Address: common.AddressToPtr(addr), Height: height})
+	}
+	sm := idx.chain.ServiceManager()
+	remains := make([]*Entry, 0, len(pending))
+	for _, e := range pending {
+		s, err := sm.GetSCOREStatus(result, e.Address)
+		if err != nil {
+			if errors.NotFoundError.Equals(err) {
+				continue
+			}
+			return err
+		}
+		if s.PendingTxHash() != nil {
+			remains = append(remains, e)
+		}
+	}
+	bk, err := idx.bucket()
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(keyPending), codec.BC.MustMarshalToBytes(remains))
+}
+
+// deployedIn returns addresses of contracts deployed by transactions in the
+// previous block of next, whose receipts are in the result of next.
+func (idx *Index) deployedIn(next module.Block) ([]module.Address, error) {
+	var addrs []module.Address
+	sm := idx.chain.ServiceManager()
+	for _, g := range []module.TransactionGroup{
+		module.TransactionGroupPatch, module.TransactionGroupNormal,
+	} {
+		rl, err := sm.ReceiptListFromResult(next.Result(), g)
+		if err != nil {
+			return nil, err
+		}
+		for itr := rl.Iterator(); itr.Has(); itr.Next() {
+			r, err := itr.Get()
+			if err != nil {
+				return nil, err
+			}
+			if addr := r.SCOREAddress(); addr != nil {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// genesisContracts returns addresses of contracts installed by the genesis
+// transaction. Receipts of the genesis don't have them.
+func genesisContracts(blk module.Block) []module.Address {
+	var addrs []module.Address
+	for itr := blk.NormalTransactions().Iterator(); itr.Has(); itr.Next() {
+		tx, _, err := itr.Get()
+		if err != nil {
+			continue
+		}
+		js, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			continue
+		}
+		jso, ok := js.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		accounts, _ := jso["accounts"].([]interface{})
+		for _, acc := range accounts {
+			m, ok := acc.(map[string]interface{})
+			if !ok || m["score"] == nil {
+				continue
+			}
+			s, _ := m["address"].(string)
+			if addr, err := common.NewAddressFromString(s); err == nil && addr.IsContract() {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs
+}
+
+func (idx *Index) waitBlock(height int64) (module.Block, bool) {
+	bch, err := idx.chain.BlockManager().WaitForBlock(height)
+	if err != nil {
+		idx.log.Warnf("Fail to wait block height=%d err=%+v", height, err)
+		return nil, false
+	}
+	select {
+	case blk, ok := <-bch:
+		return blk, ok
+	case <-idx.stop:
+		return nil, false
+	}
+}
+
+func (idx *Index) run() {
+	defer close(idx.done)
+
+	height, err := idx.Cursor()
+	if err != nil {
+		idx.log.Errorf("Fail to get cursor err=%+v", err)
+		return
+	}
+	bm := idx.chain.BlockManager()
+	if height == 0 {
+		// blocks before the pruned genesis are not available.
+		if gs := idx.chain.GenesisStorage(); gs != nil {
+			height = gs.Height()
+		}
+	}
+	idx.log.Infof("SCORE index started cursor=%d", height)
+	for {
+		next, ok := idx.waitBlock(height + 1)
+		if !ok {
+			return
+		}
+		deployed, err := idx.deployedIn(next)
+		if err != nil {
+			idx.log.Errorf("Fail to get contracts height=%d err=%+v", height, err)
+			return
+		}
+		if err := idx.updatePending(height, deployed, next.Result()); err != nil {
+			idx.log.Errorf("Fail to update pending contracts height=%d err=%+v", height, err)
+			return
+		}
+		addrs := deployed
+		if height == 0 {
+			blk, err := bm.GetBlockByHeight(height)
+			if err != nil {
+				idx.log.Errorf("Fail to get block height=%d err=%+v", height, err)
+				return
+			}
+			addrs = append([]module.Address{state.SystemAddress}, genesisContracts(blk)...)
+			addrs = append(addrs, deployed...)
+		}
+		if err := idx.Add(height, addrs); err != nil {
+			idx.log.Errorf("Fail to add contracts height=%d err=%+v", height, err)
+			return
+		}
+		height += 1
+	}
+}
+
+func (idx *Index) Start() {
+	go idx.run()
+}
+
+func (idx *Index) Stop() {
+	close(idx.stop)
+	<-idx.done
+}
+
+func New(c module.Chain) *Index {
+	return &Index{
+		chain: c,
+		dbase: c.Database(),
+		log:   c.Logger().WithFields(log.Fields{log.FieldKeyModule: "SI"}),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scoreindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestIndex_AddList(t *testing.T) {
+	idx := &Index{dbase: db.NewMapDB()}
+
+	addrs := []module.Address{
+		common.MustNewAddressFromString("cx0000000000000000000000000000000000000000"),
+		common.MustNewAddressFromString("cx0000000000000000000000000000000000000001"),
+		common.MustNewAddressFromString("cx0000000000000000000000000000000000000002"),
+		common.MustNewAddressFromString("cx0000000000000000000000000000000000000003"),
+	}
+	assert.NoError(t, idx.Add(0, addrs[:2]))
+	assert.NoError(t, idx.Add(1, nil))
+	assert.NoError(t, idx.Add(2, addrs[2:3]))
+	// added again after restart
+	assert.NoError(t, idx.Add(2, addrs[2:3]))
+	assert.NoError(t, idx.Add(3, addrs[1:]))
+
+	cursor, err := idx.Cursor()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, cursor)
+	size, err := idx.Size()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, size)
+
+	entries, total, err := idx.List(3, 0, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	assert.Len(t, entries, 3)
+	for i, e := range entries[:2] {
+		assert.True(t, addrs[i].Equal(e.Address))
+		assert.EqualValues(t, 0, e.Height)
+	}
+	assert.EqualValues(t, 2, entries[2].Height)
+
+	entries, total, err = idx.List(4, 1, 2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, total)
+	assert.Len(t, entries, 2)
+	assert.True(t, addrs[1].Equal(entries[0].Address))
+	assert.True(t, addrs[2].Equal(entries[1].Address))
+
+	entries, total, err = idx.List(1, 2, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Len(t, entries, 0)
+}
+
+type testSCOREStatus struct {
+	module.SCOREStatus
+	pending []byte
+}
+
+func (s *testSCOREStatus) PendingTxHash() []byte {
+	return s.pending
+}
+
+// testServiceManager has pending deployments of the result by address.
+type testServiceManager struct {
+	module.ServiceManager
+	pending map[string]map[string][]byte
+}
+
+func (sm *testServiceManager) GetSCOREStatus(result []byte, addr module.Address) (module.SCOREStatus, error) {
+	if tx, ok := sm.pending[string(result)][addr.String()]; ok {
+		return &testSCOREStatus{pending: tx}, nil
+	}
+	return nil, errors.NotFoundError.New("NoContract")
+}
+
+type testChain struct {
+	module.Chain
+	sm *testServiceManager
+}
+
+func (c *testChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func TestIndex_UpdatePending(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	sm := &testServiceManager{
+		pending: map[string]map[string][]byte{
+			"r1": {addr1.String(): []byte{1}, addr2.String(): nil},
+			"r2": {addr1.String(): []byte{1}, addr2.String(): []byte{2}},
+			"r3": {addr1.String(): nil, addr2.String(): []byte{3}},
+		},
+	}
+	idx := &Index{dbase: db.NewMapDB(), chain: &testChain{sm: sm}}
+
+	// addr2 is accepted on deployment
+	assert.NoError(t, idx.updatePending(1, []module.Address{addr1, addr2}, []byte("r1")))
+	pending, err := idx.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.True(t, addr1.Equal(pending[0].Address))
+	assert.EqualValues(t, 1, pending[0].Height)
+
+	assert.NoError(t, idx.updatePending(2, []module.Address{addr2}, []byte("r2")))
+	pending, err = idx.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	// addr1 is accepted, and addr2 is deployed again
+	assert.NoError(t, idx.updatePending(3, []module.Address{addr2}, []byte("r3")))
+	pending, err = idx.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.True(t, addr2.Equal(pending[0].Address))
+	assert.EqualValues(t, 3, pending[0].Height)
+}
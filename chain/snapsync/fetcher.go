@@ -0,0 +1,422 @@
+package snapsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configRequestTimeout = 10 * time.Second
+	configPeerWait       = time.Second
+	configLimitedWait    = 100 * time.Millisecond
+)
+
+type peerResponse struct {
+	id  module.PeerID
+	pi  module.ProtocolInfo
+	msg interface{}
+}
+
+// source is the snapshot to be fetched, and peers serving it.
+type source struct {
+	height   int64
+	manifest *backup.Manifest
+	raw      []byte
+	files    int
+	peers    []module.PeerID
+}
+
+func (s *source) remove(id module.PeerID) {
+	for i, p := range s.peers {
+		if p.Equal(id) {
+			s.peers = append(s.peers[:i], s.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Fetcher fetches the latest snapshot of the chain from the peers serving
+// it. Files are verified with checksums, and the database is verified with
+// the digest in the manifest.
+type Fetcher struct {
+	nm  module.NetworkManager
+	ph  module.ProtocolHandler
+	log log.Logger
+
+	mtx     sync.Mutex
+	lastID  uint32
+	pending map[uint32]chan<- *peerResponse
+}
+
+// NewFetcher returns the fetcher using the network manager, which is for
+// the network of the chain to be bootstrapped.
+func NewFetcher(nm module.NetworkManager, l log.Logger) (*Fetcher, error) {
+	f := &Fetcher{
+		nm:      nm,
+		log:     l.WithFields(log.Fields{log.FieldKeyModule: "snapsync"}),
+		pending: make(map[uint32]chan<- *peerResponse),
+	}
+	ph, err := nm.RegisterReactorForStreams("snapshot", module.ProtoSnapshot, f, protocols, configSnapshotPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		return nil, err
+	}
+	f.ph = ph
+	return f, nil
+}
+
+func (f *Fetcher) Term() {
+	if err := f.nm.UnregisterReactor(f); err != nil {
+		f.log.Warnf("fail to unregister reactor err=%+v", err)
+	}
+}
+
+// Fetch fetches the latest snapshot of the chain into the directory, and
+// returns the manifest of it. It waits for peers serving the snapshot until
+// the timeout. on is called with the number of fetched files and the total
+// number of files, and fetching stops if it returns an error.
+func (f *Fetcher) Fetch(dir string, cid int, timeout time.Duration, on func(done, total int) error) (*backup.Manifest, error) {
+	src, err := f.findSource(cid, timeout)
+	if err != nil {
+		return nil, err
+	}
+	f.log.Infof("fetch snapshot height=%d files=%d peers=%d",
+		src.height, src.files, len(src.peers))
+
+	files, err := f.listFiles(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := on(0, len(files)); err != nil {
+		return nil, err
+	}
+	for i, file := range files {
+		if err := f.fetchFile(src, dir, file); err != nil {
+			return nil, err
+		}
+		if err := on(i+1, len(files)); err != nil {
+			return nil, err
+		}
+	}
+	if err := ioutil.WriteFile(path.Join(dir, backup.ManifestFile), src.raw, 0644); err != nil {
+		return nil, err
+	}
+	if err := backup.VerifyManifest(dir, src.manifest, nil); err != nil {
+		return nil, err
+	}
+	return src.manifest, nil
+}
+
+// findSource returns the snapshot with the highest height among the
+// snapshots of the peers.
+func (f *Fetcher) findSource(cid int, timeout time.Duration) (*source, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var src *source
+		for _, peer := range f.ph.GetPeers() {
+			msg, err := f.request(peer, ProtoManifestRequest, ProtoManifestResponse,
+				func(id uint32) interface{} {
+					return &ManifestRequest{RequestID: id}
+				})
+			if err != nil {
+				f.log.Debugf("fail to get manifest peer=%v err=%+v", peer, err)
+				continue
+			}
+			resp := msg.(*ManifestResponse)
+			if resp.Status != StatusOK {
+				continue
+			}
+			mf, err := checkManifest(resp, cid)
+			if err != nil {
+				f.log.Infof("invalid manifest peer=%v err=%+v", peer, err)
+				continue
+			}
+			switch {
+			case src == nil || resp.Height > src.height:
+				src = &source{
+					height:   resp.Height,
+					manifest: mf,
+					raw:      resp.Manifest,
+					files:    int(resp.Files),
+					peers:    []module.PeerID{peer},
+				}
+			case resp.Height == src.height && bytes.Equal(resp.Manifest, src.raw) &&
+				int(resp.Files) == src.files:
+				src.peers = append(src.peers, peer)
+			}
+		}
+		if src != nil {
+			return src, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.TimeoutError.New("NoPeersWithSnapshot")
+		}
+		time.Sleep(configPeerWait)
+	}
+}
+
+func checkManifest(resp *ManifestResponse, cid int) (*backup.Manifest, error) {
+	mf := new(backup.Manifest)
+	if err := json.Unmarshal(resp.Manifest, mf); err != nil {
+		return nil, errors.InvalidStateError.Wrap(err, "InvalidManifest")
+	}
+	if int(mf.CID.Value) != cid {
+		return nil, errors.InvalidStateError.Errorf("InvalidCID(cid=%#x)", mf.CID.Value)
+	}
+	if mf.Codec != codec.BC.Name() {
+		return nil, errors.InvalidStateError.Errorf("IncompatibleCodec(codec=%s)", mf.Codec)
+	}
+	if mf.Height != resp.Height || resp.Files <= 0 {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidSnapshot(height=%d,files=%d)", resp.Height, resp.Files)
+	}
+	return mf, nil
+}
+
+// listFiles returns the files of the snapshot. Files in the manifest should
+// be in the list with the same sizes and checksums.
+func (f *Fetcher) listFiles(src *source) ([]*File, error) {
+	var files []*File
+	for len(files) < src.files {
+		if len(src.peers) == 0 {
+			return nil, errors.NotFoundError.New("NoPeersForFiles")
+		}
+		peer := src.peers[0]
+		msg, err := f.request(peer, ProtoFilesRequest, ProtoFilesResponse,
+			func(id uint32) interface{} {
+				return &FilesRequest{
+					RequestID: id,
+					Height:    src.height,
+					Start:     int32(len(files)),
+				}
+			})
+		if err == nil {
+			resp := msg.(*FilesResponse)
+			if resp.Status == StatusLimited {
+				time.Sleep(configLimitedWait)
+				continue
+			}
+			if resp.Status != StatusOK || len(resp.Files) == 0 ||
+				len(files)+len(resp.Files) > src.files {
+				err = errors.InvalidStateError.Errorf(
+					"InvalidFiles(status=%d,files=%d)", resp.Status, len(resp.Files))
+			} else {
+				files = append(files, resp.Files...)
+			}
+		}
+		if err != nil {
+			f.log.Infof("fail to list files peer=%v err=%+v", peer, err)
+			src.remove(peer)
+			files = nil
+		}
+	}
+	if err := checkFiles(files, src.manifest); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func checkFiles(files []*File, mf *backup.Manifest) error {
+	index := make(map[string]*File, len(files))
+	for _, file := range files {
+		if !validName(file.Name) || file.Size < 0 || len(file.Checksum) != 32 {
+			return errors.InvalidStateError.Errorf("InvalidFile(name=%s)", file.Name)
+		}
+		if _, ok := index[file.Name]; ok {
+			return errors.InvalidStateError.Errorf("DuplicateFile(name=%s)", file.Name)
+		}
+		index[file.Name] = file
+	}
+	for _, fi := range mf.Files {
+		file, ok := index[fi.Name]
+		if !ok || file.Size != fi.Size || !bytes.Equal(file.Checksum, fi.Checksum) {
+			return errors.InvalidStateError.Errorf("FileMismatch(name=%s)", fi.Name)
+		}
+	}
+	return nil
+}
+
+// validName returns true if the name is a relative path in the snapshot
+// directory.
+func validName(name string) bool {
+	if name == "" || name == backup.ManifestFile || path.IsAbs(name) ||
+		strings.Contains(name, "\\") || path.Clean(name) != name {
+		return false
+	}
+	return name != ".." && !strings.HasPrefix(name, "../")
+}
+
+// fetchFile fetches the file from one of the peers. If the file from the
+// peer is not valid, then the peer isn't used again.
+func (f *Fetcher) fetchFile(src *source, dir string, file *File) error {
+	target := path.Join(dir, file.Name)
+	if err := os.MkdirAll(path.Dir(target), 0700); err != nil {
+		return err
+	}
+	for {
+		if len(src.peers) == 0 {
+			return errors.NotFoundError.Errorf("NoPeersForFile(name=%s)", file.Name)
+		}
+		peer := src.peers[0]
+		err := f.fetchFileFrom(peer, src.height, target, file)
+		if err == nil {
+			return nil
+		}
+		if errors.CriticalIOError.Equals(err) {
+			return err
+		}
+		f.log.Infof("fail to fetch file peer=%v name=%s err=%+v", peer, file.Name, err)
+		src.remove(peer)
+	}
+}
+
+func (f *Fetcher) fetchFileFrom(peer module.PeerID, height int64, target string, file *File) error {
+	fd, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToCreateFile")
+	}
+	defer fd.Close()
+
+	h := sha3.New256()
+	var offset int64
+	for offset < file.Size {
+		msg, err := f.request(peer, ProtoChunkRequest, ProtoChunkResponse,
+			func(id uint32) interface{} {
+				return &ChunkRequest{
+					RequestID: id,
+					Height:    height,
+					Name:      file.Name,
+					Offset:    offset,
+				}
+			})
+		if err != nil {
+			return err
+		}
+		resp := msg.(*ChunkResponse)
+		if resp.Status == StatusLimited {
+			time.Sleep(configLimitedWait)
+			continue
+		}
+		if resp.Status != StatusOK || len(resp.Data) == 0 ||
+			offset+int64(len(resp.Data)) > file.Size {
+			return errors.InvalidStateError.Errorf(
+				"InvalidChunk(status=%d,len=%d)", resp.Status, len(resp.Data))
+		}
+		if err := writeChunk(fd, h, resp.Data); err != nil {
+			return err
+		}
+		offset += int64(len(resp.Data))
+	}
+	if !bytes.Equal(h.Sum(nil), file.Checksum) {
+		return errors.InvalidStateError.Errorf("ChecksumMismatch(name=%s)", file.Name)
+	}
+	if err := fd.Sync(); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToSyncFile")
+	}
+	return nil
+}
+
+func writeChunk(fd *os.File, h hash.Hash, data []byte) error {
+	if _, err := fd.Write(data); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToWriteFile")
+	}
+	h.Write(data)
+	return nil
+}
+
+func (f *Fetcher) request(
+	peer module.PeerID,
+	pi, rpi module.ProtocolInfo,
+	msg func(id uint32) interface{},
+) (interface{}, error) {
+	ch := make(chan *peerResponse, 1)
+
+	f.mtx.Lock()
+	f.lastID += 1
+	reqID := f.lastID
+	f.pending[reqID] = ch
+	f.mtx.Unlock()
+
+	defer func() {
+		f.mtx.Lock()
+		delete(f.pending, reqID)
+		f.mtx.Unlock()
+	}()
+
+	if err := f.ph.Unicast(pi, codec.MustMarshalToBytes(msg(reqID)), peer); err != nil {
+		return nil, err
+	}
+	after := time.After(configRequestTimeout)
+	for {
+		select {
+		case pr := <-ch:
+			if pr.id.Equal(peer) && pr.pi == rpi {
+				return pr.msg, nil
+			}
+		case <-after:
+			return nil, errors.TimeoutError.New("RequestTimeout")
+		}
+	}
+}
+
+func (f *Fetcher) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	var reqID uint32
+	var msg interface{}
+	switch pi {
+	case ProtoManifestResponse:
+		resp := new(ManifestResponse)
+		if _, err := codec.UnmarshalFromBytes(b, resp); err != nil {
+			return false, err
+		}
+		reqID, msg = resp.RequestID, resp
+	case ProtoFilesResponse:
+		resp := new(FilesResponse)
+		if _, err := codec.UnmarshalFromBytes(b, resp); err != nil {
+			return false, err
+		}
+		reqID, msg = resp.RequestID, resp
+	case ProtoChunkResponse:
+		resp := new(ChunkResponse)
+		if _, err := codec.UnmarshalFromBytes(b, resp); err != nil {
+			return false, err
+		}
+		reqID, msg = resp.RequestID, resp
+	default:
+		return false, nil
+	}
+	f.mtx.Lock()
+	ch, ok := f.pending[reqID]
+	f.mtx.Unlock()
+
+	if ok {
+		select {
+		case ch <- &peerResponse{id, pi, msg}:
+		default:
+		}
+	}
+	return false, nil
+}
+
+func (f *Fetcher) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
+	f.log.Debugf("OnFailure pi=%v err=%+v", pi, err)
+}
+
+func (f *Fetcher) OnJoin(id module.PeerID) {
+}
+
+func (f *Fetcher) OnLeave(id module.PeerID) {
+}
@@ -0,0 +1,84 @@
+package snapsync
+
+import (
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	ProtoManifestRequest module.ProtocolInfo = iota << 8
+	ProtoManifestResponse
+	ProtoFilesRequest
+	ProtoFilesResponse
+	ProtoChunkRequest
+	ProtoChunkResponse
+)
+
+var protocols = []module.ProtocolInfo{
+	ProtoManifestRequest,
+	ProtoManifestResponse,
+	ProtoFilesRequest,
+	ProtoFilesResponse,
+	ProtoChunkRequest,
+	ProtoChunkResponse,
+}
+
+// Status of responses
+const (
+	StatusOK int32 = iota
+	StatusNotFound
+	StatusInvalidRequest
+	StatusLimited
+	StatusPreparing
+	StatusFailure
+)
+
+// ManifestRequest requests the manifest of the latest snapshot of the peer.
+type ManifestRequest struct {
+	RequestID uint32
+}
+
+// ManifestResponse has the manifest (backup.Manifest in JSON) of the
+// snapshot at Height, and the number of files in the snapshot.
+type ManifestResponse struct {
+	RequestID uint32
+	Status    int32
+	Height    int64
+	Manifest  []byte
+	Files     int32
+}
+
+// File is a file in the snapshot. Name is the slash separated path
+// relative to the snapshot directory, and Checksum is SHA3-256 of it.
+type File struct {
+	Name     string
+	Size     int64
+	Checksum []byte
+}
+
+// FilesRequest requests files of the snapshot at Height from Start.
+type FilesRequest struct {
+	RequestID uint32
+	Height    int64
+	Start     int32
+}
+
+type FilesResponse struct {
+	RequestID uint32
+	Status    int32
+	Files     []*File
+}
+
+// ChunkRequest requests the chunk of the file of the snapshot at Height
+// from Offset.
+type ChunkRequest struct {
+	RequestID uint32
+	Height    int64
+	Name      string
+	Offset    int64
+}
+
+type ChunkResponse struct {
+	RequestID uint32
+	Status    int32
+	Data      []byte
+}
@@ -0,0 +1,379 @@
+package snapsync
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configSnapshotPriority = 6
+	configRequestRate      = rate.Limit(20)
+	configRequestBurst     = 20
+	configMaxConcurrency   = 4
+	configScanInterval     = time.Minute
+	ChunkSize              = 256 * 1024
+	FilesPerResponse       = 1000
+)
+
+// snapshot is the snapshot being served with the list of its files.
+type snapshot struct {
+	dir      string
+	height   int64
+	manifest []byte
+	files    []*File
+	index    map[string]*File
+}
+
+// Server serves the latest snapshot of the chain, which is taken by online
+// backup into the directory, to the peers bootstrapping the chain.
+type Server struct {
+	nm  module.NetworkManager
+	ph  module.ProtocolHandler
+	cid int
+	dir string
+	log log.Logger
+
+	mtx       sync.Mutex
+	limiters  map[string]*rate.Limiter
+	current   *snapshot
+	preparing bool
+	scanned   time.Time
+	stop      chan struct{}
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+// NewServer returns the server of snapshots of the chain in the directory.
+// Snapshots of other chains in the directory are ignored.
+func NewServer(c module.Chain, dir string) (*Server, error) {
+	s := &Server{
+		nm:       c.NetworkManager(),
+		cid:      c.CID(),
+		dir:      dir,
+		log:      c.Logger().WithFields(log.Fields{log.FieldKeyModule: "snapsync"}),
+		limiters: make(map[string]*rate.Limiter),
+		stop:     make(chan struct{}),
+		sem:      make(chan struct{}, configMaxConcurrency),
+	}
+	ph, err := s.nm.RegisterReactorForStreams("snapshot", module.ProtoSnapshot, s, protocols, configSnapshotPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		return nil, err
+	}
+	s.ph = ph
+	return s, nil
+}
+
+// Term unregisters the server and waits for requests being served.
+func (s *Server) Term() {
+	if err := s.nm.UnregisterReactor(s); err != nil {
+		s.log.Warnf("fail to unregister reactor err=%+v", err)
+	}
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// findLatest returns the directory and the manifest of the snapshot of
+// the chain with the highest height in the base directory.
+func findLatest(base string, cid int) (string, *backup.Manifest, []byte) {
+	fis, err := ioutil.ReadDir(base)
+	if err != nil {
+		return "", nil, nil
+	}
+	var dir string
+	var mf *backup.Manifest
+	var raw []byte
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		p := path.Join(base, fi.Name())
+		bs, err := ioutil.ReadFile(path.Join(p, backup.ManifestFile))
+		if err != nil {
+			continue
+		}
+		m := new(backup.Manifest)
+		if err := json.Unmarshal(bs, m); err != nil {
+			continue
+		}
+		if int(m.CID.Value) != cid || m.Codec != codec.BC.Name() {
+			continue
+		}
+		if mf == nil || m.Height > mf.Height {
+			dir, mf, raw = p, m, bs
+		}
+	}
+	return dir, mf, raw
+}
+
+// snapshot returns the snapshot being served. It looks for a newer
+// snapshot periodically, and it's served after the list of its files is
+// prepared in background.
+func (s *Server) snapshot() (*snapshot, int32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.preparing && time.Since(s.scanned) > configScanInterval {
+		s.scanned = time.Now()
+		dir, mf, raw := findLatest(s.dir, s.cid)
+		if mf != nil && (s.current == nil ||
+			s.current.dir != dir || s.current.height != mf.Height) {
+			s.preparing = true
+			s.wg.Add(1)
+			go s.prepare(dir, mf.Height, raw)
+		}
+	}
+	if s.current == nil {
+		if s.preparing {
+			return nil, StatusPreparing
+		}
+		return nil, StatusNotFound
+	}
+	return s.current, StatusOK
+}
+
+func (s *Server) prepare(dir string, height int64, raw []byte) {
+	defer s.wg.Done()
+
+	ss, err := s.listFiles(dir, height, raw)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.preparing = false
+	if err != nil {
+		if !errors.InterruptedError.Equals(err) {
+			s.log.Warnf("fail to prepare snapshot dir=%s err=%+v", dir, err)
+		}
+		return
+	}
+	s.log.Infof("serve snapshot dir=%s height=%d files=%d", dir, height, len(ss.files))
+	s.current = ss
+}
+
+func (s *Server) listFiles(dir string, height int64, raw []byte) (*snapshot, error) {
+	ss := &snapshot{
+		dir:      dir,
+		height:   height,
+		manifest: raw,
+		index:    make(map[string]*File),
+	}
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.Mode().IsRegular() {
+			return err
+		}
+		select {
+		case <-s.stop:
+			return errors.ErrInterrupted
+		default:
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if name == backup.ManifestFile {
+			return nil
+		}
+		size, sum, err := checksumOf(p)
+		if err != nil {
+			return err
+		}
+		f := &File{Name: name, Size: size, Checksum: sum}
+		ss.files = append(ss.files, f)
+		ss.index[name] = f
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func checksumOf(p string) (int64, []byte, error) {
+	fd, err := os.Open(p)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer fd.Close()
+
+	h := sha3.New256()
+	size, err := io.Copy(h, fd)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, h.Sum(nil), nil
+}
+
+func (s *Server) limiterOf(id module.PeerID) *rate.Limiter {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	l, ok := s.limiters[id.String()]
+	if !ok {
+		l = rate.NewLimiter(configRequestRate, configRequestBurst)
+		s.limiters[id.String()] = l
+	}
+	return l
+}
+
+// acquire returns true if the request of the peer can be served now.
+func (s *Server) acquire(id module.PeerID) bool {
+	if !s.limiterOf(id).Allow() {
+		return false
+	}
+	select {
+	case s.sem <- struct{}{}:
+		s.wg.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Server) release() {
+	<-s.sem
+	s.wg.Done()
+}
+
+func (s *Server) send(pi module.ProtocolInfo, resp interface{}, id module.PeerID) {
+	if err := s.ph.Unicast(pi, codec.MustMarshalToBytes(resp), id); err != nil {
+		s.log.Debugf("fail to send response peer=%v err=%+v", id, err)
+	}
+}
+
+func (s *Server) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	switch pi {
+	case ProtoManifestRequest:
+		var msg ManifestRequest
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		s.send(ProtoManifestResponse, s.serveManifest(&msg), id)
+	case ProtoFilesRequest:
+		var msg FilesRequest
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		s.send(ProtoFilesResponse, s.serveFiles(&msg), id)
+	case ProtoChunkRequest:
+		var msg ChunkRequest
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return false, err
+		}
+		if !s.acquire(id) {
+			s.send(ProtoChunkResponse, &ChunkResponse{
+				RequestID: msg.RequestID,
+				Status:    StatusLimited,
+			}, id)
+			return false, nil
+		}
+		go func() {
+			defer s.release()
+			s.send(ProtoChunkResponse, s.serveChunk(&msg), id)
+		}()
+	}
+	return false, nil
+}
+
+func (s *Server) serveManifest(req *ManifestRequest) *ManifestResponse {
+	resp := &ManifestResponse{RequestID: req.RequestID}
+	ss, status := s.snapshot()
+	if ss == nil {
+		resp.Status = status
+		return resp
+	}
+	resp.Height = ss.height
+	resp.Manifest = ss.manifest
+	resp.Files = int32(len(ss.files))
+	return resp
+}
+
+func (s *Server) serveFiles(req *FilesRequest) *FilesResponse {
+	resp := &FilesResponse{RequestID: req.RequestID}
+	ss, status := s.snapshot()
+	if ss == nil {
+		resp.Status = status
+		return resp
+	}
+	if ss.height != req.Height {
+		resp.Status = StatusNotFound
+		return resp
+	}
+	if req.Start < 0 || int(req.Start) > len(ss.files) {
+		resp.Status = StatusInvalidRequest
+		return resp
+	}
+	end := int(req.Start) + FilesPerResponse
+	if end > len(ss.files) {
+		end = len(ss.files)
+	}
+	resp.Files = ss.files[req.Start:end]
+	return resp
+}
+
+func (s *Server) serveChunk(req *ChunkRequest) *ChunkResponse {
+	resp := &ChunkResponse{RequestID: req.RequestID}
+	ss, status := s.snapshot()
+	if ss == nil {
+		resp.Status = status
+		return resp
+	}
+	if ss.height != req.Height {
+		resp.Status = StatusNotFound
+		return resp
+	}
+	f, ok := ss.index[req.Name]
+	if !ok || req.Offset < 0 || req.Offset >= f.Size {
+		resp.Status = StatusInvalidRequest
+		return resp
+	}
+	fd, err := os.Open(path.Join(ss.dir, f.Name))
+	if err != nil {
+		s.log.Warnf("fail to open snapshot file=%s err=%+v", f.Name, err)
+		resp.Status = StatusNotFound
+		return resp
+	}
+	defer fd.Close()
+
+	size := f.Size - req.Offset
+	if size > ChunkSize {
+		size = ChunkSize
+	}
+	data := make([]byte, size)
+	if _, err := fd.ReadAt(data, req.Offset); err != nil && err != io.EOF {
+		s.log.Warnf("fail to read snapshot file=%s err=%+v", f.Name, err)
+		resp.Status = StatusFailure
+		return resp
+	}
+	resp.Data = data
+	return resp
+}
+
+func (s *Server) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
+	s.log.Debugf("OnFailure pi=%v err=%+v", pi, err)
+}
+
+func (s *Server) OnJoin(id module.PeerID) {
+}
+
+func (s *Server) OnLeave(id module.PeerID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.limiters, id.String())
+}
@@ -0,0 +1,182 @@
+package snapsync
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+type testChain struct {
+	module.Chain
+	cid int
+	nm  module.NetworkManager
+	log log.Logger
+}
+
+func (c *testChain) CID() int {
+	return c.cid
+}
+
+func (c *testChain) NetworkManager() module.NetworkManager {
+	return c.nm
+}
+
+func (c *testChain) Logger() log.Logger {
+	return c.log
+}
+
+type testBarrier struct{}
+
+func (b testBarrier) Barrier(f func() error) error {
+	return f()
+}
+
+func writeFile(t *testing.T, p string, data []byte) {
+	assert.NoError(t, os.MkdirAll(path.Dir(p), 0700))
+	assert.NoError(t, ioutil.WriteFile(p, data, 0644))
+}
+
+// takeSnapshot takes a snapshot of the chain in a temporary directory into
+// the directory.
+func takeSnapshot(t *testing.T, dir string, cid int, height int64) {
+	base := t.TempDir()
+	writeFile(t, path.Join(base, "wal", "commit", "0000.log"), []byte("wal"))
+	writeFile(t, path.Join(base, "contract", "0x01", "code"),
+		make([]byte, ChunkSize*2+10))
+
+	database, err := db.NewGoLevelDB("1", path.Join(base, "db"))
+	assert.NoError(t, err)
+	defer database.Close()
+	bk, _ := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, bk.Set([]byte("key"), []byte("value")))
+
+	o := backup.New(log.New())
+	assert.NoError(t, o.Start(&backup.Source{
+		NID:      1,
+		CID:      cid,
+		Channel:  "test",
+		DBType:   string(db.GoLevelDBBackend),
+		DBName:   "1",
+		DBDir:    "db",
+		Database: database,
+		Barrier:  testBarrier{},
+		Height:   func() int64 { return height },
+		BaseDir:  base,
+		Synced:   []string{"wal"},
+		Files:    []string{"contract"},
+	}, dir))
+	for i := 0; i < 100 && o.Status().State == backup.StateRunning; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, backup.StateDone, o.Status().State)
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	snapshots := t.TempDir()
+	takeSnapshot(t, path.Join(snapshots, "old"), 2, 10)
+	takeSnapshot(t, path.Join(snapshots, "new"), 2, 20)
+	takeSnapshot(t, path.Join(snapshots, "other"), 3, 30)
+
+	snm := test.NewNetworkManager(t, wallet.New().Address())
+	s, err := NewServer(&testChain{cid: 2, nm: snm, log: log.New()}, snapshots)
+	assert.NoError(t, err)
+	defer s.Term()
+
+	fnm := test.NewNetworkManager(t, wallet.New().Address())
+	f, err := NewFetcher(fnm, log.New())
+	assert.NoError(t, err)
+	defer f.Term()
+
+	dir := t.TempDir()
+	_, err = f.Fetch(dir, 2, time.Millisecond, func(done, total int) error {
+		return nil
+	})
+	assert.Error(t, err)
+
+	snm.Connect(fnm)
+	var done, total int
+	mf, err := f.Fetch(dir, 2, 5*time.Second, func(d, t int) error {
+		done, total = d, t
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 20, mf.Height)
+	assert.True(t, total > 2)
+	assert.Equal(t, total, done)
+
+	code, err := ioutil.ReadFile(path.Join(dir, "contract", "0x01", "code"))
+	assert.NoError(t, err)
+	assert.Len(t, code, ChunkSize*2+10)
+}
+
+func TestServer_InvalidRequest(t *testing.T) {
+	snapshots := t.TempDir()
+	takeSnapshot(t, path.Join(snapshots, "snap"), 2, 10)
+
+	nm := test.NewNetworkManager(t, wallet.New().Address())
+	s, err := NewServer(&testChain{cid: 2, nm: nm, log: log.New()}, snapshots)
+	assert.NoError(t, err)
+	defer s.Term()
+
+	_, h := nm.NewPeerFor(module.ProtoSnapshot)
+	var mresp ManifestResponse
+	for i := 0; i < 100; i++ {
+		h.Unicast(ProtoManifestRequest, &ManifestRequest{RequestID: 1}, nil)
+		h.Receive(ProtoManifestResponse, nil, &mresp)
+		if mresp.Status != StatusPreparing {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, StatusOK, mresp.Status)
+	assert.EqualValues(t, 10, mresp.Height)
+
+	var resp ChunkResponse
+	h.Unicast(ProtoChunkRequest, &ChunkRequest{
+		RequestID: 2,
+		Height:    10,
+		Name:      "../snap/" + backup.ManifestFile,
+	}, nil)
+	h.Receive(ProtoChunkResponse, nil, &resp)
+	assert.Equal(t, StatusInvalidRequest, resp.Status)
+
+	h.Unicast(ProtoChunkRequest, &ChunkRequest{
+		RequestID: 3,
+		Height:    9,
+		Name:      "wal/commit/0000.log",
+	}, nil)
+	h.Receive(ProtoChunkResponse, nil, &resp)
+	assert.Equal(t, StatusNotFound, resp.Status)
+
+	h.Unicast(ProtoChunkRequest, &ChunkRequest{
+		RequestID: 4,
+		Height:    10,
+		Name:      "wal/commit/0000.log",
+		Offset:    1,
+	}, nil)
+	h.Receive(ProtoChunkResponse, nil, &resp)
+	assert.Equal(t, StatusOK, resp.Status)
+	assert.Equal(t, []byte("al"), resp.Data)
+}
+
+func TestValidName(t *testing.T) {
+	assert.True(t, validName("db/1/000001.log"))
+	assert.False(t, validName(""))
+	assert.False(t, validName(backup.ManifestFile))
+	assert.False(t, validName("/etc/passwd"))
+	assert.False(t, validName("../x"))
+	assert.False(t, validName(".."))
+	assert.False(t, validName("a/../../x"))
+	assert.False(t, validName("a\\b"))
+}
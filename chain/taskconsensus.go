@@ -17,12 +17,17 @@
 package chain
 
 import (
+	"fmt"
+
 	"github.com/icon-project/goloop/common/errors"
 )
 
 type taskConsensus struct {
 	chain  *singleChain
 	result resultStore
+
+	// height of the last block if it's halted by schedule
+	halted int64
 }
 
 var consensusStates = map[State]string{
@@ -37,6 +42,9 @@ func (t *taskConsensus) String() string {
 }
 
 func (t *taskConsensus) DetailOf(s State) string {
+	if s == Finished && t.halted > 0 {
+		return fmt.Sprintf("halted at %d", t.halted)
+	}
 	if name, ok := consensusStates[s]; ok {
 		return name
 	} else {
@@ -47,11 +55,13 @@ func (t *taskConsensus) DetailOf(s State) string {
 func (t *taskConsensus) Start() error {
 	if err := t.chain.prepareManagers(); err != nil {
 		t.result.SetValue(err)
+		t.chain.handleStartFault(err)
 		return err
 	}
 	if err := t._start(t.chain); err != nil {
 		t.chain.releaseManagers()
 		t.result.SetValue(err)
+		t.chain.handleStartFault(err)
 		return err
 	}
 	return nil
@@ -59,14 +69,34 @@ func (t *taskConsensus) Start() error {
 
 func (t *taskConsensus) _start(c *singleChain) error {
 	c.sm.Start()
+	c.checkRevision()
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
+	c.applySyncServing()
+	if err := c.startExporters(); err != nil {
+		return err
+	}
+	if err := c.startEndpointWatcher(); err != nil {
+		return err
+	}
+	if err := c.startLightServer(); err != nil {
+		return err
+	}
+	if err := c.startGenesisServer(); err != nil {
+		return err
+	}
+	if err := c.startSnapshotServer(); err != nil {
+		return err
+	}
+	c.startSCOREIndex()
+	c.startEventIndex()
+	c.startAddressIndex()
 	c.srv.SetChain(c.cfg.Channel, c)
 	if err := c.nm.Start(); err != nil {
 		return err
 	}
-	return nil
+	return c.watchHalt(t)
 }
 
 func (t *taskConsensus) Stop() {
@@ -75,6 +105,15 @@ func (t *taskConsensus) Stop() {
 	t.result.SetValue(errors.ErrInterrupted)
 }
 
+// halt stops the consensus after the block at the height is finalized,
+// then the task is finished.
+func (t *taskConsensus) halt(height int64) {
+	t.halted = height
+	t.chain.srv.RemoveChain(t.chain.cfg.Channel)
+	t.chain.releaseManagers()
+	t.result.SetValue(nil)
+}
+
 func (t *taskConsensus) Wait() error {
 	return t.result.Wait()
 }
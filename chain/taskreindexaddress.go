@@ -0,0 +1,131 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/addressindex"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	ReindexAddressTask = "reindex_address"
+)
+
+var reindexAddressStates = map[State]string{
+	Starting: "reindex_address starting",
+	Stopping: "reindex_address stopping",
+	Failed:   "reindex_address failed",
+	Finished: "reindex_address done",
+}
+
+type reindexAddressParams struct {
+	From int64 `json:"from"`
+}
+
+// taskReindexAddress rebuilds the address index from the height with the
+// chain stopped. It fills the index of blocks before the address index is
+// enabled, and updates references added by old versions.
+type taskReindexAddress struct {
+	chain  *singleChain
+	params *reindexAddressParams
+	result resultStore
+
+	current int64
+	last    int64
+	stopped int32
+}
+
+func (t *taskReindexAddress) String() string {
+	return fmt.Sprintf("ReindexAddress(from=%d)", t.params.From)
+}
+
+func (t *taskReindexAddress) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("%s %d/%d", ReindexAddressTask,
+			atomic.LoadInt64(&t.current), atomic.LoadInt64(&t.last))
+	default:
+		if st, ok := reindexAddressStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskReindexAddress) Start() error {
+	if t.params.From < 0 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(from=%d)", t.params.From)
+	}
+	if err := t.chain.prepareManagers(); err != nil {
+		return err
+	}
+	blk, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	atomic.StoreInt64(&t.current, t.params.From-1)
+	atomic.StoreInt64(&t.last, blk.Height()-1)
+	go t.doReindex()
+	return nil
+}
+
+func (t *taskReindexAddress) doReindex() {
+	defer t.chain.releaseManagers()
+	idx := addressindex.New(t.chain)
+	err := idx.Reindex(t.params.From, t.onIndex)
+	t.result.SetValue(err)
+}
+
+func (t *taskReindexAddress) onIndex(height int64) error {
+	if atomic.LoadInt32(&t.stopped) != 0 {
+		return errors.ErrInterrupted
+	}
+	atomic.StoreInt64(&t.current, height)
+	return nil
+}
+
+func (t *taskReindexAddress) Stop() {
+	atomic.StoreInt32(&t.stopped, 1)
+}
+
+func (t *taskReindexAddress) Wait() error {
+	return t.result.Wait()
+}
+
+func taskReindexAddressFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(reindexAddressParams)
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, p); err != nil {
+			return nil, err
+		}
+	}
+	return &taskReindexAddress{
+		chain:  c,
+		params: p,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(ReindexAddressTask, taskReindexAddressFactory)
+}
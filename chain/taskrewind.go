@@ -0,0 +1,189 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+)
+
+// RewindManager rewinds the local chain to the finalized block without
+// fetching blocks from the peers. Blocks above the height are dropped, and
+// the chain syncs them again after it's started.
+type RewindManager interface {
+	// PlanRewind returns the plan to rewind to the height. BlockHash of the
+	// plan is used to confirm Rewind.
+	PlanRewind(height int64) (*RewindPlan, error)
+	Rewind(height int64, blockHash []byte) error
+}
+
+type RewindPlan struct {
+	Height    int64           `json:"height"`
+	Target    int64           `json:"target"`
+	BlockHash common.HexBytes `json:"blockHash"`
+}
+
+type taskRewind struct {
+	chain     *singleChain
+	result    resultStore
+	height    int64
+	blockHash []byte
+}
+
+var rewindStates = map[State]string{
+	Starting: "rewind starting",
+	Started:  "rewind started",
+	Stopping: "rewind stopping",
+	Failed:   "rewind failed",
+	Finished: "rewind done",
+}
+
+func (t *taskRewind) String() string {
+	return fmt.Sprintf("Rewind(height=%d,blockHash=%#x)", t.height, t.blockHash)
+}
+
+func (t *taskRewind) DetailOf(s State) string {
+	if name, ok := rewindStates[s]; ok {
+		return name
+	} else {
+		return s.String()
+	}
+}
+
+func (t *taskRewind) Start() error {
+	plan, err := t.chain.PlanRewind(t.height)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(plan.BlockHash, t.blockHash) {
+		return errors.IllegalArgumentError.Errorf(
+			"BlockHashMismatch(height=%d,exp=%#x,real=%#x)",
+			t.height, plan.BlockHash, t.blockHash)
+	}
+	t.chain.audit("rewind", plan, AuditStarted, nil)
+	go t.doRewind(plan)
+	return nil
+}
+
+func (t *taskRewind) doRewind(plan *RewindPlan) {
+	err := t._rewind()
+	if err != nil {
+		t.chain.audit("rewind", plan, AuditFailed, err)
+	} else {
+		t.chain.audit("rewind", plan, AuditDone, nil)
+	}
+	t.result.SetValue(err)
+}
+
+func (t *taskRewind) _rewind() error {
+	c := t.chain
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	dbase := c.database
+	cvlBytes, err := block.GetCommitVoteListBytesForHeight(dbase, nil, t.height)
+	if err != nil {
+		return err
+	}
+	result, err := block.GetBlockResultByHeight(dbase, nil, t.height)
+	if err != nil {
+		return err
+	}
+	bd, err := block.GetBTPDigestFromResult(dbase, nil, result)
+	if err != nil {
+		return err
+	}
+	vl, err := block.GetNextValidatorsByHeight(dbase, nil, t.height)
+	if err != nil {
+		return err
+	}
+	vlmBytes, err := consensus.WALRecordBytesFromCommitVoteListBytes(
+		cvlBytes, t.height, t.blockHash, result, vl, bd, dbase, codec.BC,
+	)
+	if err != nil {
+		return err
+	}
+	if err := block.ResetDB(dbase, nil, t.height); err != nil {
+		return err
+	}
+	walDir := path.Join(c.cfg.AbsBaseDir(), DefaultWALDir)
+	return consensus.ResetWAL(t.height, walDir, vlmBytes)
+}
+
+func (t *taskRewind) Stop() {
+	// rewinding is short, so it's not interrupted.
+}
+
+func (t *taskRewind) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskRewind(chain *singleChain, height int64, blockHash []byte) chainTask {
+	return &taskRewind{
+		chain:     chain,
+		height:    height,
+		blockHash: blockHash,
+	}
+}
+
+func (c *singleChain) PlanRewind(height int64) (*RewindPlan, error) {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.database == nil {
+		return nil, errors.InvalidStateError.New("NoDatabase")
+	}
+	last := block.GetLastHeightOf(c.database)
+	if height >= last {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,last=%d)", height, last)
+	}
+	if base := c.GenesisStorage().Height(); height <= base {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,genesis=%d)", height, base)
+	}
+	ver, err := block.GetBlockVersion(c.database, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	if ver <= module.BlockVersion1 {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedBlockVersion(height=%d,version=%d)", height, ver)
+	}
+	hash, err := block.GetBlockHeaderHashByHeight(c.database, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	return &RewindPlan{
+		Height:    last,
+		Target:    height,
+		BlockHash: hash,
+	}, nil
+}
+
+func (c *singleChain) Rewind(height int64, blockHash []byte) error {
+	task := newTaskRewind(c, height, blockHash)
+	return c._runTask(task, false)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/test"
+)
+
+func newTestRewindChain(t *testing.T, blocks int) (*singleChain, *test.Node) {
+	dbase := db.NewMapDB()
+	nd := test.NewNode(t, test.UseDB(dbase))
+	for i := 0; i < blocks; i++ {
+		nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	}
+	c := &singleChain{
+		database: dbase,
+		cfg: Config{
+			BaseDir:        t.TempDir(),
+			GenesisStorage: nd.Chain.GenesisStorage(),
+		},
+		logger: log.New(),
+	}
+	return c, nd
+}
+
+func TestSingleChain_PlanRewind(t *testing.T) {
+	c, nd := newTestRewindChain(t, 3)
+	defer nd.Close()
+
+	plan, err := c.PlanRewind(2)
+	assert.NoError(t, err)
+	hash, err := block.GetBlockHeaderHashByHeight(c.database, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &RewindPlan{Height: 3, Target: 2, BlockHash: hash}, plan)
+
+	// height shall be lower than the last
+	_, err = c.PlanRewind(3)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = c.PlanRewind(4)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// height shall be higher than the genesis
+	_, err = c.PlanRewind(0)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = c.PlanRewind(-1)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
+
+func TestTaskRewind_BlockHashMismatch(t *testing.T) {
+	c, nd := newTestRewindChain(t, 3)
+	defer nd.Close()
+
+	plan, err := c.PlanRewind(1)
+	assert.NoError(t, err)
+
+	task := newTaskRewind(c, 2, plan.BlockHash)
+	err = task.Start()
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Equal(t, int64(3), block.GetLastHeightOf(c.database))
+}
+
+func TestTaskRewind_Rewind(t *testing.T) {
+	c, nd := newTestRewindChain(t, 3)
+	plan, err := c.PlanRewind(1)
+	assert.NoError(t, err)
+	target, err := nd.BM.GetBlockByHeight(1)
+	assert.NoError(t, err)
+	nd.Close()
+
+	task := newTaskRewind(c, plan.Target, plan.BlockHash)
+	assert.NoError(t, task.Start())
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, int64(1), block.GetLastHeightOf(c.database))
+	_, err = os.Stat(path.Join(c.cfg.AbsBaseDir(), DefaultWALDir))
+	assert.NoError(t, err)
+
+	// restarted chain continues from the target
+	nd2 := test.NewNode(t, test.UseDB(c.database))
+	defer nd2.Close()
+	blk := nd2.GetLastBlock()
+	assert.Equal(t, int64(1), blk.Height())
+	assert.Equal(t, target.ID(), blk.ID())
+	_, err = nd2.BM.GetBlockByHeight(2)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/blockv0/lcstore"
+	"github.com/icon-project/goloop/icon/lcimporter"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	VerifyICONTask = "verify_icon"
+)
+
+var verifyICONStates = map[State]string{
+	Starting: "verify_icon starting",
+	Stopping: "verify_icon stopping",
+	Failed:   "verify_icon failed",
+	Finished: "verify_icon done",
+}
+
+type verifyICONParams struct {
+	StoreURI    string               `json:"store_uri"`
+	MaxRPS      int                  `json:"max_rps"`
+	CacheConfig *lcstore.CacheConfig `json:"cache_config,omitempty"`
+	From        int64                `json:"from"`
+	To          int64                `json:"to"`
+}
+
+type taskVerifyICON struct {
+	chain  *singleChain
+	params *verifyICONParams
+	result resultStore
+
+	current int64
+	stopped int32
+}
+
+func (t *taskVerifyICON) String() string {
+	return fmt.Sprintf("VerifyICON(from=%d,to=%d)", t.params.From, t.params.To)
+}
+
+func (t *taskVerifyICON) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("%s %d/%d", VerifyICONTask,
+			atomic.LoadInt64(&t.current), t.params.To)
+	default:
+		if st, ok := verifyICONStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskVerifyICON) Start() error {
+	if t.params.From < 0 || t.params.To < t.params.From {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d)", t.params.From, t.params.To)
+	}
+	store, err := lcstore.OpenStore(t.params.StoreURI, t.params.MaxRPS)
+	if err != nil {
+		return err
+	}
+	cacheConfig := t.params.CacheConfig
+	if cacheConfig == nil {
+		cacheConfig = &lcStoreDefaultCacheConfig
+	}
+	cs := lcstore.NewForwardCache(store, t.chain.logger, cacheConfig)
+	cs.SetReceiptParameter(t.chain.Database(), module.LatestRevision)
+	v, err := lcimporter.NewVerifier(t.chain.Database(), cs)
+	if err != nil {
+		store.Close()
+		return err
+	}
+	atomic.StoreInt64(&t.current, t.params.From-1)
+	go t.doVerify(v, store)
+	return nil
+}
+
+func (t *taskVerifyICON) doVerify(v *lcimporter.Verifier, store *lcstore.Store) {
+	defer store.Close()
+	err := v.Verify(t.params.From, t.params.To, t.onVerify)
+	t.result.SetValue(err)
+}
+
+func (t *taskVerifyICON) onVerify(height int64) error {
+	if atomic.LoadInt32(&t.stopped) != 0 {
+		return errors.ErrInterrupted
+	}
+	atomic.StoreInt64(&t.current, height)
+	return nil
+}
+
+func (t *taskVerifyICON) Stop() {
+	atomic.StoreInt32(&t.stopped, 1)
+}
+
+func (t *taskVerifyICON) Wait() error {
+	return t.result.Wait()
+}
+
+func taskVerifyIconFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(verifyICONParams)
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, err
+	}
+	return &taskVerifyICON{
+		chain:  c,
+		params: p,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(VerifyICONTask, taskVerifyIconFactory)
+}
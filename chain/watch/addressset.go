@@ -0,0 +1,174 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"bufio"
+	"hash/fnv"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	bitsPerAddress = 10
+	hashCount      = 7
+)
+
+// AddressSet is a set of addresses for large watch lists. Most of values
+// are not in the set, so a bloom filter is checked before the exact index.
+type AddressSet struct {
+	bits  []uint64
+	nbits uint64
+	index map[string]module.Address
+}
+
+func hashOf(bs []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(bs)
+	v := h.Sum64()
+	return v & math.MaxUint32, v>>32 | 1
+}
+
+func (s *AddressSet) add(bs []byte) {
+	h1, h2 := hashOf(bs)
+	for i := uint64(0); i < hashCount; i++ {
+		b := (h1 + i*h2) % s.nbits
+		s.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+func (s *AddressSet) mayContain(bs []byte) bool {
+	h1, h2 := hashOf(bs)
+	for i := uint64(0); i < hashCount; i++ {
+		b := (h1 + i*h2) % s.nbits
+		if s.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsBytes returns whether bs is the bytes of an address in the set.
+func (s *AddressSet) ContainsBytes(bs []byte) bool {
+	if len(bs) != common.AddressBytes || !s.mayContain(bs) {
+		return false
+	}
+	_, ok := s.index[string(bs)]
+	return ok
+}
+
+func (s *AddressSet) Contains(addr module.Address) bool {
+	if addr == nil {
+		return false
+	}
+	return s.ContainsBytes(addr.Bytes())
+}
+
+func (s *AddressSet) Len() int {
+	return len(s.index)
+}
+
+// Related returns addresses in the set related to the transaction.
+// The transaction is related to the address if it's the sender or the
+// receiver of the transaction, or it's the SCORE or a parameter of an event.
+func (s *AddressSet) Related(tx module.Transaction, r module.Receipt) ([]module.Address, error) {
+	var related []module.Address
+	found := make(map[string]bool)
+	add := func(bs []byte) {
+		if s.ContainsBytes(bs) && !found[string(bs)] {
+			found[string(bs)] = true
+			related = append(related, s.index[string(bs)])
+		}
+	}
+	if from := tx.From(); from != nil {
+		add(from.Bytes())
+	}
+	if to := r.To(); to != nil {
+		add(to.Bytes())
+	}
+	for itr := r.EventLogIterator(); itr.Has(); itr.Next() {
+		ev, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		add(ev.Address().Bytes())
+		for _, bs := range ev.Indexed() {
+			add(bs)
+		}
+		for _, bs := range ev.Data() {
+			add(bs)
+		}
+	}
+	return related, nil
+}
+
+func NewAddressSet(addrs []module.Address) *AddressSet {
+	nbits := uint64(len(addrs)*bitsPerAddress+63) / 64 * 64
+	if nbits == 0 {
+		nbits = 64
+	}
+	s := &AddressSet{
+		bits:  make([]uint64, nbits/64),
+		nbits: nbits,
+		index: make(map[string]module.Address, len(addrs)),
+	}
+	for _, addr := range addrs {
+		bs := addr.Bytes()
+		s.index[string(bs)] = addr
+		s.add(bs)
+	}
+	return s
+}
+
+// LoadAddressFile reads addresses in the file. Each line has an address,
+// and empty lines or lines starting with "#" are ignored.
+func LoadAddressFile(name string) ([]module.Address, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addrs []module.Address
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		addr, err := common.NewAddressFromString(s)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidAddress(file=%s,line=%d)", name, line)
+		}
+		addrs = append(addrs, addr)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// Provider provides watch lists configured for the chain.
+type Provider interface {
+	WatchList(name string) (*AddressSet, error)
+}
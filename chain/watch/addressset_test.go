@@ -0,0 +1,67 @@
+package watch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+)
+
+func addressOf(i int, contract bool) module.Address {
+	id := make([]byte, common.AddressIDBytes)
+	binary.BigEndian.PutUint64(id[common.AddressIDBytes-8:], uint64(i))
+	return common.NewAddressWithTypeAndID(contract, id)
+}
+
+func TestAddressSet_Contains(t *testing.T) {
+	var addrs []module.Address
+	for i := 0; i < 100000; i++ {
+		addrs = append(addrs, addressOf(i, false))
+	}
+	s := NewAddressSet(addrs)
+	assert.Equal(t, len(addrs), s.Len())
+
+	for _, addr := range addrs {
+		assert.True(t, s.Contains(addr))
+		assert.True(t, s.ContainsBytes(addr.Bytes()))
+	}
+	for i := 0; i < 100000; i++ {
+		assert.False(t, s.Contains(addressOf(i, true)))
+		assert.False(t, s.Contains(addressOf(i+100000, false)))
+	}
+	assert.False(t, s.Contains(nil))
+	assert.False(t, s.ContainsBytes([]byte("Transfer(Address,Address,int)")))
+}
+
+func TestAddressSet_Empty(t *testing.T) {
+	s := NewAddressSet(nil)
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Contains(addressOf(1, false)))
+}
+
+func TestLoadAddressFile(t *testing.T) {
+	a1 := addressOf(1, false)
+	a2 := addressOf(2, true)
+	name := path.Join(t.TempDir(), "addresses.txt")
+	content := fmt.Sprintf("# deposit addresses\n%s\n\n  %s  \n", a1, a2)
+	assert.NoError(t, os.WriteFile(name, []byte(content), 0644))
+
+	addrs, err := LoadAddressFile(name)
+	assert.NoError(t, err)
+	assert.Len(t, addrs, 2)
+	assert.True(t, a1.Equal(addrs[0]))
+	assert.True(t, a2.Equal(addrs[1]))
+
+	assert.NoError(t, os.WriteFile(name, []byte("address\n"), 0644))
+	_, err = LoadAddressFile(name)
+	assert.Error(t, err)
+
+	_, err = LoadAddressFile(path.Join(t.TempDir(), "none.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package chain
+
+import (
+	"github.com/icon-project/goloop/chain/watch"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// WatchList returns the watch list configured with the name. Addresses
+// are loaded from the file on the first use.
+func (c *singleChain) WatchList(name string) (*watch.AddressSet, error) {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+
+	if s, ok := c.watchLists[name]; ok {
+		return s, nil
+	}
+	file, ok := c.cfg.WatchLists[name]
+	if !ok {
+		return nil, errors.NotFoundError.Errorf("WatchListNotFound(name=%s)", name)
+	}
+	addrs, err := watch.LoadAddressFile(c.cfg.ResolveAbsolute(file))
+	if err != nil {
+		return nil, err
+	}
+	s := watch.NewAddressSet(addrs)
+	c.logger.Infof("Watch list loaded name=%s size=%d", name, s.Len())
+	if c.watchLists == nil {
+		c.watchLists = make(map[string]*watch.AddressSet)
+	}
+	c.watchLists[name] = s
+	return s, nil
+}
@@ -0,0 +1,147 @@
+package chain
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/chain/exporter"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	keyWebhooks           = "webhooks"
+	webhookExporterPrefix = "webhook."
+)
+
+// WebhookManager manages webhooks notifying activities of addresses.
+// Webhooks are stored in the database of the chain, and they run with
+// the chain like exporters in the configuration.
+type WebhookManager interface {
+	Webhooks() ([]*exporter.Config, error)
+	AddWebhook(name string, cfg json.RawMessage) error
+	RemoveWebhook(name string) error
+}
+
+func (c *singleChain) loadWebhooks() ([]*exporter.Config, error) {
+	bk, err := c.Database().GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get([]byte(keyWebhooks))
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []*exporter.Config{}
+	if bs == nil {
+		return webhooks, nil
+	}
+	if err := json.Unmarshal(bs, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (c *singleChain) storeWebhooks(webhooks []*exporter.Config) error {
+	bk, err := c.Database().GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(webhooks)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(keyWebhooks), bs)
+}
+
+// newWebhookExporter returns the exporter for the webhook. Its name has
+// a prefix not to share the cursor with exporters in the configuration.
+func (c *singleChain) newWebhookExporter(cfg *exporter.Config) (*exporter.Exporter, error) {
+	ecfg := *cfg
+	ecfg.Name = webhookExporterPrefix + cfg.Name
+	return exporter.New(c, &ecfg)
+}
+
+func (c *singleChain) clearWebhookCursor(name string) error {
+	return exporter.ClearCursor(c.Database(), webhookExporterPrefix+name)
+}
+
+func (c *singleChain) Webhooks() ([]*exporter.Config, error) {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+	if c.database == nil {
+		return nil, errors.InvalidStateError.New("NoDatabase")
+	}
+	return c.loadWebhooks()
+}
+
+func (c *singleChain) AddWebhook(name string, cfg json.RawMessage) error {
+	if name == "" {
+		return errors.IllegalArgumentError.New("NoWebhookName")
+	}
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+	if c.database == nil {
+		return errors.InvalidStateError.New("NoDatabase")
+	}
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+
+	webhooks, err := c.loadWebhooks()
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		if w.Name == name {
+			return errors.InvalidStateError.Errorf("DuplicateWebhook(name=%s)", name)
+		}
+	}
+	wcfg := &exporter.Config{
+		Name:   name,
+		Type:   exporter.TypeActivity,
+		Config: cfg,
+	}
+	e, err := c.newWebhookExporter(wcfg)
+	if err != nil {
+		return err
+	}
+	if err := c.clearWebhookCursor(name); err != nil {
+		return err
+	}
+	if err := c.storeWebhooks(append(webhooks, wcfg)); err != nil {
+		return err
+	}
+	if c.expRunning {
+		e.Start()
+		c.webhooks[name] = e
+	}
+	return nil
+}
+
+func (c *singleChain) RemoveWebhook(name string) error {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+	if c.database == nil {
+		return errors.InvalidStateError.New("NoDatabase")
+	}
+	c.expLock.Lock()
+	defer c.expLock.Unlock()
+
+	webhooks, err := c.loadWebhooks()
+	if err != nil {
+		return err
+	}
+	for i, w := range webhooks {
+		if w.Name != name {
+			continue
+		}
+		if err := c.storeWebhooks(append(webhooks[:i], webhooks[i+1:]...)); err != nil {
+			return err
+		}
+		if e, ok := c.webhooks[name]; ok {
+			e.Stop()
+			delete(c.webhooks, name)
+		}
+		return c.clearWebhookCursor(name)
+	}
+	return errors.NotFoundError.Errorf("WebhookNotFound(name=%s)", name)
+}
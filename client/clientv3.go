@@ -207,6 +207,15 @@ func (c *ClientV3) GetBalance(param *v3.AddressParam) (*jsonrpc.HexInt, error) {
 	return &result, nil
 }
 
+func (c *ClientV3) GetBalances(param *v3.AddressesParam) (map[string]jsonrpc.HexInt, error) {
+	var result map[string]jsonrpc.HexInt
+	_, err := c.Do("icx_getBalances", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //refer servicce/scoreapi/info.go Info.ToJSON
 func (c *ClientV3) GetScoreApi(param *v3.ScoreAddressParam) ([]interface{}, error) {
 	var result []interface{}
@@ -332,6 +341,32 @@ func (c *ClientV3) GetVotesByHeight(param *v3.BlockHeightParam) ([]byte, error)
 	return result, nil
 }
 
+func (c *ClientV3) GetRandomnessByHeight(param *v3.BlockHeightParam) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	_, err := c.Do("icx_getRandomnessByHeight", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BlockReceipts is the result of icx_getReceiptsByHeight.
+type BlockReceipts struct {
+	BlockHash     jsonrpc.HexBytes     `json:"blockHash"`
+	BlockHeight   jsonrpc.HexInt       `json:"blockHeight"`
+	Receipts      []*TransactionResult `json:"receipts"`
+	PatchReceipts []*TransactionResult `json:"patchReceipts,omitempty"`
+}
+
+func (c *ClientV3) GetReceiptsByHeight(param *v3.BlockReceiptsParam) (*BlockReceipts, error) {
+	result := &BlockReceipts{}
+	_, err := c.Do("icx_getReceiptsByHeight", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //refer common/trie/ompt/mtp.go mpt.GetProof(index)
 func (c *ClientV3) GetProofForResult(param *v3.ProofResultParam) ([][]byte, error) {
 	var result [][]byte
@@ -412,6 +447,24 @@ func (c *ClientV3) GetScoreStatus(param *v3.ScoreAddressParam) (interface{}, err
 	return result, nil
 }
 
+func (c *ClientV3) GetChainConfig(param *v3.HeightParam) (interface{}, error) {
+	var result interface{}
+	_, err := c.Do("icx_getChainConfig", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ClientV3) GetStepCostHistory(param *v3.StepCostHistoryParam) (interface{}, error) {
+	var result interface{}
+	_, err := c.Do("icx_getStepCostHistory", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) MonitorBlock(param *server.BlockRequest, cb func(v *server.BlockNotification), cancelCh <-chan bool) error {
 	resp := &server.BlockNotification{}
 	return c.Monitor("/block", param, resp, func(v interface{}) {
@@ -554,3 +607,16 @@ func (c *ClientV3) EstimateStep(param *v3.TransactionParamForEstimate) (*common.
 	}
 	return &result, nil
 }
+
+func (c *ClientV3) EstimateFee(param *v3.TransactionParamForEstimate) (map[string]interface{}, error) {
+	if len(c.DebugEndPoint) == 0 {
+		return nil, errors.InvalidStateError.New("UnavailableDebugEndPoint")
+	}
+	param.Timestamp = jsonrpc.HexInt(intconv.FormatInt(time.Now().UnixNano() / int64(time.Microsecond)))
+	var result map[string]interface{}
+	if _, err := c.DoURL(c.DebugEndPoint,
+		"debug_estimateFee", param, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
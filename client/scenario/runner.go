@@ -0,0 +1,409 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+
+	DefaultTimeout  = 30 * time.Second
+	DefaultInterval = time.Second
+
+	deployAddress = "cx0000000000000000000000000000000000000000"
+)
+
+// Client is the part of client.ClientV3 used by the runner.
+type Client interface {
+	SendTransaction(w module.Wallet, param *v3.TransactionParam) (*jsonrpc.HexBytes, error)
+	GetTransactionResult(param *v3.TransactionHashParam) (*client.TransactionResult, error)
+	Call(param *v3.CallParam) (interface{}, error)
+}
+
+// Runner runs scenarios with the wallet. Contents of deploy steps are read
+// relative to Dir.
+type Runner struct {
+	Client    Client
+	Wallet    module.Wallet
+	NID       int64
+	StepLimit int64
+	Dir       string
+
+	// Timeout is the time to wait for the result of a transaction, and
+	// Interval is the interval of polling it.
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// StepResult is the result of a step. Duration is in milliseconds.
+type StepResult struct {
+	Name     string `json:"name"`
+	Action   string `json:"action"`
+	Status   string `json:"status"`
+	TxHash   string `json:"txHash,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration"`
+}
+
+// Result is the result of the scenario. Steps after the failed one are
+// skipped, because they usually depend on the previous ones.
+type Result struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Steps    []*StepResult `json:"steps"`
+	Duration int64         `json:"duration"`
+}
+
+type stepContext struct {
+	vars   map[string]interface{}
+	txHash string
+}
+
+// Run runs steps of the scenario in order.
+func (r *Runner) Run(s *Scenario) *Result {
+	start := time.Now()
+	res := &Result{Name: s.Name, Passed: true}
+	ctx := &stepContext{
+		vars: map[string]interface{}{
+			"sender": r.Wallet.Address().String(),
+		},
+	}
+	for _, step := range s.Steps {
+		sr := &StepResult{Name: step.String(), Action: step.Action}
+		res.Steps = append(res.Steps, sr)
+		if !res.Passed {
+			sr.Status = StatusSkipped
+			continue
+		}
+		ts := time.Now()
+		ctx.txHash = ""
+		err := r.runStep(ctx, step)
+		sr.Duration = time.Since(ts).Milliseconds()
+		sr.TxHash = ctx.txHash
+		if err != nil {
+			sr.Status = StatusFailed
+			sr.Error = err.Error()
+			res.Passed = false
+		} else {
+			sr.Status = StatusPassed
+		}
+	}
+	res.Duration = time.Since(start).Milliseconds()
+	return res
+}
+
+func (r *Runner) runStep(ctx *stepContext, step *Step) error {
+	to, err := ctx.resolveString(step.To)
+	if err != nil {
+		return err
+	}
+	value, err := ctx.resolveString(step.Value)
+	if err != nil {
+		return err
+	}
+	params, err := ctx.resolve(step.Params)
+	if err != nil {
+		return err
+	}
+
+	if step.Action == ActionCall {
+		result, err := r.Client.Call(&v3.CallParam{
+			FromAddress: jsonrpc.Address(r.Wallet.Address().String()),
+			ToAddress:   jsonrpc.Address(to),
+			DataType:    "call",
+			Data:        callData(step.Method, params),
+		})
+		if err != nil {
+			return err
+		}
+		if step.Expect != nil && step.Expect.Result != nil {
+			expected, err := ctx.resolve(step.Expect.Result)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(expected, result) {
+				return fmt.Errorf("unexpected result %v (expected %v)", result, expected)
+			}
+		}
+		if step.Save != "" {
+			ctx.vars[step.Save] = result
+		}
+		return nil
+	}
+
+	stepLimit := r.StepLimit
+	if step.StepLimit != "" {
+		if stepLimit, err = intconv.ParseInt(step.StepLimit, 64); err != nil {
+			return fmt.Errorf("invalid stepLimit %q", step.StepLimit)
+		}
+	}
+	param := &v3.TransactionParam{
+		Version:     v3.VersionValue,
+		FromAddress: jsonrpc.Address(r.Wallet.Address().String()),
+		ToAddress:   jsonrpc.Address(to),
+		StepLimit:   jsonrpc.HexInt(intconv.FormatInt(stepLimit)),
+		NetworkID:   jsonrpc.HexInt(intconv.FormatInt(r.NID)),
+	}
+	if value != "" {
+		param.Value = jsonrpc.HexInt(value)
+	}
+	switch step.Action {
+	case ActionDeploy:
+		content, err := ioutil.ReadFile(filepath.Join(r.Dir, step.Content))
+		if err != nil {
+			return err
+		}
+		contentType := step.ContentType
+		if contentType == "" {
+			contentType = "application/zip"
+			if strings.HasSuffix(step.Content, ".jar") {
+				contentType = "application/java"
+			}
+		}
+		if to == "" {
+			param.ToAddress = deployAddress
+		}
+		param.DataType = "deploy"
+		data := map[string]interface{}{
+			"contentType": contentType,
+			"content":     "0x" + hex.EncodeToString(content),
+		}
+		if params != nil {
+			data["params"] = params
+		}
+		param.Data = data
+	case ActionInvoke:
+		param.DataType = "call"
+		param.Data = callData(step.Method, params)
+	}
+
+	txHash, err := r.Client.SendTransaction(r.Wallet, param)
+	if err != nil {
+		return err
+	}
+	ctx.txHash = string(*txHash)
+	tr, err := r.waitResult(txHash)
+	if err != nil {
+		return err
+	}
+	if err := ctx.checkTransactionResult(step.Expect, tr); err != nil {
+		return err
+	}
+	if step.Save != "" {
+		if step.Action == ActionDeploy {
+			ctx.vars[step.Save] = string(tr.SCOREAddress)
+		} else {
+			ctx.vars[step.Save] = ctx.txHash
+		}
+	}
+	return nil
+}
+
+func callData(method string, params interface{}) map[string]interface{} {
+	data := map[string]interface{}{"method": method}
+	if params != nil {
+		data["params"] = params
+	}
+	return data
+}
+
+func (r *Runner) waitResult(txHash *jsonrpc.HexBytes) (*client.TransactionResult, error) {
+	timeout, interval := r.Timeout, r.Interval
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	expire := time.Now().Add(timeout)
+	param := &v3.TransactionHashParam{Hash: *txHash}
+	for {
+		tr, err := r.Client.GetTransactionResult(param)
+		if err == nil {
+			return tr, nil
+		}
+		je, ok := err.(*jsonrpc.Error)
+		if !ok {
+			return nil, err
+		}
+		switch je.Code {
+		case jsonrpc.ErrorCodePending, jsonrpc.ErrorCodeExecuting, jsonrpc.ErrorCodeNotFound:
+		default:
+			return nil, err
+		}
+		if time.Now().After(expire) {
+			return nil, fmt.Errorf("timeout %v", timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (ctx *stepContext) checkTransactionResult(e *Expect, tr *client.TransactionResult) error {
+	if e == nil {
+		e = &Expect{}
+	}
+	status := e.Status
+	if status == "" {
+		status = "0x1"
+		if e.Failure != "" {
+			status = "0x0"
+		}
+	}
+	if !strings.EqualFold(string(tr.Status), status) {
+		var msg string
+		if tr.Failure != nil {
+			msg = tr.Failure.MessageValue
+		}
+		return fmt.Errorf("unexpected status %s (expected %s) failure=%q",
+			tr.Status, status, msg)
+	}
+	if e.Failure != "" && (tr.Failure == nil || !strings.Contains(tr.Failure.MessageValue, e.Failure)) {
+		return fmt.Errorf("unexpected failure %+v (expected %q)", tr.Failure, e.Failure)
+	}
+	for i, ee := range e.Events {
+		ee, err := ctx.resolveEvent(ee)
+		if err != nil {
+			return err
+		}
+		if !hasEvent(tr.EventLogs, ee) {
+			return fmt.Errorf("no event matching events[%d]", i)
+		}
+	}
+	return nil
+}
+
+func matchValues(expected, values []*string) bool {
+	for i, v := range expected {
+		if v == nil {
+			continue
+		}
+		if i >= len(values) || values[i] == nil || !strings.EqualFold(*values[i], *v) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasEvent(logs []client.EventLog, ee *EventExpect) bool {
+	for _, l := range logs {
+		if ee.Address != "" && string(l.Addr) != ee.Address {
+			continue
+		}
+		if matchValues(ee.Indexed, l.Indexed) && matchValues(ee.Data, l.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *stepContext) resolveStrings(values []*string) ([]*string, error) {
+	resolved := make([]*string, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		s, err := ctx.resolveString(*v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = &s
+	}
+	return resolved, nil
+}
+
+func (ctx *stepContext) resolveEvent(ee *EventExpect) (*EventExpect, error) {
+	addr, err := ctx.resolveString(ee.Address)
+	if err != nil {
+		return nil, err
+	}
+	indexed, err := ctx.resolveStrings(ee.Indexed)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ctx.resolveStrings(ee.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &EventExpect{Address: addr, Indexed: indexed, Data: data}, nil
+}
+
+func (ctx *stepContext) resolveString(s string) (string, error) {
+	v, err := ctx.resolve(s)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("variable %q isn't a string", s)
+}
+
+// resolve returns the value with variables replaced.
+func (ctx *stepContext) resolve(v interface{}) (interface{}, error) {
+	switch obj := v.(type) {
+	case string:
+		if !strings.HasPrefix(obj, "$") {
+			return obj, nil
+		}
+		value, ok := ctx.vars[obj[1:]]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", obj)
+		}
+		return value, nil
+	case map[string]interface{}:
+		if obj == nil {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, len(obj))
+		for k, e := range obj {
+			value, err := ctx.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = value
+		}
+		return m, nil
+	case []interface{}:
+		l := make([]interface{}, len(obj))
+		for i, e := range obj {
+			value, err := ctx.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = value
+		}
+		return l, nil
+	default:
+		return v, nil
+	}
+}
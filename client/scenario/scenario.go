@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package scenario runs declarative scenarios of transactions and queries
+// against a chain, so that operators may check nodes end-to-end after
+// upgrades.
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	ActionDeploy   = "deploy"
+	ActionInvoke   = "invoke"
+	ActionTransfer = "transfer"
+	ActionCall     = "call"
+)
+
+// Scenario is a list of steps run in order.
+type Scenario struct {
+	Name  string  `json:"name"`
+	Steps []*Step `json:"steps"`
+}
+
+// Step is an action of the scenario with the expectation of the result.
+// String values starting with "$" in To, Value and Params are replaced with
+// the variables saved by the previous steps, and "$sender" is the address of
+// the wallet running the scenario.
+//
+// Save stores the score address for deploy, the transaction hash for invoke
+// and transfer, and the result for call to the variable.
+type Step struct {
+	Name        string                 `json:"name,omitempty"`
+	Action      string                 `json:"action"`
+	To          string                 `json:"to,omitempty"`
+	Value       string                 `json:"value,omitempty"`
+	Method      string                 `json:"method,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Content     string                 `json:"content,omitempty"`
+	ContentType string                 `json:"contentType,omitempty"`
+	StepLimit   string                 `json:"stepLimit,omitempty"`
+	Save        string                 `json:"save,omitempty"`
+	Expect      *Expect                `json:"expect,omitempty"`
+}
+
+// Expect is the expectation of the result of the step. Transactions are
+// expected to succeed unless Status or Failure is set. Failure matches
+// a part of the failure message.
+type Expect struct {
+	Status  string         `json:"status,omitempty"`
+	Failure string         `json:"failure,omitempty"`
+	Events  []*EventExpect `json:"events,omitempty"`
+	Result  interface{}    `json:"result,omitempty"`
+}
+
+// EventExpect matches an event log of the transaction. The first of Indexed
+// is the signature of the event, and null matches any value.
+type EventExpect struct {
+	Address string    `json:"scoreAddress,omitempty"`
+	Indexed []*string `json:"indexed,omitempty"`
+	Data    []*string `json:"data,omitempty"`
+}
+
+func (s *Step) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Action
+}
+
+func (s *Step) validate() error {
+	switch s.Action {
+	case ActionDeploy:
+		if s.Content == "" {
+			return fmt.Errorf("no content")
+		}
+	case ActionInvoke, ActionCall:
+		if s.To == "" || s.Method == "" {
+			return fmt.Errorf("no to or method")
+		}
+	case ActionTransfer:
+		if s.To == "" || s.Value == "" {
+			return fmt.Errorf("no to or value")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	if s.Action == ActionCall && s.Expect != nil &&
+		(s.Expect.Status != "" || s.Expect.Failure != "" || len(s.Expect.Events) > 0) {
+		return fmt.Errorf("call can expect only result")
+	}
+	if s.Save != "" && strings.HasPrefix(s.Save, "$") {
+		return fmt.Errorf("invalid variable name %q", s.Save)
+	}
+	return nil
+}
+
+// Parse returns the scenario in JSON.
+func Parse(bs []byte) (*Scenario, error) {
+	s := new(Scenario)
+	if err := json.Unmarshal(bs, s); err != nil {
+		return nil, err
+	}
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("no steps")
+	}
+	for i, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("invalid step[%d] %s: %v", i, step, err)
+		}
+	}
+	return s, nil
+}
+
+// Load returns the scenario in the file.
+func Load(file string) (*Scenario, error) {
+	bs, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bs)
+}
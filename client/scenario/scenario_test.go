@@ -0,0 +1,134 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+const testScore = "cx0000000000000000000000000000000000000011"
+
+type testClient struct {
+	txs     []*v3.TransactionParam
+	pending int
+	value   string
+}
+
+func (c *testClient) SendTransaction(w module.Wallet, param *v3.TransactionParam) (*jsonrpc.HexBytes, error) {
+	c.txs = append(c.txs, param)
+	c.pending = 1
+	h := jsonrpc.HexBytes(fmt.Sprintf("0x%064x", len(c.txs)))
+	return &h, nil
+}
+
+func (c *testClient) GetTransactionResult(param *v3.TransactionHashParam) (*client.TransactionResult, error) {
+	if c.pending > 0 {
+		c.pending -= 1
+		return nil, jsonrpc.ErrorCodePending.New("Pending")
+	}
+	tx := c.txs[len(c.txs)-1]
+	tr := &client.TransactionResult{Status: "0x1", TxHash: param.Hash}
+	switch tx.DataType {
+	case "deploy":
+		tr.SCOREAddress = testScore
+	case "call":
+		data := tx.Data.(map[string]interface{})
+		if data["method"] == "fail" {
+			tr.Status = "0x0"
+			tr.Failure = &client.FailureReason{CodeValue: "0x20", MessageValue: "Reverted(0)"}
+			break
+		}
+		params := data["params"].(map[string]interface{})
+		c.value = params["value"].(string)
+		sig, value := "Set(str)", c.value
+		tr.EventLogs = []client.EventLog{
+			{Addr: jsonrpc.Address(tx.ToAddress), Indexed: []*string{&sig}, Data: []*string{&value}},
+		}
+	}
+	return tr, nil
+}
+
+func (c *testClient) Call(param *v3.CallParam) (interface{}, error) {
+	return c.value, nil
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte(`{"name":"empty","steps":[]}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"steps":[{"action":"unknown"}]}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"steps":[{"action":"invoke","to":"cx0000000000000000000000000000000000000001"}]}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"steps":[{"action":"call","to":"$score","method":"get","expect":{"status":"0x1"}}]}`))
+	assert.Error(t, err)
+	s, err := Parse([]byte(`{"name":"ok","steps":[{"action":"transfer","to":"$sender","value":"0x1"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", s.Name)
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "score.jar"), []byte("jar"), 0644))
+
+	s, err := Parse([]byte(`{
+		"name": "set and get",
+		"steps": [
+			{"action": "deploy", "content": "score.jar", "save": "score"},
+			{"name": "set", "action": "invoke", "to": "$score", "method": "set",
+				"params": {"value": "$sender"},
+				"expect": {"events": [{"scoreAddress": "$score", "indexed": ["Set(str)"]}]}},
+			{"action": "call", "to": "$score", "method": "get", "expect": {"result": "$sender"}},
+			{"action": "invoke", "to": "$score", "method": "fail", "expect": {"failure": "Reverted"}}
+		]
+	}`))
+	assert.NoError(t, err)
+
+	w := wallet.New()
+	c := new(testClient)
+	r := &Runner{Client: c, Wallet: w, NID: 1, StepLimit: 1000000, Dir: dir, Interval: 1}
+	res := r.Run(s)
+	for _, sr := range res.Steps {
+		assert.Equal(t, StatusPassed, sr.Status, "step=%s err=%s", sr.Name, sr.Error)
+	}
+	assert.True(t, res.Passed)
+	assert.Equal(t, "application/java", c.txs[0].Data.(map[string]interface{})["contentType"])
+	assert.Equal(t, w.Address().String(), c.value)
+
+	// steps after the failure are skipped
+	s.Steps[1].Expect.Events[0].Indexed[0] = nil
+	s.Steps[1].Expect.Events[0].Data = []*string{&s.Name}
+	res = r.Run(s)
+	assert.False(t, res.Passed)
+	assert.Equal(t, StatusFailed, res.Steps[1].Status)
+	assert.NotEmpty(t, res.Steps[1].TxHash)
+	assert.Equal(t, StatusSkipped, res.Steps[2].Status)
+	assert.Equal(t, StatusSkipped, res.Steps[3].Status)
+}
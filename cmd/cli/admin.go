@@ -19,10 +19,14 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/profile"
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/node"
+	"github.com/icon-project/goloop/server"
 )
 
 func ReadFile(name string) ([]byte, error) {
@@ -128,6 +132,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.ConcurrencyLevel, _ = fs.GetInt("concurrency")
 			param.NormalTxPoolSize, _ = fs.GetInt("normal_tx_pool")
 			param.PatchTxPoolSize, _ = fs.GetInt("patch_tx_pool")
+			param.PriorityTxPoolSize, _ = fs.GetInt("priority_tx_pool")
 			param.MaxBlockTxBytes, _ = fs.GetInt("max_block_tx_bytes")
 			param.NodeCache, _ = fs.GetString("node_cache")
 			param.Channel, _ = fs.GetString("channel")
@@ -145,7 +150,47 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				nephewsLimit, _ := fs.GetInt("nephews_limit")
 				param.NephewsLimit = &nephewsLimit
 			}
+			if fs.Changed("wal_retention") {
+				walRetention, _ := fs.GetInt("wal_retention")
+				param.WALRetention = &walRetention
+			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.LightServer, _ = fs.GetBool("light_server")
+			param.SCOREIndex, _ = fs.GetBool("score_index")
+			param.EventIndex, _ = fs.GetBool("event_index")
+			param.AddressIndex, _ = fs.GetBool("address_index")
+			param.AccessIndex, _ = fs.GetBool("access_index")
+			param.SnapshotServer, _ = fs.GetBool("snapshot_server")
+			param.LocalTxFirst, _ = fs.GetBool("local_tx_first")
+			param.TxTimestampWindow, _ = fs.GetInt64("tx_timestamp_window")
+			param.QuotaCPU, _ = fs.GetInt("quota_cpu")
+			param.QuotaGoroutines, _ = fs.GetInt("quota_goroutines")
+			param.QuotaDBIO, _ = fs.GetInt64("quota_db_io")
+
+			if genesisID, _ := fs.GetString("genesis_id"); len(genesisID) > 0 {
+				fp := &node.ChainFetchParam{ChainConfig: *param}
+				if len(genesisID) >= 2 && genesisID[:2] == "0x" {
+					genesisID = genesisID[2:]
+				}
+				var err error
+				if fp.GenesisID, err = hex.DecodeString(genesisID); err != nil {
+					return err
+				}
+				if nid, _ := fs.GetString("nid"); len(nid) > 0 {
+					v, err := strconv.ParseInt(nid, 0, 32)
+					if err != nil {
+						return errors.Errorf("invalid nid=%s err=%+v", nid, err)
+					}
+					fp.NID = int(v)
+				}
+				fp.Timeout, _ = fs.GetInt("fetch_timeout")
+				var v string
+				if _, err := adminClient.PostWithJson(node.UrlChain+node.UrlGenesisFetch, fp, &v); err != nil {
+					return err
+				}
+				fmt.Println(v)
+				return nil
+			}
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -160,7 +205,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 					return errors.Errorf("failed WriteGenesisStorage err=%+v", err)
 				}
 			} else {
-				return errors.Errorf("required flag --genesis or --genesis_template")
+				return errors.Errorf("required flag --genesis, --genesis_template or --genesis_id")
 			}
 
 			if genesisStorage, err := gs.New(buf.Bytes()); err != nil {
@@ -170,6 +215,18 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			}
 
 			var v string
+			if chunkSize, _ := fs.GetInt("genesis_chunk"); chunkSize > 0 {
+				u, err := UploadGenesis(&adminClient, buf.Bytes(), chunkSize, os.Stderr)
+				if err != nil {
+					return err
+				}
+				reqUrl := node.UrlChain + node.UrlGenesisUpload + "/" + u.ID + "/join"
+				if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+					return err
+				}
+				fmt.Println(v)
+				return nil
+			}
 			reqUrl := node.UrlChain
 			if _, err := adminClient.PostWithReader(reqUrl, param, "genesisZip", buf, &v); err != nil {
 				return err
@@ -182,6 +239,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags := joinCmd.Flags()
 	joinFlags.String("genesis", "", "Genesis storage path")
 	joinFlags.String("genesis_template", "", "Genesis template directory or file")
+	joinFlags.Int("genesis_chunk", 0, "Upload genesis storage by chunks of the size in bytes, resuming the previous upload (0: disable)")
+	joinFlags.String("genesis_id", "", "ID of genesis transaction for fetching genesis storage from peers of seeds")
+	joinFlags.String("nid", "", "Network ID of the chain for fetching genesis storage (required if the node uses NID for P2P)")
+	joinFlags.Int("fetch_timeout", 0, "Timeout for fetching genesis storage in second (0: uses default value)")
 	joinFlags.String("seed", "", "List of trust-seed ip-port, Comma separated string")
 	joinFlags.Uint("role", 3, "[0:None, 1:Seed, 2:Validator, 3:Both]")
 	joinFlags.String("db_type", "goleveldb", "Name of database system("+strings.Join(db.RegisteredBackendTypes(), ", ")+")")
@@ -189,6 +250,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("concurrency", 1, "Maximum number of executors to be used for concurrency")
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
 	joinFlags.Int("patch_tx_pool", 0, "Size of patch transaction pool")
+	joinFlags.Int("priority_tx_pool", 0, "Size of priority lane of normal transaction pool for governance")
 	joinFlags.Int("max_block_tx_bytes", 0, "Max size of transactions in a block")
 	joinFlags.String("node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	joinFlags.String("channel", "", "Channel")
@@ -202,7 +264,19 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Bool("auto_start", false, "Auto start")
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
+	joinFlags.Int("wal_retention", -1, "Number of previous heights kept in consensus WAL (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Bool("light_server", false, "Serve headers, votes and proofs to light peers")
+	joinFlags.Bool("score_index", false, "Index deployed contracts for icx_getScoreStatusList and icx_getPendingDeployments")
+	joinFlags.Bool("event_index", false, "Index events for icx_getLogs")
+	joinFlags.Bool("address_index", false, "Index transactions of addresses for icx_getStatement and icx_getTransactionsByAddress")
+	joinFlags.Bool("access_index", false, "Record heights of blocks accessing accounts for debug_getStaleState")
+	joinFlags.Bool("snapshot_server", false, "Serve the latest snapshot taken by online backup to peers")
+	joinFlags.Bool("local_tx_first", false, "Include transactions sent through this node first in its proposals up to half of the block")
+	joinFlags.Int64("tx_timestamp_window", 0, "Max difference of timestamp of new transactions from current time in milli-second (0: uses timestamp threshold of the chain)")
+	joinFlags.Int("quota_cpu", 0, "Percentage of a CPU core for execution of transactions and queries (0: no limit)")
+	joinFlags.Int("quota_goroutines", 0, "Max number of concurrent executions of transactions and queries (0: no limit)")
+	joinFlags.Int64("quota_db_io", 0, "Bytes of database reads and writes per second (0: no limit)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -369,6 +443,14 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.DBType, _ = fs.GetString("db_type")
 			param.Height, _ = fs.GetInt64("height")
 
+			if progress, _ := fs.GetBool("progress"); progress {
+				cp := &node.ControlPruneParam{
+					CID:    args[0],
+					DBType: param.DBType,
+					Height: param.Height,
+				}
+				return runControl(&adminClient, node.ControlPrune, cp, printControlProgress)
+			}
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/prune"
 			_, err := adminClient.PostWithJson(reqUrl, param, &v)
@@ -383,6 +465,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	pruneFlags := pruneCmd.Flags()
 	pruneFlags.String("db_type", "", "Database type(default:original database type)")
 	pruneFlags.Int64("height", 0, "Block Height")
+	pruneFlags.Bool("progress", false, "Follow progress until it's done")
 	MarkAnnotationRequired(pruneFlags, "height")
 
 	backupCmd := &cobra.Command{
@@ -395,6 +478,23 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param := &node.ChainBackupParam{
 				Manual: manual,
 			}
+			if progress, _ := fs.GetBool("progress"); progress {
+				cp := &node.ControlBackupParam{
+					CID:    args[0],
+					Manual: manual,
+				}
+				return runControl(&adminClient, node.ControlBackup, cp, func(msg *node.ControlMessage) error {
+					if msg.Event != node.ControlEventDone {
+						return printControlProgress(msg)
+					}
+					var res node.ControlBackupResult
+					if err := json.Unmarshal(msg.Data, &res); err != nil {
+						return err
+					}
+					fmt.Println(res.Name)
+					return nil
+				})
+			}
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
 			_, err := adminClient.PostWithJson(reqUrl, param, &v)
@@ -408,6 +508,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	rootCmd.AddCommand(backupCmd)
 	backupFlags := backupCmd.Flags()
 	backupFlags.Bool("manual", false, "Manual backup mode (just release database)")
+	backupFlags.Bool("progress", false, "Follow progress until it's done")
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -480,6 +581,16 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	configFlags.String("value", "", "use if value starts with '-'.\n"+
 		"(if the third arg is used, this flag will be ignored)")
 
+	NewChainWebhookCmd(rootCmd, &adminClient)
+	NewChainEndpointCmd(rootCmd, &adminClient)
+	NewChainRecoveryCmd(rootCmd, &adminClient)
+	NewChainRewindCmd(rootCmd, &adminClient)
+	NewChainProfileCmd(rootCmd, &adminClient)
+	NewChainUploadCmd(rootCmd, &adminClient)
+	NewChainComponentCmd(rootCmd, &adminClient)
+	NewChainSnapshotCmd(rootCmd, &adminClient)
+	NewChainTailCmd(rootCmd, &adminClient)
+
 	rootCmd.Use = "chain TASK CID PARAM"
 	rootCmd.Args = ArgsWithDefaultErrorFunc(cobra.ExactArgs(3))
 	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -570,8 +681,85 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 	}
 	rootCmd.AddCommand(configCmd)
 
+	handshakesCmd := &cobra.Command{
+		Use:   "handshakes",
+		Short: "Get recent outcomes of P2P handshakes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := adminClient.Get(node.UrlSystem+"/handshakes", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(handshakesCmd)
+
+	alertsCmd := &cobra.Command{
+		Use:   "alerts",
+		Short: "Get firing alerts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := adminClient.Get(node.UrlSystem+"/alerts", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(alertsCmd)
+
+	registryCmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Get status of chains in the registry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sync, _ := cmd.Flags().GetBool("sync"); sync {
+				var v string
+				if _, err := adminClient.Post(node.UrlSystem+"/registry/sync", &v); err != nil {
+					return err
+				}
+			}
+			resp, err := adminClient.Get(node.UrlSystem+"/registry", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	registryCmd.Flags().Bool("sync", false, "Read the registry now (it's done in background)")
+	rootCmd.AddCommand(registryCmd)
+
+	bundleCmd := &cobra.Command{
+		Use:   "bundle [FILE]",
+		Short: "Download status bundle for support requests",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := adminClient.Get(node.UrlSystem+"/bundle", nil)
+			if err != nil {
+				return err
+			}
+			b, fileName, err := node.FileDownload(resp)
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				fileName = args[0]
+			}
+			if err = ioutil.WriteFile(fileName, b, 0600); err != nil {
+				return fmt.Errorf("fail to write file err:%+v", err)
+			}
+			fmt.Println(fileName)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(bundleCmd)
+
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
+	NewEngineCmd(rootCmd, &adminClient)
+	NewSystemLogsCmd(rootCmd, &adminClient)
+	NewAPIKeyCmd(rootCmd, &adminClient)
 
 	return rootCmd, vc
 }
@@ -627,6 +815,7 @@ func NewRestoreCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient)
 			var params node.RestoreBackupParam
 			params.Name = args[0]
 			params.Overwrite, _ = cmd.PersistentFlags().GetBool("overwrite")
+			params.Verify, _ = cmd.PersistentFlags().GetBool("verify")
 			var v string
 			_, err := client.PostWithJson(node.UrlSystem+"/restore", &params, &v)
 			if err != nil {
@@ -638,8 +827,49 @@ func NewRestoreCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient)
 	}
 	startFlags := startCmd.PersistentFlags()
 	startFlags.Bool("overwrite", false, "Overwrite existing chain")
+	startFlags.Bool("verify", false, "Verify the restored chain with peers before serving")
 	rootCmd.AddCommand(startCmd)
 
+	peersCmd := &cobra.Command{
+		Use:   "peers CID",
+		Short: "Start to restore the latest snapshot served by peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			var params node.RestorePeersParam
+			cid, err := strconv.ParseInt(args[0], 0, 32)
+			if err != nil {
+				return errors.Errorf("invalid cid=%s err=%+v", args[0], err)
+			}
+			params.CID.Value = int32(cid)
+			if nid, _ := fs.GetString("nid"); len(nid) > 0 {
+				v, err := strconv.ParseInt(nid, 0, 32)
+				if err != nil {
+					return errors.Errorf("invalid nid=%s err=%+v", nid, err)
+				}
+				params.NID = int(v)
+			}
+			params.SeedAddr, _ = fs.GetString("seed")
+			params.Overwrite, _ = fs.GetBool("overwrite")
+			params.Verify, _ = fs.GetBool("verify")
+			params.Timeout, _ = fs.GetInt("timeout")
+			var v string
+			if _, err := client.PostWithJson(node.UrlSystem+"/restore/peers", &params, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	peersFlags := peersCmd.Flags()
+	peersFlags.String("seed", "", "List of trust-seed ip-port, Comma separated string")
+	peersFlags.String("nid", "", "Network ID of the chain (required if the node uses NID for P2P)")
+	peersFlags.Bool("overwrite", false, "Overwrite existing chain")
+	peersFlags.Bool("verify", false, "Verify the restored chain with peers before serving")
+	peersFlags.Int("timeout", 0, "Timeout for finding peers serving snapshot in second (0: uses default value)")
+	MarkAnnotationRequired(peersFlags, "seed")
+	rootCmd.AddCommand(peersCmd)
+
 	stopCmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop current restoring job",
@@ -657,6 +887,526 @@ func NewRestoreCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient)
 	rootCmd.AddCommand(stopCmd)
 }
 
+func NewEngineCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "engine",
+		Short: "Manage execution engines",
+	}
+	parent.AddCommand(rootCmd)
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Get status of execution engines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlSystem+"/engine", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+
+	recycleCmd := &cobra.Command{
+		Use:   "recycle",
+		Short: "Replace executors with new ones",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			_, err := client.Post(node.UrlSystem+"/engine/recycle", &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(recycleCmd)
+}
+
+func NewChainWebhookCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Manage webhooks for activities of addresses",
+	}
+	parent.AddCommand(rootCmd)
+
+	lsCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List webhooks",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/webhook", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(lsCmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add CID NAME",
+		Short: "Add webhook",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainWebhookParam{Name: args[1]}
+			param.URL, _ = fs.GetString("url")
+			param.Secret, _ = fs.GetString("secret")
+			param.Timeout, _ = fs.GetInt64("timeout")
+			param.Headers, _ = fs.GetStringToString("header")
+			param.WatchList, _ = fs.GetString("watch_list")
+			addrs, _ := fs.GetStringSlice("address")
+			for _, s := range addrs {
+				addr := &common.Address{}
+				if err := addr.SetString(s); err != nil {
+					return errors.Wrapf(err, "invalid Address format %s", s)
+				}
+				param.Addresses = append(param.Addresses, addr)
+			}
+			var v string
+			if _, err := client.PostWithJson(node.UrlChain+"/"+args[0]+"/webhook", param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(addCmd)
+	addFlags := addCmd.Flags()
+	addFlags.String("url", "", "URL to post")
+	addFlags.StringSlice("address", nil, "Addresses to watch")
+	addFlags.String("watch_list", "", "Name of the watch list of the chain")
+	addFlags.String("secret", "", "Secret to sign the body with HMAC-SHA256")
+	addFlags.Int64("timeout", 0, "Timeout of the request in millisecond")
+	addFlags.StringToString("header", nil, "Additional HTTP headers")
+	MarkAnnotationRequired(addFlags, "url")
+
+	rmCmd := &cobra.Command{
+		Use:   "rm CID NAME",
+		Short: "Remove webhook",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			if _, err := client.Delete(node.UrlChain+"/"+args[0]+"/webhook/"+args[1], &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(rmCmd)
+}
+
+func NewAPIKeyCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Manage API keys for clients of JSON-RPC",
+	}
+	parent.AddCommand(rootCmd)
+
+	lsCmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List API keys with usages",
+		Args:  ArgsWithDefaultErrorFunc(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlSystem+"/apikey", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(lsCmd)
+
+	inspectCmd := &cobra.Command{
+		Use:   "inspect NAME",
+		Short: "Inspect API key",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlSystem+"/apikey/"+args[0], nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(inspectCmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add NAME",
+		Short: "Add API key, and print it",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &server.APIKey{Name: args[0]}
+			param.Key, _ = fs.GetString("key")
+			param.Methods, _ = fs.GetStringSlice("method")
+			param.Rate, _ = fs.GetFloat64("rate")
+			param.Burst, _ = fs.GetInt("burst")
+			v := &server.APIKey{}
+			if _, err := client.PostWithJson(node.UrlSystem+"/apikey", param, v); err != nil {
+				return err
+			}
+			fmt.Println(v.Key)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(addCmd)
+	addFlags := addCmd.Flags()
+	addFlags.String("key", "", "Key to use instead of generated one")
+	addFlags.StringSlice("method", nil, "Methods allowed for the key (default: all)")
+	addFlags.Float64("rate", 0, "Requests per second allowed for the key (default: unlimited)")
+	addFlags.Int("burst", 0, "Maximum burst of requests (default: rate)")
+
+	rmCmd := &cobra.Command{
+		Use:   "rm NAME",
+		Short: "Remove API key",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			if _, err := client.Delete(node.UrlSystem+"/apikey/"+args[0], &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(rmCmd)
+}
+
+// UploadGenesis uploads the genesis storage by chunks. If there is
+// an upload of the same genesis storage, then it resumes the upload.
+// Progress is written to w.
+func UploadGenesis(client *node.UnixDomainSockHttpClient, data []byte, chunkSize int, w io.Writer) (*node.GenesisUpload, error) {
+	u := &node.GenesisUpload{}
+	param := &node.GenesisUploadParam{
+		Size:     int64(len(data)),
+		Checksum: crypto.SHA3Sum256(data),
+	}
+	if _, err := client.PostWithJson(node.UrlChain+node.UrlGenesisUpload, param, u); err != nil {
+		return nil, err
+	}
+	if u.Received > 0 && !u.Complete {
+		fmt.Fprintf(w, "Resume upload %s from %d bytes\n", u.ID, u.Received)
+	}
+	reqUrl := node.UrlChain + node.UrlGenesisUpload + "/" + u.ID
+	for !u.Complete {
+		end := u.Received + int64(chunkSize)
+		if end > u.Size {
+			end = u.Size
+		}
+		chunk := data[u.Received:end]
+		chunkParam := &node.GenesisChunkParam{
+			Offset:   u.Received,
+			Checksum: crypto.SHA3Sum256(chunk),
+		}
+		if _, err := client.PostWithReader(reqUrl, chunkParam, "chunk", bytes.NewReader(chunk), u); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(w, "\rUploading %s %d/%d bytes (%d%%)",
+			u.ID, u.Received, u.Size, u.Received*100/u.Size)
+	}
+	fmt.Fprintln(w)
+	return u, nil
+}
+
+func NewChainUploadCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Manage uploads of genesis storages",
+	}
+	parent.AddCommand(rootCmd)
+
+	lsCmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List uploads",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+node.UrlGenesisUpload, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(lsCmd)
+
+	rmCmd := &cobra.Command{
+		Use:   "rm ID",
+		Short: "Cancel upload and remove received data",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			if _, err := client.Delete(node.UrlChain+node.UrlGenesisUpload+"/"+args[0], &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(rmCmd)
+}
+
+func NewChainEndpointCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "endpoint",
+		Short: "Manage the P2P endpoint of the P-Rep",
+	}
+	parent.AddCommand(rootCmd)
+
+	statusCmd := &cobra.Command{
+		Use:   "status CID",
+		Short: "Show the status of the endpoint watcher with the prepared update",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/endpoint", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+
+	confirmCmd := &cobra.Command{
+		Use:   "confirm CID",
+		Short: "Submit the prepared update signed with the wallet of the node",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v common.HexBytes
+			if _, err := client.Post(node.UrlChain+"/"+args[0]+"/endpoint/confirm", &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(confirmCmd)
+}
+
+func NewChainRecoveryCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	cmd := &cobra.Command{
+		Use:   "recovery CID",
+		Short: "Show the recent faults with the actions taken by the recovery policy",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/recovery", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	parent.AddCommand(cmd)
+}
+
+func NewChainRewindCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	cmd := &cobra.Command{
+		Use:   "rewind CID HEIGHT",
+		Short: "Rewind the chain to the finalized block dropping the blocks above it",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.ChainRewindParam{}
+			var err error
+			if param.Height, err = strconv.ParseInt(args[1], 0, 64); err != nil {
+				return err
+			}
+			reqUrl := node.UrlChain + "/" + args[0] + "/rewind"
+			plan := new(chain.RewindPlan)
+			if _, err = client.PostWithJson(reqUrl, param, plan); err != nil {
+				return err
+			}
+			fmt.Printf("Rewind chain from height %d to %d (block hash: %s)\n",
+				plan.Height, plan.Target, plan.BlockHash)
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+				fmt.Print("Blocks above the height are dropped. Confirm rewind? (y/n) ")
+				var answer string
+				fmt.Scanln(&answer)
+				if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+					return fmt.Errorf("rewind is cancelled")
+				}
+			}
+			param.Confirm = plan.BlockHash
+			if _, err = client.PostWithJson(reqUrl, param, plan); err != nil {
+				return err
+			}
+			fmt.Println("OK")
+			return nil
+		},
+	}
+	parent.AddCommand(cmd)
+	cmd.Flags().Bool("yes", false, "Rewind without confirmation")
+}
+
+func NewChainProfileCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Measure the size of the data of the chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	startCmd := &cobra.Command{
+		Use:   "start CID",
+		Short: "Start profiling the state of the last block",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &profile.Param{}
+			param.Top, _ = fs.GetInt("top")
+			param.ReceiptsFrom, _ = fs.GetInt64("receipts_from")
+			var v string
+			if _, err := client.PostWithJson(node.UrlChain+"/"+args[0]+"/profile/start", param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(startCmd)
+	startFlags := startCmd.Flags()
+	startFlags.Int("top", profile.DefaultTop, "Number of the largest accounts to report")
+	startFlags.Int64("receipts_from", 0, "Height of the first block to measure receipts (0: disabled)")
+
+	stopCmd := &cobra.Command{
+		Use:   "stop CID",
+		Short: "Stop the running profiling",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			if _, err := client.Post(node.UrlChain+"/"+args[0]+"/profile/stop", &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(stopCmd)
+
+	statusCmd := &cobra.Command{
+		Use:   "status CID",
+		Short: "Show the status of the profiler with the last result",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/profile", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+}
+
+func NewChainSnapshotCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Take point-in-time consistent backups of the running chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	startCmd := &cobra.Command{
+		Use:   "start CID DIR",
+		Short: "Start to backup the running chain to the directory (relative to the backup directory of the node)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.ChainSnapshotParam{Dir: args[1]}
+			var v string
+			if _, err := client.PostWithJson(node.UrlChain+"/"+args[0]+"/snapshot/start", param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(startCmd)
+
+	stopCmd := &cobra.Command{
+		Use:   "stop CID",
+		Short: "Stop the running backup",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			if _, err := client.Post(node.UrlChain+"/"+args[0]+"/snapshot/stop", &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(stopCmd)
+
+	statusCmd := &cobra.Command{
+		Use:   "status CID",
+		Short: "Show the progress or the result of the last backup",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/snapshot", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+}
+
+func NewChainComponentCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "component",
+		Short: "Pause and resume components of the running chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	lsCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List components with their states",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/component", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(lsCmd)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause CID COMPONENT",
+		Short: "Pause the component (" + strings.Join(module.Components, ", ") + ")",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/component/" + args[1] + "/pause"
+			if _, err := client.Post(reqUrl, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(pauseCmd)
+
+	resumeCmd := &cobra.Command{
+		Use:   "resume CID COMPONENT",
+		Short: "Resume the paused component",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/component/" + args[1] + "/resume"
+			if _, err := client.Post(reqUrl, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(resumeCmd)
+}
+
 func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
 	var adminClient node.UnixDomainSockHttpClient
 	rootCmd, vc := NewCommand(parentCmd, parentVc, "user", "User management")
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/node"
+)
+
+// runControl runs the request over the control connection of the node, and
+// calls the callback for the messages of it. The request is cancelled on
+// the first interrupt.
+func runControl(adminClient *node.UnixDomainSockHttpClient, method string, params interface{}, cb func(msg *node.ControlMessage) error) error {
+	cc, err := adminClient.Control()
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	defer signal.Stop(ch)
+	go func() {
+		if _, ok := <-ch; ok {
+			signal.Stop(ch)
+			_ = cc.Cancel()
+		}
+	}()
+	return cc.Run(method, params, cb)
+}
+
+func printControlProgress(msg *node.ControlMessage) error {
+	if msg.Event != node.ControlEventProgress {
+		return nil
+	}
+	var v node.ControlProgressView
+	if err := json.Unmarshal(msg.Data, &v); err != nil {
+		return err
+	}
+	fmt.Printf("%s height=%d\n", v.State, v.Height)
+	return nil
+}
+
+func NewSystemLogsCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Follow logs of the node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.ControlLogsParam{}
+			param.Level, _ = cmd.Flags().GetString("level")
+			return runControl(client, node.ControlLogs, param, func(msg *node.ControlMessage) error {
+				switch msg.Event {
+				case node.ControlEventLog:
+					var rec log.Record
+					if err := json.Unmarshal(msg.Data, &rec); err != nil {
+						return err
+					}
+					module := rec.Module
+					if module == "" {
+						module = "-"
+					}
+					fmt.Printf("%s %-5s %s %s\n",
+						rec.Time.Format(time.RFC3339Nano), rec.Level, module, rec.Message)
+				case node.ControlEventDone:
+					var res node.ControlLogsResult
+					if err := json.Unmarshal(msg.Data, &res); err != nil {
+						return err
+					}
+					if res.Dropped > 0 {
+						fmt.Fprintf(os.Stderr, "dropped %d records\n", res.Dropped)
+					}
+				}
+				return nil
+			})
+		},
+	}
+	parent.AddCommand(cmd)
+	cmd.Flags().String("level", "info", "Minimum level of logs to follow")
+}
+
+func NewChainTailCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	cmd := &cobra.Command{
+		Use:   "tail CID",
+		Short: "Follow consensus status of the chain",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.ControlChainParam{CID: args[0]}
+			return runControl(client, node.ControlConsensus, param, func(msg *node.ControlMessage) error {
+				if msg.Event != node.ControlEventConsensus {
+					return nil
+				}
+				var v node.ControlConsensusView
+				if err := json.Unmarshal(msg.Data, &v); err != nil {
+					return err
+				}
+				fmt.Printf("height=%d round=%d proposer=%t", v.Height, v.Round, v.Proposer)
+				if v.Partition != "" {
+					fmt.Printf(" partition=%s", v.Partition)
+				}
+				fmt.Println()
+				return nil
+			})
+		},
+	}
+	parent.AddCommand(cmd)
+}
@@ -68,5 +68,22 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	}
 	rootCmd.AddCommand(traceCmd)
 
+	stateDiffCmd := &cobra.Command{
+		Use:   "statediff HASH",
+		Short: "Get state keys read and written by the transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.TransactionHashParam{
+				Hash: jsonrpc.HexBytes(args[0]),
+			}
+			diff, err := debugClient.Do("debug_getStateDiff", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, diff.Result)
+		},
+	}
+	rootCmd.AddCommand(stateDiffCmd)
+
 	return rootCmd, vc
 }
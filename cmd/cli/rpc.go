@@ -74,6 +74,7 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 
 	NewSendTxCmd(rootCmd, vc)
 	NewMonitorCmd(rootCmd, vc)
+	rootCmd.AddCommand(newScenarioCmd(&rpcClient))
 
 	rootCmd.AddCommand(
 		&cobra.Command{
@@ -350,6 +351,23 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 				return JsonPrettyPrintln(os.Stdout, raw)
 			},
 		},
+		&cobra.Command{
+			Use:   "randomnessbyheight HEIGHT",
+			Short: "GetRandomnessByHeight",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				height, err := intconv.ParseInt(args[0], 64)
+				if err != nil {
+					return err
+				}
+				param := &v3.BlockHeightParam{Height: jsonrpc.HexInt(intconv.FormatInt(height))}
+				raw, err := rpcClient.GetRandomnessByHeight(param)
+				if err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, raw)
+			},
+		},
 		&cobra.Command{
 			Use:   "proofforresult HASH INDEX",
 			Short: "GetProofForResult",
@@ -424,6 +442,96 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 	flags = scoreStatusCmd.Flags()
 	flags.Int("height", -1, "BlockHeight")
 
+	chainConfigCmd := &cobra.Command{
+		Use:   "chainconfig",
+		Short: "Get revision, features, step costs and step limits of the chain",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var param *v3.HeightParam
+			height, err := intconv.ParseInt(cmd.Flag("height").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if height != -1 {
+				param = &v3.HeightParam{
+					Height: jsonrpc.HexInt(intconv.FormatInt(height)),
+				}
+			}
+			chainConfig, err := rpcClient.GetChainConfig(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, chainConfig)
+		},
+	}
+	rootCmd.AddCommand(chainConfigCmd)
+	flags = chainConfigCmd.Flags()
+	flags.Int("height", -1, "BlockHeight")
+
+	stepCostHistoryCmd := &cobra.Command{
+		Use:   "stepcosthistory [TYPE]",
+		Short: "Get history of changes of step costs and step price",
+		Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(0, 1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.StepCostHistoryParam{}
+			if len(args) > 0 {
+				param.Type = args[0]
+			}
+			from, err := intconv.ParseInt(cmd.Flag("from").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if from > 0 {
+				param.From = jsonrpc.HexInt(intconv.FormatInt(from))
+			}
+			to, err := intconv.ParseInt(cmd.Flag("to").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if to > 0 {
+				param.To = jsonrpc.HexInt(intconv.FormatInt(to))
+			}
+			history, err := rpcClient.GetStepCostHistory(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, history)
+		},
+	}
+	rootCmd.AddCommand(stepCostHistoryCmd)
+	flags = stepCostHistoryCmd.Flags()
+	flags.Int64("from", 0, "Lowest block height of changes")
+	flags.Int64("to", 0, "Highest block height of changes")
+
+	receiptsByHeightCmd := &cobra.Command{
+		Use:   "receiptsbyheight HEIGHT|HASH",
+		Short: "GetReceiptsByHeight",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.BlockReceiptsParam{}
+			if strings.HasPrefix(args[0], "0x") && len(args[0]) == 66 {
+				param.Hash = jsonrpc.HexBytes(args[0])
+			} else {
+				height, err := intconv.ParseInt(args[0], 64)
+				if err != nil {
+					return err
+				}
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			}
+			param.Patch, _ = cmd.Flags().GetBool("patch")
+			param.Decoded, _ = cmd.Flags().GetBool("decoded")
+			receipts, err := rpcClient.GetReceiptsByHeight(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, receipts)
+		},
+	}
+	rootCmd.AddCommand(receiptsByHeightCmd)
+	flags = receiptsByHeightCmd.Flags()
+	flags.Bool("patch", false, "Include results of patch transactions")
+	flags.Bool("decoded", false, "Include decoded event logs")
+
 	rootCmd.AddCommand(
 		&cobra.Command{
 			Use:   "btpnetwork ID [HEIGHT]",
@@ -559,6 +667,31 @@ func newBTPMessagesParam(args []string) (p *v3.BTPMessagesParam, err error) {
 	return p, nil
 }
 
+// loadWallet returns the wallet of the KeyStore file with the password.
+// The password is read from the secret file if it's specified.
+func loadWallet(ksf, ksec, kpass string) (module.Wallet, error) {
+	var kb, pb []byte
+	var err error
+	if kb, err = ioutil.ReadFile(ksf); err != nil {
+		return nil, fmt.Errorf("fail to open KeyStore file=%s err=%+v", ksf, err)
+	}
+	//key_secret -> key_password
+	if ksec != "" {
+		if pb, err = ioutil.ReadFile(ksec); err != nil {
+			return nil, fmt.Errorf("fail to open KeySecret file=%s err=%+v", ksec, err)
+		}
+	} else if kpass != "" {
+		pb = []byte(kpass)
+	} else {
+		return nil, fmt.Errorf("there is no password information for the KeyStore, use --key_secret or --key_password")
+	}
+	w, err := wallet.NewFromKeyStore(kb, pb)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create wallet err=%+v", err)
+	}
+	return w, nil
+}
+
 func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Command {
 	var rpcClient client.ClientV3
 	var rpcClientSendTx func(w module.Wallet, params *v3.TransactionParam) (interface{}, error)
@@ -609,29 +742,10 @@ func NewSendTxCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comman
 				return err
 			}
 		}
-		var kb, pb []byte
 		var err error
-		ksf := vc.GetString("key_store")
-		if kb, err = ioutil.ReadFile(ksf); err != nil {
-			return fmt.Errorf("fail to open KeyStore file=%s err=%+v", ksf, err)
-		}
-		//key_secret -> key_password
-		ksec := vc.GetString("key_secret")
-		kpass := vc.GetString("key_password")
-		if ksec != "" {
-			if pb, err = ioutil.ReadFile(ksec); err != nil {
-				return fmt.Errorf("fail to open KeySecret file=%s err=%+v", ksec, err)
-			}
-		} else if kpass != "" {
-			pb = []byte(kpass)
-		} else {
-			return fmt.Errorf("there is no password information for the KeyStore, use --key_secret or --key_password")
-		}
-		rpcWallet, err = wallet.NewFromKeyStore(kb, pb)
-		if err != nil {
-			return fmt.Errorf("fail to create wallet err=%+v", err)
-		}
-		return nil
+		rpcWallet, err = loadWallet(vc.GetString("key_store"),
+			vc.GetString("key_secret"), vc.GetString("key_password"))
+		return err
 	}
 	rootCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
 		txHash, ok := vc.Get("txHash").(*jsonrpc.HexBytes)
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/client/scenario"
+	"github.com/icon-project/goloop/common/intconv"
+)
+
+// newScenarioCmd returns the command running the scenario file against the
+// chain. It prints the result in JSON, and fails if any step fails, so that
+// it can be used in scripts checking upgraded nodes.
+func newScenarioCmd(rpcClient *client.ClientV3) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scenario FILE",
+		Short: "Run the scenario of transactions and queries for smoke tests",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			s, err := scenario.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("fail to load scenario file=%s err=%+v", args[0], err)
+			}
+			ksf, _ := fs.GetString("key_store")
+			ksec, _ := fs.GetString("key_secret")
+			kpass, _ := fs.GetString("key_password")
+			w, err := loadWallet(ksf, ksec, kpass)
+			if err != nil {
+				return err
+			}
+			nidStr, _ := fs.GetString("nid")
+			nid, err := intconv.ParseInt(nidStr, 64)
+			if err != nil {
+				return fmt.Errorf("invalid nid %q", nidStr)
+			}
+			stepLimit, _ := fs.GetInt64("step_limit")
+			timeout, _ := fs.GetInt("wait_timeout")
+			interval, _ := fs.GetInt("wait_interval")
+			r := &scenario.Runner{
+				Client:    rpcClient,
+				Wallet:    w,
+				NID:       nid,
+				StepLimit: stepLimit,
+				Dir:       filepath.Dir(args[0]),
+				Timeout:   time.Duration(timeout) * time.Second,
+				Interval:  time.Duration(interval) * time.Millisecond,
+			}
+			res := r.Run(s)
+			if output, _ := fs.GetString("output"); output != "" {
+				if err := JsonPrettySaveFile(output, 0644, res); err != nil {
+					return err
+				}
+			} else if err := JsonPrettyPrintln(os.Stdout, res); err != nil {
+				return err
+			}
+			if !res.Passed {
+				return fmt.Errorf("scenario %q failed", s.Name)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.String("key_store", "", "KeyStore file for wallet")
+	flags.String("key_secret", "", "Secret(password) file for KeyStore")
+	flags.String("key_password", "", "Password for the KeyStore file")
+	flags.String("nid", "", "Network ID")
+	flags.Int64("step_limit", 0, "StepLimit for steps without stepLimit")
+	flags.Int("wait_interval", 1000, "Polling interval(msec) for transaction results")
+	flags.Int("wait_timeout", 30, "Timeout(sec) for each transaction result")
+	flags.String("output", "", "File to store the result in JSON")
+	MarkAnnotationRequired(flags, "key_store", "nid", "step_limit")
+	return cmd
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	stdlog "log"
 	"os"
@@ -16,6 +17,9 @@ import (
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/node"
+	"github.com/icon-project/goloop/server"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/eeproxy"
 )
 
 type ServerConfig struct {
@@ -138,8 +142,22 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags := rootCmd.PersistentFlags()
 	rootPFlags.String("p2p", "127.0.0.1:8080", "Advertise ip-port of P2P")
 	rootPFlags.String("p2p_listen", "", "Listen ip-port of P2P")
+	rootPFlags.String("p2p_audit_log", "", "Audit log filename of P2P handshakes (rotated files resides in same directory)")
+	rootPFlags.Int("p2p_asn_limit", 0, "Max number of peers in an ASN of the mapping file (0: disabled)")
+	rootPFlags.String("p2p_asn_map", "", "Mapping file of '<CIDR> <ASN>' lines for ASN of peers")
+	rootPFlags.Bool("p2p_mux", false, "Multiplex channels over a connection to the peer supporting it")
+	rootPFlags.StringToString("p2p_packet_hops", nil, "Hop limit of relayed packets by protocol (ex: transaction=3)")
+	rootPFlags.StringToString("p2p_packet_ttl", nil, "TTL of packets sent by protocol, 1 for neighbors only (ex: transaction=1)")
+	rootPFlags.Int("p2p_query_puzzle", 0, "Difficulty of puzzle for discovery queries to seed (0: disabled, max: 24)")
+	rootPFlags.Int("p2p_subnet_limit", 0, "Max number of peers in a subnet, /24 for IPv4 and /48 for IPv6 (0: disabled)")
+	rootPFlags.String("ntp_server", "", "NTP server to check clock skew on start (ex: pool.ntp.org)")
 	rootPFlags.String("rpc_addr", ":9080", "Listen ip-port of JSON-RPC")
 	rootPFlags.Bool("rpc_dump", false, "JSON-RPC Request, Response Dump flag")
+	rootPFlags.StringSlice("rpc_trusted_proxies", nil, "CIDRs of proxies trusted for client addresses in forwarded headers or PROXY protocol, comma-separated")
+	rootPFlags.Bool("rpc_proxy_protocol", false, "Accept PROXY protocol header from trusted proxies")
+	rootPFlags.String("rpc_sock", "", "Unix domain socket path of JSON-RPC and admin APIs (empty: disabled)")
+	rootPFlags.String("rpc_sock_mode", "0660", "Permission of the unix domain socket of JSON-RPC in octal")
+	rootPFlags.String("grpc_addr", "", "Listen ip-port of gRPC API (empty: disabled)")
 	rootPFlags.String("ee_socket", "", "Execution engine socket path")
 	rootPFlags.String("key_password", "", "Password for the KeyStore file")
 	rootPFlags.String("log_level", "debug", "Global log level (trace,debug,info,warn,error,fatal,panic)")
@@ -163,6 +181,16 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("log_forwarder_name", "", "LogForwarder name")
 	rootPFlags.StringToString("log_forwarder_options", nil, "LogForwarder options, comma-separated 'key=value'")
 	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java)")
+	rootPFlags.Int64("ee_limits_memory", 0, "Max resident memory of an executor in MiB (0: unlimited)")
+	rootPFlags.Int64("ee_limits_recycle", 0, "Resident memory of an executor in MiB to replace it on release (0: never)")
+	rootPFlags.Int("ee_limits_cpus", 0, "Number of CPUs for execution engines (0: unlimited)")
+	rootPFlags.Int64("ee_limits_files", 0, "Max number of open files of an execution engine (0: unlimited)")
+	rootPFlags.String("ee_limits_seccomp", "", "Seccomp filter (BPF program) file for execution engines (Linux only)")
+	rootPFlags.String("metric_push_gateway", "", "URL of Prometheus push gateway to push metrics")
+	rootPFlags.String("metric_push_remote_write", "", "URL of Prometheus remote-write endpoint to push metrics")
+	rootPFlags.String("metric_push_job", metric.DefaultPushJob, "Job name of pushed metrics")
+	rootPFlags.Int("metric_push_interval", metric.DefaultPushInterval, "Interval of pushing metrics in second")
+	rootPFlags.StringToString("metric_push_labels", nil, "Labels of pushed metrics, comma-separated 'key=value'")
 
 	rootPFlags.String("log_writer_filename", "", "Log filename (rotated files resides in same directory)")
 	rootPFlags.Int("log_writer_maxsize", 100, "Maximum log file size in MiB")
@@ -222,6 +250,7 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 				if err != nil {
 					log.Panicf("Fail to set file logger err=%+v", err)
 				}
+				cfg.LogFile = lwCfg.Filename
 			}
 
 			if lv, err := log.ParseLevel(cfg.LogLevel); err != nil {
@@ -296,7 +325,10 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	nodeDir := vc.GetString("node_dir")
 	cliSocket := vc.GetString("node_sock")
 	eeSocket := vc.GetString("ee_socket")
+	rpcSocket := vc.GetString("rpc_sock")
 	backupDir := vc.GetString("backup_dir")
+	p2pAuditLog := vc.GetString("p2p_audit_log")
+	p2pASNMap := vc.GetString("p2p_asn_map")
 	lwFilename := vc.GetString("log_writer_filename")
 
 	if cfgFilePath != "" {
@@ -328,6 +360,24 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 				return errors.Errorf("fail to merge config file=%s err=%+v", cfg.FilePath, err)
 			}
 		}
+		if mpVc := vc.Sub("metric_push"); mpVc != nil {
+			m := make(map[string]interface{})
+			for k, v := range mpVc.AllSettings() {
+				m["metric_push_"+k] = v
+			}
+			if err := vc.MergeConfigMap(m); err != nil {
+				return errors.Errorf("fail to merge config file=%s err=%+v", cfg.FilePath, err)
+			}
+		}
+		if elVc := vc.Sub("ee_limits"); elVc != nil {
+			m := make(map[string]interface{})
+			for _, k := range elVc.AllKeys() {
+				m["ee_limits_"+k] = elVc.Get(k)
+			}
+			if err := vc.MergeConfigMap(m); err != nil {
+				return errors.Errorf("fail to merge config file=%s err=%+v", cfg.FilePath, err)
+			}
+		}
 	}
 
 	if err := vc.Unmarshal(cfg, ViperDecodeOptJson); err != nil {
@@ -380,6 +430,62 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 		cfg.LogWriter = lwCfg
 	}
 
+	elCfg := &eeproxy.Limits{
+		Memory:  vc.GetInt64("ee_limits_memory"),
+		Recycle: vc.GetInt64("ee_limits_recycle"),
+		CPUs:    vc.GetInt("ee_limits_cpus"),
+		Files:   vc.GetInt64("ee_limits_files"),
+		Seccomp: vc.GetString("ee_limits_seccomp"),
+	}
+	if len(elCfg.Seccomp) > 0 {
+		elCfg.Seccomp = cfg.ResolveRelative(elCfg.Seccomp)
+	}
+	if !elCfg.IsEmpty() {
+		cfg.EELimits = elCfg
+	}
+
+	var mpLabels map[string]string
+	switch v := vc.Get("metric_push_labels").(type) {
+	case string:
+		if m, err := stringToStringConv(v); err != nil {
+			return errors.Errorf("fail to stringToStringConv config from env err=%+v", err)
+		} else {
+			mpLabels = make(map[string]string, len(m))
+			for k, v := range m {
+				mpLabels[k] = fmt.Sprint(v)
+			}
+		}
+	case map[string]interface{}:
+		mpLabels = make(map[string]string, len(v))
+		for k, v := range v {
+			mpLabels[k] = fmt.Sprint(v)
+		}
+	case map[string]string:
+		mpLabels = v
+	}
+	mpCfg := &metric.PushConfig{
+		Gateway:     vc.GetString("metric_push_gateway"),
+		RemoteWrite: vc.GetString("metric_push_remote_write"),
+		Job:         vc.GetString("metric_push_job"),
+		Interval:    vc.GetInt("metric_push_interval"),
+		Labels:      mpLabels,
+	}
+	if !mpCfg.IsEmpty() {
+		if err := mpCfg.Validate(); err != nil {
+			return errors.Errorf("invalid metric push config err=%+v", err)
+		}
+		cfg.MetricPush = mpCfg
+	}
+	if err := cfg.Alert.Validate(); err != nil {
+		return errors.Errorf("invalid alert config err=%+v", err)
+	}
+	if err := cfg.Registry.Validate(); err != nil {
+		return errors.Errorf("invalid registry config err=%+v", err)
+	}
+	if err := cfg.DeployChecks.Validate(); err != nil {
+		return errors.Errorf("invalid deploy checks config err=%+v", err)
+	}
+
 	if nodeDir != "" {
 		cfg.BaseDir = cfg.ResolveRelative(nodeDir)
 	}
@@ -389,9 +495,21 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	if eeSocket != "" {
 		cfg.EESocket = cfg.ResolveRelative(eeSocket)
 	}
+	if rpcSocket != "" {
+		cfg.RPCSocket = cfg.ResolveRelative(rpcSocket)
+	}
+	if _, err := server.ParseUnixSocketMode(cfg.RPCSocketMode); err != nil {
+		return errors.Errorf("invalid rpc_sock_mode err=%+v", err)
+	}
 	if backupDir != "" {
 		cfg.BackupDir = cfg.ResolveRelative(backupDir)
 	}
+	if p2pAuditLog != "" {
+		cfg.P2PAuditLog = cfg.ResolveRelative(p2pAuditLog)
+	}
+	if p2pASNMap != "" {
+		cfg.P2PASNMap = cfg.ResolveRelative(p2pASNMap)
+	}
 
 	//config.KeyStorePass
 	//overwrite env.KeyStorePass
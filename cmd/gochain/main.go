@@ -34,17 +34,26 @@ const (
 
 type GoChainConfig struct {
 	chain.Config
-	P2PAddr       string `json:"p2p"`
-	P2PListenAddr string `json:"p2p_listen"`
-	EESocket      string `json:"ee_socket"`
-	RPCAddr       string `json:"rpc_addr"`
-	RPCDump       bool   `json:"rpc_dump"`
-	RPCDebug      bool   `json:"rpc_debug"`
-	RPCRosetta    bool   `json:"rpc_rosetta"`
-	RPCBatchLimit int    `json:"rpc_batch_limit,omitempty"`
-	EEInstances   int    `json:"ee_instances"`
-	Engines       string `json:"engines"`
-	WSMaxSession  int    `json:"ws_max_session"`
+	P2PAddr           string `json:"p2p"`
+	P2PListenAddr     string `json:"p2p_listen"`
+	EESocket          string `json:"ee_socket"`
+	RPCAddr           string `json:"rpc_addr"`
+	RPCDump           bool   `json:"rpc_dump"`
+	RPCDebug          bool   `json:"rpc_debug"`
+	RPCRosetta        bool   `json:"rpc_rosetta"`
+	RPCBatchLimit     int    `json:"rpc_batch_limit,omitempty"`
+	RPCCallStepLimit  int64  `json:"rpc_call_step_limit,omitempty"`
+	RPCStrict         string `json:"rpc_strict,omitempty"`
+	RPCTrustedProxies string `json:"rpc_trusted_proxies,omitempty"`
+	RPCProxyProtocol  bool   `json:"rpc_proxy_protocol,omitempty"`
+	RPCSocket         string `json:"rpc_sock,omitempty"`
+	RPCSocketMode     string `json:"rpc_sock_mode,omitempty"`
+	EEInstances       int    `json:"ee_instances"`
+	Engines           string `json:"engines"`
+	WSMaxSession      int    `json:"ws_max_session"`
+	WSMaxSessionPerIP int    `json:"ws_max_session_per_ip"`
+	WSMaxSubscription int    `json:"ws_max_subscription"`
+	WSMaxBuffer       int64  `json:"ws_max_buffer"`
 
 	Key          []byte          `json:"key,omitempty"`
 	KeyStoreData json.RawMessage `json:"key_store"`
@@ -118,6 +127,12 @@ func main() {
 	flag.BoolVar(&cfg.RPCDebug, "rpc_debug", false, "JSON-RPC Debug enable")
 	flag.BoolVar(&cfg.RPCRosetta, "rpc_rosetta", false, "JSON-RPC Rosetta enable")
 	flag.IntVar(&cfg.RPCBatchLimit, "rpc_batch_limit", 10, "JSON-RPC batch limit")
+	flag.Int64Var(&cfg.RPCCallStepLimit, "rpc_call_step_limit", 0, "JSON-RPC step limit for icx_call (0: chain limit)")
+	flag.StringVar(&cfg.RPCStrict, "rpc_strict", "", "JSON-RPC endpoints checking requests strictly (v3,v3d,rosetta) - Comma separated string")
+	flag.StringVar(&cfg.RPCTrustedProxies, "rpc_trusted_proxies", "", "CIDRs of proxies trusted for client addresses - Comma separated string")
+	flag.BoolVar(&cfg.RPCProxyProtocol, "rpc_proxy_protocol", false, "Accept PROXY protocol header from trusted proxies")
+	flag.StringVar(&cfg.RPCSocket, "rpc_sock", "", "Unix domain socket path of JSON-RPC (empty: disabled)")
+	flag.StringVar(&cfg.RPCSocketMode, "rpc_sock_mode", "0660", "Permission of the unix domain socket of JSON-RPC in octal")
 	flag.StringVar(&cfg.SeedAddr, "seed", "", "Ip-port of Seed")
 	flag.StringVar(&genesisStorage, "genesis_storage", "", "Genesis storage path")
 	flag.StringVar(&genesisPath, "genesis", "", "Genesis template directory or file")
@@ -135,11 +150,19 @@ func main() {
 	flag.IntVar(&cfg.ConcurrencyLevel, "concurrency", 1, "Maximum number of executors to be used for concurrency")
 	flag.IntVar(&cfg.NormalTxPoolSize, "normal_tx_pool", 0, "Normal transaction pool size")
 	flag.IntVar(&cfg.PatchTxPoolSize, "patch_tx_pool", 0, "Patch transaction pool size")
+	flag.IntVar(&cfg.PriorityTxPoolSize, "priority_tx_pool", 0, "Priority transaction pool size")
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.BoolVar(&cfg.LightServer, "light_server", false, "Serve headers, votes and proofs to light peers")
+	flag.BoolVar(&cfg.SCOREIndex, "score_index", false, "Index deployed contracts for icx_getScoreStatusList and icx_getPendingDeployments")
+	flag.BoolVar(&cfg.EventIndex, "event_index", false, "Index events for icx_getLogs")
+	flag.BoolVar(&cfg.AddressIndex, "address_index", false, "Index transactions of addresses for icx_getStatement and icx_getTransactionsByAddress")
+	flag.BoolVar(&cfg.AccessIndex, "access_index", false, "Record heights of blocks accessing accounts for debug_getStaleState")
+	flag.BoolVar(&cfg.LocalTxFirst, "local_tx_first", false, "Include transactions sent through this node first in its proposals up to half of the block")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
+	cfg.WALRetention = flag.Int("wal_retention", -1, "Number of previous heights kept in consensus WAL (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
 	flag.StringVar(&cfg.ConsoleLevel, "console_level", "trace", "Console log level")
 	flag.StringToStringVar(&modLevels, "mod_level", nil, "Console log level for specific module (<mod>=<level>,...)")
@@ -151,8 +174,16 @@ func main() {
 	flag.Int64Var(&cfg.DefWaitTimeout, "default_wait_timeout", 0, "Default wait timeout in milli-second (0: disable)")
 	flag.Int64Var(&cfg.MaxWaitTimeout, "max_wait_timeout", 0, "Max wait timeout in milli-second (0: uses same value of default_wait_timeout)")
 	flag.Int64Var(&cfg.TxTimeout, "tx_timeout", 0, "Transaction timeout in milli-second (0: uses system default value)")
+	flag.Int64Var(&cfg.TxTimestampWindow, "tx_timestamp_window", 0, "Max difference of timestamp of new transactions from current time in milli-second (0: uses timestamp threshold of the chain)")
+	flag.IntVar(&cfg.QuotaCPU, "quota_cpu", 0, "Percentage of a CPU core for execution of transactions and queries (0: no limit)")
+	flag.IntVar(&cfg.QuotaGoroutines, "quota_goroutines", 0, "Max number of concurrent executions of transactions and queries (0: no limit)")
+	flag.Int64Var(&cfg.QuotaDBIO, "quota_db_io", 0, "Bytes of database reads and writes per second (0: no limit)")
+	flag.Int64Var(&cfg.HaltHeight, "halt_height", 0, "Height of the last block to halt the chain for upgrades (0: no halt)")
 	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java)")
 	flag.IntVar(&cfg.WSMaxSession, "ws_max_session", server.DefaultWSMaxSession, "Websocket session limit (use -1 to disable)")
+	flag.IntVar(&cfg.WSMaxSessionPerIP, "ws_max_session_per_ip", server.DefaultWSMaxSessionPerIP, "Websocket session limit for an IP address (0: no limit)")
+	flag.IntVar(&cfg.WSMaxSubscription, "ws_max_subscription", server.DefaultWSMaxSubscription, "Websocket event filter limit for a session (0: no limit)")
+	flag.Int64Var(&cfg.WSMaxBuffer, "ws_max_buffer", server.DefaultWSMaxBuffer, "Bytes of buffered frames for a websocket session (0: no limit)")
 	flag.StringVar(&lwCfg.Filename, "log_writer_filename", "", "Log filename")
 	flag.IntVar(&lwCfg.MaxSize, "log_writer_maxsize", 100, "Log file max size")
 	flag.IntVar(&lwCfg.MaxAge, "log_writer_maxage", 0, "Log file max age")
@@ -472,7 +503,7 @@ func Execute(cmd *cobra.Command, args []string) {
 	}
 	defer nt.Close()
 
-	ee, err := eeproxy.AllocEngines(logger, strings.Split(cfg.Engines, ",")...)
+	ee, err := eeproxy.AllocEngines(logger, nil, strings.Split(cfg.Engines, ",")...)
 	if err != nil {
 		log.Panicf("FAIL to create engines err=%+v", err)
 	}
@@ -485,13 +516,34 @@ func Execute(cmd *cobra.Command, args []string) {
 
 	pm.SetInstances(cfg.EEInstances, cfg.EEInstances, cfg.EEInstances)
 
+	var strict []string
+	if cfg.RPCStrict != "" {
+		strict = strings.Split(cfg.RPCStrict, ",")
+	}
+	rpcSocketMode, err := server.ParseUnixSocketMode(cfg.RPCSocketMode)
+	if err != nil {
+		log.Panicf("Invalid rpc_sock_mode err=%+v", err)
+	}
+	var proxies []string
+	if cfg.RPCTrustedProxies != "" {
+		proxies = strings.Split(cfg.RPCTrustedProxies, ",")
+	}
 	config := &server.Config{
-		ServerAddress:       cfg.RPCAddr,
-		JSONRPCDump:         cfg.RPCDump,
-		JSONRPCIncludeDebug: cfg.RPCDebug,
-		JSONRPCRosetta:      cfg.RPCRosetta,
-		JSONRPCBatchLimit:   cfg.RPCBatchLimit,
-		WSMaxSession:        cfg.WSMaxSession,
+		ServerAddress:        cfg.RPCAddr,
+		JSONRPCDump:          cfg.RPCDump,
+		JSONRPCIncludeDebug:  cfg.RPCDebug,
+		JSONRPCRosetta:       cfg.RPCRosetta,
+		JSONRPCBatchLimit:    cfg.RPCBatchLimit,
+		JSONRPCCallStepLimit: cfg.RPCCallStepLimit,
+		JSONRPCStrict:        strict,
+		WSMaxSession:         cfg.WSMaxSession,
+		WSMaxSessionPerIP:    cfg.WSMaxSessionPerIP,
+		WSMaxSubscription:    cfg.WSMaxSubscription,
+		WSMaxBuffer:          cfg.WSMaxBuffer,
+		TrustedProxies:       proxies,
+		ProxyProtocol:        cfg.RPCProxyProtocol,
+		UnixSocket:           cfg.RPCSocket,
+		UnixSocketMode:       rpcSocketMode,
 	}
 	srv := server.NewManager(config, wallet, logger)
 	hex.EncodeToString(wallet.Address().ID())
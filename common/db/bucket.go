@@ -66,6 +66,20 @@ const (
 	// ListByMerkleRootBase is the base for the bucket that maps list
 	// from network type dependent merkle root(list)
 	ListByMerkleRootBase BucketID = "L"
+
+	// SCOREVerification maps verification record of the SCORE from
+	// the address of the SCORE.
+	SCOREVerification BucketID = "V"
+
+	// SCORESubmission maps source and metadata submitted with the
+	// verification of the SCORE from the address of the SCORE. They aren't
+	// verified, so they are kept apart from the verification record.
+	SCORESubmission BucketID = "U"
+
+	// FailureDataByHash maps failure data (revert payload) of the
+	// transaction from the hash of the transaction. It's not a part of
+	// the consensus data, so it may be missing for synced blocks.
+	FailureDataByHash BucketID = "F"
 )
 
 // internalKey returns key prefixed with the bucket's id.
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"encoding/binary"
+	"hash"
+	"path/filepath"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Checkpointer is implemented by the databases which can capture their
+// state at a moment without blocking writes.
+type Checkpointer interface {
+	Checkpoint() (Checkpoint, error)
+}
+
+// Checkpoint is the captured state of a database.
+type Checkpoint interface {
+	// Export writes all the entries to a new database named name under
+	// dir, then verifies the written database. on is called with the
+	// number of the written entries, and exporting stops if it returns
+	// an error.
+	Export(dir, name string, on func(entries int64) error) (*ExportResult, error)
+
+	// Release releases the resources for the checkpoint.
+	Release()
+}
+
+// ExportResult is the number of the exported entries with the digest of
+// them.
+type ExportResult struct {
+	Entries int64
+	Digest  []byte
+}
+
+// NewCheckpoint captures the state of the database. It returns
+// UnsupportedError if the backend doesn't support it.
+func NewCheckpoint(database Database) (Checkpoint, error) {
+	switch d := database.(type) {
+	case Checkpointer:
+		return d.Checkpoint()
+	case *databaseContext:
+		return NewCheckpoint(d.Database)
+	default:
+		return nil, errors.UnsupportedError.Errorf(
+			"CheckpointNotSupported(type=%T)", database)
+	}
+}
+
+// Digest calculates the digest of the entries of the database named name
+// under dir. It's used to verify the exported database later.
+func Digest(backend BackendType, dir, name string) (*ExportResult, error) {
+	switch backend {
+	case GoLevelDBBackend:
+		return digestGoLevelDB(filepath.Join(dir, name))
+	default:
+		return nil, errors.UnsupportedError.Errorf(
+			"DigestNotSupported(backend=%s)", backend)
+	}
+}
+
+// entryDigest calculates the digest of the entries in the order of keys.
+type entryDigest struct {
+	hash.Hash
+	entries int64
+}
+
+func (d *entryDigest) add(key, value []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	d.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+	d.Write(key)
+	d.Write(buf[:binary.PutUvarint(buf[:], uint64(len(value)))])
+	d.Write(value)
+	d.entries += 1
+}
+
+func (d *entryDigest) result() *ExportResult {
+	return &ExportResult{
+		Entries: d.entries,
+		Digest:  d.Sum(nil),
+	}
+}
+
+func newEntryDigest() *entryDigest {
+	return &entryDigest{Hash: sha3.New256()}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestGoLevelDB_Checkpoint(t *testing.T) {
+	dir := t.TempDir()
+	database, err := NewGoLevelDB("test", dir)
+	assert.NoError(t, err)
+	defer database.Close()
+
+	bk, _ := database.GetBucket(MerkleTrie)
+	assert.NoError(t, bk.Set([]byte("key1"), []byte("value1")))
+	assert.NoError(t, bk.Set([]byte("key2"), []byte("value2")))
+
+	cp, err := NewCheckpoint(WithFlags(database, Flags{"flag": 1}))
+	assert.NoError(t, err)
+	defer cp.Release()
+
+	// changes after the checkpoint are not exported.
+	assert.NoError(t, bk.Set([]byte("key3"), []byte("value3")))
+	assert.NoError(t, bk.Delete([]byte("key1")))
+
+	var progress int64
+	res, err := cp.Export(dir, "exported", func(n int64) error {
+		progress = n
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, res.Entries)
+	assert.EqualValues(t, 2, progress)
+	assert.Len(t, res.Digest, 32)
+
+	digest, err := Digest(GoLevelDBBackend, dir, "exported")
+	assert.NoError(t, err)
+	assert.Equal(t, res, digest)
+
+	exported, err := NewGoLevelDB("exported", dir)
+	assert.NoError(t, err)
+	defer exported.Close()
+	ebk, _ := exported.GetBucket(MerkleTrie)
+	v, err := ebk.Get([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), v)
+	has, err := ebk.Has([]byte("key3"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// it doesn't overwrite the existing database.
+	_, err = cp.Export(dir, "exported", func(int64) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestNewCheckpoint_Unsupported(t *testing.T) {
+	_, err := NewCheckpoint(NewMapDB())
+	assert.True(t, errors.UnsupportedError.Equals(err))
+
+	_, err = Digest(MapDBBackend, t.TempDir(), "test")
+	assert.True(t, errors.UnsupportedError.Equals(err))
+}
@@ -1,11 +1,15 @@
 package db
 
 import (
+	"bytes"
 	"path/filepath"
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	lerrors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/icon-project/goloop/common/errors"
 )
 
 const GoLevelDBBackend BackendType = "goleveldb"
@@ -34,6 +38,12 @@ func NewGoLevelDBWithOpts(name string, dir string, o *opt.Options) (*GoLevelDB,
 	return database, nil
 }
 
+// IsCorrupted returns whether the error is caused by corruption of the
+// database.
+func IsCorrupted(err error) bool {
+	return errors.FindCause(err, lerrors.IsCorrupted) != nil
+}
+
 //----------------------------------------
 // Database
 
@@ -65,6 +75,107 @@ func (db *GoLevelDB) Close() error {
 	return db.db.Close()
 }
 
+func (db *GoLevelDB) Checkpoint() (Checkpoint, error) {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &goLevelCheckpoint{snap: snap}, nil
+}
+
+//----------------------------------------
+// Checkpoint
+
+const goLevelExportBatchSize = 4 * 1024 * 1024
+
+type goLevelCheckpoint struct {
+	snap *leveldb.Snapshot
+}
+
+func (c *goLevelCheckpoint) Export(dir, name string, on func(int64) error) (*ExportResult, error) {
+	dbPath := filepath.Join(dir, name)
+	out, err := leveldb.OpenFile(dbPath, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return nil, err
+	}
+	exported, err := c.export(out, on)
+	if err2 := out.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := digestGoLevelDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if verified.Entries != exported.Entries || !bytes.Equal(verified.Digest, exported.Digest) {
+		return nil, errors.CriticalFormatError.Errorf(
+			"ExportMismatch(entries=%d,exp=%d,digest=%#x,exp=%#x)",
+			verified.Entries, exported.Entries, verified.Digest, exported.Digest)
+	}
+	return exported, nil
+}
+
+func (c *goLevelCheckpoint) export(out *leveldb.DB, on func(int64) error) (*ExportResult, error) {
+	iter := c.snap.NewIterator(nil, nil)
+	defer iter.Release()
+
+	digest := newEntryDigest()
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		digest.add(iter.Key(), iter.Value())
+		if len(batch.Dump()) >= goLevelExportBatchSize {
+			if err := out.Write(batch, nil); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+			if err := on(digest.entries); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if err := out.Write(batch, nil); err != nil {
+		return nil, err
+	}
+	if err := on(digest.entries); err != nil {
+		return nil, err
+	}
+	return digest.result(), nil
+}
+
+func (c *goLevelCheckpoint) Release() {
+	c.snap.Release()
+}
+
+func digestGoLevelDB(dbPath string) (*ExportResult, error) {
+	db, err := leveldb.OpenFile(dbPath, &opt.Options{
+		ErrorIfMissing: true,
+		ReadOnly:       true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	digest := newEntryDigest()
+	for iter.Next() {
+		digest.add(iter.Key(), iter.Value())
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return digest.result(), nil
+}
+
 //----------------------------------------
 // GetBucket
 
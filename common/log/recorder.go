@@ -0,0 +1,85 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Record is a log entry kept by Recorder.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Module  string    `json:"module,omitempty"`
+	Message string    `json:"message"`
+}
+
+// Recorder keeps the recent log entries of the level or more severe ones.
+type Recorder struct {
+	lvs []logrus.Level
+
+	lock    sync.Mutex
+	records []*Record
+	next    int
+}
+
+func (r *Recorder) Levels() []logrus.Level {
+	return r.lvs
+}
+
+func newRecord(e *logrus.Entry) *Record {
+	rec := &Record{
+		Time:    e.Time,
+		Level:   Level(e.Level).String(),
+		Message: e.Message,
+	}
+	if mod, ok := e.Data[FieldKeyModule].(string); ok {
+		rec.Module = mod
+	}
+	return rec
+}
+
+func (r *Recorder) Fire(e *logrus.Entry) error {
+	rec := newRecord(e)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.records) < cap(r.records) {
+		r.records = append(r.records, rec)
+	} else {
+		r.records[r.next] = rec
+	}
+	r.next = (r.next + 1) % cap(r.records)
+	return nil
+}
+
+// Records returns the kept entries from the oldest one.
+func (r *Recorder) Records() []*Record {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	records := make([]*Record, 0, len(r.records))
+	if len(r.records) == cap(r.records) {
+		records = append(records, r.records[r.next:]...)
+		records = append(records, r.records[:r.next]...)
+	} else {
+		records = append(records, r.records...)
+	}
+	return records
+}
+
+// AddRecorder adds the recorder to the logger, which keeps the last size
+// entries of the level or more severe ones.
+func AddRecorder(l Logger, lv Level, size int) *Recorder {
+	r := &Recorder{
+		records: make([]*Record, 0, size),
+	}
+	for _, v := range logrus.AllLevels {
+		if v <= logrus.Level(lv) {
+			r.lvs = append(r.lvs, v)
+		}
+	}
+	l.addHook(r)
+	return r
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func messagesOf(records []*Record) []string {
+	msgs := make([]string, 0, len(records))
+	for _, r := range records {
+		msgs = append(msgs, r.Message)
+	}
+	return msgs
+}
+
+func TestRecorder(t *testing.T) {
+	l := New()
+	l.SetOutput(ioutil.Discard)
+	r := AddRecorder(l, WarnLevel, 3)
+
+	l.Info("info")
+	l.Warn("warn1")
+	l.WithFields(Fields{FieldKeyModule: "CS"}).Error("error1")
+	assert.Equal(t, []string{"warn1", "error1"}, messagesOf(r.Records()))
+
+	rs := r.Records()
+	assert.Equal(t, "warn", rs[0].Level)
+	assert.Equal(t, "", rs[0].Module)
+	assert.Equal(t, "error", rs[1].Level)
+	assert.Equal(t, "CS", rs[1].Module)
+
+	// the oldest ones are dropped over the size
+	l.Warn("warn2")
+	l.Warn("warn3")
+	l.Warn("warn4")
+	assert.Equal(t, []string{"warn2", "warn3", "warn4"}, messagesOf(r.Records()))
+	l.Error("error2")
+	assert.Equal(t, []string{"warn3", "warn4", "error2"}, messagesOf(r.Records()))
+}
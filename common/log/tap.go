@@ -0,0 +1,69 @@
+package log
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type tapListener struct {
+	lv Level
+	cb func(rec *Record)
+}
+
+// Tap passes log entries to the listeners added to it, so that they can
+// follow logs of the node without reading the log file.
+type Tap struct {
+	lock      sync.Mutex
+	listeners map[*tapListener]struct{}
+}
+
+func (t *Tap) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (t *Tap) Fire(e *logrus.Entry) error {
+	t.lock.Lock()
+	var listeners []*tapListener
+	for l := range t.listeners {
+		if logrus.Level(l.lv) >= e.Level {
+			listeners = append(listeners, l)
+		}
+	}
+	t.lock.Unlock()
+
+	if len(listeners) == 0 {
+		return nil
+	}
+	rec := newRecord(e)
+	for _, l := range listeners {
+		l.cb(rec)
+	}
+	return nil
+}
+
+// AddListener adds the callback for the entries of the level or more
+// severe ones. It returns the function removing the callback. The callback
+// is called on logging, so it shouldn't block.
+func (t *Tap) AddListener(lv Level, cb func(rec *Record)) func() {
+	l := &tapListener{lv, cb}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.listeners[l] = struct{}{}
+	return func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		delete(t.listeners, l)
+	}
+}
+
+// AddTap adds the tap to the logger.
+func AddTap(l Logger) *Tap {
+	t := &Tap{
+		listeners: make(map[*tapListener]struct{}),
+	}
+	l.addHook(t)
+	return t
+}
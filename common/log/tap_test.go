@@ -0,0 +1,40 @@
+package log
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTap(t *testing.T) {
+	l := New()
+	l.SetOutput(ioutil.Discard)
+	l.SetLevel(DebugLevel)
+	tap := AddTap(l)
+
+	var infos, warns []string
+	removeInfo := tap.AddListener(InfoLevel, func(rec *Record) {
+		infos = append(infos, rec.Message)
+	})
+	removeWarn := tap.AddListener(WarnLevel, func(rec *Record) {
+		warns = append(warns, rec.Message)
+	})
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	assert.Equal(t, []string{"info", "warn"}, infos)
+	assert.Equal(t, []string{"warn"}, warns)
+
+	// removed listeners don't get entries
+	removeInfo()
+	l.Error("error")
+	assert.Equal(t, []string{"info", "warn"}, infos)
+	assert.Equal(t, []string{"warn", "error"}, warns)
+
+	removeWarn()
+	l.Error("error2")
+	assert.Equal(t, []string{"warn", "error"}, warns)
+	assert.Empty(t, tap.listeners)
+}
@@ -20,13 +20,19 @@ func (e *CopyContext) Builder() Builder {
 }
 
 func (e *CopyContext) Run() error {
-	for e.builder.UnresolvedCount() > 0 {
-		itr := e.builder.Requests()
+	return resolveFrom(e.builder, e.src)
+}
+
+// resolveFrom supplies the data requested by the builder from the database
+// until all the requests are resolved.
+func resolveFrom(builder Builder, src db.Database) error {
+	for builder.UnresolvedCount() > 0 {
+		itr := builder.Requests()
 		processed := 0
 		for itr.Next() {
 			found := false
 			for _, id := range itr.BucketIDs() {
-				bk, err := e.src.GetBucket(id)
+				bk, err := src.GetBucket(id)
 				if err != nil {
 					return err
 				}
@@ -35,7 +41,7 @@ func (e *CopyContext) Run() error {
 					return err
 				}
 				if v1 != nil {
-					err := e.builder.OnData(id, v1)
+					err := builder.OnData(id, v1)
 					if err != nil {
 						return err
 					}
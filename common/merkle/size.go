@@ -0,0 +1,94 @@
+package merkle
+
+import (
+	"github.com/icon-project/goloop/common/db"
+)
+
+// Size is the number and the bytes of the data in a bucket.
+type Size struct {
+	Count int64
+	Bytes int64
+}
+
+type sizeDB struct {
+	sizes map[db.BucketID]*Size
+}
+
+func (d *sizeDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	s, ok := d.sizes[id]
+	if !ok {
+		s = new(Size)
+		d.sizes[id] = s
+	}
+	return &sizeBucket{s}, nil
+}
+
+func (d *sizeDB) Close() error {
+	return nil
+}
+
+type sizeBucket struct {
+	size *Size
+}
+
+func (b *sizeBucket) Get(key []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *sizeBucket) Has(key []byte) (bool, error) {
+	return false, nil
+}
+
+func (b *sizeBucket) Set(key []byte, value []byte) error {
+	b.size.Count += 1
+	b.size.Bytes += int64(len(value))
+	return nil
+}
+
+func (b *sizeBucket) Delete(key []byte) error {
+	return nil
+}
+
+// SizeContext measures the data requested with the builder instead of
+// copying them. Objects for the builder should be made with the database
+// of the builder, then all the data are requested.
+type SizeContext struct {
+	builder Builder
+	src     db.Database
+	dst     *sizeDB
+}
+
+func (e *SizeContext) Builder() Builder {
+	return e.builder
+}
+
+func (e *SizeContext) Run() error {
+	return resolveFrom(e.builder, e.src)
+}
+
+// SizeOf returns the size of the data resolved for the bucket.
+func (e *SizeContext) SizeOf(id db.BucketID) Size {
+	if s, ok := e.dst.sizes[id]; ok {
+		return *s
+	}
+	return Size{}
+}
+
+// Total returns the size of all the data resolved.
+func (e *SizeContext) Total() Size {
+	var t Size
+	for _, s := range e.dst.sizes {
+		t.Count += s.Count
+		t.Bytes += s.Bytes
+	}
+	return t
+}
+
+func NewSizeContext(src db.Database) *SizeContext {
+	dst := &sizeDB{sizes: make(map[db.BucketID]*Size)}
+	return &SizeContext{
+		builder: NewBuilderWithRawDatabase(dst),
+		src:     src,
+		dst:     dst,
+	}
+}
@@ -0,0 +1,55 @@
+package merkle_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+type countingDB struct {
+	db.Database
+	size merkle.Size
+}
+
+type countingBucket struct {
+	db.Bucket
+	size *merkle.Size
+}
+
+func (d *countingDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil || id != db.MerkleTrie {
+		return bk, err
+	}
+	return &countingBucket{bk, &d.size}, nil
+}
+
+func (b *countingBucket) Set(k, v []byte) error {
+	if has, _ := b.Bucket.Has(k); !has {
+		b.size.Count += 1
+		b.size.Bytes += int64(len(v))
+	}
+	return b.Bucket.Set(k, v)
+}
+
+func TestSizeContext(t *testing.T) {
+	dbase := &countingDB{Database: db.NewMapDB()}
+	mt := trie_manager.NewMutable(dbase, nil)
+	for i := 0; i < 1000; i++ {
+		_, err := mt.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	ss := mt.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+
+	sc := merkle.NewSizeContext(dbase)
+	trie_manager.NewImmutable(sc.Builder().Database(), ss.Hash()).Resolve(sc.Builder())
+	assert.NoError(t, sc.Run())
+	assert.Equal(t, dbase.size, sc.SizeOf(db.MerkleTrie))
+	assert.Equal(t, merkle.Size{}, sc.SizeOf(db.BytesByHash))
+}
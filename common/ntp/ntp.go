@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ntp implements SNTP (RFC 4330) client to measure the clock skew.
+package ntp
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	DefaultPort = "123"
+
+	packetSize = 48
+	// seconds from 1900-01-01 (NTP epoch) to 1970-01-01 (Unix epoch)
+	epochOffset = 2208988800
+
+	modeClient = 3
+	modeServer = 4
+	version    = 4
+)
+
+func toTime(b []byte) time.Time {
+	sec := int64(binary.BigEndian.Uint32(b[0:4]))
+	frac := int64(binary.BigEndian.Uint32(b[4:8]))
+	return time.Unix(sec-epochOffset, (frac*int64(time.Second))>>32)
+}
+
+func putTime(b []byte, t time.Time) {
+	ns := t.UnixNano()
+	sec := ns/int64(time.Second) + epochOffset
+	frac := (ns % int64(time.Second)) << 32 / int64(time.Second)
+	binary.BigEndian.PutUint32(b[0:4], uint32(sec))
+	binary.BigEndian.PutUint32(b[4:8], uint32(frac))
+}
+
+// Offset returns the offset of the clock of the server from the local clock.
+// The local clock is behind the server if it's positive.
+func Offset(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, DefaultPort)
+	}
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, packetSize)
+	req[0] = version<<3 | modeClient
+	t1 := time.Now()
+	putTime(req[40:48], t1)
+	if _, err = conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, packetSize)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if n < packetSize {
+		return 0, errors.InvalidNetworkError.Errorf("ShortPacket(size=%d)", n)
+	}
+	if mode := resp[0] & 0x7; mode != modeServer {
+		return 0, errors.InvalidNetworkError.Errorf("InvalidMode(mode=%d)", mode)
+	}
+	if stratum := resp[1]; stratum == 0 {
+		return 0, errors.InvalidStateError.Errorf("KissOfDeath(code=%s)", resp[12:16])
+	}
+	t2 := toTime(resp[32:40])
+	t3 := toTime(resp[40:48])
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
@@ -0,0 +1,58 @@
+package ntp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(conn net.PacketConn, skew time.Duration, stratum byte) {
+	buf := make([]byte, packetSize)
+	n, addr, err := conn.ReadFrom(buf)
+	if err != nil || n < packetSize {
+		return
+	}
+	resp := make([]byte, packetSize)
+	resp[0] = version<<3 | modeServer
+	resp[1] = stratum
+	copy(resp[24:32], buf[40:48])
+	putTime(resp[32:40], time.Now().Add(skew))
+	putTime(resp[40:48], time.Now().Add(skew))
+	_, _ = conn.WriteTo(resp, addr)
+}
+
+func TestTime(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	b := make([]byte, 8)
+	putTime(b, now)
+	assert.InDelta(t, now.UnixNano(), toTime(b).UnixNano(), 1)
+}
+
+func TestOffset(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	skew := 3 * time.Second
+	go serve(conn, skew, 1)
+	offset, err := Offset(conn.LocalAddr().String(), time.Second)
+	assert.NoError(t, err)
+	assert.InDelta(t, int64(skew), int64(offset), float64(100*time.Millisecond))
+
+	go serve(conn, -skew, 1)
+	offset, err = Offset(conn.LocalAddr().String(), time.Second)
+	assert.NoError(t, err)
+	assert.InDelta(t, int64(-skew), int64(offset), float64(100*time.Millisecond))
+}
+
+func TestOffset_KissOfDeath(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	go serve(conn, 0, 0)
+	_, err = Offset(conn.LocalAddr().String(), time.Second)
+	assert.Error(t, err)
+}
@@ -181,6 +181,7 @@ type consensus struct {
 
 	// monitor
 	metric *metric.ConsensusMetric
+	rh     roundHistory
 
 	lastVoteData *LastVoteData
 }
@@ -293,6 +294,7 @@ func (cs *consensus) _resetForNewRound(round int32) {
 }
 
 func (cs *consensus) resetForNewRound(round int32) {
+	cs.onRoundFailure(round)
 	cs.endStep()
 	cs._resetForNewRound(round)
 	cs.beginStep(stepNewRound)
@@ -921,28 +923,41 @@ func (cs *consensus) commitAndEnterNewHeight() {
 				}
 
 				if err != nil {
-					cs.log.Panicf("commitAndEnterNewHeight: %+v\n", err)
+					cs.onCommitFault(err)
+					return
 				}
 				cs.currentBlockParts.SetValidatedBlock(blk)
 				err = cs.c.BlockManager().Finalize(cs.currentBlockParts.validatedBlock)
 				if err != nil {
-					cs.log.Panicf("commitAndEnterNewHeight: %+v\n", err)
+					cs.onCommitFault(err)
+					return
 				}
 				cs.enterNewHeight()
 			},
 		)
 		if err != nil {
-			cs.log.Panicf("commitAndEnterNewHeight: %+v\n", err)
+			cs.onCommitFault(err)
 		}
 	} else {
 		err := cs.c.BlockManager().Finalize(cs.currentBlockParts.validatedBlock)
 		if err != nil {
-			cs.log.Panicf("commitAndEnterNewHeight: %+v\n", err)
+			cs.onCommitFault(err)
+			return
 		}
 		cs.enterNewHeight()
 	}
 }
 
+// onCommitFault reports the failure on committing the block to the chain.
+// The consensus stays at the height until the chain recovers from it. If
+// the chain doesn't handle faults, then it panics.
+func (cs *consensus) onCommitFault(err error) {
+	if !module.HandleFault(cs.c, module.FaultExecution, err) {
+		cs.log.Panicf("commitAndEnterNewHeight: %+v\n", err)
+	}
+	cs.log.Errorf("commitAndEnterNewHeight: %+v", err)
+}
+
 func (cs *consensus) enterCommit(precommits *voteSet, partSetID *PartSetID, round int32) {
 	cs.resetForNewStep(stepCommit)
 	cs.commitRound = round
@@ -1026,8 +1041,10 @@ func (cs *consensus) enterTransactionWait() {
 }
 
 func (cs *consensus) enterNewHeight() {
+	cs.onCommitRound()
 	votes := cs.hvs.votesFor(cs.commitRound, VoteTypePrecommit)
 	cs.resetForNewHeight(cs.currentBlockParts.validatedBlock, votes)
+	cs.compactWAL()
 	cs.notifySyncer()
 
 	now := time.Now()
@@ -1735,7 +1752,7 @@ func (cs *consensus) Start() error {
 	cs.resetForNewHeight(lastBlock, newVoteSet(0))
 	cs.prevValidators = validators
 	if err := cs.applyWAL(validators); err != nil {
-		return err
+		return module.NewFault(module.FaultWAL, err)
 	}
 	if err := cs.applyGenesis(validators); err != nil {
 		return err
@@ -1747,33 +1764,36 @@ func (cs *consensus) Start() error {
 	ww, err := cs.wm.OpenForWrite(path.Join(cs.walDir, configRoundWALID), &WALConfig{
 		FileLimit:  configRoundWALDataSize,
 		TotalLimit: configRoundWALDataSize * 3,
+		Metric:     cs.metric,
 	})
 	if err != nil {
-		return err
+		return module.NewFault(module.FaultWAL, err)
 	}
 	cs.roundWAL = &walMessageWriter{ww}
 
 	ww, err = cs.wm.OpenForWrite(path.Join(cs.walDir, configLockWALID), &WALConfig{
 		FileLimit:  configLockWALDataSize,
 		TotalLimit: configLockWALDataSize * 3,
+		Metric:     cs.metric,
 	})
 	if err != nil {
-		return err
+		return module.NewFault(module.FaultWAL, err)
 	}
 	cs.lockWAL = &walMessageWriter{ww}
 
 	ww, err = cs.wm.OpenForWrite(path.Join(cs.walDir, configCommitWALID), &WALConfig{
 		FileLimit:  configCommitWALDataSize,
 		TotalLimit: configCommitWALDataSize * 3,
+		Metric:     cs.metric,
 	})
 	if err != nil {
-		return err
+		return module.NewFault(module.FaultWAL, err)
 	}
 	cs.commitWAL = &walMessageWriter{ww}
 
 	cs.started = true
 	cs.log.Infof("Start consensus wallet:%v", common.HexPre(cs.c.Wallet().Address().ID()))
-	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address())
+	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address(), cs.metric)
 	if err != nil {
 		return err
 	}
@@ -1801,6 +1821,41 @@ func (cs *consensus) Start() error {
 	return nil
 }
 
+// SetSyncServing starts or stops serving blocks to the peers. It doesn't
+// hold the lock while stopping the server, since handlers of the server
+// may wait for the lock to get block proofs.
+func (cs *consensus) SetSyncServing(serving bool) {
+	cs.mutex.Lock()
+	syncer := cs.syncer
+	started := cs.started
+	cs.mutex.Unlock()
+
+	if started && syncer != nil {
+		syncer.SetServing(serving)
+	}
+}
+
+// Barrier calls the function after syncing WALs while it holds the lock.
+// So the function can capture WALs and the database consistent with each
+// other.
+func (cs *consensus) Barrier(f func() error) error {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if !cs.started {
+		return errors.InvalidStateError.New("ConsensusNotStarted")
+	}
+	for _, w := range []*walMessageWriter{cs.roundWAL, cs.lockWAL, cs.commitWAL} {
+		if w == nil {
+			continue
+		}
+		if err := w.Sync(); err != nil {
+			return err
+		}
+	}
+	return f()
+}
+
 func (cs *consensus) Term() {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
@@ -1848,6 +1903,9 @@ func (cs *consensus) GetStatus() *module.ConsensusStatus {
 	if cs.validators != nil {
 		res.Proposer = cs.isProposer()
 	}
+	if cs.syncer != nil {
+		res.Partition = cs.syncer.Partition()
+	}
 	return res
 }
 
@@ -2098,6 +2156,20 @@ func (cs *consensus) GetBlockProof(height int64, opt int32) ([]byte, error) {
 	return cvs.Bytes(), nil
 }
 
+// compactWAL removes round and lock messages of the heights out of the
+// retention. Messages of the previous heights are not used on recovery, and
+// commit WAL is kept for the votes of the last block.
+func (cs *consensus) compactWAL() {
+	retention := cs.c.WALRetention()
+	for _, w := range []*walMessageWriter{cs.roundWAL, cs.lockWAL} {
+		if c, ok := w.WALWriter.(WALCompactor); ok {
+			if err := c.Compact(retention); err != nil {
+				cs.log.Warnf("fail to compact WAL: %+v", err)
+			}
+		}
+	}
+}
+
 type walMessageWriter struct {
 	WALWriter
 }
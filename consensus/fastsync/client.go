@@ -15,8 +15,10 @@ import (
 const (
 	configSendInterval      = time.Millisecond * 100
 	configTimeout           = time.Millisecond * 3500
-	configMaxPendingResults = 10
-	configMaxActive         = 3
+	configMaxPendingResults = 20
+	configMaxActive         = 4
+	configMaxRangeSize      = 4
+	configSlowPeerRatio     = 4
 )
 
 type client struct {
@@ -86,15 +88,7 @@ func (br *blockResult) Reject() {
 
 	for i, p := range fr.validPeers {
 		if p.id.Equal(br.id) {
-			last := len(fr.validPeers) - 1
-			fr.validPeers[i] = fr.validPeers[last]
-			fr.validPeers[last] = nil
-			fr.validPeers = fr.validPeers[:last]
-			if p.f != nil {
-				p.f.cancel()
-				fr.nActivePeers--
-				fr._reschedule()
-			}
+			fr._removePeer(i)
 			break
 		}
 	}
@@ -120,6 +114,27 @@ type peer struct {
 	id        module.PeerID
 	requestID uint16
 	f         *fetcher
+
+	// heights assigned to the peer to be fetched after f.
+	heights []int64
+	// throughput in bytes per second. Zero if it's not measured yet.
+	throughput float64
+}
+
+func newPeer(id module.PeerID) *peer {
+	return &peer{id: id}
+}
+
+func (p *peer) updateThroughput(size int, elapsed time.Duration) {
+	if elapsed <= 0 {
+		elapsed = time.Millisecond
+	}
+	tp := float64(size) / elapsed.Seconds()
+	if p.throughput == 0 {
+		p.throughput = tp
+	} else {
+		p.throughput = (p.throughput*3 + tp) / 4
+	}
 }
 
 type fetchRequest struct {
@@ -169,7 +184,7 @@ func (cl *client) fetchBlocks(
 	peerIDs := cl.ph.GetPeers()
 	fr.validPeers = make([]*peer, len(peerIDs))
 	for i, id := range peerIDs {
-		fr.validPeers[i] = &peer{id, 0, nil}
+		fr.validPeers[i] = newPeer(id)
 	}
 	fr.nActivePeers = 0
 	fr.consumeOffset = begin
@@ -208,8 +223,7 @@ func (cl *client) onJoin(id module.PeerID) {
 			return
 		}
 	}
-	peer := &peer{id, 0, nil}
-	fr.validPeers = append(fr.validPeers, peer)
+	fr.validPeers = append(fr.validPeers, newPeer(id))
 	fr._reschedule()
 }
 
@@ -223,22 +237,114 @@ func (cl *client) onLeave(id module.PeerID) {
 	}
 	for i, p := range fr.validPeers {
 		if p.id.Equal(id) {
-			last := len(fr.validPeers) - 1
-			fr.validPeers[i] = fr.validPeers[last]
-			fr.validPeers[last] = nil
-			fr.validPeers = fr.validPeers[:last]
-			if p.f != nil {
-				p.f.cancel()
-				fr.heightSet.add(p.f.height)
-				fr.nActivePeers--
-				fr._reschedule()
-			}
+			fr._removePeer(i)
+			fr._reschedule()
 			return
 		}
 	}
 }
 
+// _removePeer removes the peer at the index and returns heights assigned to
+// the peer to the height set.
+func (fr *fetchRequest) _removePeer(i int) {
+	p := fr.validPeers[i]
+	last := len(fr.validPeers) - 1
+	fr.validPeers[i] = fr.validPeers[last]
+	fr.validPeers[last] = nil
+	fr.validPeers = fr.validPeers[:last]
+	if p.f != nil {
+		if p.f.step != fstepFin {
+			p.f.cancel()
+		}
+		fr.heightSet.add(p.f.height)
+		fr.nActivePeers--
+		p.f = nil
+	}
+	fr._releaseHeights(p)
+}
+
+func (fr *fetchRequest) _releaseHeights(p *peer) {
+	for _, h := range p.heights {
+		fr.heightSet.add(h)
+	}
+	p.heights = nil
+}
+
+func (fr *fetchRequest) _bestThroughput() float64 {
+	var best float64
+	for _, p := range fr.validPeers {
+		if p.throughput > best {
+			best = p.throughput
+		}
+	}
+	return best
+}
+
+// _reassignSlowPeers returns heights assigned to the peers much slower than
+// the fastest one, so that they can be fetched from faster peers.
+func (fr *fetchRequest) _reassignSlowPeers() {
+	best := fr._bestThroughput()
+	for _, p := range fr.validPeers {
+		if len(p.heights) > 0 && p.throughput*configSlowPeerRatio < best {
+			fr.cl.log.Debugf("reassign heights:%v from slow peer:%s throughput:%.0f best:%.0f\n",
+				p.heights, common.HexPre(p.id.Bytes()), p.throughput, best)
+			fr._releaseHeights(p)
+		}
+	}
+}
+
+// _rangeSizeOf returns the number of heights to be assigned to the peer at
+// once. A peer not measured yet gets one height, and a measured peer gets
+// heights in proportion to its throughput.
+func (fr *fetchRequest) _rangeSizeOf(p *peer) int {
+	if p.throughput == 0 {
+		return 1
+	}
+	n := int(configMaxRangeSize * p.throughput / fr._bestThroughput())
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// _selectIdlePeer returns the idle peer to be assigned. Peers not measured
+// yet are preferred to measure them, then faster peers are preferred.
+func (fr *fetchRequest) _selectIdlePeer() *peer {
+	var selected *peer
+	for _, p := range fr.validPeers {
+		if p.f != nil {
+			continue
+		}
+		if p.throughput == 0 {
+			return p
+		}
+		if selected == nil || p.throughput > selected.throughput {
+			selected = p
+		}
+	}
+	return selected
+}
+
+func (fr *fetchRequest) _inWindow(h int64) bool {
+	return h < fr.consumeOffset+int64(len(fr.pendingResults))
+}
+
+func (fr *fetchRequest) _startFetch(p *peer, h int64) {
+	requestID := uint32(fr.cl.fetchID)<<16 | uint32(p.requestID)
+	p.f = fr.newFetcher(p.id, h, requestID)
+	p.requestID++
+	fr.nActivePeers++
+}
+
 func (fr *fetchRequest) _reschedule() {
+	// continue with the heights already assigned
+	for _, p := range fr.validPeers {
+		if p.f == nil && len(p.heights) > 0 {
+			h := p.heights[0]
+			p.heights = p.heights[1:]
+			fr._startFetch(p, h)
+		}
+	}
 	for {
 		if fr.nActivePeers >= fr.maxActive {
 			return
@@ -247,24 +353,23 @@ func (fr *fetchRequest) _reschedule() {
 			return
 		}
 		l, ok := fr.heightSet.getLowest()
-		if !ok || fr.consumeOffset+int64(len(fr.pendingResults)) <= l {
+		if !ok || !fr._inWindow(l) {
 			return
 		}
-		var peer *peer
-		for _, p := range fr.validPeers {
-			if p.f == nil {
-				peer = p
-				break
-			}
-		}
+		peer := fr._selectIdlePeer()
 		if peer == nil {
 			panic("wrong validPeers state")
 		}
-		requestID := uint32(fr.cl.fetchID)<<16 | uint32(peer.requestID)
-		peer.f = fr.newFetcher(peer.id, l, requestID)
-		peer.requestID++
 		fr.heightSet.popLowest()
-		fr.nActivePeers++
+		for n := fr._rangeSizeOf(peer) - 1; n > 0; n-- {
+			h, ok := fr.heightSet.getLowest()
+			if !ok || !fr._inWindow(h) {
+				break
+			}
+			fr.heightSet.popLowest()
+			peer.heights = append(peer.heights, h)
+		}
+		fr._startFetch(peer, l)
 	}
 }
 
@@ -297,12 +402,7 @@ func (cl *client) onResult(f *fetcher, err error, blk module.BlockData, votes []
 		if p == nil {
 			return
 		}
-		fr.nActivePeers--
-		last := len(fr.validPeers) - 1
-		fr.validPeers[i] = fr.validPeers[last]
-		fr.validPeers[last] = nil
-		fr.validPeers = fr.validPeers[:last]
-		fr.heightSet.add(f.height)
+		fr._removePeer(i)
 		if !isNoBlock(err) {
 			for i := 1; i < len(fr.pendingResults); i++ {
 				ri := fr.pendingResults[i]
@@ -340,6 +440,12 @@ func (cl *client) onResult(f *fetcher, err error, blk module.BlockData, votes []
 	fr.nActivePeers--
 	p.f = nil
 
+	size := 0
+	for _, d := range f.dataList {
+		size += len(d)
+	}
+	p.updateThroughput(size, time.Since(f.sentAt))
+	fr._reassignSlowPeers()
 	fr._reschedule()
 	if offset == 0 {
 		cl.notifyBlockResult()
@@ -379,6 +485,7 @@ type fetcher struct {
 	cl        *client
 
 	step     fstep
+	sentAt   time.Time
 	timer    *time.Timer
 	left     int32
 	voteList []byte
@@ -434,6 +541,7 @@ func (f *fetcher) _doSend() {
 	err := f.cl.ph.Unicast(ProtoBlockRequest, bs, f.id)
 	if err == nil {
 		f.step = fstepWaitResp
+		f.sentAt = time.Now()
 		var timer *time.Timer
 		timer = time.AfterFunc(configTimeout, func() {
 			f.Lock()
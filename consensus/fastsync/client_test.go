@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -175,3 +176,46 @@ func TestClient_SuccessMulti(t *testing.T) {
 	ev2 = <-s.cb.ch
 	s.assertEndEvent(nil, ev2)
 }
+
+func TestFetchRequest_RangeAssignment(t *testing.T) {
+	cl := newClient(nil, nil, nil, log.New())
+	fr := &fetchRequest{
+		cl:             cl,
+		heightSet:      newHeightSet(1, 100),
+		consumeOffset:  1,
+		pendingResults: make([]*blockResult, configMaxPendingResults),
+	}
+	fast := newPeer(test.NewNetworkManager().ID)
+	slow := newPeer(test.NewNetworkManager().ID)
+	unknown := newPeer(test.NewNetworkManager().ID)
+	fr.validPeers = []*peer{slow, fast, unknown}
+
+	fast.updateThroughput(1000, time.Second)
+	slow.updateThroughput(100, time.Second)
+	assert.Equal(t, unknown, fr._selectIdlePeer(), "unmeasured peer first")
+	fr.validPeers = fr.validPeers[:2]
+	assert.Equal(t, fast, fr._selectIdlePeer())
+
+	assert.Equal(t, configMaxRangeSize, fr._rangeSizeOf(fast))
+	assert.Equal(t, 1, fr._rangeSizeOf(slow))
+	assert.Equal(t, 1, fr._rangeSizeOf(unknown))
+
+	fr.heightSet.begin = 10
+	slow.heights = []int64{5, 6}
+	fast.heights = []int64{7, 8}
+	fr._reassignSlowPeers()
+	assert.Empty(t, slow.heights)
+	assert.Equal(t, []int64{7, 8}, fast.heights)
+	h, _ := fr.heightSet.popLowest()
+	assert.EqualValues(t, 5, h)
+	h, _ = fr.heightSet.popLowest()
+	assert.EqualValues(t, 6, h)
+}
+
+func TestPeer_UpdateThroughput(t *testing.T) {
+	p := newPeer(nil)
+	p.updateThroughput(1000, time.Second)
+	assert.EqualValues(t, 1000, p.throughput)
+	p.updateThroughput(200, time.Second)
+	assert.EqualValues(t, 800, p.throughput)
+}
@@ -0,0 +1,37 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configPartitionHeightGap    = 10
+	configPeerRoundStateTimeout = time.Minute
+)
+
+// partitionOf returns the probable partition of the node at the height
+// comparing with the heights of the peers. It detects partition only if all
+// peers are far behind or ahead of the node.
+func partitionOf(height int64, peerHeights []int64) module.Partition {
+	if len(peerHeights) == 0 {
+		return module.PartitionNone
+	}
+	var behind, ahead int
+	for _, h := range peerHeights {
+		if h+configPartitionHeightGap < height {
+			behind++
+		} else if h > height+configPartitionHeightGap {
+			ahead++
+		}
+	}
+	switch len(peerHeights) {
+	case behind:
+		return module.PartitionPeersBehind
+	case ahead:
+		return module.PartitionPeersAhead
+	default:
+		return module.PartitionNone
+	}
+}
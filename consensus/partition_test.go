@@ -0,0 +1,33 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestPartitionOf(t *testing.T) {
+	const height = 100
+	const gap = configPartitionHeightGap
+	tests := []struct {
+		name    string
+		heights []int64
+		want    module.Partition
+	}{
+		{"NoPeers", nil, module.PartitionNone},
+		{"Synced", []int64{height, height - 1, height + 1}, module.PartitionNone},
+		{"InGap", []int64{height - gap, height + gap}, module.PartitionNone},
+		{"AllBehind", []int64{height - gap - 1, 1}, module.PartitionPeersBehind},
+		{"AllAhead", []int64{height + gap + 1, height * 2}, module.PartitionPeersAhead},
+		{"SomeBehind", []int64{height - gap - 1, height}, module.PartitionNone},
+		{"SomeAhead", []int64{height + gap + 1, height}, module.PartitionNone},
+		{"BehindAndAhead", []int64{height - gap - 1, height + gap + 1}, module.PartitionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, partitionOf(height, tt.heights))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// RandomnessOf returns the randomness of the block. It's SHA3-256 hash of
+// the ID of the previous block followed by the signatures of the commit
+// votes for the previous block in the block, which are sorted in byte
+// order. Anyone having the block can reproduce it.
+//
+// It's biasable. The proposer chooses which votes to include, and each
+// validator may change the signature of its vote by choosing the timestamp
+// of the vote or the nonce of the signature. So it must not be used where
+// a value depends on it, e.g. lotteries.
+func RandomnessOf(blk module.BlockData) ([]byte, error) {
+	vl, ok := blk.Votes().(*CommitVoteList)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("UnknownVotes(%T)", blk.Votes())
+	}
+	sigs := make([][]byte, 0, len(vl.Items))
+	for _, item := range vl.Items {
+		sig, err := item.Signature.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	sort.Slice(sigs, func(i, j int) bool {
+		return bytes.Compare(sigs[i], sigs[j]) < 0
+	})
+	buf := bytes.NewBuffer(nil)
+	buf.Write(blk.PrevID())
+	for _, sig := range sigs {
+		buf.Write(sig)
+	}
+	return crypto.SHA3Sum256(buf.Bytes()), nil
+}
@@ -0,0 +1,63 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+type testRandomnessBlock struct {
+	module.BlockData
+	prevID []byte
+	votes  module.CommitVoteSet
+}
+
+func (b *testRandomnessBlock) PrevID() []byte {
+	return b.prevID
+}
+
+func (b *testRandomnessBlock) Votes() module.CommitVoteSet {
+	return b.votes
+}
+
+func TestRandomnessOf(t *testing.T) {
+	prevID := []byte("prevID")
+	msgs := []*VoteMessage{
+		NewPrecommitMessage(wallet.New(), 1, 0, prevID, nil, 1),
+		NewPrecommitMessage(wallet.New(), 1, 0, prevID, nil, 2),
+		NewPrecommitMessage(wallet.New(), 1, 0, prevID, nil, 3),
+	}
+	cvl1, err := newCommitVoteList(nil, msgs)
+	assert.NoError(t, err)
+	cvl2, err := newCommitVoteList(nil, []*VoteMessage{msgs[2], msgs[0], msgs[1]})
+	assert.NoError(t, err)
+
+	// it doesn't depend on the order of the votes
+	r1, err := RandomnessOf(&testRandomnessBlock{prevID: prevID, votes: cvl1})
+	assert.NoError(t, err)
+	assert.Len(t, r1, 32)
+	r2, err := RandomnessOf(&testRandomnessBlock{prevID: prevID, votes: cvl2})
+	assert.NoError(t, err)
+	assert.Equal(t, r1, r2)
+
+	// but the votes included
+	cvl3, err := newCommitVoteList(nil, msgs[:2])
+	assert.NoError(t, err)
+	r3, err := RandomnessOf(&testRandomnessBlock{prevID: prevID, votes: cvl3})
+	assert.NoError(t, err)
+	assert.NotEqual(t, r1, r3)
+
+	// decoded votes give the same
+	r4, err := RandomnessOf(&testRandomnessBlock{
+		prevID: prevID,
+		votes:  NewCommitVoteSetFromBytes(cvl1.Bytes()),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, r1, r4)
+
+	_, err = RandomnessOf(&testRandomnessBlock{prevID: prevID})
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package consensus
+
+import (
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configRoundHistoryCap = 100
+)
+
+// reasons of round failures
+const (
+	roundFailureNoProposal             = "NoProposal"
+	roundFailureInsufficientPrevotes   = "InsufficientPrevotes"
+	roundFailureNilPrevotes            = "NilPrevotes"
+	roundFailureInsufficientPrecommits = "InsufficientPrecommits"
+	roundFailureNilPrecommits          = "NilPrecommits"
+	roundFailureSkipped                = "Skipped"
+)
+
+// roundHistory keeps history of recent heights which needed more than
+// one round.
+type roundHistory struct {
+	failures []module.RoundFailure
+	history  []*module.RoundHistory
+}
+
+func (h *roundHistory) addFailure(f module.RoundFailure) {
+	h.failures = append(h.failures, f)
+}
+
+// commit records failures of rounds lower than the commit round and
+// resets failures for the next height. It returns the record if there
+// are failures.
+func (h *roundHistory) commit(height int64, round int32) *module.RoundHistory {
+	var failures []module.RoundFailure
+	for _, f := range h.failures {
+		if f.Round < round {
+			failures = append(failures, f)
+		}
+	}
+	h.failures = nil
+	if len(failures) == 0 {
+		return nil
+	}
+	rh := &module.RoundHistory{
+		Height:      height,
+		CommitRound: round,
+		Failures:    failures,
+	}
+	if len(h.history) >= configRoundHistoryCap {
+		copy(h.history, h.history[1:])
+		h.history = h.history[:len(h.history)-1]
+	}
+	h.history = append(h.history, rh)
+	return rh
+}
+
+func (h *roundHistory) since(height int64) []*module.RoundHistory {
+	res := make([]*module.RoundHistory, 0)
+	for _, rh := range h.history {
+		if rh.Height >= height {
+			res = append(res, rh)
+		}
+	}
+	return res
+}
+
+func (cs *consensus) proposerFor(round int32) module.Address {
+	v, ok := cs.validators.Get(cs.getProposerIndex(cs.height, round))
+	if !ok {
+		return nil
+	}
+	return v.Address()
+}
+
+// roundFailureReason returns the reason why the current round failed.
+func (cs *consensus) roundFailureReason() string {
+	noProposal := !cs.currentBlockParts.IsComplete() && cs.lockedBlockParts.IsZero()
+	prevotes := cs.hvs.votesFor(cs.round, VoteTypePrevote)
+	psid, ok := prevotes.getOverTwoThirdsPartSetID()
+	if !ok || psid == nil {
+		if noProposal {
+			return roundFailureNoProposal
+		}
+		if !ok {
+			return roundFailureInsufficientPrevotes
+		}
+		return roundFailureNilPrevotes
+	}
+	precommits := cs.hvs.votesFor(cs.round, VoteTypePrecommit)
+	if psid, ok := precommits.getOverTwoThirdsPartSetID(); ok && psid == nil {
+		return roundFailureNilPrecommits
+	}
+	return roundFailureInsufficientPrecommits
+}
+
+// onRoundFailure records failures of the current round and the rounds
+// skipped before the round.
+func (cs *consensus) onRoundFailure(round int32) {
+	if cs.step >= stepCommit {
+		return
+	}
+	cs.rh.addFailure(module.RoundFailure{
+		Round:    cs.round,
+		Proposer: cs.proposerFor(cs.round),
+		Reason:   cs.roundFailureReason(),
+	})
+	for r := cs.round + 1; r < round; r++ {
+		cs.rh.addFailure(module.RoundFailure{
+			Round:    r,
+			Proposer: cs.proposerFor(r),
+			Reason:   roundFailureSkipped,
+		})
+	}
+}
+
+func (cs *consensus) onCommitRound() {
+	if rh := cs.rh.commit(cs.height, cs.commitRound); rh != nil {
+		for _, f := range rh.Failures {
+			cs.metric.OnRoundFailure(f.Reason)
+		}
+	}
+}
+
+func (cs *consensus) GetRoundHistory(height int64) []*module.RoundHistory {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	return cs.rh.since(height)
+}
@@ -0,0 +1,39 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestRoundHistory(t *testing.T) {
+	var rh roundHistory
+
+	// nothing recorded for a height committed in the first round
+	assert.Nil(t, rh.commit(1, 0))
+
+	rh.addFailure(module.RoundFailure{Round: 0, Reason: roundFailureNoProposal})
+	rh.addFailure(module.RoundFailure{Round: 1, Reason: roundFailureSkipped})
+	rh.addFailure(module.RoundFailure{Round: 2, Reason: roundFailureInsufficientPrevotes})
+	// committed with votes of the round 2
+	r := rh.commit(2, 2)
+	assert.NotNil(t, r)
+	assert.EqualValues(t, 2, r.Height)
+	assert.EqualValues(t, 2, r.CommitRound)
+	assert.Len(t, r.Failures, 2)
+	assert.Equal(t, roundFailureSkipped, r.Failures[1].Reason)
+
+	// failures are reset for the next height
+	assert.Nil(t, rh.commit(3, 0))
+
+	for h := int64(4); h < 4+configRoundHistoryCap; h++ {
+		rh.addFailure(module.RoundFailure{Round: 0, Reason: roundFailureNilPrecommits})
+		assert.NotNil(t, rh.commit(h, 1))
+	}
+	all := rh.since(0)
+	assert.Len(t, all, configRoundHistoryCap)
+	assert.EqualValues(t, 4, all[0].Height)
+	assert.Len(t, rh.since(4+configRoundHistoryCap-2), 2)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 const (
@@ -41,6 +42,8 @@ type Syncer interface {
 	Start() error
 	Stop()
 	OnEngineStepChange()
+	Partition() module.Partition
+	SetServing(serving bool)
 }
 
 var SyncerProtocols = []module.ProtocolInfo{
@@ -58,6 +61,7 @@ type peer struct {
 
 	running bool
 	*peerRoundState
+	roundStateTime time.Time
 }
 
 func newPeer(syncer *syncer, id module.PeerID) *peer {
@@ -76,6 +80,7 @@ func newPeer(syncer *syncer, id module.PeerID) *peer {
 
 func (p *peer) setRoundState(prs *peerRoundState) {
 	p.peerRoundState = prs
+	p.roundStateTime = time.Now()
 	p.wakeUp()
 }
 
@@ -254,6 +259,7 @@ type syncer struct {
 	mutex  *common.Mutex
 	addr   module.Address
 	fsm    fastsync.Manager
+	mtr    *metric.ConsensusMetric
 
 	ph            module.ProtocolHandler
 	peers         []*peer
@@ -261,9 +267,10 @@ type syncer struct {
 	lastSendTime  time.Time
 	running       bool
 	fetchCanceler func() bool
+	partition     module.Partition
 }
 
-func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address) (Syncer, error) {
+func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address, mtr *metric.ConsensusMetric) (Syncer, error) {
 	fsm, err := fastsync.NewManager(nm, bm, e, logger)
 	if err != nil {
 		return nil, err
@@ -277,6 +284,7 @@ func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.
 		mutex:  mutex,
 		addr:   addr,
 		fsm:    fsm,
+		mtr:    mtr,
 	}, nil
 }
 
@@ -338,6 +346,7 @@ func (s *syncer) OnReceive(sp module.ProtocolInfo, bs []byte,
 				p.setRoundState(&m.peerRoundState)
 			}
 		}
+		s.updatePartition()
 	case *voteListMessage:
 		err = s.engine.ReceiveVoteListMessage(m, true)
 		if err != nil {
@@ -404,6 +413,7 @@ func (s *syncer) OnLeave(id module.PeerID) {
 			s.peers[last] = nil
 			s.peers = s.peers[:last]
 			p.stop()
+			s.updatePartition()
 			return
 		}
 	}
@@ -428,6 +438,49 @@ func (s *syncer) OnEngineStepChange() {
 	if send {
 		s.sendRoundStateMessage()
 	}
+	if e.Step() == stepCommit {
+		s.updatePartition()
+	}
+}
+
+// SetServing starts or stops serving blocks to the peers.
+func (s *syncer) SetServing(serving bool) {
+	if serving {
+		s.fsm.StartServer()
+	} else {
+		s.fsm.StopServer()
+	}
+}
+
+// Partition returns the probable partition comparing the height of the
+// engine with the heights in the recent round states of the peers.
+func (s *syncer) Partition() module.Partition {
+	now := time.Now()
+	var heights []int64
+	for _, p := range s.peers {
+		if p.peerRoundState != nil && now.Sub(p.roundStateTime) < configPeerRoundStateTimeout {
+			heights = append(heights, p.Height)
+		}
+	}
+	return partitionOf(s.engine.Height(), heights)
+}
+
+func (s *syncer) updatePartition() {
+	partition := s.Partition()
+	if partition == s.partition {
+		return
+	}
+	if partition != module.PartitionNone {
+		s.log.Warnf("Probable network partition=%s height=%d peers=%d\n",
+			partition, s.engine.Height(), len(s.peers))
+	} else {
+		s.log.Infof("Network partition=%s resolved height=%d\n",
+			s.partition, s.engine.Height())
+	}
+	if s.mtr != nil {
+		s.mtr.OnPartition(string(s.partition), string(partition))
+	}
+	s.partition = partition
 }
 
 func (s *syncer) doSendRoundStateMessage(id module.PeerID) {
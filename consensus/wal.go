@@ -50,11 +50,25 @@ type WALReader interface {
 	CloseAndRepair() error
 }
 
+// WALCompactor removes data of old heights from WAL.
+type WALCompactor interface {
+	// Compact starts a new file for the next height, and removes the
+	// files before the last retention heights.
+	Compact(retention int) error
+}
+
+// WALMetric receives the size of WAL and the latency of fsync.
+type WALMetric interface {
+	OnWALSize(id string, size int64)
+	OnWALSync(id string, d time.Duration)
+}
+
 type WALConfig struct {
 	FileLimit            int64
 	TotalLimit           int64
 	HousekeepingInterval time.Duration
 	SyncInterval         time.Duration
+	Metric               WALMetric
 }
 
 type file struct {
@@ -69,6 +83,8 @@ type walWriter struct {
 	tail             file
 	tailIdx          uint64
 	eldestUnsyncData *time.Time
+	written          bool
+	marks            []uint64
 
 	ticker        *time.Ticker
 	tickerStop    chan struct{}
@@ -205,9 +221,12 @@ func (w *walWriter) WriteBytes(payload []byte) (int, error) {
 	copy(frame[headerLen:], payload)
 	//log.Printf("wal write crc=%x payloadLen:%v payload:%x\n", crc, payloadLen, payload)
 	n, err := w.buf.Write(frame)
-	if err == nil && w.eldestUnsyncData == nil {
-		now := time.Now()
-		w.eldestUnsyncData = &now
+	if err == nil {
+		w.written = true
+		if w.eldestUnsyncData == nil {
+			now := time.Now()
+			w.eldestUnsyncData = &now
+		}
 	}
 	return n, err
 }
@@ -232,9 +251,13 @@ func (w *walWriter) sync() error {
 	if err := w.buf.Flush(); err != nil {
 		return errors.WithStack(err)
 	}
+	start := time.Now()
 	if err := w.tail.Sync(); err != nil {
 		return err
 	}
+	if w.cfg.Metric != nil {
+		w.cfg.Metric.OnWALSync(filepath.Base(w.id), time.Since(start))
+	}
 	w.eldestUnsyncData = nil
 	return nil
 }
@@ -310,6 +333,41 @@ func (w *walWriter) shift() error {
 	return nil
 }
 
+func (w *walWriter) Compact(retention int) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if retention < 0 || !w.written {
+		return nil
+	}
+	if err := w.shift(); err != nil {
+		return err
+	}
+	w.written = false
+	w.marks = append(w.marks, w.tailIdx)
+	if len(w.marks) <= retention {
+		return nil
+	}
+	keep := w.marks[len(w.marks)-1-retention]
+	w.marks = w.marks[len(w.marks)-1-retention:]
+
+	wi, err := readWALInfo(w.id)
+	if err != nil {
+		return err
+	}
+	for idx := wi.headIdx; idx < keep && idx <= wi.tailIdx; idx++ {
+		size := wi.fileSizes[idx-wi.headIdx]
+		if err := os.Remove(fileFor(w.id, idx)); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+		wi.totalSize -= size
+	}
+	if w.cfg.Metric != nil {
+		w.cfg.Metric.OnWALSize(filepath.Base(w.id), wi.totalSize)
+	}
+	return nil
+}
+
 func (w *walWriter) doHousekeeping() {
 	wi, err := readWALInfo(w.id)
 	if err != nil {
@@ -347,6 +405,9 @@ func (w *walWriter) doHousekeeping() {
 		wi.totalSize -= fInfo.Size()
 		wi.headIdx++
 	}
+	if w.cfg.Metric != nil {
+		w.cfg.Metric.OnWALSize(filepath.Base(w.id), wi.totalSize)
+	}
 }
 
 type walReader struct {
@@ -72,3 +72,57 @@ func TestWAL(t *testing.T) {
 	err = wr.Close()
 	assert.NoError(t, err)
 }
+
+type testWALMetric struct {
+	size  int64
+	syncs int
+}
+
+func (m *testWALMetric) OnWALSize(id string, size int64) {
+	m.size = size
+}
+
+func (m *testWALMetric) OnWALSync(id string, d time.Duration) {
+	m.syncs++
+}
+
+func TestWAL_Compact(t *testing.T) {
+	base, err := os.MkdirTemp("", "goloop-waltest")
+	assert.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(base)
+	}()
+	id := base + "/testwal"
+	mtr := new(testWALMetric)
+	ww, err := consensus.OpenWALForWrite(id, &consensus.WALConfig{
+		HousekeepingInterval: time.Hour,
+		Metric:               mtr,
+	})
+	assert.NoError(t, err)
+	wc := ww.(consensus.WALCompactor)
+
+	// heights 0, 1 and 2 with retention 1
+	for h := 0; h < 3; h++ {
+		assert.NoError(t, consensus.WALWriteObject(ww, h))
+		assert.NoError(t, wc.Compact(1))
+	}
+	// nothing to compact without writes
+	assert.NoError(t, wc.Compact(1))
+	assert.True(t, mtr.syncs > 0)
+	assert.True(t, mtr.size > 0)
+
+	assert.NoError(t, consensus.WALWriteObject(ww, 3))
+	assert.NoError(t, ww.Close())
+
+	wr, err := consensus.OpenWALForRead(id)
+	assert.NoError(t, err)
+	for _, exp := range []int{2, 3} {
+		var v int
+		_, err := consensus.WALReadObject(wr, &v)
+		assert.NoError(t, err)
+		assert.EqualValues(t, exp, v)
+	}
+	_, err = wr.ReadBytes()
+	assert.True(t, consensus.IsEOF(err))
+	assert.NoError(t, wr.Close())
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blockv0
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Names of the rules checked on verification of legacy blocks.
+const (
+	RuleBlockHash            = "blockHash"
+	RulePrevID               = "prevID"
+	RuleSignature            = "signature"
+	RuleTransactionRoot      = "transactionRoot"
+	RuleTransactionSignature = "transactionSignature"
+	RuleRepsHash             = "repsHash"
+	RuleLeaderVotes          = "leaderVotes"
+	RulePrevVotes            = "prevVotes"
+	RuleLeader               = "leader"
+	RuleTimestamp            = "timestamp"
+)
+
+// RulesOf returns names of the rules checked by Verify, or by VerifyStrict
+// if strict is true, for the block following the prev.
+func RulesOf(b Block, prev Block, strict bool) []string {
+	var rules []string
+	switch b.(type) {
+	case *BlockV01a:
+		rules = []string{
+			RuleBlockHash,
+			RuleTransactionRoot,
+		}
+		if b.Height() > 0 {
+			rules = append(rules, RuleSignature)
+			if prev != nil {
+				rules = append(rules, RulePrevID)
+			}
+		}
+		if strict {
+			rules = append(rules, RuleTransactionSignature)
+		}
+	case *BlockV03:
+		rules = []string{
+			RuleBlockHash,
+			RuleTransactionRoot,
+			RuleTransactionSignature,
+			RuleRepsHash,
+			RuleLeaderVotes,
+			RulePrevVotes,
+		}
+		if _, ok := prev.(*BlockV03); ok {
+			rules = append(rules, RuleLeader)
+		}
+	}
+	if strict && prev != nil {
+		rules = append(rules, RuleTimestamp)
+	}
+	return rules
+}
+
+// VerifyStrict verifies the block with the rules skipped by Verify for
+// compatibility with the history of loopchain.
+func VerifyStrict(b Block, prev Block) error {
+	if err := b.Verify(prev); err != nil {
+		return err
+	}
+	if _, ok := b.(*BlockV01a); ok {
+		for _, tx := range b.NormalTransactions() {
+			if err := tx.Verify(); err != nil {
+				return errors.CriticalFormatError.Wrapf(err,
+					"InvalidTransaction(id=%#x)", tx.ID())
+			}
+		}
+	}
+	if prev != nil && b.Timestamp() <= prev.Timestamp() {
+		return errors.CriticalFormatError.Errorf(
+			"InvalidTimestamp(prev=%d,ts=%d)", prev.Timestamp(), b.Timestamp())
+	}
+	return nil
+}
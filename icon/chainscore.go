@@ -498,6 +498,14 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getTermSummary",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionTermSummary, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getPRepStats",
 		scoreapi.FlagReadOnly, 0,
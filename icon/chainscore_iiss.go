@@ -493,6 +493,22 @@ func (s *chainScore) Ex_getPRepTerm() (map[string]interface{}, error) {
 	return jso, nil
 }
 
+func (s *chainScore) Ex_getTermSummary() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	blockHeight := s.cc.BlockHeight()
+	jso, err := es.GetTermSummaryInJSON(blockHeight)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "Failed to get TermSummary")
+	}
+	return jso, nil
+}
+
 func (s *chainScore) Ex_getNetworkInfo() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -117,6 +117,16 @@ func (c *wrapper) GetVotesByHeight(height int64) (module.CommitVoteSet, error) {
 	return c.Consensus.GetVotesByHeight(height)
 }
 
+func (c *wrapper) GetRoundHistory(height int64) []*module.RoundHistory {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Consensus == nil {
+		return []*module.RoundHistory{}
+	}
+	return c.Consensus.GetRoundHistory(height)
+}
+
 func (c *wrapper) Upgrade(bpp *bpp) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -137,6 +137,10 @@ func (f *fastSyncer) GetVotesByHeight(height int64) (module.CommitVoteSet, error
 	return nil, errors.NotFoundError.New("not found")
 }
 
+func (f *fastSyncer) GetRoundHistory(height int64) []*module.RoundHistory {
+	return []*module.RoundHistory{}
+}
+
 func (f *fastSyncer) GetBlockProof(height int64, opt int32) ([]byte, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
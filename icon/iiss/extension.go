@@ -91,6 +91,43 @@ func (s *ExtensionSnapshotImpl) RLPDecodeSelf(d codec.Decoder) error {
 	return nil
 }
 
+// Names of the stores in the extension data
+const (
+	StoreState  = "state"
+	StoreFront  = "front"
+	StoreBack1  = "back1"
+	StoreBack2  = "back2"
+	StoreReward = "reward"
+)
+
+type storeWithProof interface {
+	Bytes() []byte
+	GetWithProof(key []byte) ([]byte, [][]byte, error)
+}
+
+func (s *ExtensionSnapshotImpl) GetProof(name string, key []byte) ([]byte, []byte, [][]byte, error) {
+	var store storeWithProof
+	switch name {
+	case StoreState:
+		store = s.state
+	case StoreFront:
+		store = s.front
+	case StoreBack1:
+		store = s.back1
+	case StoreBack2:
+		store = s.back2
+	case StoreReward:
+		store = s.reward
+	default:
+		return nil, nil, nil, errors.IllegalArgumentError.Errorf("UnknownStore(name=%s)", name)
+	}
+	value, proof, err := store.GetWithProof(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return store.Bytes(), value, proof, nil
+}
+
 func (s *ExtensionSnapshotImpl) Flush() error {
 	if err := s.state.Flush(); err != nil {
 		return err
@@ -1165,6 +1202,24 @@ func (es *ExtensionStateImpl) GetPRepTermInJSON(blockHeight int64) (map[string]i
 	return jso, nil
 }
 
+// GetTermSummaryInJSON returns the summary of the current term for staking
+// services, with the result of the last reward calculation.
+func (es *ExtensionStateImpl) GetTermSummaryInJSON(blockHeight int64) (map[string]interface{}, error) {
+	term := es.State.GetTermSnapshot()
+	if term == nil {
+		err := errors.Errorf("Term is nil")
+		return nil, err
+	}
+	jso := term.SummaryToJSON(es.State)
+	rcInfo, err := es.State.GetRewardCalcInfo()
+	if err != nil {
+		return nil, err
+	}
+	jso["rcResult"] = rcInfo.GetResultInJSON()
+	jso["blockHeight"] = blockHeight
+	return jso, nil
+}
+
 func (es *ExtensionStateImpl) IsDecentralized() bool {
 	term := es.State.GetTermSnapshot()
 	return term != nil && term.IsDecentralized()
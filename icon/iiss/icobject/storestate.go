@@ -17,6 +17,7 @@
 package icobject
 
 import (
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/trie"
 )
 
@@ -52,6 +53,23 @@ func (o *ObjectStoreSnapshot) Delete(_ []byte) (trie.Object, error) {
 	panic("invalid usage")
 }
 
+// GetWithProof returns the encoded object for the key, and the proof of it
+// for the root of the store.
+func (o *ObjectStoreSnapshot) GetWithProof(key []byte) ([]byte, [][]byte, error) {
+	obj, err := o.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj == nil {
+		return nil, nil, errors.NotFoundError.Errorf("NoValue(key=%#x)", key)
+	}
+	proof := o.GetProof(key)
+	if proof == nil {
+		return nil, nil, errors.InvalidStateError.Errorf("NoProof(key=%#x)", key)
+	}
+	return obj.Bytes(), proof, nil
+}
+
 func NewObjectStoreSnapshot(t trie.ImmutableForObject) *ObjectStoreSnapshot {
 	return &ObjectStoreSnapshot{t, bytesConverter{}}
 }
@@ -67,3 +67,36 @@ func TestNewObjectStoreState(t *testing.T) {
 	assert.Equal(t, "Test", array3.Get(0).String())
 	assert.Equal(t, int64(1), array3.Get(1).Int64())
 }
+
+func TestObjectStoreSnapshot_GetWithProof(t *testing.T) {
+	database := db.NewMapDB()
+	database = AttachObjectFactory(database, testFactory)
+	tree := trie_manager.NewMutableForObject(database, nil, ObjectType)
+
+	oss := NewObjectStoreState(tree)
+	keys := [][]byte{[]byte("key1"), []byte("key2"), []byte("other")}
+	for i, key := range keys {
+		_, err := oss.Set(key, NewBytesObject([]byte{byte(i)}))
+		assert.NoError(t, err)
+	}
+	ss := tree.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+
+	snapshot := NewObjectStoreSnapshot(ss)
+	for i, key := range keys {
+		value, proof, err := snapshot.GetWithProof(key)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, proof)
+
+		// verify with the root only
+		verifier := trie_manager.NewImmutableForObject(
+			AttachObjectFactory(db.NewMapDB(), testFactory), ss.Hash(), ObjectType)
+		obj, err := verifier.Prove(key, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, value, obj.Bytes())
+		assert.Equal(t, []byte{byte(i)}, obj.(*Object).BytesValue())
+	}
+
+	_, _, err := snapshot.GetWithProof([]byte("none"))
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
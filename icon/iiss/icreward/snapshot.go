@@ -40,6 +40,10 @@ func (ss *Snapshot) Bytes() []byte {
 	return ss.store.Hash()
 }
 
+func (ss *Snapshot) GetWithProof(key []byte) ([]byte, [][]byte, error) {
+	return ss.store.GetWithProof(key)
+}
+
 func (ss *Snapshot) GetDSA() (*DSA, error) {
 	if sso, ok := ss.store.ImmutableForObject.(trie.SnapshotForObject); ok {
 		obj, err := sso.Get(DSAKey)
@@ -44,6 +44,10 @@ func (ss *Snapshot) Bytes() []byte {
 	return ss.store.Hash()
 }
 
+func (ss *Snapshot) GetWithProof(key []byte) ([]byte, [][]byte, error) {
+	return ss.store.GetWithProof(key)
+}
+
 func (ss *Snapshot) Filter(prefix []byte) trie.IteratorForObject {
 	return ss.store.Filter(prefix)
 }
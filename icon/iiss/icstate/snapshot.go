@@ -54,6 +54,10 @@ func (ss *Snapshot) GetValue(key []byte) ([]byte, error) {
 	return value, nil
 }
 
+func (ss *Snapshot) GetWithProof(key []byte) ([]byte, [][]byte, error) {
+	return ss.store.GetWithProof(key)
+}
+
 func (ss *Snapshot) GetRewardCalcInfo() (*RewardCalcInfo, error) {
 	obj, err := ss.store.Get(RewardCalcInfoKey)
 	if err != nil {
@@ -315,6 +315,126 @@ func (term *termData) prepsToJSON(blockHeight int64, state *State) []interface{}
 	return jso
 }
 
+// SummaryToJSON returns the summary of the term for staking services.
+// It includes the current bonded and delegated amounts of P-Reps, the reward
+// fund for the term and the estimated voted rewards of P-Reps.
+func (term *termData) SummaryToJSON(state *State) map[string]interface{} {
+	return map[string]interface{}{
+		"sequence":         term.sequence,
+		"startBlockHeight": term.startHeight,
+		"endBlockHeight":   term.GetEndHeight(),
+		"period":           term.period,
+		"revision":         term.revision,
+		"iissVersion":      term.GetIISSVersion(),
+		"isDecentralized":  term.isDecentralized,
+		"totalSupply":      term.totalSupply,
+		"totalDelegated":   term.totalDelegated,
+		"totalPower":       term.getTotalPower(),
+		"bondRequirement":  term.bondRequirement,
+		"mainPRepCount":    term.mainPRepCount,
+		"totalStake":       state.GetTotalStake(),
+		"totalBonded":      state.GetTotalBond(),
+		"totalDelegation":  state.GetTotalDelegation(),
+		"rewardFund":       term.rewardFundToJSON(),
+		"preps":            term.prepSummariesToJSON(state),
+	}
+}
+
+// rewardFundToJSON returns the reward fund with the amounts for the term.
+// Reward fund of IISS 3 is monthly, and it's divided by the period of the term.
+func (term *termData) rewardFundToJSON() map[string]interface{} {
+	if term.GetIISSVersion() == IISSVersion2 {
+		return map[string]interface{}{
+			"irep": term.irep,
+			"rrep": term.rrep,
+		}
+	}
+	rf := term.rewardFund
+	jso := rf.ToJSON()
+	divider := big.NewInt(100 * icmodule.MonthBlock)
+	forTerm := func(ix *big.Int) *big.Int {
+		v := new(big.Int).Mul(rf.Iglobal, ix)
+		v.Mul(v, big.NewInt(term.period))
+		return v.Div(v, divider)
+	}
+	jso["prepFund"] = forTerm(rf.Iprep)
+	jso["cpsFund"] = forTerm(rf.Icps)
+	jso["relayFund"] = forTerm(rf.Irelay)
+	jso["voterFund"] = forTerm(rf.Ivoter)
+	return jso
+}
+
+func (term *termData) prepSummariesToJSON(state *State) []interface{} {
+	br := state.GetBondRequirement()
+	rewards := term.EstimateVotedRewards()
+	jso := make([]interface{}, 0, len(term.prepSnapshots))
+	for i, pss := range term.prepSnapshots {
+		prep := map[string]interface{}{
+			"address":     pss.Owner(),
+			"power":       pss.Power(),
+			"votedReward": rewards[i],
+		}
+		if p := state.GetPRepByOwner(pss.Owner()); p != nil {
+			if pb := p.getPRepBaseState(); pb != nil {
+				prep["name"] = pb.Name()
+			}
+			prep["grade"] = int(p.Grade())
+			prep["bonded"] = p.Bonded()
+			prep["delegated"] = p.Delegated()
+			prep["currentPower"] = p.GetPower(br)
+		}
+		jso = append(jso, prep)
+	}
+	return jso
+}
+
+// EstimateVotedRewards returns the voted rewards of P-Reps in the snapshot
+// for the term in IScore, in the same order of the snapshot.
+// It's calculated in the same way of the reward calculator assuming that
+// no P-Rep is disabled during the term, so it may differ from the actual
+// rewards.
+func (term *termData) EstimateVotedRewards() []*big.Int {
+	rewards := make([]*big.Int, len(term.prepSnapshots))
+	for i := range rewards {
+		rewards[i] = new(big.Int)
+	}
+	multiplier, divider := term.varForVotedReward()
+	totalPower := term.getTotalPower()
+	if multiplier.Sign() == 0 || divider.Sign() == 0 || totalPower.Sign() == 0 {
+		return rewards
+	}
+	base := new(big.Int).Mul(multiplier, big.NewInt(term.period))
+	for i, pss := range term.prepSnapshots {
+		rewards[i].Mul(base, pss.Power())
+		rewards[i].Div(rewards[i], divider)
+		rewards[i].Div(rewards[i], totalPower)
+	}
+	return rewards
+}
+
+// varForVotedReward returns the variables for voted reward of P-Reps.
+// See varForVotedReward in iiss/calculator.go.
+func (term *termData) varForVotedReward() (multiplier, divider *big.Int) {
+	multiplier = new(big.Int)
+	divider = big.NewInt(1)
+	if term.GetIISSVersion() == IISSVersion2 {
+		if term.irep == nil {
+			return
+		}
+		multiplier.Mul(term.irep, big.NewInt(icmodule.MonthPerYear))
+		multiplier.Div(multiplier, big.NewInt(int64(icmodule.YearBlock*2)))
+		multiplier.Mul(multiplier, big.NewInt(int64(icmodule.VotedRewardMultiplier*icmodule.IScoreICXRatio)))
+	} else {
+		if term.rewardFund == nil {
+			return
+		}
+		multiplier.Mul(term.rewardFund.Iglobal, term.rewardFund.Iprep)
+		multiplier.Mul(multiplier, icmodule.BigIntIScoreICXRatio)
+		divider.SetInt64(int64(100 * icmodule.MonthBlock))
+	}
+	return
+}
+
 func (term *termData) getTotalPower() *big.Int {
 	totalPower := new(big.Int)
 	for _, snapshot := range term.prepSnapshots {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
@@ -208,3 +209,34 @@ func TestTerm_TotalBondedDelegation(t *testing.T) {
 		assert.Equal(t, ps, prepSnapshots[i])
 	}
 }
+
+func TestTerm_EstimateVotedRewards(t *testing.T) {
+	// IISS 2.0
+	term := newTermState(0, 10)
+	term.irep = big.NewInt(icmodule.YearBlock * 2)
+	term.SetPRepSnapshots(newDummyPRepSnapshots(3))
+	rewards := term.EstimateVotedRewards()
+	assert.Equal(t, []*big.Int{
+		big.NewInt(6_000_000), big.NewInt(4_000_000), big.NewInt(2_000_000),
+	}, rewards)
+
+	// IISS 3.1
+	term = newTermState(0, 10)
+	term.revision = icmodule.RevisionEnableIISS3
+	term.rewardFund.Iglobal = big.NewInt(100 * icmodule.MonthBlock)
+	term.rewardFund.Iprep = big.NewInt(50)
+	term.SetPRepSnapshots(newDummyPRepSnapshots(3))
+	rewards = term.EstimateVotedRewards()
+	assert.Equal(t, []*big.Int{
+		big.NewInt(250_000), big.NewInt(166_666), big.NewInt(83_333),
+	}, rewards)
+
+	jso := term.rewardFundToJSON()
+	assert.Equal(t, big.NewInt(500), jso["prepFund"])
+	assert.Equal(t, big.NewInt(0), jso["voterFund"])
+
+	// no P-Reps
+	term = newTermState(0, 10)
+	term.revision = icmodule.RevisionEnableIISS3
+	assert.Len(t, term.EstimateVotedRewards(), 0)
+}
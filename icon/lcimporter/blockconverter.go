@@ -151,17 +151,17 @@ func BlockIndexKey(height int64) []byte {
 	return codec.BC.MustMarshalToBytes(height)
 }
 
-func (e *BlockConverter) GetBlockByHeight(h int64) (*blockv1.Block, error) {
-	hash, err := e.blkIndex.Get(BlockIndexKey(h))
+func getBlockV1ByHeight(dbase db.Database, blkIndex, blkByHash db.Bucket, h int64) (*blockv1.Block, error) {
+	hash, err := blkIndex.Get(BlockIndexKey(h))
 	if err != nil {
 		return nil, err
 	}
 	if len(hash) > 0 {
-		bs, err := e.blkByHash.Get(hash)
+		bs, err := blkByHash.Get(hash)
 		if err != nil {
 			return nil, err
 		}
-		blk, err := blockv1.NewBlockFromHeaderReader(e.database,
+		blk, err := blockv1.NewBlockFromHeaderReader(dbase,
 			bytes.NewReader(bs))
 		if err != nil {
 			return nil, err
@@ -171,6 +171,10 @@ func (e *BlockConverter) GetBlockByHeight(h int64) (*blockv1.Block, error) {
 	return nil, nil
 }
 
+func (e *BlockConverter) GetBlockByHeight(h int64) (*blockv1.Block, error) {
+	return getBlockV1ByHeight(e.database, e.blkIndex, e.blkByHash, h)
+}
+
 func (e *BlockConverter) OnLog(level module.TraceLevel, msg string) {
 	switch level {
 	case module.TSystemLevel:
@@ -278,6 +282,13 @@ func (e *BlockConverter) proposeTransition(last *Transition) (*Transition, error
 	if err := blkv0.Verify(last.block); err != nil {
 		return nil, err
 	}
+	if err := recordVerification(e.database, &VerificationRange{
+		From:  height,
+		To:    height,
+		Rules: importRulesOf(blkv0, last.block, false),
+	}); err != nil {
+		return nil, err
+	}
 	var rcts []txresult.Receipt
 	if last.block != nil {
 		txs := last.block.NormalTransactions()
@@ -36,6 +36,7 @@ import (
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/transaction"
 	"github.com/icon-project/goloop/service/txresult"
+	"github.com/icon-project/goloop/test"
 )
 
 type BTX = lcimporter.BlockTransaction
@@ -84,6 +85,10 @@ func (c *testChain) PatchTxPoolSize() int {
 	return 2
 }
 
+func (c *testChain) PriorityTxPoolSize() int {
+	return 2
+}
+
 func (c *testChain) MaxBlockTxBytes() int {
 	return 2 * 1024 * 1024
 }
@@ -96,6 +101,14 @@ func (c *testChain) Regulator() module.Regulator {
 	return c.regulator
 }
 
+func (c *testChain) Quota() module.Quota {
+	return test.NewQuota()
+}
+
+func (c *testChain) AccessIndex() bool {
+	return false
+}
+
 func (c *testChain) Logger() log.Logger {
 	return c.log
 }
@@ -204,6 +204,10 @@ func (sm *ServiceManager) SendTransaction(result []byte, height int64, tx interf
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) ValidateTransaction(result []byte, height int64, tx interface{}) ([]error, error) {
+	return nil, errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) SendPatch(patch module.Patch) error {
 	return errors.ErrInvalidState
 }
@@ -224,6 +228,10 @@ func (sm *ServiceManager) GetBalance(result []byte, addr module.Address) (*big.I
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) GetBalances(result []byte, addrs []module.Address) ([]*big.Int, error) {
+	return nil, errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) GetTotalSupply(result []byte) (*big.Int, error) {
 	return nil, errors.ErrInvalidState
 }
@@ -295,6 +303,10 @@ func (sm *ServiceManager) BTPDigestFromResult(result []byte) (module.BTPDigest,
 	panic("implement me")
 }
 
+func (sm *ServiceManager) BTPPublicKeysFromResult(result []byte, addrs []module.Address, dsa string) (map[string][][]byte, error) {
+	return nil, errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) BTPNetworkTypeFromResult(result []byte, ntid int64) (module.BTPNetworkType, error) {
 	//TODO implement me
 	panic("implement me")
@@ -377,6 +389,14 @@ func (sm *ServiceManager) GetSCOREStatus(result []byte, addr module.Address) (mo
 	return nil, common.ErrInvalidState
 }
 
+func (sm *ServiceManager) GetFeeSharingStatus(result []byte, addr module.Address) (module.FeeSharingStatus, error) {
+	return nil, common.ErrInvalidState
+}
+
+func (sm *ServiceManager) GetChainConfig(result []byte) (module.ChainConfig, error) {
+	return nil, common.ErrInvalidState
+}
+
 func NewServiceManagerWithExecutor(chain module.Chain, ex *Executor, ps BlockV1ProofStorage, vs []*common.Address, cb ImportCallback) (*ServiceManager, error) {
 	logger := chain.Logger()
 	dbase := chain.Database()
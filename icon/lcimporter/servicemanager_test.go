@@ -47,6 +47,10 @@ func (c *testChain) Database() db.Database {
 	return c.idb
 }
 
+func (c *testChain) AccessIndex() bool {
+	return false
+}
+
 type testProofStorage struct {
 	Root  []byte
 	Size  int64
@@ -0,0 +1,292 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lcimporter
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/blockv0"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	KeyVerificationRanges = "verification.ranges"
+)
+
+// Names of the rules checked on import in addition to the ones of blockv0.
+const (
+	RuleReceiptsHash   = "receiptsHash"
+	RuleConvertedBlock = "convertedBlock"
+)
+
+// VerificationRange describes the rules checked for the legacy blocks in
+// the range of heights (From <= height <= To).
+type VerificationRange struct {
+	From   int64    `json:"from"`
+	To     int64    `json:"to"`
+	Strict bool     `json:"strict"`
+	Rules  []string `json:"rules"`
+}
+
+func (r *VerificationRange) sameRules(r2 *VerificationRange) bool {
+	if r.Strict != r2.Strict || len(r.Rules) != len(r2.Rules) {
+		return false
+	}
+	for i, rule := range r.Rules {
+		if r2.Rules[i] != rule {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeVerificationRange returns ranges after applying nr. Rules for
+// the heights in nr are replaced, and adjacent ranges with the same rules
+// are merged.
+func mergeVerificationRange(ranges []*VerificationRange, nr *VerificationRange) []*VerificationRange {
+	res := make([]*VerificationRange, 0, len(ranges)+2)
+	inserted := false
+	insert := func() {
+		nrc := *nr
+		if l := len(res); l > 0 && res[l-1].To+1 == nrc.From && res[l-1].sameRules(&nrc) {
+			res[l-1].To = nrc.To
+		} else {
+			res = append(res, &nrc)
+		}
+		inserted = true
+	}
+	for _, r := range ranges {
+		if r.To < nr.From {
+			res = append(res, r)
+			continue
+		}
+		if r.From > nr.To {
+			if !inserted {
+				insert()
+			}
+			if l := len(res); res[l-1].To+1 == r.From && res[l-1].sameRules(r) {
+				res[l-1].To = r.To
+			} else {
+				res = append(res, r)
+			}
+			continue
+		}
+		if r.From < nr.From {
+			head := *r
+			head.To = nr.From - 1
+			res = append(res, &head)
+		}
+		if !inserted {
+			insert()
+		}
+		if r.To > nr.To {
+			tail := *r
+			tail.From = nr.To + 1
+			if l := len(res); res[l-1].sameRules(&tail) {
+				res[l-1].To = tail.To
+			} else {
+				res = append(res, &tail)
+			}
+		}
+	}
+	if !inserted {
+		insert()
+	}
+	return res
+}
+
+// GetVerificationRanges returns the rules checked for the imported legacy
+// blocks in order of height.
+func GetVerificationRanges(dbase db.Database) ([]*VerificationRange, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get([]byte(KeyVerificationRanges))
+	if err != nil {
+		return nil, err
+	}
+	ranges := []*VerificationRange{}
+	if bs == nil {
+		return ranges, nil
+	}
+	if err := json.Unmarshal(bs, &ranges); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+func recordVerification(dbase db.Database, nr *VerificationRange) error {
+	ranges, err := GetVerificationRanges(dbase)
+	if err != nil {
+		return err
+	}
+	ranges = mergeVerificationRange(ranges, nr)
+	bs, err := json.Marshal(ranges)
+	if err != nil {
+		return err
+	}
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(KeyVerificationRanges), bs)
+}
+
+func importRulesOf(blk, prev blockv0.Block, strict bool) []string {
+	rules := blockv0.RulesOf(blk, prev, strict)
+	if _, ok := blk.(*blockv0.BlockV03); ok {
+		rules = append(rules, RuleReceiptsHash)
+	}
+	return append(rules, RuleConvertedBlock)
+}
+
+// Verifier verifies imported legacy blocks again with strict rules.
+type Verifier struct {
+	database  db.Database
+	cs        Store
+	blkIndex  db.Bucket
+	blkByHash db.Bucket
+}
+
+func NewVerifier(dbase db.Database, cs Store) (*Verifier, error) {
+	blkIndex, err := dbase.GetBucket(db.BlockHeaderHashByHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "FailureInBucket(bucket=HashByHeight)")
+	}
+	blkByHash, err := dbase.GetBucket(db.BytesByHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "FailureInBucket(bucket=BlockV1ByID)")
+	}
+	return &Verifier{
+		database:  dbase,
+		cs:        cs,
+		blkIndex:  blkIndex,
+		blkByHash: blkByHash,
+	}, nil
+}
+
+func (v *Verifier) verifyReceipts(blk blockv0.Block) error {
+	blkV03, ok := blk.(*blockv0.BlockV03)
+	if !ok {
+		return nil
+	}
+	txs := blk.NormalTransactions()
+	rcts := make([]txresult.Receipt, len(txs))
+	for idx, tx := range txs {
+		rct, err := v.cs.GetReceipt(tx.ID())
+		if err != nil {
+			return errors.Wrapf(err, "FailureInGetReceipts(txid=%#x)", tx.ID())
+		}
+		rcts[idx] = rct.(txresult.Receipt)
+	}
+	exp := blkV03.ReceiptsHash()
+	calc := blockv0.CalcMerkleRootOfReceiptSlice(rcts, txs, blk.Height())
+	if !bytes.Equal(exp, calc) {
+		return errors.Errorf("DifferentReceiptListHash(stored=%#x,real=%#x)",
+			exp, calc)
+	}
+	return nil
+}
+
+// Verify verifies the legacy blocks from the height from to the height to
+// with strict rules. The blocks should be imported already. cb is called
+// after the block of the height is verified.
+func (v *Verifier) Verify(from, to int64, cb func(height int64) error) (ret error) {
+	if from < 0 || to < from {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d)", from, to)
+	}
+	var prev blockv0.Block
+	if from > 0 {
+		var err error
+		if prev, err = v.cs.GetBlockByHeight(int(from - 1)); err != nil {
+			return err
+		}
+	}
+	var nr *VerificationRange
+	defer func() {
+		if nr == nil {
+			return
+		}
+		if err := recordVerification(v.database, nr); err != nil && ret == nil {
+			ret = err
+		}
+	}()
+	for height := from; height <= to; height++ {
+		blk, err := v.cs.GetBlockByHeight(int(height))
+		if err != nil {
+			return err
+		}
+		if err := blockv0.VerifyStrict(blk, prev); err != nil {
+			return errors.Wrapf(err, "VerificationFailure(height=%d)", height)
+		}
+		if err := v.verifyReceipts(blk); err != nil {
+			return errors.Wrapf(err, "VerificationFailure(height=%d)", height)
+		}
+		blkV1, err := getBlockV1ByHeight(v.database, v.blkIndex, v.blkByHash, height)
+		if err != nil {
+			return err
+		}
+		if blkV1 == nil {
+			return errors.NotFoundError.Errorf("NotImported(height=%d)", height)
+		}
+		if err := checkBlock(blkV1, blk); err != nil {
+			return errors.Wrapf(err, "VerificationFailure(height=%d)", height)
+		}
+
+		rules := importRulesOf(blk, prev, true)
+		if nr == nil || !nr.sameRules(&VerificationRange{Strict: true, Rules: rules}) {
+			if nr != nil {
+				if err := recordVerification(v.database, nr); err != nil {
+					return err
+				}
+			}
+			nr = &VerificationRange{From: height, Strict: true, Rules: rules}
+		}
+		nr.To = height
+		if cb != nil {
+			if err := cb(height); err != nil {
+				return err
+			}
+		}
+		prev = blk
+	}
+	return nil
+}
+
+// Inspect returns the rules checked for the imported legacy blocks.
+func Inspect(c module.Chain, informal bool) map[string]interface{} {
+	dbase := c.Database()
+	if sm, ok := c.ServiceManager().(*ServiceManager); ok {
+		dbase = sm.ex.rdb
+	}
+	if dbase == nil {
+		return nil
+	}
+	ranges, err := GetVerificationRanges(dbase)
+	if err != nil || len(ranges) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"verification": ranges,
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lcimporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestRecordVerification(t *testing.T) {
+	dbase := db.NewMapDB()
+	basic := []string{"blockHash", "transactionRoot"}
+	strict := []string{"blockHash", "transactionRoot", "timestamp"}
+
+	ranges, err := GetVerificationRanges(dbase)
+	assert.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	for h := int64(0); h < 10; h++ {
+		err := recordVerification(dbase, &VerificationRange{
+			From: h, To: h, Rules: basic,
+		})
+		assert.NoError(t, err)
+	}
+	ranges, err = GetVerificationRanges(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, []*VerificationRange{
+		{From: 0, To: 9, Rules: basic},
+	}, ranges)
+
+	// re-verify the middle of the range with strict rules
+	assert.NoError(t, recordVerification(dbase, &VerificationRange{
+		From: 3, To: 5, Strict: true, Rules: strict,
+	}))
+	ranges, err = GetVerificationRanges(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, []*VerificationRange{
+		{From: 0, To: 2, Rules: basic},
+		{From: 3, To: 5, Strict: true, Rules: strict},
+		{From: 6, To: 9, Rules: basic},
+	}, ranges)
+
+	// extend strict range to the end
+	assert.NoError(t, recordVerification(dbase, &VerificationRange{
+		From: 6, To: 12, Strict: true, Rules: strict,
+	}))
+	ranges, err = GetVerificationRanges(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, []*VerificationRange{
+		{From: 0, To: 2, Rules: basic},
+		{From: 3, To: 12, Strict: true, Rules: strict},
+	}, ranges)
+
+	// import again with basic rules over the whole range
+	assert.NoError(t, recordVerification(dbase, &VerificationRange{
+		From: 0, To: 12, Rules: basic,
+	}))
+	ranges, err = GetVerificationRanges(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, []*VerificationRange{
+		{From: 0, To: 12, Rules: basic},
+	}, ranges)
+}
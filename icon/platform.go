@@ -76,6 +76,10 @@ func (p *platform) NewExtensionWithBuilder(builder merkle.Builder, raw []byte) s
 	return iiss.NewExtensionSnapshotWithBuilder(builder, raw)
 }
 
+func (p *platform) MaxRevision() int {
+	return icmodule.MaxRevision
+}
+
 func (p *platform) ToRevision(value int) module.Revision {
 	return icmodule.ValueToRevision(value)
 }
@@ -137,6 +137,11 @@ type BlockManager interface {
 	Finalize(BlockCandidate) error
 
 	GetTransactionInfo(id []byte) (TransactionInfo, error)
+
+	// GetReceiptsByHeight returns receipts of normal transactions in the
+	// finalized block at the height. Receipts are read from the result of
+	// the next block on demand, so the next block shall be finalized.
+	GetReceiptsByHeight(height int64) (ReceiptList, error)
 	Term()
 
 	// WaitForTransaction waits for a transaction with timestamp between
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 )
 
@@ -31,13 +32,27 @@ type Chain interface {
 	ConcurrencyLevel() int
 	NormalTxPoolSize() int
 	PatchTxPoolSize() int
+	PriorityTxPoolSize() int
 	MaxBlockTxBytes() int
 	DefaultWaitTimeout() time.Duration
 	MaxWaitTimeout() time.Duration
 	TransactionTimeout() time.Duration
 	ChildrenLimit() int
 	NephewsLimit() int
+	// WALRetention returns the number of the previous heights kept in WAL
+	// of the consensus.
+	WALRetention() int
+	// TxTimestampWindow returns the maximum difference of the timestamp of
+	// a new transaction from the current time. Zero means the timestamp
+	// threshold of the chain.
+	TxTimestampWindow() time.Duration
 	ValidateTxOnSend() bool
+	// LocalTxFirst returns whether transactions sent through this node are
+	// included in its proposals before others.
+	LocalTxFirst() bool
+	// AccessIndex returns whether heights of the blocks accessing accounts
+	// are recorded on execution.
+	AccessIndex() bool
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
@@ -48,6 +63,7 @@ type Chain interface {
 	ServiceManager() ServiceManager
 	NetworkManager() NetworkManager
 	Regulator() Regulator
+	Quota() Quota
 
 	Init() error
 	Start() error
@@ -84,6 +100,95 @@ type Regulator interface {
 	SetBlockInterval(i time.Duration, d time.Duration)
 }
 
+// Quota throttles resources used by a chain, so that a busy chain can't
+// starve other chains sharing the process. Quotas are soft, so it delays
+// the usage exceeding them instead of failing it.
+type Quota interface {
+	// AcquireExecutor waits for the slot to execute transactions or
+	// queries, and returns the function to release the slot.
+	AcquireExecutor() func()
+
+	// OnExecution consumes the CPU budget with the duration of the
+	// execution. It blocks the caller while the budget is exhausted.
+	OnExecution(d time.Duration)
+}
+
+// Components of a chain, which can be paused and resumed while the chain
+// is running.
+const (
+	ComponentRPC    = "rpc"
+	ComponentTxPool = "txpool"
+	ComponentSync   = "sync"
+)
+
+var Components = []string{ComponentRPC, ComponentTxPool, ComponentSync}
+
+// ComponentStatus is implemented by the chains supporting pause of their
+// components.
+type ComponentStatus interface {
+	IsPaused(component string) bool
+}
+
+// IsComponentPaused returns whether the component of the chain is paused.
+// It returns false if the chain doesn't support pause of components.
+func IsComponentPaused(c interface{}, component string) bool {
+	if cs, ok := c.(ComponentStatus); ok {
+		return cs.IsPaused(component)
+	}
+	return false
+}
+
+// Kinds of faults of the chain
+const (
+	FaultDatabase  = "database"
+	FaultWAL       = "wal"
+	FaultExecution = "execution"
+)
+
+// FaultHandler is implemented by the chain recovering from faults detected
+// by the managers. HandleFault returns true if the fault is handled.
+type FaultHandler interface {
+	HandleFault(kind string, err error) bool
+}
+
+// HandleFault passes the fault to the chain. It returns false if the chain
+// doesn't handle faults, then the caller should handle it as before.
+func HandleFault(c interface{}, kind string, err error) bool {
+	if fh, ok := c.(FaultHandler); ok {
+		return fh.HandleFault(kind, err)
+	}
+	return false
+}
+
+// Fault is the error with the kind of the fault causing it.
+type Fault struct {
+	Kind string
+	Err  error
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("%s fault: %v", f.Kind, f.Err)
+}
+
+func (f *Fault) Unwrap() error {
+	return f.Err
+}
+
+func NewFault(kind string, err error) error {
+	return &Fault{Kind: kind, Err: err}
+}
+
+// FaultOf returns the fault causing the error, or nil if there is none.
+func FaultOf(err error) *Fault {
+	if f := errors.FindCause(err, func(err error) bool {
+		_, ok := err.(*Fault)
+		return ok
+	}); f != nil {
+		return f.(*Fault)
+	}
+	return nil
+}
+
 type GenesisType int
 
 const (
@@ -1,9 +1,38 @@
 package module
 
 type ConsensusStatus struct {
-	Height   int64
+	Height    int64
+	Round     int32
+	Proposer  bool
+	Partition Partition
+}
+
+// Partition is a probable partition of the network detected by comparing
+// the height of the node with the heights advertised by its peers.
+type Partition string
+
+const (
+	PartitionNone Partition = ""
+	// PartitionPeersBehind means all peers are far behind the node, so the
+	// node may be eclipsed by stale peers.
+	PartitionPeersBehind Partition = "peersBehind"
+	// PartitionPeersAhead means all peers are far ahead the node, so the
+	// node may be partitioned from the network.
+	PartitionPeersAhead Partition = "peersAhead"
+)
+
+// RoundFailure is a round failed to commit a block.
+type RoundFailure struct {
 	Round    int32
-	Proposer bool
+	Proposer Address
+	Reason   string
+}
+
+// RoundHistory is a height which needed more than one round.
+type RoundHistory struct {
+	Height      int64
+	CommitRound int32
+	Failures    []RoundFailure
 }
 
 const (
@@ -18,6 +47,10 @@ type Consensus interface {
 	GetStatus() *ConsensusStatus
 	GetVotesByHeight(height int64) (CommitVoteSet, error)
 
+	// GetRoundHistory returns recent heights which needed more than one
+	// round since the height.
+	GetRoundHistory(height int64) []*RoundHistory
+
 	// GetBTPBlockHeaderAndProof returns header and proof according to the given
 	// flag.
 	GetBTPBlockHeaderAndProof(
@@ -0,0 +1,57 @@
+package module
+
+import "sort"
+
+// Names of features supported by this build. They are exchanged with peers
+// on joining channels, so that a node can warn about peers requiring
+// features which this build lacks before it fails in the middle of the
+// consensus. Add the name for new features of releases which may not work
+// with older ones.
+const (
+	FeatureP2PKeepalive  = "p2p.keepalive"
+	FeatureLightServer   = "p2p.lightServer"
+	FeatureTxAnnounce    = "p2p.txAnnounce"
+	FeatureGenesisFetch  = "p2p.genesisFetch"
+	FeatureBackupServer  = "p2p.backupServer"
+	FeatureScheduledHalt = "chain.scheduledHalt"
+)
+
+var features = []string{
+	FeatureP2PKeepalive,
+	FeatureLightServer,
+	FeatureTxAnnounce,
+	FeatureGenesisFetch,
+	FeatureBackupServer,
+	FeatureScheduledHalt,
+}
+
+// Features returns sorted names of features supported by this build.
+func Features() []string {
+	fs := make([]string, len(features))
+	copy(fs, features)
+	sort.Strings(fs)
+	return fs
+}
+
+// MissingFeatures returns sorted names in fs, which this build doesn't
+// support.
+func MissingFeatures(fs []string) []string {
+	var missing []string
+	for _, f := range fs {
+		if !HasFeature(f) {
+			missing = append(missing, f)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// HasFeature returns whether this build supports the feature.
+func HasFeature(name string) bool {
+	for _, f := range features {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
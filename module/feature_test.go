@@ -0,0 +1,22 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatures(t *testing.T) {
+	fs := Features()
+	assert.Len(t, fs, len(features))
+	for _, f := range fs {
+		assert.True(t, HasFeature(f))
+	}
+	fs[0] = "modified"
+	assert.NotEqual(t, "modified", Features()[0])
+
+	assert.Nil(t, MissingFeatures(nil))
+	assert.Nil(t, MissingFeatures([]string{FeatureP2PKeepalive}))
+	assert.Equal(t, []string{"a.unknown", "b.unknown"},
+		MissingFeatures([]string{"b.unknown", FeatureScheduledHalt, "a.unknown"}))
+}
@@ -86,6 +86,10 @@ const (
 	ProtoConsensus
 	ProtoFastSync
 	ProtoConsensusSync
+	ProtoLight
+	ProtoGenesis
+	ProtoSnapshot
+	ProtoPriorityTransaction
 )
 
 type ProtocolInfo uint16
@@ -36,6 +36,7 @@ const (
 	PurgeEnumCache
 	ContractSetEvent
 	FixMapValues
+	ContractTxValidation
 	LastRevisionBit
 )
 
@@ -93,3 +94,35 @@ func (r Revision) LegacyBalanceCheck() bool {
 func (r Revision) Has(flag Revision) bool {
 	return (r & flag) != 0
 }
+
+var revisionFlagNames = []string{
+	"InputCostingWithJSON",
+	"ExpandErrorCode",
+	"UseChainID",
+	"UseMPTOnEvents",
+	"UseCompactAPIInfo",
+	"AutoAcceptGovernance",
+	"LegacyFeeCharge",
+	"LegacyFallbackCheck",
+	"LegacyContentCount",
+	"LegacyBalanceCheck",
+	"LegacyInputJSON",
+	"LegacyNoTimeout",
+	"FixLostFeeByDeposit",
+	"MultipleFeePayers",
+	"PurgeEnumCache",
+	"ContractSetEvent",
+	"FixMapValues",
+	"ContractTxValidation",
+}
+
+// FlagNames returns names of the flags set in the revision.
+func (r Revision) FlagNames() []string {
+	names := make([]string, 0, len(revisionFlagNames))
+	for i, name := range revisionFlagNames {
+		if r.Has(InputCostingWithJSON << i) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
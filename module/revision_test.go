@@ -0,0 +1,14 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevision_FlagNames(t *testing.T) {
+	assert.Equal(t, LastRevisionBit, InputCostingWithJSON<<len(revisionFlagNames))
+	assert.Equal(t, []string{}, Revision(NoRevision).FlagNames())
+	assert.Equal(t, []string{"UseChainID", "FixMapValues"},
+		(UseChainID | FixMapValues | 3).FlagNames())
+}
@@ -240,6 +240,22 @@ type APIInfo interface {
 
 type SCOREStatus interface {
 	ToJSON(height int64, version JSONVersion) (interface{}, error)
+
+	// CodeHash returns hash of the code of the current contract.
+	// It returns nil if there is no current contract.
+	CodeHash() []byte
+
+	// PendingTxHash returns hash of the deploy transaction of the next
+	// contract waiting for audit. It returns nil if there is no one.
+	PendingTxHash() []byte
+}
+
+type FeeSharingStatus interface {
+	ToJSON(height int64, version JSONVersion) (interface{}, error)
+}
+
+type ChainConfig interface {
+	ToJSON(height int64, version JSONVersion) (interface{}, error)
 }
 
 // Options for finalize
@@ -297,6 +313,16 @@ type TransitionManager interface {
 	WaitForTransaction(parent Transition, bi BlockInfo, cb func()) bool
 }
 
+// ExtensionProof is the proof of a value in a store of the extension state.
+// Data is the extension data of the result, which includes Root, the hash
+// of the store.
+type ExtensionProof struct {
+	Data  []byte
+	Root  []byte
+	Value []byte
+	Proof [][]byte
+}
+
 type ServiceManager interface {
 	TransitionManager
 
@@ -326,6 +352,12 @@ type ServiceManager interface {
 	// SendTransaction adds transaction to a transaction pool.
 	SendTransaction(result []byte, height int64, tx interface{}) ([]byte, error)
 
+	// ValidateTransaction checks the transaction with the state of the
+	// result for the block at the height without adding it to the pool.
+	// It returns all the violations found. Error is returned only if it
+	// fails to check.
+	ValidateTransaction(result []byte, height int64, tx interface{}) ([]error, error)
+
 	// SendPatch sends a patch
 	SendPatch(patch Patch) error
 
@@ -338,6 +370,9 @@ type ServiceManager interface {
 	// GetBalance returns balance of the account
 	GetBalance(result []byte, addr Address) (*big.Int, error)
 
+	// GetBalances returns balances of the accounts with the same state
+	GetBalances(result []byte, addrs []Address) ([]*big.Int, error)
+
 	// GetTotalSupply returns total supplied coin
 	GetTotalSupply(result []byte) (*big.Int, error)
 
@@ -353,6 +388,14 @@ type ServiceManager interface {
 	// GetSCOREStatus returns status of the contract
 	GetSCOREStatus(result []byte, addr Address) (SCOREStatus, error)
 
+	// GetFeeSharingStatus returns fee sharing configuration and deposits
+	// of the contract
+	GetFeeSharingStatus(result []byte, addr Address) (FeeSharingStatus, error)
+
+	// GetChainConfig returns revision, enabled features, step costs and
+	// step limits of the chain
+	GetChainConfig(result []byte) (ChainConfig, error)
+
 	// GetMembers returns network member list
 	GetMembers(result []byte) (MemberList, error)
 
@@ -380,6 +423,15 @@ type ServiceManager interface {
 
 	BTPNetworkTypeIDsFromResult(result []byte) ([]int64, error)
 
+	// BTPPublicKeysFromResult returns registered BTP public keys of the
+	// addresses for each DSA. If dsa is empty, all registered DSAs are
+	// returned.
+	BTPPublicKeysFromResult(result []byte, addrs []Address, dsa string) (map[string][][]byte, error)
+
+	// ExtensionProofFromResult returns the value for the key in the store
+	// of the extension state, and the proof of it.
+	ExtensionProofFromResult(result []byte, store string, key []byte) (*ExtensionProof, error)
+
 	// HasTransaction returns whether it has specified transaction in the pool
 	HasTransaction(id []byte) bool
 
@@ -41,6 +41,8 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeStateDiff
+	TraceModeDebug
 )
 
 type OpType int
@@ -81,3 +83,18 @@ type TraceCallback interface {
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
 }
+
+// StateTraceCallback is implemented by TraceCallback to receive accesses to
+// the storage of the accounts with TraceModeStateDiff.
+type StateTraceCallback interface {
+	OnStateRead(addr Address, key, value []byte) error
+	OnStateWrite(addr Address, key, before, after []byte) error
+}
+
+// DebugTraceCallback is implemented by TraceCallback to be notified of
+// entries of contracts with TraceModeDebug. The execution is blocked until
+// OnContractEnter returns.
+type DebugTraceCallback interface {
+	StateTraceCallback
+	OnContractEnter(addr Address, method string) error
+}
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultAuditRecordSize = 256
+)
+
+const (
+	AuditStageAuth    = "auth"
+	AuditStageChannel = "channel"
+	AuditStageP2P     = "p2p"
+
+	AuditResultAccepted = "accepted"
+	AuditResultRejected = "rejected"
+)
+
+// HandshakeRecord is an outcome of the handshake with a peer.
+type HandshakeRecord struct {
+	Time         time.Time     `json:"time"`
+	Stage        string        `json:"stage"`
+	Result       string        `json:"result"`
+	ID           string        `json:"id,omitempty"`
+	In           bool          `json:"in"`
+	Conn         string        `json:"conn"`
+	NetAddress   string        `json:"netAddress,omitempty"`
+	Channel      string        `json:"channel,omitempty"`
+	Protocols    []string      `json:"protocols,omitempty"`
+	Role         []module.Role `json:"role,omitempty"`
+	ResolvedRole []module.Role `json:"resolvedRole,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+}
+
+// HandshakeAuditor is implemented by the transport supporting audit log of
+// handshakes.
+type HandshakeAuditor interface {
+	// SetAuditWriter enables audit log with the writer. Records are written
+	// as JSON lines. It's disabled with nil.
+	SetAuditWriter(w io.Writer)
+
+	// HandshakeRecords returns recent records if audit log is enabled.
+	HandshakeRecords() []*HandshakeRecord
+}
+
+type auditLog struct {
+	mtx     sync.Mutex
+	w       io.Writer
+	records []*HandshakeRecord
+}
+
+func (a *auditLog) setWriter(w io.Writer) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if c, ok := a.w.(io.Closer); ok && a.w != w {
+		_ = c.Close()
+	}
+	a.w = w
+	a.records = nil
+}
+
+func (a *auditLog) Records() []*HandshakeRecord {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	records := make([]*HandshakeRecord, len(a.records))
+	copy(records, a.records)
+	return records
+}
+
+func (a *auditLog) add(r *HandshakeRecord) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.w == nil {
+		return
+	}
+	if bs, err := json.Marshal(r); err == nil {
+		_, _ = a.w.Write(append(bs, '\n'))
+	}
+	if len(a.records) >= DefaultAuditRecordSize {
+		copy(a.records, a.records[1:])
+		a.records = a.records[:len(a.records)-1]
+	}
+	a.records = append(a.records, r)
+}
+
+func (a *auditLog) enabled() bool {
+	if a == nil {
+		return false
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.w != nil
+}
+
+func (a *auditLog) record(stage, result string, p *Peer, reason string) {
+	if !a.enabled() {
+		return
+	}
+	r := &HandshakeRecord{
+		Time:       time.Now(),
+		Stage:      stage,
+		Result:     result,
+		In:         p.In(),
+		Conn:       p.ConnString(),
+		NetAddress: string(p.NetAddress()),
+		Channel:    p.Channel(),
+		Reason:     reason,
+	}
+	if id := p.ID(); id != nil {
+		r.ID = id.String()
+	}
+	if pis := p.ProtocolInfos(); pis != nil {
+		for _, pi := range pis.Array() {
+			r.Protocols = append(r.Protocols, fmt.Sprintf("%#04x", pi.Uint16()))
+		}
+	}
+	if stage == AuditStageP2P {
+		rr := p.RecvRole()
+		r.Role = rr.ToRoles()
+		role := p.Role()
+		r.ResolvedRole = role.ToRoles()
+	}
+	a.add(r)
+}
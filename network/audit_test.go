@@ -0,0 +1,45 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	var a *auditLog
+	assert.False(t, a.enabled())
+
+	a = new(auditLog)
+	a.add(&HandshakeRecord{Stage: AuditStageAuth})
+	assert.Len(t, a.Records(), 0)
+
+	buf := bytes.NewBuffer(nil)
+	a.setWriter(buf)
+	assert.True(t, a.enabled())
+	for i := 0; i < DefaultAuditRecordSize+2; i++ {
+		a.add(&HandshakeRecord{
+			Stage:  AuditStageP2P,
+			Result: AuditResultRejected,
+			Reason: string(rune('a' + i%26)),
+		})
+	}
+
+	records := a.Records()
+	assert.Len(t, records, DefaultAuditRecordSize)
+	assert.Equal(t, "c", records[0].Reason)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, DefaultAuditRecordSize+2)
+	var r HandshakeRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &r))
+	assert.Equal(t, AuditStageP2P, r.Stage)
+	assert.Equal(t, "b", r.Reason)
+
+	a.setWriter(nil)
+	assert.False(t, a.enabled())
+	assert.Len(t, a.Records(), 0)
+}
@@ -43,6 +43,8 @@ type Authenticator struct {
 	secureKeyNum int
 	secureMtx    sync.RWMutex
 	mtx          sync.Mutex
+	audit        *auditLog
+	mux          *muxManager
 }
 
 func newAuthenticator(w module.Wallet, l log.Logger) *Authenticator {
@@ -63,6 +65,12 @@ func newAuthenticator(w module.Wallet, l log.Logger) *Authenticator {
 //callback from PeerHandler.nextOnPeer
 func (a *Authenticator) onPeer(p *Peer) {
 	a.logger.Traceln("onPeer", p)
+	if st, ok := p.conn.(*muxStream); ok {
+		//stream over the authenticated session
+		p.setID(st.s.id)
+		a.nextOnPeer(p)
+		return
+	}
 	if !p.In() {
 		a.setWaitInfo(p2pProtoAuthSecureResponse, p)
 		a.sendSecureRequest(p)
@@ -77,6 +85,11 @@ func (a *Authenticator) onError(err error, p *Peer, pkt *Packet) {
 }
 
 //callback from Peer.receiveRoutine
+func (a *Authenticator) onClose(p *Peer) {
+	a.audit.record(AuditStageAuth, AuditResultRejected, p, p.CloseInfo())
+	a.peerHandler.onClose(p)
+}
+
 func (a *Authenticator) onPacket(pkt *Packet, p *Peer) {
 	switch pkt.protocol {
 	case p2pProtoAuth:
@@ -238,12 +251,14 @@ type SignatureRequest struct {
 	PublicKey []byte
 	Signature []byte
 	Rtt       time.Duration
+	Mux       bool
 }
 type SignatureResponse struct {
 	PublicKey []byte
 	Signature []byte
 	Rtt       time.Duration
 	Error     string
+	Mux       bool
 }
 
 func (a *Authenticator) sendSecureRequest(p *Peer) {
@@ -445,6 +460,7 @@ func (a *Authenticator) handleSecureResponse(pkt *Packet, p *Peer) {
 		PublicKey: a.wallet.PublicKey(),
 		Signature: a.Signature(p.secureKey.extra),
 		Rtt:       p.rtt.last,
+		Mux:       a.mux.Enabled(),
 	}
 	a.setWaitInfo(p2pProtoAuthSignatureResponse, p)
 	a.sendMessage(p2pProtoAuth, p2pProtoAuthSignatureRequest, m, p)
@@ -471,6 +487,7 @@ func (a *Authenticator) handleSignatureRequest(pkt *Packet, p *Peer) {
 		PublicKey: a.wallet.PublicKey(),
 		Signature: a.Signature(p.secureKey.extra),
 		Rtt:       p.rtt.last,
+		Mux:       rm.Mux && a.mux.Enabled(),
 	}
 
 	id, err := a.VerifySignature(rm.PublicKey, rm.Signature, p.secureKey.extra)
@@ -488,6 +505,9 @@ func (a *Authenticator) handleSignatureRequest(pkt *Packet, p *Peer) {
 		p.CloseByError(err)
 		return
 	}
+	if m.Mux {
+		p.ResetConn(a.mux.newSession(p.conn, p.In(), p.ID(), p.DialNetAddress()))
+	}
 	a.nextOnPeer(p)
 }
 
@@ -525,5 +545,8 @@ func (a *Authenticator) handleSignatureResponse(pkt *Packet, p *Peer) {
 	if !p.ID().Equal(pkt.src) {
 		a.logger.Infoln("handleSignatureResponse", "id doesnt match pkt:", pkt.src, ",expected:", p.ID())
 	}
+	if rm.Mux && a.mux.Enabled() {
+		p.ResetConn(a.mux.newSession(p.conn, p.In(), p.ID(), p.DialNetAddress()))
+	}
 	a.nextOnPeer(p)
 }
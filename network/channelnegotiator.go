@@ -20,6 +20,7 @@ type ChannelNegotiator struct {
 	netAddress NetAddress
 	m          map[string]*ProtocolInfos
 	mtx        sync.RWMutex
+	audit      *auditLog
 }
 
 func newChannelNegotiator(netAddress NetAddress, l log.Logger) *ChannelNegotiator {
@@ -46,6 +47,11 @@ func (cn *ChannelNegotiator) onError(err error, p *Peer, pkt *Packet) {
 	cn.peerHandler.onError(err, p, pkt)
 }
 
+func (cn *ChannelNegotiator) onClose(p *Peer) {
+	cn.audit.record(AuditStageChannel, AuditResultRejected, p, p.CloseInfo())
+	cn.peerHandler.onClose(p)
+}
+
 func (cn *ChannelNegotiator) onPacket(pkt *Packet, p *Peer) {
 	switch pkt.protocol {
 	case p2pProtoChan:
@@ -66,12 +72,16 @@ type JoinRequest struct {
 	Channel   string
 	Addr      NetAddress
 	Protocols []module.ProtocolInfo
+	Keepalive bool
+	Features  []string
 }
 
 type JoinResponse struct {
 	Channel   string
 	Addr      NetAddress
 	Protocols []module.ProtocolInfo
+	Keepalive bool
+	Features  []string
 }
 
 var defaultProtocols = []module.ProtocolInfo{
@@ -151,7 +161,8 @@ func (cn *ChannelNegotiator) sendJoinRequest(p *Peer) {
 		p.CloseByError(err)
 		return
 	}
-	m := &JoinRequest{Channel: p.Channel(), Addr: cn.netAddress, Protocols: pis.Array()}
+	m := &JoinRequest{Channel: p.Channel(), Addr: cn.netAddress, Protocols: pis.Array(), Keepalive: true,
+		Features: module.Features()}
 	cn.sendMessage(p2pProtoChan, p2pProtoChanJoinReq, m, p)
 	cn.logger.Traceln("sendJoinRequest", m, p)
 }
@@ -174,8 +185,11 @@ func (cn *ChannelNegotiator) handleJoinRequest(pkt *Packet, p *Peer) {
 		return
 	}
 	p.setNetAddress(rm.Addr)
+	p.PutAttr(AttrP2PKeepalive, rm.Keepalive)
+	cn.checkFeatures(p, rm.Features)
 
-	m := &JoinResponse{Channel: p.Channel(), Addr: cn.netAddress, Protocols: p.ProtocolInfos().Array()}
+	m := &JoinResponse{Channel: p.Channel(), Addr: cn.netAddress, Protocols: p.ProtocolInfos().Array(), Keepalive: true,
+		Features: module.Features()}
 	cn.sendMessage(p2pProtoChan, p2pProtoChanJoinResp, m, p)
 
 	cn.nextOnPeer(p)
@@ -199,6 +213,8 @@ func (cn *ChannelNegotiator) handleJoinResponse(pkt *Packet, p *Peer) {
 		return
 	}
 	p.setNetAddress(rm.Addr)
+	p.PutAttr(AttrP2PKeepalive, rm.Keepalive)
+	cn.checkFeatures(p, rm.Features)
 
 	cn.nextOnPeer(p)
 }
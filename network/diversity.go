@@ -0,0 +1,189 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	DefaultDiversitySubnetV4 = 24
+	DefaultDiversitySubnetV6 = 48
+)
+
+// PeerDiversitySetter is implemented by the transport supporting limits of
+// peers sharing a subnet or an ASN.
+type PeerDiversitySetter interface {
+	// SetPeerDiversity limits the number of peers in a subnet (/24 for IPv4,
+	// /48 for IPv6) and in an ASN. Zero disables the limit. ASN of a peer is
+	// looked up in the mapping file having "<CIDR> <ASN>" for each line.
+	SetPeerDiversity(subnetLimit, asnLimit int, asnFile string) error
+}
+
+type asnEntry struct {
+	ipNet *net.IPNet
+	asn   uint32
+}
+
+// loadASNMap reads "<CIDR> <ASN>" for each line. Empty lines and lines
+// starting with '#' are ignored, and ASN may have "AS" prefix.
+func loadASNMap(r io.Reader) ([]asnEntry, error) {
+	var entries []asnEntry
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidASNMap(line=%d)", line)
+		}
+		_, ipNet, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidASNMap(line=%d)", line)
+		}
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(fields[1]), "AS"), 10, 32)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidASNMap(line=%d)", line)
+		}
+		entries = append(entries, asnEntry{ipNet, uint32(asn)})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// peerDiversity limits peers sharing a subnet or an ASN to reduce the risk of
+// eclipse attacks from a few networks.
+type peerDiversity struct {
+	mtx         sync.RWMutex
+	subnetLimit int
+	asnLimit    int
+	asns        []asnEntry
+}
+
+func newPeerDiversity() *peerDiversity {
+	return &peerDiversity{}
+}
+
+func (pd *peerDiversity) set(subnetLimit, asnLimit int, asnFile string) error {
+	if subnetLimit < 0 || asnLimit < 0 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidLimit(subnet=%d,asn=%d)", subnetLimit, asnLimit)
+	}
+	var asns []asnEntry
+	if asnLimit > 0 {
+		if len(asnFile) == 0 {
+			return errors.IllegalArgumentError.New("NoASNMap")
+		}
+		f, err := os.Open(asnFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if asns, err = loadASNMap(f); err != nil {
+			return err
+		}
+	}
+	pd.mtx.Lock()
+	defer pd.mtx.Unlock()
+
+	pd.subnetLimit = subnetLimit
+	pd.asnLimit = asnLimit
+	pd.asns = asns
+	return nil
+}
+
+func (pd *peerDiversity) enabled() bool {
+	if pd == nil {
+		return false
+	}
+	pd.mtx.RLock()
+	defer pd.mtx.RUnlock()
+
+	return pd.subnetLimit > 0 || pd.asnLimit > 0
+}
+
+func subnetOf(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%s/%d", ip4.Mask(net.CIDRMask(DefaultDiversitySubnetV4, 32)), DefaultDiversitySubnetV4)
+	}
+	return fmt.Sprintf("%s/%d", ip.Mask(net.CIDRMask(DefaultDiversitySubnetV6, 128)), DefaultDiversitySubnetV6)
+}
+
+// asnOf returns ASN of the longest matching prefix, or zero if it's unknown.
+func (pd *peerDiversity) asnOf(ip net.IP) uint32 {
+	var asn uint32
+	longest := -1
+	for _, e := range pd.asns {
+		if ones, _ := e.ipNet.Mask.Size(); ones > longest && e.ipNet.Contains(ip) {
+			asn, longest = e.asn, ones
+		}
+	}
+	return asn
+}
+
+// check returns an error if the peer at the IP exceeds the limits with the
+// other peers.
+func (pd *peerDiversity) check(ip net.IP, others []net.IP) error {
+	if ip == nil || !pd.enabled() {
+		return nil
+	}
+	pd.mtx.RLock()
+	defer pd.mtx.RUnlock()
+
+	if pd.subnetLimit > 0 {
+		subnet := subnetOf(ip)
+		n := 0
+		for _, o := range others {
+			if o != nil && subnetOf(o) == subnet {
+				n++
+			}
+		}
+		if n >= pd.subnetLimit {
+			return errors.Wrapf(ErrPeerDiversity, "SubnetLimit(subnet=%s,peers=%d)", subnet, n)
+		}
+	}
+	if pd.asnLimit > 0 {
+		if asn := pd.asnOf(ip); asn != 0 {
+			n := 0
+			for _, o := range others {
+				if o != nil && pd.asnOf(o) == asn {
+					n++
+				}
+			}
+			if n >= pd.asnLimit {
+				return errors.Wrapf(ErrPeerDiversity, "ASNLimit(asn=%d,peers=%d)", asn, n)
+			}
+		}
+	}
+	return nil
+}
+
+func ipOfAddr(addr net.Addr) net.IP {
+	if addr == nil {
+		return nil
+	}
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP
+	}
+	return ipOfNetAddress(NetAddress(addr.String()))
+}
+
+// ipOfNetAddress returns IP of the address, or nil if it's not an IP address.
+func ipOfNetAddress(na NetAddress) net.IP {
+	host, _, err := net.SplitHostPort(string(na))
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,84 @@
+package network
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testASNMap = `
+# CIDR ASN
+10.0.0.0/8 AS100
+10.1.0.0/16 200
+2001:db8::/32 AS300
+`
+
+func TestLoadASNMap(t *testing.T) {
+	entries, err := loadASNMap(strings.NewReader(testASNMap))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	_, err = loadASNMap(strings.NewReader("10.0.0.0/8"))
+	assert.Error(t, err)
+	_, err = loadASNMap(strings.NewReader("10.0.0.0 100"))
+	assert.Error(t, err)
+	_, err = loadASNMap(strings.NewReader("10.0.0.0/8 ASX"))
+	assert.Error(t, err)
+}
+
+func TestPeerDiversity_Subnet(t *testing.T) {
+	pd := newPeerDiversity()
+	others := []net.IP{
+		net.ParseIP("1.2.3.4"),
+		net.ParseIP("1.2.3.5"),
+		net.ParseIP("1.2.4.4"),
+		net.ParseIP("2001:db8:1:1::1"),
+		nil,
+	}
+	assert.NoError(t, pd.check(net.ParseIP("1.2.3.6"), others), "disabled")
+
+	assert.Error(t, pd.set(-1, 0, ""))
+	assert.NoError(t, pd.set(2, 0, ""))
+	err := pd.check(net.ParseIP("1.2.3.6"), others)
+	assert.True(t, PeerDiversityError.Equals(err))
+	assert.NoError(t, pd.check(net.ParseIP("1.2.4.5"), others))
+	assert.NoError(t, pd.check(net.ParseIP("2001:db8:1:2::1"), others))
+	assert.NoError(t, pd.check(nil, others))
+
+	assert.NoError(t, pd.set(1, 0, ""))
+	assert.Error(t, pd.check(net.ParseIP("2001:db8:1:2::1"), others))
+	assert.NoError(t, pd.check(net.ParseIP("2001:db8:2::1"), others))
+}
+
+func TestPeerDiversity_ASN(t *testing.T) {
+	pd := newPeerDiversity()
+	assert.Error(t, pd.set(0, 1, ""))
+
+	asnFile := filepath.Join(t.TempDir(), "asn.txt")
+	assert.NoError(t, os.WriteFile(asnFile, []byte(testASNMap), 0644))
+	assert.NoError(t, pd.set(0, 1, asnFile))
+
+	assert.EqualValues(t, 100, pd.asnOf(net.ParseIP("10.2.0.1")))
+	assert.EqualValues(t, 200, pd.asnOf(net.ParseIP("10.1.0.1")), "longest prefix")
+	assert.EqualValues(t, 300, pd.asnOf(net.ParseIP("2001:db8::1")))
+	assert.EqualValues(t, 0, pd.asnOf(net.ParseIP("1.2.3.4")))
+
+	others := []net.IP{net.ParseIP("10.2.0.1"), net.ParseIP("1.2.3.4")}
+	assert.Error(t, pd.check(net.ParseIP("10.3.0.1"), others))
+	assert.NoError(t, pd.check(net.ParseIP("10.1.0.1"), others))
+	assert.NoError(t, pd.check(net.ParseIP("1.2.3.5"), others), "unknown ASN")
+}
+
+func TestIPOf(t *testing.T) {
+	assert.Equal(t, net.ParseIP("1.2.3.4"), ipOfNetAddress("1.2.3.4:8080"))
+	assert.Equal(t, net.ParseIP("::1"), ipOfNetAddress("[::1]:8080"))
+	assert.Nil(t, ipOfNetAddress("localhost:8080"))
+	assert.Nil(t, ipOfNetAddress("1.2.3.4"))
+
+	assert.Equal(t, net.ParseIP("1.2.3.4"), ipOfAddr(&net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 80}))
+	assert.Nil(t, ipOfAddr(nil))
+}
@@ -22,6 +22,8 @@ const (
 	DuplicatedPeerError
 	InvalidMessageSequenceError
 	InvalidSignatureError
+	KeepaliveTimeoutError
+	PeerDiversityError
 )
 
 var (
@@ -41,6 +43,8 @@ var (
 	ErrDuplicatedPeer            = errors.NewBase(DuplicatedPeerError, "DuplicatedPeer")
 	ErrInvalidMessageSequence    = errors.NewBase(InvalidMessageSequenceError, "InvalidMessageSequence")
 	ErrInvalidSignature          = errors.NewBase(InvalidSignatureError, "InvalidSignatureError")
+	ErrKeepaliveTimeout          = errors.NewBase(KeepaliveTimeoutError, "KeepaliveTimeout")
+	ErrPeerDiversity             = errors.NewBase(PeerDiversityError, "PeerDiversity")
 	ErrIllegalArgument           = errors.ErrIllegalArgument
 )
 
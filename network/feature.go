@@ -0,0 +1,37 @@
+package network
+
+import (
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	AttrP2PFeatures = "P2PFeatures"
+)
+
+// Features returns names of features advertised by the peer on joining the
+// channel. Legacy peers don't advertise them.
+func (p *Peer) Features() []string {
+	if v, ok := p.GetAttr(AttrP2PFeatures); ok {
+		if fs, ok := v.([]string); ok {
+			return fs
+		}
+	}
+	return nil
+}
+
+// MissingFeatures returns names of features advertised by the peer, which
+// this build doesn't support.
+func (p *Peer) MissingFeatures() []string {
+	return module.MissingFeatures(p.Features())
+}
+
+// checkFeatures records features of the peer, and warns if the peer
+// requires features which this build lacks. The peer is still accepted,
+// since it may not use them until the chain requires them.
+func (cn *ChannelNegotiator) checkFeatures(p *Peer, features []string) {
+	p.PutAttr(AttrP2PFeatures, features)
+	if missing := p.MissingFeatures(); len(missing) > 0 {
+		cn.logger.Warnf("peer requires features not supported by this build peer=%s missing=%v",
+			p.ID(), missing)
+	}
+}
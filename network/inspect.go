@@ -18,6 +18,7 @@ func Inspect(c module.Chain, informal bool) map[string]interface{} {
 	}
 	m := make(map[string]interface{})
 	m["p2p"] = inspectP2P(mgr, informal)
+	m["features"] = module.Features()
 	if informal {
 		m["protocol"] = inspectProtocol(mgr)
 	}
@@ -69,6 +70,12 @@ func peerToMap(p *Peer, informal bool) map[string]interface{} {
 		m["addr"] = string(p.NetAddress())
 		m["in"] = p.In()
 		m["role"] = p.Role()
+		if fs := p.Features(); fs != nil {
+			m["features"] = fs
+		}
+		if missing := p.MissingFeatures(); len(missing) > 0 {
+			m["missingFeatures"] = missing
+		}
 		if informal {
 			m["channel"] = p.Channel()
 			m["conn"] = p.ConnType()
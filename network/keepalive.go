@@ -0,0 +1,128 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultKeepalivePeriod       = 10 * time.Second
+	DefaultKeepaliveFailureLimit = 3
+	AttrP2PKeepalive             = "P2PKeepalive"
+)
+
+type KeepaliveMessage struct {
+	Seq uint32
+}
+
+// peerKeepalive tracks the probe sent to the peer. A probe is regarded as
+// failed if it's not acknowledged until the next probe.
+type peerKeepalive struct {
+	seq      uint32
+	sent     time.Time
+	pending  bool
+	failures int
+	mtx      sync.Mutex
+}
+
+// probe prepares a new probe and returns its sequence with the number of
+// consecutive failures including the previous probe.
+func (k *peerKeepalive) probe(now time.Time) (uint32, int) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if k.pending {
+		k.failures++
+	}
+	k.seq++
+	k.sent = now
+	k.pending = true
+	return k.seq, k.failures
+}
+
+// ack acknowledges the pending probe with the sequence and returns the round
+// trip time of it.
+func (k *peerKeepalive) ack(seq uint32, now time.Time) (time.Duration, bool) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if !k.pending || k.seq != seq {
+		return 0, false
+	}
+	k.pending = false
+	k.failures = 0
+	return now.Sub(k.sent), true
+}
+
+func supportKeepalive(p *Peer) bool {
+	v, ok := p.GetAttr(AttrP2PKeepalive)
+	return ok && v.(bool)
+}
+
+func (p2p *PeerToPeer) keepaliveRoutine() {
+	ticker := time.NewTicker(DefaultKeepalivePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p2p.stopCh:
+			return
+		case <-ticker.C:
+			p2p.probePeers()
+		}
+	}
+}
+
+func (p2p *PeerToPeer) probePeers() {
+	now := time.Now()
+	for _, p := range p2p.getPeers(false) {
+		if !supportKeepalive(p) || p.IsClosed() {
+			continue
+		}
+		seq, failures := p.keepalive.probe(now)
+		if failures >= DefaultKeepaliveFailureLimit {
+			p2p.logger.Infoln("probePeers", "Close, no keepalive response", failures, p)
+			p.CloseByError(ErrKeepaliveTimeout)
+			continue
+		}
+		p2p.sendKeepalive(p2pProtoPingReq, seq, p)
+	}
+}
+
+func (p2p *PeerToPeer) sendKeepalive(spi module.ProtocolInfo, seq uint32, p *Peer) {
+	m := &KeepaliveMessage{Seq: seq}
+	pkt := newPacket(p2pProtoControl, spi, p2p.encodeMsgpack(m), p2p.ID())
+	pkt.destPeer = p.ID()
+	if err := p.sendPacket(pkt); err != nil {
+		p2p.logger.Infoln("sendKeepalive", spi, err, p)
+	} else {
+		p2p.logger.Traceln("sendKeepalive", spi, m, p)
+	}
+}
+
+func (p2p *PeerToPeer) handlePing(pkt *Packet, p *Peer) {
+	rm := &KeepaliveMessage{}
+	if err := p2p.decodeMsgpack(pkt.payload, rm); err != nil {
+		p2p.logger.Infoln("handlePing", err, p)
+		return
+	}
+	p2p.sendKeepalive(p2pProtoPingResp, rm.Seq, p)
+}
+
+func (p2p *PeerToPeer) handlePong(pkt *Packet, p *Peer) {
+	rm := &KeepaliveMessage{}
+	if err := p2p.decodeMsgpack(pkt.payload, rm); err != nil {
+		p2p.logger.Infoln("handlePong", err, p)
+		return
+	}
+	d, ok := p.keepalive.ack(rm.Seq, time.Now())
+	if !ok {
+		p2p.logger.Debugln("handlePong", "ignore, unexpected sequence", rm.Seq, p)
+		return
+	}
+	p.rtt.Update(d)
+	if d >= DefaultRttLogThreshold {
+		p2p.logger.Warnln("RTT Threshold", DefaultRttLogThreshold, p)
+	}
+}
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerKeepalive(t *testing.T) {
+	k := &peerKeepalive{}
+	now := time.Now()
+
+	seq, failures := k.probe(now)
+	assert.Equal(t, 0, failures)
+	_, ok := k.ack(seq+1, now)
+	assert.False(t, ok, "unexpected sequence must be ignored")
+	d, ok := k.ack(seq, now.Add(10*time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, d)
+	_, ok = k.ack(seq, now)
+	assert.False(t, ok, "acknowledged probe must be ignored")
+
+	for i := 0; i < DefaultKeepaliveFailureLimit; i++ {
+		seq, failures = k.probe(now)
+		assert.Equal(t, i, failures)
+	}
+	_, failures = k.probe(now)
+	assert.Equal(t, DefaultKeepaliveFailureLimit, failures)
+
+	// late response of previous probe doesn't reset failures
+	_, ok = k.ack(seq, now)
+	assert.False(t, ok)
+	seq, failures = k.probe(now)
+	assert.Equal(t, DefaultKeepaliveFailureLimit+1, failures)
+	_, ok = k.ack(seq, now)
+	assert.True(t, ok)
+	_, failures = k.probe(now)
+	assert.Equal(t, 0, failures)
+}
+
+func TestPeerRTT_Update(t *testing.T) {
+	r := NewPeerRTT()
+	r.Update(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, r.last)
+	assert.Equal(t, 100*time.Millisecond, r.avg)
+	r.Update(200 * time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, r.last)
+	assert.Equal(t, 112500*time.Microsecond, r.avg)
+}
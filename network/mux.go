@@ -0,0 +1,524 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultMuxWindowSize   = 256 * 1024
+	DefaultMuxMaxFrameSize = 16 * 1024
+)
+
+const (
+	muxFrameHeaderSize = 7 // streamID(2), type(1), length(4)
+
+	muxFrameOpen   = byte(0x01)
+	muxFrameData   = byte(0x02)
+	muxFrameClose  = byte(0x03)
+	muxFrameWindow = byte(0x04)
+
+	muxInitialStreamID = uint16(0)
+)
+
+// ChannelMultiplexer is implemented by the transport supporting multiplexing
+// of channels over a single connection.
+type ChannelMultiplexer interface {
+	// SetMultiplexing enables multiplexing of channels. It's applied to the
+	// connections to be authenticated, and both nodes should enable it.
+	SetMultiplexing(enable bool)
+}
+
+type muxTimeoutError struct{}
+
+func (muxTimeoutError) Error() string   { return "i/o timeout" }
+func (muxTimeoutError) Timeout() bool   { return true }
+func (muxTimeoutError) Temporary() bool { return true }
+
+type muxAcceptCbFunc func(conn net.Conn, channel string)
+
+// muxManager keeps authenticated sessions by the addresses of the peers, so
+// the dialer opens a stream on the session instead of a new connection.
+type muxManager struct {
+	enabled  int32
+	sessions map[NetAddress]*muxSession
+	mtx      sync.Mutex
+	onAccept muxAcceptCbFunc
+	logger   log.Logger
+}
+
+func newMuxManager(cbFunc muxAcceptCbFunc, l log.Logger) *muxManager {
+	return &muxManager{
+		sessions: make(map[NetAddress]*muxSession),
+		onAccept: cbFunc,
+		logger:   l.WithFields(log.Fields{LoggerFieldKeySubModule: "mux"}),
+	}
+}
+
+func (m *muxManager) Enabled() bool {
+	return m != nil && atomic.LoadInt32(&m.enabled) == 1
+}
+
+func (m *muxManager) setEnabled(enable bool) {
+	if enable {
+		atomic.StoreInt32(&m.enabled, 1)
+	} else {
+		atomic.StoreInt32(&m.enabled, 0)
+	}
+}
+
+// newSession starts the session over the authenticated connection, and it
+// returns the initial stream for the channel of the connection. The session
+// of outgoing connection is registered with the dialed address.
+func (m *muxManager) newSession(conn net.Conn, in bool, id module.PeerID, dial NetAddress) net.Conn {
+	s := &muxSession{
+		m:       m,
+		conn:    conn,
+		id:      id,
+		streams: make(map[uint16]*muxStream),
+		done:    make(chan struct{}),
+	}
+	// streams opened by the dialer have odd ID, and others have even ID.
+	if in {
+		s.nextID = 2
+	} else {
+		s.nextID = 1
+	}
+	st := s.addStream(muxInitialStreamID)
+	go s.readRoutine()
+	m.register(dial, s)
+	return st
+}
+
+func (m *muxManager) register(na NetAddress, s *muxSession) {
+	if len(na) == 0 || s.IsClosed() {
+		return
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if old, ok := m.sessions[na]; !ok || old.IsClosed() {
+		m.sessions[na] = s
+	}
+}
+
+func (m *muxManager) unregister(s *muxSession) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for na, v := range m.sessions {
+		if v == s {
+			delete(m.sessions, na)
+		}
+	}
+}
+
+func (m *muxManager) session(na NetAddress) *muxSession {
+	if !m.Enabled() {
+		return nil
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.sessions[na]
+}
+
+// open returns the new stream for the channel on the session to the address.
+func (m *muxManager) open(na NetAddress, channel string) (net.Conn, bool) {
+	s := m.session(na)
+	if s == nil {
+		return nil, false
+	}
+	st, err := s.open(channel)
+	if err != nil {
+		m.logger.Debugln("open", na, channel, err)
+		return nil, false
+	}
+	return st, true
+}
+
+type muxSession struct {
+	m      *muxManager
+	conn   net.Conn
+	id     module.PeerID
+	wMtx   sync.Mutex
+	closed int32
+	done   chan struct{}
+
+	mtx     sync.Mutex
+	streams map[uint16]*muxStream
+	nextID  uint16
+}
+
+func (s *muxSession) IsClosed() bool {
+	return atomic.LoadInt32(&s.closed) == 1
+}
+
+func (s *muxSession) close() {
+	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		return
+	}
+	s.m.unregister(s)
+	_ = s.conn.Close()
+	close(s.done)
+
+	s.mtx.Lock()
+	streams := s.streams
+	s.streams = make(map[uint16]*muxStream)
+	s.mtx.Unlock()
+	for _, st := range streams {
+		st.onRemoteClose()
+	}
+}
+
+func (s *muxSession) addStream(id uint16) *muxStream {
+	st := &muxStream{
+		s:          s,
+		id:         id,
+		sendWindow: DefaultMuxWindowSize,
+		sendCh:     make(chan struct{}, 1),
+		recvCh:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.streams[id] = st
+	return st
+}
+
+func (s *muxSession) getStream(id uint16) *muxStream {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.streams[id]
+}
+
+// removeStream removes the stream, and it closes the session without streams.
+func (s *muxSession) removeStream(id uint16) {
+	s.mtx.Lock()
+	delete(s.streams, id)
+	empty := len(s.streams) == 0
+	s.mtx.Unlock()
+	if empty {
+		s.close()
+	}
+}
+
+func (s *muxSession) open(channel string) (*muxStream, error) {
+	if s.IsClosed() {
+		return nil, ErrNotAvailable
+	}
+	s.mtx.Lock()
+	id := s.nextID
+	s.nextID += 2
+	s.mtx.Unlock()
+
+	st := s.addStream(id)
+	if err := s.writeFrame(muxFrameOpen, id, []byte(channel), time.Time{}); err != nil {
+		s.removeStream(id)
+		return nil, err
+	}
+	return st, nil
+}
+
+func (s *muxSession) writeFrame(typ byte, id uint16, payload []byte, deadline time.Time) error {
+	if s.IsClosed() {
+		return io.ErrClosedPipe
+	}
+	frame := make([]byte, muxFrameHeaderSize+len(payload))
+	binary.BigEndian.PutUint16(frame[0:2], id)
+	frame[2] = typ
+	binary.BigEndian.PutUint32(frame[3:7], uint32(len(payload)))
+	copy(frame[muxFrameHeaderSize:], payload)
+
+	s.wMtx.Lock()
+	defer s.wMtx.Unlock()
+	if deadline.IsZero() {
+		deadline = time.Now().Add(DefaultSendTimeout)
+	}
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if _, err := s.conn.Write(frame); err != nil {
+		s.m.logger.Debugln("writeFrame", err)
+		go s.close()
+		return err
+	}
+	return nil
+}
+
+func (s *muxSession) readRoutine() {
+	defer s.close()
+
+	header := make([]byte, muxFrameHeaderSize)
+	for {
+		if _, err := io.ReadFull(s.conn, header); err != nil {
+			s.m.logger.Tracef("readRoutine err=%+v", err)
+			return
+		}
+		id := binary.BigEndian.Uint16(header[0:2])
+		typ := header[2]
+		size := binary.BigEndian.Uint32(header[3:7])
+		if size > DefaultMuxMaxFrameSize {
+			s.m.logger.Infoln("readRoutine", "invalid frame size", size)
+			return
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(s.conn, payload); err != nil {
+			s.m.logger.Tracef("readRoutine err=%+v", err)
+			return
+		}
+		if err := s.handleFrame(typ, id, payload); err != nil {
+			s.m.logger.Infoln("readRoutine", err)
+			return
+		}
+	}
+}
+
+func (s *muxSession) handleFrame(typ byte, id uint16, payload []byte) error {
+	switch typ {
+	case muxFrameOpen:
+		if id == muxInitialStreamID || s.getStream(id) != nil {
+			return errors.Errorf("InvalidStreamID(%d)", id)
+		}
+		st := s.addStream(id)
+		s.m.onAccept(st, string(payload))
+	case muxFrameData:
+		if st := s.getStream(id); st != nil {
+			return st.push(payload)
+		}
+	case muxFrameWindow:
+		if len(payload) != 4 {
+			return errors.Errorf("InvalidWindowUpdate(size=%d)", len(payload))
+		}
+		if st := s.getStream(id); st != nil {
+			st.addSendWindow(int(binary.BigEndian.Uint32(payload)))
+		}
+	case muxFrameClose:
+		if st := s.getStream(id); st != nil {
+			st.onRemoteClose()
+			s.removeStream(id)
+		}
+	default:
+		return errors.Errorf("InvalidFrameType(%d)", typ)
+	}
+	return nil
+}
+
+// muxStream is the connection for a channel over the session. The sender
+// keeps the window, and it waits for the update from the receiver after
+// sending data as much as the window, so each channel is flow-controlled
+// separately.
+type muxStream struct {
+	s  *muxSession
+	id uint16
+
+	mtx          sync.Mutex
+	buf          bytes.Buffer
+	recvPending  int
+	consumed     int
+	sendWindow   int
+	rDeadline    time.Time
+	wDeadline    time.Time
+	closed       bool
+	remoteClosed bool
+
+	sendCh chan struct{}
+	recvCh chan struct{}
+	done   chan struct{}
+}
+
+func notifyMux(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (st *muxStream) push(b []byte) error {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	if st.closed {
+		return nil
+	}
+	st.recvPending += len(b)
+	if st.recvPending > DefaultMuxWindowSize {
+		return errors.Errorf("WindowExceeded(stream=%d,size=%d)", st.id, st.recvPending)
+	}
+	st.buf.Write(b)
+	notifyMux(st.recvCh)
+	return nil
+}
+
+func (st *muxStream) addSendWindow(n int) {
+	st.mtx.Lock()
+	st.sendWindow += n
+	st.mtx.Unlock()
+	notifyMux(st.sendCh)
+}
+
+func (st *muxStream) onRemoteClose() {
+	st.mtx.Lock()
+	st.remoteClosed = true
+	st.mtx.Unlock()
+	notifyMux(st.recvCh)
+	notifyMux(st.sendCh)
+}
+
+func waitMux(ch chan struct{}, done chan struct{}, deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return muxTimeoutError{}
+		}
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case <-ch:
+	case <-done:
+	case <-timeout:
+		return muxTimeoutError{}
+	}
+	return nil
+}
+
+func (st *muxStream) Read(b []byte) (int, error) {
+	for {
+		st.mtx.Lock()
+		if st.buf.Len() > 0 {
+			n, _ := st.buf.Read(b)
+			st.consumed += n
+			var inc int
+			if st.consumed >= DefaultMuxWindowSize/2 {
+				inc = st.consumed
+				st.recvPending -= inc
+				st.consumed = 0
+			}
+			st.mtx.Unlock()
+			if inc > 0 {
+				wb := make([]byte, 4)
+				binary.BigEndian.PutUint32(wb, uint32(inc))
+				_ = st.s.writeFrame(muxFrameWindow, st.id, wb, time.Time{})
+			}
+			return n, nil
+		}
+		if st.closed || st.remoteClosed {
+			st.mtx.Unlock()
+			return 0, io.EOF
+		}
+		deadline := st.rDeadline
+		st.mtx.Unlock()
+
+		if err := waitMux(st.recvCh, st.done, deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// reserve waits for the window, then it returns the size to send.
+func (st *muxStream) reserve(size int) (int, time.Time, error) {
+	for {
+		st.mtx.Lock()
+		if st.closed || st.remoteClosed {
+			st.mtx.Unlock()
+			return 0, time.Time{}, io.ErrClosedPipe
+		}
+		deadline := st.wDeadline
+		if st.sendWindow > 0 {
+			n := size
+			if n > st.sendWindow {
+				n = st.sendWindow
+			}
+			if n > DefaultMuxMaxFrameSize {
+				n = DefaultMuxMaxFrameSize
+			}
+			st.sendWindow -= n
+			st.mtx.Unlock()
+			return n, deadline, nil
+		}
+		st.mtx.Unlock()
+
+		if err := waitMux(st.sendCh, st.done, deadline); err != nil {
+			return 0, deadline, err
+		}
+	}
+}
+
+func (st *muxStream) Write(b []byte) (int, error) {
+	written := 0
+	for written < len(b) {
+		n, deadline, err := st.reserve(len(b) - written)
+		if err != nil {
+			return written, err
+		}
+		if err = st.s.writeFrame(muxFrameData, st.id, b[written:written+n], deadline); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
+
+func (st *muxStream) Close() error {
+	st.mtx.Lock()
+	if st.closed {
+		st.mtx.Unlock()
+		return nil
+	}
+	st.closed = true
+	remoteClosed := st.remoteClosed
+	st.mtx.Unlock()
+	close(st.done)
+
+	if !remoteClosed {
+		_ = st.s.writeFrame(muxFrameClose, st.id, nil, time.Time{})
+	}
+	st.s.removeStream(st.id)
+	return nil
+}
+
+func (st *muxStream) LocalAddr() net.Addr {
+	return st.s.conn.LocalAddr()
+}
+
+func (st *muxStream) RemoteAddr() net.Addr {
+	return st.s.conn.RemoteAddr()
+}
+
+func (st *muxStream) SetDeadline(t time.Time) error {
+	st.mtx.Lock()
+	st.rDeadline = t
+	st.wDeadline = t
+	st.mtx.Unlock()
+	notifyMux(st.recvCh)
+	notifyMux(st.sendCh)
+	return nil
+}
+
+func (st *muxStream) SetReadDeadline(t time.Time) error {
+	st.mtx.Lock()
+	st.rDeadline = t
+	st.mtx.Unlock()
+	notifyMux(st.recvCh)
+	return nil
+}
+
+func (st *muxStream) SetWriteDeadline(t time.Time) error {
+	st.mtx.Lock()
+	st.wDeadline = t
+	st.mtx.Unlock()
+	notifyMux(st.sendCh)
+	return nil
+}
@@ -0,0 +1,107 @@
+package network
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+type testMuxAccept struct {
+	conn    net.Conn
+	channel string
+}
+
+func newTestMuxPair(t *testing.T) (net.Conn, net.Conn, *muxManager, chan testMuxAccept) {
+	ch := make(chan testMuxAccept, 1)
+	m1 := newMuxManager(func(conn net.Conn, channel string) {
+		t.Errorf("unexpected accept channel=%s", channel)
+	}, log.New())
+	m2 := newMuxManager(func(conn net.Conn, channel string) {
+		ch <- testMuxAccept{conn, channel}
+	}, log.New())
+	m1.setEnabled(true)
+	m2.setEnabled(true)
+
+	a, b := net.Pipe()
+	c1 := m1.newSession(a, false, generatePeerID(), "127.0.0.1:8080")
+	c2 := m2.newSession(b, true, generatePeerID(), "")
+	return c1, c2, m1, ch
+}
+
+func readFullWithTimeout(t *testing.T, c net.Conn, n int) []byte {
+	bs := make([]byte, n)
+	assert.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err := io.ReadFull(c, bs)
+	assert.NoError(t, err)
+	return bs
+}
+
+func TestMux_Stream(t *testing.T) {
+	c1, c2, m1, ch := newTestMuxPair(t)
+
+	_, err := c1.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), readFullWithTimeout(t, c2, 5))
+
+	conn, ok := m1.open("127.0.0.1:8080", "other")
+	assert.True(t, ok)
+	var accepted testMuxAccept
+	select {
+	case accepted = <-ch:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no accepted stream")
+	}
+	assert.Equal(t, "other", accepted.channel)
+
+	_, err = accepted.conn.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("world"), readFullWithTimeout(t, conn, 5))
+
+	// closing a stream doesn't affect others
+	assert.NoError(t, conn.Close())
+	assert.NoError(t, accepted.conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = accepted.conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	_, err = c2.Write([]byte("again"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("again"), readFullWithTimeout(t, c1, 5))
+
+	// session is closed without streams
+	assert.NoError(t, c1.Close())
+	_, err = c2.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+	_, ok = m1.open("127.0.0.1:8080", "other")
+	assert.False(t, ok)
+}
+
+func TestMux_FlowControl(t *testing.T) {
+	c1, c2, _, _ := newTestMuxPair(t)
+	defer c1.Close()
+
+	data := make([]byte, DefaultMuxWindowSize+DefaultMuxMaxFrameSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	// sender waits for the window
+	assert.NoError(t, c1.SetWriteDeadline(time.Now().Add(100*time.Millisecond)))
+	n, err := c1.Write(data)
+	assert.Equal(t, DefaultMuxWindowSize, n)
+	if ne, ok := err.(net.Error); assert.True(t, ok) {
+		assert.True(t, ne.Timeout())
+	}
+
+	// the window is updated after the receiver reads
+	assert.Equal(t, data[:DefaultMuxWindowSize], readFullWithTimeout(t, c2, DefaultMuxWindowSize))
+	assert.NoError(t, c1.SetWriteDeadline(time.Now().Add(time.Second)))
+	n, err = c1.Write(data[DefaultMuxWindowSize:])
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMuxMaxFrameSize, n)
+	assert.Equal(t, data[DefaultMuxWindowSize:], readFullWithTimeout(t, c2, DefaultMuxMaxFrameSize))
+}
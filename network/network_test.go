@@ -288,6 +288,7 @@ func (c *dummyChain) Logger() log.Logger             { return c.logger }
 func (c *dummyChain) MetricContext() context.Context { return c.metricCtx }
 func (c *dummyChain) ChildrenLimit() int             { return -1 }
 func (c *dummyChain) NephewsLimit() int              { return -1 }
+func (c *dummyChain) WALRetention() int              { return -1 }
 
 func generateNetwork(name string, port int, n int, t *testing.T, roles ...module.Role) ([]*testReactor, int) {
 	arr := make([]*testReactor, n)
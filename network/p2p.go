@@ -3,12 +3,15 @@ package network
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -57,6 +60,7 @@ const (
 	AttrP2PConnectionRequest    = "P2PConnectionRequest"
 	AttrP2PLegacy               = "P2PLegacy"
 	AttrSupportDefaultProtocols = "SupportDefaultProtocols"
+	AttrP2PAuditedRole          = "P2PAuditedRole"
 	DefaultQueryElementLength   = 200
 )
 
@@ -72,6 +76,8 @@ var (
 	p2pProtoConnResp  = module.ProtocolInfo(0x0A00)
 	p2pProtoRttReq    = module.ProtocolInfo(0x0B00)
 	p2pProtoRttResp   = module.ProtocolInfo(0x0C00)
+	p2pProtoPingReq   = module.ProtocolInfo(0x0D00)
+	p2pProtoPingResp  = module.ProtocolInfo(0x0E00)
 )
 
 type PeerToPeer struct {
@@ -116,7 +122,15 @@ type PeerToPeer struct {
 	logger log.Logger
 
 	//monitor
-	mtr *metric.NetworkMetric
+	mtr   *metric.NetworkMetric
+	audit *auditLog
+
+	//discovery query
+	queryLimiter *rate.Limiter
+	puzzle       *queryPuzzle
+
+	policies  *packetPolicies
+	diversity *peerDiversity
 
 	stopCh chan bool
 	run    bool
@@ -171,6 +185,8 @@ func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMet
 		logger: p2pLogger,
 		//
 		mtr: mtr,
+		//
+		queryLimiter: rate.NewLimiter(DefaultQueryTotalRate, DefaultQueryTotalBurst),
 	}
 	p2p.allowedRoots.onUpdate = func(s *PeerIDSet) {
 		p2p.onAllowedPeerIDSetUpdate(s, p2pRoleRoot)
@@ -204,6 +220,7 @@ func (p2p *PeerToPeer) Start() {
 	go p2p.sendRoutine()
 	go p2p.alternateSendRoutine()
 	go p2p.discoverRoutine()
+	go p2p.keepaliveRoutine()
 }
 
 func (p2p *PeerToPeer) Stop() {
@@ -247,6 +264,12 @@ func (p2p *PeerToPeer) supportedProtocols() []module.ProtocolInfo {
 }
 
 func (p2p *PeerToPeer) dial(na NetAddress) error {
+	if !p2p.trustSeeds.Contains(na) {
+		if err := p2p.checkDiversity(ipOfNetAddress(na), nil); err != nil {
+			p2p.logger.Infoln("Dial skip", na, err)
+			return nil
+		}
+	}
 	if err := p2p.dialer.Dial(string(na)); err != nil {
 		if err == ErrAlreadyDialing {
 			p2p.logger.Infoln("Dial ignore", na, err)
@@ -302,11 +325,19 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 	p2p.logger.Debugln("onPeer", p)
 	if !p2p.allowedPeers.IsEmpty() && !p2p.allowedPeers.Contains(p.ID()) {
 		p2p.onEvent(p2pEventNotAllowed, p)
+		p2p.audit.record(AuditStageP2P, AuditResultRejected, p, "not allowed peer")
 		p.CloseByError(fmt.Errorf("onPeer not allowed connection"))
 		return
 	}
 	if p2p.isTrustSeed(p) {
 		p2p.trustSeeds.SetAndRemoveByData(p.DialNetAddress(), string(p.NetAddress()))
+	} else if !p2p.allowedRoots.Contains(p.ID()) {
+		if err := p2p.checkDiversity(ipOfAddr(p.conn.RemoteAddr()), p.ID()); err != nil {
+			p2p.audit.record(AuditStageP2P, AuditResultRejected, p, err.Error())
+			p.CloseByError(err)
+			p2p.logger.Infoln("onPeer", "reject by diversity", p, err)
+			return
+		}
 	}
 	if dp := p2p.getPeer(p.ID(), false); dp != nil {
 		p2p.onEvent(p2pEventDuplicate, p)
@@ -317,6 +348,7 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 
 		if diff < DefaultDuplicatedPeerTime && dp.In() != p.In() && higher == p.In() {
 			//close new which is lower's outgoing
+			p2p.audit.record(AuditStageP2P, AuditResultRejected, p, ErrDuplicatedPeer.Error())
 			p.CloseByError(ErrDuplicatedPeer)
 			p2p.logger.Infoln("Already exists connected Peer, close new", p, diff)
 			return
@@ -447,6 +479,10 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 				p2p.handleRttRequest(pkt, p)
 			case p2pProtoRttResp:
 				p2p.handleRttResponse(pkt, p)
+			case p2pProtoPingReq:
+				p2p.handlePing(pkt, p)
+			case p2pProtoPingResp:
+				p2p.handlePong(pkt, p)
 			case p2pProtoConnReq:
 				p2p.handleP2PConnectionRequest(pkt, p)
 			case p2pProtoConnResp:
@@ -484,6 +520,14 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 			return
 		}
 
+		if !isOneHop {
+			if reason := p2p.policies.dropReasonOnReceive(pkt, isSourcePeer); reason != "" {
+				p2p.logger.Traceln("onPacket", "Drop, by policy", reason, pkt.protocol, pkt.subProtocol, pkt.extendInfo, p.ID())
+				p2p.mtr.OnDropPacket(pkt.protocol.Uint16(), reason)
+				return
+			}
+		}
+
 		if cbFunc := p2p.onPacketCbFuncs[pkt.protocol.Uint16()]; cbFunc != nil {
 			if isOneHop || p2p.packetPool.Put(pkt) {
 				cbFunc(pkt, p)
@@ -514,7 +558,8 @@ func (p2p *PeerToPeer) decodeMsgpack(b []byte, v interface{}) error {
 
 //TODO timestamp or sequencenumber for validation (query,result pair)
 type QueryMessage struct {
-	Role PeerRoleFlag
+	Role  PeerRoleFlag
+	Nonce []byte
 }
 
 type QueryResultMessage struct {
@@ -524,6 +569,10 @@ type QueryResultMessage struct {
 	Children []NetAddress
 	Nephews  []NetAddress
 	Message  string
+
+	// Challenge and Difficulty are set if the query is dropped for the puzzle.
+	Challenge  []byte
+	Difficulty int
 }
 
 type RttMessage struct {
@@ -643,6 +692,9 @@ func (p2p *PeerToPeer) stopRtt(p *Peer) {
 
 func (p2p *PeerToPeer) sendQuery(p *Peer) {
 	m := &QueryMessage{Role: p2p.Role()}
+	if v, ok := p.GetAttr(AttrP2PQueryPuzzleNonce); ok {
+		m.Nonce = v.([]byte)
+	}
 	pkt := newPacket(p2pProtoControl, p2pProtoQueryReq, p2p.encodeMsgpack(m), p2p.ID())
 	pkt.destPeer = p.ID()
 	err := p.sendPacket(pkt)
@@ -654,6 +706,49 @@ func (p2p *PeerToPeer) sendQuery(p *Peer) {
 	}
 }
 
+// checkQuery returns the reason to drop the query, or empty string if it's
+// allowed. Seeds require the solution of the puzzle if it's enabled.
+func (p2p *PeerToPeer) checkQuery(qm *QueryMessage, p *Peer) string {
+	if !queryLimiterOf(p).Allow() || !p2p.queryLimiter.Allow() {
+		return queryDropReasonRate
+	}
+	if p2p.Role().Has(p2pRoleSeed) && !p2p.puzzle.verify(p.ID(), qm.Nonce) {
+		return queryDropReasonPuzzle
+	}
+	return ""
+}
+
+func (p2p *PeerToPeer) sendQueryPuzzle(p *Peer) {
+	m := &QueryResultMessage{
+		Role:       p2p.Role(),
+		Challenge:  p2p.puzzle.challenge(p.ID()),
+		Difficulty: p2p.puzzle.Difficulty(),
+	}
+	pkt := newPacket(p2pProtoControl, p2pProtoQueryResp, p2p.encodeMsgpack(m), p2p.ID())
+	pkt.destPeer = p.ID()
+	if err := p.sendPacket(pkt); err != nil {
+		p2p.logger.Infoln("sendQueryPuzzle", err, p)
+	} else {
+		p2p.logger.Traceln("sendQueryPuzzle", m, p)
+	}
+}
+
+// solveQueryPuzzle solves the puzzle in background, then it sends the query
+// again with the solution.
+func (p2p *PeerToPeer) solveQueryPuzzle(qrm *QueryResultMessage, p *Peer) {
+	if qrm.Difficulty <= 0 || qrm.Difficulty > MaxQueryPuzzleDifficulty || len(qrm.Challenge) == 0 {
+		p2p.logger.Infoln("solveQueryPuzzle", "invalid puzzle", qrm.Difficulty, p)
+		return
+	}
+	go func() {
+		nonce := solveQueryPuzzle(qrm.Challenge, qrm.Difficulty)
+		p.PutAttr(AttrP2PQueryPuzzleNonce, nonce)
+		if !p.IsClosed() {
+			p2p.sendQuery(p)
+		}
+	}()
+}
+
 func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 	qm := &QueryMessage{}
 	err := p2p.decodeMsgpack(pkt.payload, qm)
@@ -664,6 +759,14 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 	p2p.logger.Traceln("handleQuery", qm, p)
 
 	r := p2p.Role()
+	if reason := p2p.checkQuery(qm, p); reason != "" {
+		p2p.mtr.OnDropQuery(reason)
+		p2p.logger.Debugln("handleQuery", "drop", reason, p)
+		if reason == queryDropReasonPuzzle {
+			p2p.sendQueryPuzzle(p)
+		}
+		return
+	}
 	m := &QueryResultMessage{
 		Role:     r,
 		Children: p2p.children.NetAddresses(),
@@ -680,14 +783,17 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 		p2p.applyPeerRole(p)
 	}
 	if rr.Has(p2pRoleSeed) || rr.Has(p2pRoleRoot) {
+		p2p.auditAccepted(p, m.Message)
 		m.Roots = p2p.roots.Array()
 		m.Seeds = p2p.seeds.Array()
 	} else {
 		if r.Has(p2pRoleRoot) {
 			p2p.logger.Infoln("handleQuery", "not allowed connection", p)
+			p2p.audit.record(AuditStageP2P, AuditResultRejected, p, "not allowed role")
 			p.Close("handleQuery not allowed connection")
 			return
 		}
+		p2p.auditAccepted(p, m.Message)
 		m.Seeds = make([]NetAddress, 0)
 		for _, s := range p2p.seeds.Array() {
 			if !p2p.roots.Contains(s) {
@@ -720,6 +826,17 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 	}
 }
 
+// auditAccepted records the peer accepted at the first query and when the
+// claimed role is changed, because queries are repeated while discovery.
+func (p2p *PeerToPeer) auditAccepted(p *Peer, reason string) {
+	role := p.RecvRole()
+	if v, ok := p.GetAttr(AttrP2PAuditedRole); ok && v.(PeerRoleFlag) == role {
+		return
+	}
+	p.PutAttr(AttrP2PAuditedRole, role)
+	p2p.audit.record(AuditStageP2P, AuditResultAccepted, p, reason)
+}
+
 func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 	qrm := &QueryResultMessage{}
 	err := p2p.decodeMsgpack(pkt.payload, qrm)
@@ -728,6 +845,10 @@ func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 		return
 	}
 	p2p.stopRtt(p)
+	if len(qrm.Challenge) > 0 {
+		p2p.solveQueryPuzzle(qrm, p)
+		return
+	}
 	if len(qrm.Roots) > DefaultQueryElementLength {
 		p2p.logger.Infoln("handleQueryResult", "invalid Roots Length:", len(qrm.Roots), p)
 		qrm.Roots = qrm.Roots[:DefaultQueryElementLength]
@@ -749,9 +870,10 @@ func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 	p.children.ClearAndAdd(qrm.Children...)
 	p.nephews.ClearAndAdd(qrm.Nephews...)
 
+	var msg string
 	rr := p2p.resolveRole(qrm.Role, p.ID(), true)
 	if rr != qrm.Role {
-		msg := fmt.Sprintf("not equal resolved role %d, expected %d", rr, qrm.Role)
+		msg = fmt.Sprintf("not equal resolved role %d, expected %d", rr, qrm.Role)
 		p2p.logger.Infoln("handleQueryResult", msg, p)
 	}
 	p.setRecvRole(qrm.Role)
@@ -762,10 +884,12 @@ func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 	if !rr.Has(p2pRoleSeed) && !rr.Has(p2pRoleRoot) {
 		if !p2p.isTrustSeed(p) {
 			p2p.logger.Infoln("handleQueryResult", "invalid query, not allowed connection", p)
+			p2p.audit.record(AuditStageP2P, AuditResultRejected, p, "not allowed role")
 			p.CloseByError(fmt.Errorf("handleQueryResult invalid query, resolved role %d", rr))
 			return
 		}
 	}
+	p2p.auditAccepted(p, msg)
 
 	r := p2p.Role()
 	if r.Has(p2pRoleSeed) || r.Has(p2pRoleRoot) {
@@ -922,7 +1046,7 @@ func (p2p *PeerToPeer) sendToFriends(ctx context.Context) {
 	if UsingSelectiveFlooding { //selective (F+1) flooding with node-list
 		pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
 		ps, ext := p2p.selectPeersFromFriends(pkt)
-		pkt.extendInfo = newPacketExtendInfo(pkt.extendInfo.hint()+1, pkt.extendInfo.len()+len(ext))
+		pkt.extendInfo = newPacketExtendInfo(pkt.extendInfo.hint(), pkt.extendInfo.len()+len(ext))
 		if len(pkt.ext) > 0 {
 			ext = append(pkt.ext, ext...)
 		}
@@ -936,7 +1060,7 @@ func (p2p *PeerToPeer) sendToFriends(ctx context.Context) {
 		}
 	} else {
 		pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
-		pkt.extendInfo = newPacketExtendInfo(pkt.extendInfo.hint()+1, 0)
+		pkt.extendInfo = newPacketExtendInfo(pkt.extendInfo.hint(), 0)
 		pkt.footerToBytes(true)
 		p2p.sendToPeers(ctx, p2p.friends)
 	}
@@ -960,6 +1084,7 @@ Loop:
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
 				c := ctx.Value(p2pContextKeyCounter).(*Counter)
 				_ = pkt.updateHash(false)
+				increaseHops(pkt)
 				//TODO p2p.packet_dump
 				//p2p.logger.Traceln("sendRoutine", pkt)
 				// p2p.packetRw.WritePacket(pkt)
@@ -1125,6 +1250,12 @@ func (p2p *PeerToPeer) Send(pkt *Packet) error {
 		pkt.src = p2p.ID()
 	}
 
+	if reason := p2p.policies.dropReasonOnSend(pkt, !p2p.ID().Equal(pkt.src)); reason != "" {
+		p2p.logger.Traceln("Send", "Drop, by policy", reason, pkt.protocol, pkt.subProtocol, pkt.extendInfo)
+		p2p.mtr.OnDropPacket(pkt.protocol.Uint16(), reason)
+		return nil
+	}
+
 	if pkt.dest == p2pDestAny && pkt.ttl == 0 &&
 		p2p.ID().Equal(pkt.src) &&
 		!p2p.HasRole(p2pRoleRoot) {
@@ -1566,6 +1697,22 @@ func (p2p *PeerToPeer) discoverFriends() {
 	}
 }
 
+// checkDiversity checks the limits of peers sharing a subnet or an ASN with
+// the peer at the IP. The peer of the id is excluded from connected peers.
+func (p2p *PeerToPeer) checkDiversity(ip net.IP, id module.PeerID) error {
+	if ip == nil || !p2p.diversity.enabled() {
+		return nil
+	}
+	var others []net.IP
+	for _, op := range p2p.getPeers(false) {
+		if id != nil && id.Equal(op.ID()) {
+			continue
+		}
+		others = append(others, ipOfAddr(op.conn.RemoteAddr()))
+	}
+	return p2p.diversity.check(ip, others)
+}
+
 func (p2p *PeerToPeer) isTrustSeed(p *Peer) bool {
 	return p2p.trustSeeds.Contains(p.DialNetAddress())
 }
@@ -0,0 +1,143 @@
+package network
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	packetDropReasonTTL  = "ttl"
+	packetDropReasonHops = "hops"
+)
+
+var protocolsByName = map[string]module.ProtocolInfo{
+	"statesync":     module.ProtoStateSync,
+	"transaction":   module.ProtoTransaction,
+	"consensus":     module.ProtoConsensus,
+	"fastsync":      module.ProtoFastSync,
+	"consensussync": module.ProtoConsensusSync,
+	"light":         module.ProtoLight,
+	"genesis":       module.ProtoGenesis,
+	"snapshot":      module.ProtoSnapshot,
+	"prioritytx":    module.ProtoPriorityTransaction,
+}
+
+// ProtocolByName returns the protocol for the name used in configuration.
+func ProtocolByName(name string) (module.ProtocolInfo, bool) {
+	pi, ok := protocolsByName[name]
+	return pi, ok
+}
+
+// PacketPolicy limits propagation of the packets of a protocol. Zero value
+// keeps the behavior requested by the reactors.
+type PacketPolicy struct {
+	// TTL overrides TTL of the broadcast and multicast packets sent by
+	// the node (module.BroadcastType.TTL()). Non-zero TTL prevents relaying,
+	// so packets relayed by other peers are dropped.
+	TTL byte
+	// Hops limits the number of peers sending a packet including the source.
+	// Packets with more hops are dropped, and the packets reached the limit
+	// are not relayed.
+	Hops byte
+}
+
+func (pp PacketPolicy) validate() error {
+	if pp.TTL > module.BROADCAST_CHILDREN.TTL() {
+		return errors.IllegalArgumentError.Errorf("InvalidTTL(%d)", pp.TTL)
+	}
+	if pp.Hops > packetExtendMaxHint {
+		return errors.IllegalArgumentError.Errorf("InvalidHops(%d)", pp.Hops)
+	}
+	return nil
+}
+
+// PacketPolicySetter is implemented by the transport supporting policies of
+// packet propagation.
+type PacketPolicySetter interface {
+	// SetPacketPolicy applies the policy to the packets of the protocol
+	// regardless of its version.
+	SetPacketPolicy(pi module.ProtocolInfo, pp PacketPolicy) error
+}
+
+type packetPolicies struct {
+	mtx sync.RWMutex
+	m   map[byte]PacketPolicy
+}
+
+func newPacketPolicies() *packetPolicies {
+	return &packetPolicies{m: make(map[byte]PacketPolicy)}
+}
+
+func (pps *packetPolicies) set(pi module.ProtocolInfo, pp PacketPolicy) error {
+	if pi.ID() == p2pProtoControl.ID() {
+		return errors.IllegalArgumentError.Errorf("InvalidProtocol(%s)", pi)
+	}
+	if err := pp.validate(); err != nil {
+		return err
+	}
+	pps.mtx.Lock()
+	defer pps.mtx.Unlock()
+
+	if pp == (PacketPolicy{}) {
+		delete(pps.m, pi.ID())
+	} else {
+		pps.m[pi.ID()] = pp
+	}
+	return nil
+}
+
+func (pps *packetPolicies) get(pi module.ProtocolInfo) PacketPolicy {
+	if pps == nil {
+		return PacketPolicy{}
+	}
+	pps.mtx.RLock()
+	defer pps.mtx.RUnlock()
+
+	return pps.m[pi.ID()]
+}
+
+// dropReasonOnSend returns the reason to drop the packet to be sent, and it
+// applies TTL of the policy to the packets of the node.
+func (pps *packetPolicies) dropReasonOnSend(pkt *Packet, relay bool) string {
+	if pkt.dest == p2pDestPeer {
+		return ""
+	}
+	pp := pps.get(pkt.protocol)
+	if relay {
+		if pp.TTL != 0 {
+			return packetDropReasonTTL
+		}
+		if pp.Hops != 0 && pkt.extendInfo.hint() >= pp.Hops {
+			return packetDropReasonHops
+		}
+	} else if pp.TTL != 0 && pkt.ttl == 0 {
+		pkt.ttl = pp.TTL
+	}
+	return ""
+}
+
+// dropReasonOnReceive returns the reason to drop the multi-hop packet
+// received from the sender.
+func (pps *packetPolicies) dropReasonOnReceive(pkt *Packet, fromSource bool) string {
+	pp := pps.get(pkt.protocol)
+	if pp.TTL != 0 && !fromSource {
+		return packetDropReasonTTL
+	}
+	if pp.Hops != 0 && pkt.extendInfo.hint() > pp.Hops {
+		return packetDropReasonHops
+	}
+	return ""
+}
+
+// increaseHops counts the node as a sender of the multi-hop packet.
+func increaseHops(pkt *Packet) {
+	if pkt.ttl != 0 || pkt.dest == p2pDestPeer {
+		return
+	}
+	if hops := pkt.extendInfo.hint(); hops < packetExtendMaxHint {
+		pkt.extendInfo = newPacketExtendInfo(hops+1, pkt.extendInfo.len())
+		pkt.footerToBytes(true)
+	}
+}
@@ -0,0 +1,77 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestPacketPolicies_Set(t *testing.T) {
+	pps := newPacketPolicies()
+	assert.Error(t, pps.set(p2pProtoControl, PacketPolicy{Hops: 1}))
+	assert.Error(t, pps.set(module.ProtoTransaction, PacketPolicy{TTL: 3}))
+	assert.Error(t, pps.set(module.ProtoTransaction, PacketPolicy{Hops: packetExtendMaxHint + 1}))
+
+	assert.NoError(t, pps.set(module.ProtoTransaction, PacketPolicy{Hops: 3}))
+	v1 := module.NewProtocolInfo(module.ProtoTransaction.ID(), 1)
+	assert.Equal(t, PacketPolicy{Hops: 3}, pps.get(v1))
+	assert.Equal(t, PacketPolicy{}, pps.get(module.ProtoConsensus))
+
+	assert.NoError(t, pps.set(module.ProtoTransaction, PacketPolicy{}))
+	assert.Empty(t, pps.m)
+
+	var nilPolicies *packetPolicies
+	assert.Equal(t, PacketPolicy{}, nilPolicies.get(module.ProtoTransaction))
+}
+
+func TestPacketPolicies_TTL(t *testing.T) {
+	pps := newPacketPolicies()
+	assert.NoError(t, pps.set(module.ProtoConsensus, PacketPolicy{TTL: 1}))
+
+	pkt := NewPacket(module.ProtoConsensus, module.ProtoConsensus, []byte("test"))
+	pkt.dest = p2pDestAny
+	assert.Equal(t, "", pps.dropReasonOnSend(pkt, false))
+	assert.EqualValues(t, 1, pkt.ttl, "TTL of the policy must be applied")
+
+	pkt.ttl = 0
+	assert.Equal(t, packetDropReasonTTL, pps.dropReasonOnSend(pkt, true))
+	assert.Equal(t, "", pps.dropReasonOnReceive(pkt, true))
+	assert.Equal(t, packetDropReasonTTL, pps.dropReasonOnReceive(pkt, false))
+
+	pkt.dest = p2pDestPeer
+	assert.Equal(t, "", pps.dropReasonOnSend(pkt, true))
+	assert.EqualValues(t, 0, pkt.ttl)
+}
+
+func TestPacketPolicies_Hops(t *testing.T) {
+	pps := newPacketPolicies()
+	assert.NoError(t, pps.set(module.ProtoTransaction, PacketPolicy{Hops: 2}))
+
+	pkt := NewPacket(module.ProtoTransaction, module.ProtoTransaction, []byte("test"))
+	pkt.dest = p2pDestAny
+	assert.Equal(t, "", pps.dropReasonOnSend(pkt, false))
+	assert.EqualValues(t, 0, pkt.ttl)
+
+	increaseHops(pkt)
+	assert.EqualValues(t, 1, pkt.extendInfo.hint())
+	assert.Equal(t, "", pps.dropReasonOnReceive(pkt, true))
+	assert.Equal(t, "", pps.dropReasonOnSend(pkt, true))
+
+	increaseHops(pkt)
+	assert.Equal(t, "", pps.dropReasonOnReceive(pkt, false))
+	assert.Equal(t, packetDropReasonHops, pps.dropReasonOnSend(pkt, true))
+
+	increaseHops(pkt)
+	assert.Equal(t, packetDropReasonHops, pps.dropReasonOnReceive(pkt, false))
+
+	pkt.extendInfo = newPacketExtendInfo(packetExtendMaxHint, 0)
+	increaseHops(pkt)
+	assert.EqualValues(t, packetExtendMaxHint, pkt.extendInfo.hint())
+
+	pkt.ttl = 1
+	pkt.extendInfo = newPacketExtendInfo(0, 0)
+	increaseHops(pkt)
+	assert.EqualValues(t, 0, pkt.extendInfo.hint(), "one-hop packet must not be counted")
+}
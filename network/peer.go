@@ -64,6 +64,7 @@ type Peer struct {
 	//
 	secureKey *secureKey
 	rtt       PeerRTT
+	keepalive peerKeepalive
 
 	//log
 	logger log.Logger
@@ -659,7 +660,21 @@ func (r *PeerRTT) Stop() time.Time {
 	}
 
 	r.et = time.Now()
-	r.last = r.et.Sub(r.st)
+	r.update(r.et.Sub(r.st))
+	return r.et
+}
+
+// Update applies the round trip time measured by others, such as keepalive
+// probes.
+func (r *PeerRTT) Update(d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.update(d)
+}
+
+func (r *PeerRTT) update(d time.Duration) {
+	r.last = d
 
 	//exponential weighted moving average model
 	//avg = (1-0.125)*avg + 0.125*last
@@ -669,7 +684,6 @@ func (r *PeerRTT) Stop() time.Time {
 	} else {
 		r.avg = r.last
 	}
-	return r.et
 }
 
 func (r *PeerRTT) Last(d time.Duration) float64 {
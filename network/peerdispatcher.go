@@ -18,7 +18,12 @@ type PeerDispatcher struct {
 	p2pMap          map[string]*PeerToPeer
 	p2pMapMtx       sync.RWMutex
 
-	mtr *metric.NetworkMetric
+	mtr       *metric.NetworkMetric
+	audit     *auditLog
+	puzzle    *queryPuzzle
+	policies  *packetPolicies
+	diversity *peerDiversity
+	mux       *muxManager
 }
 
 func newPeerDispatcher(id module.PeerID, l log.Logger, peerHandlers ...PeerHandler) *PeerDispatcher {
@@ -27,9 +32,13 @@ func newPeerDispatcher(id module.PeerID, l log.Logger, peerHandlers ...PeerHandl
 		p2pMap:       make(map[string]*PeerToPeer),
 		peerHandler:  newPeerHandler(l),
 		mtr:          metric.NewNetworkMetric(metric.DefaultMetricContext()),
+		puzzle:       newQueryPuzzle(),
+		policies:     newPacketPolicies(),
+		diversity:    newPeerDiversity(),
 	}
 
 	pd.setSelfPeerID(id)
+	pd.mux = newMuxManager(pd.onAcceptStream, l)
 
 	//listener or dialer => pd.dispatchPeer => front.onPeer => back.onPeer => pd.onPeer => p2p.onPeer
 	for _, ph := range peerHandlers {
@@ -45,6 +54,10 @@ func (pd *PeerDispatcher) registerPeerToPeer(p2p *PeerToPeer) bool {
 	if _, ok := pd.p2pMap[p2p.channel]; ok {
 		return false
 	}
+	p2p.audit = pd.audit
+	p2p.puzzle = pd.puzzle
+	p2p.policies = pd.policies
+	p2p.diversity = pd.diversity
 	pd.p2pMap[p2p.channel] = p2p
 	return true
 }
@@ -105,6 +118,14 @@ func (pd *PeerDispatcher) onConnect(conn net.Conn, addr string, d *Dialer) {
 	pd.dispatchPeer(p)
 }
 
+//callback from muxSession.readRoutine
+func (pd *PeerDispatcher) onAcceptStream(conn net.Conn, channel string) {
+	pd.logger.Traceln("onAcceptStream", conn.LocalAddr(), "<-", conn.RemoteAddr(), channel)
+	p := newPeer(conn, nil, true, "", pd.logger)
+	p.setChannel(channel)
+	pd.dispatchPeer(p)
+}
+
 func (pd *PeerDispatcher) dispatchPeer(p *Peer) {
 	pd.peerHandlersMtx.RLock()
 	defer pd.peerHandlersMtx.RUnlock()
@@ -121,6 +142,9 @@ func (pd *PeerDispatcher) dispatchPeer(p *Peer) {
 //callback from PeerHandler.nextOnPeer
 func (pd *PeerDispatcher) onPeer(p *Peer) {
 	pd.logger.Traceln("onPeer", p)
+	if st, ok := p.conn.(*muxStream); ok {
+		pd.mux.register(p.NetAddress(), st.s)
+	}
 	if p2p := pd.getPeerToPeer(p.Channel()); p2p != nil {
 		p.setMetric(p2p.mtr)
 		p.setPacketCbFunc(p2p.onPacket)
@@ -129,6 +153,7 @@ func (pd *PeerDispatcher) onPeer(p *Peer) {
 		p2p.onPeer(p)
 	} else {
 		err := fmt.Errorf("not exists PeerToPeer[%s]", p.Channel())
+		pd.audit.record(AuditStageChannel, AuditResultRejected, p, err.Error())
 		p.CloseByError(err)
 	}
 }
@@ -0,0 +1,140 @@
+package network
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/bits"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultQueryRate         = rate.Limit(1)
+	DefaultQueryBurst        = 5
+	DefaultQueryTotalRate    = rate.Limit(100)
+	DefaultQueryTotalBurst   = 200
+	DefaultQueryPuzzlePeriod = 10 * time.Minute
+	MaxQueryPuzzleDifficulty = 24
+	AttrP2PQueryLimiter      = "P2PQueryLimiter"
+	AttrP2PQueryPuzzleNonce  = "P2PQueryPuzzleNonce"
+)
+
+const (
+	queryDropReasonRate   = "rate"
+	queryDropReasonPuzzle = "puzzle"
+	queryPuzzleSecretSize = 32
+	queryPuzzleNonceSize  = 8
+)
+
+// QueryPuzzleSetter is implemented by the transport supporting client puzzles
+// for discovery queries.
+type QueryPuzzleSetter interface {
+	// SetQueryPuzzle makes seeds require the solution of the puzzle with the
+	// difficulty (number of leading zero bits) for discovery queries.
+	// It's disabled with zero.
+	SetQueryPuzzle(difficulty int) error
+}
+
+// queryPuzzle issues challenges derived from the secret and the period, so
+// it doesn't need to keep issued challenges.
+type queryPuzzle struct {
+	secret     []byte
+	difficulty int32
+}
+
+func newQueryPuzzle() *queryPuzzle {
+	secret := make([]byte, queryPuzzleSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		log.Panicf("fail to make secret for query puzzle err=%+v", err)
+	}
+	return &queryPuzzle{secret: secret}
+}
+
+func (qp *queryPuzzle) setDifficulty(d int) error {
+	if d < 0 || d > MaxQueryPuzzleDifficulty {
+		return errors.IllegalArgumentError.Errorf("InvalidDifficulty(%d)", d)
+	}
+	atomic.StoreInt32(&qp.difficulty, int32(d))
+	return nil
+}
+
+func (qp *queryPuzzle) Difficulty() int {
+	if qp == nil {
+		return 0
+	}
+	return int(atomic.LoadInt32(&qp.difficulty))
+}
+
+func (qp *queryPuzzle) challengeOf(id module.PeerID, period int64) []byte {
+	pb := make([]byte, 8)
+	binary.BigEndian.PutUint64(pb, uint64(period))
+	bs := make([]byte, 0, len(qp.secret)+len(id.Bytes())+len(pb))
+	bs = append(bs, qp.secret...)
+	bs = append(bs, id.Bytes()...)
+	bs = append(bs, pb...)
+	return crypto.SHA3Sum256(bs)
+}
+
+func (qp *queryPuzzle) period() int64 {
+	return time.Now().UnixNano() / int64(DefaultQueryPuzzlePeriod)
+}
+
+func (qp *queryPuzzle) challenge(id module.PeerID) []byte {
+	return qp.challengeOf(id, qp.period())
+}
+
+// verify returns true if the nonce solves the challenge of the current or
+// the previous period.
+func (qp *queryPuzzle) verify(id module.PeerID, nonce []byte) bool {
+	d := qp.Difficulty()
+	if d == 0 {
+		return true
+	}
+	if len(nonce) == 0 {
+		return false
+	}
+	period := qp.period()
+	return checkQueryPuzzle(qp.challengeOf(id, period), nonce, d) ||
+		checkQueryPuzzle(qp.challengeOf(id, period-1), nonce, d)
+}
+
+func checkQueryPuzzle(challenge, nonce []byte, difficulty int) bool {
+	bs := make([]byte, 0, len(challenge)+len(nonce))
+	bs = append(bs, challenge...)
+	bs = append(bs, nonce...)
+	h := crypto.SHA3Sum256(bs)
+	zeros := 0
+	for _, b := range h {
+		zeros += bits.LeadingZeros8(b)
+		if b != 0 || zeros >= difficulty {
+			break
+		}
+	}
+	return zeros >= difficulty
+}
+
+func solveQueryPuzzle(challenge []byte, difficulty int) []byte {
+	nonce := make([]byte, queryPuzzleNonceSize)
+	for i := uint64(0); ; i++ {
+		binary.BigEndian.PutUint64(nonce, i)
+		if checkQueryPuzzle(challenge, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
+
+func queryLimiterOf(p *Peer) *rate.Limiter {
+	if v, ok := p.GetAttr(AttrP2PQueryLimiter); ok {
+		return v.(*rate.Limiter)
+	}
+	l := rate.NewLimiter(DefaultQueryRate, DefaultQueryBurst)
+	p.PutAttr(AttrP2PQueryLimiter, l)
+	return l
+}
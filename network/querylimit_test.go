@@ -0,0 +1,43 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPuzzle(t *testing.T) {
+	var nilPuzzle *queryPuzzle
+	assert.Equal(t, 0, nilPuzzle.Difficulty())
+
+	qp := newQueryPuzzle()
+	id := generatePeerID()
+	assert.True(t, qp.verify(id, nil), "disabled puzzle must allow all")
+
+	assert.Error(t, qp.setDifficulty(-1))
+	assert.Error(t, qp.setDifficulty(MaxQueryPuzzleDifficulty+1))
+	assert.NoError(t, qp.setDifficulty(8))
+	assert.Equal(t, 8, qp.Difficulty())
+	assert.False(t, qp.verify(id, nil))
+
+	challenge := qp.challenge(id)
+	assert.Equal(t, challenge, qp.challenge(id))
+	assert.NotEqual(t, challenge, qp.challenge(generatePeerID()))
+
+	nonce := solveQueryPuzzle(challenge, qp.Difficulty())
+	assert.True(t, checkQueryPuzzle(challenge, nonce, qp.Difficulty()))
+	assert.True(t, qp.verify(id, nonce))
+	assert.True(t, qp.verify(id, solveQueryPuzzle(qp.challengeOf(id, qp.period()-1), 8)))
+	assert.NotEqual(t, challenge, qp.challengeOf(id, qp.period()-1))
+}
+
+func TestQueryLimiterOf(t *testing.T) {
+	p := generatePeer()
+	p.attr = make(map[string]interface{})
+	l := queryLimiterOf(p)
+	assert.Equal(t, l, queryLimiterOf(p))
+	for i := 0; i < DefaultQueryBurst; i++ {
+		assert.True(t, l.Allow())
+	}
+	assert.False(t, l.Allow())
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"sync"
@@ -17,6 +18,7 @@ type transport struct {
 	cn      *ChannelNegotiator
 	pd      *PeerDispatcher
 	dMap    map[string]*Dialer
+	audit   *auditLog
 	logger  log.Logger
 }
 
@@ -29,6 +31,9 @@ func NewTransport(address string, w module.Wallet, l log.Logger) module.NetworkT
 	a := newAuthenticator(w, transportLogger)
 	cn := newChannelNegotiator(na, transportLogger)
 	pd := newPeerDispatcher(NewPeerIDFromAddress(w.Address()), transportLogger, a, cn)
+	audit := new(auditLog)
+	a.audit, cn.audit, pd.audit = audit, audit, audit
+	a.mux = pd.mux
 	listener := newListener(address, pd.onAccept, transportLogger)
 	t := &transport{
 		l:       listener,
@@ -37,6 +42,7 @@ func NewTransport(address string, w module.Wallet, l log.Logger) module.NetworkT
 		cn:      cn,
 		pd:      pd,
 		dMap:    make(map[string]*Dialer),
+		audit:   audit,
 		logger:  transportLogger,
 	}
 	return t
@@ -74,6 +80,7 @@ func (t *transport) GetDialer(channel string) *Dialer {
 	d, ok := t.dMap[channel]
 	if !ok {
 		d = newDialer(channel, t.pd.onConnect)
+		d.mux = t.pd.mux
 		t.dMap[channel] = d
 	}
 	return d
@@ -222,6 +229,7 @@ type Dialer struct {
 	onConnect connectCbFunc
 	channel   string
 	dialing   *Set
+	mux       *muxManager
 }
 
 type connectCbFunc func(conn net.Conn, addr string, d *Dialer)
@@ -238,6 +246,11 @@ func (d *Dialer) Dial(addr string) error {
 	if !d.dialing.Add(addr) {
 		return ErrAlreadyDialing
 	}
+	if conn, ok := d.mux.open(NetAddress(addr), d.channel); ok {
+		_ = d.dialing.Remove(addr)
+		d.onConnect(conn, addr, d)
+		return nil
+	}
 	conn, err := net.DialTimeout(DefaultTransportNet, addr, DefaultDialTimeout)
 	_ = d.dialing.Remove(addr)
 	if err != nil {
@@ -246,3 +259,27 @@ func (d *Dialer) Dial(addr string) error {
 	d.onConnect(conn, addr, d)
 	return nil
 }
+
+func (t *transport) SetAuditWriter(w io.Writer) {
+	t.audit.setWriter(w)
+}
+
+func (t *transport) HandshakeRecords() []*HandshakeRecord {
+	return t.audit.Records()
+}
+
+func (t *transport) SetQueryPuzzle(difficulty int) error {
+	return t.pd.puzzle.setDifficulty(difficulty)
+}
+
+func (t *transport) SetMultiplexing(enable bool) {
+	t.pd.mux.setEnabled(enable)
+}
+
+func (t *transport) SetPacketPolicy(pi module.ProtocolInfo, pp PacketPolicy) error {
+	return t.pd.policies.set(pi, pp)
+}
+
+func (t *transport) SetPeerDiversity(subnetLimit, asnLimit int, asnFile string) error {
+	return t.pd.diversity.set(subnetLimit, asnLimit, asnFile)
+}
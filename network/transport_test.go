@@ -163,3 +163,69 @@ func Test_transport(t *testing.T) {
 	assert.NoError(t, nt2.Close(), "Transport2.Close fail")
 	time.Sleep(1 * time.Second)
 }
+
+type testMuxPeerHandler struct {
+	*peerHandler
+	peers chan *Peer
+}
+
+func (ph *testMuxPeerHandler) onPeer(p *Peer) {
+	p.setPacketCbFunc(ph.onPacket)
+	ph.peers <- p
+}
+
+func waitTestPeer(t *testing.T, ch chan *Peer) *Peer {
+	select {
+	case p := <-ch:
+		return p
+	case <-time.After(5 * time.Second):
+		assert.FailNow(t, "timeout to wait peer")
+		return nil
+	}
+}
+
+func Test_transport_multiplexing(t *testing.T) {
+	const otherChannel = "otherchannel"
+	nts := make([]*transport, 2)
+	phs := make([]*testMuxPeerHandler, 2)
+	for i := range nts {
+		w := walletFromGeneratedPrivateKey()
+		nts[i] = NewTransport(getAvailableLocalhostAddress(t), w, log.New()).(*transport)
+		nts[i].SetMultiplexing(true)
+		phs[i] = &testMuxPeerHandler{newPeerHandler(nts[i].logger), make(chan *Peer, 2)}
+		nts[i].pd.registerPeerHandler(phs[i], true)
+		nts[i].cn.addProtocol(testChannel, module.ProtoP2P)
+		nts[i].cn.addProtocol(otherChannel, module.ProtoP2P)
+		assert.NoError(t, nts[i].Listen())
+	}
+
+	assert.NoError(t, nts[1].Dial(nts[0].GetListenAddress(), testChannel))
+	in1, out1 := waitTestPeer(t, phs[0].peers), waitTestPeer(t, phs[1].peers)
+	assert.Equal(t, testChannel, in1.Channel())
+	assert.Equal(t, testChannel, out1.Channel())
+
+	// other channel uses the same connection
+	assert.NoError(t, nts[1].Dial(nts[0].GetListenAddress(), otherChannel))
+	in2, out2 := waitTestPeer(t, phs[0].peers), waitTestPeer(t, phs[1].peers)
+	assert.Equal(t, otherChannel, in2.Channel())
+	assert.Equal(t, otherChannel, out2.Channel())
+	assert.True(t, in2.In())
+	assert.False(t, out2.In())
+	assert.True(t, in2.ID().Equal(in1.ID()))
+	assert.True(t, out2.ID().Equal(out1.ID()))
+	assert.Same(t, in1.conn.(*muxStream).s, in2.conn.(*muxStream).s)
+	assert.Same(t, out1.conn.(*muxStream).s, out2.conn.(*muxStream).s)
+
+	// closing a channel doesn't affect others
+	assert.NoError(t, out1.Close("test"))
+	in1.WaitClose()
+	assert.False(t, in2.IsClosed())
+	assert.False(t, out2.IsClosed())
+
+	for _, p := range []*Peer{in2, out2} {
+		p.Close("test")
+	}
+	for _, nt := range nts {
+		assert.NoError(t, nt.Close())
+	}
+}
@@ -0,0 +1,441 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+// Kinds of rules
+const (
+	// KindNoBlock fires if no block is finalized for Threshold seconds.
+	KindNoBlock = "no_block"
+	// KindPeersBelow fires if the number of peers is below Threshold.
+	KindPeersBelow = "peers_below"
+	// KindPoolAbove fires if the usage of the transaction pool is above
+	// Threshold percent.
+	KindPoolAbove = "pool_above"
+	// KindDiskBelow fires if the free space of the disk of the node is
+	// below Threshold GB.
+	KindDiskBelow = "disk_below"
+)
+
+// Severities of alerts, which are compatible with PagerDuty.
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Status of alerts
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+const (
+	DefaultInterval     = 10
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	sendTimeout         = 5 * time.Second
+)
+
+type Rule struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	Threshold float64 `json:"threshold"`
+	// Channel is the channel of the chain to be checked. All chains are
+	// checked if it's empty. It's ignored for KindDiskBelow.
+	Channel  string `json:"channel,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+func (r *Rule) severity() string {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+type PagerDuty struct {
+	RoutingKey string `json:"routing_key"`
+	URL        string `json:"url,omitempty"`
+}
+
+func (p *PagerDuty) url() string {
+	if p.URL == "" {
+		return DefaultPagerDutyURL
+	}
+	return p.URL
+}
+
+// Config is the configuration of alerts. Alerts are sent to the webhooks
+// and PagerDuty on changes of the status.
+type Config struct {
+	Interval  int        `json:"interval,omitempty"` // in second
+	Rules     []*Rule    `json:"rules,omitempty"`
+	Webhooks  []string   `json:"webhooks,omitempty"`
+	PagerDuty *PagerDuty `json:"pagerduty,omitempty"`
+}
+
+func (c *Config) IsEmpty() bool {
+	return c == nil || len(c.Rules) == 0
+}
+
+func (c *Config) Validate() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	if c.Interval < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidInterval(%d)", c.Interval)
+	}
+	names := make(map[string]bool)
+	for _, r := range c.Rules {
+		if r == nil || r.Name == "" || names[r.Name] {
+			return errors.IllegalArgumentError.Errorf("InvalidRuleName(rule=%+v)", r)
+		}
+		names[r.Name] = true
+		switch r.Kind {
+		case KindNoBlock, KindPeersBelow, KindPoolAbove, KindDiskBelow:
+		default:
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidRuleKind(rule=%s,kind=%s)", r.Name, r.Kind)
+		}
+		if r.Threshold < 0 {
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidThreshold(rule=%s,threshold=%v)", r.Name, r.Threshold)
+		}
+		switch r.Severity {
+		case "", SeverityCritical, SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidSeverity(rule=%s,severity=%s)", r.Name, r.Severity)
+		}
+	}
+	urls := append([]string{}, c.Webhooks...)
+	if c.PagerDuty != nil {
+		if c.PagerDuty.RoutingKey == "" {
+			return errors.IllegalArgumentError.New("NoPagerDutyRoutingKey")
+		}
+		urls = append(urls, c.PagerDuty.url())
+	}
+	for _, u := range urls {
+		if pu, err := url.Parse(u); err != nil || (pu.Scheme != "http" && pu.Scheme != "https") {
+			return errors.IllegalArgumentError.Errorf("InvalidURL(url=%s)", u)
+		}
+	}
+	return nil
+}
+
+func (c *Config) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultInterval * time.Second
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// ChainStatus is the status of a running chain to be evaluated.
+type ChainStatus struct {
+	Channel   string
+	LastBlock time.Time
+	Peers     int
+	PoolUsage float64 // in percent
+}
+
+// Source provides the status of the node.
+type Source interface {
+	// ChainStatus returns the status of running chains.
+	ChainStatus() []*ChainStatus
+	// DiskFree returns the free space of the disk of the node in bytes.
+	DiskFree() (uint64, error)
+}
+
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Kind      string    `json:"kind"`
+	Channel   string    `json:"channel,omitempty"`
+	Status    string    `json:"status"`
+	Severity  string    `json:"severity"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Summary   string    `json:"summary"`
+	Since     time.Time `json:"since"`
+	Time      time.Time `json:"time"`
+}
+
+func (a *Alert) key() string {
+	if a.Channel == "" {
+		return a.Rule
+	}
+	return a.Rule + "/" + a.Channel
+}
+
+// Engine evaluates the rules periodically, and sends alerts on changes.
+type Engine struct {
+	cfg    Config
+	src    Source
+	host   string
+	client *http.Client
+	logger log.Logger
+
+	lock   sync.Mutex
+	active map[string]*Alert
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func NewEngine(cfg *Config, src Source, l log.Logger) (*Engine, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	return &Engine{
+		cfg:    *cfg,
+		src:    src,
+		host:   host,
+		client: &http.Client{Timeout: sendTimeout},
+		logger: l,
+		active: make(map[string]*Alert),
+	}, nil
+}
+
+func (e *Engine) Start() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.stop != nil {
+		return
+	}
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	go e.run(e.stop, e.done)
+}
+
+func (e *Engine) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(e.cfg.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		e.Evaluate(time.Now())
+	}
+}
+
+func (e *Engine) Stop() {
+	e.lock.Lock()
+	stop, done := e.stop, e.done
+	e.stop, e.done = nil, nil
+	e.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Alerts returns the firing alerts.
+func (e *Engine) Alerts() []*Alert {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	alerts := make([]*Alert, 0, len(e.active))
+	for _, a := range e.active {
+		ac := *a
+		alerts = append(alerts, &ac)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].key() < alerts[j].key()
+	})
+	return alerts
+}
+
+// Evaluate evaluates the rules, and sends the alerts started firing or
+// resolved since the last evaluation.
+func (e *Engine) Evaluate(now time.Time) {
+	var chains []*ChainStatus
+	var diskFree *float64
+	for _, r := range e.cfg.Rules {
+		if r.Kind == KindDiskBelow {
+			if diskFree == nil {
+				free, err := e.src.DiskFree()
+				if err != nil {
+					e.logger.Warnf("fail to get free disk space err=%+v", err)
+					continue
+				}
+				gb := float64(free) / (1 << 30)
+				diskFree = &gb
+			}
+		} else if chains == nil {
+			chains = e.src.ChainStatus()
+		}
+	}
+
+	var changes []*Alert
+	seen := make(map[string]bool)
+	update := func(r *Rule, channel string, value float64, firing bool, summary string) {
+		a := &Alert{
+			Rule:      r.Name,
+			Kind:      r.Kind,
+			Channel:   channel,
+			Status:    StatusFiring,
+			Severity:  r.severity(),
+			Value:     value,
+			Threshold: r.Threshold,
+			Summary:   summary,
+			Since:     now,
+			Time:      now,
+		}
+		key := a.key()
+		seen[key] = true
+		if old, ok := e.active[key]; ok {
+			if firing {
+				old.Value, old.Summary, old.Time = value, summary, now
+			} else {
+				delete(e.active, key)
+				a.Status = StatusResolved
+				a.Since = old.Since
+				changes = append(changes, a)
+			}
+		} else if firing {
+			e.active[key] = a
+			ac := *a
+			changes = append(changes, &ac)
+		}
+	}
+
+	e.lock.Lock()
+	for _, r := range e.cfg.Rules {
+		switch r.Kind {
+		case KindDiskBelow:
+			if diskFree != nil {
+				update(r, "", *diskFree, *diskFree < r.Threshold,
+					fmt.Sprintf("free disk space %.1fGB (threshold %vGB)", *diskFree, r.Threshold))
+			}
+		default:
+			for _, cs := range chains {
+				if r.Channel != "" && r.Channel != cs.Channel {
+					continue
+				}
+				switch r.Kind {
+				case KindNoBlock:
+					if cs.LastBlock.IsZero() {
+						continue
+					}
+					elapsed := now.Sub(cs.LastBlock).Seconds()
+					update(r, cs.Channel, elapsed, elapsed > r.Threshold,
+						fmt.Sprintf("no block for %.0fs on %s (threshold %vs)", elapsed, cs.Channel, r.Threshold))
+				case KindPeersBelow:
+					peers := float64(cs.Peers)
+					update(r, cs.Channel, peers, peers < r.Threshold,
+						fmt.Sprintf("%d peers on %s (threshold %v)", cs.Peers, cs.Channel, r.Threshold))
+				case KindPoolAbove:
+					update(r, cs.Channel, cs.PoolUsage, cs.PoolUsage > r.Threshold,
+						fmt.Sprintf("transaction pool of %s is %.1f%% full (threshold %v%%)", cs.Channel, cs.PoolUsage, r.Threshold))
+				}
+			}
+		}
+	}
+	// resolve alerts of the chains not running any more.
+	for key, a := range e.active {
+		if !seen[key] && a.Channel != "" {
+			delete(e.active, key)
+			ac := *a
+			ac.Status = StatusResolved
+			ac.Time = now
+			ac.Summary = fmt.Sprintf("%s is not running", a.Channel)
+			changes = append(changes, &ac)
+		}
+	}
+	e.lock.Unlock()
+
+	for _, a := range changes {
+		e.send(a)
+	}
+}
+
+func (e *Engine) send(a *Alert) {
+	e.logger.Warnf("ALERT %s rule=%s channel=%s summary=%s", a.Status, a.Rule, a.Channel, a.Summary)
+	for _, u := range e.cfg.Webhooks {
+		if err := e.post(u, a); err != nil {
+			e.logger.Warnf("fail to send alert url=%s err=%+v", u, err)
+		}
+	}
+	if pd := e.cfg.PagerDuty; pd != nil {
+		if err := e.post(pd.url(), e.pagerDutyEvent(a)); err != nil {
+			e.logger.Warnf("fail to send alert to PagerDuty err=%+v", err)
+		}
+	}
+}
+
+type pagerDutyPayload struct {
+	Summary       string      `json:"summary"`
+	Source        string      `json:"source"`
+	Severity      string      `json:"severity"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Component     string      `json:"component,omitempty"`
+	Class         string      `json:"class"`
+	CustomDetails interface{} `json:"custom_details"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// pagerDutyEvent returns the event of PagerDuty Events API v2 for the alert.
+func (e *Engine) pagerDutyEvent(a *Alert) *pagerDutyEvent {
+	ev := &pagerDutyEvent{
+		RoutingKey: e.cfg.PagerDuty.RoutingKey,
+		DedupKey:   "goloop/" + e.host + "/" + a.key(),
+	}
+	if a.Status == StatusResolved {
+		ev.EventAction = "resolve"
+		return ev
+	}
+	ev.EventAction = "trigger"
+	ev.Payload = &pagerDutyPayload{
+		Summary:       a.Summary,
+		Source:        e.host,
+		Severity:      a.Severity,
+		Timestamp:     a.Time,
+		Component:     a.Channel,
+		Class:         a.Kind,
+		CustomDetails: a,
+	}
+	return ev
+}
+
+func (e *Engine) post(u string, v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(u, "application/json", bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
+		return errors.Errorf("AlertFailure(status=%s,msg=%s)", resp.Status, msg)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+type testSource struct {
+	chains []*ChainStatus
+	free   uint64
+}
+
+func (s *testSource) ChainStatus() []*ChainStatus {
+	return s.chains
+}
+
+func (s *testSource) DiskFree() (uint64, error) {
+	return s.free, nil
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		ok   bool
+	}{
+		{"Nil", nil, true},
+		{"Valid", &Config{
+			Rules:     []*Rule{{Name: "stall", Kind: KindNoBlock, Threshold: 60}},
+			Webhooks:  []string{"http://localhost/hook"},
+			PagerDuty: &PagerDuty{RoutingKey: "key"},
+		}, true},
+		{"NoName", &Config{
+			Rules: []*Rule{{Kind: KindNoBlock, Threshold: 60}},
+		}, false},
+		{"DupName", &Config{
+			Rules: []*Rule{
+				{Name: "a", Kind: KindNoBlock, Threshold: 60},
+				{Name: "a", Kind: KindPeersBelow, Threshold: 3},
+			},
+		}, false},
+		{"BadKind", &Config{
+			Rules: []*Rule{{Name: "a", Kind: "unknown"}},
+		}, false},
+		{"BadSeverity", &Config{
+			Rules: []*Rule{{Name: "a", Kind: KindDiskBelow, Severity: "fatal"}},
+		}, false},
+		{"BadWebhook", &Config{
+			Rules:    []*Rule{{Name: "a", Kind: KindDiskBelow, Threshold: 10}},
+			Webhooks: []string{"localhost/hook"},
+		}, false},
+		{"NoRoutingKey", &Config{
+			Rules:     []*Rule{{Name: "a", Kind: KindDiskBelow, Threshold: 10}},
+			PagerDuty: &PagerDuty{},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	var lock sync.Mutex
+	var alerts []*Alert
+	var events []*pagerDutyEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := new(Alert)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(a))
+		lock.Lock()
+		alerts = append(alerts, a)
+		lock.Unlock()
+	}))
+	defer hook.Close()
+	pd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev := new(pagerDutyEvent)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(ev))
+		lock.Lock()
+		events = append(events, ev)
+		lock.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer pd.Close()
+
+	now := time.Now()
+	src := &testSource{
+		chains: []*ChainStatus{
+			{Channel: "icon", LastBlock: now.Add(-10 * time.Second), Peers: 5, PoolUsage: 10},
+		},
+		free: 100 << 30,
+	}
+	e, err := NewEngine(&Config{
+		Rules: []*Rule{
+			{Name: "stall", Kind: KindNoBlock, Threshold: 60, Severity: SeverityCritical},
+			{Name: "peers", Kind: KindPeersBelow, Threshold: 3},
+			{Name: "pool", Kind: KindPoolAbove, Threshold: 80, Channel: "other"},
+			{Name: "disk", Kind: KindDiskBelow, Threshold: 10},
+		},
+		Webhooks:  []string{hook.URL},
+		PagerDuty: &PagerDuty{RoutingKey: "key", URL: pd.URL},
+	}, src, log.New())
+	assert.NoError(t, err)
+
+	e.Evaluate(now)
+	assert.Empty(t, e.Alerts())
+	assert.Empty(t, alerts)
+
+	// stalled, lost peers, and the pool of the other channel is full
+	src.chains[0].Peers = 1
+	src.chains[0].PoolUsage = 90
+	e.Evaluate(now.Add(time.Minute))
+	as := e.Alerts()
+	if assert.Len(t, as, 2) {
+		assert.Equal(t, "peers", as[0].Rule)
+		assert.Equal(t, "stall", as[1].Rule)
+		assert.Equal(t, "icon", as[1].Channel)
+		assert.Equal(t, SeverityCritical, as[1].Severity)
+	}
+	assert.Len(t, alerts, 2)
+	if assert.Len(t, events, 2) {
+		for _, ev := range events {
+			assert.Equal(t, "trigger", ev.EventAction)
+			assert.Equal(t, "key", ev.RoutingKey)
+			assert.NotNil(t, ev.Payload)
+		}
+	}
+
+	// still firing, nothing is sent
+	e.Evaluate(now.Add(2 * time.Minute))
+	assert.Len(t, e.Alerts(), 2)
+	assert.Len(t, alerts, 2)
+
+	// peers are back, and disk is full
+	src.chains[0].Peers = 5
+	src.free = 1 << 30
+	e.Evaluate(now.Add(3 * time.Minute))
+	as = e.Alerts()
+	if assert.Len(t, as, 2) {
+		assert.Equal(t, "disk", as[0].Rule)
+		assert.Equal(t, "stall", as[1].Rule)
+	}
+	if assert.Len(t, alerts, 4) {
+		assert.Equal(t, StatusResolved, alerts[2].Status)
+		assert.Equal(t, "peers", alerts[2].Rule)
+		assert.Equal(t, StatusFiring, alerts[3].Status)
+		assert.Equal(t, "disk", alerts[3].Rule)
+	}
+	if assert.Len(t, events, 4) {
+		assert.Equal(t, "resolve", events[2].EventAction)
+		assert.Equal(t, events[1].DedupKey, events[2].DedupKey)
+	}
+
+	// the chain is stopped
+	src.chains = nil
+	e.Evaluate(now.Add(4 * time.Minute))
+	as = e.Alerts()
+	if assert.Len(t, as, 1) {
+		assert.Equal(t, "disk", as[0].Rule)
+	}
+	if assert.Len(t, alerts, 5) {
+		assert.Equal(t, StatusResolved, alerts[4].Status)
+		assert.Equal(t, "stall", alerts[4].Rule)
+	}
+}
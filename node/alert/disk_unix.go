@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package alert
+
+import "syscall"
+
+// DiskFree returns the free space in bytes of the file system including
+// the path, which is available to the user.
+func DiskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package alert
+
+import "github.com/icon-project/goloop/common/errors"
+
+func DiskFree(path string) (uint64, error) {
+	return 0, errors.UnsupportedError.New("DiskFreeNotSupported")
+}
@@ -0,0 +1,51 @@
+package node
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/node/alert"
+	"github.com/icon-project/goloop/service"
+)
+
+// alertSource provides the status of the node for the alert engine.
+type alertSource struct {
+	n *Node
+}
+
+func (s *alertSource) ChainStatus() []*alert.ChainStatus {
+	var status []*alert.ChainStatus
+	for _, c := range s.n.GetChains() {
+		if state, _, _ := c.State(); state != "started" {
+			continue
+		}
+		bm, nm, sm := c.BlockManager(), c.NetworkManager(), c.ServiceManager()
+		if bm == nil || nm == nil || sm == nil {
+			continue
+		}
+		cs := &alert.ChainStatus{
+			Channel: c.Channel(),
+			Peers:   len(nm.GetPeers()),
+		}
+		if blk, err := bm.GetLastBlock(); err == nil {
+			cs.LastBlock = time.UnixMicro(blk.Timestamp())
+		}
+		if ps, err := service.GetPoolStatus(sm, module.TransactionGroupNormal); err == nil && ps.Size > 0 {
+			cs.PoolUsage = float64(ps.Used) * 100 / float64(ps.Size)
+		}
+		status = append(status, cs)
+	}
+	return status
+}
+
+func (s *alertSource) DiskFree() (uint64, error) {
+	return alert.DiskFree(s.n.cfg.AbsBaseDir())
+}
+
+// GetAlerts returns the firing alerts.
+func (n *Node) GetAlerts() []*alert.Alert {
+	if n.alert == nil {
+		return []*alert.Alert{}
+	}
+	return n.alert.Alerts()
+}
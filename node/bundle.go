@@ -0,0 +1,221 @@
+package node
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	// BundleRecentErrors is the number of recent warnings and errors kept
+	// for the status bundle.
+	BundleRecentErrors = 200
+	// BundleLogTailSize is the max size of the tail of the log file in the
+	// status bundle.
+	BundleLogTailSize = 1 << 20
+
+	redactedValue = "REDACTED"
+)
+
+// BundleManifest describes the status bundle. Errors are the failures of
+// gathering some parts, which don't stop gathering the others.
+type BundleManifest struct {
+	Time         time.Time `json:"time"`
+	BuildVersion string    `json:"buildVersion"`
+	BuildTags    string    `json:"buildTags"`
+	Files        []string  `json:"files"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+type bundleWriter struct {
+	zw       *zip.Writer
+	manifest BundleManifest
+}
+
+func (w *bundleWriter) write(name string, f func(w io.Writer) error) {
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: w.manifest.Time,
+	})
+	if err == nil {
+		err = f(fw)
+	}
+	if err != nil {
+		w.manifest.Errors = append(w.manifest.Errors, name+": "+err.Error())
+		return
+	}
+	w.manifest.Files = append(w.manifest.Files, name)
+}
+
+func (w *bundleWriter) writeJSON(name string, v interface{}) {
+	w.write(name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	})
+}
+
+// WriteBundle writes the status of the node in a zip archive for support
+// requests. It includes the configuration without secrets, metrics, the
+// status of chains and peers, recent errors, and the tail of the log file.
+func (n *Node) WriteBundle(out io.Writer) error {
+	w := &bundleWriter{
+		zw: zip.NewWriter(out),
+		manifest: BundleManifest{
+			Time:         time.Now(),
+			BuildVersion: n.cfg.BuildVersion,
+			BuildTags:    n.cfg.BuildTags,
+		},
+	}
+
+	w.writeJSON("system.json", NewSystemView(n))
+	w.write("config.json", func(w io.Writer) error {
+		cfg, err := redactedConfig(&n.cfg)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(cfg, '\n'))
+		return err
+	})
+	w.write("metrics.txt", writeMetrics)
+	w.writeJSON("engines.json", n.pm.Status())
+	w.writeJSON("alerts.json", n.GetAlerts())
+	if n.recorder != nil {
+		w.writeJSON("errors.json", n.recorder.Records())
+	}
+	if n.cfg.LogFile != "" {
+		w.write("log/"+path.Base(n.cfg.LogFile), func(w io.Writer) error {
+			return writeTail(w, n.cfg.LogFile, BundleLogTailSize)
+		})
+	}
+
+	chains := n.GetChains()
+	views := make([]*ChainView, 0, len(chains))
+	for _, c := range chains {
+		views = append(views, NewChainView(c))
+	}
+	w.writeJSON("chains.json", views)
+	for _, c := range chains {
+		dir := "chains/" + c.Channel() + "/"
+		w.writeJSON(dir+"inspect.json", inspectChain(c, false))
+		if cs := c.Consensus(); cs != nil {
+			w.writeJSON(dir+"consensus.json", cs.GetStatus())
+		}
+	}
+
+	w.writeJSON("manifest.json", &w.manifest)
+	return w.zw.Close()
+}
+
+func writeMetrics(w io.Writer) error {
+	metric.BeforeExport()
+	mfs, err := metric.Gatherer().Gather()
+	if err != nil {
+		return err
+	}
+	enc := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTail writes the last lines of the file in the size.
+func writeTail(w io.Writer, name string, size int64) error {
+	fd, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	st, err := fd.Stat()
+	if err != nil {
+		return err
+	}
+	offset := st.Size() - size
+	if offset <= 0 {
+		_, err = io.Copy(w, fd)
+		return err
+	}
+	bs := make([]byte, size)
+	if _, err := fd.ReadAt(bs, offset); err != nil && err != io.EOF {
+		return err
+	}
+	// skip the partial line
+	if idx := bytes.IndexByte(bs, '\n'); idx >= 0 {
+		bs = bs[idx+1:]
+	}
+	_, err = w.Write(bs)
+	return err
+}
+
+// secretKeys are the keys of the configuration whose values are secrets.
+var secretKeys = map[string]bool{
+	"key_password": true,
+	"key_secret":   true,
+	"password":     true,
+	"routing_key":  true,
+	"token":        true,
+}
+
+// redactedConfig returns the configuration in JSON without secrets. URLs
+// may have credentials in the user info, the path or the query (for
+// example, tokens of webhooks), so only the scheme and the host are kept.
+func redactedConfig(cfg *StaticConfig) ([]byte, error) {
+	bs, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(bs, &m); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal configuration")
+	}
+	return json.MarshalIndent(redact(m), "", "  ")
+}
+
+func redact(v interface{}) interface{} {
+	switch obj := v.(type) {
+	case map[string]interface{}:
+		for k, e := range obj {
+			if secretKeys[k] {
+				obj[k] = redactedValue
+			} else {
+				obj[k] = redact(e)
+			}
+		}
+		return obj
+	case []interface{}:
+		for i, e := range obj {
+			obj[i] = redact(e)
+		}
+		return obj
+	case string:
+		return redactURL(obj)
+	default:
+		return v
+	}
+}
+
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return s
+	}
+	if u.User == nil && u.RawQuery == "" && (u.Path == "" || u.Path == "/") {
+		return s
+	}
+	return u.Scheme + "://" + u.Host + "/" + redactedValue
+}
@@ -6,16 +6,25 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/node/alert"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
 )
 
 const (
 	ChainConfigFileName     = "config.json"
 	ChainGenesisZipFileName = "genesis.zip"
+	APIKeyFileName          = "apikeys.json"
+	AuthFileName            = "rpcauth.json"
+	PluginFileName          = "rpcplugins.json"
 )
 
 type StaticConfig struct {
@@ -23,17 +32,39 @@ type StaticConfig struct {
 	CliSocket     string `json:"node_sock"` // relative path
 	P2PAddr       string `json:"p2p"`
 	P2PListenAddr string `json:"p2p_listen"`
+	P2PAuditLog   string `json:"p2p_audit_log,omitempty"` // relative path
 	RPCAddr       string `json:"rpc_addr"`
 	RPCDump       bool   `json:"rpc_dump"`
+	RPCSocket     string `json:"rpc_sock,omitempty"` // relative path
+	RPCSocketMode string `json:"rpc_sock_mode,omitempty"`
+	GRPCAddr      string `json:"grpc_addr,omitempty"`
 	EESocket      string `json:"ee_socket"`
 	Engines       string `json:"engines"`
 	BackupDir     string `json:"backup_dir"`
 
-	AuthSkipIfEmptyUsers bool `json:"auth_skip_if_empty_users,omitempty"`
-	NIDForP2P            bool `json:"nid_for_p2p,omitempty"`
+	EELimits   *eeproxy.Limits    `json:"ee_limits,omitempty"`
+	MetricPush *metric.PushConfig `json:"metric_push,omitempty"`
+	Alert      *alert.Config      `json:"alert,omitempty"`
+	Registry   *RegistryConfig    `json:"registry,omitempty"`
+
+	DeployChecks *contract.DeployCheckConfig `json:"deploy_checks,omitempty"`
+
+	AuthSkipIfEmptyUsers bool           `json:"auth_skip_if_empty_users,omitempty"`
+	NIDForP2P            bool           `json:"nid_for_p2p,omitempty"`
+	P2PQueryPuzzle       int            `json:"p2p_query_puzzle,omitempty"`
+	P2PMultiplexing      bool           `json:"p2p_mux,omitempty"`
+	P2PPacketTTL         map[string]int `json:"p2p_packet_ttl,omitempty"`
+	P2PPacketHops        map[string]int `json:"p2p_packet_hops,omitempty"`
+	P2PSubnetLimit       int            `json:"p2p_subnet_limit,omitempty"`
+	P2PASNLimit          int            `json:"p2p_asn_limit,omitempty"`
+	P2PASNMap            string         `json:"p2p_asn_map,omitempty"` // relative path
+	NTPServer            string         `json:"ntp_server,omitempty"`
+	RPCTrustedProxies    []string       `json:"rpc_trusted_proxies,omitempty"`
+	RPCProxyProtocol     bool           `json:"rpc_proxy_protocol,omitempty"`
 
 	BaseDir  string `json:"node_dir"`
 	FilePath string `json:"-"` // absolute path
+	LogFile  string `json:"-"` // absolute path
 
 	// build info
 	BuildVersion string `json:"-"`
@@ -77,9 +108,15 @@ func (c *StaticConfig) SetFilePath(path string) string {
 	if c.EESocket != "" {
 		c.EESocket = c.ResolveRelative(ResolveAbsolute(o, c.EESocket))
 	}
+	if c.RPCSocket != "" {
+		c.RPCSocket = c.ResolveRelative(ResolveAbsolute(o, c.RPCSocket))
+	}
 	if c.BackupDir != "" {
 		c.BackupDir = c.ResolveRelative(ResolveAbsolute(o, c.BackupDir))
 	}
+	if c.EELimits != nil && c.EELimits.Seccomp != "" {
+		c.EELimits.Seccomp = c.ResolveRelative(ResolveAbsolute(o, c.EELimits.Seccomp))
+	}
 	return o
 }
 
@@ -102,21 +139,85 @@ func (c *StaticConfig) AbsBaseDir() string {
 	return c.ResolveAbsolute(c.BaseDir)
 }
 
+// AbsEELimits returns the limits of execution engines with the absolute
+// path of the seccomp filter.
+func (c *StaticConfig) AbsEELimits() *eeproxy.Limits {
+	if c.EELimits == nil || c.EELimits.Seccomp == "" {
+		return c.EELimits
+	}
+	limits := *c.EELimits
+	limits.Seccomp = c.ResolveAbsolute(limits.Seccomp)
+	return &limits
+}
+
 const (
-	DefaultEEInstances = 1
+	DefaultEEInstances        = 1
+	DefaultP2PAuditLogMaxSize = 100 // MiB
+
+	ClockSkewCheckTimeout = 5 * time.Second
+	ClockSkewWarning      = time.Second
 )
 
 type RuntimeConfig struct {
-	EEInstances       int    `json:"eeInstances"`
-	RPCDefaultChannel string `json:"rpcDefaultChannel"`
-	RPCIncludeDebug   bool   `json:"rpcIncludeDebug"`
-	RPCRosetta        bool   `json:"rpcRosetta"`
-	RPCBatchLimit     int    `json:"rpcBatchLimit"`
-	WSMaxSession      int    `json:"wsMaxSession"`
+	EEInstances             int     `json:"eeInstances"`
+	RPCDefaultChannel       string  `json:"rpcDefaultChannel"`
+	RPCIncludeDebug         bool    `json:"rpcIncludeDebug"`
+	RPCRosetta              bool    `json:"rpcRosetta"`
+	RPCBatchLimit           int     `json:"rpcBatchLimit"`
+	RPCCallStepLimit        int64   `json:"rpcCallStepLimit"`
+	RPCTraceMaxEntries      int     `json:"rpcTraceMaxEntries"`
+	RPCTraceMaxDepth        int     `json:"rpcTraceMaxDepth"`
+	RPCTraceMaxStringLength int     `json:"rpcTraceMaxStringLength"`
+	RPCTraceRedactValues    bool    `json:"rpcTraceRedactValues"`
+	RPCTraceMaxTimeout      int     `json:"rpcTraceMaxTimeout"`
+	RPCStrict               string  `json:"rpcStrict"`
+	RPCAuth                 string  `json:"rpcAuth"`
+	RPCAPIKeyRequired       bool    `json:"rpcAPIKeyRequired"`
+	RPCRateLimitPerIP       float64 `json:"rpcRateLimitPerIP"`
+	RPCRateLimitPerKey      float64 `json:"rpcRateLimitPerKey"`
+	RPCRateLimitExpensive   float64 `json:"rpcRateLimitExpensive"`
+	WSMaxSession            int     `json:"wsMaxSession"`
+	WSMaxSessionPerIP       int     `json:"wsMaxSessionPerIP"`
+	WSMaxSubscription       int     `json:"wsMaxSubscription"`
+	WSMaxBuffer             int64   `json:"wsMaxBuffer"`
 
 	FilePath string `json:"-"` // absolute path
 }
 
+func (c *RuntimeConfig) traceLimit() jsonrpc.TraceLimit {
+	return jsonrpc.TraceLimit{
+		MaxEntries:      c.RPCTraceMaxEntries,
+		MaxDepth:        c.RPCTraceMaxDepth,
+		MaxStringLength: c.RPCTraceMaxStringLength,
+		RedactValues:    c.RPCTraceRedactValues,
+		MaxTimeout:      time.Duration(c.RPCTraceMaxTimeout) * time.Millisecond,
+	}
+}
+
+func (c *RuntimeConfig) rateLimit() jsonrpc.RateLimit {
+	return jsonrpc.RateLimit{
+		PerIP:     c.RPCRateLimitPerIP,
+		PerKey:    c.RPCRateLimitPerKey,
+		Expensive: c.RPCRateLimitExpensive,
+	}
+}
+
+// strictEndpoints returns the endpoints of JSON-RPC in strict mode.
+func (c *RuntimeConfig) strictEndpoints() []string {
+	if c.RPCStrict == "" {
+		return nil
+	}
+	return strings.Split(c.RPCStrict, ",")
+}
+
+// authEndpoints returns the endpoints of JSON-RPC requiring authentication.
+func (c *RuntimeConfig) authEndpoints() []string {
+	if c.RPCAuth == "" {
+		return nil
+	}
+	return strings.Split(c.RPCAuth, ",")
+}
+
 func (c *RuntimeConfig) load() error {
 	log.Println("load ", c.FilePath)
 	if _, err := os.Stat(c.FilePath); err != nil {
@@ -150,6 +251,10 @@ func loadRuntimeConfig(baseDir string) (*RuntimeConfig, error) {
 		RPCBatchLimit: jsonrpc.DefaultBatchLimit,
 		FilePath:      path.Join(baseDir, "rconfig.json"),
 		WSMaxSession:  server.DefaultWSMaxSession,
+
+		WSMaxSessionPerIP: server.DefaultWSMaxSessionPerIP,
+		WSMaxSubscription: server.DefaultWSMaxSubscription,
+		WSMaxBuffer:       server.DefaultWSMaxBuffer,
 	}
 	if err := cfg.load(); err != nil {
 		if os.IsNotExist(err) {
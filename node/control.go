@@ -0,0 +1,449 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server"
+)
+
+const (
+	UrlControl = "/control"
+
+	controlPollInterval = 200 * time.Millisecond
+	controlLogQueueSize = 256
+)
+
+// Methods of requests over the control connection.
+const (
+	ControlLogs      = "logs"
+	ControlConsensus = "consensus"
+	ControlBackup    = "backup"
+	ControlPrune     = "prune"
+	ControlCancel    = "cancel"
+)
+
+// Events of messages over the control connection. Each request ends with
+// ControlEventDone or ControlEventError.
+const (
+	ControlEventLog       = "log"
+	ControlEventConsensus = "consensus"
+	ControlEventProgress  = "progress"
+	ControlEventDone      = "done"
+	ControlEventError     = "error"
+)
+
+type ControlRequest struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type ControlMessage struct {
+	ID    int64           `json:"id"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+type ControlLogsParam struct {
+	Level string `json:"level,omitempty"`
+}
+
+type ControlChainParam struct {
+	CID string `json:"cid"`
+}
+
+type ControlBackupParam struct {
+	CID    string `json:"cid"`
+	Manual bool   `json:"manual,omitempty"`
+}
+
+type ControlPruneParam struct {
+	CID    string `json:"cid"`
+	DBType string `json:"dbType,omitempty"`
+	Height int64  `json:"height"`
+}
+
+type ControlCancelParam struct {
+	ID int64 `json:"id"`
+}
+
+type ControlLogsResult struct {
+	Dropped int64 `json:"dropped"`
+}
+
+type ControlConsensusView struct {
+	Height    int64  `json:"height"`
+	Round     int32  `json:"round"`
+	Proposer  bool   `json:"proposer"`
+	Partition string `json:"partition,omitempty"`
+}
+
+type ControlProgressView struct {
+	State  string `json:"state"`
+	Height int64  `json:"height"`
+}
+
+type ControlBackupResult struct {
+	Name string `json:"name"`
+}
+
+type controlHandler func(cs *controlSession, params json.RawMessage, stop <-chan struct{}, id int64) (interface{}, error)
+
+var controlHandlers = map[string]controlHandler{
+	ControlLogs:      controlLogs,
+	ControlConsensus: controlConsensus,
+	ControlBackup:    controlBackup,
+	ControlPrune:     controlPrune,
+}
+
+// controlSession handles requests over a connection. Requests run
+// concurrently, and they are stopped on cancel or closing the connection.
+type controlSession struct {
+	n    *Node
+	conn server.WebSocketConn
+
+	wlock sync.Mutex
+
+	lock  sync.Mutex
+	stops map[int64]chan struct{}
+	wg    sync.WaitGroup
+}
+
+func (cs *controlSession) send(id int64, event string, data interface{}, err error) error {
+	msg := &ControlMessage{ID: id, Event: event}
+	if data != nil {
+		bs, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		msg.Data = bs
+	}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+	cs.wlock.Lock()
+	defer cs.wlock.Unlock()
+	return cs.conn.WriteJSON(msg)
+}
+
+func (cs *controlSession) start(req *ControlRequest) error {
+	if req.Method == ControlCancel {
+		var param ControlCancelParam
+		if err := json.Unmarshal(req.Params, &param); err != nil {
+			return errors.IllegalArgumentError.Wrap(err, "InvalidParams")
+		}
+		cs.lock.Lock()
+		defer cs.lock.Unlock()
+		if stop, ok := cs.stops[param.ID]; ok {
+			close(stop)
+			delete(cs.stops, param.ID)
+		}
+		return nil
+	}
+	h, ok := controlHandlers[req.Method]
+	if !ok {
+		return errors.NotFoundError.Errorf("UnknownMethod(method=%s)", req.Method)
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if _, ok := cs.stops[req.ID]; ok {
+		return errors.IllegalArgumentError.Errorf("DuplicateID(id=%d)", req.ID)
+	}
+	stop := make(chan struct{})
+	cs.stops[req.ID] = stop
+	cs.wg.Add(1)
+	go func() {
+		defer cs.wg.Done()
+		res, err := h(cs, req.Params, stop, req.ID)
+		cs.lock.Lock()
+		if s, ok := cs.stops[req.ID]; ok && s == stop {
+			delete(cs.stops, req.ID)
+		}
+		cs.lock.Unlock()
+		if err != nil {
+			_ = cs.send(req.ID, ControlEventError, nil, err)
+		} else {
+			_ = cs.send(req.ID, ControlEventDone, res, nil)
+		}
+	}()
+	return nil
+}
+
+func (cs *controlSession) run() {
+	defer func() {
+		cs.lock.Lock()
+		for id, stop := range cs.stops {
+			close(stop)
+			delete(cs.stops, id)
+		}
+		cs.lock.Unlock()
+		cs.wg.Wait()
+		_ = cs.conn.Close()
+	}()
+	for {
+		_, bs, err := cs.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		req := new(ControlRequest)
+		if err := json.Unmarshal(bs, req); err != nil {
+			_ = cs.send(0, ControlEventError, nil, err)
+			continue
+		}
+		if err := cs.start(req); err != nil {
+			_ = cs.send(req.ID, ControlEventError, nil, err)
+		}
+	}
+}
+
+func (cs *controlSession) chainOf(params json.RawMessage, param interface{}, cid *string) (*Chain, error) {
+	if err := json.Unmarshal(params, param); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidParams")
+	}
+	c := cs.n.GetChainBySelector(*cid)
+	if c == nil {
+		return nil, errors.NotFoundError.Errorf("ChainNotFound(cid=%s)", *cid)
+	}
+	return c, nil
+}
+
+func controlLogs(cs *controlSession, params json.RawMessage, stop <-chan struct{}, id int64) (interface{}, error) {
+	param := ControlLogsParam{Level: "info"}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &param); err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidParams")
+		}
+	}
+	lv, err := log.ParseLevel(param.Level)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidLevel")
+	}
+
+	var dropped int64
+	ch := make(chan *log.Record, controlLogQueueSize)
+	remove := cs.n.tap.AddListener(lv, func(rec *log.Record) {
+		select {
+		case ch <- rec:
+		default:
+			atomic.AddInt64(&dropped, 1)
+		}
+	})
+	defer remove()
+
+	for {
+		select {
+		case rec := <-ch:
+			if err := cs.send(id, ControlEventLog, rec, nil); err != nil {
+				return nil, err
+			}
+		case <-stop:
+			return &ControlLogsResult{Dropped: atomic.LoadInt64(&dropped)}, nil
+		}
+	}
+}
+
+func controlConsensus(cs *controlSession, params json.RawMessage, stop <-chan struct{}, id int64) (interface{}, error) {
+	var param ControlChainParam
+	c, err := cs.chainOf(params, &param, &param.CID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(controlPollInterval)
+	defer ticker.Stop()
+	var last ControlConsensusView
+	for {
+		if cns := c.Consensus(); cns != nil {
+			if status := cns.GetStatus(); status != nil {
+				v := ControlConsensusView{
+					Height:    status.Height,
+					Round:     status.Round,
+					Proposer:  status.Proposer,
+					Partition: string(status.Partition),
+				}
+				if v != last {
+					if err := cs.send(id, ControlEventConsensus, &v, nil); err != nil {
+						return nil, err
+					}
+					last = v
+				}
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return nil, nil
+		}
+	}
+}
+
+// followTask sends the state of the chain on changes until the task of the
+// name finishes.
+func (cs *controlSession) followTask(c *Chain, name string, stop <-chan struct{}, id int64) error {
+	ticker := time.NewTicker(controlPollInterval)
+	defer ticker.Stop()
+	var last ControlProgressView
+	for {
+		state, height, lastErr := c.State()
+		v := ControlProgressView{State: state, Height: height}
+		if v != last {
+			if err := cs.send(id, ControlEventProgress, &v, nil); err != nil {
+				return err
+			}
+			last = v
+		}
+		switch {
+		case state == name+" failed":
+			if lastErr != nil {
+				return lastErr
+			}
+			return errors.InvalidStateError.Errorf("TaskFailed(state=%s)", state)
+		case state == name+" done", !strings.HasPrefix(state, name+" "):
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+func controlBackup(cs *controlSession, params json.RawMessage, stop <-chan struct{}, id int64) (interface{}, error) {
+	var param ControlBackupParam
+	c, err := cs.chainOf(params, &param, &param.CID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := cs.n.BackupChain(c.CID(), param.Manual)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.followTask(c, "backup", stop, id); err != nil {
+		return nil, err
+	}
+	return &ControlBackupResult{Name: name}, nil
+}
+
+func controlPrune(cs *controlSession, params json.RawMessage, stop <-chan struct{}, id int64) (interface{}, error) {
+	var param ControlPruneParam
+	c, err := cs.chainOf(params, &param, &param.CID)
+	if err != nil {
+		return nil, err
+	}
+	if param.Height < 1 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidHeight(height=%d)", param.Height)
+	}
+	if err := cs.n.PruneChain(c.CID(), param.DBType, param.Height); err != nil {
+		return nil, err
+	}
+	return nil, cs.followTask(c, "pruning", stop, id)
+}
+
+func (r *Rest) RegisterControlHandlers(g *echo.Group) *echo.Route {
+	return g.GET(UrlControl, r.Control)
+}
+
+// Control serves the control connection. It streams logs, consensus status
+// and progress of long-running tasks, so that clients don't need to poll.
+func (r *Rest) Control(ctx echo.Context) error {
+	conn, err := server.NewWebSocketUpgrader().Upgrade(ctx)
+	if err != nil {
+		return err
+	}
+	cs := &controlSession{
+		n:     r.n,
+		conn:  conn,
+		stops: make(map[int64]chan struct{}),
+	}
+	cs.run()
+	return nil
+}
+
+// ControlClient sends a request over the control connection of the node
+// through the unix domain socket.
+type ControlClient struct {
+	conn *websocket.Conn
+	next int64
+
+	wlock sync.Mutex
+}
+
+// Control opens the control connection through the socket of the client.
+func (c *UnixDomainSockHttpClient) Control() (*ControlClient, error) {
+	d := websocket.Dialer{
+		NetDialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var nd net.Dialer
+			return nd.DialContext(ctx, "unix", resolveSocketPath(c.sockPath))
+		},
+	}
+	conn, _, err := d.Dial("ws://localhost"+UrlControl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ControlClient{conn: conn}, nil
+}
+
+// Run sends the request and calls the callback for the messages of it
+// until it's done. It returns the error of the request if it fails.
+func (c *ControlClient) Run(method string, params interface{}, cb func(msg *ControlMessage) error) error {
+	req := &ControlRequest{ID: atomic.AddInt64(&c.next, 1), Method: method}
+	if params != nil {
+		bs, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = bs
+	}
+	if err := c.write(req); err != nil {
+		return err
+	}
+	for {
+		msg := new(ControlMessage)
+		if err := c.conn.ReadJSON(msg); err != nil {
+			return err
+		}
+		if msg.ID != req.ID {
+			continue
+		}
+		switch msg.Event {
+		case ControlEventError:
+			return errors.New(msg.Error)
+		case ControlEventDone:
+			return cb(msg)
+		default:
+			if err := cb(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Cancel requests to stop the request of the last Run.
+func (c *ControlClient) Cancel() error {
+	params, _ := json.Marshal(&ControlCancelParam{ID: atomic.LoadInt64(&c.next)})
+	return c.write(&ControlRequest{Method: ControlCancel, Params: params})
+}
+
+func (c *ControlClient) write(req *ControlRequest) error {
+	c.wlock.Lock()
+	defer c.wlock.Unlock()
+	return c.conn.WriteJSON(req)
+}
+
+func (c *ControlClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,111 @@
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+func newTestControlClient(t *testing.T, n *Node) (*ControlClient, func()) {
+	e := echo.New()
+	r := &Rest{n: n}
+	r.RegisterControlHandlers(e.Group(""))
+	srv := httptest.NewServer(e)
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(srv.URL, "http")+UrlControl, nil)
+	assert.NoError(t, err)
+	c := &ControlClient{conn: conn}
+	return c, func() {
+		_ = c.Close()
+		srv.Close()
+	}
+}
+
+func newTestControlNode() (*Node, log.Logger) {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	return &Node{
+		tap:      log.AddTap(logger),
+		chains:   make(map[string]*Chain),
+		channels: make(map[int]string),
+	}, logger
+}
+
+func TestControl_Logs(t *testing.T) {
+	n, logger := newTestControlNode()
+	c, closer := newTestControlClient(t, n)
+	defer closer()
+
+	var logs []*log.Record
+	var result ControlLogsResult
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ControlLogs, &ControlLogsParam{Level: "warn"}, func(msg *ControlMessage) error {
+			switch msg.Event {
+			case ControlEventLog:
+				rec := new(log.Record)
+				assert.NoError(t, json.Unmarshal(msg.Data, rec))
+				logs = append(logs, rec)
+				if len(logs) == 1 {
+					return c.Cancel()
+				}
+			case ControlEventDone:
+				return json.Unmarshal(msg.Data, &result)
+			}
+			return nil
+		})
+	}()
+
+	// log until the request gets one
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		logger.Info("info")
+		logger.Warn("warn")
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.NotEmpty(t, logs)
+			for _, rec := range logs {
+				assert.Equal(t, "warn", rec.Message)
+				assert.Equal(t, "warn", rec.Level)
+			}
+			assert.Zero(t, result.Dropped)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func TestControl_Errors(t *testing.T) {
+	n, _ := newTestControlNode()
+	c, closer := newTestControlClient(t, n)
+	defer closer()
+
+	none := func(msg *ControlMessage) error { return nil }
+	err := c.Run("unknown", nil, none)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "UnknownMethod")
+
+	err = c.Run(ControlLogs, &ControlLogsParam{Level: "noisy"}, none)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "InvalidLevel")
+
+	err = c.Run(ControlConsensus, &ControlChainParam{CID: "0x1"}, none)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ChainNotFound")
+
+	err = c.Run(ControlBackup, &ControlBackupParam{CID: "test"}, none)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ChainNotFound")
+}
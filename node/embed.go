@@ -0,0 +1,257 @@
+package node
+
+import (
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server"
+)
+
+// Embedded runs the node inside another Go program, such as test frameworks
+// or appliances, instead of running goloop binary. Unlike Node.Start, Start
+// doesn't block and the CLI socket isn't served. JSON-RPC is served only if
+// the RPC address is configured, and chains can be queried directly with
+// Query. Other operations of the node (ex. JoinChain) are available through
+// the embedded Node.
+type Embedded struct {
+	*Node
+
+	lock    sync.Mutex
+	started bool
+	rpc     bool
+}
+
+func panicError(r interface{}) error {
+	switch v := r.(type) {
+	case error:
+		return v
+	case *logrus.Entry:
+		return errors.InvalidStateError.New(v.Message)
+	default:
+		return errors.InvalidStateError.Errorf("%v", v)
+	}
+}
+
+// NewEmbedded creates the node with the configuration, and loads the
+// chains in the base directory. Failures are returned as error instead of
+// panic.
+func NewEmbedded(w module.Wallet, cfg *StaticConfig, l log.Logger) (e *Embedded, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, err = nil, panicError(r)
+		}
+	}()
+	return &Embedded{Node: NewNode(w, cfg, l)}, nil
+}
+
+// Start listens P2P and starts chains configured to start automatically.
+// It returns an error if any of them fails to start.
+func (e *Embedded) Start() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.started {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	if err := e.nt.Listen(); err != nil {
+		return errors.Wrap(err, "fail to P2P listen")
+	}
+	e.started = true
+
+	for _, c := range e.GetChains() {
+		if !c.cfg.AutoStart {
+			continue
+		}
+		if err := e.StartChain(c.CID()); err != nil {
+			return errors.Wrapf(err, "fail to start chain channel=%s", c.cfg.Channel)
+		}
+	}
+
+	if e.alert != nil {
+		e.alert.Start()
+	}
+	if e.registry != nil {
+		e.registry.Start()
+	}
+
+	if e.cfg.RPCAddr != "" {
+		e.rpc = true
+		go func() {
+			if err := e.srv.Start(); err != nil && err != http.ErrServerClosed {
+				e.logger.Errorf("fail to start server err=%+v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop terminates the chains and closes the network and the server. The
+// node can't be started again.
+func (e *Embedded) Stop() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if !e.started {
+		return errors.InvalidStateError.New("NotStarted")
+	}
+	e.started = false
+
+	for _, c := range e.GetChains() {
+		if err := c.Term(); err != nil && !errors.InvalidStateError.Equals(err) {
+			e.logger.Warnf("fail to terminate chain channel=%s err=%+v",
+				c.cfg.Channel, err)
+		}
+	}
+	if e.alert != nil {
+		e.alert.Stop()
+	}
+	if e.registry != nil {
+		e.registry.Stop()
+	}
+	if e.rpc {
+		if err := e.srv.Stop(); err != nil {
+			e.logger.Warnf("fail to stop server err=%+v", err)
+		}
+	}
+	if err := e.pm.Close(); err != nil {
+		e.logger.Warnf("fail to close execution engines err=%+v", err)
+	}
+	return e.nt.Close()
+}
+
+// Server returns the JSON-RPC server, so that middlewares like CORS
+// allowlists or request logging may be configured before Start.
+func (e *Embedded) Server() *server.Manager {
+	return e.srv
+}
+
+// Query returns ChainQuery for the chain selected by the channel or
+// the chain ID in hex.
+func (e *Embedded) Query(selector string) (*ChainQuery, error) {
+	c := e.GetChainBySelector(selector)
+	if c == nil {
+		return nil, errors.Wrapf(ErrNotExists, "Network(%s) not exists", selector)
+	}
+	return &ChainQuery{chain: c.Chain}, nil
+}
+
+// ChainQuery queries blocks and states of the chain through the block
+// manager and the service manager without JSON-RPC. The chain must be
+// started.
+type ChainQuery struct {
+	chain module.Chain
+}
+
+func (q *ChainQuery) Chain() module.Chain {
+	return q.chain
+}
+
+func (q *ChainQuery) managers() (module.BlockManager, module.ServiceManager, error) {
+	bm := q.chain.BlockManager()
+	sm := q.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, nil, errors.InvalidStateError.New("Stopped")
+	}
+	return bm, sm, nil
+}
+
+func (q *ChainQuery) LastBlock() (module.Block, error) {
+	bm, _, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	return bm.GetLastBlock()
+}
+
+func (q *ChainQuery) BlockByHeight(height int64) (module.Block, error) {
+	bm, _, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	return bm.GetBlockByHeight(height)
+}
+
+func (q *ChainQuery) BlockByHash(id []byte) (module.Block, error) {
+	bm, _, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	return bm.GetBlock(id)
+}
+
+// TransactionInfo returns the block, the index and the result of the
+// transaction.
+func (q *ChainQuery) TransactionInfo(id []byte) (module.TransactionInfo, error) {
+	bm, _, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	return bm.GetTransactionInfo(id)
+}
+
+// Balance returns the balance of the account at the last block.
+func (q *ChainQuery) Balance(addr module.Address) (*big.Int, error) {
+	bm, sm, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	return sm.GetBalance(blk.Result(), addr)
+}
+
+// TotalSupply returns the total supply at the last block.
+func (q *ChainQuery) TotalSupply() (*big.Int, error) {
+	bm, sm, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	return sm.GetTotalSupply(blk.Result())
+}
+
+// Call runs the read-only call in JSON (same as params of icx_call) on
+// the state of the last block.
+func (q *ChainQuery) Call(js []byte) (interface{}, error) {
+	bm, sm, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	return sm.Call(blk.Result(), blk.NextValidators(), js, bi)
+}
+
+// SendTransaction adds the transaction in JSON (same as params of
+// icx_sendTransaction) to the transaction pool, and returns its hash.
+func (q *ChainQuery) SendTransaction(js []byte) ([]byte, error) {
+	_, sm, err := q.managers()
+	if err != nil {
+		return nil, err
+	}
+	var result []byte
+	var height int64
+	if q.chain.ValidateTxOnSend() {
+		blk, err := q.LastBlock()
+		if err != nil {
+			return nil, err
+		}
+		result, height = blk.Result(), blk.Height()+1
+	}
+	return sm.SendTransaction(result, height, js)
+}
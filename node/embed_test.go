@@ -0,0 +1,114 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test"
+)
+
+func TestPanicError(t *testing.T) {
+	err := errors.NotFoundError.New("NotFound")
+	assert.Equal(t, err, panicError(err))
+
+	err = panicError(&logrus.Entry{Message: "fail to load"})
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.EqualError(t, err, "fail to load")
+
+	err = panicError("unknown")
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.EqualError(t, err, "unknown")
+}
+
+func TestEmbedded_State(t *testing.T) {
+	e := &Embedded{Node: &Node{}}
+	assert.True(t, errors.InvalidStateError.Equals(e.Stop()))
+
+	e.started = true
+	assert.True(t, errors.InvalidStateError.Equals(e.Start()))
+}
+
+type testStoppedChain struct {
+	module.Chain
+}
+
+func (c *testStoppedChain) BlockManager() module.BlockManager     { return nil }
+func (c *testStoppedChain) ServiceManager() module.ServiceManager { return nil }
+
+// testQueryServiceManager returns the balance of the state at the result.
+type testQueryServiceManager struct {
+	module.ServiceManager
+	balances map[string]*big.Int
+}
+
+func (sm *testQueryServiceManager) GetBalance(result []byte, addr module.Address) (*big.Int, error) {
+	return sm.balances[string(result)], nil
+}
+
+func (sm *testQueryServiceManager) GetTotalSupply(result []byte) (*big.Int, error) {
+	return sm.balances[string(result)], nil
+}
+
+type testQueryChain struct {
+	module.Chain
+	sm module.ServiceManager
+}
+
+func (c *testQueryChain) ServiceManager() module.ServiceManager { return c.sm }
+
+func TestEmbedded_Query(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	sm := &testQueryServiceManager{
+		ServiceManager: nd.SM,
+		balances: map[string]*big.Int{
+			string(nd.GetLastBlock().Result()): big.NewInt(100),
+		},
+	}
+
+	e := &Embedded{Node: &Node{
+		chains: map[string]*Chain{
+			"test":    {Chain: &testQueryChain{Chain: nd.Chain, sm: sm}},
+			"stopped": {Chain: &testStoppedChain{}},
+		},
+		channels: make(map[int]string),
+	}}
+	_, err := e.Query("unknown")
+	assert.True(t, errors.Is(err, ErrNotExists))
+
+	q, err := e.Query("test")
+	assert.NoError(t, err)
+	blk, err := q.LastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), blk.Height())
+	blk2, err := q.BlockByHeight(1)
+	assert.NoError(t, err)
+	assert.Equal(t, blk.ID(), blk2.ID())
+	blk2, err = q.BlockByHash(blk.ID())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), blk2.Height())
+
+	god := common.MustNewAddressFromString("hx54f7853dc6481b670caf69c5a27c7c8fe5be8269")
+	balance, err := q.Balance(god)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), balance)
+	supply, err := q.TotalSupply()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), supply)
+
+	// queries fail while the chain is stopped
+	q, err = e.Query("stopped")
+	assert.NoError(t, err)
+	_, err = q.LastBlock()
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	_, err = q.Balance(god)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+}
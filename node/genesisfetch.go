@@ -0,0 +1,152 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/gssync"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	DefaultGenesisFetchTimeout = 600 // in second
+)
+
+// ChainFetchParam is the parameter for joining the chain with the genesis
+// storage fetched from the peers. GenesisID is the ID of the genesis
+// transaction, which determines CID of the chain, and NID is required only
+// if the node uses NID for P2P.
+type ChainFetchParam struct {
+	ChainConfig
+	GenesisID common.HexBytes `json:"genesisID"`
+	NID       int             `json:"nid,omitempty"`
+	Timeout   int             `json:"timeout,omitempty"` // in second
+}
+
+// fetchChain is the chain for the network manager fetching the genesis
+// storage, and it has only the IDs of the chain to be joined.
+type fetchChain struct {
+	module.Chain
+	cid, nid, netID int
+	metricCtx       context.Context
+	logger          log.Logger
+}
+
+func (c *fetchChain) CID() int {
+	return c.cid
+}
+
+func (c *fetchChain) NID() int {
+	return c.nid
+}
+
+func (c *fetchChain) NetID() int {
+	return c.netID
+}
+
+func (c *fetchChain) MetricContext() context.Context {
+	return c.metricCtx
+}
+
+func (c *fetchChain) Logger() log.Logger {
+	return c.logger
+}
+
+func (c *fetchChain) ChildrenLimit() int {
+	return chain.ConfigDefaultChildrenLimit
+}
+
+func (c *fetchChain) NephewsLimit() int {
+	return chain.ConfigDefaultNephewLimit
+}
+
+// newFetchChain returns the chain for fetching data of the chain with the
+// CID from the peers. NID is required if the node uses NID for P2P.
+func (n *Node) newFetchChain(cid, nid int) (*fetchChain, error) {
+	netID := cid
+	if n.cfg.NIDForP2P {
+		if nid == 0 {
+			return nil, errors.IllegalArgumentError.New("NIDRequired")
+		}
+		netID = nid
+	}
+	return &fetchChain{
+		cid:       cid,
+		nid:       nid,
+		netID:     netID,
+		metricCtx: metric.GetMetricContextByCID(cid),
+		logger: n.logger.WithFields(log.Fields{
+			log.FieldKeyCID: strconv.FormatInt(int64(cid), 16),
+		}),
+	}, nil
+}
+
+// JoinChainWithFetch joins the chain with the genesis storage fetched from
+// the peers of the seeds. The genesis storage is verified with the ID of
+// the genesis transaction, so pruned genesis storages are not supported.
+func (n *Node) JoinChainWithFetch(p *ChainFetchParam) (module.Chain, error) {
+	if len(p.GenesisID) != 32 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidGenesisID(%s)", p.GenesisID)
+	}
+	if p.SeedAddr == "" {
+		return nil, errors.IllegalArgumentError.New("NoSeedAddress")
+	}
+	cid := transaction.CIDForGenesisTransactionID(p.GenesisID)
+	if n.GetChain(cid) != nil {
+		return nil, errors.Wrapf(ErrAlreadyExists, "Chain(cid=%#x) already exists", cid)
+	}
+	fc, err := n.newFetchChain(cid, p.NID)
+	if err != nil {
+		return nil, err
+	}
+	timeout := DefaultGenesisFetchTimeout
+	if p.Timeout > 0 {
+		timeout = p.Timeout
+	}
+
+	bs, err := n.fetchGenesis(fc, p.SeedAddr, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	genesisStorage, err := gs.New(bs)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get genesis storage")
+	}
+	gtx, err := transaction.NewGenesisTransaction(genesisStorage.Genesis())
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get genesis transaction")
+	}
+	if !bytes.Equal(gtx.ID(), p.GenesisID) {
+		return nil, errors.InvalidStateError.Errorf(
+			"GenesisIDMismatch(exp=%s,real=%#x)", p.GenesisID, gtx.ID())
+	}
+	return n.JoinChain(&p.ChainConfig, bs)
+}
+
+func (n *Node) fetchGenesis(c *fetchChain, seeds string, timeout time.Duration) ([]byte, error) {
+	nm := network.NewManager(c, n.nt, seeds)
+	defer nm.Term()
+
+	f, err := gssync.NewFetcher(nm, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Term()
+
+	if err := nm.Start(); err != nil {
+		return nil, err
+	}
+	c.logger.Infof("fetch genesis from peers seeds=%s", seeds)
+	return f.Fetch(timeout)
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright 2021 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package node
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	GenesisUploadDirectory = ".genesis_upload"
+
+	genesisUploadDataSuffix = ".part"
+	genesisUploadMetaSuffix = ".json"
+)
+
+// GenesisUpload is the status of a chunked genesis storage upload.
+// ID is the hex string of the checksum (SHA3-256) of the whole file,
+// so starting an upload of the same file again resumes it.
+type GenesisUpload struct {
+	ID       string          `json:"id"`
+	Size     int64           `json:"size"`
+	Checksum common.HexBytes `json:"checksum"`
+	Received int64           `json:"received"`
+	Complete bool            `json:"complete"`
+}
+
+type genesisUploadMeta struct {
+	Size     int64           `json:"size"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+// GenesisUploadManager keeps partially uploaded genesis storages under
+// the node directory. Received bytes are kept in the file system, so
+// uploads survive restart of the node.
+type GenesisUploadManager struct {
+	lock sync.Mutex
+	dir  string
+}
+
+func (m *GenesisUploadManager) dataFile(id string) string {
+	return path.Join(m.dir, id+genesisUploadDataSuffix)
+}
+
+func (m *GenesisUploadManager) metaFile(id string) string {
+	return path.Join(m.dir, id+genesisUploadMetaSuffix)
+}
+
+func (m *GenesisUploadManager) _get(id string) (*GenesisUpload, error) {
+	if _, err := hex.DecodeString(id); err != nil || len(id) == 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidUploadID(id=%s)", id)
+	}
+	b, err := ioutil.ReadFile(m.metaFile(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NotFoundError.Errorf("UploadNotFound(id=%s)", id)
+		}
+		return nil, err
+	}
+	var meta genesisUploadMeta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return nil, errors.CriticalFormatError.Wrapf(err, "InvalidUploadMeta(id=%s)", id)
+	}
+	var received int64
+	if fi, err := os.Stat(m.dataFile(id)); err == nil {
+		received = fi.Size()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &GenesisUpload{
+		ID:       id,
+		Size:     meta.Size,
+		Checksum: meta.Checksum,
+		Received: received,
+		Complete: received == meta.Size,
+	}, nil
+}
+
+// Start begins a new upload or returns the status of the existing upload
+// with the same checksum.
+func (m *GenesisUploadManager) Start(size int64, checksum []byte) (*GenesisUpload, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if size <= 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidSize(size=%d)", size)
+	}
+	if len(checksum) != 32 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidChecksum(checksum=%#x)", checksum)
+	}
+	id := hex.EncodeToString(checksum)
+	if u, err := m._get(id); err == nil {
+		if u.Size != size {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"SizeMismatch(id=%s,size=%d,exp=%d)", id, size, u.Size)
+		}
+		return u, nil
+	} else if !errors.NotFoundError.Equals(err) {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return nil, err
+	}
+	meta, err := json.Marshal(&genesisUploadMeta{
+		Size:     size,
+		Checksum: checksum,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(m.dataFile(id), nil, 0644); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(m.metaFile(id), meta, 0644); err != nil {
+		_ = os.Remove(m.dataFile(id))
+		return nil, err
+	}
+	return m._get(id)
+}
+
+// Get returns the status of the upload.
+func (m *GenesisUploadManager) Get(id string) (*GenesisUpload, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m._get(id)
+}
+
+// GetAll returns the status of all the uploads.
+func (m *GenesisUploadManager) GetAll() ([]*GenesisUpload, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	fis, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*GenesisUpload{}, nil
+		}
+		return nil, err
+	}
+	uploads := make([]*GenesisUpload, 0)
+	for _, fi := range fis {
+		if !fi.Mode().IsRegular() || !strings.HasSuffix(fi.Name(), genesisUploadMetaSuffix) {
+			continue
+		}
+		u, err := m._get(strings.TrimSuffix(fi.Name(), genesisUploadMetaSuffix))
+		if err != nil {
+			continue
+		}
+		uploads = append(uploads, u)
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].ID < uploads[j].ID
+	})
+	return uploads, nil
+}
+
+// Write appends the chunk at the offset. The offset must be same as
+// the number of received bytes, and the chunk must match its checksum.
+// On receiving the last chunk, it verifies the checksum of the whole
+// file, and discards received bytes on mismatch.
+func (m *GenesisUploadManager) Write(id string, offset int64, chunk []byte, checksum []byte) (*GenesisUpload, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	u, err := m._get(id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Complete {
+		return u, errors.InvalidStateError.Errorf("AlreadyComplete(id=%s)", id)
+	}
+	if offset != u.Received {
+		return u, errors.InvalidStateError.Errorf(
+			"InvalidOffset(offset=%d,received=%d)", offset, u.Received)
+	}
+	if offset+int64(len(chunk)) > u.Size {
+		return u, errors.IllegalArgumentError.Errorf(
+			"ChunkOverflow(offset=%d,len=%d,size=%d)", offset, len(chunk), u.Size)
+	}
+	if !bytes.Equal(crypto.SHA3Sum256(chunk), checksum) {
+		return u, errors.IllegalArgumentError.Errorf(
+			"ChunkChecksumMismatch(offset=%d,checksum=%#x)", offset, checksum)
+	}
+
+	f, err := os.OpenFile(m.dataFile(id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Write(chunk)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Received += int64(len(chunk))
+	if u.Received == u.Size {
+		if err := m._verify(u); err != nil {
+			return nil, err
+		}
+		u.Complete = true
+	}
+	return u, nil
+}
+
+func (m *GenesisUploadManager) _verify(u *GenesisUpload) error {
+	f, err := os.Open(m.dataFile(u.ID))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha3.New256()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, u.Checksum) {
+		if err := os.Truncate(m.dataFile(u.ID), 0); err != nil {
+			return err
+		}
+		return errors.IllegalArgumentError.Errorf(
+			"ChecksumMismatch(id=%s,checksum=%#x)", u.ID, sum)
+	}
+	return nil
+}
+
+// Consume calls the function with the path of the complete upload, and
+// it removes the upload if the function succeeds. The function may move
+// the file to the other place.
+func (m *GenesisUploadManager) Consume(id string, f func(file string) error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	u, err := m._get(id)
+	if err != nil {
+		return err
+	}
+	if !u.Complete {
+		return errors.InvalidStateError.Errorf(
+			"IncompleteUpload(id=%s,received=%d,size=%d)", id, u.Received, u.Size)
+	}
+	if err := f(m.dataFile(id)); err != nil {
+		return err
+	}
+	return m._remove(id)
+}
+
+// Cancel removes the upload and received bytes.
+func (m *GenesisUploadManager) Cancel(id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, err := m._get(id); err != nil {
+		return err
+	}
+	return m._remove(id)
+}
+
+func (m *GenesisUploadManager) _remove(id string) error {
+	if err := os.Remove(m.dataFile(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(m.metaFile(id))
+}
@@ -0,0 +1,103 @@
+package node
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestGenesisUploadManager_Resume(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("genesis storage with some bytes")
+	checksum := crypto.SHA3Sum256(data)
+	chunk1, chunk2 := data[:10], data[10:]
+
+	m := &GenesisUploadManager{dir: dir}
+	u, err := m.Start(int64(len(data)), checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(checksum), u.ID)
+	assert.Zero(t, u.Received)
+
+	// chunks shall be in order with valid checksums
+	_, err = m.Write(u.ID, 10, chunk2, crypto.SHA3Sum256(chunk2))
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	_, err = m.Write(u.ID, 0, chunk1, crypto.SHA3Sum256(chunk2))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	u, err = m.Write(u.ID, 0, chunk1, crypto.SHA3Sum256(chunk1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), u.Received)
+	assert.False(t, u.Complete)
+
+	// the upload is resumed by the manager after restart
+	m = &GenesisUploadManager{dir: dir}
+	u, err = m.Start(int64(len(data)), checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), u.Received)
+	_, err = m.Start(int64(len(data))+1, checksum)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	u, err = m.Write(u.ID, 10, chunk2, crypto.SHA3Sum256(chunk2))
+	assert.NoError(t, err)
+	assert.True(t, u.Complete)
+	_, err = m.Write(u.ID, u.Size, nil, crypto.SHA3Sum256(nil))
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	uploads, err := m.GetAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []*GenesisUpload{u}, uploads)
+
+	err = m.Consume(u.ID, func(file string) error {
+		b, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Equal(t, data, b)
+		return nil
+	})
+	assert.NoError(t, err)
+	_, err = m.Get(u.ID)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
+
+func TestGenesisUploadManager_ChecksumMismatch(t *testing.T) {
+	m := &GenesisUploadManager{dir: t.TempDir()}
+	data := []byte("genesis")
+	checksum := crypto.SHA3Sum256([]byte("another"))
+
+	u, err := m.Start(int64(len(data)), checksum)
+	assert.NoError(t, err)
+	_, err = m.Write(u.ID, 0, data, crypto.SHA3Sum256(data))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// received bytes are discarded to upload again
+	u, err = m.Get(u.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, u.Received)
+	assert.False(t, u.Complete)
+	err = m.Consume(u.ID, func(file string) error { return nil })
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	assert.NoError(t, m.Cancel(u.ID))
+	uploads, err := m.GetAll()
+	assert.NoError(t, err)
+	assert.Empty(t, uploads)
+}
+
+func TestGenesisUploadManager_InvalidParams(t *testing.T) {
+	m := &GenesisUploadManager{dir: t.TempDir()}
+
+	_, err := m.Start(0, crypto.SHA3Sum256(nil))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = m.Start(10, []byte{0x01})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = m.Get("../escape")
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	u, err := m.Start(4, crypto.SHA3Sum256([]byte("data")))
+	assert.NoError(t, err)
+	_, err = m.Write(u.ID, 0, []byte("data+"), crypto.SHA3Sum256([]byte("data+")))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
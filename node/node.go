@@ -14,13 +14,21 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/chain/endpoint"
+	"github.com/icon-project/goloop/chain/exporter"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/profile"
+	"github.com/icon-project/goloop/chain/recovery"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/ntp"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
+	"github.com/icon-project/goloop/node/alert"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/eeproxy"
 )
 
@@ -35,6 +43,7 @@ type Node struct {
 	srv  *server.Manager
 	pm   eeproxy.Manager
 	rsm  RestoreManager
+	gum  GenesisUploadManager
 	cfg  StaticConfig
 	rcfg *RuntimeConfig
 
@@ -45,7 +54,11 @@ type Node struct {
 	chains   map[string]*Chain
 	channels map[int]string
 
-	cliSrv *UnixDomainSockHttpServer
+	cliSrv   *UnixDomainSockHttpServer
+	alert    *alert.Engine
+	registry *registryManager
+	recorder *log.Recorder
+	tap      *log.Tap
 }
 
 type Chain struct {
@@ -75,14 +88,17 @@ func (n *Node) loadChainConfig(chainDir string) (*chain.Config, error) {
 	cfg.NIDForP2P = n.cfg.NIDForP2P
 
 	gsFile := path.Join(chainDir, ChainGenesisZipFileName)
-	genesis, err := ioutil.ReadFile(gsFile)
+	cfg.GenesisFile, _ = filepath.Abs(gsFile)
+	cfg.SnapshotDir = n.cfg.ResolveAbsolute(n.cfg.BackupDir)
+	fd, err := os.Open(gsFile)
 	if err != nil {
 		return nil, errors.CriticalIOError.Wrapf(err,
 			"Fail to read chain genesis zip file %s err=%+v", gsFile, err)
 	}
 
-	genesisStorage, err := gs.New(genesis)
+	genesisStorage, err := gs.NewFromFile(fd)
 	if err != nil {
+		fd.Close()
 		return nil, errors.CriticalIOError.Wrapf(err,
 			"Fail to parse chain genesis zip file %s err=%+v", gsFile, err)
 	}
@@ -329,6 +345,18 @@ func (n *Node) Start() {
 		}
 	}()
 
+	if n.cfg.NTPServer != "" {
+		go n.checkClockSkew(n.cfg.NTPServer)
+	}
+
+	if n.alert != nil {
+		n.alert.Start()
+	}
+
+	if n.registry != nil {
+		n.registry.Start()
+	}
+
 	go func() {
 		if err := n.srv.Start(); err != nil {
 			log.Panicf("fail to server close err=%+v", err)
@@ -341,7 +369,31 @@ func (n *Node) Start() {
 
 }
 
+// checkClockSkew measures the offset of the NTP server from the local clock,
+// because transactions of users are rejected if the clock of the node is
+// behind more than the timestamp window of the chain.
+func (n *Node) checkClockSkew(server string) {
+	offset, err := ntp.Offset(server, ClockSkewCheckTimeout)
+	if err != nil {
+		n.logger.Warnf("fail to check clock skew server=%s err=%+v", server, err)
+		return
+	}
+	metric.RecordClockSkew(offset)
+	if offset > ClockSkewWarning || offset < -ClockSkewWarning {
+		n.logger.Warnf("clock skew from NTP server=%s offset=%s; "+
+			"transactions may be rejected with FutureTx or Expired", server, offset)
+	} else {
+		n.logger.Infof("clock skew from NTP server=%s offset=%s", server, offset)
+	}
+}
+
 func (n *Node) Stop() {
+	if n.alert != nil {
+		n.alert.Stop()
+	}
+	if n.registry != nil {
+		n.registry.Stop()
+	}
 	if err := n.nt.Close(); err != nil {
 		log.Panicf("fail to P2P close err=%+v", err)
 	}
@@ -366,6 +418,50 @@ func (n *Node) JoinChain(
 		return nil, errors.Wrap(err, "fail to get genesis storage")
 	}
 
+	return n._joinChain(p, genesisStorage, func(gsFile string) error {
+		return ioutil.WriteFile(gsFile, genesis, 0644)
+	})
+}
+
+// JoinChainWithUpload joins the chain with the genesis storage uploaded
+// by chunks. The uploaded file is moved to the chain directory.
+func (n *Node) JoinChainWithUpload(
+	p *ChainConfig,
+	id string,
+) (module.Chain, error) {
+	var c module.Chain
+	err := n.gum.Consume(id, func(file string) error {
+		defer n.mtx.Unlock()
+		n.mtx.Lock()
+
+		fd, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		genesisStorage, err := gs.NewFromFile(fd)
+		if err != nil {
+			fd.Close()
+			return errors.Wrap(err, "fail to get genesis storage")
+		}
+		c, err = n._joinChain(p, genesisStorage, func(gsFile string) error {
+			return os.Rename(file, gsFile)
+		})
+		if err != nil {
+			fd.Close()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (n *Node) _joinChain(
+	p *ChainConfig,
+	genesisStorage module.GenesisStorage,
+	writeGenesis func(gsFile string) error,
+) (module.Chain, error) {
 	cid, err := genesisStorage.CID()
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to get CID for genesis")
@@ -389,31 +485,51 @@ func (n *Node) JoinChain(
 	log.Println("ChainDir", chainDir)
 
 	cfgFile, _ := filepath.Abs(path.Join(chainDir, ChainConfigFileName))
+	gsFile, _ := filepath.Abs(path.Join(chainDir, ChainGenesisZipFileName))
 
 	cfg := &chain.Config{
-		NID:              nid,
-		DBType:           p.DBType,
-		Platform:         p.Platform,
-		Channel:          channel,
-		SecureSuites:     p.SecureSuites,
-		SecureAeads:      p.SecureAeads,
-		SeedAddr:         p.SeedAddr,
-		Role:             p.Role,
-		GenesisStorage:   genesisStorage,
-		ConcurrencyLevel: p.ConcurrencyLevel,
-		NormalTxPoolSize: p.NormalTxPoolSize,
-		PatchTxPoolSize:  p.PatchTxPoolSize,
-		MaxBlockTxBytes:  p.MaxBlockTxBytes,
-		NodeCache:        p.NodeCache,
-		DefWaitTimeout:   p.DefWaitTimeout,
-		MaxWaitTimeout:   p.MaxWaitTimeout,
-		TxTimeout:        p.TxTimeout,
-		AutoStart:        p.AutoStart,
-		FilePath:         cfgFile,
-		NIDForP2P:        n.cfg.NIDForP2P,
-		ChildrenLimit:    p.ChildrenLimit,
-		NephewsLimit:     p.NephewsLimit,
-		ValidateTxOnSend: p.ValidateTxOnSend,
+		NID:                nid,
+		DBType:             p.DBType,
+		Platform:           p.Platform,
+		Channel:            channel,
+		SecureSuites:       p.SecureSuites,
+		SecureAeads:        p.SecureAeads,
+		SeedAddr:           p.SeedAddr,
+		Role:               p.Role,
+		GenesisStorage:     genesisStorage,
+		ConcurrencyLevel:   p.ConcurrencyLevel,
+		NormalTxPoolSize:   p.NormalTxPoolSize,
+		PatchTxPoolSize:    p.PatchTxPoolSize,
+		PriorityTxPoolSize: p.PriorityTxPoolSize,
+		MaxBlockTxBytes:    p.MaxBlockTxBytes,
+		NodeCache:          p.NodeCache,
+		DefWaitTimeout:     p.DefWaitTimeout,
+		MaxWaitTimeout:     p.MaxWaitTimeout,
+		TxTimeout:          p.TxTimeout,
+		AutoStart:          p.AutoStart,
+		FilePath:           cfgFile,
+		GenesisFile:        gsFile,
+		SnapshotDir:        n.cfg.ResolveAbsolute(n.cfg.BackupDir),
+		NIDForP2P:          n.cfg.NIDForP2P,
+		ChildrenLimit:      p.ChildrenLimit,
+		NephewsLimit:       p.NephewsLimit,
+		WALRetention:       p.WALRetention,
+		ValidateTxOnSend:   p.ValidateTxOnSend,
+		LightServer:        p.LightServer,
+		SCOREIndex:         p.SCOREIndex,
+		EventIndex:         p.EventIndex,
+		AddressIndex:       p.AddressIndex,
+		AccessIndex:        p.AccessIndex,
+		SnapshotServer:     p.SnapshotServer,
+		LocalTxFirst:       p.LocalTxFirst,
+
+		TxTimestampWindow: p.TxTimestampWindow,
+
+		QuotaCPU:        p.QuotaCPU,
+		QuotaGoroutines: p.QuotaGoroutines,
+		QuotaDBIO:       p.QuotaDBIO,
+
+		HaltHeight: p.HaltHeight,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -421,8 +537,7 @@ func (n *Node) JoinChain(
 		return nil, err
 	}
 
-	gsFile := path.Join(chainDir, ChainGenesisZipFileName)
-	if err := ioutil.WriteFile(gsFile, genesis, 0644); err != nil {
+	if err := writeGenesis(gsFile); err != nil {
 		_ = os.RemoveAll(chainDir)
 		return nil, err
 	}
@@ -571,7 +686,24 @@ func (n *Node) GetBackups() ([]BackupInfo, error) {
 	}
 	infos := make([]BackupInfo, 0, len(fis))
 	for _, fi := range fis {
-		if fi.Mode().IsRegular() {
+		if fi.IsDir() {
+			dir := path.Join(backupDir, fi.Name())
+			mf, err := backup.ReadManifest(dir)
+			if err != nil {
+				continue
+			}
+			infos = append(infos, BackupInfo{
+				Name: fi.Name(),
+				Size: sizeOfDir(dir),
+				BackupInfo: chain.BackupInfo{
+					NID:     mf.NID,
+					CID:     mf.CID,
+					Channel: mf.Channel,
+					Height:  mf.Height,
+					Codec:   mf.Codec,
+				},
+			})
+		} else if fi.Mode().IsRegular() {
 			if strings.HasPrefix(fi.Name(), chain.TemporalBackupFile) {
 				continue
 			}
@@ -589,15 +721,29 @@ func (n *Node) GetBackups() ([]BackupInfo, error) {
 	return infos, nil
 }
 
+func sizeOfDir(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
+
 type RestoreView struct {
-	State     string `json:"state"`
-	Name      string `json:"name,omitempty"`
-	Overwrite bool   `json:"overwrite,omitempty"`
-	Error     string `json:"error,omitempty"`
+	State     string            `json:"state"`
+	Name      string            `json:"name,omitempty"`
+	Overwrite bool              `json:"overwrite,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Integrity *RestoreIntegrity `json:"integrity,omitempty"`
 }
 
 // StartRestore start to restore chain.
-func (n *Node) StartRestore(name string, overwrite bool) (ret error) {
+// If verify is true, then it verifies the restored chain with the peers
+// before it serves.
+func (n *Node) StartRestore(name string, overwrite, verify bool) (ret error) {
 	baseDir, backupDir := func() (string, string) {
 		n.mtx.Lock()
 		defer n.mtx.Unlock()
@@ -608,7 +754,25 @@ func (n *Node) StartRestore(name string, overwrite bool) (ret error) {
 
 	backupFile := path.Join(backupDir, name)
 
-	return n.rsm.Start(n, backupFile, baseDir, overwrite)
+	return n.rsm.Start(n, backupFile, baseDir, overwrite, verify)
+}
+
+// StartRestoreFromPeers starts to restore the latest snapshot of the chain
+// served by the peers of the seeds.
+func (n *Node) StartRestoreFromPeers(p *RestorePeersParam) error {
+	if p.CID.Value == 0 {
+		return errors.IllegalArgumentError.New("NoCID")
+	}
+	if p.SeedAddr == "" {
+		return errors.IllegalArgumentError.New("NoSeedAddress")
+	}
+	baseDir := func() string {
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+
+		return n.cfg.ResolveAbsolute(n.cfg.BaseDir)
+	}()
+	return n.rsm.StartPeers(n, p, baseDir)
 }
 
 // GetRestore returns state of latest restore operations.
@@ -624,6 +788,7 @@ func (n *Node) GetRestore() *RestoreView {
 		Name:      path.Base(status.File),
 		Overwrite: status.Overwrite,
 		Error:     errors.ToString(status.Error),
+		Integrity: status.Integrity,
 	}
 }
 
@@ -633,6 +798,33 @@ func (n *Node) StopRestore() error {
 	return n.rsm.Stop()
 }
 
+// StartGenesisUpload starts chunked upload of the genesis storage.
+// If there is an upload with the same checksum, it returns the upload
+// for resuming it.
+func (n *Node) StartGenesisUpload(size int64, checksum []byte) (*GenesisUpload, error) {
+	return n.gum.Start(size, checksum)
+}
+
+// GetGenesisUploads returns uploads of genesis storages.
+func (n *Node) GetGenesisUploads() ([]*GenesisUpload, error) {
+	return n.gum.GetAll()
+}
+
+// GetGenesisUpload returns the upload of the genesis storage.
+func (n *Node) GetGenesisUpload(id string) (*GenesisUpload, error) {
+	return n.gum.Get(id)
+}
+
+// WriteGenesisUpload writes a chunk of the genesis storage at the offset.
+func (n *Node) WriteGenesisUpload(id string, offset int64, chunk, checksum []byte) (*GenesisUpload, error) {
+	return n.gum.Write(id, offset, chunk, checksum)
+}
+
+// CancelGenesisUpload removes the upload of the genesis storage.
+func (n *Node) CancelGenesisUpload(id string) error {
+	return n.gum.Cancel(id)
+}
+
 func (n *Node) ConfigureChain(cid int, key string, value string) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -663,6 +855,17 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.AutoStart = as
 			}
+		case "quotaCPU", "quotaGoroutines", "quotaDBIO":
+			if err := configureQuota(c.cfg, key, value); err != nil {
+				return err
+			}
+			if qm, ok := c.Chain.(chain.QuotaManager); ok {
+				qm.SetQuota(c.cfg.QuotaCPU, c.cfg.QuotaGoroutines, c.cfg.QuotaDBIO)
+			}
+		case "haltHeight":
+			if err := configureHaltHeight(c, value); err != nil {
+				return err
+			}
 		default:
 			return errors.ErrInvalidState
 		}
@@ -688,6 +891,12 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.PatchTxPoolSize = intVal
 			}
+		case "priorityTxPool":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PriorityTxPoolSize = intVal
+			}
 		case "maxBlockTxBytes":
 			if intVal, err := strconv.Atoi(value); err != nil {
 				return errors.Wrapf(err, "invalid value type")
@@ -761,12 +970,74 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.NephewsLimit = &intVal
 			}
+		case "walRetention":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.WALRetention = &intVal
+			}
 		case "validateTxOnSend":
 			if bc, err := strconv.ParseBool(value); err != nil {
 				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "txTimestampWindow":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.TxTimestampWindow = intVal
+			}
+		case "lightServer":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.LightServer = bc
+			}
+		case "scoreIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.SCOREIndex = bc
+			}
+		case "eventIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.EventIndex = bc
+			}
+		case "addressIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.AddressIndex = bc
+			}
+		case "accessIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.AccessIndex = bc
+			}
+		case "snapshotServer":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.SnapshotServer = bc
+			}
+		case "localTxFirst":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.LocalTxFirst = bc
+			}
+		case "quotaCPU", "quotaGoroutines", "quotaDBIO":
+			if err := configureQuota(c.cfg, key, value); err != nil {
+				return err
+			}
+		case "haltHeight":
+			if err := configureHaltHeight(c, value); err != nil {
+				return err
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -790,6 +1061,37 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 	}
 }
 
+func configureQuota(cfg *chain.Config, key string, value string) error {
+	intVal, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid value type")
+	}
+	if intVal < 0 {
+		return errors.IllegalArgumentError.Errorf("NegativeQuota(%s=%d)", key, intVal)
+	}
+	switch key {
+	case "quotaCPU":
+		cfg.QuotaCPU = int(intVal)
+	case "quotaGoroutines":
+		cfg.QuotaGoroutines = int(intVal)
+	case "quotaDBIO":
+		cfg.QuotaDBIO = intVal
+	}
+	return nil
+}
+
+func configureHaltHeight(c *Chain, value string) error {
+	height, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid value type")
+	}
+	hs, ok := c.Chain.(chain.HaltScheduler)
+	if !ok {
+		return errors.UnsupportedError.New("HaltNotSupported")
+	}
+	return hs.SetHaltHeight(height)
+}
+
 func (n *Node) RunChainTask(cid int, task string, params json.RawMessage) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -801,6 +1103,310 @@ func (n *Node) RunChainTask(cid int, task string, params json.RawMessage) error
 	return c.RunTask(task, params)
 }
 
+func (n *Node) webhookManagerOf(cid int) (chain.WebhookManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	wm, ok := c.Chain.(chain.WebhookManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("WebhookNotSupported")
+	}
+	return wm, nil
+}
+
+func (n *Node) GetChainWebhooks(cid int) ([]*exporter.Config, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	wm, err := n.webhookManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return wm.Webhooks()
+}
+
+func (n *Node) AddChainWebhook(cid int, name string, cfg json.RawMessage) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	wm, err := n.webhookManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	return wm.AddWebhook(name, cfg)
+}
+
+func (n *Node) RemoveChainWebhook(cid int, name string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	wm, err := n.webhookManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	return wm.RemoveWebhook(name)
+}
+
+func (n *Node) endpointManagerOf(cid int) (chain.EndpointManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	em, ok := c.Chain.(chain.EndpointManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("EndpointWatcherNotSupported")
+	}
+	return em, nil
+}
+
+func (n *Node) GetChainEndpoint(cid int) (*endpoint.Status, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	em, err := n.endpointManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return em.EndpointStatus()
+}
+
+func (n *Node) ConfirmChainEndpoint(cid int) ([]byte, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	em, err := n.endpointManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return em.ConfirmEndpointUpdate()
+}
+
+func (n *Node) profileManagerOf(cid int) (chain.ProfileManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	pm, ok := c.Chain.(chain.ProfileManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("ProfilerNotSupported")
+	}
+	return pm, nil
+}
+
+func (n *Node) GetChainProfile(cid int) (*profile.Status, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	pm, err := n.profileManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return pm.ProfileStatus(), nil
+}
+
+func (n *Node) StartChainProfile(cid int, param *profile.Param) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	pm, err := n.profileManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	return pm.StartProfile(param)
+}
+
+func (n *Node) StopChainProfile(cid int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	pm, err := n.profileManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	pm.StopProfile()
+	return nil
+}
+
+func (n *Node) onlineBackupManagerOf(cid int) (chain.OnlineBackupManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	obm, ok := c.Chain.(chain.OnlineBackupManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("OnlineBackupNotSupported")
+	}
+	return obm, nil
+}
+
+func (n *Node) GetChainSnapshot(cid int) (*backup.Status, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	obm, err := n.onlineBackupManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return obm.OnlineBackupStatus(), nil
+}
+
+// StartChainSnapshot starts an online backup of the chain to the directory.
+// Relative directory is resolved with the backup directory of the node.
+func (n *Node) StartChainSnapshot(cid int, dir string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	obm, err := n.onlineBackupManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		return errors.IllegalArgumentError.New("EmptyDirectory")
+	}
+	if !path.IsAbs(dir) {
+		dir = path.Join(n.cfg.ResolveAbsolute(n.cfg.BackupDir), dir)
+	}
+	return obm.StartOnlineBackup(dir, []string{ChainGenesisZipFileName, ChainConfigFileName})
+}
+
+func (n *Node) StopChainSnapshot(cid int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	obm, err := n.onlineBackupManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	obm.StopOnlineBackup()
+	return nil
+}
+
+// CheckChainWithPeers compares the last block of the chain with the
+// blocks of the peers.
+func (n *Node) CheckChainWithPeers(cid int, timeout time.Duration) (*chain.PeerCheck, error) {
+	c, err := func() (*Chain, error) {
+		defer n.mtx.RUnlock()
+		n.mtx.RLock()
+
+		return n._get(cid)
+	}()
+	if err != nil {
+		return nil, err
+	}
+	pc, ok := c.Chain.(chain.PeerChecker)
+	if !ok {
+		return nil, errors.UnsupportedError.New("PeerCheckNotSupported")
+	}
+	return pc.CheckLastBlockWithPeers(timeout)
+}
+
+func (n *Node) recoveryManagerOf(cid int) (chain.RecoveryManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	rm, ok := c.Chain.(chain.RecoveryManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("RecoveryNotSupported")
+	}
+	return rm, nil
+}
+
+// GetChainRecovery returns the recent faults of the chain with the actions
+// taken by the recovery policy.
+func (n *Node) GetChainRecovery(cid int) ([]*recovery.Event, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	rm, err := n.recoveryManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return rm.RecoveryEvents()
+}
+
+func (n *Node) rewindManagerOf(cid int) (chain.RewindManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	rm, ok := c.Chain.(chain.RewindManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("RewindNotSupported")
+	}
+	return rm, nil
+}
+
+// RewindChain rewinds the chain to the height. Without confirm, it returns
+// the plan only. Otherwise, confirm should be the hash of the block at the
+// height in the plan.
+func (n *Node) RewindChain(cid int, height int64, confirm []byte) (*chain.RewindPlan, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	rm, err := n.rewindManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := rm.PlanRewind(height)
+	if err != nil {
+		return nil, err
+	}
+	if len(confirm) == 0 {
+		return plan, nil
+	}
+	if err := rm.Rewind(height, confirm); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (n *Node) componentManagerOf(cid int) (chain.ComponentManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := c.Chain.(chain.ComponentManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("ComponentNotSupported")
+	}
+	return cm, nil
+}
+
+func (n *Node) GetChainComponents(cid int) ([]*chain.Component, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	cm, err := n.componentManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return cm.Components(), nil
+}
+
+func (n *Node) PauseChainComponent(cid int, name string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	cm, err := n.componentManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	return cm.PauseComponent(name)
+}
+
+func (n *Node) ResumeChainComponent(cid int, name string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	cm, err := n.componentManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	return cm.ResumeComponent(name)
+}
+
 func (n *Node) GetChains() []*Chain {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -879,6 +1485,40 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCRosetta = boolVal
 		}
 		n.srv.SetRosetta(n.rcfg.RPCRosetta)
+	case "rpcAPIKeyRequired":
+		if boolVal, err := strconv.ParseBool(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCAPIKeyRequired = boolVal
+		}
+		n.srv.SetAPIKeyRequired(n.rcfg.RPCAPIKeyRequired)
+	case "rpcRateLimitPerIP":
+		if floatVal, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if floatVal < 0 {
+			return errors.Errorf("invalid value %v", floatVal)
+		} else {
+			n.rcfg.RPCRateLimitPerIP = floatVal
+		}
+		n.srv.SetRateLimit(n.rcfg.rateLimit())
+	case "rpcRateLimitPerKey":
+		if floatVal, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if floatVal < 0 {
+			return errors.Errorf("invalid value %v", floatVal)
+		} else {
+			n.rcfg.RPCRateLimitPerKey = floatVal
+		}
+		n.srv.SetRateLimit(n.rcfg.rateLimit())
+	case "rpcRateLimitExpensive":
+		if floatVal, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if floatVal < 0 {
+			return errors.Errorf("invalid value %v", floatVal)
+		} else {
+			n.rcfg.RPCRateLimitExpensive = floatVal
+		}
+		n.srv.SetRateLimit(n.rcfg.rateLimit())
 	case "rpcBatchLimit":
 		if intVal, err := strconv.Atoi(value); err != nil {
 			return errors.Wrapf(err, "invalid value type")
@@ -886,6 +1526,58 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCBatchLimit = intVal
 		}
 		n.srv.SetBatchLimit(n.rcfg.RPCBatchLimit)
+	case "rpcCallStepLimit":
+		if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if intVal < 0 {
+			return errors.Errorf("invalid value %d", intVal)
+		} else {
+			n.rcfg.RPCCallStepLimit = intVal
+		}
+		n.srv.SetCallStepLimit(n.rcfg.RPCCallStepLimit)
+	case "rpcTraceMaxEntries", "rpcTraceMaxDepth", "rpcTraceMaxStringLength", "rpcTraceMaxTimeout":
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if intVal < 0 {
+			return errors.Errorf("invalid value %d", intVal)
+		}
+		switch key {
+		case "rpcTraceMaxEntries":
+			n.rcfg.RPCTraceMaxEntries = intVal
+		case "rpcTraceMaxDepth":
+			n.rcfg.RPCTraceMaxDepth = intVal
+		case "rpcTraceMaxTimeout":
+			n.rcfg.RPCTraceMaxTimeout = intVal
+		default:
+			n.rcfg.RPCTraceMaxStringLength = intVal
+		}
+		n.srv.SetTraceLimit(n.rcfg.traceLimit())
+	case "rpcTraceRedactValues":
+		if boolVal, err := strconv.ParseBool(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCTraceRedactValues = boolVal
+		}
+		n.srv.SetTraceLimit(n.rcfg.traceLimit())
+	case "rpcStrict":
+		var endpoints []string
+		if value != "" {
+			endpoints = strings.Split(value, ",")
+		}
+		if err := n.srv.SetStrict(endpoints); err != nil {
+			return err
+		}
+		n.rcfg.RPCStrict = value
+	case "rpcAuth":
+		var endpoints []string
+		if value != "" {
+			endpoints = strings.Split(value, ",")
+		}
+		if err := n.srv.SetAuthEndpoints(endpoints); err != nil {
+			return err
+		}
+		n.rcfg.RPCAuth = value
 	case "wsMaxSession":
 		if intVal, err := strconv.Atoi(value); err != nil {
 			return errors.Wrapf(err, "invalid value type")
@@ -893,6 +1585,27 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.WSMaxSession = intVal
 		}
 		n.srv.SetWSMaxSession(n.rcfg.WSMaxSession)
+	case "wsMaxSessionPerIP":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.WSMaxSessionPerIP = intVal
+		}
+		n.srv.SetWSMaxSessionPerIP(n.rcfg.WSMaxSessionPerIP)
+	case "wsMaxSubscription":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.WSMaxSubscription = intVal
+		}
+		n.srv.SetWSMaxSubscription(n.rcfg.WSMaxSubscription)
+	case "wsMaxBuffer":
+		if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.WSMaxBuffer = intVal
+		}
+		n.srv.SetWSMaxBuffer(n.rcfg.WSMaxBuffer)
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -902,6 +1615,40 @@ func (n *Node) Configure(key string, value string) error {
 	return nil
 }
 
+func setPacketPolicies(ps network.PacketPolicySetter, ttls, hops map[string]int) error {
+	policies := make(map[string]*network.PacketPolicy)
+	policyOf := func(name string) *network.PacketPolicy {
+		pp, ok := policies[name]
+		if !ok {
+			pp = &network.PacketPolicy{}
+			policies[name] = pp
+		}
+		return pp
+	}
+	for name, v := range ttls {
+		if v < 0 || v > 0xFF {
+			return errors.Errorf("invalid ttl %d for %s", v, name)
+		}
+		policyOf(name).TTL = byte(v)
+	}
+	for name, v := range hops {
+		if v < 0 || v > 0xFF {
+			return errors.Errorf("invalid hops %d for %s", v, name)
+		}
+		policyOf(name).Hops = byte(v)
+	}
+	for name, pp := range policies {
+		pi, ok := network.ProtocolByName(name)
+		if !ok {
+			return errors.Errorf("unknown protocol %s", name)
+		}
+		if err := ps.SetPacketPolicy(pi, *pp); err != nil {
+			return errors.Wrapf(err, "invalid policy for %s", name)
+		}
+	}
+	return nil
+}
+
 func NewNode(
 	w module.Wallet,
 	cfg *StaticConfig,
@@ -924,6 +1671,56 @@ func NewNode(
 	if cfg.P2PListenAddr != "" {
 		_ = nt.SetListenAddress(cfg.P2PListenAddr)
 	}
+	if cfg.P2PAuditLog != "" {
+		if ha, ok := nt.(network.HandshakeAuditor); ok {
+			writer, err := log.NewWriter(&log.WriterConfig{
+				Filename: cfg.ResolveAbsolute(cfg.P2PAuditLog),
+				MaxSize:  DefaultP2PAuditLogMaxSize,
+			})
+			if err != nil {
+				log.Panicf("fail to make P2P audit log writer err=%+v", err)
+			}
+			ha.SetAuditWriter(writer)
+		}
+	}
+	if cfg.P2PMultiplexing {
+		if cm, ok := nt.(network.ChannelMultiplexer); ok {
+			cm.SetMultiplexing(true)
+		}
+	}
+	if len(cfg.P2PPacketTTL) > 0 || len(cfg.P2PPacketHops) > 0 {
+		if ps, ok := nt.(network.PacketPolicySetter); ok {
+			if err := setPacketPolicies(ps, cfg.P2PPacketTTL, cfg.P2PPacketHops); err != nil {
+				log.Panicf("fail to set P2P packet policies err=%+v", err)
+			}
+		}
+	}
+	if cfg.P2PQueryPuzzle != 0 {
+		if qp, ok := nt.(network.QueryPuzzleSetter); ok {
+			if err := qp.SetQueryPuzzle(cfg.P2PQueryPuzzle); err != nil {
+				log.Panicf("fail to set P2P query puzzle err=%+v", err)
+			}
+		}
+	}
+	if cfg.P2PSubnetLimit != 0 || cfg.P2PASNLimit != 0 {
+		if pd, ok := nt.(network.PeerDiversitySetter); ok {
+			var asnMap string
+			if cfg.P2PASNMap != "" {
+				asnMap = cfg.ResolveAbsolute(cfg.P2PASNMap)
+			}
+			if err := pd.SetPeerDiversity(cfg.P2PSubnetLimit, cfg.P2PASNLimit, asnMap); err != nil {
+				log.Panicf("fail to set P2P peer diversity err=%+v", err)
+			}
+		}
+	}
+	var rpcSocket string
+	if cfg.RPCSocket != "" {
+		rpcSocket = cfg.ResolveAbsolute(cfg.RPCSocket)
+	}
+	rpcSocketMode, err := server.ParseUnixSocketMode(cfg.RPCSocketMode)
+	if err != nil {
+		log.Panicf("invalid RPC socket mode err=%+v", err)
+	}
 	config := &server.Config{
 		ServerAddress:         cfg.RPCAddr,
 		JSONRPCDump:           cfg.RPCDump,
@@ -931,11 +1728,33 @@ func NewNode(
 		JSONRPCRosetta:        rcfg.RPCRosetta,
 		JSONRPCDefaultChannel: rcfg.RPCDefaultChannel,
 		JSONRPCBatchLimit:     rcfg.RPCBatchLimit,
+		JSONRPCCallStepLimit:  rcfg.RPCCallStepLimit,
+		JSONRPCTraceLimit:     rcfg.traceLimit(),
+		JSONRPCStrict:         rcfg.strictEndpoints(),
+		APIKeyFile:            path.Join(cfg.AbsBaseDir(), APIKeyFileName),
+		APIKeyRequired:        rcfg.RPCAPIKeyRequired,
+		JSONRPCRateLimit:      rcfg.rateLimit(),
+		AuthFile:              path.Join(cfg.AbsBaseDir(), AuthFileName),
+		AuthEndpoints:         rcfg.authEndpoints(),
+		PluginFile:            path.Join(cfg.AbsBaseDir(), PluginFileName),
 		WSMaxSession:          rcfg.WSMaxSession,
+		WSMaxSessionPerIP:     rcfg.WSMaxSessionPerIP,
+		WSMaxSubscription:     rcfg.WSMaxSubscription,
+		WSMaxBuffer:           rcfg.WSMaxBuffer,
+		TrustedProxies:        cfg.RPCTrustedProxies,
+		ProxyProtocol:         cfg.RPCProxyProtocol,
+		UnixSocket:            rpcSocket,
+		UnixSocketMode:        rpcSocketMode,
+		MetricPush:            cfg.MetricPush,
+		GRPCAddress:           cfg.GRPCAddr,
 	}
 	srv := server.NewManager(config, w, l)
 
-	ee, err := eeproxy.AllocEngines(l, strings.Split(cfg.Engines, ",")...)
+	if err := contract.ConfigureDeployCheckers(cfg.DeployChecks); err != nil {
+		log.Panicf("fail to configure deploy checkers err=%+v", err)
+	}
+
+	ee, err := eeproxy.AllocEngines(l, cfg.AbsEELimits(), strings.Split(cfg.Engines, ",")...)
 	if err != nil {
 		log.Panicf("fail to create engines err=%+v", err)
 	}
@@ -968,6 +1787,29 @@ func NewNode(
 		chains:   make(map[string]*Chain),
 		channels: make(map[int]string),
 		cliSrv:   cliSrv,
+		recorder: log.AddRecorder(l, log.WarnLevel, BundleRecentErrors),
+		tap:      log.AddTap(l),
+		gum: GenesisUploadManager{
+			dir: path.Join(nodeDir, GenesisUploadDirectory),
+		},
+	}
+
+	if !cfg.Alert.IsEmpty() {
+		n.alert, err = alert.NewEngine(cfg.Alert, &alertSource{n: n}, l.WithFields(log.Fields{
+			log.FieldKeyModule: "AL",
+		}))
+		if err != nil {
+			log.Panicf("fail to create alert engine err=%+v", err)
+		}
+	}
+
+	if !cfg.Registry.IsEmpty() {
+		n.registry, err = newRegistryManager(n, cfg.Registry, l.WithFields(log.Fields{
+			log.FieldKeyModule: "RG",
+		}))
+		if err != nil {
+			log.Panicf("fail to create registry manager err=%+v", err)
+		}
 	}
 
 	// Load chains
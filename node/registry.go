@@ -0,0 +1,380 @@
+package node
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server"
+)
+
+const (
+	DefaultRegistryInterval = 60
+	RegistryFetchTimeout    = 5 * time.Minute
+)
+
+// RegistryConfig is the configuration of the chain registry. The registry
+// lists the chains to be joined by the nodes of a fleet, so that adding a
+// chain to the fleet doesn't need changes of the configuration of nodes.
+type RegistryConfig struct {
+	// Source is the path or the URL of the registry.
+	Source   string `json:"source"`
+	Interval int    `json:"interval,omitempty"` // in second
+	// AutoJoin makes the node join the chains in the registry. Otherwise,
+	// the chains are only reported.
+	AutoJoin bool `json:"auto_join,omitempty"`
+}
+
+func (c *RegistryConfig) IsEmpty() bool {
+	return c == nil || c.Source == ""
+}
+
+func (c *RegistryConfig) Validate() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	if c.Interval < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidInterval(%d)", c.Interval)
+	}
+	if isURL(c.Source) {
+		if _, err := url.Parse(c.Source); err != nil {
+			return errors.IllegalArgumentError.Errorf("InvalidSource(%s)", c.Source)
+		}
+	}
+	return nil
+}
+
+func (c *RegistryConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultRegistryInterval * time.Second
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// RegistryChain is a chain in the registry. Parameters for joining the
+// chain are same as the ones of the admin API, and Channel is required.
+type RegistryChain struct {
+	ChainConfig
+
+	// Genesis is the path or the URL of the genesis storage.
+	Genesis string `json:"genesis,omitempty"`
+	// Peers are URLs of the RPC servers of nodes in the chain. The genesis
+	// storage is fetched with the admin API of them, if Genesis is empty or
+	// not available.
+	Peers []string `json:"peers,omitempty"`
+	// Checksum is SHA3-256 of the genesis storage.
+	Checksum common.HexBytes `json:"checksum,omitempty"`
+}
+
+type Registry struct {
+	Chains []*RegistryChain `json:"chains"`
+}
+
+type RegistryChainView struct {
+	Channel string `json:"channel"`
+	Joined  bool   `json:"joined"`
+	Error   string `json:"error,omitempty"`
+}
+
+type RegistryView struct {
+	Source   string               `json:"source"`
+	AutoJoin bool                 `json:"autoJoin"`
+	Updated  time.Time            `json:"updated"`
+	Error    string               `json:"error,omitempty"`
+	Chains   []*RegistryChainView `json:"chains"`
+}
+
+// registryManager reads the registry periodically, and joins the chains
+// not joined yet if AutoJoin is enabled.
+type registryManager struct {
+	n      *Node
+	cfg    RegistryConfig
+	client *http.Client
+	logger log.Logger
+
+	lock    sync.Mutex
+	view    RegistryView
+	trigger chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newRegistryManager(n *Node, cfg *RegistryConfig, l log.Logger) (*registryManager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &registryManager{
+		n:      n,
+		cfg:    *cfg,
+		client: &http.Client{Timeout: RegistryFetchTimeout},
+		logger: l,
+		view: RegistryView{
+			Source:   cfg.Source,
+			AutoJoin: cfg.AutoJoin,
+			Chains:   []*RegistryChainView{},
+		},
+		trigger: make(chan struct{}, 1),
+	}, nil
+}
+
+func (m *registryManager) Start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(m.stop, m.done)
+}
+
+func (m *registryManager) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.cfg.interval())
+	defer ticker.Stop()
+	for {
+		m.Sync()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-m.trigger:
+		}
+	}
+}
+
+func (m *registryManager) Stop() {
+	m.lock.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Trigger makes the manager read the registry without waiting for the
+// interval.
+func (m *registryManager) Trigger() {
+	select {
+	case m.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (m *registryManager) View() *RegistryView {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	v := m.view
+	return &v
+}
+
+// Sync reads the registry, and joins the chains not joined yet.
+func (m *registryManager) Sync() {
+	view := RegistryView{
+		Source:   m.cfg.Source,
+		AutoJoin: m.cfg.AutoJoin,
+		Updated:  time.Now(),
+		Chains:   []*RegistryChainView{},
+	}
+	defer func() {
+		m.lock.Lock()
+		m.view = view
+		m.lock.Unlock()
+	}()
+
+	reg, err := m.read()
+	if err != nil {
+		m.logger.Warnf("fail to read registry source=%s err=%+v", m.cfg.Source, err)
+		view.Error = err.Error()
+		return
+	}
+	for _, rc := range reg.Chains {
+		if rc == nil {
+			continue
+		}
+		cv := &RegistryChainView{Channel: rc.Channel}
+		view.Chains = append(view.Chains, cv)
+		if rc.Channel == "" {
+			cv.Error = "no channel"
+			continue
+		}
+		if m.n.GetChainByChannel(rc.Channel) != nil {
+			cv.Joined = true
+			continue
+		}
+		if !m.cfg.AutoJoin {
+			continue
+		}
+		if err := m.join(rc); err != nil {
+			m.logger.Warnf("fail to join chain channel=%s err=%+v", rc.Channel, err)
+			cv.Error = err.Error()
+			continue
+		}
+		cv.Joined = true
+	}
+}
+
+func (m *registryManager) read() (*Registry, error) {
+	bs, err := m.fetch(m.cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+	reg := new(Registry)
+	if err := json.Unmarshal(bs, reg); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidRegistry")
+	}
+	return reg, nil
+}
+
+func (m *registryManager) join(rc *RegistryChain) error {
+	genesis, err := m.fetchGenesis(rc)
+	if err != nil {
+		return err
+	}
+	p := rc.ChainConfig
+	c, err := m.n.JoinChain(&p, genesis)
+	if err != nil {
+		return err
+	}
+	m.logger.Infof("joined chain channel=%s cid=%#x", rc.Channel, c.CID())
+	if p.AutoStart {
+		return m.n.StartChain(c.CID())
+	}
+	return nil
+}
+
+// fetchGenesis returns the genesis storage from Genesis, or peers of the
+// chain in order. It's verified with Checksum if it's specified.
+func (m *registryManager) fetchGenesis(rc *RegistryChain) ([]byte, error) {
+	var errs []string
+	verify := func(bs []byte) bool {
+		if len(rc.Checksum) == 0 {
+			return true
+		}
+		sum := sha3.Sum256(bs)
+		return bytes.Equal(sum[:], rc.Checksum)
+	}
+	if rc.Genesis != "" {
+		bs, err := m.fetch(rc.Genesis)
+		if err == nil && !verify(bs) {
+			err = errors.InvalidStateError.New("ChecksumMismatch")
+		}
+		if err == nil {
+			return bs, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", rc.Genesis, err))
+	}
+	for _, peer := range rc.Peers {
+		bs, err := m.fetchFromPeer(peer, rc.Channel)
+		if err == nil && !verify(bs) {
+			err = errors.InvalidStateError.New("ChecksumMismatch")
+		}
+		if err == nil {
+			return bs, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", peer, err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.NotFoundError.New("NoGenesisSource")
+	}
+	return nil, errors.NotFoundError.Errorf("NoGenesis(%s)", strings.Join(errs, ", "))
+}
+
+func (m *registryManager) fetch(source string) ([]byte, error) {
+	if !isURL(source) {
+		return ioutil.ReadFile(m.n.cfg.ResolveAbsolute(source))
+	}
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.do(req)
+}
+
+// fetchFromPeer gets the genesis storage of the chain from the admin API of
+// the peer. The request is signed with the wallet of the node, so the
+// address of the node should be a user of the admin API of the peer.
+func (m *registryManager) fetchFromPeer(peer, channel string) ([]byte, error) {
+	p := UrlChain + "/" + url.PathEscape(channel) + "/genesis"
+	req, err := http.NewRequest(http.MethodGet,
+		strings.TrimRight(peer, "/")+server.UrlAdmin+p, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := signAdminRequest(m.n.w, req, p); err != nil {
+		return nil, err
+	}
+	return m.do(req)
+}
+
+func (m *registryManager) do(req *http.Request) ([]byte, error) {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
+		return nil, errors.Errorf("status=%d message=%s",
+			resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// signAdminRequest signs the request for the admin API in the way Auth
+// validates, where p is the path of the request without server.UrlAdmin.
+func signAdminRequest(w module.Wallet, req *http.Request, p string) error {
+	ts := strconv.FormatInt(time.Now().UnixMicro(), 10)
+	serialized := fmt.Sprintf("Method=%s,Url=%s,Timestamp=%s", req.Method, p, ts)
+	sig, err := w.Sign(crypto.SHA3Sum256([]byte(serialized)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("%s Timestamp=%s,Signature=%s",
+		AuthScheme, ts, hex.EncodeToString(sig)))
+	return nil
+}
+
+// GetRegistry returns the status of the chains in the registry.
+func (n *Node) GetRegistry() (*RegistryView, error) {
+	if n.registry == nil {
+		return nil, errors.NotFoundError.New("NoRegistry")
+	}
+	return n.registry.View(), nil
+}
+
+// SyncRegistry makes the node read the registry now.
+func (n *Node) SyncRegistry() error {
+	if n.registry == nil {
+		return errors.NotFoundError.New("NoRegistry")
+	}
+	n.registry.Trigger()
+	return nil
+}
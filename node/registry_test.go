@@ -0,0 +1,125 @@
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/server"
+)
+
+func TestRegistryConfig_Validate(t *testing.T) {
+	var cfg *RegistryConfig
+	assert.True(t, cfg.IsEmpty())
+	assert.NoError(t, cfg.Validate())
+
+	cfg = &RegistryConfig{Source: "registry.json"}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, DefaultRegistryInterval, int(cfg.interval().Seconds()))
+
+	cfg = &RegistryConfig{Source: "https://fleet.example.com/registry.json", Interval: 10}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 10, int(cfg.interval().Seconds()))
+
+	cfg = &RegistryConfig{Source: "registry.json", Interval: -1}
+	assert.True(t, errors.IllegalArgumentError.Equals(cfg.Validate()))
+	cfg = &RegistryConfig{Source: "http://fleet example.com/"}
+	assert.True(t, errors.IllegalArgumentError.Equals(cfg.Validate()))
+}
+
+func newTestRegistryManager(t *testing.T, cfg *RegistryConfig) *registryManager {
+	n := &Node{
+		w:      wallet.New(),
+		chains: make(map[string]*Chain),
+	}
+	m, err := newRegistryManager(n, cfg, log.New())
+	assert.NoError(t, err)
+	return m
+}
+
+func TestRegistryManager_FetchGenesis(t *testing.T) {
+	genesis := []byte("genesis storage")
+	sum := sha3.Sum256(genesis)
+
+	m := newTestRegistryManager(t, &RegistryConfig{Source: "registry.json"})
+
+	// peers serve the genesis to the users of their admin API
+	auth := NewAuth("", server.UrlAdmin)
+	assert.NoError(t, auth.AddUser(m.n.w.Address().String()))
+	e := echo.New()
+	r := e.GET(server.UrlAdmin+UrlChain+"/:chain/genesis", func(ctx echo.Context) error {
+		assert.Equal(t, "icon_dex", ctx.Param("chain"))
+		return ctx.Blob(http.StatusOK, echo.MIMEOctetStream, genesis)
+	}, auth.MiddlewareFunc())
+	auth.SetSkip(r, false)
+	peer := httptest.NewServer(e)
+	defer peer.Close()
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("other genesis"))
+	}))
+	defer source.Close()
+
+	rc := &RegistryChain{
+		Genesis: source.URL + "/genesis.zip",
+		Peers:   []string{peer.URL + "/"},
+	}
+	rc.Channel = "icon_dex"
+	bs, err := m.fetchGenesis(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other genesis"), bs)
+
+	// it falls back to the peers on checksum mismatch
+	rc.Checksum = sum[:]
+	bs, err = m.fetchGenesis(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, genesis, bs)
+
+	// requests from unknown nodes are rejected by the peers
+	m.n.w = wallet.New()
+	_, err = m.fetchGenesis(rc)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	_, err = m.fetchGenesis(&RegistryChain{})
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
+
+func TestRegistryManager_Sync(t *testing.T) {
+	file := path.Join(t.TempDir(), "registry.json")
+	m := newTestRegistryManager(t, &RegistryConfig{Source: file})
+
+	m.Sync()
+	v := m.View()
+	assert.NotEmpty(t, v.Error)
+	assert.Empty(t, v.Chains)
+
+	reg := &Registry{Chains: []*RegistryChain{{}, {}, {}}}
+	reg.Chains[0].Channel = "joined"
+	reg.Chains[1].Channel = "new"
+	bs, err := json.Marshal(reg)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(file, bs, 0644))
+	m.n.chains["joined"] = &Chain{}
+
+	// chains are only reported without AutoJoin
+	m.Sync()
+	v = m.View()
+	assert.Empty(t, v.Error)
+	assert.Equal(t, file, v.Source)
+	assert.False(t, v.AutoJoin)
+	assert.Equal(t, []*RegistryChainView{
+		{Channel: "joined", Joined: true},
+		{Channel: "new"},
+		{Error: "no channel"},
+	}, v.Chains)
+}
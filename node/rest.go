@@ -17,9 +17,12 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/chain/exporter"
+	"github.com/icon-project/goloop/chain/profile"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/lcimporter"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -37,6 +40,16 @@ const (
 	ParamID     = "id"
 	UrlUserRes  = "/:" + ParamID
 	TaskID      = "task"
+	ParamHook   = "hook"
+	ParamName   = "name"
+
+	ParamComponent = "component"
+
+	UrlGenesisUpload    = "/upload"
+	ParamUploadID       = "upload"
+	UrlGenesisUploadRes = "/:" + ParamUploadID
+
+	UrlGenesisFetch = "/fetch"
 
 	UrlDB    = "/db"
 	ParamBK  = "bucket"
@@ -49,8 +62,9 @@ type Rest struct {
 }
 
 type SystemView struct {
-	BuildVersion string `json:"buildVersion"`
-	BuildTags    string `json:"buildTags"`
+	BuildVersion string   `json:"buildVersion"`
+	BuildTags    string   `json:"buildTags"`
+	Features     []string `json:"features"`
 	Setting      struct {
 		Address       string `json:"address"`
 		P2PAddr       string `json:"p2p"`
@@ -73,6 +87,7 @@ type ChainView struct {
 	State     string          `json:"state"`
 	Height    int64           `json:"height"`
 	LastError string          `json:"lastError"`
+	Partition string          `json:"partition,omitempty"`
 }
 
 type ChainInspectView struct {
@@ -84,25 +99,42 @@ type ChainInspectView struct {
 }
 
 type ChainConfig struct {
-	DBType           string `json:"dbType"`
-	Platform         string `json:"platform"`
-	SeedAddr         string `json:"seedAddress"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrencyLevel,omitempty"`
-	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
-	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
-	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
-	NodeCache        string `json:"nodeCache,omitempty"`
-	Channel          string `json:"channel"`
-	SecureSuites     string `json:"secureSuites"`
-	SecureAeads      string `json:"secureAeads"`
-	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
-	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
-	TxTimeout        int64  `json:"txTimeout"`
-	AutoStart        bool   `json:"autoStart"`
-	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
-	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
-	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	DBType             string `json:"dbType"`
+	Platform           string `json:"platform"`
+	SeedAddr           string `json:"seedAddress"`
+	Role               uint   `json:"role"`
+	ConcurrencyLevel   int    `json:"concurrencyLevel,omitempty"`
+	NormalTxPoolSize   int    `json:"normalTxPool,omitempty"`
+	PatchTxPoolSize    int    `json:"patchTxPool,omitempty"`
+	PriorityTxPoolSize int    `json:"priorityTxPool,omitempty"`
+	MaxBlockTxBytes    int    `json:"maxBlockTxBytes,omitempty"`
+	NodeCache          string `json:"nodeCache,omitempty"`
+	Channel            string `json:"channel"`
+	SecureSuites       string `json:"secureSuites"`
+	SecureAeads        string `json:"secureAeads"`
+	DefWaitTimeout     int64  `json:"defaultWaitTimeout"`
+	MaxWaitTimeout     int64  `json:"maxWaitTimeout"`
+	TxTimeout          int64  `json:"txTimeout"`
+	AutoStart          bool   `json:"autoStart"`
+	ChildrenLimit      *int   `json:"childrenLimit,omitempty"`
+	NephewsLimit       *int   `json:"nephewsLimit,omitempty"`
+	WALRetention       *int   `json:"walRetention,omitempty"`
+	ValidateTxOnSend   bool   `json:"validateTxOnSend,omitempty"`
+	LightServer        bool   `json:"lightServer,omitempty"`
+	SCOREIndex         bool   `json:"scoreIndex,omitempty"`
+	EventIndex         bool   `json:"eventIndex,omitempty"`
+	AddressIndex       bool   `json:"addressIndex,omitempty"`
+	AccessIndex        bool   `json:"accessIndex,omitempty"`
+	SnapshotServer     bool   `json:"snapshotServer,omitempty"`
+	LocalTxFirst       bool   `json:"localTxFirst,omitempty"`
+
+	TxTimestampWindow int64 `json:"txTimestampWindow,omitempty"`
+
+	QuotaCPU        int   `json:"quotaCPU,omitempty"`
+	QuotaGoroutines int   `json:"quotaGoroutines,omitempty"`
+	QuotaDBIO       int64 `json:"quotaDBIO,omitempty"`
+
+	HaltHeight int64 `json:"haltHeight,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -110,6 +142,11 @@ type ChainResetParam struct {
 	BlockHash common.HexBytes `json:"blockHash,omitempty"`
 }
 
+type ChainRewindParam struct {
+	Height  int64           `json:"height"`
+	Confirm common.HexBytes `json:"confirm,omitempty"`
+}
+
 type ChainImportParam struct {
 	DBPath string `json:"dbPath"`
 	Height int64  `json:"height"`
@@ -124,6 +161,27 @@ type ChainBackupParam struct {
 	Manual bool `json:"manual,omitempty"`
 }
 
+type ChainSnapshotParam struct {
+	Dir string `json:"dir"`
+}
+
+type GenesisUploadParam struct {
+	Size     int64           `json:"size"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+type GenesisChunkParam struct {
+	Offset   int64           `json:"offset"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+// ChainWebhookParam is a webhook notifying activities of the addresses.
+// Secret is not shown on listing webhooks.
+type ChainWebhookParam struct {
+	Name string `json:"name"`
+	exporter.ActivityConfig
+}
+
 type ConfigureParam struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -132,6 +190,19 @@ type ConfigureParam struct {
 type RestoreBackupParam struct {
 	Name      string `json:"name"`
 	Overwrite bool   `json:"overwrite"`
+	Verify    bool   `json:"verify"`
+}
+
+// RestorePeersParam is the parameter for restoring the latest snapshot of
+// the chain from the peers of the seeds. NID is required only if the node
+// uses NID for P2P.
+type RestorePeersParam struct {
+	CID       common.HexInt32 `json:"cid"`
+	NID       int             `json:"nid,omitempty"`
+	SeedAddr  string          `json:"seedAddress"`
+	Overwrite bool            `json:"overwrite"`
+	Verify    bool            `json:"verify"`
+	Timeout   int             `json:"timeout,omitempty"` // in second
 }
 
 func NewChainView(c *Chain) *ChainView {
@@ -146,6 +217,11 @@ func NewChainView(c *Chain) *ChainView {
 	if lastErr != nil {
 		v.LastError = lastErr.Error()
 	}
+	if cs := c.Consensus(); cs != nil {
+		if status := cs.GetStatus(); status != nil {
+			v.Partition = string(status.Partition)
+		}
+	}
 	return v
 }
 
@@ -166,29 +242,66 @@ func NewChainInspectView(c *Chain) *ChainInspectView {
 
 func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
-		DBType:           cfg.DBType,
-		Platform:         cfg.Platform,
-		SeedAddr:         cfg.SeedAddr,
-		Role:             cfg.Role,
-		ConcurrencyLevel: cfg.ConcurrencyLevel,
-		NormalTxPoolSize: cfg.NormalTxPoolSize,
-		PatchTxPoolSize:  cfg.PatchTxPoolSize,
-		MaxBlockTxBytes:  cfg.MaxBlockTxBytes,
-		NodeCache:        cfg.NodeCache,
-		Channel:          cfg.Channel,
-		SecureSuites:     cfg.SecureSuites,
-		SecureAeads:      cfg.SecureAeads,
-		DefWaitTimeout:   cfg.DefWaitTimeout,
-		MaxWaitTimeout:   cfg.MaxWaitTimeout,
-		TxTimeout:        cfg.TxTimeout,
-		AutoStart:        cfg.AutoStart,
-		ChildrenLimit:    cfg.ChildrenLimit,
-		NephewsLimit:     cfg.NephewsLimit,
-		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		DBType:             cfg.DBType,
+		Platform:           cfg.Platform,
+		SeedAddr:           cfg.SeedAddr,
+		Role:               cfg.Role,
+		ConcurrencyLevel:   cfg.ConcurrencyLevel,
+		NormalTxPoolSize:   cfg.NormalTxPoolSize,
+		PatchTxPoolSize:    cfg.PatchTxPoolSize,
+		PriorityTxPoolSize: cfg.PriorityTxPoolSize,
+		MaxBlockTxBytes:    cfg.MaxBlockTxBytes,
+		NodeCache:          cfg.NodeCache,
+		Channel:            cfg.Channel,
+		SecureSuites:       cfg.SecureSuites,
+		SecureAeads:        cfg.SecureAeads,
+		DefWaitTimeout:     cfg.DefWaitTimeout,
+		MaxWaitTimeout:     cfg.MaxWaitTimeout,
+		TxTimeout:          cfg.TxTimeout,
+		AutoStart:          cfg.AutoStart,
+		ChildrenLimit:      cfg.ChildrenLimit,
+		NephewsLimit:       cfg.NephewsLimit,
+		WALRetention:       cfg.WALRetention,
+		ValidateTxOnSend:   cfg.ValidateTxOnSend,
+		LightServer:        cfg.LightServer,
+		SCOREIndex:         cfg.SCOREIndex,
+		EventIndex:         cfg.EventIndex,
+		AddressIndex:       cfg.AddressIndex,
+		AccessIndex:        cfg.AccessIndex,
+		SnapshotServer:     cfg.SnapshotServer,
+		LocalTxFirst:       cfg.LocalTxFirst,
+
+		TxTimestampWindow: cfg.TxTimestampWindow,
+
+		QuotaCPU:        cfg.QuotaCPU,
+		QuotaGoroutines: cfg.QuotaGoroutines,
+		QuotaDBIO:       cfg.QuotaDBIO,
+
+		HaltHeight: cfg.HaltHeight,
 	}
 	return v
 }
 
+func inspectCompatibility(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		c = nc.Chain
+	}
+	if ci, ok := c.(chain.CompatibilityInspector); ok {
+		return ci.InspectCompatibility()
+	}
+	return nil
+}
+
+func inspectExporters(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		c = nc.Chain
+	}
+	if ei, ok := c.(chain.ExporterInspector); ok {
+		return ei.InspectExporters()
+	}
+	return nil
+}
+
 func RegisterInspectFunc(name string, f InspectFunc) error {
 	if _, ok := inspectFuncs[name]; ok {
 		return fmt.Errorf("already exist function name:%s", name)
@@ -206,16 +319,21 @@ func RegisterRest(n *Node) {
 	ag := n.srv.AdminEchoGroup(r.a.MiddlewareFunc())
 	r.RegisterChainHandlers(ag.Group(UrlChain))
 	r.RegisterSystemHandlers(ag.Group(UrlSystem))
+	r.a.SetSkip(r.RegisterControlHandlers(ag), false)
 
 	r.RegisterChainHandlers(n.cliSrv.e.Group(UrlChain))
 	r.RegisterSystemHandlers(n.cliSrv.e.Group(UrlSystem))
 	r.RegisterUserHandlers(n.cliSrv.e.Group(UrlUser))
 	r.RegisterStatsHandlers(n.cliSrv.e.Group(UrlStats))
 	r.RegisterDBHandlers(n.cliSrv.e.Group(UrlDB))
+	r.RegisterControlHandlers(n.cliSrv.e.Group(""))
 
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
 	_ = RegisterInspectFunc("service", service.Inspect)
+	_ = RegisterInspectFunc("lcimporter", lcimporter.Inspect)
+	_ = RegisterInspectFunc("compatibility", inspectCompatibility)
+	_ = RegisterInspectFunc("exporters", inspectExporters)
 
 	// json rpc
 	n.srv.RegisterAPIHandler(n.cliSrv.e.Group("/api"))
@@ -227,12 +345,20 @@ func RegisterRest(n *Node) {
 func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	g.GET("", r.GetChains)
 	g.POST("", r.JoinChain)
+	g.GET(UrlGenesisUpload, r.GetGenesisUploads)
+	g.POST(UrlGenesisUpload, r.StartGenesisUpload)
+	g.GET(UrlGenesisUpload+UrlGenesisUploadRes, r.GetGenesisUpload)
+	g.POST(UrlGenesisUpload+UrlGenesisUploadRes, r.WriteGenesisUpload)
+	g.DELETE(UrlGenesisUpload+UrlGenesisUploadRes, r.CancelGenesisUpload)
+	g.POST(UrlGenesisUpload+UrlGenesisUploadRes+"/join", r.JoinChainWithUpload)
+	g.POST(UrlGenesisFetch, r.JoinChainWithFetch)
 
 	g.GET(UrlChainRes, r.GetChain, r.ChainInjector)
 	g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector)
+	g.POST(UrlChainRes+"/rewind", r.RewindChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
@@ -243,6 +369,21 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	}
 	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
 	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/webhook", r.GetChainWebhooks, r.ChainInjector)
+	g.POST(UrlChainRes+"/webhook", r.AddChainWebhook, r.ChainInjector)
+	g.DELETE(UrlChainRes+"/webhook/:"+ParamHook, r.RemoveChainWebhook, r.ChainInjector)
+	g.GET(UrlChainRes+"/endpoint", r.GetChainEndpoint, r.ChainInjector)
+	g.POST(UrlChainRes+"/endpoint/confirm", r.ConfirmChainEndpoint, r.ChainInjector)
+	g.GET(UrlChainRes+"/profile", r.GetChainProfile, r.ChainInjector)
+	g.POST(UrlChainRes+"/profile/start", r.StartChainProfile, r.ChainInjector)
+	g.POST(UrlChainRes+"/profile/stop", r.StopChainProfile, r.ChainInjector)
+	g.GET(UrlChainRes+"/snapshot", r.GetChainSnapshot, r.ChainInjector)
+	g.POST(UrlChainRes+"/snapshot/start", r.StartChainSnapshot, r.ChainInjector)
+	g.POST(UrlChainRes+"/snapshot/stop", r.StopChainSnapshot, r.ChainInjector)
+	g.GET(UrlChainRes+"/recovery", r.GetChainRecovery, r.ChainInjector)
+	g.GET(UrlChainRes+"/component", r.GetChainComponents, r.ChainInjector)
+	g.POST(UrlChainRes+"/component/:"+ParamComponent+"/pause", r.PauseChainComponent, r.ChainInjector)
+	g.POST(UrlChainRes+"/component/:"+ParamComponent+"/resume", r.ResumeChainComponent, r.ChainInjector)
 	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
 }
 
@@ -319,26 +460,147 @@ func (r *Rest) JoinChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, fmt.Sprintf("%#x", c.CID()))
 }
 
+func (r *Rest) JoinChainWithUpload(ctx echo.Context) error {
+	p := &ChainConfig{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	c, err := r.n.JoinChainWithUpload(p, ctx.Param(ParamUploadID))
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		if we, ok := err.(errors.Unwrapper); ok {
+			switch we.Unwrap() {
+			case ErrAlreadyExists:
+				return ctx.String(http.StatusConflict, err.Error())
+			}
+		}
+		return errors.Wrap(err, "fail to join")
+	}
+	return ctx.String(http.StatusOK, fmt.Sprintf("%#x", c.CID()))
+}
+
+func (r *Rest) JoinChainWithFetch(ctx echo.Context) error {
+	p := &ChainFetchParam{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	c, err := r.n.JoinChainWithFetch(p)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		if errors.TimeoutError.Equals(err) {
+			return ctx.String(http.StatusGatewayTimeout, err.Error())
+		}
+		if we, ok := err.(errors.Unwrapper); ok {
+			switch we.Unwrap() {
+			case ErrAlreadyExists:
+				return ctx.String(http.StatusConflict, err.Error())
+			}
+		}
+		return errors.Wrap(err, "fail to join")
+	}
+	return ctx.String(http.StatusOK, fmt.Sprintf("%#x", c.CID()))
+}
+
+func (r *Rest) GetGenesisUploads(ctx echo.Context) error {
+	l, err := r.n.GetGenesisUploads()
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) StartGenesisUpload(ctx echo.Context) error {
+	param := &GenesisUploadParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	u, err := r.n.StartGenesisUpload(param.Size, param.Checksum)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, u)
+}
+
+func (r *Rest) GetGenesisUpload(ctx echo.Context) error {
+	u, err := r.n.GetGenesisUpload(ctx.Param(ParamUploadID))
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, u)
+}
+
+func (r *Rest) WriteGenesisUpload(ctx echo.Context) error {
+	param := &GenesisChunkParam{}
+	if err := GetJsonMultipart(ctx, param); err != nil {
+		return errors.Wrap(err, "fail to get 'json' from multipart")
+	}
+	chunk, err := GetFileMultipart(ctx, "chunk")
+	if err != nil {
+		return errors.Wrap(err, "fail to get 'chunk' from multipart")
+	}
+	u, err := r.n.WriteGenesisUpload(ctx.Param(ParamUploadID),
+		param.Offset, chunk, param.Checksum)
+	if err != nil {
+		switch {
+		case errors.NotFoundError.Equals(err):
+			return ctx.String(http.StatusNotFound, err.Error())
+		case errors.InvalidStateError.Equals(err):
+			return ctx.String(http.StatusConflict, err.Error())
+		case errors.IllegalArgumentError.Equals(err):
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, u)
+}
+
+func (r *Rest) CancelGenesisUpload(ctx echo.Context) error {
+	if err := r.n.CancelGenesisUpload(ctx.Param(ParamUploadID)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 var (
 	defaultJsonTemplate = NewJsonTemplate("default")
 )
 
 func (r *Rest) GetChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
-	v := NewChainInspectView(c)
-
 	informal, _ := strconv.ParseBool(ctx.QueryParam("informal"))
+	v := inspectChain(c, informal)
+
+	format := ctx.QueryParam("format")
+	if format != "" {
+		return defaultJsonTemplate.Response(format, v, ctx.Response())
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func inspectChain(c *Chain, informal bool) *ChainInspectView {
+	v := NewChainInspectView(c)
 	v.Module = make(map[string]interface{})
 	for name, f := range inspectFuncs {
 		if m := f(c, informal); m != nil {
 			v.Module[name] = m
 		}
 	}
-	format := ctx.QueryParam("format")
-	if format != "" {
-		return defaultJsonTemplate.Response(format, v, ctx.Response())
-	}
-	return ctx.JSON(http.StatusOK, v)
+	return v
 }
 
 func (r *Rest) LeaveChain(ctx echo.Context) error {
@@ -380,6 +642,25 @@ func (r *Rest) ResetChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) RewindChain(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainRewindParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	plan, err := r.n.RewindChain(c.CID(), param.Height, param.Confirm)
+	if err != nil {
+		switch errors.CodeOf(err) {
+		case errors.IllegalArgumentError, errors.UnsupportedError:
+			return ctx.String(http.StatusBadRequest, err.Error())
+		case errors.InvalidStateError:
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, plan)
+}
+
 func (r *Rest) VerifyChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	if err := r.n.VerifyChain(c.CID()); err != nil {
@@ -451,6 +732,194 @@ func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetChainWebhooks(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	webhooks, err := r.n.GetChainWebhooks(c.CID())
+	if err != nil {
+		return err
+	}
+	l := make([]*ChainWebhookParam, 0, len(webhooks))
+	for _, w := range webhooks {
+		p := &ChainWebhookParam{Name: w.Name}
+		if err := json.Unmarshal(w.Config, &p.ActivityConfig); err != nil {
+			return err
+		}
+		p.Secret = ""
+		l = append(l, p)
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) AddChainWebhook(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	p := &ChainWebhookParam{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+	cfg, err := json.Marshal(&p.ActivityConfig)
+	if err != nil {
+		return err
+	}
+	if err := r.n.AddChainWebhook(c.CID(), p.Name, cfg); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) RemoveChainWebhook(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.RemoveChainWebhook(c.CID(), ctx.Param(ParamHook)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetChainEndpoint(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	s, err := r.n.GetChainEndpoint(c.CID())
+	if err != nil {
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, s)
+}
+
+func (r *Rest) ConfirmChainEndpoint(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	id, err := r.n.ConfirmChainEndpoint(c.CID())
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, common.HexBytes(id))
+}
+
+func (r *Rest) GetChainRecovery(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	events, err := r.n.GetChainRecovery(c.CID())
+	if err != nil {
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, events)
+}
+
+func (r *Rest) GetChainProfile(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	s, err := r.n.GetChainProfile(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, s)
+}
+
+func (r *Rest) StartChainProfile(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	p := &profile.Param{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.StartChainProfile(c.CID(), p); err != nil {
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) StopChainProfile(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.StopChainProfile(c.CID()); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetChainSnapshot(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	s, err := r.n.GetChainSnapshot(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, s)
+}
+
+func (r *Rest) StartChainSnapshot(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	p := &ChainSnapshotParam{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.StartChainSnapshot(c.CID(), p.Dir); err != nil {
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) StopChainSnapshot(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.StopChainSnapshot(c.CID()); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetChainComponents(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	comps, err := r.n.GetChainComponents(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, comps)
+}
+
+func (r *Rest) PauseChainComponent(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.PauseChainComponent(c.CID(), ctx.Param(ParamComponent)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) ResumeChainComponent(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.ResumeChainComponent(c.CID(), ctx.Param(ParamComponent)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RunChainTask(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	task := ctx.Param(TaskID)
@@ -474,19 +943,35 @@ func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
 	g.POST("/configure", r.ConfigureSystem)
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
+	r.RegisterEngineHandlers(g.Group("/engine"))
+	g.GET("/handshakes", r.GetHandshakes)
+	g.GET("/alerts", r.GetAlerts)
+	g.GET("/bundle", r.GetBundle)
+	g.GET("/registry", r.GetRegistry)
+	g.POST("/registry/sync", r.SyncRegistry)
+	g.GET("/apikey", r.GetAPIKeys)
+	g.POST("/apikey", r.AddAPIKey)
+	g.GET("/apikey/:"+ParamName, r.GetAPIKey)
+	g.DELETE("/apikey/:"+ParamName, r.RemoveAPIKey)
 }
 
-func (r *Rest) GetSystem(ctx echo.Context) error {
+func NewSystemView(n *Node) *SystemView {
 	v := &SystemView{
-		BuildVersion: r.n.cfg.BuildVersion,
-		BuildTags:    r.n.cfg.BuildTags,
+		BuildVersion: n.cfg.BuildVersion,
+		BuildTags:    n.cfg.BuildTags,
+		Features:     module.Features(),
 	}
-	v.Setting.Address = r.n.w.Address().String()
-	v.Setting.P2PAddr = r.n.nt.Address()
-	v.Setting.P2PListenAddr = r.n.nt.GetListenAddress()
-	v.Setting.RPCAddr = r.n.cfg.RPCAddr
-	v.Setting.RPCDump = r.n.cfg.RPCDump
-	v.Config = r.n.rcfg
+	v.Setting.Address = n.w.Address().String()
+	v.Setting.P2PAddr = n.nt.Address()
+	v.Setting.P2PListenAddr = n.nt.GetListenAddress()
+	v.Setting.RPCAddr = n.cfg.RPCAddr
+	v.Setting.RPCDump = n.cfg.RPCDump
+	v.Config = n.rcfg
+	return v
+}
+
+func (r *Rest) GetSystem(ctx echo.Context) error {
+	v := NewSystemView(r.n)
 
 	format := ctx.QueryParam("format")
 	if format != "" {
@@ -511,6 +996,88 @@ func (r *Rest) ConfigureSystem(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetHandshakes(ctx echo.Context) error {
+	records := make([]*network.HandshakeRecord, 0)
+	if ha, ok := r.n.nt.(network.HandshakeAuditor); ok {
+		records = append(records, ha.HandshakeRecords()...)
+	}
+	return ctx.JSON(http.StatusOK, records)
+}
+
+func (r *Rest) GetAlerts(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.n.GetAlerts())
+}
+
+func (r *Rest) GetRegistry(ctx echo.Context) error {
+	v, err := r.n.GetRegistry()
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) GetAPIKeys(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.n.srv.APIKeys().List())
+}
+
+func (r *Rest) AddAPIKey(ctx echo.Context) error {
+	p := &server.APIKey{}
+	if err := ctx.Bind(p); err != nil {
+		return echo.ErrBadRequest
+	}
+	k, err := r.n.srv.APIKeys().Add(p)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, k)
+}
+
+func (r *Rest) GetAPIKey(ctx echo.Context) error {
+	v, err := r.n.srv.APIKeys().Get(ctx.Param(ParamName))
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) RemoveAPIKey(ctx echo.Context) error {
+	if err := r.n.srv.APIKeys().Remove(ctx.Param(ParamName)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) SyncRegistry(ctx echo.Context) error {
+	if err := r.n.SyncRegistry(); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetBundle(ctx echo.Context) error {
+	name := fmt.Sprintf("goloop_bundle_%s.zip", time.Now().Format("20060102-150405"))
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "application/zip")
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	resp.WriteHeader(http.StatusOK)
+	return r.n.WriteBundle(resp)
+}
+
 func (r *Rest) RegistryBackupHandlers(g *echo.Group) {
 	g.GET("", r.GetBackups)
 }
@@ -525,6 +1092,7 @@ func (r *Rest) GetBackups(ctx echo.Context) error {
 
 func (r *Rest) RegistryRestoreHandlers(g *echo.Group) {
 	g.POST("", r.RestoreBackup)
+	g.POST("/peers", r.RestoreFromPeers)
 	g.GET("", r.GetRestore)
 	g.DELETE("", r.StopRestore)
 }
@@ -539,7 +1107,27 @@ func (r *Rest) RestoreBackup(ctx echo.Context) error {
 	if err := ctx.Bind(param); err != nil {
 		return err
 	}
-	if err := r.n.StartRestore(param.Name, param.Overwrite); err != nil {
+	if err := r.n.StartRestore(param.Name, param.Overwrite, param.Verify); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) RestoreFromPeers(ctx echo.Context) error {
+	param := new(RestorePeersParam)
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.StartRestoreFromPeers(param); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		if we, ok := err.(errors.Unwrapper); ok {
+			switch we.Unwrap() {
+			case ErrAlreadyExists:
+				return ctx.String(http.StatusConflict, err.Error())
+			}
+		}
 		return err
 	}
 	return ctx.String(http.StatusOK, "OK")
@@ -552,6 +1140,22 @@ func (r *Rest) StopRestore(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) RegisterEngineHandlers(g *echo.Group) {
+	g.GET("", r.GetEngines)
+	g.POST("/recycle", r.RecycleEngines)
+}
+
+func (r *Rest) GetEngines(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.n.pm.Status())
+}
+
+func (r *Rest) RecycleEngines(ctx echo.Context) error {
+	if err := r.n.pm.Recycle(); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RegisterUserHandlers(g *echo.Group) {
 	g.GET("", r.Users)
 	g.POST("", r.AddUser)
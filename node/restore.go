@@ -23,17 +23,56 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/chain/backup"
+	"github.com/icon-project/goloop/chain/snapsync"
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 )
 
 const (
 	RestoreDirectoryPrefix = ".restore"
 )
 
+const (
+	restoreStartTimeout = time.Minute
+	restorePeerTimeout  = 10 * time.Second
+
+	DefaultSnapshotFetchTimeout = 60 // in second
+)
+
+// Methods to verify checksums of restored files
+const (
+	ChecksumCRC32   = "crc32"
+	ChecksumSHA3256 = "sha3-256"
+)
+
+// Results of the integrity checks
+const (
+	IntegritySkipped    = "skipped"
+	IntegrityVerified   = "verified"
+	IntegrityUnverified = "unverified"
+	IntegrityMismatched = "mismatched"
+)
+
+// RestoreIntegrity is the result of the integrity checks of the restored
+// chain. Height is the height of the last block after replaying WAL, and
+// Peers is the result of the comparison of the block with the peers.
+type RestoreIntegrity struct {
+	Checksum     string           `json:"checksum"`
+	BackupHeight int64            `json:"backupHeight"`
+	Height       int64            `json:"height,omitempty"`
+	Peers        *chain.PeerCheck `json:"peers,omitempty"`
+	Result       string           `json:"result"`
+	Reason       string           `json:"reason,omitempty"`
+}
+
 type RestoreState int
 
 const (
@@ -42,6 +81,7 @@ const (
 	RestoreFailed
 	RestoreSuccess
 	RestoreStopping
+	RestoreVerifying
 )
 
 func (s RestoreState) String() string {
@@ -56,6 +96,8 @@ func (s RestoreState) String() string {
 		return "success"
 	case RestoreStopping:
 		return "stopping"
+	case RestoreVerifying:
+		return "verifying"
 	default:
 		return "unknown"
 	}
@@ -66,6 +108,7 @@ type RestoreStatus struct {
 	Overwrite bool
 	State     string
 	Error     error
+	Integrity *RestoreIntegrity
 }
 
 type RestoreManager struct {
@@ -74,23 +117,27 @@ type RestoreManager struct {
 	channel   string
 	overwrite bool
 
-	state   RestoreState
-	current int
-	total   int
-	lastErr error
+	state     RestoreState
+	current   int
+	total     int
+	lastErr   error
+	integrity *RestoreIntegrity
 }
 
-func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrite bool) (ret error) {
+// Start starts to restore the backup. The backup is a zip file made by
+// backup of the chain, or a directory made by online backup. If verify is
+// true, then the restored chain is started with its components paused, and
+// they are resumed after the last block is verified with the peers.
+func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrite, verify bool) (ret error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	switch m.state {
-	case RestoreFailed, RestoreSuccess:
-		m._setStateInLock(RestoreNone, nil)
-	case RestoreNone:
-	case RestoreStarted, RestoreStopping:
-		return errors.InvalidStateError.Errorf(
-			"StillRestoring(%s)", path.Base(m.file))
+	if err := m._checkIdle(); err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(file); err == nil && fi.IsDir() {
+		return m._startSnapshot(node, file, baseDir, overwrite, verify)
 	}
 
 	tmpDir, err := ioutil.TempDir(baseDir, RestoreDirectoryPrefix)
@@ -130,8 +177,143 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		return err
 	}
 
+	ri := &RestoreIntegrity{
+		Checksum:     ChecksumCRC32,
+		BackupHeight: info.Height,
+	}
+	m._run(node, int(info.CID.Value), verify, ri, func() error {
+		return m._restore(node, zr, tmpDir, overwrite)
+	})
+
+	m.file = file
+	m.overwrite = overwrite
+	m.state = RestoreStarted
+	m.current = 0
+	m.total = len(zr.File)
+	return nil
+}
+
+// StartPeers starts to restore the latest snapshot of the chain fetched
+// from the peers serving it. The chain shouldn't be running, because the
+// snapshot is fetched with the network of the chain.
+func (m *RestoreManager) StartPeers(node *Node, p *RestorePeersParam, baseDir string) (ret error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m._checkIdle(); err != nil {
+		return err
+	}
+
+	cid := int(p.CID.Value)
+	if c := node.GetChain(cid); c != nil {
+		if !p.Overwrite {
+			return errors.Wrapf(ErrAlreadyExists, "Chain(cid=%#x) already exists", cid)
+		}
+		if !c.IsStopped() {
+			return errors.InvalidStateError.Errorf("ChainNotStopped(cid=%#x)", cid)
+		}
+	}
+	fc, err := node.newFetchChain(cid, p.NID)
+	if err != nil {
+		return err
+	}
+	timeout := DefaultSnapshotFetchTimeout
+	if p.Timeout > 0 {
+		timeout = p.Timeout
+	}
+
+	tmpDir, err := ioutil.TempDir(baseDir, RestoreDirectoryPrefix)
+	if err != nil {
+		return err
+	}
+
+	ri := &RestoreIntegrity{
+		Checksum: ChecksumSHA3256,
+	}
+	m._run(node, cid, p.Verify, ri, func() error {
+		return m._restorePeers(node, fc, p, time.Duration(timeout)*time.Second, tmpDir, ri)
+	})
+
+	m.file = "peers(" + p.SeedAddr + ")"
+	m.overwrite = p.Overwrite
+	m.state = RestoreStarted
+	m.current = 0
+	m.total = 0
+	return nil
+}
+
+func (m *RestoreManager) _checkIdle() error {
+	switch m.state {
+	case RestoreFailed, RestoreSuccess:
+		m._setStateInLock(RestoreNone, nil)
+	case RestoreNone:
+	case RestoreStarted, RestoreStopping, RestoreVerifying:
+		return errors.InvalidStateError.Errorf(
+			"StillRestoring(%s)", path.Base(m.file))
+	}
+	return nil
+}
+
+func (m *RestoreManager) _startSnapshot(node *Node, dir string, baseDir string, overwrite, verify bool) error {
+	mf, err := backup.ReadManifest(dir)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"InvalidSnapshot(backup=%s)", dir)
+	}
+	if mf.Codec != codec.BC.Name() {
+		return errors.IllegalArgumentError.Errorf(
+			"IncompatibleCodec(backup=%s,system=%s)",
+			mf.Codec, codec.BC.Name())
+	}
+	if err := node.CanAdd(int(mf.CID.Value), int(mf.NID.Value), mf.Channel, overwrite); err != nil {
+		return err
+	}
+
+	total := 0
+	err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() && fi.Name() != backup.ManifestFile {
+			total += 1
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := ioutil.TempDir(baseDir, RestoreDirectoryPrefix)
+	if err != nil {
+		return err
+	}
+
+	ri := &RestoreIntegrity{
+		Checksum:     ChecksumSHA3256,
+		BackupHeight: mf.Height,
+	}
+	m._run(node, int(mf.CID.Value), verify, ri, func() error {
+		return m._restoreSnapshot(node, dir, mf, tmpDir, overwrite)
+	})
+
+	m.file = dir
+	m.overwrite = overwrite
+	m.state = RestoreStarted
+	m.current = 0
+	m.total = total
+	return nil
+}
+
+func (m *RestoreManager) _run(node *Node, cid int, verify bool, ri *RestoreIntegrity, restore func() error) {
+	m.integrity = nil
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		err := restore()
+		if err == nil {
+			if verify {
+				err = m._verify(node, cid, ri)
+			} else {
+				ri.Result = IntegritySkipped
+			}
+		}
+		m._setIntegrity(ri)
+		if err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -142,13 +324,6 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 			m._setState(RestoreSuccess, nil)
 		}
 	}()
-
-	m.file = file
-	m.overwrite = overwrite
-	m.state = RestoreStarted
-	m.current = 0
-	m.total = len(zr.File)
-	return nil
 }
 
 func (m *RestoreManager) _onRestored(idx int) error {
@@ -162,6 +337,23 @@ func (m *RestoreManager) _onRestored(idx int) error {
 	return nil
 }
 
+func (m *RestoreManager) _setIntegrity(ri *RestoreIntegrity) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.integrity = ri
+}
+
+func (m *RestoreManager) _checkStopping() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.state == RestoreStopping {
+		return errors.ErrInterrupted
+	}
+	return nil
+}
+
 func (m *RestoreManager) GetStatus() *RestoreStatus {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -181,6 +373,7 @@ func (m *RestoreManager) GetStatus() *RestoreStatus {
 			Overwrite: m.overwrite,
 			State:     m.state.String(),
 			Error:     m.lastErr,
+			Integrity: m.integrity,
 		}
 	}
 }
@@ -199,6 +392,7 @@ func (m *RestoreManager) _setStateInLock(s RestoreState, e error) {
 		m.file = ""
 		m.total = 0
 		m.current = 0
+		m.integrity = nil
 	}
 }
 
@@ -255,6 +449,145 @@ func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string,
 	return node.restoreChain(tmpDir, overwrite)
 }
 
+func (m *RestoreManager) _restoreSnapshot(node *Node, dir string, mf *backup.Manifest, tmpDir string, overwrite bool) (ret error) {
+	defer func() {
+		if ret != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	idx := 0
+	err := backup.Copy(dir, tmpDir, func(fi *backup.FileInfo) error {
+		err := m._onRestored(idx)
+		idx += 1
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := backup.VerifyManifest(tmpDir, mf, nil); err != nil {
+		return err
+	}
+	return node.restoreChain(tmpDir, overwrite)
+}
+
+func (m *RestoreManager) _restorePeers(node *Node, fc *fetchChain, p *RestorePeersParam, timeout time.Duration, tmpDir string, ri *RestoreIntegrity) (ret error) {
+	defer func() {
+		if ret != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	nm := network.NewManager(fc, node.nt, p.SeedAddr)
+	defer nm.Term()
+
+	f, err := snapsync.NewFetcher(nm, fc.logger)
+	if err != nil {
+		return err
+	}
+	defer f.Term()
+
+	if err := nm.Start(); err != nil {
+		return err
+	}
+	mf, err := f.Fetch(tmpDir, fc.CID(), timeout, func(done, total int) error {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		if m.state != RestoreStarted {
+			return errors.ErrInterrupted
+		}
+		m.current, m.total = done, total
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ri.BackupHeight = mf.Height
+	return node.restoreChain(tmpDir, p.Overwrite)
+}
+
+// _verify starts the restored chain with its components paused, so WAL
+// is replayed, then it compares the last block with the peers. Components
+// are resumed only if the block matches the blocks of the peers.
+func (m *RestoreManager) _verify(node *Node, cid int, ri *RestoreIntegrity) error {
+	m.lock.Lock()
+	if m.state != RestoreStarted {
+		m.lock.Unlock()
+		return errors.ErrInterrupted
+	}
+	m.state = RestoreVerifying
+	m.lock.Unlock()
+
+	for _, name := range module.Components {
+		if err := node.PauseChainComponent(cid, name); err != nil {
+			return err
+		}
+	}
+	if err := node.StartChain(cid); err != nil {
+		return err
+	}
+	height, err := m._waitStarted(node, cid)
+	if err != nil {
+		return err
+	}
+	ri.Height = height
+
+	pc, err := node.CheckChainWithPeers(cid, restorePeerTimeout)
+	if err != nil {
+		ri.Result = IntegrityUnverified
+		ri.Reason = err.Error()
+		return nil
+	}
+	ri.Peers = pc
+	switch {
+	case pc.Mismatched > 0:
+		ri.Result = IntegrityMismatched
+		if err := node.StopChain(cid); err != nil {
+			node.logger.Warnf("Fail to stop restored chain cid=%#x err=%+v", cid, err)
+		}
+		return errors.CriticalFormatError.Errorf(
+			"MismatchedBlock(height=%d,mismatched=%d,peers=%d)",
+			pc.Height, pc.Mismatched, pc.Peers)
+	case pc.Matched == 0:
+		ri.Result = IntegrityUnverified
+		ri.Reason = "NoPeersWithBlock"
+		return nil
+	}
+	for _, name := range module.Components {
+		if err := node.ResumeChainComponent(cid, name); err != nil {
+			return err
+		}
+	}
+	ri.Result = IntegrityVerified
+	return nil
+}
+
+func (m *RestoreManager) _waitStarted(node *Node, cid int) (int64, error) {
+	after := time.After(restoreStartTimeout)
+	for {
+		c := node.GetChain(cid)
+		if c == nil {
+			return 0, errors.NotFoundError.Errorf("ChainNotFound(cid=%#x)", cid)
+		}
+		if c.IsStarted() {
+			_, height, _ := c.State()
+			return height, nil
+		}
+		if _, _, err := c.State(); err != nil {
+			return 0, err
+		}
+		if err := m._checkStopping(); err != nil {
+			return 0, err
+		}
+		select {
+		case <-after:
+			return 0, errors.TimeoutError.New("ChainNotStarted")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
 func (m *RestoreManager) Stop() error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -263,7 +596,7 @@ func (m *RestoreManager) Stop() error {
 	case RestoreFailed, RestoreSuccess:
 		m._setStateInLock(RestoreNone, nil)
 		return nil
-	case RestoreStarted:
+	case RestoreStarted, RestoreVerifying:
 		m._setStateInLock(RestoreStopping, nil)
 		return nil
 	default:
@@ -0,0 +1,310 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+	QueryAPIKey  = "apikey"
+
+	apiKeyBytes = 16
+)
+
+// APIKey is the key of a client of the APIs. Requests with the key are
+// limited to Methods, and to Rate requests per second with Burst. Empty
+// Methods allows all methods, and zero Rate is unlimited.
+type APIKey struct {
+	Name    string   `json:"name"`
+	Key     string   `json:"key"`
+	Methods []string `json:"methods,omitempty"`
+	Rate    float64  `json:"rate,omitempty"`
+	Burst   int      `json:"burst,omitempty"`
+}
+
+// APIKeyUsage has the number of requests with the key since the node
+// started. Requests are the allowed ones. Denied ones are for the methods
+// not allowed, and limited ones are over the rate.
+type APIKeyUsage struct {
+	Requests int64 `json:"requests"`
+	Denied   int64 `json:"denied"`
+	Limited  int64 `json:"limited"`
+}
+
+// APIKeyView is the API key without the secret, and with the usage.
+type APIKeyView struct {
+	Name    string      `json:"name"`
+	Methods []string    `json:"methods,omitempty"`
+	Rate    float64     `json:"rate,omitempty"`
+	Burst   int         `json:"burst,omitempty"`
+	Usage   APIKeyUsage `json:"usage"`
+}
+
+type apiKeyEntry struct {
+	key     APIKey
+	methods map[string]bool
+	limiter *rate.Limiter
+	metric  *metric.APIKeyMetric
+
+	requests int64
+	denied   int64
+	limited  int64
+}
+
+func (e *apiKeyEntry) allow() bool {
+	if e.limiter != nil && !e.limiter.Allow() {
+		atomic.AddInt64(&e.limited, 1)
+		e.metric.OnRequest(metric.APIKeyResultLimited)
+		return false
+	}
+	atomic.AddInt64(&e.requests, 1)
+	e.metric.OnRequest(metric.APIKeyResultOK)
+	return true
+}
+
+// FilterMethod checks each JSON-RPC request with the key, so that requests
+// in a batch consume the quota one by one.
+func (e *apiKeyEntry) FilterMethod(method string) *jsonrpc.Error {
+	if e.methods != nil && !e.methods[method] {
+		atomic.AddInt64(&e.denied, 1)
+		e.metric.OnRequest(metric.APIKeyResultDenied)
+		return jsonrpc.ErrorCodeMethodNotFound.New(
+			fmt.Sprintf("MethodNotAllowed(method=%s)", method))
+	}
+	if !e.allow() {
+		return jsonrpc.ErrorCodeTooManyRequests.New(
+			fmt.Sprintf("RateLimited(rate=%v)", e.key.Rate))
+	}
+	return nil
+}
+
+func (e *apiKeyEntry) view() *APIKeyView {
+	return &APIKeyView{
+		Name:    e.key.Name,
+		Methods: e.key.Methods,
+		Rate:    e.key.Rate,
+		Burst:   e.key.Burst,
+		Usage: APIKeyUsage{
+			Requests: atomic.LoadInt64(&e.requests),
+			Denied:   atomic.LoadInt64(&e.denied),
+			Limited:  atomic.LoadInt64(&e.limited),
+		},
+	}
+}
+
+func newAPIKeyEntry(k APIKey) *apiKeyEntry {
+	e := &apiKeyEntry{
+		key:    k,
+		metric: metric.NewAPIKeyMetric(metric.DefaultMetricContext(), k.Name),
+	}
+	if len(k.Methods) > 0 {
+		e.methods = make(map[string]bool)
+		for _, m := range k.Methods {
+			e.methods[m] = true
+		}
+	}
+	if k.Rate > 0 {
+		burst := k.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(k.Rate))
+		}
+		e.limiter = rate.NewLimiter(rate.Limit(k.Rate), burst)
+	}
+	return e
+}
+
+// APIKeys manages API keys for multiple clients sharing the node. Keys are
+// stored in the file, and usages of them are kept in memory.
+type APIKeys struct {
+	lock     sync.RWMutex
+	keys     map[string]*apiKeyEntry
+	names    map[string]*apiKeyEntry
+	required int32
+	filePath string
+}
+
+func (ks *APIKeys) entryOf(key string) *apiKeyEntry {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	return ks.keys[key]
+}
+
+func (ks *APIKeys) _export() error {
+	if ks.filePath == "" {
+		return nil
+	}
+	keys := make([]*APIKey, 0, len(ks.names))
+	for _, e := range ks.names {
+		keys = append(keys, &e.key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+	bs, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ks.filePath, bs, 0600)
+}
+
+// Add adds the key for the name. It generates the secret of the key if
+// Key is empty.
+func (ks *APIKeys) Add(k *APIKey) (*APIKey, error) {
+	if k.Name == "" {
+		return nil, errors.IllegalArgumentError.New("EmptyName")
+	}
+	if k.Rate < 0 || k.Burst < 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidQuota(rate=%v,burst=%d)", k.Rate, k.Burst)
+	}
+	nk := *k
+	if nk.Key == "" {
+		bs := make([]byte, apiKeyBytes)
+		if _, err := rand.Read(bs); err != nil {
+			return nil, err
+		}
+		nk.Key = hex.EncodeToString(bs)
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	if _, ok := ks.names[nk.Name]; ok {
+		return nil, errors.IllegalArgumentError.Errorf("DuplicateName(name=%s)", nk.Name)
+	}
+	if _, ok := ks.keys[nk.Key]; ok {
+		return nil, errors.IllegalArgumentError.New("DuplicateKey")
+	}
+	e := newAPIKeyEntry(nk)
+	ks.names[nk.Name] = e
+	ks.keys[nk.Key] = e
+	if err := ks._export(); err != nil {
+		delete(ks.names, nk.Name)
+		delete(ks.keys, nk.Key)
+		return nil, err
+	}
+	return &nk, nil
+}
+
+// Remove revokes the key of the name.
+func (ks *APIKeys) Remove(name string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	e, ok := ks.names[name]
+	if !ok {
+		return errors.NotFoundError.Errorf("NoAPIKey(name=%s)", name)
+	}
+	delete(ks.names, name)
+	delete(ks.keys, e.key.Key)
+	return ks._export()
+}
+
+func (ks *APIKeys) Get(name string) (*APIKeyView, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	e, ok := ks.names[name]
+	if !ok {
+		return nil, errors.NotFoundError.Errorf("NoAPIKey(name=%s)", name)
+	}
+	return e.view(), nil
+}
+
+// List returns keys in order of names.
+func (ks *APIKeys) List() []*APIKeyView {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	views := make([]*APIKeyView, 0, len(ks.names))
+	for _, e := range ks.names {
+		views = append(views, e.view())
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+	return views
+}
+
+// SetRequired sets whether requests without the key are rejected.
+func (ks *APIKeys) SetRequired(required bool) {
+	atomicStore(&ks.required, required)
+}
+
+func (ks *APIKeys) Required() bool {
+	return atomicLoad(&ks.required)
+}
+
+// Middleware checks the API key of the request in the header or the query.
+// JSON-RPC requests with the key are filtered by the methods and the quota
+// of it, and websocket sessions consume the quota on connecting.
+func (ks *APIKeys) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			key := ctx.Request().Header.Get(HeaderAPIKey)
+			if key == "" {
+				key = ctx.QueryParam(QueryAPIKey)
+			}
+			if key == "" {
+				if ks.Required() {
+					return ctx.String(http.StatusUnauthorized, "api key required")
+				}
+				return next(ctx)
+			}
+			e := ks.entryOf(key)
+			if e == nil {
+				return ctx.String(http.StatusUnauthorized, "invalid api key")
+			}
+			if websocket.IsWebSocketUpgrade(ctx.Request()) && !e.allow() {
+				return ctx.String(http.StatusTooManyRequests, "rate limited")
+			}
+			ctx.Set("methodFilter", e)
+			ctx.Set("apiKey", e.key.Name)
+			return next(ctx)
+		}
+	}
+}
+
+// NewAPIKeys returns keys stored in the file. Empty file path keeps keys
+// only in memory.
+func NewAPIKeys(filePath string) (*APIKeys, error) {
+	ks := &APIKeys{
+		keys:     make(map[string]*apiKeyEntry),
+		names:    make(map[string]*apiKeyEntry),
+		filePath: filePath,
+	}
+	if filePath == "" {
+		return ks, nil
+	}
+	bs, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ks, nil
+		}
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(bs, &keys); err != nil {
+		return nil, errors.Wrapf(err, "InvalidAPIKeyFile(path=%s)", filePath)
+	}
+	for _, k := range keys {
+		e := newAPIKeyEntry(k)
+		ks.names[k.Name] = e
+		ks.keys[k.Key] = e
+	}
+	return ks, nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func TestAPIKeys_AddRemove(t *testing.T) {
+	file := path.Join(t.TempDir(), "apikeys.json")
+	ks, err := NewAPIKeys(file)
+	assert.NoError(t, err)
+
+	k1, err := ks.Add(&APIKey{Name: "alice", Methods: []string{"icx_getBalance"}, Rate: 10})
+	assert.NoError(t, err)
+	assert.Len(t, k1.Key, apiKeyBytes*2)
+	_, err = ks.Add(&APIKey{Name: "bob", Key: "secret"})
+	assert.NoError(t, err)
+
+	_, err = ks.Add(&APIKey{Name: "alice"})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = ks.Add(&APIKey{Name: "carol", Key: "secret"})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = ks.Add(&APIKey{Name: "carol", Rate: -1})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// keys are loaded from the file
+	ks2, err := NewAPIKeys(file)
+	assert.NoError(t, err)
+	views := ks2.List()
+	assert.Len(t, views, 2)
+	assert.Equal(t, "alice", views[0].Name)
+	assert.Equal(t, []string{"icx_getBalance"}, views[0].Methods)
+	assert.Equal(t, "bob", views[1].Name)
+	assert.Equal(t, ks2.entryOf(k1.Key), ks2.names["alice"])
+
+	assert.NoError(t, ks2.Remove("bob"))
+	assert.True(t, errors.NotFoundError.Equals(ks2.Remove("bob")))
+	assert.Nil(t, ks2.entryOf("secret"))
+	_, err = ks2.Get("bob")
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
+
+func TestAPIKeys_FilterMethod(t *testing.T) {
+	ks, _ := NewAPIKeys("")
+	k, err := ks.Add(&APIKey{Name: "alice", Methods: []string{"icx_getBalance"}, Rate: 1, Burst: 2})
+	assert.NoError(t, err)
+	e := ks.entryOf(k.Key)
+
+	assert.Nil(t, e.FilterMethod("icx_getBalance"))
+	jerr := e.FilterMethod("icx_sendTransaction")
+	assert.Equal(t, jsonrpc.ErrorCodeMethodNotFound, jerr.Code)
+	assert.Nil(t, e.FilterMethod("icx_getBalance"))
+	jerr = e.FilterMethod("icx_getBalance")
+	assert.Equal(t, jsonrpc.ErrorCodeTooManyRequests, jerr.Code)
+
+	v, err := ks.Get("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, APIKeyUsage{Requests: 2, Denied: 1, Limited: 1}, v.Usage)
+}
+
+func TestAPIKeys_Middleware(t *testing.T) {
+	ks, _ := NewAPIKeys("")
+	k, err := ks.Add(&APIKey{Name: "alice"})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.GET("/", func(ctx echo.Context) error {
+		if f, ok := ctx.Get("methodFilter").(jsonrpc.MethodFilter); ok && f != nil {
+			return ctx.String(http.StatusOK, "key")
+		}
+		return ctx.String(http.StatusOK, "anonymous")
+	}, ks.Middleware())
+
+	get := func(target string, header string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if header != "" {
+			req.Header.Set(HeaderAPIKey, header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "anonymous", body)
+	code, body = get("/", k.Key)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "key", body)
+	code, body = get("/?"+QueryAPIKey+"="+k.Key, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "key", body)
+	code, _ = get("/", "invalid")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	ks.SetRequired(true)
+	code, _ = get("/", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = get("/", k.Key)
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	AuthScheme = "Bearer"
+
+	authReloadInterval = time.Second
+)
+
+// Authenticator authenticates requests to the endpoints configured for
+// authentication. Operators may set their own one by SetAuthenticator.
+type Authenticator interface {
+	// Authenticate returns an error if the request is not authorized.
+	Authenticate(req *http.Request) error
+}
+
+// AuthTokens is the content of the file for TokenAuthenticator. Tokens are
+// accepted as they are, and Secrets are HMAC keys of JWTs.
+type AuthTokens struct {
+	Tokens  []string `json:"tokens,omitempty"`
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// TokenAuthenticator accepts bearer tokens in the file, or JWTs signed
+// with one of the secrets in the file. The file is read again on changes,
+// so that tokens and secrets are rotated without restarting the node.
+type TokenAuthenticator struct {
+	lock     sync.Mutex
+	filePath string
+	modTime  time.Time
+	checked  time.Time
+	tokens   *AuthTokens
+	logger   log.Logger
+}
+
+func (a *TokenAuthenticator) _load(now time.Time) {
+	if now.Sub(a.checked) < authReloadInterval {
+		return
+	}
+	a.checked = now
+	fi, err := os.Stat(a.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			a.tokens = &AuthTokens{}
+			return
+		}
+		a.logger.Warnf("Fail to check auth tokens err=%+v", err)
+		return
+	}
+	if a.tokens != nil && fi.ModTime().Equal(a.modTime) {
+		return
+	}
+	bs, err := ioutil.ReadFile(a.filePath)
+	if err != nil {
+		a.logger.Warnf("Fail to read auth tokens err=%+v", err)
+		return
+	}
+	tokens := &AuthTokens{}
+	if err := json.Unmarshal(bs, tokens); err != nil {
+		// keep using tokens loaded before until the file is fixed.
+		a.logger.Warnf("Fail to parse auth tokens path=%s err=%+v", a.filePath, err)
+		return
+	}
+	a.tokens = tokens
+	a.modTime = fi.ModTime()
+	a.logger.Infof("Load auth tokens path=%s tokens=%d secrets=%d",
+		a.filePath, len(tokens.Tokens), len(tokens.Secrets))
+}
+
+func (a *TokenAuthenticator) current() *AuthTokens {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a._load(time.Now())
+	if a.tokens == nil {
+		return &AuthTokens{}
+	}
+	return a.tokens
+}
+
+func verifyJWT(token string, secrets []string) error {
+	var err error
+	for _, secret := range secrets {
+		_, err = jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.Errorf("UnexpectedSigningMethod(alg=%v)", t.Header["alg"])
+			}
+			return []byte(secret), nil
+		})
+		if err == nil {
+			return nil
+		}
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			return err
+		}
+	}
+	if err == nil {
+		err = errors.New("NoSecret")
+	}
+	return err
+}
+
+func (a *TokenAuthenticator) Authenticate(req *http.Request) error {
+	auth := req.Header.Get(echo.HeaderAuthorization)
+	if !strings.HasPrefix(auth, AuthScheme+" ") {
+		return errors.New("NoBearerToken")
+	}
+	token := strings.TrimSpace(auth[len(AuthScheme)+1:])
+	tokens := a.current()
+	for _, t := range tokens.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	if strings.Count(token, ".") == 2 {
+		if err := verifyJWT(token, tokens.Secrets); err != nil {
+			return errors.Wrap(err, "InvalidToken")
+		}
+		return nil
+	}
+	return errors.New("InvalidToken")
+}
+
+func NewTokenAuthenticator(filePath string, l log.Logger) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		filePath: filePath,
+		logger:   l,
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+func writeAuthTokens(t *testing.T, file string, tokens *AuthTokens) {
+	bs, err := json.Marshal(tokens)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(file, bs, 0600))
+}
+
+func signJWT(t *testing.T, secret string, exp time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		ExpiresAt: exp.Unix(),
+	})
+	s, err := token.SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return s
+}
+
+func authRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, AuthScheme+" "+token)
+	}
+	return req
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	file := path.Join(t.TempDir(), "rpcauth.json")
+	a := NewTokenAuthenticator(file, log.New())
+
+	// no file, no tokens
+	assert.Error(t, a.Authenticate(authRequest("token1")))
+
+	writeAuthTokens(t, file, &AuthTokens{
+		Tokens:  []string{"token1"},
+		Secrets: []string{"secret1"},
+	})
+	a.checked = time.Time{}
+	assert.NoError(t, a.Authenticate(authRequest("token1")))
+	assert.Error(t, a.Authenticate(authRequest("token2")))
+	assert.Error(t, a.Authenticate(authRequest("")))
+	assert.NoError(t, a.Authenticate(authRequest(signJWT(t, "secret1", time.Now().Add(time.Hour)))))
+	assert.Error(t, a.Authenticate(authRequest(signJWT(t, "secret1", time.Now().Add(-time.Hour)))))
+	assert.Error(t, a.Authenticate(authRequest(signJWT(t, "secret2", time.Now().Add(time.Hour)))))
+
+	// rotate tokens and secrets
+	writeAuthTokens(t, file, &AuthTokens{
+		Tokens:  []string{"token2"},
+		Secrets: []string{"secret2", "secret1"},
+	})
+	a.checked = time.Time{}
+	a.modTime = time.Time{}
+	assert.Error(t, a.Authenticate(authRequest("token1")))
+	assert.NoError(t, a.Authenticate(authRequest("token2")))
+	assert.NoError(t, a.Authenticate(authRequest(signJWT(t, "secret1", time.Now().Add(time.Hour)))))
+	assert.NoError(t, a.Authenticate(authRequest(signJWT(t, "secret2", time.Now().Add(time.Hour)))))
+
+	// broken file keeps tokens loaded before
+	assert.NoError(t, ioutil.WriteFile(file, []byte("{"), 0600))
+	a.checked = time.Time{}
+	a.modTime = time.Time{}
+	assert.NoError(t, a.Authenticate(authRequest("token2")))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	assert.NoError(t, verifyJWT(signJWT(t, "secret1", exp), []string{"secret2", "secret1"}))
+
+	// no secret to verify with
+	err := verifyJWT(signJWT(t, "secret1", exp), nil)
+	assert.EqualError(t, err, "NoSecret")
+
+	// only HMAC signing methods are accepted
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.StandardClaims{
+		ExpiresAt: exp.Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	assert.Error(t, verifyJWT(none, []string{"secret1"}))
+
+	// expired token fails with the matching secret
+	err = verifyJWT(signJWT(t, "secret1", time.Now().Add(-time.Hour)), []string{"secret1", "secret2"})
+	ve, ok := err.(*jwt.ValidationError)
+	assert.True(t, ok)
+	assert.NotZero(t, ve.Errors&jwt.ValidationErrorExpired)
+
+	// unknown secret fails with invalid signature
+	err = verifyJWT(signJWT(t, "secret3", exp), []string{"secret1", "secret2"})
+	ve, ok = err.(*jwt.ValidationError)
+	assert.True(t, ok)
+	assert.NotZero(t, ve.Errors&jwt.ValidationErrorSignatureInvalid)
+}
+
+func TestManager_CheckAuth(t *testing.T) {
+	file := path.Join(t.TempDir(), "rpcauth.json")
+	writeAuthTokens(t, file, &AuthTokens{Tokens: []string{"token1"}})
+	srv := &Manager{}
+	srv.SetAuthenticator(NewTokenAuthenticator(file, log.New()))
+	assert.NoError(t, srv.SetAuthEndpoints([]string{EndpointV3Debug}))
+	assert.Error(t, srv.SetAuthEndpoints([]string{"v4"}))
+
+	e := echo.New()
+	ok := func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	}
+	api := e.Group("/api", srv.authenticating())
+	api.POST("/v3", ok, srv.CheckAuth(EndpointV3))
+	api.POST("/v3d", ok, srv.CheckAuth(EndpointV3Debug))
+	e.POST("/cli/v3d", ok, srv.CheckAuth(EndpointV3Debug))
+
+	post := func(target, token string) int {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, AuthScheme+" "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, post("/api/v3", ""))
+	assert.Equal(t, http.StatusUnauthorized, post("/api/v3d", ""))
+	assert.Equal(t, http.StatusUnauthorized, post("/api/v3d", "token2"))
+	assert.Equal(t, http.StatusOK, post("/api/v3d", "token1"))
+	assert.Equal(t, http.StatusOK, post("/cli/v3d", ""))
+
+	assert.NoError(t, srv.SetAuthEndpoints(nil))
+	assert.Equal(t, http.StatusOK, post("/api/v3d", ""))
+}
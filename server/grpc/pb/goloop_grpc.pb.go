@@ -0,0 +1,416 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: goloop.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Goloop_GetBlock_FullMethodName             = "/goloop.v1.Goloop/GetBlock"
+	Goloop_GetTransaction_FullMethodName       = "/goloop.v1.Goloop/GetTransaction"
+	Goloop_GetTransactionResult_FullMethodName = "/goloop.v1.Goloop/GetTransactionResult"
+	Goloop_Call_FullMethodName                 = "/goloop.v1.Goloop/Call"
+	Goloop_GetBTPHeader_FullMethodName         = "/goloop.v1.Goloop/GetBTPHeader"
+	Goloop_GetBTPMessages_FullMethodName       = "/goloop.v1.Goloop/GetBTPMessages"
+	Goloop_GetBTPProof_FullMethodName          = "/goloop.v1.Goloop/GetBTPProof"
+	Goloop_SubscribeBlocks_FullMethodName      = "/goloop.v1.Goloop/SubscribeBlocks"
+)
+
+// GoloopClient is the client API for Goloop service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GoloopClient interface {
+	// GetBlock returns the block by the height or the hash. It returns the
+	// last block if both are missing.
+	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// GetTransaction returns the transaction by the hash.
+	GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error)
+	// GetTransactionResult returns the result of the transaction by the hash.
+	GetTransactionResult(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Receipt, error)
+	// Call calls the read-only method of the contract.
+	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error)
+	// GetBTPHeader returns the BTP block header of the network at the height.
+	GetBTPHeader(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPData, error)
+	// GetBTPMessages returns BTP messages of the network at the height.
+	GetBTPMessages(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPMessages, error)
+	// GetBTPProof returns the proof of the BTP block of the network at the
+	// height.
+	GetBTPProof(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPData, error)
+	// SubscribeBlocks streams finalized blocks from the height.
+	SubscribeBlocks(ctx context.Context, in *BlockStreamRequest, opts ...grpc.CallOption) (Goloop_SubscribeBlocksClient, error)
+}
+
+type goloopClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGoloopClient(cc grpc.ClientConnInterface) GoloopClient {
+	return &goloopClient{cc}
+}
+
+func (c *goloopClient) GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, Goloop_GetBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error) {
+	out := new(TransactionInfo)
+	err := c.cc.Invoke(ctx, Goloop_GetTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) GetTransactionResult(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, Goloop_GetTransactionResult_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error) {
+	out := new(CallResponse)
+	err := c.cc.Invoke(ctx, Goloop_Call_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) GetBTPHeader(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPData, error) {
+	out := new(BTPData)
+	err := c.cc.Invoke(ctx, Goloop_GetBTPHeader_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) GetBTPMessages(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPMessages, error) {
+	out := new(BTPMessages)
+	err := c.cc.Invoke(ctx, Goloop_GetBTPMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) GetBTPProof(ctx context.Context, in *BTPRequest, opts ...grpc.CallOption) (*BTPData, error) {
+	out := new(BTPData)
+	err := c.cc.Invoke(ctx, Goloop_GetBTPProof_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goloopClient) SubscribeBlocks(ctx context.Context, in *BlockStreamRequest, opts ...grpc.CallOption) (Goloop_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Goloop_ServiceDesc.Streams[0], Goloop_SubscribeBlocks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goloopSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Goloop_SubscribeBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type goloopSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *goloopSubscribeBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GoloopServer is the server API for Goloop service.
+// All implementations must embed UnimplementedGoloopServer
+// for forward compatibility
+type GoloopServer interface {
+	// GetBlock returns the block by the height or the hash. It returns the
+	// last block if both are missing.
+	GetBlock(context.Context, *BlockRequest) (*Block, error)
+	// GetTransaction returns the transaction by the hash.
+	GetTransaction(context.Context, *TransactionRequest) (*TransactionInfo, error)
+	// GetTransactionResult returns the result of the transaction by the hash.
+	GetTransactionResult(context.Context, *TransactionRequest) (*Receipt, error)
+	// Call calls the read-only method of the contract.
+	Call(context.Context, *CallRequest) (*CallResponse, error)
+	// GetBTPHeader returns the BTP block header of the network at the height.
+	GetBTPHeader(context.Context, *BTPRequest) (*BTPData, error)
+	// GetBTPMessages returns BTP messages of the network at the height.
+	GetBTPMessages(context.Context, *BTPRequest) (*BTPMessages, error)
+	// GetBTPProof returns the proof of the BTP block of the network at the
+	// height.
+	GetBTPProof(context.Context, *BTPRequest) (*BTPData, error)
+	// SubscribeBlocks streams finalized blocks from the height.
+	SubscribeBlocks(*BlockStreamRequest, Goloop_SubscribeBlocksServer) error
+	mustEmbedUnimplementedGoloopServer()
+}
+
+// UnimplementedGoloopServer must be embedded to have forward compatible implementations.
+type UnimplementedGoloopServer struct {
+}
+
+func (UnimplementedGoloopServer) GetBlock(context.Context, *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedGoloopServer) GetTransaction(context.Context, *TransactionRequest) (*TransactionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedGoloopServer) GetTransactionResult(context.Context, *TransactionRequest) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionResult not implemented")
+}
+func (UnimplementedGoloopServer) Call(context.Context, *CallRequest) (*CallResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
+}
+func (UnimplementedGoloopServer) GetBTPHeader(context.Context, *BTPRequest) (*BTPData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBTPHeader not implemented")
+}
+func (UnimplementedGoloopServer) GetBTPMessages(context.Context, *BTPRequest) (*BTPMessages, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBTPMessages not implemented")
+}
+func (UnimplementedGoloopServer) GetBTPProof(context.Context, *BTPRequest) (*BTPData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBTPProof not implemented")
+}
+func (UnimplementedGoloopServer) SubscribeBlocks(*BlockStreamRequest, Goloop_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (UnimplementedGoloopServer) mustEmbedUnimplementedGoloopServer() {}
+
+// UnsafeGoloopServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoloopServer will
+// result in compilation errors.
+type UnsafeGoloopServer interface {
+	mustEmbedUnimplementedGoloopServer()
+}
+
+func RegisterGoloopServer(s grpc.ServiceRegistrar, srv GoloopServer) {
+	s.RegisterService(&Goloop_ServiceDesc, srv)
+}
+
+func _Goloop_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetTransaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_GetTransactionResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetTransactionResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetTransactionResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetTransactionResult(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_Call_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).Call(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_GetBTPHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetBTPHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetBTPHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetBTPHeader(ctx, req.(*BTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_GetBTPMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetBTPMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetBTPMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetBTPMessages(ctx, req.(*BTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_GetBTPProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoloopServer).GetBTPProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goloop_GetBTPProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoloopServer).GetBTPProof(ctx, req.(*BTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goloop_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlockStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoloopServer).SubscribeBlocks(m, &goloopSubscribeBlocksServer{stream})
+}
+
+type Goloop_SubscribeBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type goloopSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *goloopSubscribeBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Goloop_ServiceDesc is the grpc.ServiceDesc for Goloop service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Goloop_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goloop.v1.Goloop",
+	HandlerType: (*GoloopServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlock",
+			Handler:    _Goloop_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Goloop_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransactionResult",
+			Handler:    _Goloop_GetTransactionResult_Handler,
+		},
+		{
+			MethodName: "Call",
+			Handler:    _Goloop_Call_Handler,
+		},
+		{
+			MethodName: "GetBTPHeader",
+			Handler:    _Goloop_GetBTPHeader_Handler,
+		},
+		{
+			MethodName: "GetBTPMessages",
+			Handler:    _Goloop_GetBTPMessages_Handler,
+		},
+		{
+			MethodName: "GetBTPProof",
+			Handler:    _Goloop_GetBTPProof_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _Goloop_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "goloop.proto",
+}
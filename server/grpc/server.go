@@ -0,0 +1,468 @@
+// Package grpc serves queries of the v3 JSON-RPC API over gRPC, so that
+// clients reading many blocks and results can avoid the overhead of JSON.
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/grpc/pb"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+// Backend provides chains by channels and the limit of steps for calls.
+type Backend interface {
+	Chain(channel string) module.Chain
+	CallStepLimit() int64
+}
+
+type Server struct {
+	pb.UnimplementedGoloopServer
+
+	backend Backend
+	server  *gogrpc.Server
+	log     log.Logger
+}
+
+// Serve accepts connections on the listener. It returns after Stop is
+// called.
+func (s *Server) Serve(l net.Listener) error {
+	return s.server.Serve(l)
+}
+
+// Stop stops the server after pending RPCs are finished. Streaming RPCs
+// are finished by it.
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
+
+func (s *Server) chain(channel string) (module.Chain, error) {
+	c := s.backend.Chain(channel)
+	if c == nil {
+		return nil, status.Errorf(codes.NotFound, "ChainNotFound(channel=%s)", channel)
+	}
+	if module.IsComponentPaused(c, module.ComponentRPC) {
+		return nil, status.Error(codes.Unavailable, "Paused")
+	}
+	return c, nil
+}
+
+func errStopped() error {
+	return status.Error(codes.Unavailable, "Stopped")
+}
+
+// statusOf returns the status error for the error from the chain.
+func statusOf(err error) error {
+	switch {
+	case errors.NotFoundError.Equals(err):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.IllegalArgumentError.Equals(err),
+		service.InvalidQueryError.Equals(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case scoreresult.IsValid(err):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func checkBaseHeight(c module.Chain, height int64) error {
+	if height < 0 {
+		return errors.NotFoundError.Errorf("NegativeHeight(height=%d)", height)
+	}
+	base := c.GenesisStorage().Height()
+	if height < base {
+		return errors.NotFoundError.Errorf(
+			"PrunedBlock(height=%d,base=%d)", height, base)
+	}
+	return nil
+}
+
+func blockAt(c module.Chain, bm module.BlockManager, height int64) (module.Block, error) {
+	if err := checkBaseHeight(c, height); err != nil {
+		return nil, err
+	}
+	return bm.GetBlockByHeight(height)
+}
+
+func addressBytes(addr module.Address) []byte {
+	if addr == nil {
+		return nil
+	}
+	return addr.Bytes()
+}
+
+func bigIntBytes(v *big.Int) []byte {
+	if v == nil {
+		return nil
+	}
+	return v.Bytes()
+}
+
+func transactionOf(tx module.Transaction, data bool) *pb.Transaction {
+	t := &pb.Transaction{Hash: tx.ID()}
+	if data {
+		t.Data = tx.Bytes()
+	}
+	return t
+}
+
+func blockOf(blk module.Block, data bool) (*pb.Block, error) {
+	b := &pb.Block{
+		Version:   int32(blk.Version()),
+		Height:    blk.Height(),
+		Hash:      blk.ID(),
+		PrevHash:  blk.PrevID(),
+		Timestamp: blk.Timestamp(),
+		Proposer:  addressBytes(blk.Proposer()),
+	}
+	for it := blk.NormalTransactions().Iterator(); it.Has(); it.Next() {
+		tx, _, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		b.Transactions = append(b.Transactions, transactionOf(tx, data))
+	}
+	return b, nil
+}
+
+func (s *Server) GetBlock(ctx context.Context, req *pb.BlockRequest) (*pb.Block, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	if bm == nil {
+		return nil, errStopped()
+	}
+	var blk module.Block
+	switch id := req.Id.(type) {
+	case *pb.BlockRequest_Height:
+		blk, err = blockAt(c, bm, id.Height)
+	case *pb.BlockRequest_Hash:
+		blk, err = bm.GetBlock(id.Hash)
+	default:
+		blk, err = bm.GetLastBlock()
+	}
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	b, err := blockOf(blk, req.Transactions)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return b, nil
+}
+
+func (s *Server) GetTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionInfo, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	if bm == nil {
+		return nil, errStopped()
+	}
+	info, err := bm.GetTransactionInfo(req.Hash)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	tx, err := info.Transaction()
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	blk := info.Block()
+	return &pb.TransactionInfo{
+		Transaction: transactionOf(tx, true),
+		BlockHash:   blk.ID(),
+		BlockHeight: blk.Height(),
+		Index:       int32(info.Index()),
+	}, nil
+}
+
+func (s *Server) GetTransactionResult(ctx context.Context, req *pb.TransactionRequest) (*pb.Receipt, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	sm := c.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, errStopped()
+	}
+	info, err := bm.GetTransactionInfo(req.Hash)
+	if errors.NotFoundError.Equals(err) {
+		if sm.HasTransaction(req.Hash) {
+			return nil, status.Error(codes.Unavailable, "Pending")
+		}
+		return nil, statusOf(err)
+	} else if err != nil {
+		return nil, statusOf(err)
+	}
+	blk := info.Block()
+	if err := checkBaseHeight(c, blk.Height()); err != nil {
+		return nil, statusOf(err)
+	}
+	rct, err := info.GetReceipt()
+	if block.ResultNotFinalizedError.Equals(err) {
+		return nil, status.Error(codes.Unavailable, "Executing")
+	} else if err != nil {
+		return nil, statusOf(err)
+	}
+	r := &pb.Receipt{
+		TxHash:             req.Hash,
+		BlockHash:          blk.ID(),
+		BlockHeight:        blk.Height(),
+		Index:              int32(info.Index()),
+		Status:             int32(rct.Status()),
+		StepUsed:           bigIntBytes(rct.StepUsed()),
+		StepPrice:          bigIntBytes(rct.StepPrice()),
+		CumulativeStepUsed: bigIntBytes(rct.CumulativeStepUsed()),
+		ScoreAddress:       addressBytes(rct.SCOREAddress()),
+	}
+	for it := rct.EventLogIterator(); it.Has(); it.Next() {
+		el, err := it.Get()
+		if err != nil {
+			return nil, statusOf(err)
+		}
+		r.EventLogs = append(r.EventLogs, &pb.EventLog{
+			Address: addressBytes(el.Address()),
+			Indexed: el.Indexed(),
+			Data:    el.Data(),
+		})
+	}
+	return r, nil
+}
+
+// callQuery returns the query of the call in the form of the parameters of
+// icx_call.
+func (s *Server) callQuery(req *pb.CallRequest) ([]byte, error) {
+	to, err := common.NewAddress(req.To)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidTo")
+	}
+	data := map[string]interface{}{
+		"method": req.Method,
+	}
+	if len(req.Params) > 0 {
+		data["params"] = json.RawMessage(req.Params)
+	}
+	query := map[string]interface{}{
+		"to":       to,
+		"dataType": "call",
+		"data":     data,
+	}
+	if len(req.From) > 0 {
+		from, err := common.NewAddress(req.From)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidFrom")
+		}
+		query["from"] = from
+	}
+	if limit := s.backend.CallStepLimit(); limit > 0 {
+		query["stepLimit"] = intconv.FormatInt(limit)
+	}
+	js, err := json.Marshal(query)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidParams")
+	}
+	return js, nil
+}
+
+func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.CallResponse, error) {
+	js, err := s.callQuery(req)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	sm := c.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, errStopped()
+	}
+	var blk module.Block
+	if req.Height != nil {
+		blk, err = blockAt(c, bm, *req.Height)
+	} else {
+		blk, err = bm.GetLastBlock()
+	}
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	result, err := sm.Call(blk.Result(), blk.NextValidators(), js, bi)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.CallResponse{Result: bs}, nil
+}
+
+func (s *Server) GetBTPHeader(ctx context.Context, req *pb.BTPRequest) (*pb.BTPData, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	cs := c.Consensus()
+	if bm == nil || cs == nil {
+		return nil, errStopped()
+	}
+	blk, err := blockAt(c, bm, req.Height)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	bb, _, err := cs.GetBTPBlockHeaderAndProof(blk, req.NetworkId, module.FlagBTPBlockHeader)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.BTPData{Data: bb.HeaderBytes()}, nil
+}
+
+func (s *Server) GetBTPProof(ctx context.Context, req *pb.BTPRequest) (*pb.BTPData, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	cs := c.Consensus()
+	if bm == nil || cs == nil {
+		return nil, errStopped()
+	}
+	blk, err := blockAt(c, bm, req.Height)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	_, proof, err := cs.GetBTPBlockHeaderAndProof(blk, req.NetworkId, module.FlagBTPBlockProof)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.BTPData{Data: proof}, nil
+}
+
+func (s *Server) GetBTPMessages(ctx context.Context, req *pb.BTPRequest) (*pb.BTPMessages, error) {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	bm := c.BlockManager()
+	sm := c.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, errStopped()
+	}
+	blk, err := blockAt(c, bm, req.Height)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	msgs, err := btpMessagesOf(c, sm, blk.Result(), req.NetworkId)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.BTPMessages{Messages: msgs}, nil
+}
+
+func btpMessagesOf(c module.Chain, sm module.ServiceManager, result []byte, nid int64) ([][]byte, error) {
+	bd, err := sm.BTPDigestFromResult(result)
+	if err != nil || bd == nil {
+		return nil, err
+	}
+	nw, err := sm.BTPNetworkFromResult(result, nid)
+	if err != nil {
+		return nil, err
+	}
+	ntid := nw.NetworkTypeID()
+	nt, err := sm.BTPNetworkTypeFromResult(result, ntid)
+	if err != nil {
+		return nil, err
+	}
+	ntd := bd.NetworkTypeDigestFor(ntid)
+	if ntd == nil {
+		return nil, nil
+	}
+	nwd := ntd.NetworkDigestFor(nid)
+	if nwd == nil {
+		return nil, nil
+	}
+	ml, err := nwd.MessageList(c.Database(), ntm.ForUID(nt.UID()))
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([][]byte, 0, ml.Len())
+	for i := 0; i < int(ml.Len()); i++ {
+		msg, err := ml.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg.Bytes())
+	}
+	return msgs, nil
+}
+
+func (s *Server) SubscribeBlocks(req *pb.BlockStreamRequest, stream pb.Goloop_SubscribeBlocksServer) error {
+	c, err := s.chain(req.Channel)
+	if err != nil {
+		return err
+	}
+	bm := c.BlockManager()
+	if bm == nil {
+		return errStopped()
+	}
+	if err := checkBaseHeight(c, req.Height); err != nil {
+		return statusOf(err)
+	}
+	ctx := stream.Context()
+	for h := req.Height; ; h++ {
+		bch, err := bm.WaitForBlock(h)
+		if err != nil {
+			return statusOf(err)
+		}
+		var blk module.Block
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b, ok := <-bch:
+			if !ok {
+				return errStopped()
+			}
+			blk = b
+		}
+		b, err := blockOf(blk, req.Transactions)
+		if err != nil {
+			return statusOf(err)
+		}
+		if err := stream.Send(b); err != nil {
+			s.log.Debugf("fail to send block height=%d err=%+v", h, err)
+			return err
+		}
+	}
+}
+
+func New(b Backend, l log.Logger) *Server {
+	s := &Server{
+		backend: b,
+		server:  gogrpc.NewServer(),
+		log:     l,
+	}
+	pb.RegisterGoloopServer(s.server, s)
+	return s
+}
@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/grpc/pb"
+)
+
+type testTxIterator struct{}
+
+func (testTxIterator) Has() bool                             { return false }
+func (testTxIterator) Next() error                           { return nil }
+func (testTxIterator) Get() (module.Transaction, int, error) { return nil, 0, nil }
+
+type testTxList struct {
+	module.TransactionList
+}
+
+func (testTxList) Iterator() module.TransactionIterator {
+	return testTxIterator{}
+}
+
+type testBlock struct {
+	module.Block
+	height int64
+}
+
+func (b *testBlock) Version() int                               { return 2 }
+func (b *testBlock) ID() []byte                                 { return []byte{byte(b.height)} }
+func (b *testBlock) Height() int64                              { return b.height }
+func (b *testBlock) PrevID() []byte                             { return []byte{byte(b.height - 1)} }
+func (b *testBlock) Timestamp() int64                           { return b.height * 1000 }
+func (b *testBlock) Proposer() module.Address                   { return nil }
+func (b *testBlock) NormalTransactions() module.TransactionList { return testTxList{} }
+
+type testBlockManager struct {
+	module.BlockManager
+	blocks []module.Block
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height >= int64(len(bm.blocks)) {
+		return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+	}
+	return bm.blocks[height], nil
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.blocks[len(bm.blocks)-1], nil
+}
+
+func (bm *testBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	bch := make(chan module.Block, 1)
+	if height < int64(len(bm.blocks)) {
+		bch <- bm.blocks[height]
+	}
+	return bch, nil
+}
+
+type testGenesisStorage struct {
+	module.GenesisStorage
+	height int64
+}
+
+func (gs *testGenesisStorage) Height() int64 {
+	return gs.height
+}
+
+type testChain struct {
+	module.Chain
+	bm *testBlockManager
+	gs *testGenesisStorage
+}
+
+func (c *testChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *testChain) GenesisStorage() module.GenesisStorage {
+	return c.gs
+}
+
+type testBackend struct {
+	chain module.Chain
+	limit int64
+}
+
+func (b *testBackend) Chain(channel string) module.Chain {
+	if channel == "" || channel == "test" {
+		return b.chain
+	}
+	return nil
+}
+
+func (b *testBackend) CallStepLimit() int64 {
+	return b.limit
+}
+
+func newTestClient(t *testing.T, b Backend) pb.GoloopClient {
+	l := bufconn.Listen(1024 * 1024)
+	s := New(b, log.New())
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+
+	conn, err := gogrpc.DialContext(context.Background(), "bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewGoloopClient(conn)
+}
+
+func newTestChain(base int64, n int) *testChain {
+	bm := &testBlockManager{}
+	for i := 0; i < n; i++ {
+		bm.blocks = append(bm.blocks, &testBlock{height: int64(i)})
+	}
+	return &testChain{bm: bm, gs: &testGenesisStorage{height: base}}
+}
+
+func TestServer_GetBlock(t *testing.T) {
+	c := newTestChain(1, 3)
+	client := newTestClient(t, &testBackend{chain: c})
+	ctx := context.Background()
+
+	blk, err := client.GetBlock(ctx, &pb.BlockRequest{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, blk.Height)
+	assert.EqualValues(t, 2000, blk.Timestamp)
+
+	blk, err = client.GetBlock(ctx, &pb.BlockRequest{
+		Channel: "test",
+		Id:      &pb.BlockRequest_Height{Height: 1},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, blk.Height)
+	assert.EqualValues(t, []byte{0x01}, blk.Hash)
+	assert.EqualValues(t, []byte{0x00}, blk.PrevHash)
+
+	// pruned block
+	_, err = client.GetBlock(ctx, &pb.BlockRequest{
+		Id: &pb.BlockRequest_Height{Height: 0},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// not yet
+	_, err = client.GetBlock(ctx, &pb.BlockRequest{
+		Id: &pb.BlockRequest_Height{Height: 3},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetBlock(ctx, &pb.BlockRequest{Channel: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_SubscribeBlocks(t *testing.T) {
+	c := newTestChain(0, 3)
+	client := newTestClient(t, &testBackend{chain: c})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SubscribeBlocks(ctx, &pb.BlockStreamRequest{Height: 1})
+	assert.NoError(t, err)
+	for h := int64(1); h < 3; h++ {
+		blk, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.EqualValues(t, h, blk.Height)
+	}
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestServer_CallQuery(t *testing.T) {
+	s := New(&testBackend{limit: 0x100}, log.New())
+	to := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+
+	js, err := s.callQuery(&pb.CallRequest{
+		From:   from.Bytes(),
+		To:     to.Bytes(),
+		Method: "balanceOf",
+		Params: []byte(`{"_owner":"hx0000000000000000000000000000000000000002"}`),
+	})
+	assert.NoError(t, err)
+	var query map[string]interface{}
+	assert.NoError(t, json.Unmarshal(js, &query))
+	assert.Equal(t, to.String(), query["to"])
+	assert.Equal(t, from.String(), query["from"])
+	assert.Equal(t, "call", query["dataType"])
+	assert.Equal(t, "0x100", query["stepLimit"])
+	assert.Equal(t, map[string]interface{}{
+		"method": "balanceOf",
+		"params": map[string]interface{}{
+			"_owner": "hx0000000000000000000000000000000000000002",
+		},
+	}, query["data"])
+
+	_, err = s.callQuery(&pb.CallRequest{To: []byte{0x01}, Method: "name"})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	_, err = s.callQuery(&pb.CallRequest{To: to.Bytes(), Params: []byte("{")})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
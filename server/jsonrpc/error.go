@@ -3,10 +3,13 @@ package jsonrpc
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoreresult"
 )
@@ -72,6 +75,10 @@ func (c ErrorCode) String() string {
 		return "Timeout"
 	case ErrorCodeSystemTimeout:
 		return "SystemTimeout"
+	case ErrorCodeStateUnavailable:
+		return "StateUnavailable"
+	case ErrorCodeTooManyRequests:
+		return "TooManyRequests"
 	default:
 		switch {
 		case c < ErrorCodeServer && c > ErrorCodeServer-1000:
@@ -104,6 +111,14 @@ const (
 	ErrorLackOfResource     ErrorCode = -31005
 	ErrorCodeTimeout        ErrorCode = -31006
 	ErrorCodeSystemTimeout  ErrorCode = -31007
+
+	// ErrorCodeStateUnavailable is for queries on the state of the block
+	// which is not available in the node.
+	ErrorCodeStateUnavailable ErrorCode = -31008
+
+	// ErrorCodeTooManyRequests is for requests over the rate limits of the
+	// client. The response of a single request has 429 status.
+	ErrorCodeTooManyRequests ErrorCode = -31009
 )
 
 type Error struct {
@@ -141,15 +156,68 @@ func ErrInvalidParams(message ...interface{}) *Error {
 	return ErrorCodeInvalidParams.NewWithData(firstOf(message...))
 }
 
+const maxScoreErrorMessage = 256
+
+// ScoreErrorData is the data of SCORE errors, so that clients can handle
+// the failure without parsing the message.
+type ScoreErrorData struct {
+	Code       HexInt  `json:"code"`
+	RevertCode HexInt  `json:"revertCode,omitempty"`
+	Address    Address `json:"address,omitempty"`
+	Message    string  `json:"message"`
+	StepUsed   HexInt  `json:"stepUsed,omitempty"`
+	TxIndex    HexInt  `json:"txIndex,omitempty"`
+	Debug      string  `json:"debug,omitempty"`
+}
+
+// truncateMessage returns the message truncated to at most n bytes without
+// breaking a UTF-8 character.
+func truncateMessage(msg string, n int) string {
+	if len(msg) <= n {
+		return msg
+	}
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+	return msg[:n]
+}
+
+func newScoreErrorData(s module.Status, msg string) *ScoreErrorData {
+	data := &ScoreErrorData{
+		Code:    HexInt(intconv.FormatInt(int64(s))),
+		Message: truncateMessage(msg, maxScoreErrorMessage),
+	}
+	if s >= module.StatusReverted {
+		data.RevertCode = HexInt(intconv.FormatInt(int64(s - module.StatusReverted)))
+	}
+	return data
+}
+
 func ErrScore(err error, debug bool) *Error {
+	return ErrScoreWithSteps(err, nil, debug)
+}
+
+// ErrScoreWithSteps returns SCORE error for the failure with the steps
+// used until the failure.
+func ErrScoreWithSteps(err error, steps *big.Int, debug bool) *Error {
 	s, _ := scoreresult.StatusOf(err)
 	code := ErrorCodeScore - ErrorCode(s)
-	return code.Wrap(err, debug)
+	data := newScoreErrorData(s, err.Error())
+	if addr := scoreresult.AddressOf(err); addr != nil {
+		data.Address = Address(addr.String())
+	}
+	if steps != nil {
+		data.StepUsed = HexInt(intconv.FormatBigInt(steps))
+	}
+	if debug {
+		data.Debug = fmt.Sprintf("%+v", err)
+	}
+	return code.New(fmt.Sprintf("%v", err), data)
 }
 
 func ErrScoreWithStatus(s module.Status) *Error {
 	code := ErrorCodeScore - ErrorCode(s)
-	return code.New(s.String())
+	return code.New(s.String(), newScoreErrorData(s, s.String()))
 }
 
 func ErrorHandler(re *Error, c echo.Context) {
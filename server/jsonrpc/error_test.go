@@ -17,9 +17,14 @@
 package jsonrpc
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
 )
 
 func TestErrorCode_String(t *testing.T) {
@@ -44,3 +49,34 @@ func TestErrorCode_String(t *testing.T) {
 		})
 	}
 }
+
+func TestErrScore(t *testing.T) {
+	addr := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	err := scoreresult.WithAddress(scoreresult.Errorf(module.StatusReverted+2, "custom"), addr)
+	err = scoreresult.WithAddress(err, common.MustNewAddressFromString("cx02"))
+
+	je := ErrScoreWithSteps(err, big.NewInt(100), false)
+	assert.Equal(t, ErrorCodeScore-ErrorCode(module.StatusReverted+2), je.Code)
+	assert.Equal(t, &ScoreErrorData{
+		Code:       "0x22",
+		RevertCode: "0x2",
+		Address:    Address(addr.String()),
+		Message:    "custom",
+		StepUsed:   "0x64",
+	}, je.Data)
+
+	je = ErrScore(scoreresult.ErrOutOfStep, true)
+	data := je.Data.(*ScoreErrorData)
+	assert.EqualValues(t, "0xa", data.Code)
+	assert.Empty(t, data.RevertCode)
+	assert.Empty(t, data.Address)
+	assert.Empty(t, data.StepUsed)
+	assert.NotEmpty(t, data.Debug)
+}
+
+func TestTruncateMessage(t *testing.T) {
+	assert.Equal(t, "abc", truncateMessage("abc", 3))
+	assert.Equal(t, "ab", truncateMessage("abc", 2))
+	assert.Equal(t, "a", truncateMessage("aé", 2))
+	assert.Equal(t, "aé", truncateMessage("aéb", 3))
+}
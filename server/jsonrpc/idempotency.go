@@ -0,0 +1,85 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultIdempotencyKeyTTL = 10 * time.Minute
+	DefaultIdempotencyKeyMax = 10000
+)
+
+type idempotencyEntry struct {
+	key    string
+	digest []byte
+	result []byte
+	expire time.Time
+}
+
+// IdempotencyKeys keeps results of requests by client-provided keys for a
+// while, so that a retried request returns the result of the original one.
+type IdempotencyKeys struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	max     int
+	entries map[string]*idempotencyEntry
+	queue   []*idempotencyEntry
+}
+
+func NewIdempotencyKeys(ttl time.Duration, max int) *IdempotencyKeys {
+	return &IdempotencyKeys{
+		ttl:     ttl,
+		max:     max,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+func (ik *IdempotencyKeys) _purge(now time.Time) {
+	for len(ik.queue) > 0 {
+		e := ik.queue[0]
+		if len(ik.queue) <= ik.max && now.Before(e.expire) {
+			break
+		}
+		ik.queue[0] = nil
+		ik.queue = ik.queue[1:]
+		if ik.entries[e.key] == e {
+			delete(ik.entries, e.key)
+		}
+	}
+}
+
+// Get returns the result stored for the key. If the key is used for the
+// request with another digest, then it returns false for ok.
+func (ik *IdempotencyKeys) Get(key string, digest []byte) (result []byte, ok bool) {
+	ik.lock.Lock()
+	defer ik.lock.Unlock()
+
+	ik._purge(time.Now())
+	e, found := ik.entries[key]
+	if !found {
+		return nil, true
+	}
+	if !bytes.Equal(e.digest, digest) {
+		return nil, false
+	}
+	return e.result, true
+}
+
+// Put stores the result of the request with the digest for the key.
+func (ik *IdempotencyKeys) Put(key string, digest []byte, result []byte) {
+	ik.lock.Lock()
+	defer ik.lock.Unlock()
+
+	now := time.Now()
+	e := &idempotencyEntry{
+		key:    key,
+		digest: digest,
+		result: result,
+		expire: now.Add(ik.ttl),
+	}
+	ik.entries[key] = e
+	ik.queue = append(ik.queue, e)
+	ik._purge(now)
+}
@@ -0,0 +1,83 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeys_GetPut(t *testing.T) {
+	ik := NewIdempotencyKeys(time.Minute, 10)
+
+	r, ok := ik.Get("key1", []byte("digest1"))
+	assert.True(t, ok)
+	assert.Nil(t, r)
+
+	ik.Put("key1", []byte("digest1"), []byte("hash1"))
+	r, ok = ik.Get("key1", []byte("digest1"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("hash1"), r)
+
+	// the key is reused for another request
+	r, ok = ik.Get("key1", []byte("digest2"))
+	assert.False(t, ok)
+	assert.Nil(t, r)
+}
+
+func TestIdempotencyKeys_Expire(t *testing.T) {
+	ik := NewIdempotencyKeys(10*time.Millisecond, 10)
+	ik.Put("key1", []byte("digest1"), []byte("hash1"))
+
+	time.Sleep(20 * time.Millisecond)
+	r, ok := ik.Get("key1", []byte("digest2"))
+	assert.True(t, ok)
+	assert.Nil(t, r)
+	assert.Empty(t, ik.entries)
+	assert.Empty(t, ik.queue)
+}
+
+func TestIdempotencyKeys_Max(t *testing.T) {
+	ik := NewIdempotencyKeys(time.Minute, 2)
+	ik.Put("key1", []byte("digest1"), []byte("hash1"))
+	ik.Put("key2", []byte("digest2"), []byte("hash2"))
+	ik.Put("key3", []byte("digest3"), []byte("hash3"))
+
+	// the oldest one is dropped
+	r, ok := ik.Get("key1", []byte("digest1"))
+	assert.True(t, ok)
+	assert.Nil(t, r)
+	r, _ = ik.Get("key3", []byte("digest3"))
+	assert.Equal(t, []byte("hash3"), r)
+
+	// dropping the old entry keeps the new one for the same key
+	ik.Put("key2", []byte("digest4"), []byte("hash4"))
+	ik.Put("key5", []byte("digest5"), []byte("hash5"))
+	r, ok = ik.Get("key2", []byte("digest4"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("hash4"), r)
+	assert.Len(t, ik.entries, 2)
+}
+
+func TestContext_IdempotencyKey(t *testing.T) {
+	ik := NewIdempotencyKeys(time.Minute, 10)
+	newContext := func(options string) *Context {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if options != "" {
+			req.Header.Set(HeaderKeyIconOptions, options)
+		}
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		c.Set("idempotencyKeys", ik)
+		return NewContext(c)
+	}
+
+	ctx := newContext("timeout=1000,idempotencyKey=order-1")
+	assert.Equal(t, "order-1", ctx.IdempotencyKey())
+	assert.Equal(t, ik, ctx.IdempotencyKeys())
+
+	ctx = newContext("")
+	assert.Equal(t, "", ctx.IdempotencyKey())
+}
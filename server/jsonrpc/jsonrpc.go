@@ -42,6 +42,8 @@ const (
 	HeaderKeyIconOptions = "Icon-Options"
 	IconOptionsDebug     = "debug"
 	IconOptionsTimeout   = "timeout"
+
+	IconOptionsIdempotencyKey = "idempotencyKey"
 )
 
 type IconOptions map[string]string
@@ -142,6 +144,37 @@ func (ctx *Context) BatchLimit() int {
 	return batchLimit
 }
 
+// CallStepLimit returns the maximum step limit for read-only calls.
+// Zero means that there is no limit other than the limit of the chain.
+func (ctx *Context) CallStepLimit() int64 {
+	limit, _ := ctx.Get("callStepLimit").(int64)
+	return limit
+}
+
+// TraceLimit limits the size of the results of the debug methods tracing
+// transactions. Zero means no limit except MaxTimeout, which is the maximum
+// execution time requested by clients, and zero uses the default.
+type TraceLimit struct {
+	MaxEntries      int
+	MaxDepth        int
+	MaxStringLength int
+	RedactValues    bool
+	MaxTimeout      time.Duration
+}
+
+// TraceLimit returns the limits on the results of the debug methods.
+func (ctx *Context) TraceLimit() TraceLimit {
+	limit, _ := ctx.Get("traceLimit").(TraceLimit)
+	return limit
+}
+
+// Strict returns true if the request and its params are checked by
+// CheckStrict in addition to the validation.
+func (ctx *Context) Strict() bool {
+	strict, _ := ctx.Get("strict").(bool)
+	return strict
+}
+
 func (ctx *Context) GetTimeout(t time.Duration) time.Duration {
 	if v, err := ctx.opts.GetInt(IconOptionsTimeout); err != nil {
 		return t
@@ -150,6 +183,55 @@ func (ctx *Context) GetTimeout(t time.Duration) time.Duration {
 	}
 }
 
+// IdempotencyKey returns the key given by the client to identify retries of
+// the request. Empty string means that it's not given.
+func (ctx *Context) IdempotencyKey() string {
+	return ctx.opts.Get(IconOptionsIdempotencyKey)
+}
+
+// IdempotencyKeys returns the storage for the results of the requests with
+// idempotency keys. It returns nil if it's not supported.
+func (ctx *Context) IdempotencyKeys() *IdempotencyKeys {
+	ik, _ := ctx.Get("idempotencyKeys").(*IdempotencyKeys)
+	return ik
+}
+
+// MethodFilter checks the request of the method before handling it, like
+// allowed methods and quotas of the client.
+type MethodFilter interface {
+	// FilterMethod returns the error for the response if the request of
+	// the method is not allowed.
+	FilterMethod(method string) *Error
+}
+
+// MethodFilter returns the filter of requests. It returns nil if requests
+// aren't filtered.
+func (ctx *Context) MethodFilter() MethodFilter {
+	f, _ := ctx.Get("methodFilter").(MethodFilter)
+	return f
+}
+
+// Hook intercepts requests and responses of the endpoint, like custom
+// authentication, request rewriting or response shaping. Hooks are called
+// in order for requests, and in reverse order for responses.
+type Hook interface {
+	// OnRequest is called before handling the request, and it may change
+	// Method and Params of the request. The request is rejected with the
+	// error if it returns one.
+	OnRequest(ctx *Context, req *Request) *Error
+
+	// OnResponse is called with the response of the request, and it may
+	// change the response. It's not called for notifications and the
+	// requests rejected by hooks.
+	OnResponse(ctx *Context, req *Request, resp *Response)
+}
+
+// Hooks returns hooks of the endpoint.
+func (ctx *Context) Hooks() []Hook {
+	hooks, _ := ctx.Get("hooks").([]Hook)
+	return hooks
+}
+
 func (ctx *Context) Validator() echo.Validator {
 	return ctx.Echo().Validator
 }
@@ -165,6 +247,7 @@ func (ctx *Context) MetricContext() context.Context {
 type Params struct {
 	rawMessage json.RawMessage
 	validator  echo.Validator
+	strict     bool
 }
 
 func (p *Params) Convert(v interface{}) error {
@@ -185,6 +268,11 @@ func (p *Params) Convert(v interface{}) error {
 			return nil
 		}
 	} else {
+		if p.strict {
+			if err := CheckStrict(p.rawMessage, v); err != nil {
+				return err
+			}
+		}
 		rve := rv.Elem()
 		if rve.Kind() == reflect.Ptr {
 			value := reflect.New(rve.Type().Elem())
@@ -18,6 +18,7 @@ type MethodRepository struct {
 	mtx     sync.RWMutex
 	methods map[string]Handler
 	allowed map[string]bool
+	schemas map[string]*methodSchema
 	v       *Validator
 	mtr     *metric.JsonrpcMetric
 }
@@ -26,6 +27,7 @@ func NewMethodRepository(mtr *metric.JsonrpcMetric) *MethodRepository {
 	return &MethodRepository{
 		methods: make(map[string]Handler),
 		allowed: make(map[string]bool),
+		schemas: make(map[string]*methodSchema),
 		v:       NewValidator(),
 		mtr:     mtr,
 	}
@@ -89,11 +91,42 @@ func (mr *MethodRepository) handle(ctx *Context, raw json.RawMessage) *Response
 		return resp
 	}
 	resp.ID = req.ID
+	strict := ctx.Strict()
+	if strict {
+		if err := CheckStrict(raw, req); err != nil {
+			resp.Error = ErrorCodeInvalidRequest.Wrap(err, debug)
+			return resp
+		}
+	}
 	if req.Method == nil {
 		err := errors.New(ValidateFailPrefix + "required('method')")
 		resp.Error = ErrorCodeInvalidRequest.Wrap(err, debug)
 		return resp
 	}
+
+	hooks := ctx.Hooks()
+	for _, hook := range hooks {
+		if err := hook.OnRequest(ctx, req); err != nil {
+			if req.ID == nil {
+				return nil
+			}
+			resp.Error = err
+			return resp
+		}
+	}
+	if r := mr.handleRequest(ctx, req, resp); r != nil {
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i].OnResponse(ctx, req, r)
+		}
+		return r
+	}
+	return nil
+}
+
+// handleRequest handles the request with the method, and returns the
+// response. It returns nil for notifications.
+func (mr *MethodRepository) handleRequest(ctx *Context, req *Request, resp *Response) *Response {
+	debug := ctx.IncludeDebug()
 	method := mr.GetMethod(*req.Method)
 	if method == nil {
 		resp.Error = ErrMethodNotFound()
@@ -107,9 +140,20 @@ func (mr *MethodRepository) handle(ctx *Context, raw json.RawMessage) *Response
 		return nil
 	}
 
+	if f := ctx.MethodFilter(); f != nil {
+		if err := f.FilterMethod(*req.Method); err != nil {
+			resp.Error = err
+			if req.ID == nil {
+				return nil
+			}
+			return resp
+		}
+	}
+
 	p := &Params{
 		rawMessage: req.Params,
 		validator:  mr.v,
+		strict:     ctx.Strict(),
 	}
 	res, err := method(ctx, p)
 	if err != nil {
@@ -131,6 +175,30 @@ func (mr *MethodRepository) handle(ctx *Context, raw json.RawMessage) *Response
 	return resp
 }
 
+// handleBatchEntry handles the entry of the batch. Entries are handled in
+// their own goroutines out of the middleware recovering panics, so it
+// recovers the panic of the handler into the error of the entry.
+func (mr *MethodRepository) handleBatchEntry(ctx *Context, raw json.RawMessage) (resp *Response) {
+	defer func() {
+		if r := recover(); r != nil {
+			var req struct {
+				ID interface{} `json:"id"`
+			}
+			_ = json.Unmarshal(raw, &req)
+			if req.ID == nil {
+				resp = nil
+				return
+			}
+			resp = &Response{
+				Version: Version,
+				ID:      req.ID,
+				Error:   ErrorCodeInternal.Wrap(errors.Errorf("panic in handler: %v", r), ctx.IncludeDebug()),
+			}
+		}
+	}()
+	return mr.handle(ctx, raw)
+}
+
 func (mr *MethodRepository) Handle(c echo.Context) error {
 	ctx := NewContext(c)
 	raw := c.Get("raw").(json.RawMessage)
@@ -158,8 +226,8 @@ func (mr *MethodRepository) Handle(c echo.Context) error {
 		rs := make([]*Response, len(raws))
 		for i, r := range raws {
 			go func(r json.RawMessage, rs []*Response, i int) {
-				rs[i] = mr.handle(ctx, r)
-				wg.Done()
+				defer wg.Done()
+				rs[i] = mr.handleBatchEntry(ctx, r)
 			}(r, rs, i)
 		}
 		wg.Wait()
@@ -170,11 +238,18 @@ func (mr *MethodRepository) Handle(c echo.Context) error {
 				resps = append(resps, r)
 			}
 		}
+		// nothing is returned for the batch of notifications
+		if len(resps) == 0 {
+			return c.NoContent(http.StatusOK)
+		}
 		return c.JSON(http.StatusOK, resps)
 	} else {
 		resp := mr.handle(ctx, raw)
 		if resp != nil {
 			if resp.Error != nil {
+				if resp.Error.Code == ErrorCodeTooManyRequests {
+					return c.JSON(http.StatusTooManyRequests, resp)
+				}
 				return c.JSON(http.StatusBadRequest, resp)
 			} else {
 				return c.JSON(http.StatusOK, resp)
@@ -190,11 +190,101 @@ func TestMethodRepository(t *testing.T) {
 	}
 	invokeBatchTest(t, mr, mixedBatch, mixedBatchResp)
 
+	notificationBatch := "[" + notification + "," + notification + "]"
+	invokeTest(t, mr, notificationBatch, "", http.StatusOK)
+
+	mr.RegisterMethod("panic", func(ctx *Context, params *Params) (interface{}, error) {
+		panic("unexpected")
+	})
+	panicBatch := []string{
+		`{"jsonrpc":"2.0","method":"panic","id":"1001"}`,
+		`{"jsonrpc":"2.0","method":"panic"}`,
+		`{"jsonrpc":"2.0","method":"noArgs","id":"1002"}`,
+	}
+	panicBatchResp := []string{
+		`{"jsonrpc":"2.0","error":{"code":-32603,"message":"InternalError: panic in handler: unexpected"},"id":"1001"}`,
+		`{"jsonrpc":"2.0","result":"noArgs","id":"1002"}`,
+	}
+	invokeBatchTest(t, mr, panicBatch, panicBatchResp)
+
 	exceedLimitBatch := "[" + strings.Repeat(","+notification, DefaultBatchLimit+1)[1:] + "]"
 	exceedLimitBatchResp := `{"jsonrpc":"2.0","error":{"code":-32600,"message":"InvalidRequest","data":"too many request"},"id":null}`
 	invokeTest(t, mr, exceedLimitBatch, exceedLimitBatchResp, http.StatusServiceUnavailable)
 }
 
+type testMethodFilter map[string]bool
+
+func (f testMethodFilter) FilterMethod(method string) *Error {
+	if !f[method] {
+		return ErrorCodeMethodNotFound.New("MethodNotAllowed")
+	}
+	return nil
+}
+
+func TestMethodRepository_MethodFilter(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+	mr.RegisterMethod("noArgs", noArgs)
+
+	req := "[" + strings.Join([]string{
+		`{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"},"id":"1001"}`,
+		`{"jsonrpc":"2.0","method":"noArgs","id":"1002"}`,
+	}, ",") + "]"
+	c, rec, err := prepare(req)
+	assert.NoError(t, err)
+	c.Set("methodFilter", testMethodFilter{"hello": true})
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "["+strings.Join([]string{
+		`{"jsonrpc":"2.0","result":"hello, icon","id":"1001"}`,
+		`{"jsonrpc":"2.0","error":{"code":-32601,"message":"MethodNotFound: MethodNotAllowed"},"id":"1002"}`,
+	}, ",")+"]\n", rec.Body.String())
+}
+
+type testHook struct {
+	token string
+}
+
+func (h *testHook) OnRequest(ctx *Context, req *Request) *Error {
+	if ctx.Request().Header.Get("X-Token") != h.token {
+		return ErrorCodeInvalidRequest.New("InvalidToken")
+	}
+	if *req.Method == "hi" {
+		method := "hello"
+		req.Method = &method
+	}
+	return nil
+}
+
+func (h *testHook) OnResponse(ctx *Context, req *Request, resp *Response) {
+	if s, ok := resp.Result.(string); ok {
+		resp.Result = strings.ToUpper(s)
+	}
+}
+
+func TestMethodRepository_Hooks(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+
+	req := `{"jsonrpc":"2.0","method":"hi","params":{"name":"icon"},"id":"1001"}`
+	c, rec, err := prepare(req)
+	assert.NoError(t, err)
+	c.Set("hooks", []Hook{&testHook{token: "token1"}})
+	c.Request().Header.Set("X-Token", "token1")
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"HELLO, ICON","id":"1001"}`+"\n", rec.Body.String())
+
+	c, rec, err = prepare(req)
+	assert.NoError(t, err)
+	c.Set("hooks", []Hook{&testHook{token: "token1"}})
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"InvalidRequest: InvalidToken"},"id":"1001"}`+"\n", rec.Body.String())
+}
+
 type HelloParam struct {
 	Name string `json:"name" validate:"required"`
 }
@@ -214,3 +304,32 @@ func noArgs(ctx *Context, params *Params) (interface{}, error) {
 	}
 	return "noArgs", nil
 }
+
+func TestMethodRepository_HandleBatchEntry(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("noArgs", noArgs)
+	mr.RegisterMethod("panic", func(ctx *Context, params *Params) (interface{}, error) {
+		panic("unexpected")
+	})
+	c, _, err := prepare(`{}`)
+	assert.NoError(t, err)
+	ctx := NewContext(c)
+
+	// the panic is returned with the id of the request
+	resp := mr.handleBatchEntry(ctx, json.RawMessage(`{"jsonrpc":"2.0","method":"panic","id":7}`))
+	assert.NotNil(t, resp)
+	assert.Equal(t, float64(7), resp.ID)
+	assert.Equal(t, ErrorCodeInternal, resp.Error.Code)
+	assert.Nil(t, resp.Result)
+
+	// nothing for the notification
+	resp = mr.handleBatchEntry(ctx, json.RawMessage(`{"jsonrpc":"2.0","method":"panic"}`))
+	assert.Nil(t, resp)
+
+	resp = mr.handleBatchEntry(ctx, json.RawMessage(`{"jsonrpc":"2.0","method":"noArgs","id":"8"}`))
+	assert.NotNil(t, resp)
+	assert.Equal(t, "8", resp.ID)
+	assert.Equal(t, "noArgs", resp.Result)
+	assert.Nil(t, resp.Error)
+}
@@ -0,0 +1,146 @@
+package jsonrpc
+
+import (
+	"reflect"
+	"sort"
+)
+
+const (
+	OpenRPCVersion = "1.2.6"
+	DiscoverMethod = "rpc.discover"
+	ParamsByName   = "by-name"
+	resultName     = "result"
+)
+
+type methodSchema struct {
+	params reflect.Type
+	result interface{}
+}
+
+// ContentDescriptor describes params and results of methods in the OpenRPC
+// document.
+type ContentDescriptor struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema"`
+}
+
+type OpenRPCMethod struct {
+	Name           string               `json:"name"`
+	ParamStructure string               `json:"paramStructure"`
+	Params         []*ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor   `json:"result"`
+}
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCDocument is the OpenRPC document of the methods of the repository.
+type OpenRPCDocument struct {
+	OpenRPC string           `json:"openrpc"`
+	Info    OpenRPCInfo      `json:"info"`
+	Methods []*OpenRPCMethod `json:"methods"`
+}
+
+// SetSchema sets the types of params and the result of the method. Params
+// should be the struct converted by Params.Convert, or nil if the method
+// has no params. Result may be a Schema, a value of the type of the result,
+// or nil for any value.
+func (mr *MethodRepository) SetSchema(method string, params interface{}, result interface{}) {
+	mr.mtx.Lock()
+	defer mr.mtx.Unlock()
+
+	var pt reflect.Type
+	if params != nil {
+		pt = reflect.TypeOf(params)
+	}
+	mr.schemas[method] = &methodSchema{pt, result}
+}
+
+// HasSchema returns true if the schema of the method is set.
+func (mr *MethodRepository) HasSchema(method string) bool {
+	mr.mtx.RLock()
+	defer mr.mtx.RUnlock()
+
+	_, ok := mr.schemas[method]
+	return ok
+}
+
+// Methods returns the names of the registered methods in order.
+func (mr *MethodRepository) Methods() []string {
+	mr.mtx.RLock()
+	defer mr.mtx.RUnlock()
+
+	names := make([]string, 0, len(mr.methods))
+	for name := range mr.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (mr *MethodRepository) resultSchema(result interface{}) Schema {
+	switch r := result.(type) {
+	case nil:
+		return Schema{}
+	case Schema:
+		return r
+	default:
+		return mr.v.SchemaOf(reflect.TypeOf(result), "")
+	}
+}
+
+// OpenRPC returns the OpenRPC document generated from the registered
+// methods, their schemas and the validation rules. Methods without schema
+// have no params and any result in the document.
+func (mr *MethodRepository) OpenRPC(title, version string) *OpenRPCDocument {
+	doc := &OpenRPCDocument{
+		OpenRPC: OpenRPCVersion,
+		Info:    OpenRPCInfo{title, version},
+	}
+	for _, name := range mr.Methods() {
+		mr.mtx.RLock()
+		ms := mr.schemas[name]
+		mr.mtx.RUnlock()
+
+		m := &OpenRPCMethod{
+			Name:           name,
+			ParamStructure: ParamsByName,
+			Params:         []*ContentDescriptor{},
+			Result:         &ContentDescriptor{Name: resultName, Schema: Schema{}},
+		}
+		if ms != nil {
+			if ms.params != nil {
+				pt := ms.params
+				for pt.Kind() == reflect.Ptr {
+					pt = pt.Elem()
+				}
+				for _, p := range mr.v.propertiesOf(pt) {
+					m.Params = append(m.Params, &ContentDescriptor{
+						Name:     p.Name,
+						Required: p.Required,
+						Schema:   p.Schema,
+					})
+				}
+			}
+			m.Result.Schema = mr.resultSchema(ms.result)
+		}
+		doc.Methods = append(doc.Methods, m)
+	}
+	return doc
+}
+
+// RegisterDiscovery registers the method returning the OpenRPC document of
+// the repository.
+func (mr *MethodRepository) RegisterDiscovery(title, version string) {
+	mr.RegisterMethod(DiscoverMethod, func(ctx *Context, params *Params) (interface{}, error) {
+		var param struct{}
+		if err := params.Convert(&param); err != nil {
+			return nil, ErrorCodeInvalidParams.Wrap(err, ctx.IncludeDebug())
+		}
+		return mr.OpenRPC(title, version), nil
+	})
+	mr.SetSchema(DiscoverMethod, nil, Schema{"type": "object"})
+}
@@ -0,0 +1,79 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type openRPCParam struct {
+	Height    HexInt    `json:"height,omitempty" validate:"optional,t_int"`
+	Hash      HexBytes  `json:"hash" validate:"required,t_hash"`
+	Addresses []Address `json:"addresses" validate:"gt=0,dive,t_addr_eoa"`
+	Kind      string    `json:"kind,omitempty" validate:"optional,foo|bar"`
+	Data      interface{}
+}
+
+func TestMethodRepository_OpenRPC(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.Validator().RegisterSchema("foo", Schema{"const": "foo"})
+	mr.Validator().RegisterSchema("bar", Schema{"const": "bar"})
+
+	mr.RegisterMethod("hello", hello)
+	mr.RegisterMethod("noArgs", noArgs)
+	mr.RegisterMethod("test", noArgs)
+	mr.SetSchema("hello", HelloParam{}, "")
+	mr.SetSchema("test", openRPCParam{}, HexInt(""))
+	mr.RegisterDiscovery("Test", "1")
+
+	doc := mr.OpenRPC("Test", "1")
+	assert.Equal(t, OpenRPCVersion, doc.OpenRPC)
+	assert.Equal(t, OpenRPCInfo{"Test", "1"}, doc.Info)
+
+	var names []string
+	for _, m := range doc.Methods {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"hello", "noArgs", DiscoverMethod, "test"}, names)
+
+	hello := doc.Methods[0]
+	assert.Equal(t, []*ContentDescriptor{
+		{Name: "name", Required: true, Schema: Schema{"type": "string"}},
+	}, hello.Params)
+	assert.Equal(t, Schema{"type": "string"}, hello.Result.Schema)
+
+	// methods without schema
+	assert.Empty(t, doc.Methods[1].Params)
+	assert.Equal(t, Schema{}, doc.Methods[1].Result.Schema)
+
+	test := doc.Methods[3]
+	assert.Equal(t, []*ContentDescriptor{
+		{Name: "height", Schema: Schema{"type": "string", "pattern": hexInt.String()}},
+		{Name: "hash", Required: true, Schema: Schema{"type": "string", "pattern": hashRegex.String()}},
+		{Name: "addresses", Required: true, Schema: Schema{
+			"type":     "array",
+			"items":    Schema{"type": "string", "pattern": eoaAddressRegex.String()},
+			"minItems": 1,
+		}},
+		{Name: "kind", Schema: Schema{"anyOf": []interface{}{
+			Schema{"const": "foo"}, Schema{"const": "bar"},
+		}}},
+		{Name: "Data", Schema: Schema{}},
+	}, test.Params)
+	assert.Equal(t, Schema{"type": "string", "pattern": hexInt.String()}, test.Result.Schema)
+
+	c, rec, err := prepare(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)
+	assert.NoError(t, err)
+	assert.NoError(t, mr.Handle(c))
+	var resp struct {
+		Result *OpenRPCDocument `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	if assert.NotNil(t, resp.Result) {
+		assert.Len(t, resp.Result.Methods, len(names))
+	}
+}
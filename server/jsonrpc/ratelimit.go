@@ -0,0 +1,174 @@
+package jsonrpc
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+// DefaultExpensiveMethods are methods replaying transactions of the block
+// or the pool, which are limited by RateLimit.Expensive as well.
+var DefaultExpensiveMethods = []string{
+	"debug_getTrace",
+	"debug_getStateDiff",
+	"debug_simulateProposal",
+	"rosetta_getTrace",
+}
+
+const rateBucketExpire = time.Minute
+
+// RateLimit is the number of requests per second allowed for a client.
+// Clients with API keys are limited by PerKey, and others are limited by
+// PerIP. Expensive is for expensive methods of each client. Zero is
+// unlimited.
+type RateLimit struct {
+	PerIP     float64 `json:"perIP"`
+	PerKey    float64 `json:"perKey"`
+	Expensive float64 `json:"expensive"`
+}
+
+type rateBucket struct {
+	limiter *rate.Limiter
+	used    time.Time
+}
+
+// RateLimiter limits requests of clients with token buckets.
+type RateLimiter struct {
+	lock      sync.Mutex
+	limit     RateLimit
+	expensive map[string]bool
+	buckets   map[string]*rateBucket
+	swept     time.Time
+	mtr       *metric.RateLimitMetric
+}
+
+func NewRateLimiter(limit RateLimit, expensive []string) *RateLimiter {
+	rl := &RateLimiter{
+		limit:     limit,
+		expensive: make(map[string]bool),
+		buckets:   make(map[string]*rateBucket),
+		mtr:       metric.NewRateLimitMetric(metric.DefaultMetricContext()),
+	}
+	for _, m := range expensive {
+		rl.expensive[m] = true
+	}
+	return rl
+}
+
+func (rl *RateLimiter) Limit() RateLimit {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	return rl.limit
+}
+
+// SetLimit changes limits. Buckets of clients are reset.
+func (rl *RateLimiter) SetLimit(limit RateLimit) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.limit = limit
+	rl.buckets = make(map[string]*rateBucket)
+}
+
+// _sweep removes buckets not used for a while, which are full again.
+func (rl *RateLimiter) _sweep(now time.Time) {
+	if now.Sub(rl.swept) < rateBucketExpire {
+		return
+	}
+	rl.swept = now
+	for id, b := range rl.buckets {
+		if now.Sub(b.used) >= rateBucketExpire {
+			delete(rl.buckets, id)
+		}
+	}
+}
+
+func (rl *RateLimiter) _allow(id string, r float64, now time.Time) bool {
+	if r <= 0 {
+		return true
+	}
+	b, ok := rl.buckets[id]
+	if !ok {
+		b = &rateBucket{
+			limiter: rate.NewLimiter(rate.Limit(r), int(math.Ceil(r))),
+		}
+		rl.buckets[id] = b
+	}
+	b.used = now
+	return b.limiter.AllowN(now, 1)
+}
+
+func (rl *RateLimiter) check(ip, key, method string) (string, float64) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	now := time.Now()
+	rl._sweep(now)
+	id, limit, r := "ip:"+ip, metric.RateLimitIP, rl.limit.PerIP
+	if key != "" {
+		id, limit, r = "key:"+key, metric.RateLimitKey, rl.limit.PerKey
+	}
+	if rl.expensive[method] && !rl._allow(id+":expensive", rl.limit.Expensive, now) {
+		return metric.RateLimitExpensive, rl.limit.Expensive
+	}
+	if !rl._allow(id, r, now) {
+		return limit, r
+	}
+	return "", 0
+}
+
+// Allow checks the request of the method from the client of the IP address
+// or the API key. It returns the error for the response if the request is
+// over the limit.
+func (rl *RateLimiter) Allow(ip, key, method string) *Error {
+	limit, r := rl.check(ip, key, method)
+	if limit == "" {
+		return nil
+	}
+	rl.mtr.OnReject(limit, method)
+	return ErrorCodeTooManyRequests.Errorf("RateLimited(limit=%s,rate=%v)", limit, r)
+}
+
+type clientFilter struct {
+	rl  *RateLimiter
+	ip  string
+	key string
+}
+
+func (f *clientFilter) FilterMethod(method string) *Error {
+	return f.rl.Allow(f.ip, f.key, method)
+}
+
+// MethodFilters applies filters in order until one of them rejects the
+// request.
+type MethodFilters []MethodFilter
+
+func (fs MethodFilters) FilterMethod(method string) *Error {
+	for _, f := range fs {
+		if err := f.FilterMethod(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Middleware adds the filter checking rate limits of the client after the
+// filter set by preceding middlewares. The API key of the client is given
+// by "apiKey" of the context.
+func (rl *RateLimiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, _ := c.Get("apiKey").(string)
+			var f MethodFilter = &clientFilter{rl: rl, ip: c.RealIP(), key: key}
+			if prev, ok := c.Get("methodFilter").(MethodFilter); ok && prev != nil {
+				f = MethodFilters{prev, f}
+			}
+			c.Set("methodFilter", f)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{PerIP: 2, PerKey: 3, Expensive: 1}, []string{"debug_getTrace"})
+
+	assert.Nil(t, rl.Allow("10.0.0.1", "", "icx_getBalance"))
+	assert.Nil(t, rl.Allow("10.0.0.1", "", "icx_getBalance"))
+	err := rl.Allow("10.0.0.1", "", "icx_getBalance")
+	assert.Equal(t, ErrorCodeTooManyRequests, err.Code)
+	assert.Nil(t, rl.Allow("10.0.0.2", "", "icx_getBalance"))
+
+	// keys are limited regardless of the IP address
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, rl.Allow("10.0.0.1", "alice", "icx_getBalance"))
+	}
+	assert.NotNil(t, rl.Allow("10.0.0.3", "alice", "icx_getBalance"))
+
+	assert.Nil(t, rl.Allow("10.0.0.4", "", "debug_getTrace"))
+	err = rl.Allow("10.0.0.4", "", "debug_getTrace")
+	assert.Equal(t, ErrorCodeTooManyRequests, err.Code)
+	assert.Nil(t, rl.Allow("10.0.0.4", "", "icx_getBalance"))
+
+	rl.SetLimit(RateLimit{})
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, rl.Allow("10.0.0.1", "", "debug_getTrace"))
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+	mr.RegisterMethod("noArgs", noArgs)
+	rl := NewRateLimiter(RateLimit{PerIP: 1}, nil)
+
+	handle := func(req string, filter MethodFilter) (int, string) {
+		c, rec, err := prepare(req)
+		assert.NoError(t, err)
+		if filter != nil {
+			c.Set("methodFilter", filter)
+		}
+		assert.NoError(t, rl.Middleware()(mr.Handle)(c))
+		return rec.Code, rec.Body.String()
+	}
+
+	req := `{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"},"id":"1001"}`
+	code, _ := handle(req, nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, body := handle(req, nil)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	assert.Contains(t, body, `"code":-31009`)
+
+	// the preceding filter is applied first
+	rl.SetLimit(RateLimit{PerIP: 1})
+	code, body = handle(`{"jsonrpc":"2.0","method":"noArgs","id":"1002"}`, testMethodFilter{"hello": true})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, `"code":-32601`)
+	code, _ = handle(req, testMethodFilter{"hello": true})
+	assert.Equal(t, http.StatusOK, code)
+}
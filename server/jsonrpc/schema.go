@@ -0,0 +1,210 @@
+package jsonrpc
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema is the JSON schema of a value.
+type Schema map[string]interface{}
+
+// with returns the copy of the schema with the keyword.
+func (s Schema) with(key string, value interface{}) Schema {
+	ns := make(Schema, len(s)+1)
+	for k, v := range s {
+		ns[k] = v
+	}
+	ns[key] = value
+	return ns
+}
+
+func patternSchema(pattern string) Schema {
+	return Schema{"type": "string", "pattern": pattern}
+}
+
+func (v *Validator) registerDefaultSchemas() {
+	v.RegisterSchema("t_addr_eoa", patternSchema(eoaAddressRegex.String()))
+	v.RegisterSchema("t_addr_score", patternSchema(scoreAddressRegex.String()))
+	v.RegisterSchema("t_int", patternSchema(hexInt.String()))
+	v.RegisterSchema("t_hash", patternSchema(hashRegex.String()))
+	v.RegisterSchema("t_rhash", patternSchema(rosettaHashRegex.String()))
+	v.RegisterSchema("t_bin_data", patternSchema(binDataRegex.String()))
+	v.RegisterSchema("t_sig", Schema{"type": "string", "contentEncoding": "base64"})
+	v.RegisterSchema("t_addr", patternSchema(addressRegex.String()))
+}
+
+// RegisterSchema sets the schema of the values satisfying the validation
+// tag, so that the schemas of params reflect the validation rules.
+func (v *Validator) RegisterSchema(tag string, s Schema) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.schemas[tag] = s
+}
+
+func (v *Validator) schemaOfTag(tag string) Schema {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	alts := strings.Split(tag, "|")
+	schemas := make([]interface{}, 0, len(alts))
+	for _, alt := range alts {
+		s, ok := v.schemas[alt]
+		if !ok {
+			return nil
+		}
+		schemas = append(schemas, s)
+	}
+	if len(schemas) == 1 {
+		return schemas[0].(Schema)
+	}
+	return Schema{"anyOf": schemas}
+}
+
+// SchemaOf returns the schema of the values of the type validated by the
+// validation tag.
+func (v *Validator) SchemaOf(t reflect.Type, tag string) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var tags, itemTag string
+	if idx := strings.Index(tag, "dive"); idx >= 0 {
+		tags, itemTag = tag[:idx], strings.TrimPrefix(tag[idx+len("dive"):], ",")
+	} else {
+		tags = tag
+	}
+
+	var s Schema
+	switch t {
+	case typeRawMessage:
+		s = Schema{}
+	case typeHexInt:
+		s = v.schemaOfTag("t_int")
+	case typeHexBytes:
+		s = v.schemaOfTag("t_bin_data")
+	case typeAddress:
+		s = v.schemaOfTag("t_addr")
+	default:
+		s = v.schemaOfKind(t, itemTag)
+	}
+	for _, tg := range strings.Split(tags, ",") {
+		if ts := v.schemaOfTag(tg); ts != nil {
+			s = ts
+		} else if strings.HasPrefix(tg, "gt=") || strings.HasPrefix(tg, "gte=") {
+			if s["type"] != "array" {
+				continue
+			}
+			n, err := strconv.Atoi(tg[strings.Index(tg, "=")+1:])
+			if err != nil {
+				continue
+			}
+			if tg[2] == '=' {
+				n += 1
+			}
+			s = s.with("minItems", n)
+		}
+	}
+	return s
+}
+
+func (v *Validator) schemaOfKind(t reflect.Type, itemTag string) Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": v.SchemaOf(t.Elem(), itemTag)}
+	case reflect.Map:
+		return Schema{
+			"type":                 "object",
+			"additionalProperties": v.SchemaOf(t.Elem(), itemTag),
+		}
+	case reflect.Struct:
+		props := make(Schema)
+		var required []string
+		for _, p := range v.propertiesOf(t) {
+			props[p.Name] = p.Schema
+			if p.Required {
+				required = append(required, p.Name)
+			}
+		}
+		s := Schema{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	default:
+		return Schema{}
+	}
+}
+
+func hasTag(tags string, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Property is a field of an object in params.
+type Property struct {
+	Name     string
+	Required bool
+	Schema   Schema
+}
+
+// propertiesOf returns the properties of the struct in order including the
+// ones of embedded structs.
+func (v *Validator) propertiesOf(t reflect.Type) []*Property {
+	var props []*Property
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("json")
+		if idx := strings.Index(name, ","); idx >= 0 {
+			name = name[:idx]
+		}
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				props = append(props, v.propertiesOf(ft)...)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		tag := sf.Tag.Get("validate")
+		schema := v.SchemaOf(sf.Type, tag)
+		_, nonEmpty := schema["minItems"]
+		props = append(props, &Property{
+			Name:     name,
+			Required: hasTag(tag, "required") || nonEmpty,
+			Schema:   schema,
+		})
+	}
+	return props
+}
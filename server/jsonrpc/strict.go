@@ -0,0 +1,184 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	StrictFailPrefix = "fail to check strictly, "
+)
+
+var (
+	typeHexInt     = reflect.TypeOf(HexInt(""))
+	typeHexBytes   = reflect.TypeOf(HexBytes(""))
+	typeAddress    = reflect.TypeOf(Address(""))
+	typeRawMessage = reflect.TypeOf(json.RawMessage(nil))
+	addressRegex   = regexp.MustCompile("^[hc]x[0-9a-f]{40}$")
+)
+
+// StrictError is the problem found by CheckStrict. Pointer is the JSON
+// pointer(RFC 6901) to the value having the problem.
+type StrictError struct {
+	Pointer string
+	Reason  string
+}
+
+func (e *StrictError) Error() string {
+	return fmt.Sprintf(StrictFailPrefix+"%s at '%s'", e.Reason, e.Pointer)
+}
+
+// CheckStrict checks the JSON data for the type of v more strictly than
+// json.Unmarshal. It rejects the fields unknown to the type even if they
+// match the known ones case-insensitively, duplicate fields and malformed
+// values of HexInt, HexBytes and Address. Values for interface{} and
+// json.RawMessage are not checked.
+func CheckStrict(data []byte, v interface{}) error {
+	return checkStrict(data, reflect.TypeOf(v), "", "")
+}
+
+func escapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+func isNull(data []byte) bool {
+	return string(bytes.TrimSpace(data)) == "null"
+}
+
+func checkStrict(data []byte, t reflect.Type, tag string, ptr string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isNull(data) || t == typeRawMessage || t.Kind() == reflect.Interface {
+		return nil
+	}
+	switch t {
+	case typeHexInt:
+		return checkStrictString(data, hexInt, "malformed hex integer", ptr)
+	case typeHexBytes:
+		if strings.Contains(tag, "t_rhash") {
+			return checkStrictString(data, rosettaHashRegex, "malformed hex bytes", ptr)
+		}
+		return checkStrictString(data, binDataRegex, "malformed hex bytes", ptr)
+	case typeAddress:
+		return checkStrictString(data, addressRegex, "malformed address", ptr)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return checkStrictStruct(data, t, ptr)
+	case reflect.Map:
+		return checkStrictObject(data, ptr, func(key string, value []byte, p string) error {
+			return checkStrict(value, t.Elem(), "", p)
+		})
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return &StrictError{ptr, "not an array"}
+		}
+		for i, value := range values {
+			if err := checkStrict(value, t.Elem(), tag, ptr+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkStrictString(data []byte, re *regexp.Regexp, reason string, ptr string) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return &StrictError{ptr, "not a string"}
+	}
+	if !re.MatchString(s) {
+		return &StrictError{ptr, reason}
+	}
+	return nil
+}
+
+type strictField struct {
+	t   reflect.Type
+	tag string
+}
+
+// strictFields returns the fields of the struct by their names in JSON
+// including the ones of embedded structs.
+func strictFields(t reflect.Type, fields map[string]strictField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("json")
+		if idx := strings.Index(name, ","); idx >= 0 {
+			name = name[:idx]
+		}
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				strictFields(ft, fields)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = strictField{sf.Type, sf.Tag.Get("validate")}
+	}
+}
+
+func checkStrictStruct(data []byte, t reflect.Type, ptr string) error {
+	fields := make(map[string]strictField)
+	strictFields(t, fields)
+	return checkStrictObject(data, ptr, func(key string, value []byte, p string) error {
+		f, ok := fields[key]
+		if !ok {
+			return &StrictError{p, "unknown field"}
+		}
+		return checkStrict(value, f.t, f.tag, p)
+	})
+}
+
+// checkStrictObject calls the function for each field of the object in
+// order. It rejects duplicate fields.
+func checkStrictObject(data []byte, ptr string, fn func(key string, value []byte, p string) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tk, err := dec.Token(); err != nil || tk != json.Delim('{') {
+		return &StrictError{ptr, "not an object"}
+	}
+	keys := make(map[string]bool)
+	for dec.More() {
+		tk, err := dec.Token()
+		if err != nil {
+			return &StrictError{ptr, "not an object"}
+		}
+		key := tk.(string)
+		p := ptr + "/" + escapePointer(key)
+		if keys[key] {
+			return &StrictError{p, "duplicate field"}
+		}
+		keys[key] = true
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return &StrictError{p, "invalid value"}
+		}
+		if err := fn(key, value, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
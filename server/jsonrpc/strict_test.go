@@ -0,0 +1,85 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type strictInner struct {
+	Height HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	Owner  Address `json:"owner,omitempty"`
+}
+
+type strictParam struct {
+	strictInner
+	Hash   HexBytes          `json:"hash" validate:"required,t_hash"`
+	Events []HexInt          `json:"events,omitempty"`
+	Inners []*strictInner    `json:"inners,omitempty"`
+	Meta   map[string]HexInt `json:"meta,omitempty"`
+	Data   interface{}       `json:"data,omitempty"`
+}
+
+func TestCheckStrict(t *testing.T) {
+	const hash = `"0xb5f908339f447ca97525a3eb8c3e450e767ffe3e242df3f87e4af4295e1277f3"`
+	cases := []struct {
+		name    string
+		data    string
+		pointer string
+		reason  string
+	}{
+		{"Valid", `{"hash":` + hash + `,"height":"0x10","events":["0x0","0x1"],"inners":[{"owner":"hx1111111111111111111111111111111111111111"}],"meta":{"a/b":"0x1"},"data":{"any":"thing"}}`, "", ""},
+		{"Null", `null`, "", ""},
+		{"UnknownField", `{"hash":` + hash + `,"foo":1}`, "/foo", "unknown field"},
+		{"CaseMismatch", `{"Hash":` + hash + `}`, "/Hash", "unknown field"},
+		{"DuplicateField", `{"hash":` + hash + `,"hash":` + hash + `}`, "/hash", "duplicate field"},
+		{"EmbeddedHex", `{"hash":` + hash + `,"height":"16"}`, "/height", "malformed hex integer"},
+		{"HexInArray", `{"hash":` + hash + `,"events":["0x1","0x01"]}`, "/events/1", "malformed hex integer"},
+		{"NestedUnknown", `{"hash":` + hash + `,"inners":[{},{"heigth":"0x1"}]}`, "/inners/1/heigth", "unknown field"},
+		{"NestedAddress", `{"hash":` + hash + `,"inners":[{"owner":"hx11"}]}`, "/inners/0/owner", "malformed address"},
+		{"EscapedKey", `{"hash":` + hash + `,"meta":{"a/b~c":"0xG"}}`, "/meta/a~1b~0c", "malformed hex integer"},
+		{"NotString", `{"hash":` + hash + `,"height":16}`, "/height", "not a string"},
+		{"OddBytes", `{"hash":"0x123"}`, "/hash", "malformed hex bytes"},
+		{"NotObject", `[]`, "", "not an object"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckStrict([]byte(c.data), &strictParam{})
+			if c.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			se, ok := err.(*StrictError)
+			if assert.True(t, ok, "unexpected error %v", err) {
+				assert.Equal(t, c.pointer, se.Pointer)
+				assert.Equal(t, c.reason, se.Reason)
+			}
+		})
+	}
+}
+
+func TestMethodRepository_Strict(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+
+	invoke := func(strict bool, req, resp string, status int) {
+		c, rec, err := prepare(req)
+		assert.NoError(t, err)
+		c.Set("strict", strict)
+		assert.NoError(t, mr.Handle(c))
+		assert.Equal(t, status, rec.Code)
+		assert.Equal(t, resp+"\n", rec.Body.String())
+	}
+
+	// field names are matched case-insensitively without strict mode.
+	req := `{"jsonrpc":"2.0","method":"hello","params":{"Name":"icon"},"id":"1001"}`
+	invoke(false, req, `{"jsonrpc":"2.0","result":"hello, icon","id":"1001"}`, http.StatusOK)
+	invoke(true, req, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"InvalidParams: fail to check strictly, unknown field at '/Name'"},"id":"1001"}`, http.StatusBadRequest)
+
+	req = `{"jsonrpc":"2.0","method":"hello","method":"hello","params":{"name":"icon"},"id":"1001"}`
+	invoke(true, req, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"InvalidRequest: fail to check strictly, duplicate field at '/method'"},"id":"1001"}`, http.StatusBadRequest)
+}
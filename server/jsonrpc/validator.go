@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"reflect"
 	"regexp"
+	"sync"
 
 	"gopkg.in/go-playground/validator.v9"
 )
@@ -13,15 +14,20 @@ var (
 	hexInt            = regexp.MustCompile("^0x(0|[1-9a-f][0-9a-f]*)$")
 	hashRegex         = regexp.MustCompile("^0x[0-9a-f]{64}$")
 	rosettaHashRegex  = regexp.MustCompile("^[0b]x[0-9a-f]{64}$")
+	binDataRegex      = regexp.MustCompile("^0x([0-9a-f]{2})*$")
 )
 
 type Validator struct {
 	validator *validator.Validate
+
+	lock    sync.Mutex
+	schemas map[string]Schema
 }
 
 func NewValidator() *Validator {
 	v := &Validator{
 		validator: validator.New(),
+		schemas:   make(map[string]Schema),
 	}
 
 	v.RegisterAlias("optional", "omitempty")
@@ -34,10 +40,12 @@ func NewValidator() *Validator {
 	v.RegisterValidation("t_int", isHexInt)
 	v.RegisterValidation("t_hash", isHash)
 	v.RegisterValidation("t_rhash", isRosettaHash)
+	v.RegisterValidation("t_bin_data", isBinData)
 
 	v.RegisterAlias("t_sig", "base64")
 	v.RegisterAlias("t_addr", "t_addr_eoa|t_addr_score")
 
+	v.registerDefaultSchemas()
 	return v
 }
 
@@ -85,3 +93,7 @@ func isHash(fl validator.FieldLevel) bool {
 func isRosettaHash(fl validator.FieldLevel) bool {
 	return rosettaHashRegex.MatchString(fl.Field().String())
 }
+
+func isBinData(fl validator.FieldLevel) bool {
+	return binDataRegex.MatchString(fl.Field().String())
+}
@@ -16,13 +16,15 @@ func TestValidator(t *testing.T) {
 		Hash    HexBytes `json:"hash" validate:"required,t_hash"`
 		Height  HexInt   `json:"height" validate:"optional,t_int"`
 		Address Address  `json:"address" validate:"required,t_addr"`
+		Data    HexBytes `json:"data" validate:"optional,t_bin_data"`
 	}
 
 	params := []byte(`
 		{
 			"hash": "0xb5f908339f447ca97525a3eb8c3e450e767ffe3e242df3f87e4af4295e1277f3",
 			"height": "0x10",
-			"address": "cx94b475b51924f4a2f449b982e5bfa1a47055a66f"
+			"address": "cx94b475b51924f4a2f449b982e5bfa1a47055a66f",
+			"data": "0x34b2"
 		}
 	`)
 
@@ -36,5 +38,9 @@ func TestValidator(t *testing.T) {
 	assert.Equal(t, "b5f908339f447ca97525a3eb8c3e450e767ffe3e242df3f87e4af4295e1277f3", hex.EncodeToString(param.Hash.Bytes()))
 	assert.Equal(t, int64(0x10), param.Height.Value())
 	assert.Equal(t, "cx94b475b51924f4a2f449b982e5bfa1a47055a66f", param.Address.Address().String())
+	assert.Equal(t, []byte{0x34, 0xb2}, param.Data.Bytes())
+
+	param.Data = "0x34b"
+	assert.Error(t, validator.Validate(&param))
 
 }
@@ -0,0 +1,49 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	APIKeyResultOK      = "ok"
+	APIKeyResultDenied  = "denied"
+	APIKeyResultLimited = "limited"
+)
+
+var (
+	msAPIKeyRequest = stats.Int64("apikey_request", "Requests with API keys", stats.UnitDimensionless)
+	mkAPIKey        = NewMetricKey("apikey")
+	mkAPIKeyResult  = NewMetricKey("result")
+	apiKeyMks       = []tag.Key{mkAPIKey, mkAPIKeyResult}
+)
+
+func RegisterAPIKey() {
+	RegisterMetricView(msAPIKeyRequest, view.Count(), apiKeyMks)
+}
+
+type APIKeyMetric struct {
+	results map[string]context.Context
+}
+
+// OnRequest records the request with the API key and the result of
+// checking the method and the quota of the key.
+func (m *APIKeyMetric) OnRequest(result string) {
+	ctx, ok := m.results[result]
+	if !ok {
+		return
+	}
+	stats.Record(ctx, msAPIKeyRequest.M(1))
+}
+
+func NewAPIKeyMetric(ctx context.Context, name string) *APIKeyMetric {
+	kctx := GetMetricContext(ctx, &mkAPIKey, name)
+	results := make(map[string]context.Context)
+	for _, r := range []string{APIKeyResultOK, APIKeyResultDenied, APIKeyResultLimited} {
+		results[r] = GetMetricContext(kctx, &mkAPIKeyResult, r)
+	}
+	return &APIKeyMetric{results: results}
+}
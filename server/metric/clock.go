@@ -0,0 +1,21 @@
+package metric
+
+import (
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var (
+	msClockSkew = stats.Int64("clock_skew", "Clock skew from NTP server", stats.UnitMilliseconds)
+)
+
+func RegisterClock() {
+	RegisterMetricView(msClockSkew, view.LastValue(), nil)
+}
+
+// RecordClockSkew records the offset of the NTP server from the local clock.
+func RecordClockSkew(d time.Duration) {
+	stats.Record(rootMetricCtx, msClockSkew.M(int64(d/time.Millisecond)))
+}
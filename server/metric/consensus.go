@@ -14,6 +14,13 @@ var (
 	msRound      = stats.Int64("consensus_round", "round", stats.UnitDimensionless)
 	msHeightD    = stats.Int64("consensus_height_duration", "block_duration", stats.UnitMilliseconds)
 	msRoundD     = stats.Int64("consensus_round_duration", "block_duration", stats.UnitMilliseconds)
+	msRoundFail  = stats.Int64("consensus_round_failure", "round_failure", stats.UnitDimensionless)
+	msPartition  = stats.Int64("consensus_partition", "partition", stats.UnitDimensionless)
+	msWALSize    = stats.Int64("consensus_wal_size", "wal_size", stats.UnitBytes)
+	msWALSyncL   = stats.Int64("consensus_wal_sync_latency", "wal_sync_latency", "us")
+	mkReason     = NewMetricKey("reason")
+	mkPartition  = NewMetricKey("partition")
+	mkWAL        = NewMetricKey("wal")
 	consensusMks = []tag.Key{}
 )
 
@@ -22,6 +29,10 @@ func RegisterConsensus() {
 	RegisterMetricView(msRound, view.LastValue(), consensusMks)
 	RegisterMetricView(msHeightD, view.LastValue(), consensusMks)
 	RegisterMetricView(msRoundD, view.LastValue(), consensusMks)
+	RegisterMetricView(msRoundFail, view.Count(), []tag.Key{mkReason})
+	RegisterMetricView(msPartition, view.LastValue(), []tag.Key{mkPartition})
+	RegisterMetricView(msWALSize, view.LastValue(), []tag.Key{mkWAL})
+	RegisterMetricView(msWALSyncL, view.LastValue(), []tag.Key{mkWAL})
 }
 
 type ConsensusMetric struct {
@@ -44,6 +55,31 @@ func (m *ConsensusMetric) OnRound(round int32) {
 	stats.Record(m.ctx, msRound.M(int64(round)), msRoundD.M(int64(d/time.Millisecond)))
 }
 
+func (m *ConsensusMetric) OnRoundFailure(reason string) {
+	_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkReason, reason)}, msRoundFail.M(1))
+}
+
+// OnPartition sets 1 for the new partition and 0 for the old one, so that
+// the metric for the partition is 1 while it's detected.
+func (m *ConsensusMetric) OnPartition(old, partition string) {
+	if old != "" {
+		_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkPartition, old)}, msPartition.M(0))
+	}
+	if partition != "" {
+		_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkPartition, partition)}, msPartition.M(1))
+	}
+}
+
+// OnWALSize records the total size of the files of the WAL.
+func (m *ConsensusMetric) OnWALSize(id string, size int64) {
+	_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkWAL, id)}, msWALSize.M(size))
+}
+
+// OnWALSync records the latency of fsync on the WAL in micro-second.
+func (m *ConsensusMetric) OnWALSync(id string, d time.Duration) {
+	_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkWAL, id)}, msWALSyncL.M(int64(d/time.Microsecond)))
+}
+
 func NewConsensusMetric(ctx context.Context) *ConsensusMetric {
 	return &ConsensusMetric{
 		ctx : ctx,
@@ -0,0 +1,64 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msEngineActive    = stats.Int64("engine_active", "Active executors of the execution engine", stats.UnitDimensionless)
+	msEngineReady     = stats.Int64("engine_ready", "Idle executors of the execution engine", stats.UnitDimensionless)
+	msEngineBusy      = stats.Int64("engine_busy", "Executors of the execution engine in use", stats.UnitDimensionless)
+	msEngineRestart   = stats.Int64("engine_restart", "Executors disconnected unexpectedly", stats.UnitDimensionless)
+	msEngineRecycle   = stats.Int64("engine_recycle", "Executors replaced by recycling", stats.UnitDimensionless)
+	msEngineOOMKilled = stats.Int64("engine_oom_killed", "Executors killed for exceeding the memory limit", stats.UnitDimensionless)
+	mkEngine          = NewMetricKey("engine")
+	engineMks         = []tag.Key{mkEngine}
+)
+
+func RegisterEngine() {
+	RegisterMetricView(msEngineActive, view.LastValue(), engineMks)
+	RegisterMetricView(msEngineReady, view.LastValue(), engineMks)
+	RegisterMetricView(msEngineBusy, view.LastValue(), engineMks)
+	RegisterMetricView(msEngineRestart, view.Count(), engineMks)
+	RegisterMetricView(msEngineRecycle, view.Count(), engineMks)
+	RegisterMetricView(msEngineOOMKilled, view.Count(), engineMks)
+}
+
+// EngineMetric records the health of executors of an execution engine.
+type EngineMetric struct {
+	context context.Context
+}
+
+// OnExecutors records the number of executors.
+func (m *EngineMetric) OnExecutors(active, ready, busy int) {
+	stats.Record(m.context,
+		msEngineActive.M(int64(active)),
+		msEngineReady.M(int64(ready)),
+		msEngineBusy.M(int64(busy)),
+	)
+}
+
+// OnRestart records the executor disconnected unexpectedly.
+func (m *EngineMetric) OnRestart() {
+	stats.Record(m.context, msEngineRestart.M(1))
+}
+
+// OnRecycle records the executor replaced by recycling.
+func (m *EngineMetric) OnRecycle() {
+	stats.Record(m.context, msEngineRecycle.M(1))
+}
+
+// OnOOMKilled records the executor killed for exceeding the memory limit.
+func (m *EngineMetric) OnOOMKilled() {
+	stats.Record(m.context, msEngineOOMKilled.M(1))
+}
+
+func NewEngineMetric(ctx context.Context, engine string) *EngineMetric {
+	return &EngineMetric{
+		context: GetMetricContext(ctx, &mkEngine, engine),
+	}
+}
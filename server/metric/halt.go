@@ -0,0 +1,33 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var (
+	msHaltHeight = stats.Int64("halt_height", "Scheduled height to halt the chain", stats.UnitDimensionless)
+	msHalted     = stats.Int64("halted", "Whether the chain is halted at the scheduled height", stats.UnitDimensionless)
+)
+
+func RegisterHalt() {
+	RegisterMetricView(msHaltHeight, view.LastValue(), nil)
+	RegisterMetricView(msHalted, view.LastValue(), nil)
+}
+
+// RecordHaltHeight records the scheduled height to halt the chain. Zero
+// means there is no schedule.
+func RecordHaltHeight(ctx context.Context, height int64) {
+	stats.Record(ctx, msHaltHeight.M(height))
+}
+
+// RecordHalted records whether the chain is halted at the scheduled height.
+func RecordHalted(ctx context.Context, halted bool) {
+	var v int64
+	if halted {
+		v = 1
+	}
+	stats.Record(ctx, msHalted.M(v))
+}
@@ -0,0 +1,42 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msHotspotBlock    = stats.Int64("hotspot_block", "Sampled execution time of contracts in the last block", stats.UnitMilliseconds)
+	msHotspotContract = stats.Int64("hotspot_contract", "Sampled execution time of the hottest contracts of blocks", stats.UnitMilliseconds)
+	mkContract        = NewMetricKey("contract")
+	hotspotMks        = []tag.Key{mkContract}
+)
+
+func RegisterHotspot() {
+	RegisterMetricView(msHotspotBlock, view.LastValue(), nil)
+	RegisterMetricView(msHotspotContract, view.Sum(), hotspotMks)
+}
+
+type HotspotMetric struct {
+	context context.Context
+}
+
+// OnBlock records the sampled execution time of the block.
+func (m *HotspotMetric) OnBlock(d time.Duration) {
+	stats.Record(m.context, msHotspotBlock.M(int64(d/time.Millisecond)))
+}
+
+// OnContract records the sampled execution time of the contract, which is
+// one of the hottest contracts of the block.
+func (m *HotspotMetric) OnContract(addr string, d time.Duration) {
+	ctx := GetMetricContext(m.context, &mkContract, addr)
+	stats.Record(ctx, msHotspotContract.M(int64(d/time.Millisecond)))
+}
+
+func NewHotspotMetric(ctx context.Context) *HotspotMetric {
+	return &HotspotMetric{context: ctx}
+}
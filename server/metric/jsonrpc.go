@@ -33,6 +33,7 @@ var (
 			emptyMks,
 		},
 		"icx_getBalance":           msRetrieve,
+		"icx_getBalances":          msRetrieve,
 		"icx_getScoreApi":          msRetrieve,
 		"icx_getTotalSupply":       msRetrieve,
 		"icx_getTransactionResult": msRetrieve,
@@ -52,28 +53,74 @@ var (
 			stats.Int64("jsonrpc_wait_transaction_result_avg", "moving average of jsonrpc icx_waitTransactionResult method", "ns"),
 			emptyMks,
 		},
-		"icx_getDataByHash":          msRetrieve,
-		"icx_getBlockHeaderByHeight": msRetrieve,
-		"icx_getVotesByHeight":       msRetrieve,
-		"icx_getProofForResult":      msRetrieve,
-		"icx_getProofForEvents":      msRetrieve,
-		"icx_getScoreStatus":         msRetrieve,
-		"btp_getNetworkInfo":         msRetrieve,
-		"btp_getNetworkTypeInfo":     msRetrieve,
-		"btp_getMessages":            msRetrieve,
-		"btp_getHeader":              msRetrieve,
-		"btp_getProof":               msRetrieve,
-		"btp_getSourceInformation":   msRetrieve,
+		"icx_validateTransaction":      msRetrieve,
+		"icx_getDataByHash":            msRetrieve,
+		"icx_getBlockHeaderByHeight":   msRetrieve,
+		"icx_getVotesByHeight":         msRetrieve,
+		"icx_getRandomnessByHeight":    msRetrieve,
+		"icx_getReceiptsByHeight":      msRetrieve,
+		"icx_getLogs":                  msRetrieve,
+		"icx_getStatement":             msRetrieve,
+		"icx_getTransactionsByAddress": msRetrieve,
+		"icx_getVoteParticipation":     msRetrieve,
+		"icx_getValidatorTransitions":  msRetrieve,
+		"icx_getRoundHistory":          msRetrieve,
+		"icx_getProofForResult":        msRetrieve,
+		"icx_getProofForEvents":        msRetrieve,
+		"icx_getProofForTransaction":   msRetrieve,
+		"icx_getProofForExtension":     msRetrieve,
+		"icx_getScoreStatus":           msRetrieve,
+		"icx_getScoreStatusList":       msRetrieve,
+		"icx_getPendingDeployments":    msRetrieve,
+		"icx_getFeeSharingStatus":      msRetrieve,
+		"icx_getScoreVerification":     msRetrieve,
+		"icx_getChainConfig":           msRetrieve,
+		"icx_getStepCostHistory":       msRetrieve,
+		"icx_getScheduledHalt":         msRetrieve,
+		"icx_getTransactionSuggestion": msRetrieve,
+		"icx_getStateRange":            msRetrieve,
+		"btp_getNetworkInfo":           msRetrieve,
+		"btp_getNetworkTypeInfo":       msRetrieve,
+		"btp_getMessages":              msRetrieve,
+		"btp_getHeader":                msRetrieve,
+		"btp_getProof":                 msRetrieve,
+		"btp_getSourceInformation":     msRetrieve,
+		"btp_getPublicKeys":            msRetrieve,
+		"debug_getStaleState":          msRetrieve,
+		"debug_verifyScore":            msRetrieve,
+		"debug_getHotspots":            msRetrieve,
+		"debug_getStepLimitSuggestion": msRetrieve,
 		"debug_getTrace": {
 			stats.Int64("jsonrpc_get_trace", "jsonrpc debug_getTrace method", "ns"),
 			stats.Int64("jsonrpc_get_trace_avg", "moving average of jsonrpc debug_getTrace method", "ns"),
 			emptyMks,
 		},
+		"debug_getStateDiff": {
+			stats.Int64("jsonrpc_get_state_diff", "jsonrpc debug_getStateDiff method", "ns"),
+			stats.Int64("jsonrpc_get_state_diff_avg", "moving average of jsonrpc debug_getStateDiff method", "ns"),
+			emptyMks,
+		},
 		"debug_estimateStep": {
 			stats.Int64("jsonrpc_estimate_step", "jsonrpc debug_estimateStep method", "ns"),
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
 			emptyMks,
 		},
+		"debug_estimateStepBatch": {
+			stats.Int64("jsonrpc_estimate_step_batch", "jsonrpc debug_estimateStepBatch method", "ns"),
+			stats.Int64("jsonrpc_estimate_step_batch_avg", "moving average of jsonrpc debug_estimateStepBatch method", "ns"),
+			emptyMks,
+		},
+		"debug_estimateFee": {
+			stats.Int64("jsonrpc_estimate_fee", "jsonrpc debug_estimateFee method", "ns"),
+			stats.Int64("jsonrpc_estimate_fee_avg", "moving average of jsonrpc debug_estimateFee method", "ns"),
+			emptyMks,
+		},
+		"debug_simulateProposal": {
+			stats.Int64("jsonrpc_simulate_proposal", "jsonrpc debug_simulateProposal method", "ns"),
+			stats.Int64("jsonrpc_simulate_proposal_avg", "moving average of jsonrpc debug_simulateProposal method", "ns"),
+			emptyMks,
+		},
+		"rpc.discover": msRetrieve,
 		"rosetta_getTrace": {
 			stats.Int64("jsonrpc_rosetta_trace_", "jsonrpc rosetta_getTrace method", "ns"),
 			stats.Int64("jsonrpc_rosetta_trace_avg", "moving average of jsonrpc rosetta_getTTrace method", "ns"),
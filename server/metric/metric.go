@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"contrib.go.opencensus.io/exporter/prometheus"
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
@@ -36,6 +37,9 @@ var (
 	beforeExportFuncsMtx sync.RWMutex
 
 	mtOnce sync.Once
+
+	// mRegistry gathers metrics of the views for the exporter and pushers.
+	mRegistry = promclient.NewRegistry()
 )
 
 func NewMetricKey(k string) tag.Key {
@@ -175,6 +179,7 @@ func PrometheusExporter() *prometheus.Exporter {
 	// prometheus
 	pe, err := prometheus.NewExporter(prometheus.Options{
 		Namespace: "goloop",
+		Registry:  mRegistry,
 	})
 
 	if err != nil {
@@ -189,9 +194,24 @@ func PrometheusExporter() *prometheus.Exporter {
 	RegisterNetwork()
 	RegisterTransaction()
 	RegisterJsonrpc()
+	RegisterClock()
+	RegisterQuota()
+	RegisterHotspot()
+	RegisterStepLimit()
+	RegisterWebSocket()
+	RegisterAPIKey()
+	RegisterRateLimit()
+	RegisterHalt()
+	RegisterEngine()
 	return pe
 }
 
+// Gatherer returns the gatherer of the metrics exported by
+// PrometheusExporter.
+func Gatherer() promclient.Gatherer {
+	return mRegistry
+}
+
 func BeforeExport() {
 	beforeExportFuncsMtx.RLock()
 	defer beforeExportFuncsMtx.RUnlock()
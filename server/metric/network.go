@@ -13,6 +13,8 @@ import (
 var (
 	msSend     = stats.Int64("network_send", "send", stats.UnitBytes)
 	msRecv     = stats.Int64("network_recv", "recv", stats.UnitBytes)
+	msDropQry  = stats.Int64("network_query_drop", "dropped discovery queries", stats.UnitDimensionless)
+	msDropPkt  = stats.Int64("network_packet_drop", "dropped packets by policy", stats.UnitDimensionless)
 	mkDest     = NewMetricKey("dest")
 	mkProtocol = NewMetricKey("protocol")
 	networkMks = []tag.Key{mkDest, mkProtocol}
@@ -23,6 +25,8 @@ func RegisterNetwork() {
 	RegisterMetricView(msSend, view.Sum(), networkMks)
 	RegisterMetricView(msRecv, view.Count(), networkMks)
 	RegisterMetricView(msRecv, view.Sum(), networkMks)
+	RegisterMetricView(msDropQry, view.Count(), []tag.Key{mkReason})
+	RegisterMetricView(msDropPkt, view.Count(), []tag.Key{mkProtocol, mkReason})
 }
 
 type NetworkMetric struct {
@@ -69,6 +73,17 @@ func (m *NetworkMetric) OnRecv(dest byte, ttl byte, hint byte, protocol uint16,
 	stats.Record(ctx, msRecv.M(int64(pktLen)))
 }
 
+func (m *NetworkMetric) OnDropQuery(reason string) {
+	_ = stats.RecordWithTags(m.ctx, []tag.Mutator{tag.Upsert(mkReason, reason)}, msDropQry.M(1))
+}
+
+func (m *NetworkMetric) OnDropPacket(protocol uint16, reason string) {
+	_ = stats.RecordWithTags(m.ctx, []tag.Mutator{
+		tag.Upsert(mkProtocol, fmt.Sprintf("%#04x", protocol)),
+		tag.Upsert(mkReason, reason),
+	}, msDropPkt.M(1))
+}
+
 func NewNetworkMetric(ctx context.Context) *NetworkMetric {
 	return &NetworkMetric{
 		ctx: ctx,
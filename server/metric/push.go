@@ -0,0 +1,324 @@
+package metric
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	DefaultPushJob      = "goloop"
+	DefaultPushInterval = 15
+	pushTimeout         = 10 * time.Second
+)
+
+// PushConfig is the configuration for pushing metrics to the push gateway
+// or the remote-write endpoint of Prometheus, for the nodes which can't be
+// scraped.
+type PushConfig struct {
+	Gateway     string            `json:"gateway,omitempty"`
+	RemoteWrite string            `json:"remote_write,omitempty"`
+	Job         string            `json:"job,omitempty"`
+	Interval    int               `json:"interval,omitempty"` // in second
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+var labelNamePattern = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+func (c *PushConfig) IsEmpty() bool {
+	return c == nil || (c.Gateway == "" && c.RemoteWrite == "")
+}
+
+func (c *PushConfig) Validate() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	for _, u := range []string{c.Gateway, c.RemoteWrite} {
+		if u == "" {
+			continue
+		}
+		if pu, err := url.Parse(u); err != nil || (pu.Scheme != "http" && pu.Scheme != "https") {
+			return errors.IllegalArgumentError.Errorf("InvalidURL(url=%s)", u)
+		}
+	}
+	if c.Interval < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidInterval(%d)", c.Interval)
+	}
+	for k := range c.Labels {
+		if !labelNamePattern.MatchString(k) || k == "job" {
+			return errors.IllegalArgumentError.Errorf("InvalidLabel(%s)", k)
+		}
+	}
+	return nil
+}
+
+func (c *PushConfig) job() string {
+	if c.Job == "" {
+		return DefaultPushJob
+	}
+	return c.Job
+}
+
+func (c *PushConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultPushInterval * time.Second
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Pusher pushes the metrics periodically.
+type Pusher struct {
+	cfg    PushConfig
+	g      promclient.Gatherer
+	client *http.Client
+	logger log.Logger
+
+	lock sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPusher(cfg *PushConfig, g promclient.Gatherer, l log.Logger) (*Pusher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Pusher{
+		cfg:    *cfg,
+		g:      g,
+		client: &http.Client{Timeout: pushTimeout},
+		logger: l,
+	}, nil
+}
+
+func (p *Pusher) Start() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done)
+}
+
+func (p *Pusher) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(p.cfg.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if err := p.Push(); err != nil {
+			p.logger.Warnf("Fail to push metrics err=%+v", err)
+		}
+	}
+}
+
+func (p *Pusher) Stop() {
+	p.lock.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Push pushes the current metrics to the push gateway and the remote-write
+// endpoint in the configuration.
+func (p *Pusher) Push() error {
+	BeforeExport()
+	var errs []error
+	if p.cfg.Gateway != "" {
+		if err := p.pushToGateway(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.cfg.RemoteWrite != "" {
+		if err := p.remoteWrite(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errors.Errorf("FailToPush(gateway=%v,remote_write=%v)", errs[0], errs[1])
+	}
+}
+
+func (p *Pusher) pushToGateway() error {
+	ps := push.New(p.cfg.Gateway, p.cfg.job()).Gatherer(p.g).Client(p.client)
+	for _, k := range sortedKeys(p.cfg.Labels) {
+		ps = ps.Grouping(k, p.cfg.Labels[k])
+	}
+	return ps.Push()
+}
+
+func (p *Pusher) remoteWrite() error {
+	mfs, err := p.g.Gather()
+	if err != nil {
+		return err
+	}
+	extra := map[string]string{"job": p.cfg.job()}
+	for k, v := range p.cfg.Labels {
+		extra[k] = v
+	}
+	ts := time.Now().UnixNano() / int64(time.Millisecond)
+	body := snappy.Encode(nil, encodeWriteRequest(mfs, extra, ts))
+
+	req, err := http.NewRequest(http.MethodPost, p.cfg.RemoteWrite, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
+		return errors.Errorf("RemoteWriteFailure(status=%s,msg=%s)", resp.Status, msg)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeWriteRequest encodes the metrics in WriteRequest of the remote-write
+// protocol of Prometheus (prompb).
+func encodeWriteRequest(mfs []*dto.MetricFamily, extra map[string]string, ts int64) []byte {
+	var buf []byte
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			labels := make(map[string]string, len(m.GetLabel())+len(extra))
+			for k, v := range extra {
+				labels[k] = v
+			}
+			for _, lp := range m.GetLabel() {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			t := ts
+			if m.TimestampMs != nil {
+				t = m.GetTimestampMs()
+			}
+			add := func(name string, value float64, kv ...string) {
+				ls := make(map[string]string, len(labels)+1+len(kv)/2)
+				for k, v := range labels {
+					ls[k] = v
+				}
+				for i := 0; i+1 < len(kv); i += 2 {
+					ls[kv[i]] = kv[i+1]
+				}
+				ls["__name__"] = name
+				buf = appendMessage(buf, 1, encodeTimeSeries(ls, value, t))
+			}
+			name := mf.GetName()
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add(name, q.GetValue(), "quantile", formatFloat(q.GetQuantile()))
+				}
+				add(name+"_sum", s.GetSampleSum())
+				add(name+"_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					add(name+"_bucket", float64(b.GetCumulativeCount()),
+						"le", formatFloat(b.GetUpperBound()))
+				}
+				if bs := h.GetBucket(); len(bs) == 0 || !math.IsInf(bs[len(bs)-1].GetUpperBound(), 1) {
+					add(name+"_bucket", float64(h.GetSampleCount()), "le", "+Inf")
+				}
+				add(name+"_sum", h.GetSampleSum())
+				add(name+"_count", float64(h.GetSampleCount()))
+			}
+		}
+	}
+	return buf
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func encodeTimeSeries(labels map[string]string, value float64, ts int64) []byte {
+	var buf []byte
+	for _, k := range sortedKeys(labels) {
+		var lb []byte
+		lb = appendMessage(lb, 1, []byte(k))
+		lb = appendMessage(lb, 2, []byte(labels[k]))
+		buf = appendMessage(buf, 1, lb)
+	}
+	var sb []byte
+	sb = appendKey(sb, 1, 1)
+	var vb [8]byte
+	binary.LittleEndian.PutUint64(vb[:], math.Float64bits(value))
+	sb = append(sb, vb[:]...)
+	sb = appendKey(sb, 2, 0)
+	sb = appendUvarint(sb, uint64(ts))
+	return appendMessage(buf, 2, sb)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+func appendKey(buf []byte, field int, wire int) []byte {
+	return appendUvarint(buf, uint64(field<<3|wire))
+}
+
+// appendMessage appends the length-delimited field.
+func appendMessage(buf []byte, field int, data []byte) []byte {
+	buf = appendKey(buf, field, 2)
+	buf = appendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
@@ -0,0 +1,101 @@
+package metric
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+func newTestGatherer() promclient.Gatherer {
+	reg := promclient.NewRegistry()
+	c := promclient.NewCounter(promclient.CounterOpts{
+		Name: "test_count", Help: "test counter",
+	})
+	c.Add(3)
+	h := promclient.NewHistogram(promclient.HistogramOpts{
+		Name: "test_latency", Help: "test histogram", Buckets: []float64{1, 10},
+	})
+	h.Observe(5)
+	reg.MustRegister(c, h)
+	return reg
+}
+
+func TestPushConfig_Validate(t *testing.T) {
+	cases := []struct {
+		cfg   PushConfig
+		valid bool
+	}{
+		{PushConfig{}, true},
+		{PushConfig{Gateway: "http://localhost:9091"}, true},
+		{PushConfig{RemoteWrite: "https://localhost/api/v1/write", Labels: map[string]string{"region": "kr"}}, true},
+		{PushConfig{Gateway: "localhost:9091"}, false},
+		{PushConfig{Gateway: "http://localhost:9091", Interval: -1}, false},
+		{PushConfig{Gateway: "http://localhost:9091", Labels: map[string]string{"in-valid": "x"}}, false},
+		{PushConfig{Gateway: "http://localhost:9091", Labels: map[string]string{"job": "x"}}, false},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.valid, c.cfg.Validate() == nil, "case %d", i)
+	}
+}
+
+func TestPusher_Gateway(t *testing.T) {
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p, err := NewPusher(&PushConfig{
+		Gateway: srv.URL,
+		Labels:  map[string]string{"region": "kr"},
+	}, newTestGatherer(), log.New())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Push())
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/goloop/region/kr", path)
+}
+
+func TestPusher_RemoteWrite(t *testing.T) {
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		bs, _ := ioutil.ReadAll(r.Body)
+		body, _ = snappy.Decode(nil, bs)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	p, err := NewPusher(&PushConfig{
+		RemoteWrite: srv.URL,
+		Job:         "validator",
+	}, newTestGatherer(), log.New())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Push())
+	assert.Equal(t, "snappy", header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", header.Get("Content-Type"))
+
+	ts := encodeTimeSeries(map[string]string{
+		"__name__": "test_count",
+		"job":      "validator",
+	}, 3, 0)
+	// compare the labels and the value without the timestamp at the end
+	labels, value := ts[:len(ts)-13], ts[len(ts)-11:len(ts)-2]
+	assert.True(t, bytes.Contains(body, labels))
+	assert.True(t, bytes.Contains(body, value))
+	for _, name := range []string{"test_latency_bucket", "test_latency_sum", "test_latency_count", "+Inf"} {
+		assert.True(t, bytes.Contains(body, []byte(name)), name)
+	}
+
+	srv.Close()
+	assert.Error(t, p.Push())
+}
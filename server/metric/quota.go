@@ -0,0 +1,66 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	QuotaTypeCPU       = "cpu"
+	QuotaTypeGoroutine = "goroutine"
+	QuotaTypeDBIO      = "db_io"
+)
+
+var (
+	msQuotaExecution = stats.Int64("quota_execution", "Execution time of transactions and queries", stats.UnitMilliseconds)
+	msQuotaDBIO      = stats.Int64("quota_db_io", "Bytes of database reads and writes", stats.UnitBytes)
+	msQuotaThrottle  = stats.Int64("quota_throttle", "Delay by exceeding quota", stats.UnitMilliseconds)
+	mkQuotaType      = NewMetricKey("quota_type")
+	quotaMks         = []tag.Key{mkQuotaType}
+)
+
+func RegisterQuota() {
+	RegisterMetricView(msQuotaExecution, view.Sum(), nil)
+	RegisterMetricView(msQuotaDBIO, view.Sum(), nil)
+	RegisterMetricView(msQuotaThrottle, view.Count(), quotaMks)
+	RegisterMetricView(msQuotaThrottle, view.Sum(), quotaMks)
+}
+
+type QuotaMetric struct {
+	context context.Context
+	types   map[string]context.Context
+}
+
+// OnExecution records the execution time consuming the CPU quota.
+func (m *QuotaMetric) OnExecution(d time.Duration) {
+	stats.Record(m.context, msQuotaExecution.M(int64(d/time.Millisecond)))
+}
+
+// OnDBIO records bytes consuming the database I/O quota.
+func (m *QuotaMetric) OnDBIO(n int) {
+	stats.Record(m.context, msQuotaDBIO.M(int64(n)))
+}
+
+// OnThrottle records the delay by exceeding the quota of the type.
+func (m *QuotaMetric) OnThrottle(t string, d time.Duration) {
+	ctx, ok := m.types[t]
+	if !ok {
+		return
+	}
+	stats.Record(ctx, msQuotaThrottle.M(int64(d/time.Millisecond)))
+}
+
+func NewQuotaMetric(ctx context.Context) *QuotaMetric {
+	types := make(map[string]context.Context)
+	for _, t := range []string{QuotaTypeCPU, QuotaTypeGoroutine, QuotaTypeDBIO} {
+		types[t] = GetMetricContext(ctx, &mkQuotaType, t)
+	}
+	return &QuotaMetric{
+		context: ctx,
+		types:   types,
+	}
+}
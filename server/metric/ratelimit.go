@@ -0,0 +1,46 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	RateLimitIP        = "ip"
+	RateLimitKey       = "key"
+	RateLimitExpensive = "expensive"
+)
+
+var (
+	msRateLimited = stats.Int64("jsonrpc_rate_limited", "JSON-RPC requests rejected by rate limits", stats.UnitDimensionless)
+	mkRateLimit   = NewMetricKey("limit")
+	rateLimitMks  = []tag.Key{mkRateLimit, mkMethod}
+)
+
+func RegisterRateLimit() {
+	RegisterMetricView(msRateLimited, view.Count(), rateLimitMks)
+}
+
+type RateLimitMetric struct {
+	limits map[string]context.Context
+}
+
+// OnReject records the request of the method rejected by the limit.
+func (m *RateLimitMetric) OnReject(limit string, method string) {
+	ctx, ok := m.limits[limit]
+	if !ok {
+		return
+	}
+	stats.Record(GetMetricContext(ctx, &mkMethod, method), msRateLimited.M(1))
+}
+
+func NewRateLimitMetric(ctx context.Context) *RateLimitMetric {
+	limits := make(map[string]context.Context)
+	for _, l := range []string{RateLimitIP, RateLimitKey, RateLimitExpensive} {
+		limits[l] = GetMetricContext(ctx, &mkRateLimit, l)
+	}
+	return &RateLimitMetric{limits: limits}
+}
@@ -0,0 +1,32 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var (
+	msStepThroughput     = stats.Int64("step_throughput", "Steps used per second by executing transactions of recent blocks", stats.UnitDimensionless)
+	msStepLimitSuggested = stats.Int64("step_limit_suggested", "Suggested maximum step limit for invoke", stats.UnitDimensionless)
+)
+
+func RegisterStepLimit() {
+	RegisterMetricView(msStepThroughput, view.LastValue(), nil)
+	RegisterMetricView(msStepLimitSuggested, view.LastValue(), nil)
+}
+
+type StepLimitMetric struct {
+	context context.Context
+}
+
+// OnSuggestion records the throughput of execution and the step limit
+// suggested with it.
+func (m *StepLimitMetric) OnSuggestion(sps, limit int64) {
+	stats.Record(m.context, msStepThroughput.M(sps), msStepLimitSuggested.M(limit))
+}
+
+func NewStepLimitMetric(ctx context.Context) *StepLimitMetric {
+	return &StepLimitMetric{context: ctx}
+}
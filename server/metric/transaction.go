@@ -22,6 +22,9 @@ var (
 	msAddUserTx     = stats.Int64("txpool_user_add", "Add User Transaction", stats.UnitBytes)
 	msRemoveUserTx  = stats.Int64("txpool_user_remove", "Remove User Transaction", stats.UnitBytes)
 	msDropUserTx    = stats.Int64("txpool_user_drop", "Drop User Transaction", stats.UnitBytes)
+	msFutureTx      = stats.Int64("txpool_future", "Reject Future Transaction", stats.UnitDimensionless)
+	msAddPriorityTx = stats.Int64("txpool_priority_add", "Add Transaction to Priority Lane", stats.UnitBytes)
+	msPriorityUsed  = stats.Int64("txpool_priority_used", "Transactions in Priority Lane", stats.UnitDimensionless)
 	msFinLatency    = stats.Int64("txlatency_finalize", "Finalize Transaction Latency", stats.UnitMilliseconds)
 	msCommitLatency = stats.Int64("txlatency_commit", "Commit Transaction Latency", stats.UnitMilliseconds)
 	mkTxType        = NewMetricKey("tx_type")
@@ -41,6 +44,10 @@ func RegisterTransaction() {
 	RegisterMetricView(msRemoveUserTx, view.Sum(), txPoolMks)
 	RegisterMetricView(msDropUserTx, view.Count(), txPoolMks)
 	RegisterMetricView(msDropUserTx, view.Sum(), txPoolMks)
+	RegisterMetricView(msFutureTx, view.Count(), txPoolMks)
+	RegisterMetricView(msAddPriorityTx, view.Count(), txPoolMks)
+	RegisterMetricView(msAddPriorityTx, view.Sum(), txPoolMks)
+	RegisterMetricView(msPriorityUsed, view.LastValue(), txPoolMks)
 	RegisterMetricView(msFinLatency, view.LastValue(), txPoolMks)
 	RegisterMetricView(msCommitLatency, view.LastValue(), txPoolMks)
 }
@@ -77,6 +84,18 @@ func (c *TxMetric) OnDropTx(n int, user bool) {
 	}
 }
 
+func (c *TxMetric) OnAddPriorityTx(n int) {
+	stats.Record(c.context, msAddPriorityTx.M(int64(n)))
+}
+
+func (c *TxMetric) OnPriorityLaneUpdated(used int) {
+	stats.Record(c.context, msPriorityUsed.M(int64(used)))
+}
+
+func (c *TxMetric) OnFutureTx() {
+	stats.Record(c.context, msFutureTx.M(1))
+}
+
 func (c *TxMetric) OnFinalize(hash []byte, ts time.Time) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -0,0 +1,66 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	WSRejectSession      = "session"
+	WSRejectIP           = "ip"
+	WSRejectSubscription = "subscription"
+	WSRejectBuffer       = "buffer"
+)
+
+var (
+	msWSSession  = stats.Int64("ws_session", "WebSocket sessions", stats.UnitDimensionless)
+	msWSReject   = stats.Int64("ws_reject", "WebSocket sessions rejected or closed by limits", stats.UnitDimensionless)
+	msWSBuffered = stats.Int64("ws_buffered", "Bytes of buffered WebSocket frames", stats.UnitBytes)
+	mkWSReason   = NewMetricKey("reason")
+	wsMks        = []tag.Key{mkWSReason}
+)
+
+func RegisterWebSocket() {
+	RegisterMetricView(msWSSession, view.LastValue(), nil)
+	RegisterMetricView(msWSReject, view.Count(), wsMks)
+	RegisterMetricView(msWSBuffered, view.LastValue(), nil)
+}
+
+type WebSocketMetric struct {
+	context context.Context
+	reasons map[string]context.Context
+}
+
+// OnSession records the number of WebSocket sessions.
+func (m *WebSocketMetric) OnSession(n int) {
+	stats.Record(m.context, msWSSession.M(int64(n)))
+}
+
+// OnReject records the session rejected or closed by the limit of the
+// reason.
+func (m *WebSocketMetric) OnReject(reason string) {
+	ctx, ok := m.reasons[reason]
+	if !ok {
+		return
+	}
+	stats.Record(ctx, msWSReject.M(1))
+}
+
+// OnBuffered records bytes of frames buffered by all sessions.
+func (m *WebSocketMetric) OnBuffered(n int64) {
+	stats.Record(m.context, msWSBuffered.M(n))
+}
+
+func NewWebSocketMetric(ctx context.Context) *WebSocketMetric {
+	reasons := make(map[string]context.Context)
+	for _, r := range []string{WSRejectSession, WSRejectIP, WSRejectSubscription, WSRejectBuffer} {
+		reasons[r] = GetMetricContext(ctx, &mkWSReason, r)
+	}
+	return &WebSocketMetric{
+		context: ctx,
+		reasons: reasons,
+	}
+}
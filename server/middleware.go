@@ -9,6 +9,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
 
@@ -38,6 +39,9 @@ func ChainInjector(srv *Manager) echo.MiddlewareFunc {
 			if c == nil {
 				return ctx.NoContent(http.StatusNotFound)
 			}
+			if module.IsComponentPaused(c, module.ComponentRPC) {
+				return ctx.NoContent(http.StatusServiceUnavailable)
+			}
 			ctx.Set("chain", c)
 			return next(ctx)
 		}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type testPausableChain struct {
+	module.Chain
+	paused map[string]bool
+}
+
+func (c *testPausableChain) IsPaused(name string) bool {
+	return c.paused[name]
+}
+
+func TestChainInjector(t *testing.T) {
+	srv := &Manager{chains: make(map[string]module.Chain)}
+	c := &testPausableChain{paused: make(map[string]bool)}
+	srv.SetChain("icon_dex", c)
+
+	e := echo.New()
+	e.POST("/:channel", func(ctx echo.Context) error {
+		assert.Equal(t, c, ctx.Get("chain"))
+		return ctx.String(http.StatusOK, "OK")
+	}, ChainInjector(srv))
+	post := func(channel string) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/"+channel, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post("icon_dex"))
+	assert.Equal(t, http.StatusNotFound, post("unknown"))
+
+	c.paused[module.ComponentRPC] = true
+	assert.Equal(t, http.StatusServiceUnavailable, post("icon_dex"))
+	c.paused[module.ComponentRPC] = false
+	assert.Equal(t, http.StatusOK, post("icon_dex"))
+}
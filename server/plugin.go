@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"plugin"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+// PluginSymbol is the function of Go plugins creating the hook with the
+// configuration in PluginConfig. Its type is PluginFactory.
+const PluginSymbol = "NewHook"
+
+type PluginFactory = func(config json.RawMessage) (jsonrpc.Hook, error)
+
+// PluginConfig is the Go plugin built with -buildmode=plugin for the
+// endpoint like EndpointV3.
+type PluginConfig struct {
+	Endpoint string          `json:"endpoint"`
+	Path     string          `json:"path"`
+	Config   json.RawMessage `json:"config,omitempty"`
+}
+
+type PluginConfigs struct {
+	Plugins []PluginConfig `json:"plugins"`
+}
+
+// AddHook adds the hook of JSON-RPC requests to the endpoint. Hooks are
+// called in order of addition for requests, and in reverse order for
+// responses.
+func (srv *Manager) AddHook(endpoint string, hook jsonrpc.Hook) error {
+	if !IsJSONRPCEndpoint(endpoint) {
+		return errors.IllegalArgumentError.Errorf("UnknownEndpoint(endpoint=%s)", endpoint)
+	}
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+
+	old, _ := srv.hooks.Load().(map[string][]jsonrpc.Hook)
+	hooks := make(map[string][]jsonrpc.Hook, len(old)+1)
+	for ep, hs := range old {
+		hooks[ep] = hs
+	}
+	hooks[endpoint] = append(append([]jsonrpc.Hook{}, old[endpoint]...), hook)
+	srv.hooks.Store(hooks)
+	return nil
+}
+
+func (srv *Manager) Hooks(endpoint string) []jsonrpc.Hook {
+	hooks, _ := srv.hooks.Load().(map[string][]jsonrpc.Hook)
+	return hooks[endpoint]
+}
+
+// CheckHooks sets hooks of the endpoint for the requests.
+func (srv *Manager) CheckHooks(endpoint string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if hooks := srv.Hooks(endpoint); len(hooks) > 0 {
+				ctx.Set("hooks", hooks)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// LoadPlugins loads Go plugins in the file of PluginConfigs, and adds hooks
+// created by them. It does nothing if the file doesn't exist.
+func (srv *Manager) LoadPlugins(file string) error {
+	bs, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var configs PluginConfigs
+	if err := json.Unmarshal(bs, &configs); err != nil {
+		return errors.IllegalArgumentError.Wrapf(err, "InvalidPluginFile(file=%s)", file)
+	}
+	for _, cfg := range configs.Plugins {
+		hook, err := loadPlugin(&cfg)
+		if err != nil {
+			return err
+		}
+		if err := srv.AddHook(cfg.Endpoint, hook); err != nil {
+			return err
+		}
+		srv.logger.Infof("plugin loaded endpoint=%s path=%s", cfg.Endpoint, cfg.Path)
+	}
+	return nil
+}
+
+func loadPlugin(cfg *PluginConfig) (jsonrpc.Hook, error) {
+	p, err := plugin.Open(cfg.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open plugin path=%s", cfg.Path)
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to lookup %s path=%s", PluginSymbol, cfg.Path)
+	}
+	factory, ok := sym.(PluginFactory)
+	if !ok {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidPluginSymbol(path=%s,type=%T)", cfg.Path, sym)
+	}
+	hook, err := factory(cfg.Config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to create hook path=%s", cfg.Path)
+	}
+	return hook, nil
+}
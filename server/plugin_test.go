@@ -0,0 +1,64 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type testHook struct{}
+
+func (h testHook) OnRequest(ctx *jsonrpc.Context, req *jsonrpc.Request) *jsonrpc.Error {
+	return nil
+}
+
+func (h testHook) OnResponse(ctx *jsonrpc.Context, req *jsonrpc.Request, resp *jsonrpc.Response) {
+}
+
+func TestManager_AddHook(t *testing.T) {
+	srv := &Manager{}
+	assert.Error(t, srv.AddHook("v4", testHook{}))
+	assert.NoError(t, srv.AddHook(EndpointV3Debug, testHook{}))
+	assert.NoError(t, srv.AddHook(EndpointV3Debug, testHook{}))
+	assert.Len(t, srv.Hooks(EndpointV3), 0)
+	assert.Len(t, srv.Hooks(EndpointV3Debug), 2)
+
+	e := echo.New()
+	count := func(ctx echo.Context) error {
+		hooks := jsonrpc.NewContext(ctx).Hooks()
+		return ctx.JSON(http.StatusOK, len(hooks))
+	}
+	e.POST("/v3", count, srv.CheckHooks(EndpointV3))
+	e.POST("/v3d", count, srv.CheckHooks(EndpointV3Debug))
+	post := func(target string) string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		return rec.Body.String()
+	}
+	assert.Equal(t, "0\n", post("/v3"))
+	assert.Equal(t, "2\n", post("/v3d"))
+}
+
+func TestManager_LoadPlugins(t *testing.T) {
+	srv := &Manager{logger: log.New()}
+	dir := t.TempDir()
+	assert.NoError(t, srv.LoadPlugins(path.Join(dir, "none.json")))
+
+	file := path.Join(dir, "rpcplugins.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"plugins":`), 0600))
+	assert.Error(t, srv.LoadPlugins(file))
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"plugins":[
+		{"endpoint":"v3","path":"`+path.Join(dir, "none.so")+`"}
+	]}`), 0600))
+	assert.Error(t, srv.LoadPlugins(file))
+	assert.Len(t, srv.Hooks(EndpointV3), 0)
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	proxyHeaderTimeout = 5 * time.Second
+	proxyV1MaxLength   = 107
+)
+
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// TrustedProxies is the IP ranges of the proxies trusted to tell the
+// address of the client with PROXY protocol or forwarded headers.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses CIDRs or IP addresses of trusted proxies.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	var tp TrustedProxies
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, errors.IllegalArgumentError.Errorf("InvalidProxyAddress(%s)", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			tp = append(tp, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidProxyCIDR(%s)", cidr)
+		}
+		tp = append(tp, ipNet)
+	}
+	return tp, nil
+}
+
+func (tp TrustedProxies) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range tp {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostIP(addr string) net.IP {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(strings.Trim(addr, "[]"))
+}
+
+// forwardedFor returns addresses in "for" parameters of Forwarded
+// headers(RFC 7239) in order. Obfuscated or unknown ones are returned as nil.
+func forwardedFor(values []string) []net.IP {
+	var ips []net.IP
+	for _, value := range values {
+		for _, elem := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kv) != 2 || !strings.EqualFold(kv[0], "for") {
+					continue
+				}
+				ips = append(ips, hostIP(strings.Trim(kv[1], "\"")))
+			}
+		}
+	}
+	return ips
+}
+
+func xForwardedFor(values []string) []net.IP {
+	var ips []net.IP
+	for _, value := range values {
+		for _, addr := range strings.Split(value, ",") {
+			ips = append(ips, hostIP(strings.TrimSpace(addr)))
+		}
+	}
+	return ips
+}
+
+// ExtractIP returns the address of the client of the request. Headers
+// are used only if the peer is trusted. The addresses of forwarded headers
+// are searched from the last one, and the first address not trusted is the
+// client.
+func (tp TrustedProxies) ExtractIP(req *http.Request) string {
+	direct := hostIP(req.RemoteAddr)
+	if direct == nil {
+		return req.RemoteAddr
+	}
+	if !tp.Contains(direct) {
+		return direct.String()
+	}
+	var ips []net.IP
+	if values := req.Header.Values("Forwarded"); len(values) > 0 {
+		ips = forwardedFor(values)
+	} else if values := req.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		ips = xForwardedFor(values)
+	} else if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-Ip"))); ip != nil {
+		ips = []net.IP{ip}
+	}
+	client := direct
+	for i := len(ips) - 1; i >= 0; i-- {
+		if ips[i] == nil {
+			break
+		}
+		client = ips[i]
+		if !tp.Contains(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// proxyListener accepts connections from trusted proxies with PROXY
+// protocol header (version 1 or 2), and replaces the remote address of
+// them with the address of the client in the header.
+type proxyListener struct {
+	net.Listener
+	trusted TrustedProxies
+	timeout time.Duration
+}
+
+// NewProxyListener returns the listener handling PROXY protocol header of
+// connections from trusted proxies. Connections from trusted proxies
+// without the header are accepted as they are.
+func NewProxyListener(l net.Listener, trusted TrustedProxies) net.Listener {
+	return &proxyListener{
+		Listener: l,
+		trusted:  trusted,
+		timeout:  proxyHeaderTimeout,
+	}
+}
+
+func (l *proxyListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.trusted.Contains(hostIP(c.RemoteAddr().String())) {
+		return c, nil
+	}
+	return &proxyConn{
+		Conn:    c,
+		reader:  bufio.NewReader(c),
+		timeout: l.timeout,
+	}, nil
+}
+
+// proxyConn reads the header on the first use in the goroutine serving
+// the connection, so that slow proxies don't block others.
+type proxyConn struct {
+	net.Conn
+	once    sync.Once
+	reader  *bufio.Reader
+	timeout time.Duration
+	remote  net.Addr
+	err     error
+}
+
+func (c *proxyConn) init() {
+	c.once.Do(func() {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+		c.remote, c.err = readProxyHeader(c.reader)
+		_ = c.Conn.SetReadDeadline(time.Time{})
+		if c.err != nil {
+			c.Conn.Close()
+		}
+	})
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.init()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.init()
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyHeader reads PROXY protocol header if it exists. It returns
+// nil address if there is no header or the header has no address of the
+// client.
+func readProxyHeader(r *bufio.Reader) (net.Addr, error) {
+	if sig, err := r.Peek(len(proxyV2Signature)); err == nil && bytes.Equal(sig, proxyV2Signature) {
+		return readProxyV2Header(r)
+	}
+	if sig, err := r.Peek(6); err == nil && string(sig) == "PROXY " {
+		return readProxyV1Header(r)
+	}
+	return nil, nil
+}
+
+func readProxyV1Header(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= proxyV1MaxLength {
+			return nil, errors.InvalidStateError.New("InvalidProxyHeader(too long)")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errors.InvalidStateError.Errorf("InvalidProxyHeader(%q)", line)
+	}
+	addr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(fields[2], fields[4]))
+	if err != nil || addr.IP == nil {
+		return nil, errors.InvalidStateError.Errorf("InvalidProxyHeader(%q)", line)
+	}
+	return addr, nil
+}
+
+func readProxyV2Header(r *bufio.Reader) (net.Addr, error) {
+	hdr := make([]byte, len(proxyV2Signature)+4)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	verCmd, family := hdr[12], hdr[13]
+	if verCmd>>4 != 2 {
+		return nil, errors.InvalidStateError.Errorf("InvalidProxyVersion(%d)", verCmd>>4)
+	}
+	body := make([]byte, binary.BigEndian.Uint16(hdr[14:]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	// LOCAL command is used by the proxy itself (ex. health checks).
+	if verCmd&0xf == 0 {
+		return nil, nil
+	}
+	switch family {
+	case 0x11: // TCP over IPv4
+		if len(body) < 12 {
+			return nil, errors.InvalidStateError.New("InvalidProxyHeader(short)")
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(body[0:4]),
+			Port: int(binary.BigEndian.Uint16(body[8:])),
+		}, nil
+	case 0x21: // TCP over IPv6
+		if len(body) < 36 {
+			return nil, errors.InvalidStateError.New("InvalidProxyHeader(short)")
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(body[0:16]),
+			Port: int(binary.BigEndian.Uint16(body[32:])),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	tp, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.0.1 ", "", "::1"})
+	assert.NoError(t, err)
+	assert.Len(t, tp, 3)
+	assert.True(t, tp.Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, tp.Contains(net.ParseIP("192.168.0.1")))
+	assert.False(t, tp.Contains(net.ParseIP("192.168.0.2")))
+	assert.True(t, tp.Contains(net.ParseIP("::1")))
+	assert.False(t, tp.Contains(nil))
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy"})
+	assert.Error(t, err)
+}
+
+func TestTrustedProxies_ExtractIP(t *testing.T) {
+	tp, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		ip      string
+	}{
+		{"Direct", "1.2.3.4:1000", nil, "1.2.3.4"},
+		{"UntrustedPeer", "1.2.3.4:1000", map[string]string{"X-Forwarded-For": "5.6.7.8"}, "1.2.3.4"},
+		{"NoHeader", "10.0.0.1:1000", nil, "10.0.0.1"},
+		{"XFF", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "5.6.7.8"}, "5.6.7.8"},
+		{"XFFSpoofed", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "9.9.9.9, 5.6.7.8, 10.0.0.2"}, "5.6.7.8"},
+		{"XFFInvalid", "10.0.0.1:1000", map[string]string{"X-Forwarded-For": "unknown, 10.0.0.2"}, "10.0.0.2"},
+		{"RealIP", "10.0.0.1:1000", map[string]string{"X-Real-Ip": "5.6.7.8"}, "5.6.7.8"},
+		{"Forwarded", "10.0.0.1:1000", map[string]string{"Forwarded": `for=9.9.9.9, for="[2001:db8::1]:4711";proto=https`, "X-Forwarded-For": "5.6.7.8"}, "2001:db8::1"},
+		{"ForwardedObfuscated", "10.0.0.1:1000", map[string]string{"Forwarded": "for=_hidden"}, "10.0.0.1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+			assert.NoError(t, err)
+			req.RemoteAddr = c.remote
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, c.ip, tp.ExtractIP(req))
+		})
+	}
+}
+
+func proxyV2Header(ip net.IP, port uint16) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(proxyV2Signature)
+	buf.WriteByte(0x21)
+	body := bytes.NewBuffer(nil)
+	if ip4 := ip.To4(); ip4 != nil {
+		buf.WriteByte(0x11)
+		body.Write(ip4)
+		body.Write(net.IPv4(127, 0, 0, 1).To4())
+	} else {
+		buf.WriteByte(0x21)
+		body.Write(ip.To16())
+		body.Write(net.IPv6loopback)
+	}
+	_ = binary.Write(body, binary.BigEndian, port)
+	_ = binary.Write(body, binary.BigEndian, uint16(9080))
+	// TLV to be skipped
+	body.Write([]byte{0x04, 0x00, 0x01, 0xff})
+	_ = binary.Write(buf, binary.BigEndian, uint16(body.Len()))
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
+
+func TestProxyListener(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer tcp.Close()
+
+	tp, err := ParseTrustedProxies([]string{"127.0.0.1"})
+	assert.NoError(t, err)
+	l := NewProxyListener(tcp, tp)
+
+	cases := []struct {
+		name   string
+		header []byte
+		remote string
+	}{
+		{"V2IPv4", proxyV2Header(net.ParseIP("1.2.3.4"), 1000), "1.2.3.4:1000"},
+		{"V2IPv6", proxyV2Header(net.ParseIP("2001:db8::1"), 1000), "[2001:db8::1]:1000"},
+		{"V1", []byte("PROXY TCP4 1.2.3.4 127.0.0.1 1000 9080\r\n"), "1.2.3.4:1000"},
+		{"V1Unknown", []byte("PROXY UNKNOWN\r\n"), ""},
+		{"NoHeader", nil, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := net.Dial("tcp", tcp.Addr().String())
+			assert.NoError(t, err)
+			defer client.Close()
+			_, err = client.Write(append(c.header, []byte("GET / HTTP/1.1\r\n")...))
+			assert.NoError(t, err)
+
+			conn, err := l.Accept()
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			remote := c.remote
+			if remote == "" {
+				remote = client.LocalAddr().String()
+			}
+			assert.Equal(t, remote, conn.RemoteAddr().String())
+			bs := make([]byte, 16)
+			_, err = io.ReadFull(conn, bs)
+			assert.NoError(t, err)
+			assert.Equal(t, "GET / HTTP/1.1\r\n", string(bs))
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		client, err := net.Dial("tcp", tcp.Addr().String())
+		assert.NoError(t, err)
+		defer client.Close()
+		_, err = client.Write([]byte("PROXY TCP4 invalid\r\nGET / HTTP/1.1\r\n"))
+		assert.NoError(t, err)
+
+		conn, err := l.Accept()
+		assert.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Read(make([]byte, 16))
+		assert.Error(t, err)
+	})
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,8 +12,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/grpc"
+	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/server/v3"
 )
@@ -22,6 +27,16 @@ const (
 	UrlAdmin          = "/admin"
 )
 
+// Endpoints of JSON-RPC which can be configured for strict mode and
+// authentication.
+const (
+	EndpointV3      = "v3"
+	EndpointV3Debug = "v3d"
+	EndpointRosetta = "rosetta"
+)
+
+var jsonrpcEndpoints = []string{EndpointV3, EndpointV3Debug, EndpointRosetta}
+
 type Config struct {
 	ServerAddress         string
 	JSONRPCDump           bool
@@ -29,7 +44,33 @@ type Config struct {
 	JSONRPCRosetta        bool
 	JSONRPCDefaultChannel string
 	JSONRPCBatchLimit     int
+	JSONRPCCallStepLimit  int64
+	JSONRPCTraceLimit     jsonrpc.TraceLimit
+	JSONRPCStrict         []string
+	JSONRPCRateLimit      jsonrpc.RateLimit
 	WSMaxSession          int
+	WSMaxSessionPerIP     int
+	WSMaxSubscription     int
+	WSMaxBuffer           int64
+	TrustedProxies        []string
+	ProxyProtocol         bool
+	UnixSocket            string
+	UnixSocketMode        os.FileMode
+	MetricPush            *metric.PushConfig
+	GRPCAddress           string
+	APIKeyFile            string
+	APIKeyRequired        bool
+	AuthFile              string
+	AuthEndpoints         []string
+
+	// CORSAllowOrigins is the list of origins allowed by CORS. All origins
+	// are allowed if it's empty.
+	CORSAllowOrigins []string
+	// Middlewares are applied to all requests after CORS, and before
+	// handlers of the server like authentication and rate limits.
+	Middlewares []echo.MiddlewareFunc
+	// PluginFile is the file of PluginConfigs for hooks of JSON-RPC.
+	PluginFile string
 }
 
 type Manager struct {
@@ -38,15 +79,35 @@ type Manager struct {
 	wallet                module.Wallet
 	chains                map[string]module.Chain // chain manager
 	wssm                  *wsSessionManager
+	trustedProxies        TrustedProxies
+	proxyProtocol         bool
+	unixSocket            string
+	unixSocketMode        os.FileMode
+	unixServer            *http.Server
+	grpcAddr              string
+	grpcServer            *grpc.Server
 	mtx                   sync.RWMutex
 	jsonrpcDefaultChannel string
 	jsonrpcMessageDump    int32
 	jsonrpcRosetta        int32
 	jsonrpcIncludeDebug   int32
 	jsonrpcBatchLimit     int32
+	jsonrpcCallStepLimit  int64
+	jsonrpcTraceLimit     atomic.Value
+	jsonrpcStrict         atomic.Value
 	logger                log.Logger
 	metricsHandler        echo.HandlerFunc
+	metricPusher          *metric.Pusher
 	mtr                   *metric.JsonrpcMetric
+	idempotencyKeys       *jsonrpc.IdempotencyKeys
+	apiKeys               *APIKeys
+	rateLimiter           *jsonrpc.RateLimiter
+	authenticator         atomic.Value
+	authEndpoints         atomic.Value
+	hooks                 atomic.Value
+	cors                  *middleware.CORSConfig
+	middlewares           []echo.MiddlewareFunc
+	started               bool
 }
 
 func NewManager(
@@ -61,6 +122,17 @@ func NewManager(
 
 	e.HTTPErrorHandler = HTTPErrorHandler
 	logger := l.WithFields(log.Fields{log.FieldKeyModule: "SR"})
+	trusted, err := ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		logger.Warnf("Fail to parse trusted proxies err=%+v", err)
+	}
+	e.IPExtractor = trusted.ExtractIP
+	apiKeys, err := NewAPIKeys(config.APIKeyFile)
+	if err != nil {
+		logger.Warnf("Fail to load API keys err=%+v", err)
+		apiKeys, _ = NewAPIKeys("")
+	}
+	apiKeys.SetRequired(config.APIKeyRequired)
 	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, false)
 	e.Logger.SetOutput(l.WriterLevel(log.DebugLevel))
 	m := &Manager{
@@ -69,16 +141,56 @@ func NewManager(
 		wallet:                wallet,
 		chains:                make(map[string]module.Chain),
 		wssm:                  newWSSessionManager(logger, config.WSMaxSession),
+		trustedProxies:        trusted,
+		proxyProtocol:         config.ProxyProtocol,
+		unixSocket:            config.UnixSocket,
+		unixSocketMode:        config.UnixSocketMode,
+		grpcAddr:              config.GRPCAddress,
 		mtx:                   sync.RWMutex{},
 		jsonrpcDefaultChannel: config.JSONRPCDefaultChannel,
 		jsonrpcBatchLimit:     int32(config.JSONRPCBatchLimit),
+		jsonrpcCallStepLimit:  config.JSONRPCCallStepLimit,
 		logger:                logger,
 		metricsHandler:        echo.WrapHandler(metric.PrometheusExporter()),
 		mtr:                   mtr,
+		idempotencyKeys: jsonrpc.NewIdempotencyKeys(
+			jsonrpc.DefaultIdempotencyKeyTTL, jsonrpc.DefaultIdempotencyKeyMax),
+		apiKeys:     apiKeys,
+		rateLimiter: jsonrpc.NewRateLimiter(config.JSONRPCRateLimit, jsonrpc.DefaultExpensiveMethods),
+		cors: &middleware.CORSConfig{
+			AllowOrigins: config.CORSAllowOrigins,
+			MaxAge:       3600,
+		},
+		middlewares: append([]echo.MiddlewareFunc{}, config.Middlewares...),
 	}
+	m.wssm.SetMaxSessionPerIP(config.WSMaxSessionPerIP)
+	m.wssm.SetMaxSubscription(config.WSMaxSubscription)
+	m.wssm.SetMaxBuffer(config.WSMaxBuffer)
 	m.SetMessageDump(config.JSONRPCDump)
 	m.SetIncludeDebug(config.JSONRPCIncludeDebug)
 	m.SetRosetta(config.JSONRPCRosetta)
+	m.SetTraceLimit(config.JSONRPCTraceLimit)
+	if err := m.SetStrict(config.JSONRPCStrict); err != nil {
+		logger.Warnf("Fail to set strict endpoints err=%+v", err)
+	}
+	if config.AuthFile != "" {
+		m.SetAuthenticator(NewTokenAuthenticator(config.AuthFile, logger))
+	}
+	if err := m.SetAuthEndpoints(config.AuthEndpoints); err != nil {
+		logger.Warnf("Fail to set auth endpoints err=%+v", err)
+	}
+	if config.PluginFile != "" {
+		if err := m.LoadPlugins(config.PluginFile); err != nil {
+			logger.Warnf("Fail to load plugins err=%+v", err)
+		}
+	}
+	if !config.MetricPush.IsEmpty() {
+		if p, err := metric.NewPusher(config.MetricPush, metric.Gatherer(), logger); err != nil {
+			logger.Warnf("Fail to create metric pusher err=%+v", err)
+		} else {
+			m.metricPusher = p
+		}
+	}
 	return m
 }
 
@@ -175,26 +287,234 @@ func (srv *Manager) BatchLimit() int {
 	return int(atomic.LoadInt32(&srv.jsonrpcBatchLimit))
 }
 
+func (srv *Manager) SetCallStepLimit(limit int64) {
+	atomic.StoreInt64(&srv.jsonrpcCallStepLimit, limit)
+}
+
+func (srv *Manager) CallStepLimit() int64 {
+	return atomic.LoadInt64(&srv.jsonrpcCallStepLimit)
+}
+
+func (srv *Manager) SetTraceLimit(limit jsonrpc.TraceLimit) {
+	srv.jsonrpcTraceLimit.Store(limit)
+}
+
+func (srv *Manager) TraceLimit() jsonrpc.TraceLimit {
+	limit, _ := srv.jsonrpcTraceLimit.Load().(jsonrpc.TraceLimit)
+	return limit
+}
+
+// SetStrict sets the endpoints checking requests by jsonrpc.CheckStrict.
+func (srv *Manager) SetStrict(endpoints []string) error {
+	strict := make(map[string]bool)
+	for _, ep := range endpoints {
+		if ep == "" {
+			continue
+		}
+		if !IsJSONRPCEndpoint(ep) {
+			return errors.IllegalArgumentError.Errorf("UnknownEndpoint(endpoint=%s)", ep)
+		}
+		strict[ep] = true
+	}
+	srv.jsonrpcStrict.Store(strict)
+	return nil
+}
+
+func (srv *Manager) Strict(endpoint string) bool {
+	strict, _ := srv.jsonrpcStrict.Load().(map[string]bool)
+	return strict[endpoint]
+}
+
+type authenticatorHolder struct {
+	Authenticator
+}
+
+// SetAuthenticator sets the authenticator for the endpoints configured by
+// SetAuthEndpoints.
+func (srv *Manager) SetAuthenticator(a Authenticator) {
+	srv.authenticator.Store(authenticatorHolder{a})
+}
+
+func (srv *Manager) Authenticator() Authenticator {
+	h, _ := srv.authenticator.Load().(authenticatorHolder)
+	return h.Authenticator
+}
+
+// SetAuthEndpoints sets the endpoints requiring authentication of requests
+// by the authenticator.
+func (srv *Manager) SetAuthEndpoints(endpoints []string) error {
+	auth := make(map[string]bool)
+	for _, ep := range endpoints {
+		if ep == "" {
+			continue
+		}
+		if !IsJSONRPCEndpoint(ep) {
+			return errors.IllegalArgumentError.Errorf("UnknownEndpoint(endpoint=%s)", ep)
+		}
+		auth[ep] = true
+	}
+	srv.authEndpoints.Store(auth)
+	return nil
+}
+
+func (srv *Manager) AuthRequired(endpoint string) bool {
+	auth, _ := srv.authEndpoints.Load().(map[string]bool)
+	return auth[endpoint]
+}
+
+// IsJSONRPCEndpoint returns true if the endpoint can be configured for
+// strict mode and
+// authentication.
+func IsJSONRPCEndpoint(endpoint string) bool {
+	for _, ep := range jsonrpcEndpoints {
+		if ep == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
 func (srv *Manager) SetWSMaxSession(limit int) {
 	srv.wssm.SetMaxSession(limit)
 }
 
+func (srv *Manager) SetWSMaxSessionPerIP(limit int) {
+	srv.wssm.SetMaxSessionPerIP(limit)
+}
+
+func (srv *Manager) SetWSMaxSubscription(limit int) {
+	srv.wssm.SetMaxSubscription(limit)
+}
+
+func (srv *Manager) SetWSMaxBuffer(limit int64) {
+	srv.wssm.SetMaxBuffer(limit)
+}
+
+// APIKeys returns the keys for clients of the APIs.
+func (srv *Manager) APIKeys() *APIKeys {
+	return srv.apiKeys
+}
+
+func (srv *Manager) SetAPIKeyRequired(required bool) {
+	srv.apiKeys.SetRequired(required)
+}
+
+// SetRateLimit sets rate limits of JSON-RPC requests for each client.
+func (srv *Manager) SetRateLimit(limit jsonrpc.RateLimit) {
+	srv.rateLimiter.SetLimit(limit)
+}
+
+// SetCORS replaces the configuration of CORS. CORS is disabled if it's nil,
+// so that it can be done by a custom middleware. It must be called before
+// Start.
+func (srv *Manager) SetCORS(config *middleware.CORSConfig) error {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+
+	if srv.started {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	srv.cors = config
+	return nil
+}
+
+// Use adds middlewares applied to all requests after ones of Config, like
+// request logging or request size limits. Middlewares for net/http are
+// adapted with echo.WrapMiddleware. It must be called before Start.
+func (srv *Manager) Use(middlewares ...echo.MiddlewareFunc) error {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+
+	if srv.started {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	srv.middlewares = append(srv.middlewares, middlewares...)
+	return nil
+}
+
+// useMiddlewares applies CORS and custom middlewares to the server.
+func (srv *Manager) useMiddlewares() {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+
+	srv.started = true
+	if srv.cors != nil {
+		srv.e.Use(middleware.CORSWithConfig(*srv.cors))
+	}
+	srv.e.Use(srv.middlewares...)
+}
+
 func (srv *Manager) Start() error {
 	srv.logger.Infoln("starting the server")
-	// CORS middleware
-	srv.e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		MaxAge: 3600,
-	}))
+	srv.useMiddlewares()
 
 	// json rpc
-	srv.RegisterAPIHandler(srv.e.Group("/api"))
+	srv.RegisterAPIHandler(srv.e.Group("/api", srv.authenticating(), srv.apiKeys.Middleware(), srv.rateLimiter.Middleware()))
 
 	// metric
 	srv.RegisterMetricsHandler(srv.e.Group("/metrics"))
+	if srv.metricPusher != nil {
+		srv.metricPusher.Start()
+	}
 
+	if srv.unixSocket != "" {
+		if err := srv.startUnixServer(); err != nil {
+			return err
+		}
+	}
+	if srv.grpcAddr != "" {
+		if err := srv.startGRPCServer(); err != nil {
+			return err
+		}
+	}
+	if srv.proxyProtocol {
+		l, err := net.Listen("tcp", srv.addr)
+		if err != nil {
+			return err
+		}
+		srv.e.Listener = NewProxyListener(l, srv.trustedProxies)
+	}
 	return srv.e.Start(srv.addr)
 }
 
+// startUnixServer serves the APIs over the unix domain socket for local
+// processes, which are controlled by the permission of the socket.
+func (srv *Manager) startUnixServer() error {
+	mode := srv.unixSocketMode
+	if mode == 0 {
+		mode = DefaultUnixSocketMode
+	}
+	l, err := listenUnix(srv.unixSocket, mode)
+	if err != nil {
+		return err
+	}
+	srv.unixServer = &http.Server{
+		Handler:  srv.e,
+		ErrorLog: srv.e.StdLogger,
+	}
+	go func(s *http.Server) {
+		if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+			srv.logger.Warnf("fail to serve on unix socket err=%+v", err)
+		}
+	}(srv.unixServer)
+	return nil
+}
+
+// startGRPCServer serves queries of the APIs over gRPC.
+func (srv *Manager) startGRPCServer() error {
+	l, err := net.Listen("tcp", srv.grpcAddr)
+	if err != nil {
+		return err
+	}
+	srv.grpcServer = grpc.New(srv, srv.logger)
+	go func(s *grpc.Server) {
+		if err := s.Serve(l); err != nil {
+			srv.logger.Warnf("fail to serve gRPC err=%+v", err)
+		}
+	}(srv.grpcServer)
+	return nil
+}
+
 func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	g.Use(middleware.Recover())
 
@@ -210,7 +530,10 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 		return func(ctx echo.Context) error {
 			ctx.Set("includeDebug", srv.IncludeDebug())
 			ctx.Set("batchLimit", srv.BatchLimit())
+			ctx.Set("callStepLimit", srv.CallStepLimit())
+			ctx.Set("traceLimit", srv.TraceLimit())
 			ctx.Set("rosetta", srv.Rosetta())
+			ctx.Set("idempotencyKeys", srv.idempotencyKeys)
 			return next(ctx)
 		}
 	})
@@ -218,14 +541,16 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	// v3 APIs
 	mr := v3.MethodRepository(srv.mtr)
 	v3api := rpc.Group("/v3")
-	v3api.Use(JsonRpc(), Chunk())
+	v3api.Use(srv.CheckAuth(EndpointV3), JsonRpc(), Chunk(), srv.CheckStrict(EndpointV3), srv.CheckHooks(EndpointV3))
 	v3api.POST("", mr.Handle, ChainInjector(srv))
 	v3api.POST("/", mr.Handle, ChainInjector(srv))
 	v3api.POST("/:channel", mr.Handle, ChainInjector(srv))
+	rpc.GET("/v3/:channel/statement/:address", srv.getStatement,
+		srv.CheckAuth(EndpointV3), ChainInjector(srv))
 
 	dmr := v3.DebugMethodRepository(srv.mtr)
 	v3dbg := rpc.Group("/v3d")
-	v3dbg.Use(srv.CheckDebug(), JsonRpc(), Chunk())
+	v3dbg.Use(srv.CheckDebug(), srv.CheckAuth(EndpointV3Debug), JsonRpc(), Chunk(), srv.CheckStrict(EndpointV3Debug), srv.CheckHooks(EndpointV3Debug))
 	v3dbg.POST("", dmr.Handle, ChainInjector(srv))
 	v3dbg.POST("/", dmr.Handle, ChainInjector(srv))
 	v3dbg.POST("/:channel", dmr.Handle, ChainInjector(srv))
@@ -233,7 +558,7 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	// Rosetta APIs
 	rmr := v3.RosettaMethodRepository(srv.mtr)
 	rosetta := rpc.Group("/rosetta")
-	rosetta.Use(srv.CheckRosetta(), JsonRpc(), Chunk())
+	rosetta.Use(srv.CheckRosetta(), srv.CheckAuth(EndpointRosetta), JsonRpc(), Chunk(), srv.CheckStrict(EndpointRosetta), srv.CheckHooks(EndpointRosetta))
 	rosetta.POST("", rmr.Handle, ChainInjector(srv))
 	rosetta.POST("/", rmr.Handle, ChainInjector(srv))
 	rosetta.POST("/:channel", rmr.Handle, ChainInjector(srv))
@@ -242,7 +567,12 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	ws := g.Group("")
 	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/activity", srv.wssm.RunActivitySession, ChainInjector(srv))
+	ws.GET("/v3/:channel/pending", srv.wssm.RunPendingSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/txpool", srv.wssm.RunTxPoolSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	ws.GET("/v3d/:channel/debug", srv.wssm.RunDebugSession, srv.CheckDebug(), srv.CheckAuth(EndpointV3Debug), ChainInjector(srv))
+	ws.GET("/v3d/:channel/trace", srv.wssm.RunTraceSession, srv.CheckDebug(), srv.CheckAuth(EndpointV3Debug), ChainInjector(srv))
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -260,6 +590,7 @@ func (srv *Manager) CheckDebug() echo.MiddlewareFunc {
 			if !srv.IncludeDebug() {
 				return ctx.String(http.StatusNotFound, "rpc_debug is false")
 			}
+			ctx.Set("traceLimit", srv.TraceLimit())
 			return next(ctx)
 		}
 	}
@@ -276,12 +607,65 @@ func (srv *Manager) CheckRosetta() echo.MiddlewareFunc {
 	}
 }
 
+// CheckAuth authenticates requests to the endpoint if it requires
+// authentication. Requests not marked by authenticating, like the ones from
+// the CLI socket of the node, are not checked.
+func (srv *Manager) CheckAuth(endpoint string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if marked, _ := ctx.Get("authenticating").(bool); !marked || !srv.AuthRequired(endpoint) {
+				return next(ctx)
+			}
+			a := srv.Authenticator()
+			if a == nil {
+				return ctx.String(http.StatusUnauthorized, "no authenticator")
+			}
+			if err := a.Authenticate(ctx.Request()); err != nil {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, AuthScheme)
+				return ctx.String(http.StatusUnauthorized, err.Error())
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (srv *Manager) authenticating() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set("authenticating", true)
+			return next(ctx)
+		}
+	}
+}
+
+// CheckStrict sets whether the requests to the endpoint are checked
+// strictly.
+func (srv *Manager) CheckStrict(endpoint string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set("strict", srv.Strict(endpoint))
+			return next(ctx)
+		}
+	}
+}
+
 func (srv *Manager) Stop() error {
 	srv.logger.Infoln("shutting down the server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 	srv.wssm.StopAllSessions()
+	if srv.metricPusher != nil {
+		srv.metricPusher.Stop()
+	}
+	if srv.unixServer != nil {
+		if err := srv.unixServer.Shutdown(ctx); err != nil {
+			srv.logger.Warnf("fail to shutdown unix socket server err=%+v", err)
+		}
+	}
+	if srv.grpcServer != nil {
+		srv.grpcServer.Stop()
+	}
 	return srv.e.Shutdown(ctx)
 }
 
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+func TestManager_Use(t *testing.T) {
+	header := func(key, value string) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(ctx echo.Context) error {
+				ctx.Response().Header().Add(key, value)
+				return next(ctx)
+			}
+		}
+	}
+	srv := NewManager(&Config{
+		CORSAllowOrigins: []string{"https://wallet.example.com"},
+		Middlewares:      []echo.MiddlewareFunc{header("X-Test", "config")},
+	}, nil, log.New())
+	assert.NoError(t, srv.Use(header("X-Test", "use"), middleware.BodyLimit("1K")))
+
+	srv.useMiddlewares()
+	assert.Error(t, srv.Use(header("X-Test", "started")))
+	assert.Error(t, srv.SetCORS(nil))
+	srv.e.POST("/", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	post := func(origin string, size int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.ContentLength = int64(size)
+		rec := httptest.NewRecorder()
+		srv.e.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := post("https://wallet.example.com", 0)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://wallet.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, []string{"config", "use"}, rec.Header().Values("X-Test"))
+
+	rec = post("https://other.example.com", 0)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	rec = post("https://wallet.example.com", 2048)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/v3"
+)
+
+const (
+	StatementFormatCSV  = "csv"
+	StatementFormatJSON = "json"
+
+	// HeaderNextStart has the start of the next page of the statement.
+	HeaderNextStart = "X-Next-Start"
+
+	statementMethod = "icx_getStatement"
+)
+
+var statementCSVHeader = []string{
+	"blockHeight", "timestamp", "txHash", "type", "counterparty", "amount",
+}
+
+func statusOfError(err *jsonrpc.Error) int {
+	switch err.Code {
+	case jsonrpc.ErrorCodeInvalidParams:
+		return http.StatusBadRequest
+	case jsonrpc.ErrorCodeMethodNotFound, jsonrpc.ErrorCodeNotFound:
+		return http.StatusNotFound
+	case jsonrpc.ErrorCodeExecuting, jsonrpc.ErrorCodeServer:
+		return http.StatusServiceUnavailable
+	case jsonrpc.ErrorCodeTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeStatementCSV(ctx echo.Context, s *v3.Statement) error {
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=UTF-8")
+	res.WriteHeader(http.StatusOK)
+	w := csv.NewWriter(res)
+	if err := w.Write(statementCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range s.Items {
+		var counterparty string
+		if item.Counterparty != nil {
+			counterparty = item.Counterparty.String()
+		}
+		if err := w.Write([]string{
+			strconv.FormatInt(item.Height, 10),
+			strconv.FormatInt(item.Timestamp, 10),
+			common.HexBytes(item.TxHash).String(),
+			item.Type,
+			counterparty,
+			item.Amount.String(),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// getStatement returns the statement of the address like icx_getStatement
+// for GET requests, so that accounting tools may download it as CSV.
+func (srv *Manager) getStatement(ctx echo.Context) error {
+	if f, ok := ctx.Get("methodFilter").(jsonrpc.MethodFilter); ok && f != nil {
+		if err := f.FilterMethod(statementMethod); err != nil {
+			return ctx.JSON(statusOfError(err), err)
+		}
+	}
+	format := ctx.QueryParam("format")
+	if format == "" {
+		format = StatementFormatCSV
+	}
+	if format != StatementFormatCSV && format != StatementFormatJSON {
+		return ctx.JSON(http.StatusBadRequest,
+			jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidFormat(%s)", format))
+	}
+	addr := ctx.Param("address")
+	if _, err := common.NewAddressFromString(addr); err != nil {
+		return ctx.JSON(http.StatusBadRequest,
+			jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidAddress(%s)", addr))
+	}
+	param := &v3.StatementParam{
+		Address:       jsonrpc.Address(addr),
+		FromTimestamp: jsonrpc.HexInt(ctx.QueryParam("from")),
+		ToTimestamp:   jsonrpc.HexInt(ctx.QueryParam("to")),
+		Start:         jsonrpc.HexInt(ctx.QueryParam("start")),
+		Limit:         jsonrpc.HexInt(ctx.QueryParam("limit")),
+	}
+	chain := ctx.Get("chain").(module.Chain)
+	s, err := v3.GetStatement(chain, param, srv.IncludeDebug())
+	if err != nil {
+		je, ok := err.(*jsonrpc.Error)
+		if !ok {
+			je = jsonrpc.ErrorCodeSystem.Wrap(err, srv.IncludeDebug())
+		}
+		return ctx.JSON(statusOfError(je), je)
+	}
+	if s.Next >= 0 {
+		ctx.Response().Header().Set(HeaderNextStart, intconv.FormatInt(s.Next))
+	}
+	if format == StatementFormatJSON {
+		return ctx.JSON(http.StatusOK, s.ToJSON())
+	}
+	return writeStatementCSV(ctx, s)
+}
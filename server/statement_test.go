@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type testStatementFilter map[string]bool
+
+func (f testStatementFilter) FilterMethod(method string) *jsonrpc.Error {
+	if !f[method] {
+		return jsonrpc.ErrorCodeTooManyRequests.New("RateLimited")
+	}
+	return nil
+}
+
+func TestManager_getStatement(t *testing.T) {
+	srv := &Manager{}
+	get := func(target string, filter jsonrpc.MethodFilter) (int, string) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("channel", "address")
+		c.SetParamValues("icon", "hx0000000000000000000000000000000000000001")
+		if filter != nil {
+			c.Set("methodFilter", filter)
+		}
+		assert.NoError(t, srv.getStatement(c))
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/?format=xml", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "InvalidFormat")
+
+	code, _ = get("/", testStatementFilter{})
+	assert.Equal(t, http.StatusTooManyRequests, code)
+
+	code, body = get("/?format=xml", testStatementFilter{statementMethod: true})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "InvalidFormat")
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const DefaultUnixSocketMode os.FileMode = 0660
+
+// ParseUnixSocketMode parses the permission of the socket file in octal.
+// It returns DefaultUnixSocketMode for the empty string.
+func ParseUnixSocketMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return DefaultUnixSocketMode, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, errors.IllegalArgumentError.Errorf("InvalidSocketMode(%s)", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// listenUnix listens on the unix domain socket with the permission. The
+// stale socket file is removed, but other files are kept.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, errors.IllegalArgumentError.Errorf("NotSocket(path=%s)", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
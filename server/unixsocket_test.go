@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnixSocketMode(t *testing.T) {
+	mode, err := ParseUnixSocketMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUnixSocketMode, mode)
+
+	mode, err = ParseUnixSocketMode("0600")
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), mode)
+
+	_, err = ParseUnixSocketMode("0999")
+	assert.Error(t, err)
+	_, err = ParseUnixSocketMode("01777")
+	assert.Error(t, err)
+}
+
+func TestListenUnix(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rpc.sock")
+
+	l, err := listenUnix(path, 0600)
+	assert.NoError(t, err)
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	c, err := net.Dial("unix", path)
+	assert.NoError(t, err)
+	c.Close()
+
+	// stale socket file is replaced
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, l.Close())
+	l, err = listenUnix(path, 0660)
+	assert.NoError(t, err)
+	fi, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), fi.Mode().Perm())
+	assert.NoError(t, l.Close())
+
+	// other files are kept
+	file := filepath.Join(dir, "file")
+	assert.NoError(t, os.WriteFile(file, []byte("data"), 0644))
+	_, err = listenUnix(file, 0600)
+	assert.Error(t, err)
+	bs, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(bs))
+}
@@ -4,28 +4,46 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
 	"strconv"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/chain/addressindex"
+	"github.com/icon-project/goloop/chain/eventindex"
+	"github.com/icon-project/goloop/chain/scoreindex"
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/hotspot"
 	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/trace"
 	"github.com/icon-project/goloop/service/txresult"
 )
 
 const (
 	ConfigShowPatchTransaction = false
+	ConfigMaxBalanceAddresses  = 1000
+	ConfigMaxVoteBlocks        = 1000
+
+	ConfigMaxValidatorTransitionBlocks = 10000
+
+	ConfigMaxEstimateTransactions = 20
 )
 
 func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
@@ -37,20 +55,41 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getBlockByHash", getBlockByHash)
 	mr.RegisterMethod("icx_call", call)
 	mr.RegisterMethod("icx_getBalance", getBalance)
+	mr.RegisterMethod("icx_getBalances", getBalances)
 	mr.RegisterMethod("icx_getScoreApi", getScoreApi)
 	mr.RegisterMethod("icx_getTotalSupply", getTotalSupply)
 	mr.RegisterMethod("icx_getTransactionResult", getTransactionResult)
 	mr.RegisterMethod("icx_getTransactionByHash", getTransactionByHash)
 	mr.RegisterMethod("icx_sendTransaction", sendTransaction)
+	mr.RegisterMethod("icx_validateTransaction", validateTransaction)
 	mr.RegisterMethod("icx_sendTransactionAndWait", sendTransactionAndWait)
 	mr.RegisterMethod("icx_waitTransactionResult", waitTransactionResult)
 
 	mr.RegisterMethod("icx_getDataByHash", getDataByHash)
 	mr.RegisterMethod("icx_getBlockHeaderByHeight", getBlockHeaderByHeight)
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
+	mr.RegisterMethod("icx_getRandomnessByHeight", getRandomnessByHeight)
+	mr.RegisterMethod("icx_getReceiptsByHeight", getReceiptsByHeight)
+	mr.RegisterMethod("icx_getLogs", getLogs)
+	mr.RegisterMethod("icx_getStatement", getStatement)
+	mr.RegisterMethod("icx_getTransactionsByAddress", getTransactionsByAddress)
+	mr.RegisterMethod("icx_getVoteParticipation", getVoteParticipation)
+	mr.RegisterMethod("icx_getValidatorTransitions", getValidatorTransitions)
+	mr.RegisterMethod("icx_getRoundHistory", getRoundHistory)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
+	mr.RegisterMethod("icx_getProofForTransaction", getProofForTransaction)
+	mr.RegisterMethod("icx_getProofForExtension", getProofForExtension)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
+	mr.RegisterMethod("icx_getScoreStatusList", getScoreStatusList)
+	mr.RegisterMethod("icx_getPendingDeployments", getPendingDeployments)
+	mr.RegisterMethod("icx_getFeeSharingStatus", getFeeSharingStatus)
+	mr.RegisterMethod("icx_getScoreVerification", getScoreVerification)
+	mr.RegisterMethod("icx_getChainConfig", getChainConfig)
+	mr.RegisterMethod("icx_getStepCostHistory", getStepCostHistory)
+	mr.RegisterMethod("icx_getScheduledHalt", getScheduledHalt)
+	mr.RegisterMethod("icx_getTransactionSuggestion", getTransactionSuggestion)
+	mr.RegisterMethod("icx_getStateRange", getStateRange)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -58,13 +97,72 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("btp_getHeader", getBTPHeader)
 	mr.RegisterMethod("btp_getProof", getBTPProof)
 	mr.RegisterMethod("btp_getSourceInformation", getBTPSourceInformation)
+	mr.RegisterMethod("btp_getPublicKeys", getBTPPublicKeys)
 
 	mr.SetAllowedNotification("icx_sendTransaction")
 	mr.SetAllowedNotification("icx_sendTransactionAndWait")
+
+	setSchemas(mr)
 	return mr
 }
 
-func fillTransactions(blockJson interface{}, b module.Block, v module.JSONVersion) error {
+// txListOption is the parsed TxListParam. Limit is zero for all
+// transactions following the offset.
+type txListOption struct {
+	offset   int
+	limit    int
+	hashOnly bool
+}
+
+func (p *TxListParam) option() (*txListOption, error) {
+	if p == nil || (p.TxOffset == "" && p.TxLimit == "" && !p.TxHashOnly) {
+		return nil, nil
+	}
+	offset, err := p.TxOffset.ParseInt(32)
+	if err != nil || offset < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidTxOffset(%s)", p.TxOffset)
+	}
+	limit, err := p.TxLimit.ParseInt(32)
+	if err != nil || limit < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidTxLimit(%s)", p.TxLimit)
+	}
+	return &txListOption{
+		offset:   int(offset),
+		limit:    int(limit),
+		hashOnly: p.TxHashOnly,
+	}, nil
+}
+
+// selectTransactions returns transactions in the range of the option with
+// the number of all transactions in the list.
+func selectTransactions(txs module.TransactionList, v module.JSONVersion, opt *txListOption) ([]interface{}, int, error) {
+	list := []interface{}{}
+	idx := 0
+	for it := txs.Iterator(); it.Has(); it.Next() {
+		if idx >= opt.offset && (opt.limit == 0 || len(list) < opt.limit) {
+			tx, _, err := it.Get()
+			if err != nil {
+				return nil, 0, err
+			}
+			if opt.hashOnly {
+				list = append(list, "0x"+hex.EncodeToString(tx.ID()))
+			} else {
+				res, err := tx.ToJSON(v)
+				if err != nil {
+					return nil, 0, err
+				}
+				list = append(list, res)
+			}
+		}
+		idx++
+	}
+	return list, idx, nil
+}
+
+// fillTransactions fills transactions of the block. If opt is not nil, it
+// fills selected transactions in confirmed_transaction_list with the number
+// of all normal transactions in confirmed_transaction_count.
+func fillTransactions(blockJson interface{}, b module.Block, v module.JSONVersion, opt *txListOption) error {
 	result := blockJson.(map[string]interface{})
 
 	if ConfigShowPatchTransaction {
@@ -77,6 +175,16 @@ func fillTransactions(blockJson interface{}, b module.Block, v module.JSONVersio
 		}
 	}
 
+	if opt != nil {
+		txs, count, err := selectTransactions(b.NormalTransactions(), v, opt)
+		if err != nil {
+			return err
+		}
+		result["confirmed_transaction_list"] = txs
+		result["confirmed_transaction_count"] = intconv.FormatInt(int64(count))
+		return nil
+	}
+
 	if txs, err := convertTransactionList(b.NormalTransactions(), v); err != nil {
 		return err
 	} else {
@@ -85,6 +193,45 @@ func fillTransactions(blockJson interface{}, b module.Block, v module.JSONVersio
 	return nil
 }
 
+// logsBloomOf returns the bloom of event logs of transactions in the block.
+// Receipts of the block are in the result of the next block, so it's the
+// bloom in the header of the next block. It returns nil if the next block
+// is not finalized yet or it doesn't have the bloom.
+func logsBloomOf(bm module.BlockManager, b module.Block) (module.LogsBloom, error) {
+	next, err := bm.GetBlockByHeight(b.Height() + 1)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lb := next.LogsBloom()
+	if lb == nil {
+		return nil, nil
+	}
+	return lb, nil
+}
+
+func fillLogsBloom(blockJson interface{}, bm module.BlockManager, b module.Block) error {
+	lb, err := logsBloomOf(bm, b)
+	if err != nil {
+		return err
+	}
+	if lb != nil {
+		result := blockJson.(map[string]interface{})
+		result["logs_bloom"] = lb
+	}
+	return nil
+}
+
+// fillRandomness adds the randomness of the block if the block supports it.
+func fillRandomness(blockJson interface{}, b module.Block) {
+	if r, err := consensus.RandomnessOf(b); err == nil {
+		result := blockJson.(map[string]interface{})
+		result["randomness"] = common.HexBytes(r)
+	}
+}
+
 func checkBaseHeight(c module.Chain, height int64) error {
 	if height < 0 {
 		return errors.NotFoundError.Errorf("NegativeHeight(height=%d)", height)
@@ -99,10 +246,14 @@ func checkBaseHeight(c module.Chain, height int64) error {
 
 func getLastBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
-	var param struct{}
+	var param *TxListParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	opt, err := param.option()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
@@ -124,9 +275,13 @@ func getLastBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	if err := fillTransactions(blockJson, block, module.JSONVersion3); err != nil {
+	if err := fillTransactions(blockJson, block, module.JSONVersion3, opt); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := fillLogsBloom(blockJson, bm, block); err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	fillRandomness(blockJson, block)
 	return blockJson, nil
 }
 
@@ -141,6 +296,10 @@ func getBlockByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	opt, err := param.option()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
@@ -168,9 +327,13 @@ func getBlockByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	if err := fillTransactions(blockJson, block, module.JSONVersion3); err != nil {
+	if err := fillTransactions(blockJson, block, module.JSONVersion3, opt); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := fillLogsBloom(blockJson, bm, block); err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	fillRandomness(blockJson, block)
 	return blockJson, nil
 }
 
@@ -181,6 +344,10 @@ func getBlockByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	opt, err := param.option()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
@@ -207,9 +374,13 @@ func getBlockByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	if err := fillTransactions(blockJson, block, module.JSONVersion3); err != nil {
+	if err := fillTransactions(blockJson, block, module.JSONVersion3, opt); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := fillLogsBloom(blockJson, bm, block); err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	fillRandomness(blockJson, block)
 	return blockJson, nil
 }
 
@@ -239,11 +410,17 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	js, err := applyCallStepLimit(ctx, &param, params.RawMessage())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
 	bi := common.NewBlockInfo(block.Height(), block.Timestamp())
-	result, err := sm.Call(block.Result(), block.NextValidators(), params.RawMessage(), bi)
+	result, err := sm.Call(block.Result(), block.NextValidators(), js, bi)
 	if err != nil {
 		if service.InvalidQueryError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		} else if service.StateUnavailableError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeStateUnavailable.Wrap(err, debug)
 		} else if scoreresult.IsValid(err) {
 			return nil, jsonrpc.ErrScore(err, debug)
 		} else {
@@ -254,6 +431,26 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	}
 }
 
+// applyCallStepLimit returns the query with the step limit lowered to
+// the limit of the server if the requested one is missing or over it.
+func applyCallStepLimit(ctx *jsonrpc.Context, param *CallParam, js []byte) ([]byte, error) {
+	limit := ctx.CallStepLimit()
+	if limit <= 0 {
+		return js, nil
+	}
+	if param.StepLimit != "" {
+		if v, err := param.StepLimit.Int64(); err == nil && v <= limit {
+			return js, nil
+		}
+	}
+	var jso map[string]json.RawMessage
+	if err := json.Unmarshal(js, &jso); err != nil {
+		return nil, err
+	}
+	jso["stepLimit"] = json.RawMessage(strconv.Quote(intconv.FormatInt(limit)))
+	return json.Marshal(jso)
+}
+
 func getBlock(chain module.Chain, bm module.BlockManager, height jsonrpc.HexInt) (block module.Block, err error) {
 	if height == "" {
 		block, err = bm.GetLastBlock()
@@ -298,6 +495,49 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	return &balance, nil
 }
 
+func getBalances(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var param AddressesParam
+	debug := ctx.IncludeDebug()
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if len(param.Addresses) > ConfigMaxBalanceAddresses {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooManyAddresses(max=%d)", ConfigMaxBalanceAddresses)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	block, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	addrs := make([]module.Address, len(param.Addresses))
+	for i, addr := range param.Addresses {
+		addrs[i] = addr.Address()
+	}
+	bs, err := sm.GetBalances(block.Result(), addrs)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	balances := make(map[string]*common.HexInt, len(addrs))
+	for i, b := range bs {
+		var balance common.HexInt
+		balance.Set(b)
+		balances[addrs[i].String()] = &balance
+	}
+	return balances, nil
+}
+
 func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var param ScoreAddressParam
 	debug := ctx.IncludeDebug()
@@ -368,10 +608,203 @@ func getTotalSupply(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	return &tsValue, nil
 }
 
+func getChainConfig(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	var param *HeightParam
+	var height jsonrpc.HexInt
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	} else {
+		if param != nil {
+			height = param.Height
+		}
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	b, err := getBlock(chain, bm, height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	cc, err := sm.GetChainConfig(b.Result())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	jso, err := cc.ToJSON(b.Height(), module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return jso, nil
+}
+
+type haltScheduler interface {
+	HaltHeight() int64
+}
+
+// getScheduledHalt returns the height of the last block if the chain is
+// scheduled to halt for coordinated upgrades. It returns null without
+// the schedule.
+func getScheduledHalt(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	hs, ok := chain.(haltScheduler)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.New("NotSupported")
+	}
+	height := hs.HaltHeight()
+	if height == 0 {
+		return nil, nil
+	}
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return map[string]interface{}{
+		"height":     intconv.FormatInt(height),
+		"lastHeight": intconv.FormatInt(blk.Height()),
+	}, nil
+}
+
+func getTransactionSuggestion(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	var param TxSuggestionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	s, err := service.TxSuggestionOf(sm, param.Address.Address())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	}
+	res := map[string]interface{}{
+		"address":   param.Address,
+		"pending":   intconv.FormatInt(int64(s.Pending)),
+		"timestamp": intconv.FormatInt(s.Timestamp),
+		"nonce":     intconv.FormatBigInt(s.Nonce),
+	}
+	if s.Pending > 0 {
+		res["lastTimestamp"] = intconv.FormatInt(s.LastTimestamp)
+	}
+	return res, nil
+}
+
+func getStateRange(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	start, end, err := service.StateRangeOf(sm, bm, chain.GenesisStorage().Height())
+	if err != nil {
+		switch {
+		case service.StateUnavailableError.Equals(err):
+			return nil, jsonrpc.ErrorCodeStateUnavailable.Wrap(err, debug)
+		case errors.UnsupportedError.Equals(err):
+			return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+		default:
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+	}
+	return map[string]interface{}{
+		"start": intconv.FormatInt(start),
+		"end":   intconv.FormatInt(end),
+	}, nil
+}
+
+func getStepCostHistory(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	var param *StepCostHistoryParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if param == nil {
+		param = &StepCostHistoryParam{}
+	}
+	var from, to int64
+	var err error
+	if param.From != "" {
+		if from, err = param.From.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
+	if param.To != "" {
+		if to, err = param.To.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if to < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidRange(from=%d,to=%d)", from, to)
+		}
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	changes, err := service.GetStepCostHistory(chain.Database(), param.Type, from, to)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result := make([]interface{}, 0, len(changes))
+	for _, c := range changes {
+		jso := map[string]interface{}{
+			"type":   c.Type,
+			"value":  intconv.FormatBigInt(c.Value),
+			"height": intconv.FormatInt(c.Height),
+		}
+		if c.TxHash != nil {
+			jso["txHash"] = "0x" + hex.EncodeToString(c.TxHash)
+		}
+		result = append(result, jso)
+	}
+	return result, nil
+}
+
 func getTransactionResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param TransactionHashParam
+	var param TransactionResultParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
@@ -418,9 +851,47 @@ func getTransactionResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interfa
 	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
 	result["txHash"] = "0x" + hex.EncodeToString(param.Hash.Bytes())
 
+	if param.Decoded {
+		decoder, err := eventDecoderAt(bm, sm, blk.Height())
+		if block.ResultNotFinalizedError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+		} else if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		logs, err := decoder.DecodeEventLogs(receipt)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		result["decodedEventLogs"] = logs
+	}
+
+	if param.FailureData && receipt.Status() != module.StatusSuccess {
+		data, err := txresult.GetFailureData(chain.Database(), param.Hash.Bytes())
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if data != nil {
+			result["failure"] = failureWithData(receipt.Status(), data)
+		}
+	}
+
 	return result, nil
 }
 
+// failureWithData returns failure object of the receipt including the
+// revert payload. The payload is also given as a string if it's valid UTF-8.
+func failureWithData(s module.Status, data []byte) map[string]interface{} {
+	failure := map[string]interface{}{
+		"code":    "0x" + strconv.FormatInt(int64(s), 16),
+		"message": s.String(),
+		"data":    "0x" + hex.EncodeToString(data),
+	}
+	if utf8.Valid(data) {
+		failure["decodedMessage"] = string(data)
+	}
+	return failure
+}
+
 func getTransactionByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -494,24 +965,130 @@ func sendTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		height = block.Height() + 1
 	}
 
+	var ikey string
+	var digest []byte
+	ik := ctx.IdempotencyKeys()
+	if key := ctx.IdempotencyKey(); len(key) > 0 && ik != nil {
+		ikey = fmt.Sprintf("%d:%s", chain.NID(), key)
+		digest = crypto.SHA3Sum256(params.RawMessage())
+		if hash, ok := ik.Get(ikey, digest); !ok {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"IdempotencyKeyReused(key=%s)", key)
+		} else if hash != nil {
+			return "0x" + hex.EncodeToString(hash), nil
+		}
+	}
+
 	hash, err := sm.SendTransaction(state, height, params.RawMessage())
 	if err != nil {
 		if service.TransactionPoolOverflowError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeTxPoolOverflow.Wrap(err, debug)
 		}
+		if id, status, ok := service.DuplicateOf(err); ok {
+			return nil, errDuplicateTransaction(chain, err, id, status, debug)
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	if len(ikey) > 0 {
+		ik.Put(ikey, digest, hash)
+	}
 
 	result := "0x" + hex.EncodeToString(hash)
 
 	return result, nil
 }
 
-func getDataByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
-	debug := ctx.IncludeDebug()
+// duplicateTxData is the data of the error for the duplicate transaction,
+// so that clients can wait for the existing one instead of failing.
+type duplicateTxData struct {
+	TxHash      jsonrpc.HexBytes `json:"txHash"`
+	Status      string           `json:"status"`
+	BlockHeight jsonrpc.HexInt   `json:"blockHeight,omitempty"`
+	Debug       string           `json:"debug,omitempty"`
+}
 
-	var param DataHashParam
-	if err := params.Convert(&param); err != nil {
+func errDuplicateTransaction(chain module.Chain, err error, id []byte, status string, debug bool) *jsonrpc.Error {
+	data := &duplicateTxData{
+		TxHash: jsonrpc.HexBytes("0x" + hex.EncodeToString(id)),
+		Status: status,
+	}
+	if status == service.DuplicateCommitted {
+		if bm := chain.BlockManager(); bm != nil {
+			if ti, err := bm.GetTransactionInfo(id); err == nil {
+				data.BlockHeight = jsonrpc.HexInt(intconv.FormatInt(ti.Block().Height()))
+			}
+		}
+	}
+	if debug {
+		data.Debug = fmt.Sprintf("%+v", err)
+	}
+	return jsonrpc.ErrorCodeSystem.New(fmt.Sprintf("%v", err), data)
+}
+
+type violation struct {
+	Code    jsonrpc.HexInt `json:"code"`
+	Message string         `json:"message"`
+}
+
+func violationOf(err error) violation {
+	return violation{
+		Code:    jsonrpc.HexInt(intconv.FormatInt(int64(errors.CodeOf(err)))),
+		Message: err.Error(),
+	}
+}
+
+func validateTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ValidateTransactionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	violations := []violation{}
+	result := map[string]interface{}{
+		"violations": &violations,
+	}
+	var txParam TransactionParam
+	if err := jsonrpc.UnmarshalWithValidate(param.Transaction, &txParam, ctx.Validator()); err != nil {
+		violations = append(violations, violationOf(errors.IllegalArgumentError.Wrap(err, "InvalidSchema")))
+		return result, nil
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	block, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	errs, err := sm.ValidateTransaction(block.Result(), block.Height()+1, []byte(param.Transaction))
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	for _, e := range errs {
+		violations = append(violations, violationOf(e))
+	}
+	return result, nil
+}
+
+func getDataByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param DataHashParam
+	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
 
@@ -571,12 +1148,12 @@ func getBlockHeaderByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (inter
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	buf := bytes.NewBuffer(nil)
-	if err := block.MarshalHeader(buf); err != nil {
+	header, err := marshalBlockHeader(block)
+	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	return buf.Bytes(), nil
+	return header, nil
 }
 
 func getVotesByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
@@ -612,18 +1189,16 @@ func getVotesByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}
 	return votes.Bytes(), nil
 }
 
-func getProofForResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+func getRandomnessByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param ProofResultParam
+	var param BlockHeightParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
-	var idx int
-	if v64, err := param.Index.ParseInt(int(unsafe.Sizeof(idx)) * 8); err != nil {
+	height, err := param.Height.ParseInt(64)
+	if err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
-	} else {
-		idx = int(v64)
 	}
 
 	chain, err := ctx.Chain()
@@ -631,50 +1206,87 @@ func getProofForResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
 
+	if err := checkBaseHeight(chain, height); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+
 	bm := chain.BlockManager()
-	sm := chain.ServiceManager()
-	if bm == nil || sm == nil {
+	if bm == nil {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	block, err := bm.GetBlock(param.BlockHash.Bytes())
+	block, err := bm.GetBlockByHeight(height)
 	if errors.NotFoundError.Equals(err) {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	if err := checkBaseHeight(chain, block.Height()); err != nil {
-		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-	}
 
-	blockResult := block.Result()
-	receiptList, err := sm.ReceiptListFromResult(blockResult, module.TransactionGroupNormal)
-	if err != nil {
+	r, err := consensus.RandomnessOf(block)
+	if errors.UnsupportedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	proofs, err := receiptList.GetProof(idx)
-	if err != nil {
-		if errors.NotFoundError.Equals(err) {
-			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	return map[string]interface{}{
+		"height":     jsonrpc.HexInt(intconv.FormatInt(height)),
+		"blockHash":  common.HexBytes(block.ID()),
+		"randomness": common.HexBytes(r),
+	}, nil
+}
+
+// receiptsToJSON returns receipts of the transactions in the block with
+// the location of them. Decoded event logs are added if decoder is not nil.
+func receiptsToJSON(blk module.Block, txs module.TransactionList, rl module.ReceiptList, decoder *EventDecoder) ([]interface{}, error) {
+	blockHash := "0x" + hex.EncodeToString(blk.ID())
+	blockHeight := "0x" + strconv.FormatInt(blk.Height(), 16)
+	receipts := []interface{}{}
+	txItr := txs.Iterator()
+	for idx, itr := 0, rl.Iterator(); itr.Has(); idx, _ = idx+1, itr.Next() {
+		receipt, err := itr.Get()
+		if err != nil {
+			return nil, err
 		}
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		tx, _, err := txItr.Get()
+		if err != nil {
+			return nil, err
+		}
+		if err := txItr.Next(); err != nil {
+			return nil, err
+		}
+		res, err := receipt.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		result := res.(map[string]interface{})
+		result["blockHash"] = blockHash
+		result["blockHeight"] = blockHeight
+		result["txIndex"] = "0x" + strconv.FormatInt(int64(idx), 16)
+		result["txHash"] = "0x" + hex.EncodeToString(tx.ID())
+		if decoder != nil {
+			logs, err := decoder.DecodeEventLogs(receipt)
+			if err != nil {
+				return nil, err
+			}
+			result["decodedEventLogs"] = logs
+		}
+		receipts = append(receipts, result)
 	}
-
-	return proofs, nil
+	return receipts, nil
 }
 
-func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+// getReceiptsByHeight returns the results of all transactions in the block
+// selected by height or hash, so that indexers don't need to get them one
+// by one. Results of patch transactions are included on request.
+func getReceiptsByHeight(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param ProofEventsParam
+	var param BlockReceiptsParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
-	var idx int
-	if v64, err := param.Index.ParseInt(int(unsafe.Sizeof(idx)) * 8); err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
-	} else {
-		idx = int(v64)
+	if (param.Height == "") == (param.Hash == "") {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NeedHeightOrHash")
 	}
 
 	chain, err := ctx.Chain()
@@ -688,156 +1300,1369 @@ func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	block, err := bm.GetBlock(param.BlockHash.Bytes())
+	var blk module.Block
+	if param.Hash != "" {
+		blk, err = bm.GetBlock(param.Hash.Bytes())
+	} else {
+		var height int64
+		if height, err = param.Height.ParseInt(64); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		blk, err = bm.GetBlockByHeight(height)
+	}
 	if errors.NotFoundError.Equals(err) {
-		err = errors.NotFoundError.Wrapf(err,
-			"fail to get a block for hash=%#x", param.BlockHash.Bytes())
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	if err := checkBaseHeight(chain, block.Height()); err != nil {
+	if err := checkBaseHeight(chain, blk.Height()); err != nil {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 	}
 
-	blockResult := block.Result()
-	receiptList, err := sm.ReceiptListFromResult(blockResult, module.TransactionGroupNormal)
-	if err != nil {
+	rl, err := bm.GetReceiptsByHeight(blk.Height())
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if block.ResultNotFinalizedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-
-	receipt, err := receiptList.Get(idx)
-	if err != nil {
-		err = errors.NotFoundError.Wrapf(err,
-			"fail to get a receipt for index=%d", idx)
-		if errors.NotFoundError.Equals(err) {
-			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	var decoder *EventDecoder
+	if param.Decoded {
+		decoder, err = eventDecoderAt(bm, sm, blk.Height())
+		if block.ResultNotFinalizedError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+		} else if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 		}
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	proofs := [][][]byte{}
-	rProof, err := receiptList.GetProof(idx)
+	receipts, err := receiptsToJSON(blk, blk.NormalTransactions(), rl, decoder)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	proofs = append(proofs, rProof)
-	for _, idx := range param.Events {
-		proof, err := receipt.GetProofOfEvent(int(idx.Value()))
-		if errors.InvalidStateError.Equals(err) {
-			break
+	result := map[string]interface{}{
+		"blockHash":   "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight": "0x" + strconv.FormatInt(blk.Height(), 16),
+		"receipts":    receipts,
+	}
+	if param.Patch {
+		// patch transactions are applied to the transition of the parent
+		// block, so their receipts are in the result of the block itself
+		// unlike ones of normal transactions.
+		prl, err := sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupPatch)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 		}
-		if errors.NotFoundError.Equals(err) {
-			err = errors.NotFoundError.Wrapf(err,
-				"fail to get a proof for event index=%d", idx.Value())
-			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		patches, err := receiptsToJSON(blk, blk.PatchTransactions(), prl, decoder)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 		}
-		proofs = append(proofs, proof)
+		result["patchReceipts"] = patches
 	}
-	return proofs, nil
+	return result, nil
 }
 
-func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
-	var param ScoreAddressParam
-	debug := ctx.IncludeDebug()
-	if err := params.Convert(&param); err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+const (
+	DefaultLogsLimit  = 100
+	MaxLogsLimit      = 1000
+	MaxLogsBlockRange = 5000
+)
+
+func getEventIndex(chain module.Chain, debug bool) (*eventindex.Index, error) {
+	ip, ok := chain.(eventindex.Provider)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.New("NotSupported")
 	}
-	chain, err := ctx.Chain()
+	index, err := ip.EventIndex()
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
-	}
-	bm := chain.BlockManager()
-	sm := chain.ServiceManager()
-	if bm == nil || sm == nil {
-		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
 	}
-	b, err := getBlock(chain, bm, param.Height)
+	return index, nil
+}
+
+func logToJSON(l *eventindex.Log) (map[string]interface{}, error) {
+	bs, err := json.Marshal(l.EventLog)
 	if err != nil {
-		if errors.NotFoundError.Equals(err) {
-			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-		}
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
-	s, err := sm.GetSCOREStatus(b.Result(), param.Address.Address())
-	if err != nil {
-		if errors.NotFoundError.Equals(err) {
-			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-		}
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	var jso map[string]interface{}
+	if err := json.Unmarshal(bs, &jso); err != nil {
+		return nil, err
 	}
-	jso, err := s.ToJSON(b.Height(), module.JSONVersion3)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	jso["blockHeight"] = intconv.FormatInt(l.Height)
+	jso["blockHash"] = common.HexBytes(l.BlockHash)
+	jso["txHash"] = common.HexBytes(l.TxHash)
+	jso["txIndex"] = intconv.FormatInt(int64(l.TxIndex))
+	jso["logIndex"] = intconv.FormatInt(int64(l.LogIndex))
+	if l.Group == module.TransactionGroupPatch {
+		jso["patch"] = true
 	}
 	return jso, nil
 }
 
-func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+// getLogs returns event logs matched with the filter in the range of blocks.
+// Logs are found with the index of the chain, which is enabled with
+// event_index of the chain configuration, so blocks are not replayed.
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param BTPQueryParam
+	var param LogsParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
-
-	height, err := param.Height.Int64()
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	limit := DefaultLogsLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxLogsLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
 	}
-	nid, err := param.Id.Int64()
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	f := &eventindex.Filter{Signature: param.Event}
+	for _, addr := range param.Addresses {
+		f.Addresses = append(f.Addresses, addr.Address())
+	}
+	if param.Event != "" {
+		name, pts := txresult.DecomposeEventSignature(param.Event)
+		if len(name) == 0 || pts == nil || len(pts) < len(param.Indexed) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidEvent(%s)", param.Event)
+		}
+		for i, v := range param.Indexed {
+			if v == nil {
+				f.Indexed = append(f.Indexed, nil)
+				continue
+			}
+			bs, err := txresult.EventDataStringToBytesByType(pts[i], *v)
+			if err != nil {
+				return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+					"InvalidIndexed(idx=%d,value=%s)", i, *v)
+			}
+			f.Indexed = append(f.Indexed, bs)
+		}
+	} else if len(param.Indexed) > 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("IndexedWithoutEvent")
 	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
-
-	bm := chain.BlockManager()
-	sm := chain.ServiceManager()
-	if bm == nil || sm == nil {
+	if chain.BlockManager() == nil || chain.ServiceManager() == nil {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
-	block, err := getBlock(chain, bm, param.Height)
-	if errors.NotFoundError.Equals(err) {
-		err = errors.NotFoundError.Wrapf(err,
-			"fail to get a block for height=%d", height)
-		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-	} else if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	index, err := getEventIndex(chain, debug)
+	if err != nil {
+		return nil, err
 	}
-
-	blockResult := block.Result()
-	nw, err := sm.BTPNetworkFromResult(blockResult, nid)
+	cursor, err := index.Cursor()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	nt, err := sm.BTPNetworkTypeFromResult(blockResult, nw.NetworkTypeID())
+	if cursor == 0 {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Indexing")
+	}
+	to := cursor - 1
+	if param.ToHeight != "" {
+		v, err := param.ToHeight.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidToHeight(%s)", param.ToHeight)
+		}
+		if v >= cursor {
+			return nil, jsonrpc.ErrorCodeExecuting.Errorf(
+				"Indexing(cursor=%d,height=%d)", cursor, v)
+		}
+		to = v
+	}
+	from := to
+	if param.FromHeight != "" {
+		v, err := param.FromHeight.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidFromHeight(%s)", param.FromHeight)
+		}
+		from = v
+	}
+	if from > to || to-from >= MaxLogsBlockRange {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidRange(from=%d,to=%d,max=%d)", from, to, MaxLogsBlockRange)
+	}
+	if err := checkBaseHeight(chain, from); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+
+	logs, next, err := index.Query(from, to, f, limit)
 	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	res := nw.ToJSON()
-	res["networkID"] = intconv.FormatInt(nid)
-	res["networkTypeName"] = nt.UID()
+	decoders := make(map[int64]*EventDecoder)
+	jsa := make([]interface{}, 0, len(logs))
+	for _, l := range logs {
+		js, err := logToJSON(l)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if param.Decoded {
+			decoder, ok := decoders[l.Height]
+			if !ok {
+				decoder, err = eventDecoderAt(chain.BlockManager(), chain.ServiceManager(), l.Height)
+				if block.ResultNotFinalizedError.Equals(err) {
+					return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+				} else if err != nil {
+					return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+				}
+				decoders[l.Height] = decoder
+			}
+			js["decoded"] = decoder.DecodeEventLog(l.EventLog)
+		}
+		jsa = append(jsa, js)
+	}
+	res := map[string]interface{}{
+		"fromHeight": intconv.FormatInt(from),
+		"toHeight":   intconv.FormatInt(to),
+		"logs":       jsa,
+	}
+	if next <= to {
+		res["next"] = intconv.FormatInt(next)
+	}
 	return res, nil
 }
 
-func getBTPNetworkTypeInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
-	debug := ctx.IncludeDebug()
+const (
+	DefaultStatementLimit = 100
+	MaxStatementLimit     = 1000
+)
 
-	var param BTPQueryParam
-	if err := params.Convert(&param); err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+func getAddressIndex(chain module.Chain, debug bool) (*addressindex.Index, error) {
+	ip, ok := chain.(addressindex.Provider)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.New("NotSupported")
 	}
-
-	height, err := param.Height.Int64()
+	index, err := ip.AddressIndex()
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
 	}
-	ntid, err := param.Id.Int64()
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	return index, nil
+}
+
+// Statement is the page of the statement of the address. Next is the
+// start of the next page, and it's -1 for the last page.
+type Statement struct {
+	Address module.Address
+	Items   []*addressindex.Item
+	Next    int64
+}
+
+// GetStatement returns the page of value movements of the address with
+// the index of the chain, which is enabled with address_index of the chain
+// configuration.
+func GetStatement(chain module.Chain, param *StatementParam, debug bool) (*Statement, error) {
+	from, err := param.FromTimestamp.Int64()
+	if err != nil || from < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidFromTimestamp(%s)", param.FromTimestamp)
+	}
+	to, err := param.ToTimestamp.Int64()
+	if err != nil || to < 0 || (to > 0 && to < from) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidToTimestamp(%s)", param.ToTimestamp)
+	}
+	start, err := param.Start.Int64()
+	if err != nil || start < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidStart(%s)", param.Start)
+	}
+	limit := DefaultStatementLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxStatementLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
+	}
+
+	if chain.BlockManager() == nil || chain.ServiceManager() == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	index, err := getAddressIndex(chain, debug)
+	if err != nil {
+		return nil, err
+	}
+	if cursor, err := index.Cursor(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else if cursor == 0 {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Indexing")
+	}
+
+	addr := param.Address.Address()
+	items, next, err := index.Statement(addr, from, to, start, limit)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return &Statement{Address: addr, Items: items, Next: next}, nil
+}
+
+func statementItemToJSON(item *addressindex.Item) interface{} {
+	jso := map[string]interface{}{
+		"blockHeight": intconv.FormatInt(item.Height),
+		"timestamp":   intconv.FormatInt(item.Timestamp),
+		"txHash":      common.HexBytes(item.TxHash),
+		"type":        item.Type,
+		"amount":      intconv.FormatBigInt(item.Amount),
+	}
+	if item.Counterparty != nil {
+		jso["counterparty"] = item.Counterparty
+	}
+	return jso
+}
+
+// ToJSON returns the statement in the result of icx_getStatement.
+func (s *Statement) ToJSON() interface{} {
+	jsa := make([]interface{}, 0, len(s.Items))
+	for _, item := range s.Items {
+		jsa = append(jsa, statementItemToJSON(item))
+	}
+	res := map[string]interface{}{
+		"address": s.Address,
+		"items":   jsa,
+	}
+	if s.Next >= 0 {
+		res["next"] = intconv.FormatInt(s.Next)
+	}
+	return res
+}
+
+// getStatement returns value movements of the address, which are transfers,
+// fees, internal transfers and rewards, in order of blocks.
+func getStatement(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param StatementParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	s, err := GetStatement(chain, &param, debug)
+	if err != nil {
+		return nil, err
+	}
+	return s.ToJSON(), nil
+}
+
+const (
+	DefaultTransactionsByAddressLimit = 100
+	MaxTransactionsByAddressLimit     = 1000
+)
+
+// getTransactionsByAddress returns the page of transactions sent from or to
+// the address with the index of the chain. Transactions are like ones of
+// icx_getTransactionByHash with the status of the result.
+func getTransactionsByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionsByAddressParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	var flags int
+	switch param.Direction {
+	case "":
+		flags = addressindex.FlagFrom | addressindex.FlagTo
+	case "from":
+		flags = addressindex.FlagFrom
+	case "to":
+		flags = addressindex.FlagTo
+	default:
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidDirection(%s)", param.Direction)
+	}
+	var reverse bool
+	switch param.Order {
+	case "", "asc":
+	case "desc":
+		reverse = true
+	default:
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidOrder(%s)", param.Order)
+	}
+	var start int64
+	if param.Start != "" {
+		v, err := param.Start.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidStart(%s)", param.Start)
+		}
+		start = v
+	} else if reverse {
+		start = math.MaxInt64
+	}
+	limit := DefaultTransactionsByAddressLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxTransactionsByAddressLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	if chain.BlockManager() == nil || chain.ServiceManager() == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	index, err := getAddressIndex(chain, debug)
+	if err != nil {
+		return nil, err
+	}
+	if cursor, err := index.Cursor(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else if cursor == 0 {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Indexing")
+	}
+
+	addr := param.Address.Address()
+	txs, next, err := index.Transactions(addr, flags, start, limit, reverse)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	jsa := make([]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		js, err := tx.Transaction.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		jso := js.(map[string]interface{})
+		jso["blockHash"] = "0x" + hex.EncodeToString(tx.Block.ID())
+		jso["blockHeight"] = intconv.FormatInt(tx.Height)
+		jso["txIndex"] = intconv.FormatInt(int64(tx.TxIndex))
+		if tx.Receipt.Status() == module.StatusSuccess {
+			jso["status"] = "0x1"
+		} else {
+			jso["status"] = "0x0"
+		}
+		jsa = append(jsa, jso)
+	}
+	res := map[string]interface{}{
+		"address":      addr,
+		"transactions": jsa,
+	}
+	if next >= 0 {
+		res["next"] = intconv.FormatInt(next)
+	}
+	return res, nil
+}
+
+func getVoteParticipation(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param HeightRangeParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	from, err := param.From.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	if err := checkBaseHeight(chain, from); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	// votes for the block are in the next block
+	to := last.Height() - 1
+	if len(param.To) > 0 {
+		h, err := param.To.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if h < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidRange(from=%d,to=%d)", from, h)
+		}
+		if h > to {
+			return nil, jsonrpc.ErrorCodeNotFound.Errorf(
+				"NoVotes(height=%d)", h)
+		}
+		to = h
+	}
+	if from > to {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf("NoVotes(height=%d)", from)
+	}
+	var next int64
+	if to-from >= ConfigMaxVoteBlocks {
+		next = from + ConfigMaxVoteBlocks
+		to = next - 1
+	}
+
+	vp := newVoteParticipation()
+	for h := from; h <= to; h++ {
+		blk, err := bm.GetBlockByHeight(h + 1)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		csi, err := bm.NewConsensusInfo(blk)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		vp.add(csi)
+	}
+	res := map[string]interface{}{
+		"from":       intconv.FormatInt(from),
+		"to":         intconv.FormatInt(to),
+		"validators": vp.ToJSON(),
+	}
+	if next > 0 {
+		res["next"] = intconv.FormatInt(next)
+	}
+	return res, nil
+}
+
+func marshalBlockHeader(blk module.Block) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := blk.MarshalHeader(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// getValidatorTransitions returns headers and votes of the blocks changing
+// validators in the range, so that light clients trusting validators of
+// the block at from verify the block at to without other headers.
+func getValidatorTransitions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param HeightRangeParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	from, err := param.From.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	cs := chain.Consensus()
+	if bm == nil || cs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	if err := checkBaseHeight(chain, from); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	to := last.Height()
+	if len(param.To) > 0 {
+		h, err := param.To.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if h < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidRange(from=%d,to=%d)", from, h)
+		}
+		if h > to {
+			return nil, jsonrpc.ErrorCodeNotFound.Errorf(
+				"NoBlock(height=%d)", h)
+		}
+		to = h
+	}
+	if from > to {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf("NoBlock(height=%d)", from)
+	}
+	var next int64
+	if to-from > ConfigMaxValidatorTransitionBlocks {
+		next = from + ConfigMaxValidatorTransitionBlocks
+		to = next
+	}
+
+	proofOf := func(blk module.Block) (map[string]interface{}, error) {
+		header, err := marshalBlockHeader(blk)
+		if err != nil {
+			return nil, err
+		}
+		votes, err := cs.GetVotesByHeight(blk.Height())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"height": intconv.FormatInt(blk.Height()),
+			"header": header,
+			"votes":  votes.Bytes(),
+		}, nil
+	}
+
+	blk, err := bm.GetBlockByHeight(from)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	transitions := make([]interface{}, 0)
+	validators := blk.NextValidatorsHash()
+	for h := from + 1; h <= to; h++ {
+		if blk, err = bm.GetBlockByHeight(h); err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if bytes.Equal(validators, blk.NextValidatorsHash()) {
+			continue
+		}
+		validators = blk.NextValidatorsHash()
+		proof, err := proofOf(blk)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if nvs := blk.NextValidators(); nvs != nil {
+			proof["validators"] = nvs.Bytes()
+		}
+		transitions = append(transitions, proof)
+	}
+
+	res := map[string]interface{}{
+		"from":        intconv.FormatInt(from),
+		"to":          intconv.FormatInt(to),
+		"transitions": transitions,
+	}
+	if to > from {
+		// blk is the block at to
+		target, err := proofOf(blk)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		res["header"] = target["header"]
+		res["votes"] = target["votes"]
+	}
+	if next > 0 {
+		res["next"] = intconv.FormatInt(next)
+	}
+	return res, nil
+}
+
+func getRoundHistory(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param HeightParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	height, err := param.Height.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	cs := chain.Consensus()
+	if cs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	history := cs.GetRoundHistory(height)
+	res := make([]interface{}, len(history))
+	for i, rh := range history {
+		failures := make([]interface{}, len(rh.Failures))
+		for j, f := range rh.Failures {
+			failures[j] = map[string]interface{}{
+				"round":    intconv.FormatInt(int64(f.Round)),
+				"proposer": f.Proposer,
+				"reason":   f.Reason,
+			}
+		}
+		res[i] = map[string]interface{}{
+			"height":      intconv.FormatInt(rh.Height),
+			"commitRound": intconv.FormatInt(int64(rh.CommitRound)),
+			"failures":    failures,
+		}
+	}
+	return res, nil
+}
+
+func getProofForResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofResultParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	var idx int
+	if v64, err := param.Index.ParseInt(int(unsafe.Sizeof(idx)) * 8); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	} else {
+		idx = int(v64)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	block, err := bm.GetBlock(param.BlockHash.Bytes())
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := checkBaseHeight(chain, block.Height()); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+
+	blockResult := block.Result()
+	receiptList, err := sm.ReceiptListFromResult(blockResult, module.TransactionGroupNormal)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	proofs, err := receiptList.GetProof(idx)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	return proofs, nil
+}
+
+func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofEventsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	var idx int
+	if v64, err := param.Index.ParseInt(int(unsafe.Sizeof(idx)) * 8); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	} else {
+		idx = int(v64)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	block, err := bm.GetBlock(param.BlockHash.Bytes())
+	if errors.NotFoundError.Equals(err) {
+		err = errors.NotFoundError.Wrapf(err,
+			"fail to get a block for hash=%#x", param.BlockHash.Bytes())
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := checkBaseHeight(chain, block.Height()); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+
+	blockResult := block.Result()
+	receiptList, err := sm.ReceiptListFromResult(blockResult, module.TransactionGroupNormal)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	receipt, err := receiptList.Get(idx)
+	if err != nil {
+		err = errors.NotFoundError.Wrapf(err,
+			"fail to get a receipt for index=%d", idx)
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	proofs := [][][]byte{}
+	rProof, err := receiptList.GetProof(idx)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	proofs = append(proofs, rProof)
+	for _, idx := range param.Events {
+		proof, err := receipt.GetProofOfEvent(int(idx.Value()))
+		if errors.InvalidStateError.Equals(err) {
+			break
+		}
+		if errors.NotFoundError.Equals(err) {
+			err = errors.NotFoundError.Wrapf(err,
+				"fail to get a proof for event index=%d", idx.Value())
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, nil
+}
+
+// getProofForTransaction returns everything to verify the receipt of the
+// transaction in one response. The receipt is in the result of the next
+// block of the block including the transaction, so the header and the votes
+// are of the next block, and the validators are the ones of the next block
+// (the next validators of the block including the transaction).
+func getProofForTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofTransactionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	cs := chain.Consensus()
+	if bm == nil || sm == nil || cs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	txInfo, err := bm.GetTransactionInfo(param.Hash.Bytes())
+	if errors.NotFoundError.Equals(err) {
+		if sm.HasTransaction(param.Hash.Bytes()) {
+			return nil, jsonrpc.ErrorCodePending.New("Pending")
+		}
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	blk := txInfo.Block()
+	if err := checkBaseHeight(chain, blk.Height()); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	rblk, err := bm.GetBlockByHeight(blk.Height() + 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	header := bytes.NewBuffer(nil)
+	if err := rblk.MarshalHeader(header); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	votes, err := cs.GetVotesByHeight(rblk.Height())
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	receiptList, err := sm.ReceiptListFromResult(rblk.Result(), txInfo.Group())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	receipt, err := receiptList.Get(txInfo.Index())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	rProof, err := receiptList.GetProof(txInfo.Index())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	result := map[string]interface{}{
+		"txHash":         "0x" + hex.EncodeToString(param.Hash.Bytes()),
+		"txIndex":        "0x" + strconv.FormatInt(int64(txInfo.Index()), 16),
+		"blockHash":      "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight":    "0x" + strconv.FormatInt(blk.Height(), 16),
+		"header":         header.Bytes(),
+		"votes":          votes.Bytes(),
+		"receipt":        receipt.Bytes(),
+		"receiptProof":   rProof,
+		"validatorsHash": "0x" + hex.EncodeToString(blk.NextValidatorsHash()),
+	}
+	if txInfo.Group() == module.TransactionGroupPatch {
+		result["patch"] = true
+	}
+	if vl := blk.NextValidators(); vl != nil {
+		result["validators"] = vl.Bytes()
+	}
+	if len(param.Events) > 0 {
+		eProofs := make([][][]byte, 0, len(param.Events))
+		for _, idx := range param.Events {
+			proof, err := receipt.GetProofOfEvent(int(idx.Value()))
+			if err != nil {
+				if errors.NotFoundError.Equals(err) || errors.InvalidStateError.Equals(err) {
+					err = errors.NotFoundError.Wrapf(err,
+						"fail to get a proof for event index=%d", idx.Value())
+					return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+				}
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			}
+			eProofs = append(eProofs, proof)
+		}
+		result["eventProofs"] = eProofs
+	}
+	return result, nil
+}
+
+// getProofForExtension returns the value in the store of the extension
+// state at the block, and the proof of it. The extension data is in the
+// result of the header of the block.
+func getProofForExtension(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofExtensionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	cs := chain.Consensus()
+	if bm == nil || sm == nil || cs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	blk, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	ep, err := sm.ExtensionProofFromResult(blk.Result(), param.Store, param.Key.Bytes())
+	if err != nil {
+		switch {
+		case errors.NotFoundError.Equals(err):
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		case errors.IllegalArgumentError.Equals(err):
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		case errors.UnsupportedError.Equals(err):
+			return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+		default:
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+	}
+
+	header := bytes.NewBuffer(nil)
+	if err := blk.MarshalHeader(header); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result := map[string]interface{}{
+		"blockHash":   "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight": "0x" + strconv.FormatInt(blk.Height(), 16),
+		"header":      header.Bytes(),
+		"extension":   ep.Data,
+		"store":       param.Store,
+		"root":        "0x" + hex.EncodeToString(ep.Root),
+		"value":       ep.Value,
+		"proof":       ep.Proof,
+	}
+	if votes, err := cs.GetVotesByHeight(blk.Height()); err == nil {
+		result["votes"] = votes.Bytes()
+	}
+	return result, nil
+}
+
+func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var param ScoreAddressParam
+	debug := ctx.IncludeDebug()
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	b, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	s, err := sm.GetSCOREStatus(b.Result(), param.Address.Address())
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	jso, err := s.ToJSON(b.Height(), module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return jso, nil
+}
+
+const (
+	DefaultScoreStatusLimit = 100
+	MaxScoreStatusLimit     = 100
+)
+
+func getSCOREIndex(chain module.Chain, debug bool) (*scoreindex.Index, error) {
+	ip, ok := chain.(scoreindex.Provider)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.New("NotSupported")
+	}
+	index, err := ip.SCOREIndex()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	}
+	return index, nil
+}
+
+// getScoreStatusList returns the status of contracts deployed before the
+// height in order of deployment. Contracts are listed with the index of
+// the chain, which is enabled with score_index of the chain configuration.
+func getScoreStatusList(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ScoreStatusListParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	var start int64
+	if param.Start != "" {
+		v, err := param.Start.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidStart(%s)", param.Start)
+		}
+		start = v
+	}
+	limit := DefaultScoreStatusLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxScoreStatusLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	index, err := getSCOREIndex(chain, debug)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	// the result of the block has contracts deployed before the block.
+	if cursor, err := index.Cursor(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else if cursor < blk.Height() {
+		return nil, jsonrpc.ErrorCodeExecuting.Errorf(
+			"Indexing(cursor=%d,height=%d)", cursor, blk.Height())
+	}
+
+	entries, total, err := index.List(blk.Height(), start, limit)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	scores := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		var jso map[string]interface{}
+		s, err := sm.GetSCOREStatus(blk.Result(), e.Address)
+		if err == nil {
+			var js interface{}
+			js, err = s.ToJSON(blk.Height(), module.JSONVersion3)
+			jso, _ = js.(map[string]interface{})
+		}
+		if err != nil && !errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if jso == nil {
+			jso = make(map[string]interface{})
+		}
+		jso["address"] = e.Address
+		jso["deployHeight"] = intconv.FormatInt(e.Height)
+		scores = append(scores, jso)
+	}
+	res := map[string]interface{}{
+		"height": intconv.FormatInt(blk.Height()),
+		"total":  intconv.FormatInt(total),
+		"scores": scores,
+	}
+	if next := start + int64(len(entries)); next < total {
+		res["next"] = intconv.FormatInt(next)
+	}
+	return res, nil
+}
+
+// getPendingDeployments returns deployments waiting for audit at the last
+// block of the index in order of deployment.
+func getPendingDeployments(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	index, err := getSCOREIndex(chain, debug)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := index.Pending()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	cursor, err := index.Cursor()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if cursor == 0 {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Indexing")
+	}
+	blk, err := bm.GetBlockByHeight(cursor)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	deployments := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		// the index may be updated after getting the cursor.
+		if e.Height >= blk.Height() {
+			continue
+		}
+		s, err := sm.GetSCOREStatus(blk.Result(), e.Address)
+		if err != nil {
+			if errors.NotFoundError.Equals(err) {
+				continue
+			}
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		if s.PendingTxHash() == nil {
+			continue
+		}
+		js, err := s.ToJSON(blk.Height(), module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		jso := js.(map[string]interface{})
+		next, ok := jso["next"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		next["address"] = e.Address
+		next["owner"] = jso["owner"]
+		next["deployHeight"] = intconv.FormatInt(e.Height)
+		deployments = append(deployments, next)
+	}
+	return map[string]interface{}{
+		"height":      intconv.FormatInt(blk.Height()),
+		"deployments": deployments,
+	}, nil
+}
+
+func getFeeSharingStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var param ScoreAddressParam
+	debug := ctx.IncludeDebug()
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	b, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	s, err := sm.GetFeeSharingStatus(b.Result(), param.Address.Address())
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		if errors.IllegalArgumentError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	jso, err := s.ToJSON(b.Height(), module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return jso, nil
+}
+
+func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param BTPQueryParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	height, err := param.Height.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	nid, err := param.Id.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	block, err := getBlock(chain, bm, param.Height)
+	if errors.NotFoundError.Equals(err) {
+		err = errors.NotFoundError.Wrapf(err,
+			"fail to get a block for height=%d", height)
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	blockResult := block.Result()
+	nw, err := sm.BTPNetworkFromResult(blockResult, nid)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	nt, err := sm.BTPNetworkTypeFromResult(blockResult, nw.NetworkTypeID())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	res := nw.ToJSON()
+	res["networkID"] = intconv.FormatInt(nid)
+	res["networkTypeName"] = nt.UID()
+	err = fillBTPNetworkLifecycle(res, btpNetworkAtOf(bm, sm), nid, nw, block.Height())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return res, nil
+}
+
+func getBTPNetworkTypeInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param BTPQueryParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	height, err := param.Height.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	ntid, err := param.Id.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	block, err := getBlock(chain, bm, param.Height)
+	if errors.NotFoundError.Equals(err) {
+		err = errors.NotFoundError.Wrapf(err, "fail to get a block for height=%d", height)
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	blockResult := block.Result()
+	nt, err := sm.BTPNetworkTypeFromResult(blockResult, ntid)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	res := nt.ToJSON()
+	res["networkTypeID"] = intconv.FormatInt(ntid)
+	closed, err := closedBTPNetworkIDs(btpNetworkAtOf(bm, sm), ntid, block.Height())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	res["closedNetworkIDs"] = closed
+	return res, nil
+}
+
+func getBTPPublicKeys(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param BTPPublicKeysParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	height, err := param.Height.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
 
 	chain, err := ctx.Chain()
@@ -857,14 +2682,90 @@ func getBTPNetworkTypeInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interf
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	height = block.Height()
+
+	var dsa string
+	if len(param.NetworkTypeID) > 0 {
+		ntid, err := param.NetworkTypeID.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		nt, err := sm.BTPNetworkTypeFromResult(block.Result(), ntid)
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		} else if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		mod := ntm.ForUID(nt.UID())
+		if mod == nil {
+			return nil, jsonrpc.ErrorCodeSystem.Errorf("UnknownNetworkType(uid=%s)", nt.UID())
+		}
+		dsa = mod.DSA()
+	}
+
+	vl := block.NextValidators()
+	if vl == nil {
+		return nil, jsonrpc.ErrorCodeSystem.New("NoValidators")
+	}
+	addrs := make([]module.Address, vl.Len())
+	for i := range addrs {
+		v, _ := vl.Get(i)
+		addrs[i] = v.Address()
+	}
+	keysAt := func(h int64) (btpPublicKeys, error) {
+		blk, err := bm.GetBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		return sm.BTPPublicKeysFromResult(blk.Result(), addrs, dsa)
+	}
 
-	blockResult := block.Result()
-	nt, err := sm.BTPNetworkTypeFromResult(blockResult, ntid)
+	keys, err := keysAt(height)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	res := nt.ToJSON()
-	res["networkTypeID"] = intconv.FormatInt(ntid)
+	validators := make([]interface{}, len(addrs))
+	for i, addr := range addrs {
+		pks := make(map[string]interface{}, len(keys))
+		for _, name := range keys.names() {
+			if key := keys.get(name, i); len(key) > 0 {
+				pks[name] = common.HexBytes(key)
+			} else {
+				pks[name] = nil
+			}
+		}
+		validators[i] = map[string]interface{}{
+			"address":    addr,
+			"publicKeys": pks,
+		}
+	}
+	res := map[string]interface{}{
+		"height":     intconv.FormatInt(height),
+		"validators": validators,
+	}
+
+	if len(param.From) > 0 {
+		from, err := param.From.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if err := checkBaseHeight(chain, from); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if from > height {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidFrom(from=%d,height=%d)", from, height)
+		}
+		changes, err := btpPublicKeyChanges(keysAt, from, height)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		history := make([]interface{}, len(changes))
+		for i, c := range changes {
+			history[i] = c.ToJSON(addrs)
+		}
+		res["changes"] = history
+	}
 	return res, nil
 }
 
@@ -1239,174 +3140,753 @@ func waitTransactionResultOnChannel(ctx *jsonrpc.Context, chain module.Chain, bm
 	if err := checkBaseHeight(chain, blk.Height()); err != nil {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 	}
-	res, err := receipt.ToJSON(module.JSONVersion3)
+	res, err := receipt.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result := res.(map[string]interface{})
+	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
+	result["blockHeight"] = "0x" + strconv.FormatInt(int64(blk.Height()), 16)
+	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
+	result["txHash"] = "0x" + hex.EncodeToString(id)
+
+	return result, nil
+}
+
+func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
+	mr := jsonrpc.NewMethodRepository(mtr)
+	RegisterValidationRule(mr.Validator())
+
+	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_getStateDiff", getStateDiff)
+	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_estimateStepBatch", estimateStepBatch)
+	mr.RegisterMethod("debug_estimateFee", estimateFee)
+	mr.RegisterMethod("debug_getStaleState", getStaleState)
+	mr.RegisterMethod("debug_verifyScore", verifyScore)
+	mr.RegisterMethod("debug_getHotspots", getHotspots)
+	mr.RegisterMethod("debug_getStepLimitSuggestion", getStepLimitSuggestion)
+	mr.RegisterMethod("debug_simulateProposal", simulateProposal)
+
+	setDebugSchemas(mr)
+	return mr
+}
+
+func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionHashParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	tr, txInfo, err := ReplayTransition(chain, param.Hash.Bytes(), debug)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &traceCallback{
+		logs:    make([]interface{}, 0, 100),
+		channel: make(chan interface{}, 10),
+		limit:   ctx.TraceLimit(),
+	}
+	ti := module.TraceInfo{
+		TraceMode: module.TraceModeInvoke,
+		Range:     module.TraceRangeTransaction,
+		Group:     txInfo.Group(),
+		Index:     txInfo.Index(),
+		Callback:  cb,
+	}
+	canceller, err := tr.ExecuteForTrace(ti)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	timer := time.After(TraceTimeout(ctx.GetTimeout(0), cb.limit))
+	for {
+		select {
+		case <-timer:
+			canceller()
+			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+				"Not enough time to get result of %x", param.Hash.Bytes())
+		case <-cb.channel:
+			return cb.invokeTraceToJSON(), nil
+		}
+	}
+	return nil, jsonrpc.ErrorCodeSystem.New("Unknown error on channel")
+}
+
+// StreamTrace executes the transaction again like debug_getTrace, and sends
+// trace logs with send as they are made, followed by the end frame with the
+// result. The execution is canceled after the timeout or on abort.
+func StreamTrace(chain module.Chain, txHash []byte, timeout time.Duration, limit jsonrpc.TraceLimit, send func(frame interface{}) error, abort <-chan error) error {
+	tr, txInfo, err := ReplayTransition(chain, txHash, true)
+	if err != nil {
+		return err
+	}
+
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+		limit:   limit,
+		send:    send,
+	}
+	ti := module.TraceInfo{
+		TraceMode: module.TraceModeInvoke,
+		Range:     module.TraceRangeTransaction,
+		Group:     txInfo.Group(),
+		Index:     txInfo.Index(),
+		Callback:  cb,
+	}
+	canceller, err := tr.ExecuteForTrace(ti)
+	if err != nil {
+		return jsonrpc.ErrorCodeSystem.Wrap(err, true)
+	}
+
+	select {
+	case <-time.After(TraceTimeout(timeout, limit)):
+		canceller()
+		return jsonrpc.ErrorCodeSystemTimeout.Errorf(
+			"Not enough time to get result of %x", txHash)
+	case err := <-abort:
+		canceller()
+		return err
+	case <-cb.channel:
+		cb.lock.Lock()
+		err := cb.sendErr
+		cb.lock.Unlock()
+		if err != nil {
+			return err
+		}
+		return send(cb.endFrame())
+	}
+}
+
+func getStateDiff(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionHashParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	tr, txInfo, err := ReplayTransition(chain, param.Hash.Bytes(), debug)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+		st:      trace.NewStateTracer(),
+		limit:   ctx.TraceLimit(),
+	}
+	ti := module.TraceInfo{
+		TraceMode: module.TraceModeStateDiff,
+		Range:     module.TraceRangeTransaction,
+		Group:     txInfo.Group(),
+		Index:     txInfo.Index(),
+		Callback:  cb,
+	}
+	canceller, err := tr.ExecuteForTrace(ti)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	select {
+	case <-time.After(TraceTimeout(ctx.GetTimeout(0), cb.limit)):
+		canceller()
+		return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+			"Not enough time to get result of %x", param.Hash.Bytes())
+	case <-cb.channel:
+		return cb.stateDiffToJSON(), nil
+	}
+}
+
+// ReplayTransition returns the transition to execute the block including
+// the transaction again.
+func ReplayTransition(chain module.Chain, txHash []byte, debug bool) (module.Transition, module.TransactionInfo, error) {
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	txInfo, err := bm.GetTransactionInfo(txHash)
+	if errors.NotFoundError.Equals(err) {
+		if sm.HasTransaction(txHash) {
+			return nil, nil, jsonrpc.ErrorCodePending.New("Pending")
+		}
+		return nil, nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	if txInfo.Group() == module.TransactionGroupPatch {
+		return nil, nil, jsonrpc.ErrorCodeInvalidParams.New("Patch transaction can't be replayed")
+	}
+
+	blk := txInfo.Block()
+	if err = checkBaseHeight(chain, blk.Height()); err != nil {
+		return nil, nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	_, err = txInfo.GetReceipt()
+	if block.ResultNotFinalizedError.Equals(err) {
+		return nil, nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	csi, err := bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	nblk, err := bm.GetBlockByHeight(blk.Height() + 1)
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	tr1, err := sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	tr2, err := sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk), txInfo, nil
+}
+
+// executeForEstimate executes the transaction on the state of the last block
+// without adding it to the pool, and returns the last block with the receipt.
+func executeForEstimate(chain module.Chain, params *jsonrpc.Params, debug bool) (module.Block, module.Receipt, error) {
+	var param TransactionParamForEstimate
+	if err := params.Convert(&param); err != nil {
+		return nil, nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, nil, jsonrpc.ErrorCodeServer.New("ChannelStopped")
+	}
+
+	// get last block
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	// execute transaction
+	rct, err := sm.ExecuteTransaction(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		params.RawMessage(),
+		blockInfoForEstimate(blk),
+	)
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	if rct.Status() != module.StatusSuccess {
+		return nil, nil, errOfReceipt(rct, debug)
+	}
+	return blk, rct, nil
+}
+
+// blockInfoForEstimate returns the information of the new block based on
+// the last block for executing transactions.
+func blockInfoForEstimate(blk module.Block) module.BlockInfo {
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	return common.NewBlockInfo(blk.Height()+1, newTS)
+}
+
+// errOfReceipt returns the error of the failed receipt with the steps used.
+func errOfReceipt(rct module.Receipt, debug bool) *jsonrpc.Error {
+	if rctex, ok := rct.(txresult.Receipt); ok {
+		if err := rctex.Reason(); err != nil {
+			return jsonrpc.ErrScoreWithSteps(err, rct.StepUsed(), debug)
+		}
+	}
+	status := rct.Status()
+	return jsonrpc.ErrScoreWithSteps(
+		scoreresult.New(status, status.String()), rct.StepUsed(), debug)
+}
+
+func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	_, rct, err := executeForEstimate(chain, params, debug)
+	if err != nil {
+		return nil, err
+	}
+	steps := new(common.HexInt)
+	steps.Set(rct.StepUsed())
+	return steps, nil
+}
+
+// estimateStepBatch returns the steps of the transactions executed in order
+// on the last block. Each transaction is executed with the changes of the
+// previous ones, so that dependent transactions can be estimated.
+func estimateStepBatch(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionBatchParamForEstimate
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if len(param.Transactions) > ConfigMaxEstimateTransactions {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooManyTransactions(max=%d)", ConfigMaxEstimateTransactions)
+	}
+	var raw struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal(params.RawMessage(), &raw); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	jss := make([][]byte, len(raw.Transactions))
+	for i, js := range raw.Transactions {
+		jss[i] = js
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("ChannelStopped")
+	}
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	rcts, err := service.ExecuteTransactions(sm,
+		blk.Result(), blk.NextValidators().Hash(), jss, blockInfoForEstimate(blk))
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
-	result := res.(map[string]interface{})
-	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
-	result["blockHeight"] = "0x" + strconv.FormatInt(int64(blk.Height()), 16)
-	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
-	result["txHash"] = "0x" + hex.EncodeToString(id)
+	steps := make([]string, len(rcts))
+	total := new(big.Int)
+	for i, rct := range rcts {
+		if rct.Status() != module.StatusSuccess {
+			jerr := errOfReceipt(rct, debug)
+			if data, ok := jerr.Data.(*jsonrpc.ScoreErrorData); ok {
+				data.TxIndex = jsonrpc.HexInt(intconv.FormatInt(int64(i)))
+			}
+			return nil, jerr
+		}
+		steps[i] = intconv.FormatBigInt(rct.StepUsed())
+		total.Add(total, rct.StepUsed())
+	}
+	return map[string]interface{}{
+		"steps": steps,
+		"total": intconv.FormatBigInt(total),
+	}, nil
+}
 
-	return result, nil
+// feeEstimateBlocks is the number of recent blocks used to get the
+// utilization and the interval of blocks for debug_estimateFee.
+const feeEstimateBlocks = 10
+
+// recentBlockStats returns the average number of normal transactions and
+// the average interval in microseconds of the recent blocks up to blk.
+func recentBlockStats(chain module.Chain, blk module.Block) (float64, int64) {
+	bm := chain.BlockManager()
+	base := chain.GenesisStorage().Height()
+	txs, blocks := 0, 0
+	first := blk
+	for b := blk; ; {
+		for it := b.NormalTransactions().Iterator(); it.Has(); it.Next() {
+			txs += 1
+		}
+		blocks += 1
+		first = b
+		if blocks >= feeEstimateBlocks || b.Height() <= base {
+			break
+		}
+		prev, err := bm.GetBlockByHeight(b.Height() - 1)
+		if err != nil {
+			break
+		}
+		b = prev
+	}
+	var interval int64
+	if blocks > 1 {
+		interval = (blk.Timestamp() - first.Timestamp()) / int64(blocks-1)
+	}
+	return float64(txs) / float64(blocks), interval
 }
 
-func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
-	mr := jsonrpc.NewMethodRepository(mtr)
-	RegisterValidationRule(mr.Validator())
+func estimateFee(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
 
-	mr.RegisterMethod("debug_getTrace", getTrace)
-	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
 
-	return mr
+	blk, rct, err := executeForEstimate(chain, params, debug)
+	if err != nil {
+		return nil, err
+	}
+
+	sm := chain.ServiceManager()
+	price, err := service.GetStepPrice(sm, blk.Result())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	pool, err := service.GetPoolStatus(sm, module.TransactionGroupNormal)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	capacity := chain.Regulator().MaxTxCount()
+	average, interval := recentBlockStats(chain, blk)
+	blocks := service.PredictInclusion(pool.Used, capacity, average)
+
+	var utilization int64
+	if capacity > 0 {
+		utilization = int64(average * 100 / float64(capacity))
+	}
+	fee := new(big.Int).Mul(rct.StepUsed(), price)
+	return map[string]interface{}{
+		"step":             intconv.FormatBigInt(rct.StepUsed()),
+		"stepPrice":        intconv.FormatBigInt(price),
+		"fee":              intconv.FormatBigInt(fee),
+		"poolDepth":        intconv.FormatInt(int64(pool.Used)),
+		"poolSize":         intconv.FormatInt(int64(pool.Size)),
+		"blockCapacity":    intconv.FormatInt(int64(capacity)),
+		"blockUtilization": intconv.FormatInt(utilization),
+		"blockInterval":    intconv.FormatInt(interval / 1000),
+		"inclusionBlocks":  intconv.FormatInt(blocks),
+		"inclusionTime":    intconv.FormatInt(blocks * interval / 1000),
+	}, nil
 }
 
-func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+const (
+	DefaultStaleStateLimit = 100
+	MaxStaleStateLimit     = 1000
+)
+
+// getStaleState returns the accounts not accessed in the last age blocks
+// before the height. The heights of accesses are recorded by the node only
+// with access_index of the chain, so accounts not accessed since the index
+// is enabled have zero.
+func getStaleState(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
-	var param TransactionHashParam
+	var param StaleStateParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	age, err := param.Age.Int64()
+	if err != nil || age < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidAge(%s)", param.Age)
+	}
+	limit := DefaultStaleStateLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxStaleStateLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
+	}
 
 	chain, err := ctx.Chain()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
-
 	bm := chain.BlockManager()
 	sm := chain.ServiceManager()
 	if bm == nil || sm == nil {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
-
-	txInfo, err := bm.GetTransactionInfo(param.Hash.Bytes())
-	if errors.NotFoundError.Equals(err) {
-		if sm.HasTransaction(param.Hash.Bytes()) {
-			return nil, jsonrpc.ErrorCodePending.New("Pending")
+	blk, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 		}
-		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	if txInfo.Group() == module.TransactionGroupPatch {
-		return nil, jsonrpc.ErrorCodeInvalidParams.New("Patch transaction can't be replayed")
-	}
-
-	blk := txInfo.Block()
-	if err = checkBaseHeight(chain, blk.Height()); err != nil {
-		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
-	}
-	_, err = txInfo.GetReceipt()
-	if block.ResultNotFinalizedError.Equals(err) {
-		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
-	} else if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
-	}
-	csi, err := bm.NewConsensusInfo(blk)
+	accounts, next, err := service.FindStaleAccounts(sm, blk.Result(),
+		blk.Height()-age, param.Start.Bytes(), limit)
 	if err != nil {
+		if errors.UnsupportedError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	nblk, err := bm.GetBlockByHeight(blk.Height() + 1)
+	indexStart, err := service.AccessIndexStart(sm)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	tr1, err := sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	list := make([]interface{}, 0, len(accounts))
+	for _, a := range accounts {
+		contract := "0x0"
+		if a.Contract {
+			contract = "0x1"
+		}
+		list = append(list, map[string]interface{}{
+			"key":           a.Key,
+			"contract":      contract,
+			"accessHeight":  intconv.FormatInt(a.Account),
+			"storageHeight": intconv.FormatInt(a.Storage),
+		})
 	}
-	tr2, err := sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	res := map[string]interface{}{
+		"height":     intconv.FormatInt(blk.Height()),
+		"indexStart": intconv.FormatInt(indexStart),
+		"accounts":   list,
 	}
-	tr2 = sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
+	if next != nil {
+		res["next"] = common.HexBytes(next)
+	}
+	return res, nil
+}
 
-	cb := &traceCallback{
-		logs:    make([]interface{}, 0, 100),
-		channel: make(chan interface{}, 10),
+const (
+	DefaultHotspotLimit = 10
+	MaxHotspotLimit     = 100
+)
+
+// getHotspots returns the contracts and the methods consuming the most
+// wall time in the last blocks up to the height, which are sampled while
+// executing transactions. Only recent blocks executed by the node are
+// available.
+func getHotspots(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param HotspotsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
-	ti := module.TraceInfo{
-		TraceMode: module.TraceModeInvoke,
-		Range:     module.TraceRangeTransaction,
-		Group:     txInfo.Group(),
-		Index:     txInfo.Index(),
-		Callback:  cb,
+	blocks := int64(1)
+	if param.Blocks != "" {
+		v, err := param.Blocks.Int64()
+		if err != nil || v <= 0 || v > hotspot.DefaultBlocks {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidBlocks(%s)", param.Blocks)
+		}
+		blocks = v
 	}
-	canceller, err := tr2.ExecuteForTrace(ti)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	limit := DefaultHotspotLimit
+	if param.Limit != "" {
+		v, err := param.Limit.Int64()
+		if err != nil || v <= 0 || v > MaxHotspotLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidLimit(%s)", param.Limit)
+		}
+		limit = int(v)
 	}
 
-	timer := time.After(time.Second * 5)
-	for {
-		select {
-		case <-timer:
-			canceller()
-			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
-				"Not enough time to get result of %x", param.Hash.Bytes())
-		case <-cb.channel:
-			return cb.invokeTraceToJSON(), nil
-		}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
-	return nil, jsonrpc.ErrorCodeSystem.New("Unknown error on channel")
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	p, err := service.HotspotProfiler(sm)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	}
+	height := p.LastHeight()
+	if param.Height != "" {
+		v, err := param.Height.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidHeight(%s)", param.Height)
+		}
+		height = v
+	}
+
+	s := p.Top(height-blocks+1, height, limit)
+	list := make([]interface{}, 0, len(s.Entries))
+	for _, e := range s.Entries {
+		list = append(list, map[string]interface{}{
+			"address":  e.Address,
+			"method":   e.Method,
+			"samples":  intconv.FormatInt(e.Samples),
+			"duration": intconv.FormatInt(e.Duration.Microseconds()),
+		})
+	}
+	return map[string]interface{}{
+		"from":      intconv.FormatInt(s.From),
+		"to":        intconv.FormatInt(s.To),
+		"blocks":    intconv.FormatInt(int64(s.Blocks)),
+		"samples":   intconv.FormatInt(s.Samples),
+		"duration":  intconv.FormatInt(s.Duration.Microseconds()),
+		"contracts": list,
+	}, nil
 }
 
-func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+// getStepLimitSuggestion returns the maximum step limit for invoke
+// suggested from the throughput of the execution of recent blocks.
+func getStepLimitSuggestion(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
 	chain, err := ctx.Chain()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	a, err := service.StepLimitAdvisorOf(sm)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+	}
+	s, err := a.Suggest()
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return map[string]interface{}{
+		"from":           intconv.FormatInt(s.From),
+		"to":             intconv.FormatInt(s.To),
+		"blocks":         intconv.FormatInt(int64(s.Blocks)),
+		"steps":          intconv.FormatInt(s.Steps),
+		"duration":       intconv.FormatInt(s.Duration.Microseconds()),
+		"stepsPerSecond": intconv.FormatInt(s.StepsPerSecond),
+		"target":         intconv.FormatInt(s.Target.Microseconds()),
+		"current":        intconv.FormatBigInt(s.Current),
+		"suggested":      intconv.FormatBigInt(s.Suggested),
+	}, nil
+}
 
-	var param TransactionParamForEstimate
+const (
+	DefaultSimulationTransactions = 100
+	MaxSimulationTransactions     = 500
+)
+
+// simulateProposal applies the calls of the network proposal to a copy of
+// the state of the last block, and executes transactions in the pool with
+// and without it. It returns the transactions having different results,
+// so that validators may see the effects of the proposal before voting.
+func simulateProposal(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProposalSimulationParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
 	}
+	n := DefaultSimulationTransactions
+	if param.Transactions != "" {
+		v, err := param.Transactions.Int64()
+		if err != nil || v <= 0 || v > MaxSimulationTransactions {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidTransactions(%s)", param.Transactions)
+		}
+		n = int(v)
+	}
+	calls := make([]*service.ProposalCall, len(param.Calls))
+	for i, c := range param.Calls {
+		var to module.Address = state.SystemAddress
+		if c.To != "" {
+			to = c.To.Address()
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"method": c.Method,
+			"params": c.Params,
+		})
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		calls[i] = &service.ProposalCall{To: to, Data: data}
+	}
 
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
 	bm := chain.BlockManager()
 	sm := chain.ServiceManager()
 	if bm == nil || sm == nil {
-		return nil, jsonrpc.ErrorCodeServer.New("ChannelStopped")
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
-
-	// get last block
 	blk, err := bm.GetLastBlock()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
 
-	// new block information based on the last
-	oldTS := blk.Timestamp()
-	newTS := common.UnixMicroFromTime(time.Now())
-	if newTS <= oldTS {
-		newTS = oldTS + 1
+	sts, err := service.SimulateProposal(sm, blk.Result(),
+		blk.NextValidators().Hash(), calls, n, blockInfoForEstimate(blk))
+	if err != nil {
+		if errors.UnsupportedError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, debug)
+		}
+		if scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
-	bi := common.NewBlockInfo(blk.Height()+1, newTS)
 
-	// execute transaction
-	rct, err := sm.ExecuteTransaction(
-		blk.Result(),
-		blk.NextValidators().Hash(),
-		params.RawMessage(),
-		bi,
-	)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	failures := 0
+	changes := make([]interface{}, 0)
+	for _, st := range sts {
+		before := simulatedResultToJSON(st.Base)
+		after := simulatedResultToJSON(st.Applied)
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		if st.Base.Status() == module.StatusSuccess && st.Applied.Status() != module.StatusSuccess {
+			failures += 1
+		}
+		changes = append(changes, map[string]interface{}{
+			"txHash": "0x" + hex.EncodeToString(st.Transaction.ID()),
+			"before": before,
+			"after":  after,
+		})
+	}
+	return map[string]interface{}{
+		"height":       intconv.FormatInt(blk.Height()),
+		"transactions": intconv.FormatInt(int64(len(sts))),
+		"failures":     intconv.FormatInt(int64(failures)),
+		"changes":      changes,
+	}, nil
+}
+
+// simulatedResultToJSON returns the result of the simulated transaction
+// compared for the changes by the proposal.
+func simulatedResultToJSON(rct module.Receipt) map[string]interface{} {
+	fee := new(big.Int).Mul(rct.StepUsed(), rct.StepPrice())
+	jso := map[string]interface{}{
+		"status":    "0x1",
+		"stepUsed":  intconv.FormatBigInt(rct.StepUsed()),
+		"stepPrice": intconv.FormatBigInt(rct.StepPrice()),
+		"fee":       intconv.FormatBigInt(fee),
 	}
 	if status := rct.Status(); status != module.StatusSuccess {
-		if rctex, ok := rct.(txresult.Receipt); ok {
-			if err := rctex.Reason(); err != nil {
-				return nil, jsonrpc.ErrScore(rctex.Reason(), debug)
-			}
+		msg := status.String()
+		if rctex, ok := rct.(txresult.Receipt); ok && rctex.Reason() != nil {
+			msg = rctex.Reason().Error()
+		}
+		jso["status"] = "0x0"
+		jso["failure"] = map[string]interface{}{
+			"code":    intconv.FormatInt(int64(status)),
+			"message": msg,
 		}
-		return nil, jsonrpc.ErrScoreWithStatus(status)
 	}
-	steps := new(common.HexInt)
-	steps.Set(rct.StepUsed())
-	return steps, nil
+	return jso
 }
 
 const CIDForMainNet = 0x1
@@ -1430,6 +3910,13 @@ func getTraceForRosetta(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
+	if param.FromHeight != "" || param.ToHeight != "" {
+		if len(param.Tx) > 0 || len(param.Block) > 0 || param.Height != "" {
+			return nil, jsonrpc.ErrorCodeInvalidParams.New("RangeWithTarget")
+		}
+		return getTraceRangeForRosetta(chain, &param, debug)
+	}
+
 	blk, txInfo, err := findBlockAndTxInfoByRosettaTraceParam(chain.CID(), bm, sm, param, debug)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
@@ -1501,6 +3988,134 @@ func getTraceForRosetta(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface
 	return nil, jsonrpc.ErrorCodeSystem.New("Unknown error on channel")
 }
 
+const (
+	MaxRosettaTraceBlocks    = 100
+	RosettaTraceRangeTimeout = time.Minute
+)
+
+// getTraceRangeForRosetta returns balance changes of the blocks from
+// FromHeight to ToHeight. Blocks are replayed in one chain of transitions,
+// so it doesn't need to load the state of each block again. If it runs out
+// of time, it returns balance changes of the blocks done with the next.
+func getTraceRangeForRosetta(chain module.Chain, param *RosettaTraceParam, debug bool) (interface{}, error) {
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	// transactions in the last block are not finalized yet
+	top := last.Height() - 1
+
+	if param.FromHeight == "" {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NoFromHeight")
+	}
+	from, err := param.FromHeight.Int64()
+	if err != nil || from < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidFromHeight(%s)", param.FromHeight)
+	}
+	to := from + MaxRosettaTraceBlocks - 1
+	if to > top {
+		to = top
+	}
+	if param.ToHeight != "" {
+		v, err := param.ToHeight.Int64()
+		if err != nil || v < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidToHeight(%s)", param.ToHeight)
+		}
+		if v > top {
+			return nil, jsonrpc.ErrorCodeExecuting.Errorf(
+				"Executing(last=%d,height=%d)", top, v)
+		}
+		to = v
+	}
+	if from > to || to-from >= MaxRosettaTraceBlocks {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidRange(from=%d,to=%d,max=%d)", from, to, MaxRosettaTraceBlocks)
+	}
+	if err := checkBaseHeight(chain, from); err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+
+	blk, err := bm.GetBlockByHeight(from)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	tr, err := sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	var replacer trace.TxHashReplacer
+	if chain.CID() == CIDForMainNet {
+		replacer = trace.ReplaceMissingTxHash
+	}
+
+	timer := time.After(RosettaTraceRangeTimeout)
+	blocks := make([]interface{}, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		nblk, err := bm.GetBlockByHeight(height + 1)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		csi, err := bm.NewConsensusInfo(blk)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		ntr, err := sm.CreateTransition(tr, blk.NormalTransactions(), blk, csi, true)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		ntr = sm.PatchTransition(ntr, nblk.PatchTransactions(), nblk)
+		rl, err := sm.ReceiptListFromResult(nblk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+
+		cb := &traceCallback{
+			channel: make(chan interface{}, 10),
+			bt:      trace.NewBalanceTracer(10, replacer),
+		}
+		canceller, err := ntr.ExecuteForTrace(module.TraceInfo{
+			TraceMode:  module.TraceModeBalanceChange,
+			TraceBlock: trace.NewTraceBlock(blk.ID(), rl),
+			Range:      module.TraceRangeBlock,
+			Callback:   cb,
+		})
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		select {
+		case <-timer:
+			canceller()
+			if len(blocks) == 0 {
+				return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+					"Not enough time to get result of height=%d", height)
+			}
+			return rosettaTraceRangeToJSON(from, to, blocks, height), nil
+		case e := <-cb.channel:
+			if err, ok := e.(error); ok && err != nil {
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			}
+		}
+		blocks = append(blocks, cb.balanceChangeToJSON(blk))
+		tr, blk = ntr, nblk
+	}
+	return rosettaTraceRangeToJSON(from, to, blocks, to+1), nil
+}
+
+func rosettaTraceRangeToJSON(from, to int64, blocks []interface{}, next int64) interface{} {
+	res := map[string]interface{}{
+		"fromHeight": intconv.FormatInt(from),
+		"toHeight":   intconv.FormatInt(to),
+		"blocks":     blocks,
+	}
+	if next <= to {
+		res["next"] = intconv.FormatInt(next)
+	}
+	return res
+}
+
 func findBlockAndTxInfoByRosettaTraceParam(
 	cid int,
 	bm module.BlockManager,
@@ -1612,5 +4227,6 @@ func RosettaMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepositor
 
 	mr.RegisterMethod("rosetta_getTrace", getTraceForRosetta)
 
+	setRosettaSchemas(mr)
 	return mr
 }
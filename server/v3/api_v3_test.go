@@ -0,0 +1,525 @@
+package v3
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/txresult"
+	"github.com/icon-project/goloop/test"
+)
+
+type testBlock struct {
+	module.Block
+	height    int64
+	logsBloom module.LogsBloom
+	txs       module.TransactionList
+	result    []byte
+}
+
+func (b *testBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testBlock) LogsBloom() module.LogsBloom {
+	return b.logsBloom
+}
+
+func (b *testBlock) NormalTransactions() module.TransactionList {
+	return b.txs
+}
+
+func (b *testBlock) Result() []byte {
+	return b.result
+}
+
+type testBlockManager struct {
+	module.BlockManager
+	blocks []module.Block
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height >= int64(len(bm.blocks)) {
+		return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+	}
+	return bm.blocks[height], nil
+}
+
+func TestLogsBloomOf(t *testing.T) {
+	lb := txresult.NewLogsBloom(nil)
+	lb.AddIndexedOfLog(0, []byte("Transfer(Address,Address,int)"))
+	bm := &testBlockManager{blocks: []module.Block{
+		&testBlock{height: 0},
+		&testBlock{height: 1, logsBloom: lb},
+		&testBlock{height: 2, logsBloom: txresult.NewLogsBloom(nil)},
+	}}
+
+	// the bloom of the block is in the header of the next block
+	r, err := logsBloomOf(bm, bm.blocks[0])
+	assert.NoError(t, err)
+	assert.True(t, lb.Equal(r))
+
+	// no bloom in the header of the next block
+	bm.blocks[1].(*testBlock).logsBloom = nil
+	r, err = logsBloomOf(bm, bm.blocks[0])
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	// the next block is not finalized yet
+	r, err = logsBloomOf(bm, bm.blocks[2])
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	jso := map[string]interface{}{}
+	assert.NoError(t, fillLogsBloom(jso, bm, bm.blocks[1]))
+	assert.Equal(t, bm.blocks[2].LogsBloom(), jso["logs_bloom"])
+}
+
+type testTransaction struct {
+	module.Transaction
+	id []byte
+}
+
+func (tx *testTransaction) ID() []byte {
+	return tx.id
+}
+
+func (tx *testTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{"txHash": tx.id}, nil
+}
+
+type testTransactionIterator struct {
+	txs []module.Transaction
+	idx int
+}
+
+func (it *testTransactionIterator) Has() bool {
+	return it.idx < len(it.txs)
+}
+
+func (it *testTransactionIterator) Next() error {
+	it.idx++
+	return nil
+}
+
+func (it *testTransactionIterator) Get() (module.Transaction, int, error) {
+	return it.txs[it.idx], it.idx, nil
+}
+
+type testTransactionList struct {
+	module.TransactionList
+	txs []module.Transaction
+}
+
+func (l *testTransactionList) Iterator() module.TransactionIterator {
+	return &testTransactionIterator{txs: l.txs}
+}
+
+func TestTxListParam_Option(t *testing.T) {
+	var p *TxListParam
+	opt, err := p.option()
+	assert.NoError(t, err)
+	assert.Nil(t, opt)
+
+	p = &TxListParam{}
+	opt, err = p.option()
+	assert.NoError(t, err)
+	assert.Nil(t, opt)
+
+	p = &TxListParam{TxHashOnly: true}
+	opt, err = p.option()
+	assert.NoError(t, err)
+	assert.Equal(t, &txListOption{hashOnly: true}, opt)
+
+	p = &TxListParam{TxOffset: "0x2", TxLimit: "0x10"}
+	opt, err = p.option()
+	assert.NoError(t, err)
+	assert.Equal(t, &txListOption{offset: 2, limit: 16}, opt)
+
+	p = &TxListParam{TxOffset: "-0x1"}
+	_, err = p.option()
+	assert.Error(t, err)
+
+	p = &TxListParam{TxLimit: "-0x1"}
+	_, err = p.option()
+	assert.Error(t, err)
+}
+
+func TestSelectTransactions(t *testing.T) {
+	l := &testTransactionList{}
+	for i := 0; i < 5; i++ {
+		l.txs = append(l.txs, &testTransaction{id: []byte{byte(i)}})
+	}
+
+	txs, count, err := selectTransactions(l, module.JSONVersion3, &txListOption{offset: 1, limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, []byte{0x01}, txs[0].(map[string]interface{})["txHash"])
+	assert.Equal(t, []byte{0x02}, txs[1].(map[string]interface{})["txHash"])
+
+	txs, count, err = selectTransactions(l, module.JSONVersion3, &txListOption{offset: 3, hashOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, []interface{}{"0x03", "0x04"}, txs)
+
+	txs, count, err = selectTransactions(l, module.JSONVersion3, &txListOption{offset: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+	assert.Empty(t, txs)
+
+	jso := map[string]interface{}{}
+	block := &testBlock{txs: l}
+	assert.NoError(t, fillTransactions(jso, block, module.JSONVersion3, &txListOption{limit: 1, hashOnly: true}))
+	assert.Equal(t, []interface{}{"0x00"}, jso["confirmed_transaction_list"])
+	assert.Equal(t, "0x5", jso["confirmed_transaction_count"])
+
+	jso = map[string]interface{}{}
+	assert.NoError(t, fillTransactions(jso, block, module.JSONVersion3, nil))
+	assert.Len(t, jso["confirmed_transaction_list"], 5)
+	assert.NotContains(t, jso, "confirmed_transaction_count")
+}
+
+func newTestCallContext(limit int64) *jsonrpc.Context {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	if limit > 0 {
+		c.Set("callStepLimit", limit)
+	}
+	return jsonrpc.NewContext(c)
+}
+
+func TestApplyCallStepLimit(t *testing.T) {
+	stepLimitOf := func(js []byte) interface{} {
+		var jso map[string]interface{}
+		assert.NoError(t, json.Unmarshal(js, &jso))
+		return jso["stepLimit"]
+	}
+	tests := []struct {
+		name      string
+		limit     int64
+		stepLimit jsonrpc.HexInt
+		expected  interface{}
+	}{
+		{"NoServerLimit", 0, "0x10000", "0x10000"},
+		{"NoStepLimit", 0x1000, "", "0x1000"},
+		{"UnderLimit", 0x1000, "0x800", "0x800"},
+		{"SameAsLimit", 0x1000, "0x1000", "0x1000"},
+		{"OverLimit", 0x1000, "0x10000", "0x1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := &CallParam{
+				ToAddress: "cx0000000000000000000000000000000000000001",
+				DataType:  "call",
+				StepLimit: tt.stepLimit,
+			}
+			js, err := json.Marshal(param)
+			assert.NoError(t, err)
+
+			js, err = applyCallStepLimit(newTestCallContext(tt.limit), param, js)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, stepLimitOf(js))
+		})
+	}
+
+	_, err := applyCallStepLimit(newTestCallContext(0x1000), &CallParam{}, []byte("invalid"))
+	assert.Error(t, err)
+}
+
+type testTransitionConsensus struct {
+	module.Consensus
+}
+
+func (cs *testTransitionConsensus) GetVotesByHeight(height int64) (module.CommitVoteSet, error) {
+	return consensus.NewEmptyCommitVoteList(), nil
+}
+
+type testTransitionChain struct {
+	module.Chain
+}
+
+func (c *testTransitionChain) Consensus() module.Consensus {
+	return &testTransitionConsensus{}
+}
+
+func (c *testTransitionChain) MetricContext() context.Context {
+	return metric.DefaultMetricContext()
+}
+
+func invokeWithChain(t *testing.T, c module.Chain, req string) map[string]interface{} {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	return invokeWithRepository(t, MethodRepository(mtr), c, req)
+}
+
+func invokeWithRepository(t *testing.T, mr *jsonrpc.MethodRepository, c module.Chain, req string) map[string]interface{} {
+	e := echo.New()
+	e.Validator = jsonrpc.NewValidator()
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(req)), rec)
+	ctx.Set("includeDebug", false)
+	ctx.Set("raw", json.RawMessage(req))
+	ctx.Set("chain", c)
+	assert.NoError(t, mr.Handle(ctx))
+
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func errorCodeOf(resp map[string]interface{}) jsonrpc.ErrorCode {
+	return jsonrpc.ErrorCode(resp["error"].(map[string]interface{})["code"].(float64))
+}
+
+func TestGetValidatorTransitions(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsNode(nd).String(),
+	)
+	for i := 0; i < 2; i++ {
+		nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	}
+	nd.ProposeFinalizeBlock(nd.NewVoteListForLastBlock())
+
+	c := &testTransitionChain{Chain: nd.Chain}
+	invoke := func(params string) map[string]interface{} {
+		return invokeWithChain(t, c, `{"jsonrpc":"2.0","id":1,"method":"icx_getValidatorTransitions","params":`+params+`}`)
+	}
+
+	// validators are changed by the result of the block at 2
+	resp := invoke(`{"from":"0x0"}`)
+	res := resp["result"].(map[string]interface{})
+	assert.Equal(t, "0x0", res["from"])
+	assert.Equal(t, "0x4", res["to"])
+	assert.NotEmpty(t, res["header"])
+	assert.NotEmpty(t, res["votes"])
+	assert.NotContains(t, res, "next")
+	transitions := res["transitions"].([]interface{})
+	assert.Len(t, transitions, 1)
+	transition := transitions[0].(map[string]interface{})
+	assert.Equal(t, "0x2", transition["height"])
+	blk, err := nd.BM.GetBlockByHeight(2)
+	assert.NoError(t, err)
+	header, err := marshalBlockHeader(blk)
+	assert.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(header), transition["header"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString(blk.NextValidators().Bytes()), transition["validators"])
+
+	resp = invoke(`{"from":"0x2","to":"0x3"}`)
+	res = resp["result"].(map[string]interface{})
+	assert.Equal(t, "0x3", res["to"])
+	assert.Empty(t, res["transitions"])
+	assert.NotEmpty(t, res["header"])
+
+	// the block at from is trusted already
+	resp = invoke(`{"from":"0x4"}`)
+	res = resp["result"].(map[string]interface{})
+	assert.Empty(t, res["transitions"])
+	assert.NotContains(t, res, "header")
+
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"from":"0x3","to":"0x2"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, errorCodeOf(invoke(`{"from":"0x0","to":"0x5"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, errorCodeOf(invoke(`{"from":"0x5"}`)))
+}
+
+type testBalanceServiceManager struct {
+	module.ServiceManager
+	result   []byte
+	balances map[string]*big.Int
+}
+
+func (sm *testBalanceServiceManager) GetBalances(result []byte, addrs []module.Address) ([]*big.Int, error) {
+	if !bytes.Equal(sm.result, result) {
+		return nil, errors.NotFoundError.New("NoResult")
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		if balances[i] = sm.balances[addr.String()]; balances[i] == nil {
+			balances[i] = new(big.Int)
+		}
+	}
+	return balances, nil
+}
+
+type testBalanceChain struct {
+	testTransitionChain
+	sm module.ServiceManager
+}
+
+func (c *testBalanceChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func TestGetBalances(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	blk, err := nd.BM.GetBlockByHeight(0)
+	assert.NoError(t, err)
+
+	c := &testBalanceChain{
+		testTransitionChain: testTransitionChain{Chain: nd.Chain},
+		sm: &testBalanceServiceManager{
+			result: blk.Result(),
+			balances: map[string]*big.Int{
+				"hx0000000000000000000000000000000000000001": big.NewInt(100),
+			},
+		},
+	}
+	invoke := func(params string) map[string]interface{} {
+		return invokeWithChain(t, c, `{"jsonrpc":"2.0","id":1,"method":"icx_getBalances","params":`+params+`}`)
+	}
+
+	resp := invoke(`{"addresses":["hx0000000000000000000000000000000000000001","hx0000000000000000000000000000000000000002"],"height":"0x0"}`)
+	assert.Equal(t, map[string]interface{}{
+		"hx0000000000000000000000000000000000000001": "0x64",
+		"hx0000000000000000000000000000000000000002": "0x0",
+	}, resp["result"])
+
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"addresses":[]}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"addresses":["0x01"]}`)))
+
+	addrs := make([]string, ConfigMaxBalanceAddresses+1)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf(`"hx%040x"`, i)
+	}
+	resp = invoke(`{"addresses":[` + strings.Join(addrs, ",") + `]}`)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(resp))
+}
+
+type testFeeSharingStatus map[string]interface{}
+
+func (s testFeeSharingStatus) ToJSON(height int64, version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}(s), nil
+}
+
+// testFeeSharingServiceManager returns the status or the error registered
+// for the address.
+type testFeeSharingServiceManager struct {
+	module.ServiceManager
+	result   []byte
+	statuses map[string]module.FeeSharingStatus
+	errs     map[string]error
+}
+
+func (sm *testFeeSharingServiceManager) GetFeeSharingStatus(result []byte, addr module.Address) (module.FeeSharingStatus, error) {
+	if !bytes.Equal(sm.result, result) {
+		return nil, errors.NotFoundError.New("NoResult")
+	}
+	if err := sm.errs[addr.String()]; err != nil {
+		return nil, err
+	}
+	return sm.statuses[addr.String()], nil
+}
+
+func TestGetFeeSharingStatus(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	blk, err := nd.BM.GetBlockByHeight(0)
+	assert.NoError(t, err)
+
+	status := testFeeSharingStatus{"feeSharingEnabled": "0x1"}
+	c := &testBalanceChain{
+		testTransitionChain: testTransitionChain{Chain: nd.Chain},
+		sm: &testFeeSharingServiceManager{
+			result: blk.Result(),
+			statuses: map[string]module.FeeSharingStatus{
+				"cx0000000000000000000000000000000000000001": status,
+			},
+			errs: map[string]error{
+				"cx0000000000000000000000000000000000000002": errors.NotFoundError.New("NoValidContract"),
+				"cx0000000000000000000000000000000000000003": errors.IllegalArgumentError.New("NotContract"),
+				"cx0000000000000000000000000000000000000004": errors.UnknownError.New("Unknown"),
+			},
+		},
+	}
+	invoke := func(params string) map[string]interface{} {
+		return invokeWithChain(t, c, `{"jsonrpc":"2.0","id":1,"method":"icx_getFeeSharingStatus","params":`+params+`}`)
+	}
+
+	resp := invoke(`{"address":"cx0000000000000000000000000000000000000001","height":"0x0"}`)
+	assert.Equal(t, map[string]interface{}(status), resp["result"])
+
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, errorCodeOf(invoke(`{"address":"cx0000000000000000000000000000000000000002","height":"0x0"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"address":"cx0000000000000000000000000000000000000003","height":"0x0"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeSystem, errorCodeOf(invoke(`{"address":"cx0000000000000000000000000000000000000004","height":"0x0"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"address":"hx0000000000000000000000000000000000000001"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, errorCodeOf(invoke(`{"address":"cx0000000000000000000000000000000000000001","height":"0x5"}`)))
+}
+
+// testReceiptsServiceManager keeps the result used for receipts of each
+// group.
+type testReceiptsServiceManager struct {
+	module.ServiceManager
+	results map[module.TransactionGroup][]byte
+}
+
+func (sm *testReceiptsServiceManager) ReceiptListFromResult(result []byte, g module.TransactionGroup) (module.ReceiptList, error) {
+	sm.results[g] = result
+	return sm.ServiceManager.ReceiptListFromResult(result, g)
+}
+
+func TestGetReceiptsByHeight(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(), nd.NewTx().String())
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	blk1, err := nd.BM.GetBlockByHeight(1)
+	assert.NoError(t, err)
+
+	sm := &testReceiptsServiceManager{
+		ServiceManager: nd.SM,
+		results:        make(map[module.TransactionGroup][]byte),
+	}
+	c := &testBalanceChain{
+		testTransitionChain: testTransitionChain{Chain: nd.Chain},
+		sm:                  sm,
+	}
+	invoke := func(params string) map[string]interface{} {
+		return invokeWithChain(t, c, `{"jsonrpc":"2.0","id":1,"method":"icx_getReceiptsByHeight","params":`+params+`}`)
+	}
+
+	resp := invoke(`{"height":"0x1"}`)
+	res := resp["result"].(map[string]interface{})
+	blockHash := "0x" + hex.EncodeToString(blk1.ID())
+	assert.Equal(t, blockHash, res["blockHash"])
+	assert.Equal(t, "0x1", res["blockHeight"])
+	receipts := res["receipts"].([]interface{})
+	assert.Len(t, receipts, 1)
+	tx, err := blk1.NormalTransactions().Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x"+hex.EncodeToString(tx.ID()), receipts[0].(map[string]interface{})["txHash"])
+	assert.NotContains(t, res, "patchReceipts")
+
+	// receipts of patch transactions are in the block itself
+	resp = invoke(`{"hash":"` + blockHash + `","patch":true}`)
+	res = resp["result"].(map[string]interface{})
+	assert.Equal(t, "0x1", res["blockHeight"])
+	assert.Len(t, res["receipts"], 1)
+	assert.Empty(t, res["patchReceipts"])
+	assert.Equal(t, blk1.Result(), sm.results[module.TransactionGroupPatch])
+
+	assert.Equal(t, jsonrpc.ErrorCodeExecuting, errorCodeOf(invoke(`{"height":"0x2"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, errorCodeOf(invoke(`{"height":"0x3"}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{}`)))
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(invoke(`{"height":"0x1","hash":"`+blockHash+`"}`)))
+}
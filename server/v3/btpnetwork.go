@@ -0,0 +1,81 @@
+package v3
+
+import (
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+type btpNetworkAt func(height int64, nid int64) (module.BTPNetwork, error)
+
+func btpNetworkAtOf(bm module.BlockManager, sm module.ServiceManager) btpNetworkAt {
+	return func(height int64, nid int64) (module.BTPNetwork, error) {
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		return sm.BTPNetworkFromResult(blk.Result(), nid)
+	}
+}
+
+// btpNetworkClosedHeight returns the height of the block closing the network.
+// The network is opened at start, and it's closed at end. A closed network
+// can't be opened again, so it finds the first state of closed network.
+func btpNetworkClosedHeight(nwAt btpNetworkAt, nid int64, start, end int64) (int64, error) {
+	var err error
+	h := sort.Search(int(end-start), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		// the state of the block at start+1 is the first state
+		// including the network.
+		nw, e := nwAt(start+1+int64(i), nid)
+		if e != nil {
+			err = e
+			return true
+		}
+		return !nw.Open()
+	})
+	if err != nil {
+		return 0, err
+	}
+	// the state of the block includes the result of the previous block.
+	return start + int64(h), nil
+}
+
+// fillBTPNetworkLifecycle adds the last message SN and the height of
+// the block closing the network if it's closed at the height.
+func fillBTPNetworkLifecycle(res map[string]interface{}, nwAt btpNetworkAt, nid int64, nw module.BTPNetwork, height int64) error {
+	if sn := nw.NextMessageSN(); sn > 0 {
+		res["lastMessageSN"] = intconv.FormatInt(sn - 1)
+	} else {
+		res["lastMessageSN"] = nil
+	}
+	if !nw.Open() {
+		ch, err := btpNetworkClosedHeight(nwAt, nid, nw.StartHeight(), height)
+		if err != nil {
+			return err
+		}
+		res["closedHeight"] = intconv.FormatInt(ch)
+	}
+	return nil
+}
+
+// closedBTPNetworkIDs returns IDs of closed networks of the network type.
+// Networks are not removed, so it looks up networks until it's not found.
+func closedBTPNetworkIDs(nwAt btpNetworkAt, ntid int64, height int64) ([]interface{}, error) {
+	ids := make([]interface{}, 0)
+	for nid := int64(1); ; nid++ {
+		nw, err := nwAt(height, nid)
+		if errors.NotFoundError.Equals(err) {
+			return ids, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if nw.NetworkTypeID() == ntid && !nw.Open() {
+			ids = append(ids, intconv.FormatInt(nid))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type testBTPNetwork struct {
+	module.BTPNetwork
+	ntid   int64
+	open   bool
+	nextSN int64
+}
+
+func (nw *testBTPNetwork) NetworkTypeID() int64 {
+	return nw.ntid
+}
+
+func (nw *testBTPNetwork) Open() bool {
+	return nw.open
+}
+
+func (nw *testBTPNetwork) NextMessageSN() int64 {
+	return nw.nextSN
+}
+
+func (nw *testBTPNetwork) StartHeight() int64 {
+	return 10
+}
+
+func TestBTPNetworkLifecycle(t *testing.T) {
+	// network 1 (type 1) is opened at 10 and closed at 25
+	// network 2 (type 1) is opened at 10
+	// network 3 (type 2) is opened at 10 and closed at 12
+	nwAt := func(h int64, nid int64) (module.BTPNetwork, error) {
+		switch nid {
+		case 1:
+			return &testBTPNetwork{ntid: 1, open: h <= 25, nextSN: 3}, nil
+		case 2:
+			return &testBTPNetwork{ntid: 1, open: true}, nil
+		case 3:
+			return &testBTPNetwork{ntid: 2, open: h <= 12}, nil
+		default:
+			return nil, errors.NotFoundError.New("NotFound")
+		}
+	}
+
+	h, err := btpNetworkClosedHeight(nwAt, 1, 10, 40)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 25, h)
+
+	h, err = btpNetworkClosedHeight(nwAt, 1, 10, 26)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 25, h)
+
+	h, err = btpNetworkClosedHeight(nwAt, 3, 10, 40)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 12, h)
+
+	nw, _ := nwAt(40, 1)
+	res := map[string]interface{}{}
+	assert.NoError(t, fillBTPNetworkLifecycle(res, nwAt, 1, nw, 40))
+	assert.Equal(t, "0x2", res["lastMessageSN"])
+	assert.Equal(t, "0x19", res["closedHeight"])
+
+	nw, _ = nwAt(40, 2)
+	res = map[string]interface{}{}
+	assert.NoError(t, fillBTPNetworkLifecycle(res, nwAt, 2, nw, 40))
+	assert.Nil(t, res["lastMessageSN"])
+	assert.NotContains(t, res, "closedHeight")
+
+	ids, err := closedBTPNetworkIDs(nwAt, 1, 40)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"0x1"}, ids)
+}
@@ -0,0 +1,120 @@
+package v3
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+// btpPublicKeys is the registered BTP public keys of the addresses for
+// each DSA at a height.
+type btpPublicKeys map[string][][]byte
+
+func (k btpPublicKeys) equal(o btpPublicKeys) bool {
+	for name, keys := range k {
+		for i, key := range keys {
+			if !bytes.Equal(key, o.get(name, i)) {
+				return false
+			}
+		}
+	}
+	for name, keys := range o {
+		for i, key := range keys {
+			if !bytes.Equal(key, k.get(name, i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (k btpPublicKeys) get(name string, idx int) []byte {
+	if keys, ok := k[name]; ok && idx < len(keys) {
+		return keys[idx]
+	}
+	return nil
+}
+
+func (k btpPublicKeys) names() []string {
+	names := make([]string, 0, len(k))
+	for name := range k {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type btpPublicKeyChange struct {
+	Height int64
+	Index  int
+	DSA    string
+	Key    []byte
+}
+
+func (c *btpPublicKeyChange) ToJSON(addrs []module.Address) interface{} {
+	jso := map[string]interface{}{
+		"height":  intconv.FormatInt(c.Height),
+		"address": addrs[c.Index],
+		"dsa":     c.DSA,
+	}
+	if len(c.Key) > 0 {
+		jso["publicKey"] = common.HexBytes(c.Key)
+	} else {
+		jso["publicKey"] = nil
+	}
+	return jso
+}
+
+// btpPublicKeyChanges returns changes of the keys in (from, to] in order
+// of the height. It bisects the range, so it reads the keys O(log(to-from))
+// times for each change.
+func btpPublicKeyChanges(
+	keysAt func(height int64) (btpPublicKeys, error), from, to int64,
+) ([]*btpPublicKeyChange, error) {
+	lo, err := keysAt(from)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := keysAt(to)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*btpPublicKeyChange
+	var bisect func(from int64, lo btpPublicKeys, to int64, hi btpPublicKeys) error
+	bisect = func(from int64, lo btpPublicKeys, to int64, hi btpPublicKeys) error {
+		if lo.equal(hi) {
+			return nil
+		}
+		if to-from == 1 {
+			for _, name := range hi.names() {
+				for i, key := range hi[name] {
+					if !bytes.Equal(key, lo.get(name, i)) {
+						changes = append(changes, &btpPublicKeyChange{
+							Height: to,
+							Index:  i,
+							DSA:    name,
+							Key:    key,
+						})
+					}
+				}
+			}
+			return nil
+		}
+		mid := from + (to-from)/2
+		keys, err := keysAt(mid)
+		if err != nil {
+			return err
+		}
+		if err := bisect(from, lo, mid, keys); err != nil {
+			return err
+		}
+		return bisect(mid, keys, to, hi)
+	}
+	if err := bisect(from, lo, to, hi); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,43 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBTPPublicKeyChanges(t *testing.T) {
+	k1 := []byte{0x01}
+	k2 := []byte{0x02}
+	k3 := []byte{0x03}
+	const dsa = "ecdsa/secp256k1"
+	keysAt := func(h int64) (btpPublicKeys, error) {
+		keys := btpPublicKeys{dsa: [][]byte{nil, k3}}
+		switch {
+		case h >= 20:
+			keys[dsa][0] = k2
+		case h >= 7:
+			keys[dsa][0] = k1
+		}
+		if h >= 15 {
+			keys[dsa][1] = nil
+		}
+		return keys, nil
+	}
+
+	changes, err := btpPublicKeyChanges(keysAt, 0, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []*btpPublicKeyChange{
+		{Height: 7, Index: 0, DSA: dsa, Key: k1},
+		{Height: 15, Index: 1, DSA: dsa, Key: nil},
+		{Height: 20, Index: 0, DSA: dsa, Key: k2},
+	}, changes)
+
+	changes, err = btpPublicKeyChanges(keysAt, 20, 30)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = btpPublicKeyChanges(keysAt, 10, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,128 @@
+package v3
+
+import (
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+const (
+	eventRegistrySize = 256
+)
+
+// eventRegistry keeps API information of the contracts for decoding
+// event logs. Entries are keyed by the hash of the code of the contract,
+// so they're shared by blocks until the contract is updated, and updated
+// contracts are decoded with the API at the time.
+type eventRegistry struct {
+	cache *cache.LRUCache
+}
+
+func (r *eventRegistry) apiInfoOf(sm module.ServiceManager, result []byte, addr module.Address) (*scoreapi.Info, error) {
+	// system contracts don't have the status.
+	var codeHash []byte
+	if ss, err := sm.GetSCOREStatus(result, addr); err == nil {
+		codeHash = ss.CodeHash()
+	}
+	if codeHash != nil {
+		if v, err := r.cache.Get(string(codeHash)); err == nil {
+			return v.(*scoreapi.Info), nil
+		}
+	}
+	api, err := sm.GetAPIInfo(result, addr)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := api.(*scoreapi.Info)
+	if !ok || info == nil {
+		return nil, errors.NotFoundError.Errorf("NoAPIInfo(addr=%s)", addr)
+	}
+	if codeHash != nil {
+		r.cache.Put(string(codeHash), info)
+	}
+	return info, nil
+}
+
+var eventAPIs = &eventRegistry{
+	cache: cache.NewLRUCache(eventRegistrySize, nil),
+}
+
+// EventDecoder decodes event logs with the APIs of the contracts in the
+// result of a block.
+type EventDecoder struct {
+	sm     module.ServiceManager
+	result []byte
+	apis   map[string]*scoreapi.Info
+}
+
+func (d *EventDecoder) apiInfoOf(addr module.Address) *scoreapi.Info {
+	key := string(addr.Bytes())
+	if info, ok := d.apis[key]; ok {
+		return info
+	}
+	info, _ := eventAPIs.apiInfoOf(d.sm, d.result, addr)
+	d.apis[key] = info
+	return info
+}
+
+// DecodeEventLogs returns decoded event logs of the receipt. Element of
+// the returned list is nil if the event can't be decoded with the API.
+func (d *EventDecoder) DecodeEventLogs(rct module.Receipt) ([]interface{}, error) {
+	var logs []interface{}
+	for itr := rct.EventLogIterator(); itr.Has(); itr.Next() {
+		ev, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, d.DecodeEventLog(ev))
+	}
+	if logs == nil {
+		logs = []interface{}{}
+	}
+	return logs, nil
+}
+
+// DecodeEventLog returns the decoded event log. It returns nil if the
+// event can't be decoded with the API.
+func (d *EventDecoder) DecodeEventLog(ev module.EventLog) interface{} {
+	info := d.apiInfoOf(ev.Address())
+	if info == nil {
+		return nil
+	}
+	m, params, err := info.DecodeEvent(ev.Indexed(), ev.Data())
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"scoreAddress": ev.Address(),
+		"name":         m.Name,
+		"signature":    m.Signature(),
+		"params":       params,
+	}
+}
+
+// NewEventDecoder returns the decoder with the APIs in the result.
+func NewEventDecoder(sm module.ServiceManager, result []byte) *EventDecoder {
+	return &EventDecoder{
+		sm:     sm,
+		result: result,
+		apis:   make(map[string]*scoreapi.Info),
+	}
+}
+
+// eventDecoderAt returns the decoder for event logs of the transactions
+// in the block at the height. API of the contract after execution of the
+// transaction is stored in the result of the next block, so it returns
+// block.ResultNotFinalizedError until the next block is finalized.
+func eventDecoderAt(bm module.BlockManager, sm module.ServiceManager, height int64) (*EventDecoder, error) {
+	blk, err := bm.GetBlockByHeight(height + 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, block.ErrResultNotFinalized
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewEventDecoder(sm, blk.Result()), nil
+}
@@ -0,0 +1,138 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+type testSCOREStatus struct {
+	module.SCOREStatus
+	codeHash []byte
+}
+
+func (s *testSCOREStatus) CodeHash() []byte {
+	return s.codeHash
+}
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+func (l *testEventLog) Address() module.Address { return l.addr }
+func (l *testEventLog) Indexed() [][]byte       { return l.indexed }
+func (l *testEventLog) Data() [][]byte          { return l.data }
+
+type testServiceManager struct {
+	module.ServiceManager
+	codeHash map[string][]byte
+	apis     map[string]*scoreapi.Info
+	calls    int
+}
+
+func (sm *testServiceManager) GetSCOREStatus(result []byte, addr module.Address) (module.SCOREStatus, error) {
+	if ch, ok := sm.codeHash[string(addr.Bytes())]; ok {
+		return &testSCOREStatus{codeHash: ch}, nil
+	}
+	return nil, errors.NotFoundError.New("NoSCOREStatus")
+}
+
+func (sm *testServiceManager) GetAPIInfo(result []byte, addr module.Address) (module.APIInfo, error) {
+	sm.calls += 1
+	if info, ok := sm.apis[string(addr.Bytes())]; ok {
+		return info, nil
+	}
+	return nil, errors.NotFoundError.New("NoAPIInfo")
+}
+
+func newTestEventAPI(name string) *scoreapi.Info {
+	return scoreapi.NewInfo([]*scoreapi.Method{{
+		Type:    scoreapi.Event,
+		Name:    name,
+		Indexed: 1,
+		Inputs: []scoreapi.Parameter{
+			{Name: "_value", Type: scoreapi.Integer},
+		},
+	}})
+}
+
+func TestEventDecoder_CacheByCodeHash(t *testing.T) {
+	defer func(r *eventRegistry) { eventAPIs = r }(eventAPIs)
+	eventAPIs = &eventRegistry{cache: cache.NewLRUCache(eventRegistrySize, nil)}
+
+	score1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000011")
+	score2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000012")
+	sm := &testServiceManager{
+		codeHash: map[string][]byte{
+			string(score1.Bytes()): []byte("code1"),
+			string(score2.Bytes()): []byte("code1"),
+		},
+		apis: map[string]*scoreapi.Info{
+			string(score1.Bytes()): newTestEventAPI("Deposit"),
+			string(score2.Bytes()): newTestEventAPI("Deposit"),
+		},
+	}
+	value := common.NewHexInt(7)
+	ev := &testEventLog{
+		addr:    score1,
+		indexed: [][]byte{[]byte("Deposit(int)"), value.Bytes()},
+	}
+
+	d := NewEventDecoder(sm, nil)
+	dl, ok := d.DecodeEventLog(ev).(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "Deposit", dl["name"])
+	assert.Equal(t, "Deposit(int)", dl["signature"])
+	assert.Equal(t, 0, value.Cmp(dl["params"].(map[string]interface{})["_value"].(*common.HexInt).Value()))
+	assert.Equal(t, 1, sm.calls)
+
+	// contracts of the same code share the API across decoders
+	ev.addr = score2
+	d = NewEventDecoder(sm, nil)
+	assert.NotNil(t, d.DecodeEventLog(ev))
+	assert.Equal(t, 1, sm.calls)
+
+	// updated contract is decoded with the API of the new code
+	sm.codeHash[string(score2.Bytes())] = []byte("code2")
+	sm.apis[string(score2.Bytes())] = newTestEventAPI("Withdraw")
+	d = NewEventDecoder(sm, nil)
+	assert.Nil(t, d.DecodeEventLog(ev))
+	assert.Equal(t, 2, sm.calls)
+	ev.indexed[0] = []byte("Withdraw(int)")
+	dl, ok = d.DecodeEventLog(ev).(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "Withdraw", dl["name"])
+	assert.Equal(t, 2, sm.calls)
+
+	// contracts without the status aren't cached
+	score3 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000013")
+	ev.addr = score3
+	NewEventDecoder(sm, nil).DecodeEventLog(ev)
+	NewEventDecoder(sm, nil).DecodeEventLog(ev)
+	assert.Equal(t, 4, sm.calls)
+}
+
+func TestEventDecoderAt(t *testing.T) {
+	bm := &testBlockManager{blocks: []module.Block{
+		&testBlock{height: 0, result: []byte("result0")},
+		&testBlock{height: 1, result: []byte("result1")},
+	}}
+	sm := &testServiceManager{}
+
+	// APIs after execution of the transactions are in the next block
+	d, err := eventDecoderAt(bm, sm, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("result1"), d.result)
+
+	_, err = eventDecoderAt(bm, sm, 1)
+	assert.True(t, block.ResultNotFinalizedError.Equals(err))
+}
@@ -0,0 +1,93 @@
+package v3
+
+import (
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	openRPCTitle        = "Goloop JSON-RPC API v3"
+	openRPCDebugTitle   = "Goloop JSON-RPC Debug API v3"
+	openRPCRosettaTitle = "Goloop JSON-RPC Rosetta API"
+	openRPCVersion      = "3"
+)
+
+var (
+	resultObject = jsonrpc.Schema{"type": "object"}
+	resultArray  = jsonrpc.Schema{"type": "array"}
+	resultBase64 = jsonrpc.Schema{"type": "string", "contentEncoding": "base64"}
+	resultHexInt = jsonrpc.HexInt("")
+)
+
+func setSchemas(mr *jsonrpc.MethodRepository) {
+	mr.SetSchema("icx_getLastBlock", TxListParam{}, resultObject)
+	mr.SetSchema("icx_getBlockByHeight", BlockHeightParam{}, resultObject)
+	mr.SetSchema("icx_getBlockByHash", BlockHashParam{}, resultObject)
+	mr.SetSchema("icx_call", CallParam{}, nil)
+	mr.SetSchema("icx_getBalance", AddressParam{}, resultHexInt)
+	mr.SetSchema("icx_getBalances", AddressesParam{}, map[string]jsonrpc.HexInt{})
+	mr.SetSchema("icx_getScoreApi", ScoreAddressParam{}, resultArray)
+	mr.SetSchema("icx_getTotalSupply", HeightParam{}, resultHexInt)
+	mr.SetSchema("icx_getTransactionResult", TransactionResultParam{}, resultObject)
+	mr.SetSchema("icx_getTransactionByHash", TransactionHashParam{}, resultObject)
+	mr.SetSchema("icx_sendTransaction", TransactionParam{}, jsonrpc.HexBytes(""))
+	mr.SetSchema("icx_validateTransaction", ValidateTransactionParam{}, resultObject)
+	mr.SetSchema("icx_sendTransactionAndWait", TransactionParam{}, resultObject)
+	mr.SetSchema("icx_waitTransactionResult", TransactionHashParam{}, resultObject)
+
+	mr.SetSchema("icx_getDataByHash", DataHashParam{}, resultBase64)
+	mr.SetSchema("icx_getBlockHeaderByHeight", BlockHeightParam{}, resultBase64)
+	mr.SetSchema("icx_getVotesByHeight", BlockHeightParam{}, resultBase64)
+	mr.SetSchema("icx_getRandomnessByHeight", BlockHeightParam{}, resultObject)
+	mr.SetSchema("icx_getReceiptsByHeight", BlockReceiptsParam{}, resultObject)
+	mr.SetSchema("icx_getLogs", LogsParam{}, resultObject)
+	mr.SetSchema("icx_getStatement", StatementParam{}, resultObject)
+	mr.SetSchema("icx_getTransactionsByAddress", TransactionsByAddressParam{}, resultObject)
+	mr.SetSchema("icx_getVoteParticipation", HeightRangeParam{}, resultObject)
+	mr.SetSchema("icx_getValidatorTransitions", HeightRangeParam{}, resultObject)
+	mr.SetSchema("icx_getRoundHistory", HeightParam{}, resultArray)
+	mr.SetSchema("icx_getProofForResult", ProofResultParam{}, resultArray)
+	mr.SetSchema("icx_getProofForEvents", ProofEventsParam{}, resultArray)
+	mr.SetSchema("icx_getProofForTransaction", ProofTransactionParam{}, resultObject)
+	mr.SetSchema("icx_getProofForExtension", ProofExtensionParam{}, resultObject)
+	mr.SetSchema("icx_getScoreStatus", ScoreAddressParam{}, resultObject)
+	mr.SetSchema("icx_getScoreStatusList", ScoreStatusListParam{}, resultObject)
+	mr.SetSchema("icx_getPendingDeployments", nil, resultObject)
+	mr.SetSchema("icx_getFeeSharingStatus", ScoreAddressParam{}, resultObject)
+	mr.SetSchema("icx_getScoreVerification", ScoreAddressParam{}, resultObject)
+	mr.SetSchema("icx_getChainConfig", HeightParam{}, resultObject)
+	mr.SetSchema("icx_getStepCostHistory", StepCostHistoryParam{}, resultArray)
+	mr.SetSchema("icx_getScheduledHalt", nil, resultObject)
+	mr.SetSchema("icx_getTransactionSuggestion", TxSuggestionParam{}, resultObject)
+	mr.SetSchema("icx_getStateRange", nil, resultObject)
+
+	mr.SetSchema("btp_getNetworkInfo", BTPQueryParam{}, resultObject)
+	mr.SetSchema("btp_getNetworkTypeInfo", BTPQueryParam{}, resultObject)
+	mr.SetSchema("btp_getMessages", BTPMessagesParam{}, []string{})
+	mr.SetSchema("btp_getHeader", BTPMessagesParam{}, resultBase64)
+	mr.SetSchema("btp_getProof", BTPMessagesParam{}, resultBase64)
+	mr.SetSchema("btp_getSourceInformation", nil, resultObject)
+	mr.SetSchema("btp_getPublicKeys", BTPPublicKeysParam{}, resultObject)
+
+	mr.RegisterDiscovery(openRPCTitle, openRPCVersion)
+}
+
+func setDebugSchemas(mr *jsonrpc.MethodRepository) {
+	mr.SetSchema("debug_getTrace", TransactionHashParam{}, resultObject)
+	mr.SetSchema("debug_getStateDiff", TransactionHashParam{}, resultObject)
+	mr.SetSchema("debug_estimateStep", TransactionParamForEstimate{}, resultHexInt)
+	mr.SetSchema("debug_estimateStepBatch", TransactionBatchParamForEstimate{}, resultObject)
+	mr.SetSchema("debug_estimateFee", TransactionParamForEstimate{}, resultObject)
+	mr.SetSchema("debug_getStaleState", StaleStateParam{}, resultObject)
+	mr.SetSchema("debug_verifyScore", VerifyScoreParam{}, resultObject)
+	mr.SetSchema("debug_getHotspots", HotspotsParam{}, resultObject)
+	mr.SetSchema("debug_getStepLimitSuggestion", nil, resultObject)
+	mr.SetSchema("debug_simulateProposal", ProposalSimulationParam{}, resultObject)
+
+	mr.RegisterDiscovery(openRPCDebugTitle, openRPCVersion)
+}
+
+func setRosettaSchemas(mr *jsonrpc.MethodRepository) {
+	mr.SetSchema("rosetta_getTrace", RosettaTraceParam{}, resultObject)
+
+	mr.RegisterDiscovery(openRPCRosettaTitle, openRPCVersion)
+}
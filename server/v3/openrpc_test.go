@@ -0,0 +1,42 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func TestMethodRepository_Schemas(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, false)
+	for _, mr := range []*jsonrpc.MethodRepository{
+		MethodRepository(mtr),
+		DebugMethodRepository(mtr),
+		RosettaMethodRepository(mtr),
+	} {
+		for _, name := range mr.Methods() {
+			assert.True(t, mr.HasSchema(name), "no schema for %s", name)
+		}
+	}
+
+	doc := MethodRepository(mtr).OpenRPC(openRPCTitle, openRPCVersion)
+	for _, m := range doc.Methods {
+		if m.Name != "icx_sendTransaction" {
+			continue
+		}
+		for _, p := range m.Params {
+			if p.Name == "dataType" {
+				assert.Equal(t, jsonrpc.Schema{"anyOf": []interface{}{
+					jsonrpc.Schema{"const": "call"},
+					jsonrpc.Schema{"const": "deploy"},
+					jsonrpc.Schema{"const": "message"},
+					jsonrpc.Schema{"const": "deposit"},
+				}}, p.Schema)
+				return
+			}
+		}
+	}
+	assert.Fail(t, "no dataType of icx_sendTransaction")
+}
@@ -1,6 +1,8 @@
 package v3
 
 import (
+	"encoding/json"
+
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -13,16 +15,46 @@ var (
 	VersionValue = jsonrpc.HexInt(intconv.FormatInt(Version))
 )
 
+// TxListParam selects transactions in confirmed_transaction_list of
+// the block. TxOffset and TxLimit page through them, and TxHashOnly returns
+// hashes of them instead of the transactions.
+type TxListParam struct {
+	TxOffset   jsonrpc.HexInt `json:"txOffset,omitempty" validate:"optional,t_int"`
+	TxLimit    jsonrpc.HexInt `json:"txLimit,omitempty" validate:"optional,t_int"`
+	TxHashOnly bool           `json:"txHashOnly,omitempty"`
+}
+
 type BlockHeightParam struct {
 	Height jsonrpc.HexInt `json:"height" validate:"required,t_int"`
+	TxListParam
+}
+
+// BlockReceiptsParam selects the block with either Height or Hash.
+type BlockReceiptsParam struct {
+	Height  jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	Hash    jsonrpc.HexBytes `json:"hash,omitempty" validate:"optional,t_hash"`
+	Patch   bool             `json:"patch,omitempty"`
+	Decoded bool             `json:"decoded,omitempty"`
+}
+
+type HeightRangeParam struct {
+	From jsonrpc.HexInt `json:"from" validate:"required,t_int"`
+	To   jsonrpc.HexInt `json:"to,omitempty" validate:"optional,t_int"`
 }
 
 type HeightParam struct {
 	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type StepCostHistoryParam struct {
+	Type string         `json:"type,omitempty"`
+	From jsonrpc.HexInt `json:"from,omitempty" validate:"optional,t_int"`
+	To   jsonrpc.HexInt `json:"to,omitempty" validate:"optional,t_int"`
+}
+
 type BlockHashParam struct {
 	Hash jsonrpc.HexBytes `json:"hash" validate:"required,t_hash"`
+	TxListParam
 }
 
 type CallParam struct {
@@ -31,6 +63,7 @@ type CallParam struct {
 	DataType    string          `json:"dataType" validate:"required,call"`
 	Data        interface{}     `json:"data"`
 	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	StepLimit   jsonrpc.HexInt  `json:"stepLimit,omitempty" validate:"optional,t_int"`
 }
 
 type AddressParam struct {
@@ -38,15 +71,37 @@ type AddressParam struct {
 	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type TxSuggestionParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr_eoa"`
+}
+
+type AddressesParam struct {
+	Addresses []jsonrpc.Address `json:"addresses" validate:"gt=0,dive,t_addr"`
+	Height    jsonrpc.HexInt    `json:"height,omitempty" validate:"optional,t_int"`
+}
+
 type ScoreAddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr_score"`
 	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type VerifyScoreParam struct {
+	Address  jsonrpc.Address   `json:"address" validate:"required,t_addr_score"`
+	Code     jsonrpc.HexBytes  `json:"code" validate:"required,t_bin_data"`
+	Source   jsonrpc.HexBytes  `json:"source,omitempty" validate:"optional,t_bin_data"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TransactionResultParam struct {
+	Hash        jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Decoded     bool             `json:"decoded,omitempty"`
+	FailureData bool             `json:"failureData,omitempty"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -59,6 +114,10 @@ type TransactionParamForEstimate struct {
 	Data        interface{}     `json:"data,omitempty"`
 }
 
+type TransactionBatchParamForEstimate struct {
+	Transactions []TransactionParamForEstimate `json:"transactions" validate:"gt=0,dive"`
+}
+
 type TransactionParam struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -73,6 +132,11 @@ type TransactionParam struct {
 	Data        interface{}     `json:"data,omitempty"`
 }
 
+type ValidateTransactionParam struct {
+	Transaction json.RawMessage `json:"transaction" validate:"required"`
+	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+}
+
 type DataHashParam struct {
 	Hash jsonrpc.HexBytes `json:"hash" validate:"required,t_hash"`
 }
@@ -88,10 +152,85 @@ type ProofEventsParam struct {
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
 
+type ProofTransactionParam struct {
+	Hash   jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Events []jsonrpc.HexInt `json:"events,omitempty" validate:"optional,dive,t_int"`
+}
+
+type ProofExtensionParam struct {
+	Store  string           `json:"store" validate:"required"`
+	Key    jsonrpc.HexBytes `json:"key" validate:"required,t_bin_data"`
+	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+}
+
+// RosettaTraceParam selects a transaction or a block to trace. FromHeight
+// and ToHeight select consecutive blocks instead.
 type RosettaTraceParam struct {
-	Tx     jsonrpc.HexBytes `json:"tx,omitempty" validate:"optional,t_rhash"`
-	Block  jsonrpc.HexBytes `json:"block,omitempty" validate:"optional,t_hash"`
-	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,gte=0,t_int"`
+	Tx         jsonrpc.HexBytes `json:"tx,omitempty" validate:"optional,t_rhash"`
+	Block      jsonrpc.HexBytes `json:"block,omitempty" validate:"optional,t_hash"`
+	Height     jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,gte=0,t_int"`
+	FromHeight jsonrpc.HexInt   `json:"fromHeight,omitempty" validate:"optional,t_int"`
+	ToHeight   jsonrpc.HexInt   `json:"toHeight,omitempty" validate:"optional,t_int"`
+}
+
+// ProposalCallParam is a call made by the governance on the approval of
+// the network proposal. To is the chain SCORE if it's omitted.
+type ProposalCallParam struct {
+	To     jsonrpc.Address `json:"to,omitempty" validate:"optional,t_addr_score"`
+	Method string          `json:"method" validate:"required"`
+	Params interface{}     `json:"params,omitempty"`
+}
+
+type ProposalSimulationParam struct {
+	Calls        []ProposalCallParam `json:"calls" validate:"gt=0,dive"`
+	Transactions jsonrpc.HexInt      `json:"transactions,omitempty" validate:"optional,t_int"`
+}
+
+type StaleStateParam struct {
+	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	Age    jsonrpc.HexInt   `json:"age" validate:"required,t_int"`
+	Start  jsonrpc.HexBytes `json:"start,omitempty" validate:"optional,t_hash"`
+	Limit  jsonrpc.HexInt   `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+type HotspotsParam struct {
+	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
+	Blocks jsonrpc.HexInt `json:"blocks,omitempty" validate:"optional,t_int"`
+	Limit  jsonrpc.HexInt `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+// LogsParam filters event logs in blocks from FromHeight to ToHeight.
+// Indexed has values of indexed parameters of Event, and null matches any.
+type LogsParam struct {
+	FromHeight jsonrpc.HexInt    `json:"fromHeight,omitempty" validate:"optional,t_int"`
+	ToHeight   jsonrpc.HexInt    `json:"toHeight,omitempty" validate:"optional,t_int"`
+	Addresses  []jsonrpc.Address `json:"addresses,omitempty" validate:"optional,dive,t_addr_score"`
+	Event      string            `json:"event,omitempty"`
+	Indexed    []*string         `json:"indexed,omitempty"`
+	Limit      jsonrpc.HexInt    `json:"limit,omitempty" validate:"optional,t_int"`
+	Decoded    bool              `json:"decoded,omitempty"`
+}
+
+type StatementParam struct {
+	Address       jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	FromTimestamp jsonrpc.HexInt  `json:"fromTimestamp,omitempty" validate:"optional,t_int"`
+	ToTimestamp   jsonrpc.HexInt  `json:"toTimestamp,omitempty" validate:"optional,t_int"`
+	Start         jsonrpc.HexInt  `json:"start,omitempty" validate:"optional,t_int"`
+	Limit         jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+type TransactionsByAddressParam struct {
+	Address   jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Direction string          `json:"direction,omitempty"`
+	Order     string          `json:"order,omitempty"`
+	Start     jsonrpc.HexInt  `json:"start,omitempty" validate:"optional,t_int"`
+	Limit     jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+type ScoreStatusListParam struct {
+	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
+	Start  jsonrpc.HexInt `json:"start,omitempty" validate:"optional,t_int"`
+	Limit  jsonrpc.HexInt `json:"limit,omitempty" validate:"optional,t_int"`
 }
 
 type BTPQueryParam struct {
@@ -99,6 +238,12 @@ type BTPQueryParam struct {
 	Id     jsonrpc.HexInt `json:"id" validate:"required,t_int"`
 }
 
+type BTPPublicKeysParam struct {
+	Height        jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
+	NetworkTypeID jsonrpc.HexInt `json:"networkTypeID,omitempty" validate:"optional,t_int"`
+	From          jsonrpc.HexInt `json:"from,omitempty" validate:"optional,t_int"`
+}
+
 type BTPMessagesParam struct {
 	Height    jsonrpc.HexInt `json:"height" validate:"required,t_int"`
 	NetworkId jsonrpc.HexInt `json:"networkID" validate:"required,t_int"`
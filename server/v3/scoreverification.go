@@ -0,0 +1,224 @@
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+// scoreVerification is the record of the code verified against
+// the deployed code of the SCORE.
+type scoreVerification struct {
+	CodeHash []byte
+	Height   int64
+}
+
+// ToJSON returns the record with the submission. The submission is
+// returned as unverified, since nothing binds it to the code.
+func (v *scoreVerification) ToJSON(addr module.Address, current []byte, s *scoreSubmission) (interface{}, error) {
+	jso := map[string]interface{}{
+		"address":        addr,
+		"codeHash":       common.HexBytes(v.CodeHash),
+		"verifiedHeight": intconv.FormatInt(v.Height),
+	}
+	if bytes.Equal(v.CodeHash, current) {
+		jso["current"] = "0x1"
+	} else {
+		jso["current"] = "0x0"
+	}
+	if s != nil {
+		jso["unverified"] = s.ToJSON()
+	}
+	return jso, nil
+}
+
+// scoreSubmission is the source and the metadata submitted with the code
+// on the verification. They are stored as they are.
+type scoreSubmission struct {
+	Source   []byte
+	Metadata []byte
+}
+
+func (s *scoreSubmission) ToJSON() interface{} {
+	jso := make(map[string]interface{})
+	if len(s.Source) > 0 {
+		jso["source"] = common.HexBytes(s.Source)
+		jso["sourceHash"] = common.HexBytes(crypto.SHA3Sum256(s.Source))
+	}
+	if len(s.Metadata) > 0 {
+		jso["metadata"] = json.RawMessage(s.Metadata)
+	}
+	return jso
+}
+
+func getSCOREVerification(dbase db.Database, addr module.Address) (*scoreVerification, error) {
+	bs, err := db.DoGetWithBucketID(dbase, db.SCOREVerification, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	v := new(scoreVerification)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func setSCOREVerification(dbase db.Database, addr module.Address, v *scoreVerification) error {
+	bk, err := dbase.GetBucket(db.SCOREVerification)
+	if err != nil {
+		return err
+	}
+	return bk.Set(addr.Bytes(), codec.BC.MustMarshalToBytes(v))
+}
+
+// getSCORESubmission returns the submission of the last verification.
+// It returns nil if nothing was submitted.
+func getSCORESubmission(dbase db.Database, addr module.Address) (*scoreSubmission, error) {
+	bs, err := db.DoGetWithBucketID(dbase, db.SCORESubmission, addr.Bytes())
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	s := new(scoreSubmission)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// setSCORESubmission replaces the submission of the SCORE. It removes the
+// old one if s is nil.
+func setSCORESubmission(dbase db.Database, addr module.Address, s *scoreSubmission) error {
+	bk, err := dbase.GetBucket(db.SCORESubmission)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return bk.Delete(addr.Bytes())
+	}
+	return bk.Set(addr.Bytes(), codec.BC.MustMarshalToBytes(s))
+}
+
+func verifyScore(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var param VerifyScoreParam
+	debug := ctx.IncludeDebug()
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	b, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	addr := param.Address.Address()
+	s, err := sm.GetSCOREStatus(b.Result(), addr)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	current := s.CodeHash()
+	if current == nil {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf("NoContract(addr=%s)", addr)
+	}
+	codeHash := crypto.SHA3Sum256(param.Code.Bytes())
+	if !bytes.Equal(codeHash, current) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"CodeHashMismatch(exp=%#x,given=%#x)", current, codeHash)
+	}
+
+	v := &scoreVerification{
+		CodeHash: codeHash,
+		Height:   b.Height(),
+	}
+	var sub *scoreSubmission
+	if param.Source != "" || len(param.Metadata) > 0 {
+		sub = new(scoreSubmission)
+		if param.Source != "" {
+			sub.Source = param.Source.Bytes()
+		}
+		if len(param.Metadata) > 0 {
+			if sub.Metadata, err = json.Marshal(param.Metadata); err != nil {
+				return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+			}
+		}
+	}
+	if err := setSCOREVerification(chain.Database(), addr, v); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if err := setSCORESubmission(chain.Database(), addr, sub); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if jso, err := v.ToJSON(addr, current, sub); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else {
+		return jso, nil
+	}
+}
+
+func getScoreVerification(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var param ScoreAddressParam
+	debug := ctx.IncludeDebug()
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	b, err := getBlock(chain, bm, param.Height)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	addr := param.Address.Address()
+	s, err := sm.GetSCOREStatus(b.Result(), addr)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	v, err := getSCOREVerification(chain.Database(), addr)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	sub, err := getSCORESubmission(chain.Database(), addr)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if jso, err := v.ToJSON(addr, s.CodeHash(), sub); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	} else {
+		return jso, nil
+	}
+}
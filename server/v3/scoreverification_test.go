@@ -0,0 +1,64 @@
+package v3
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/test"
+)
+
+func TestVerifyScore(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	addr := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	c := &testBalanceChain{
+		testTransitionChain: testTransitionChain{Chain: nd.Chain},
+		sm: &testServiceManager{
+			codeHash: map[string][]byte{
+				string(addr.Bytes()): crypto.SHA3Sum256([]byte("code")),
+			},
+		},
+	}
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	verify := func(params string) map[string]interface{} {
+		return invokeWithRepository(t, DebugMethodRepository(mtr), c,
+			`{"jsonrpc":"2.0","id":1,"method":"debug_verifyScore","params":`+params+`}`)
+	}
+	get := func() map[string]interface{} {
+		resp := invokeWithChain(t, c,
+			`{"jsonrpc":"2.0","id":1,"method":"icx_getScoreVerification","params":{"address":"cx0000000000000000000000000000000000000001"}}`)
+		jso, _ := resp["result"].(map[string]interface{})
+		return jso
+	}
+
+	resp := verify(`{"address":"cx0000000000000000000000000000000000000001","code":"0x01"}`)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, errorCodeOf(resp))
+	assert.Nil(t, get())
+
+	// source and metadata are served apart as unverified
+	verify(`{"address":"cx0000000000000000000000000000000000000001","code":"0x636f6465","source":"0x0102","metadata":{"compiler":"javac"}}`)
+	jso := get()
+	assert.Equal(t, "0x1", jso["current"])
+	assert.NotContains(t, jso, "source")
+	assert.NotContains(t, jso, "metadata")
+	assert.Equal(t, map[string]interface{}{
+		"source":     "0x0102",
+		"sourceHash": "0x" + hex.EncodeToString(crypto.SHA3Sum256([]byte{1, 2})),
+		"metadata":   map[string]interface{}{"compiler": "javac"},
+	}, jso["unverified"])
+
+	// the submission of the previous verification is removed
+	verify(`{"address":"cx0000000000000000000000000000000000000001","code":"0x636f6465"}`)
+	jso = get()
+	assert.Equal(t, "0x1", jso["current"])
+	assert.NotContains(t, jso, "unverified")
+}
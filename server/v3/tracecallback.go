@@ -6,19 +6,60 @@ import (
 	"math/big"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/trace"
 )
 
+const (
+	DefaultTraceTimeout    = 5 * time.Second
+	DefaultTraceMaxTimeout = time.Minute
+)
+
+// Types of frames of streaming traces.
+const (
+	TraceFrameLog   = "log"
+	TraceFrameReset = "reset"
+	TraceFrameEnd   = "end"
+)
+
+// TraceTimeout returns the timeout of the execution for tracing, which is
+// requested by the client. Zero requested uses the default, and it's limited
+// by MaxTimeout of the limit.
+func TraceTimeout(requested time.Duration, limit jsonrpc.TraceLimit) time.Duration {
+	max := limit.MaxTimeout
+	if max <= 0 {
+		max = DefaultTraceMaxTimeout
+	}
+	if requested <= 0 {
+		requested = DefaultTraceTimeout
+	}
+	if requested > max {
+		return max
+	}
+	return requested
+}
+
 type traceCallback struct {
-	lock    sync.Mutex
-	logs    []interface{}
-	last    error
-	ts      time.Time
-	channel chan interface{}
-	bt      *trace.BalanceTracer
+	lock      sync.Mutex
+	logs      []interface{}
+	entries   int
+	last      error
+	ts        time.Time
+	channel   chan interface{}
+	bt        *trace.BalanceTracer
+	st        *trace.StateTracer
+	limit     jsonrpc.TraceLimit
+	depth     int
+	truncated bool
+
+	// send is set for streaming traces. Logs are sent as they are made
+	// instead of being kept in logs.
+	send    func(frame interface{}) error
+	sendErr error
 }
 
 type traceLog struct {
@@ -27,16 +68,55 @@ type traceLog struct {
 	Ts    int64             `json:"ts"`
 }
 
+type traceLogFrame struct {
+	Type string `json:"type"`
+	traceLog
+}
+
+type traceResetFrame struct {
+	Type string `json:"type"`
+}
+
+func (t *traceCallback) sendInLock(frame interface{}) {
+	if t.sendErr == nil {
+		t.sendErr = t.send(frame)
+	}
+}
+
 func (t *traceCallback) OnLog(level module.TraceLevel, msg string) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
 	ts := time.Now()
-	if len(t.logs) == 0 {
+	if t.entries == 0 {
 		t.ts = ts
 	}
+	if (t.limit.MaxDepth > 0 && t.depth > t.limit.MaxDepth) ||
+		(t.limit.MaxEntries > 0 && t.entries >= t.limit.MaxEntries) {
+		t.truncated = true
+		return
+	}
+	t.entries += 1
 	dur := ts.Sub(t.ts) / time.Microsecond
-	t.logs = append(t.logs, traceLog{level, msg, int64(dur)})
+	msg = truncateString(msg, t.limit.MaxStringLength)
+	l := traceLog{level, msg, int64(dur)}
+	if t.send != nil {
+		t.sendInLock(&traceLogFrame{TraceFrameLog, l})
+		return
+	}
+	t.logs = append(t.logs, l)
+}
+
+// truncateString returns s cut to at most limit bytes without breaking
+// a character. Zero limit means no limit.
+func truncateString(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit] + "..."
 }
 
 func (t *traceCallback) OnEnd(e error) {
@@ -56,6 +136,9 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	result := map[string]interface{}{
 		"logs": t.logs,
 	}
+	if t.truncated {
+		result["truncated"] = true
+	}
 	if t.last == nil {
 		result["status"] = "0x1"
 	} else {
@@ -69,6 +152,15 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	return result
 }
 
+// endFrame returns the last frame of the streaming trace, which has the
+// result of invokeTraceToJSON except logs.
+func (t *traceCallback) endFrame() interface{} {
+	result := t.invokeTraceToJSON().(map[string]interface{})
+	delete(result, "logs")
+	result["type"] = TraceFrameEnd
+	return result
+}
+
 func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -94,6 +186,11 @@ func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -102,9 +199,18 @@ func (t *traceCallback) OnTransactionReset() error {
 	defer t.lock.Unlock()
 
 	t.logs = nil
+	t.entries = 0
+	t.depth = 0
+	t.truncated = false
+	if t.send != nil {
+		t.sendInLock(&traceResetFrame{TraceFrameReset})
+	}
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.st != nil {
+		return t.st.OnTransactionReset()
+	}
 	return nil
 }
 
@@ -114,24 +220,41 @@ func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
 func (t *traceCallback) OnFrameEnter() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.depth += 1
 	if t.bt != nil {
-		t.lock.Lock()
-		defer t.lock.Unlock()
 		return t.bt.OnFrameEnter()
 	}
+	if t.st != nil {
+		return t.st.OnFrameEnter()
+	}
 	return nil
 }
 
 func (t *traceCallback) OnFrameExit(success bool) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.depth > 0 {
+		t.depth -= 1
+	}
 	if t.bt != nil {
-		t.lock.Lock()
-		defer t.lock.Unlock()
 		return t.bt.OnFrameExit(success)
 	}
+	if t.st != nil {
+		return t.st.OnFrameExit(success)
+	}
 	return nil
 }
 
@@ -143,3 +266,42 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnStateRead(addr module.Address, key, value []byte) error {
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnStateRead(addr, key, value)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnStateWrite(addr module.Address, key, before, after []byte) error {
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnStateWrite(addr, key, before, after)
+	}
+	return nil
+}
+
+func (t *traceCallback) stateDiffToJSON() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := t.st.ToJSON(t.limit.MaxEntries, t.limit.RedactValues)
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	if t.last == nil {
+		result["status"] = "0x1"
+	} else {
+		result["status"] = "0x0"
+		status, _ := scoreresult.StatusOf(t.last)
+		result["failure"] = map[string]interface{}{
+			"code":    status,
+			"message": t.last.Error(),
+		}
+	}
+	return result
+}
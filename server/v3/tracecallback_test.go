@@ -0,0 +1,91 @@
+package v3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "abcdef", truncateString("abcdef", 0))
+	assert.Equal(t, "abcdef", truncateString("abcdef", 6))
+	assert.Equal(t, "abc...", truncateString("abcdef", 3))
+	// not to break multibyte characters
+	assert.Equal(t, "a...", truncateString("a한글", 3))
+}
+
+func TestTraceCallback_Limit(t *testing.T) {
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+		limit: jsonrpc.TraceLimit{
+			MaxEntries:      3,
+			MaxDepth:        1,
+			MaxStringLength: 4,
+		},
+	}
+	assert.NoError(t, cb.OnTransactionReset())
+	assert.NoError(t, cb.OnFrameEnter())
+	cb.OnLog(module.TSystemLevel, "first")
+	assert.NoError(t, cb.OnFrameEnter())
+	cb.OnLog(module.TSystemLevel, "deep")
+	assert.NoError(t, cb.OnFrameExit(true))
+	cb.OnLog(module.TSystemLevel, "2nd")
+	cb.OnLog(module.TSystemLevel, "3rd")
+	cb.OnLog(module.TSystemLevel, "4th")
+	assert.NoError(t, cb.OnFrameExit(true))
+	cb.OnEnd(nil)
+
+	result := cb.invokeTraceToJSON().(map[string]interface{})
+	logs := result["logs"].([]interface{})
+	assert.Len(t, logs, 3)
+	assert.Equal(t, "firs...", logs[0].(traceLog).Msg)
+	assert.Equal(t, "2nd", logs[1].(traceLog).Msg)
+	assert.Equal(t, "3rd", logs[2].(traceLog).Msg)
+	assert.Equal(t, true, result["truncated"])
+}
+
+func TestTraceTimeout(t *testing.T) {
+	limit := jsonrpc.TraceLimit{}
+	assert.Equal(t, DefaultTraceTimeout, TraceTimeout(0, limit))
+	assert.Equal(t, 10*time.Second, TraceTimeout(10*time.Second, limit))
+	assert.Equal(t, DefaultTraceMaxTimeout, TraceTimeout(time.Hour, limit))
+
+	limit.MaxTimeout = 2 * time.Second
+	assert.Equal(t, 2*time.Second, TraceTimeout(0, limit))
+	assert.Equal(t, time.Second, TraceTimeout(time.Second, limit))
+}
+
+func TestTraceCallback_Stream(t *testing.T) {
+	var frames []interface{}
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+		limit:   jsonrpc.TraceLimit{MaxEntries: 2},
+		send: func(frame interface{}) error {
+			frames = append(frames, frame)
+			return nil
+		},
+	}
+	cb.OnLog(module.TSystemLevel, "first")
+	assert.NoError(t, cb.OnTransactionReset())
+	cb.OnLog(module.TSystemLevel, "1st")
+	cb.OnLog(module.TSystemLevel, "2nd")
+	cb.OnLog(module.TSystemLevel, "3rd")
+	cb.OnEnd(nil)
+
+	assert.Len(t, cb.logs, 0)
+	assert.Len(t, frames, 4)
+	assert.Equal(t, "first", frames[0].(*traceLogFrame).Msg)
+	assert.Equal(t, TraceFrameReset, frames[1].(*traceResetFrame).Type)
+	assert.Equal(t, TraceFrameLog, frames[2].(*traceLogFrame).Type)
+	assert.Equal(t, "2nd", frames[3].(*traceLogFrame).Msg)
+
+	end := cb.endFrame().(map[string]interface{})
+	assert.Equal(t, TraceFrameEnd, end["type"])
+	assert.Equal(t, "0x1", end["status"])
+	assert.Equal(t, true, end["truncated"])
+	assert.NotContains(t, end, "logs")
+}
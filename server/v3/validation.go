@@ -22,6 +22,11 @@ func RegisterValidationRule(v *jsonrpc.Validator) {
 	v.RegisterValidation("message", isMessage)
 	v.RegisterValidation("deposit", isDeposit)
 
+	v.RegisterSchema("call", jsonrpc.Schema{"const": contract.DataTypeCall})
+	v.RegisterSchema("deploy", jsonrpc.Schema{"const": contract.DataTypeDeploy})
+	v.RegisterSchema("message", jsonrpc.Schema{"const": contract.DataTypeMessage})
+	v.RegisterSchema("deposit", jsonrpc.Schema{"const": contract.DataTypeDeposit})
+
 	// validate : CallParam.Data, TransactionParam.Data
 	v.RegisterStructValidation(DataParamValidation, CallParam{}, TransactionParam{})
 
@@ -86,3 +86,251 @@ func TestTransactionParamValidator(t *testing.T) {
 		assert.Fail(t, "validate fail", err.Error())
 	}
 }
+
+func TestProofTransactionParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"NoEvents", `{"txHash":"0x375540830d475a73b704cf8dee9fa9eba2798f9d2af1fa55a85482e48daefd3b"}`, true},
+		{"Events", `{"txHash":"0x375540830d475a73b704cf8dee9fa9eba2798f9d2af1fa55a85482e48daefd3b","events":["0x0","0x2"]}`, true},
+		{"NoHash", `{"events":["0x0"]}`, false},
+		{"InvalidEvent", `{"txHash":"0x375540830d475a73b704cf8dee9fa9eba2798f9d2af1fa55a85482e48daefd3b","events":["x"]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param ProofTransactionParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestProofExtensionParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Valid", `{"store":"reward","key":"0xf84440"}`, true},
+		{"Height", `{"store":"state","key":"0x01","height":"0x10"}`, true},
+		{"NoStore", `{"key":"0x01"}`, false},
+		{"NoKey", `{"store":"reward"}`, false},
+		{"InvalidHeight", `{"store":"reward","key":"0x01","height":"10"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param ProofExtensionParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestScoreStatusListParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Empty", `{}`, true},
+		{"Valid", `{"height":"0x10","start":"0x64","limit":"0x64"}`, true},
+		{"InvalidStart", `{"start":"100"}`, false},
+		{"InvalidLimit", `{"limit":"ten"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param ScoreStatusListParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestBlockReceiptsParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Height", `{"height":"0x10"}`, true},
+		{"Hash", `{"hash":"0x8ef3b2a67262b9b1fe4b598059774472e9ccef401734335d87a4ba998cfd40fb","patch":true}`, true},
+		{"InvalidHeight", `{"height":"16"}`, false},
+		{"InvalidHash", `{"hash":"0x1234"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param BlockReceiptsParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestLogsParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Empty", `{}`, true},
+		{"Full", `{"fromHeight":"0x10","toHeight":"0x20","addresses":["cx0000000000000000000000000000000000000001"],"event":"Transfer(Address,Address,int)","indexed":[null,"hx0000000000000000000000000000000000000001"],"limit":"0x10"}`, true},
+		{"InvalidHeight", `{"fromHeight":"16"}`, false},
+		{"InvalidAddress", `{"addresses":["hx0000000000000000000000000000000000000001"]}`, false},
+		{"InvalidLimit", `{"limit":"ten"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param LogsParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestRosettaTraceParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Empty", `{}`, true},
+		{"Height", `{"height":"0x10"}`, true},
+		{"Range", `{"fromHeight":"0x10","toHeight":"0x20"}`, true},
+		{"InvalidFromHeight", `{"fromHeight":"16"}`, false},
+		{"InvalidToHeight", `{"fromHeight":"0x10","toHeight":"-1"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param RosettaTraceParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestProposalSimulationParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Call", `{"calls":[{"method":"setStepPrice","params":{"price":"0x2e90edd00"}}]}`, true},
+		{"Full", `{"calls":[{"to":"cx0000000000000000000000000000000000000000","method":"setRevision","params":{"code":"0x15"}}],"transactions":"0x10"}`, true},
+		{"NoCalls", `{"calls":[]}`, false},
+		{"NoMethod", `{"calls":[{"params":{}}]}`, false},
+		{"InvalidTo", `{"calls":[{"to":"hx0000000000000000000000000000000000000001","method":"setRevision"}]}`, false},
+		{"InvalidTransactions", `{"calls":[{"method":"setRevision"}],"transactions":"10"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param ProposalSimulationParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestTransactionBatchParamForEstimateValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tx := `{"version":"0x3","from":"hx0000000000000000000000000000000000000001","to":"cx0000000000000000000000000000000000000001","timestamp":"0x5f7a6c4c1e3d0","nid":"0x1","dataType":"call","data":{"method":"transfer"}}`
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"Single", `{"transactions":[` + tx + `]}`, true},
+		{"Multiple", `{"transactions":[` + tx + `,` + tx + `]}`, true},
+		{"Empty", `{"transactions":[]}`, false},
+		{"NoTransactions", `{}`, false},
+		{"InvalidFrom", `{"transactions":[` + tx + `,{"version":"0x3","from":"cx0000000000000000000000000000000000000001","to":"hx0000000000000000000000000000000000000001","timestamp":"0x1","nid":"0x1"}]}`, false},
+		{"NoTimestamp", `{"transactions":[{"version":"0x3","from":"hx0000000000000000000000000000000000000001","to":"hx0000000000000000000000000000000000000002","nid":"0x1"}]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param TransactionBatchParamForEstimate
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestStatementParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"NoAddress", `{}`, false},
+		{"Valid", `{"address":"hx0000000000000000000000000000000000000001","fromTimestamp":"0x5fb8b1c1e3a00","start":"0x64","limit":"0x64"}`, true},
+		{"InvalidAddress", `{"address":"0x01"}`, false},
+		{"InvalidTimestamp", `{"address":"hx0000000000000000000000000000000000000001","toTimestamp":"100"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param StatementParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
+
+func TestTransactionsByAddressParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name  string
+		param string
+		ok    bool
+	}{
+		{"NoAddress", `{}`, false},
+		{"Valid", `{"address":"cx0000000000000000000000000000000000000001","direction":"to","order":"desc","start":"0x64","limit":"0x64"}`, true},
+		{"InvalidAddress", `{"address":"0x01"}`, false},
+		{"InvalidStart", `{"address":"hx0000000000000000000000000000000000000001","start":"100"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param TransactionsByAddressParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			err := validator.Validate(&param)
+			assert.Equal(t, tt.ok, err == nil, "err=%v", err)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package v3
+
+import (
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+type validatorParticipation struct {
+	address  module.Address
+	signed   int64
+	missed   int64
+	proposed int64
+}
+
+// voteParticipation aggregates participation of validators in commit votes
+// of blocks. Validators are kept in order of their first appearance.
+type voteParticipation struct {
+	validators []*validatorParticipation
+	index      map[string]int
+}
+
+func newVoteParticipation() *voteParticipation {
+	return &voteParticipation{
+		index: make(map[string]int),
+	}
+}
+
+func (p *voteParticipation) get(addr module.Address) *validatorParticipation {
+	key := string(addr.Bytes())
+	if idx, ok := p.index[key]; ok {
+		return p.validators[idx]
+	}
+	vp := &validatorParticipation{address: addr}
+	p.index[key] = len(p.validators)
+	p.validators = append(p.validators, vp)
+	return vp
+}
+
+// add adds the consensus information of a block.
+func (p *voteParticipation) add(csi module.ConsensusInfo) {
+	if proposer := csi.Proposer(); proposer != nil {
+		p.get(proposer).proposed++
+	}
+	voters := csi.Voters()
+	if voters == nil {
+		return
+	}
+	voted := csi.Voted()
+	for i := 0; i < voters.Len(); i++ {
+		v, _ := voters.Get(i)
+		vp := p.get(v.Address())
+		if i < len(voted) && voted[i] {
+			vp.signed++
+		} else {
+			vp.missed++
+		}
+	}
+}
+
+func (p *voteParticipation) ToJSON() []interface{} {
+	res := make([]interface{}, len(p.validators))
+	for i, vp := range p.validators {
+		res[i] = map[string]interface{}{
+			"address":  vp.address,
+			"signed":   intconv.FormatInt(vp.signed),
+			"missed":   intconv.FormatInt(vp.missed),
+			"proposed": intconv.FormatInt(vp.proposed),
+		}
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestVoteParticipation(t *testing.T) {
+	dbase := db.NewMapDB()
+	addrs := []module.Address{
+		common.MustNewAddressFromString("hx0000000000000000000000000000000000000001"),
+		common.MustNewAddressFromString("hx0000000000000000000000000000000000000002"),
+		common.MustNewAddressFromString("hx0000000000000000000000000000000000000003"),
+	}
+	validatorsOf := func(addrs ...module.Address) module.ValidatorList {
+		var vs []module.Validator
+		for _, addr := range addrs {
+			v, err := state.ValidatorFromAddress(addr)
+			assert.NoError(t, err)
+			vs = append(vs, v)
+		}
+		vl, err := state.ValidatorSnapshotFromSlice(dbase, vs)
+		assert.NoError(t, err)
+		return vl
+	}
+
+	vp := newVoteParticipation()
+	vl := validatorsOf(addrs[0], addrs[1])
+	vp.add(common.NewConsensusInfo(addrs[0], vl, []bool{true, true}))
+	vp.add(common.NewConsensusInfo(addrs[1], vl, []bool{true, false}))
+	vl = validatorsOf(addrs[1], addrs[2])
+	vp.add(common.NewConsensusInfo(addrs[1], vl, []bool{false, true}))
+	// the genesis block doesn't have voters
+	vp.add(common.NewConsensusInfo(nil, nil, nil))
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address":  addrs[0],
+			"signed":   "0x2",
+			"missed":   "0x0",
+			"proposed": "0x1",
+		},
+		map[string]interface{}{
+			"address":  addrs[1],
+			"signed":   "0x1",
+			"missed":   "0x2",
+			"proposed": "0x2",
+		},
+		map[string]interface{}{
+			"address":  addrs[2],
+			"signed":   "0x1",
+			"missed":   "0x0",
+			"proposed": "0x0",
+		},
+	}, vp.ToJSON())
+}
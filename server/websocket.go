@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
@@ -14,6 +17,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 type WebSocketConn interface {
@@ -21,6 +25,7 @@ type WebSocketConn interface {
 	WriteJSON(v interface{}) error
 	ReadMessage() (messageType int, p []byte, err error)
 	NextReader() (messageType int, r io.Reader, err error)
+	WriteControl(messageType int, data []byte, deadline time.Time) error
 }
 
 type WebSocketUpgrader interface {
@@ -28,17 +33,45 @@ type WebSocketUpgrader interface {
 }
 
 type wsSession struct {
-	lock  sync.Mutex
-	c     WebSocketConn
-	chain module.Chain
+	lock     sync.Mutex
+	c        WebSocketConn
+	conn     WebSocketConn
+	chain    module.Chain
+	ip       string
+	wm       *wsSessionManager
+	buffered int64
 }
 
 type wsSessionManager struct {
 	sync.Mutex
-	upgrader   WebSocketUpgrader
-	maxSession int
-	logger     log.Logger
-	sessions   []*wsSession
+	upgrader        WebSocketUpgrader
+	maxSession      int
+	maxSessionPerIP int
+	maxSubscription int
+	maxBuffer       int64
+	buffered        int64
+	logger          log.Logger
+	metric          *metric.WebSocketMetric
+	sessions        []*wsSession
+	ips             map[string]int
+}
+
+// wsSubscriptionRequest is implemented by requests subscribing multiple
+// sets of notifications with a session.
+type wsSubscriptionRequest interface {
+	subscriptions() int
+}
+
+// wsCloseError is the reason of rejecting or closing the session by the
+// limits.
+type wsCloseError struct {
+	code   int
+	reason string
+	msg    string
+}
+
+func (e *wsCloseError) Error() string {
+	return e.msg
 }
 
 func newWSSessionManager(logger log.Logger, maxSession int) *wsSessionManager {
@@ -47,31 +80,51 @@ func newWSSessionManager(logger log.Logger, maxSession int) *wsSessionManager {
 
 func newWSSessionManagerWithUpgrader(logger log.Logger, maxSession int, upgrader WebSocketUpgrader) *wsSessionManager {
 	return &wsSessionManager{
-		upgrader:   upgrader,
-		maxSession: maxSession,
-		logger:     logger,
+		upgrader:        upgrader,
+		maxSession:      maxSession,
+		maxSessionPerIP: DefaultWSMaxSessionPerIP,
+		maxSubscription: DefaultWSMaxSubscription,
+		maxBuffer:       DefaultWSMaxBuffer,
+		logger:          logger,
+		metric:          metric.NewWebSocketMetric(metric.DefaultMetricContext()),
+		ips:             make(map[string]int),
 	}
 }
 
-func (wm *wsSessionManager) NewSession(c WebSocketConn, chain module.Chain) *wsSession {
+func (wm *wsSessionManager) NewSession(c WebSocketConn, chain module.Chain, ip string) (*wsSession, error) {
 	wm.Lock()
 	defer wm.Unlock()
 
 	if len(wm.sessions) >= wm.maxSession {
-		return nil
+		return nil, &wsCloseError{WSCloseTooManySessions, metric.WSRejectSession, "too many monitor"}
+	}
+	if wm.maxSessionPerIP > 0 && wm.ips[ip] >= wm.maxSessionPerIP {
+		return nil, &wsCloseError{WSCloseTooManySessionsForIP, metric.WSRejectIP, "too many monitor for the address"}
 	}
-	wss := &wsSession{c: c, chain: chain}
+	wss := &wsSession{c: c, conn: c, chain: chain, ip: ip, wm: wm}
 	wm.sessions = append(wm.sessions, wss)
-	return wss
+	wm.ips[ip] += 1
+	wm.metric.OnSession(len(wm.sessions))
+	return wss, nil
+}
+
+func (wm *wsSessionManager) removeIPInLock(ip string) {
+	if wm.ips[ip] > 1 {
+		wm.ips[ip] -= 1
+	} else {
+		delete(wm.ips, ip)
+	}
 }
 
 func (wm *wsSessionManager) stopSessionAt(i int) {
 	wss := wm.sessions[i]
 	wss.Close()
+	wm.removeIPInLock(wss.ip)
 	last := len(wm.sessions) - 1
 	wm.sessions[i] = wm.sessions[last]
 	wm.sessions[last] = nil
 	wm.sessions = wm.sessions[:last]
+	wm.metric.OnSession(len(wm.sessions))
 }
 
 func (wm *wsSessionManager) StopSession(wss *wsSession) {
@@ -81,6 +134,7 @@ func (wm *wsSessionManager) StopSession(wss *wsSession) {
 	for i := 0; i < len(wm.sessions); i++ {
 		if wss == wm.sessions[i] {
 			wm.stopSessionAt(i)
+			break
 		}
 	}
 }
@@ -98,16 +152,20 @@ func (wm *wsSessionManager) stopAllSessionsInLock() {
 		wss.Close()
 	}
 	wm.sessions = nil
+	wm.ips = make(map[string]int)
+	wm.metric.OnSession(0)
 }
 
 func (wm *wsSessionManager) StopSessionsForChain(chain module.Chain) {
 	wm.Lock()
 	defer wm.Unlock()
 
-	for i := 0; i < len(wm.sessions); i++ {
+	for i := 0; i < len(wm.sessions); {
 		wss := wm.sessions[i]
 		if wss.chain == chain {
 			wm.stopSessionAt(i)
+		} else {
+			i++
 		}
 	}
 }
@@ -122,6 +180,82 @@ func (wm *wsSessionManager) SetMaxSession(limit int) {
 	}
 }
 
+// SetMaxSessionPerIP sets the limit of sessions from an IP address. It's
+// applied to new sessions, and zero or negative value means no limit.
+func (wm *wsSessionManager) SetMaxSessionPerIP(limit int) {
+	wm.Lock()
+	defer wm.Unlock()
+
+	wm.maxSessionPerIP = limit
+}
+
+// SetMaxSubscription sets the limit of event filters of a session. It's
+// applied to new sessions, and zero or negative value means no limit.
+func (wm *wsSessionManager) SetMaxSubscription(limit int) {
+	wm.Lock()
+	defer wm.Unlock()
+
+	wm.maxSubscription = limit
+}
+
+// SetMaxBuffer sets the limit of bytes of frames buffered by a session.
+// The session exceeding the limit is closed. Zero or negative value means
+// no limit.
+func (wm *wsSessionManager) SetMaxBuffer(limit int64) {
+	atomic.StoreInt64(&wm.maxBuffer, limit)
+}
+
+func (wm *wsSessionManager) checkSubscriptions(reqPtr interface{}) error {
+	r, ok := reqPtr.(wsSubscriptionRequest)
+	if !ok {
+		return nil
+	}
+	wm.Lock()
+	defer wm.Unlock()
+
+	if wm.maxSubscription > 0 && r.subscriptions() > wm.maxSubscription {
+		return &wsCloseError{WSCloseTooManySubscriptions, metric.WSRejectSubscription,
+			fmt.Sprintf("too many subscriptions (limit=%d)", wm.maxSubscription)}
+	}
+	return nil
+}
+
+// reject sends the response and the close frame for the error, then
+// closes the connection.
+func (wm *wsSessionManager) reject(c WebSocketConn, ce *wsCloseError) {
+	wm.metric.OnReject(ce.reason)
+	wsResponse := WSResponse{
+		Code:    int(jsonrpc.ErrorLackOfResource),
+		Message: ce.msg,
+	}
+	c.WriteJSON(&wsResponse)
+	writeClose(c, ce.code, ce.msg)
+	c.Close()
+}
+
+// reserve accounts bytes of the frame to be written. If the session
+// exceeds the limit, it closes the session and returns error.
+func (wm *wsSessionManager) reserve(wss *wsSession, n int) error {
+	limit := atomic.LoadInt64(&wm.maxBuffer)
+	if used := atomic.AddInt64(&wss.buffered, int64(n)); limit > 0 && used > limit {
+		atomic.AddInt64(&wss.buffered, -int64(n))
+		ce := &wsCloseError{WSCloseBufferOverflow, metric.WSRejectBuffer,
+			fmt.Sprintf("too many buffered frames (limit=%d)", limit)}
+		wm.metric.OnReject(ce.reason)
+		// WriteControl and Close may be called concurrently with writes.
+		writeClose(wss.conn, ce.code, ce.msg)
+		wss.conn.Close()
+		return ce
+	}
+	wm.metric.OnBuffered(atomic.AddInt64(&wm.buffered, int64(n)))
+	return nil
+}
+
+func (wm *wsSessionManager) release(wss *wsSession, n int) {
+	atomic.AddInt64(&wss.buffered, -int64(n))
+	wm.metric.OnBuffered(atomic.AddInt64(&wm.buffered, -int64(n)))
+}
+
 func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*wsSession, error) {
 	chain, err := wm.chain(ctx)
 	if err != nil {
@@ -149,16 +283,15 @@ func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*
 		c.Close()
 		return nil, err
 	}
+	if err := wm.checkSubscriptions(reqPtr); err != nil {
+		wm.reject(c, err.(*wsCloseError))
+		return nil, err
+	}
 
-	wss := wm.NewSession(c, chain)
-	if wss == nil {
-		wsResponse := WSResponse{
-			Code:    int(jsonrpc.ErrorLackOfResource),
-			Message: "too many monitor",
-		}
-		c.WriteJSON(&wsResponse)
-		c.Close()
-		return nil, errors.New("too many monitor")
+	wss, err := wm.NewSession(c, chain, ctx.RealIP())
+	if err != nil {
+		wm.reject(c, err.(*wsCloseError))
+		return nil, err
 	}
 	return wss, nil
 }
@@ -180,11 +313,20 @@ func (wss *wsSession) response(code int, msg string) error {
 }
 
 func (wss *wsSession) WriteJSON(v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := wss.wm.reserve(wss, len(bs)); err != nil {
+		return err
+	}
+	defer wss.wm.release(wss, len(bs))
+
 	wss.lock.Lock()
 	defer wss.lock.Unlock()
 
 	if wss.c != nil {
-		return wss.c.WriteJSON(v)
+		return wss.c.WriteJSON(json.RawMessage(bs))
 	} else {
 		return io.ErrUnexpectedEOF
 	}
@@ -214,7 +356,49 @@ func (wss *wsSession) RunLoop(ech chan<- error) {
 	}
 }
 
-const DefaultWSMaxSession = 10
+// RunReadLoop calls f with messages from the client until it fails to read.
+func (wss *wsSession) RunReadLoop(f func(msg []byte), ech chan<- error) {
+	wss.lock.Lock()
+	defer wss.lock.Unlock()
+
+	if wss.c != nil {
+		go func(c WebSocketConn) {
+			for {
+				_, msg, err := c.ReadMessage()
+				if err != nil {
+					ech <- err
+					break
+				}
+				f(msg)
+			}
+		}(wss.c)
+	} else {
+		ech <- errors.New("AlreadyClosed")
+	}
+}
+
+const (
+	DefaultWSMaxSession      = 10
+	DefaultWSMaxSessionPerIP = 0
+	DefaultWSMaxSubscription = 32
+	DefaultWSMaxBuffer       = 8 * 1024 * 1024
+)
+
+// Codes of the close frame sent on closing the session by the limits.
+const (
+	WSCloseTooManySessions      = 4001
+	WSCloseTooManySessionsForIP = 4002
+	WSCloseTooManySubscriptions = 4003
+	WSCloseBufferOverflow       = 4004
+
+	wsCloseTimeout = time.Second
+)
+
+func writeClose(c WebSocketConn, code int, msg string) error {
+	return c.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, msg),
+		time.Now().Add(wsCloseTimeout))
+}
 
 type WSResponse struct {
 	Code    int    `json:"code"`
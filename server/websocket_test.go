@@ -23,9 +23,11 @@ import (
 	"io"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
@@ -60,9 +62,10 @@ func newTestWebsocketUpgrader(handler testWebSocketHandler) *testWebSocketUpgrad
 
 type testWebSocketConn struct {
 	WebSocketConn
-	lock    sync.Mutex
-	in, out chan interface{}
-	closed  bool
+	lock      sync.Mutex
+	in, out   chan interface{}
+	closed    bool
+	closeCode int
 }
 
 func (c *testWebSocketConn) Close() error {
@@ -119,6 +122,21 @@ func (c *testWebSocketConn) WriteJSON(v interface{}) error {
 	return nil
 }
 
+func (c *testWebSocketConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if messageType == websocket.CloseMessage && len(data) >= 2 {
+		c.closeCode = int(data[0])<<8 | int(data[1])
+	}
+	return nil
+}
+
+func (c *testWebSocketConn) getCloseCode() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.closeCode
+}
+
 func (c *testWebSocketConn) ReadMessage() (messageType int, p []byte, err error) {
 	o, ok := <-c.in
 	if !ok {
@@ -159,6 +177,13 @@ func (ctx *testContext) Get(key string) interface{} {
 	return ctx.config[key]
 }
 
+func (ctx *testContext) RealIP() string {
+	if ip, ok := ctx.config["ip"].(string); ok {
+		return ip
+	}
+	return "127.0.0.1"
+}
+
 func newTestContext(chain module.Chain) *testContext {
 	return &testContext{
 		config: map[string]interface{}{
@@ -617,3 +642,89 @@ func TestWSSessionManager_Basic(t *testing.T) {
 
 	wm.StopAllSessions()
 }
+
+func TestWSSessionManager_Limits(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	cch := make(chan *testWebSocketConn, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, conn *testWebSocketConn) {
+		cch <- conn
+	})
+	s1 := make(chan string, 1)
+	wm := newWSSessionManagerWithUpgrader(logger, 10, upgrader)
+	wm.SetMaxSessionPerIP(1)
+	wm.SetMaxSubscription(2)
+	chain := newTestChain(0,
+		func(h int64) (getBlockFunc, error) {
+			return func() module.Block {
+				_, _ = <-s1
+				return &testBlock{
+					height: h,
+					result: "empty",
+				}
+			}, nil
+		},
+		blockReceipts{
+			"empty": testReceiptList{},
+		},
+	)
+	filter := map[string]interface{}{"event": "EventLog()"}
+	run := func(ip string, request interface{}) (*testWebSocketConn, int) {
+		ctx := newTestContext(chain)
+		ctx.config["ip"] = ip
+		go wm.RunEventSession(ctx)
+		conn := <-cch
+		assert.NoError(t, conn.clientWriteJSON(request))
+		bs, err := conn.clientRead()
+		assert.NoError(t, err)
+		var res WSResponse
+		assert.NoError(t, json.Unmarshal(bs, &res))
+		return conn, res.Code
+	}
+
+	_, code := run("10.0.0.1", map[string]interface{}{
+		"height": "0x1",
+		"event":  "EventLog()",
+	})
+	assert.Equal(t, 0, code)
+
+	conn, code := run("10.0.0.1", map[string]interface{}{
+		"height": "0x1",
+		"event":  "EventLog()",
+	})
+	assert.Equal(t, int(jsonrpc.ErrorLackOfResource), code)
+	assert.Equal(t, WSCloseTooManySessionsForIP, conn.getCloseCode())
+
+	conn, code = run("10.0.0.2", map[string]interface{}{
+		"height":       "0x1",
+		"eventFilters": []interface{}{filter, filter, filter},
+	})
+	assert.Equal(t, int(jsonrpc.ErrorLackOfResource), code)
+	assert.Equal(t, WSCloseTooManySubscriptions, conn.getCloseCode())
+
+	conn, code = run("10.0.0.2", map[string]interface{}{
+		"height":       "0x1",
+		"eventFilters": []interface{}{filter, filter},
+	})
+	assert.Equal(t, 0, code)
+
+	wm.Lock()
+	var wss *wsSession
+	for _, s := range wm.sessions {
+		if s.ip == "10.0.0.2" {
+			wss = s
+		}
+	}
+	wm.Unlock()
+
+	wm.SetMaxBuffer(16)
+	err := wss.WriteJSON(map[string]string{"message": "larger than the limit"})
+	assert.Error(t, err)
+	assert.Equal(t, WSCloseBufferOverflow, conn.getCloseCode())
+	assert.EqualValues(t, 0, atomic.LoadInt64(&wss.buffered))
+	assert.EqualValues(t, 0, atomic.LoadInt64(&wm.buffered))
+
+	close(s1)
+	wm.StopAllSessions()
+}
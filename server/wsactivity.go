@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/chain/watch"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type ActivityRequest struct {
+	Height    common.HexInt64   `json:"height"`
+	WatchList string            `json:"watchList,omitempty"`
+	Addresses []*common.Address `json:"addresses,omitempty"`
+}
+
+type ActivityNotification struct {
+	Hash      common.HexBytes  `json:"hash"`
+	Height    common.HexInt64  `json:"height"`
+	Index     common.HexInt32  `json:"index"`
+	TxHash    common.HexBytes  `json:"txHash"`
+	Addresses []module.Address `json:"addresses"`
+}
+
+func (r *ActivityRequest) Compile(c module.Chain) (*watch.AddressSet, error) {
+	if r.WatchList != "" {
+		if len(r.Addresses) > 0 {
+			return nil, fmt.Errorf("both watchList and addresses are used")
+		}
+		wp, ok := c.(watch.Provider)
+		if !ok {
+			return nil, fmt.Errorf("watch list is not supported")
+		}
+		return wp.WatchList(r.WatchList)
+	}
+	if len(r.Addresses) == 0 {
+		return nil, fmt.Errorf("no addresses")
+	}
+	addrs := make([]module.Address, 0, len(r.Addresses))
+	for i, addr := range r.Addresses {
+		if addr == nil {
+			return nil, fmt.Errorf("null address idx:%d", i)
+		}
+		addrs = append(addrs, addr)
+	}
+	return watch.NewAddressSet(addrs), nil
+}
+
+// RunActivitySession notifies transactions related to the addresses.
+// Receipts of the block are in the result of the next block, so
+// the notification of the block is sent after the next block is finalized.
+func (wm *wsSessionManager) RunActivitySession(ctx echo.Context) error {
+	var ar ActivityRequest
+	wss, err := wm.initSession(ctx, &ar)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	as, err := ar.Compile(wss.chain)
+	if err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		return nil
+	}
+
+	bm := wss.chain.BlockManager()
+	sm := wss.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	h := ar.Height.Value
+	if gh := wss.chain.GenesisStorage().Height(); gh > h {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
+			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
+		return nil
+	}
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+	var bch <-chan module.Block
+loop:
+	for {
+		bch, err = bm.WaitForBlock(h + 1)
+		if err != nil {
+			break loop
+		}
+		select {
+		case err = <-ech:
+			break loop
+		case next, ok := <-bch:
+			if !ok {
+				break loop
+			}
+			blk, err := bm.GetBlockByHeight(h)
+			if err != nil {
+				break loop
+			}
+			rl, err := sm.ReceiptListFromResult(next.Result(), module.TransactionGroupNormal)
+			if err != nil {
+				break loop
+			}
+			index := 0
+			for it := blk.NormalTransactions().Iterator(); it.Has(); it.Next() {
+				tx, _, err := it.Get()
+				if err != nil {
+					break loop
+				}
+				r, err := rl.Get(index)
+				if err != nil {
+					break loop
+				}
+				addrs, err := as.Related(tx, r)
+				if err != nil {
+					break loop
+				}
+				if len(addrs) > 0 {
+					an := &ActivityNotification{
+						Hash:      blk.ID(),
+						Height:    common.HexInt64{Value: h},
+						Index:     common.HexInt32{Value: int32(index)},
+						TxHash:    tx.ID(),
+						Addresses: addrs,
+					}
+					if err = wss.WriteJSON(an); err != nil {
+						wm.logger.Infof("fail to write json ActivityNotification err:%+v\n", err)
+						break loop
+					}
+				}
+				index++
+			}
+		}
+		h++
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
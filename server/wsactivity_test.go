@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/watch"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type testWatchChain struct {
+	module.Chain
+	lists map[string]*watch.AddressSet
+}
+
+func (c *testWatchChain) WatchList(name string) (*watch.AddressSet, error) {
+	if s, ok := c.lists[name]; ok {
+		return s, nil
+	}
+	return nil, errors.NotFoundError.Errorf("WatchListNotFound(name=%s)", name)
+}
+
+func TestActivityRequest_Compile(t *testing.T) {
+	addr := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	deposits := watch.NewAddressSet([]module.Address{addr})
+	chain := &testWatchChain{lists: map[string]*watch.AddressSet{"deposits": deposits}}
+
+	r := &ActivityRequest{Addresses: []*common.Address{addr}}
+	as, err := r.Compile(chain)
+	assert.NoError(t, err)
+	assert.True(t, as.Contains(addr))
+
+	r = &ActivityRequest{WatchList: "deposits"}
+	as, err = r.Compile(chain)
+	assert.NoError(t, err)
+	assert.Equal(t, deposits, as)
+
+	for _, r := range []*ActivityRequest{
+		{},
+		{WatchList: "unknown"},
+		{WatchList: "deposits", Addresses: []*common.Address{addr}},
+		{Addresses: []*common.Address{nil}},
+	} {
+		_, err = r.Compile(chain)
+		assert.Error(t, err)
+	}
+
+	_, err = (&ActivityRequest{WatchList: "deposits"}).Compile(nil)
+	assert.Error(t, err)
+}
@@ -145,6 +145,10 @@ loop:
 	return nil
 }
 
+func (r *BlockRequest) subscriptions() int {
+	return len(r.EventFilters)
+}
+
 func (r *BlockRequest) Compile() error {
 	for i, f := range r.EventFilters {
 		if f == nil {
@@ -0,0 +1,378 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+const (
+	DebugTypeBreak = "break"
+	DebugTypeEnd   = "end"
+
+	DebugCommandContinue = "continue"
+	DebugCommandStep     = "step"
+	DebugCommandAbort    = "abort"
+
+	debugIdleTimeout = 10 * time.Minute
+)
+
+var errDebugAborted = errors.New("debug session aborted")
+
+// DebugBreakpoint matches entries of the contract. If Method is empty,
+// it matches all methods of the contract.
+type DebugBreakpoint struct {
+	Address *common.Address `json:"address"`
+	Method  string          `json:"method,omitempty"`
+}
+
+type DebugRequest struct {
+	TxHash      common.HexBytes    `json:"txHash"`
+	Breakpoints []*DebugBreakpoint `json:"breakpoints,omitempty"`
+}
+
+type DebugCommand struct {
+	Command string `json:"command"`
+}
+
+type DebugFrame struct {
+	Depth   common.HexInt32 `json:"depth"`
+	Address module.Address  `json:"address"`
+	Method  string          `json:"method"`
+}
+
+type DebugStateRead struct {
+	Address module.Address  `json:"address"`
+	Key     common.HexBytes `json:"key"`
+	Value   common.HexBytes `json:"value"`
+}
+
+// DebugNotification is sent on a breakpoint (type "break") and at the end of
+// the transaction (type "end"). Reads has the storage reads after the last
+// notification.
+type DebugNotification struct {
+	Type   string            `json:"type"`
+	Step   common.HexInt32   `json:"step"`
+	Frames []*DebugFrame     `json:"frames,omitempty"`
+	Reads  []*DebugStateRead `json:"reads"`
+	Status *common.HexInt32  `json:"status,omitempty"`
+}
+
+// debugTracer stops the execution of the transaction on entries of the
+// contracts until the client continues.
+type debugTracer struct {
+	lock        sync.Mutex
+	breakpoints []*DebugBreakpoint
+	redact      bool
+	stepping    bool
+	active      bool
+	step        int32
+	frames      []*DebugFrame
+	reads       []*DebugStateRead
+
+	notify    func(n *DebugNotification) error
+	commands  chan string
+	aborted   chan struct{}
+	abortOnce sync.Once
+	channel   chan error
+}
+
+func newDebugTracer(bps []*DebugBreakpoint, notify func(n *DebugNotification) error) *debugTracer {
+	return &debugTracer{
+		breakpoints: bps,
+		stepping:    len(bps) == 0,
+		notify:      notify,
+		commands:    make(chan string),
+		aborted:     make(chan struct{}),
+		channel:     make(chan error, 1),
+	}
+}
+
+func (d *debugTracer) abort() {
+	d.abortOnce.Do(func() {
+		close(d.aborted)
+	})
+}
+
+func (d *debugTracer) isAborted() bool {
+	select {
+	case <-d.aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// onCommand handles the command from the client. Commands except abort
+// are applied at the next breakpoint.
+func (d *debugTracer) onCommand(cmd string) error {
+	switch cmd {
+	case DebugCommandAbort:
+		d.abort()
+		return nil
+	case DebugCommandContinue, DebugCommandStep:
+		select {
+		case d.commands <- cmd:
+		case <-d.aborted:
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func (d *debugTracer) hitBreakpoint(addr module.Address, method string) bool {
+	for _, bp := range d.breakpoints {
+		if bp.Address.Equal(addr) && (bp.Method == "" || bp.Method == method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *debugTracer) contractFramesInLock() []*DebugFrame {
+	frames := make([]*DebugFrame, 0, len(d.frames))
+	for _, f := range d.frames {
+		if f.Address != nil {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+func (d *debugTracer) takeReadsInLock() []*DebugStateRead {
+	reads := d.reads
+	d.reads = nil
+	if reads == nil {
+		reads = []*DebugStateRead{}
+	}
+	return reads
+}
+
+func (d *debugTracer) OnContractEnter(addr module.Address, method string) error {
+	d.lock.Lock()
+	if !d.active || d.isAborted() {
+		d.lock.Unlock()
+		return nil
+	}
+	if l := len(d.frames); l > 0 {
+		d.frames[l-1].Address = addr
+		d.frames[l-1].Method = method
+	}
+	if !d.stepping && !d.hitBreakpoint(addr, method) {
+		d.lock.Unlock()
+		return nil
+	}
+	d.step += 1
+	n := &DebugNotification{
+		Type:   DebugTypeBreak,
+		Step:   common.HexInt32{Value: d.step},
+		Frames: d.contractFramesInLock(),
+		Reads:  d.takeReadsInLock(),
+	}
+	d.lock.Unlock()
+
+	if err := d.notify(n); err != nil {
+		d.abort()
+		return err
+	}
+	select {
+	case cmd := <-d.commands:
+		d.lock.Lock()
+		d.stepping = cmd == DebugCommandStep
+		d.lock.Unlock()
+		return nil
+	case <-d.aborted:
+		return errDebugAborted
+	case <-time.After(debugIdleTimeout):
+		d.abort()
+		return errDebugAborted
+	}
+}
+
+func (d *debugTracer) OnStateRead(addr module.Address, key, value []byte) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.active {
+		if d.redact {
+			value = nil
+		}
+		d.reads = append(d.reads, &DebugStateRead{
+			Address: addr,
+			Key:     key,
+			Value:   value,
+		})
+	}
+	return nil
+}
+
+func (d *debugTracer) OnStateWrite(addr module.Address, key, before, after []byte) error {
+	return nil
+}
+
+func (d *debugTracer) OnLog(level module.TraceLevel, msg string) {
+	// do nothing
+}
+
+func (d *debugTracer) OnEnd(e error) {
+	d.channel <- e
+}
+
+func (d *debugTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	return d.OnTransactionReset()
+}
+
+func (d *debugTracer) OnTransactionReset() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.active = true
+	d.frames = nil
+	d.reads = nil
+	return nil
+}
+
+func (d *debugTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.active = false
+	return nil
+}
+
+func (d *debugTracer) OnFrameEnter() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.frames = append(d.frames, &DebugFrame{
+		Depth: common.HexInt32{Value: int32(len(d.frames) + 1)},
+	})
+	return nil
+}
+
+func (d *debugTracer) OnFrameExit(success bool) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if l := len(d.frames); l > 0 {
+		d.frames = d.frames[:l-1]
+	}
+	return nil
+}
+
+func (d *debugTracer) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) error {
+	return nil
+}
+
+func (d *debugTracer) endNotification(status module.Status) *DebugNotification {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return &DebugNotification{
+		Type:   DebugTypeEnd,
+		Step:   common.HexInt32{Value: d.step},
+		Reads:  d.takeReadsInLock(),
+		Status: &common.HexInt32{Value: int32(status)},
+	}
+}
+
+// RunDebugSession executes the transaction again, and stops on entries of
+// the contracts matched with the breakpoints (or all entries if there is
+// no breakpoint) until the client sends a command.
+func (wm *wsSessionManager) RunDebugSession(ctx echo.Context) error {
+	var dr DebugRequest
+	wss, err := wm.initSession(ctx, &dr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if len(dr.TxHash) == 0 {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), "no txHash")
+		return nil
+	}
+	for i, bp := range dr.Breakpoints {
+		if bp == nil || bp.Address == nil {
+			_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
+				fmt.Sprintf("no address for breakpoint idx:%d", i))
+			return nil
+		}
+	}
+
+	tr, txInfo, err := v3.ReplayTransition(wss.chain, dr.TxHash, true)
+	if err != nil {
+		code := jsonrpc.ErrorCodeServer
+		if je, ok := err.(*jsonrpc.Error); ok {
+			code = je.Code
+		}
+		_ = wss.response(int(code), err.Error())
+		return nil
+	}
+	rct, err := txInfo.GetReceipt()
+	if err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeSystem), err.Error())
+		return nil
+	}
+
+	d := newDebugTracer(dr.Breakpoints, func(n *DebugNotification) error {
+		return wss.WriteJSON(n)
+	})
+	if limit, ok := ctx.Get("traceLimit").(jsonrpc.TraceLimit); ok {
+		d.redact = limit.RedactValues
+	}
+	canceler, err := tr.ExecuteForTrace(module.TraceInfo{
+		TraceMode: module.TraceModeDebug,
+		Range:     module.TraceRangeTransaction,
+		Group:     txInfo.Group(),
+		Index:     txInfo.Index(),
+		Callback:  d,
+	})
+	if err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeSystem), err.Error())
+		return nil
+	}
+	defer d.abort()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunReadLoop(func(msg []byte) {
+		var cmd DebugCommand
+		if err := json.Unmarshal(msg, &cmd); err != nil {
+			_ = wss.response(int(jsonrpc.ErrorCodeJsonParse), err.Error())
+			return
+		}
+		if err := d.onCommand(cmd.Command); err != nil {
+			_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		}
+	}, ech)
+
+	select {
+	case err = <-ech:
+		canceler()
+	case <-d.aborted:
+		canceler()
+		err = errDebugAborted
+	case err = <-d.channel:
+		if err != nil {
+			_ = wss.response(int(jsonrpc.ErrorCodeSystem), err.Error())
+		} else if d.isAborted() {
+			err = errDebugAborted
+		} else {
+			err = wss.WriteJSON(d.endNotification(rct.Status()))
+		}
+	}
+	wm.logger.Infof("debug session done err=%+v", err)
+	return nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestDebugTracer(t *testing.T) {
+	score1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000011")
+	score2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000012")
+
+	notified := make(chan *DebugNotification, 1)
+	d := newDebugTracer([]*DebugBreakpoint{
+		{Address: score2, Method: "transfer"},
+	}, func(n *DebugNotification) error {
+		notified <- n
+		return nil
+	})
+
+	// entries out of the transaction are ignored
+	assert.NoError(t, d.OnContractEnter(score2, "transfer"))
+	assert.Len(t, notified, 0)
+
+	assert.NoError(t, d.OnTransactionStart(0, []byte{0x01}, false))
+	assert.NoError(t, d.OnFrameEnter())
+	assert.NoError(t, d.OnContractEnter(score1, "run"))
+	assert.NoError(t, d.OnStateRead(score1, []byte{0x01}, []byte{0x02}))
+	assert.Len(t, notified, 0)
+
+	// stop at the breakpoint, then step to the next entry
+	assert.NoError(t, d.OnFrameEnter())
+	go func() {
+		n := <-notified
+		assert.Equal(t, DebugTypeBreak, n.Type)
+		assert.Equal(t, int32(1), n.Step.Value)
+		assert.Len(t, n.Frames, 2)
+		assert.True(t, score2.Equal(n.Frames[1].Address))
+		assert.Equal(t, "transfer", n.Frames[1].Method)
+		assert.Len(t, n.Reads, 1)
+		assert.NoError(t, d.onCommand(DebugCommandStep))
+	}()
+	assert.NoError(t, d.OnContractEnter(score2, "transfer"))
+	assert.NoError(t, d.OnFrameExit(true))
+
+	assert.NoError(t, d.OnFrameEnter())
+	go func() {
+		n := <-notified
+		assert.Equal(t, int32(2), n.Step.Value)
+		assert.Len(t, n.Reads, 0)
+		assert.Error(t, d.onCommand("unknown"))
+		assert.NoError(t, d.onCommand(DebugCommandAbort))
+	}()
+	assert.Error(t, d.OnContractEnter(score1, "other"))
+	assert.True(t, d.isAborted())
+
+	// no more breaks after abort
+	assert.NoError(t, d.OnContractEnter(score2, "transfer"))
+	assert.Len(t, notified, 0)
+}
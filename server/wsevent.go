@@ -10,6 +10,7 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/v3"
 	"github.com/icon-project/goloop/service/scoreapi"
 	"github.com/icon-project/goloop/service/txresult"
 )
@@ -19,6 +20,10 @@ type EventRequest struct {
 	Height common.HexInt64 `json:"height"`
 	Logs   common.HexInt32 `json:"logs,omitempty""`
 
+	// Decoded is whether to include event logs decoded with the APIs of
+	// the contracts.
+	Decoded bool `json:"decoded,omitempty"`
+
 	Filters EventFilters `json:"eventFilters,omitempty"`
 }
 
@@ -42,6 +47,8 @@ type EventNotification struct {
 	Index  common.HexInt32   `json:"index"`
 	Events []common.HexInt32 `json:"events"`
 	Logs   []module.EventLog `json:"logs,omitempty"`
+
+	DecodedLogs []interface{} `json:"decodedLogs,omitempty"`
 }
 
 // FilteredByLogBloom returns applicable event filters.
@@ -155,19 +162,31 @@ loop:
 			if err != nil {
 				break loop
 			}
+			// APIs of the contracts after execution are in the result.
+			var decoder *v3.EventDecoder
+			if er.Decoded {
+				decoder = v3.NewEventDecoder(sm, blk.Result())
+			}
 			index := int32(0)
 			for rit := rl.Iterator(); rit.Has(); rit.Next() {
 				r, err := rit.Get()
 				if err != nil {
 					break loop
 				}
-				if es, el, err := filters2.MatchEvents(r, er.Logs.Value != 0); err == nil && len(es) > 0 {
+				if es, el, err := filters2.MatchEvents(r, er.Logs.Value != 0 || er.Decoded); err == nil && len(es) > 0 {
 					var en EventNotification
 					en.Height.Value = h
 					en.Hash = blk.ID()
 					en.Index.Value = index
 					en.Events = es
-					en.Logs = el
+					if er.Logs.Value != 0 {
+						en.Logs = el
+					}
+					if decoder != nil {
+						for _, l := range el {
+							en.DecodedLogs = append(en.DecodedLogs, decoder.DecodeEventLog(l))
+						}
+					}
 					if err := wss.WriteJSON(&en); err != nil {
 						wm.logger.Infof("fail to write json EventNotification err:%+v\n", err)
 						break loop
@@ -299,6 +318,13 @@ func (f *EventFilter) filterEvents(r module.Receipt, v func(idx int, log module.
 	return nil
 }
 
+func (f *EventRequest) subscriptions() int {
+	if len(f.Filters) > 0 {
+		return len(f.Filters)
+	}
+	return 1
+}
+
 func (f *EventRequest) Compile() (EventFilters, error) {
 	var filters []*EventFilter
 	if len(f.Filters) > 0 {
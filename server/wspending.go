@@ -0,0 +1,217 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	PendingTypeEvent       = "pending"
+	PendingTypeConfirmed   = "confirmed"
+	PendingTypeInvalidated = "invalidated"
+
+	pendingQueueSize = 64
+)
+
+type PendingRequest struct {
+	EventFilters EventFilters    `json:"eventFilters"`
+	Logs         common.HexInt32 `json:"logs,omitempty"`
+}
+
+// PendingNotification notifies events of a transaction in a block which is
+// not finalized yet (type "pending"). After the block at the height is
+// finalized, it notifies whether the transactions with the ID is included
+// in the finalized block (type "confirmed") or not (type "invalidated").
+type PendingNotification struct {
+	Type   string              `json:"type"`
+	Height common.HexInt64     `json:"height"`
+	ID     common.HexBytes     `json:"id"`
+	Index  *common.HexInt32    `json:"index,omitempty"`
+	TxHash common.HexBytes     `json:"txHash,omitempty"`
+	Events [][]common.HexInt32 `json:"events,omitempty"`
+	Logs   [][]module.EventLog `json:"logs,omitempty"`
+	Hash   common.HexBytes     `json:"hash,omitempty"`
+}
+
+func (r *PendingRequest) subscriptions() int {
+	return len(r.EventFilters)
+}
+
+func (r *PendingRequest) Compile() error {
+	if len(r.EventFilters) == 0 {
+		return fmt.Errorf("no event filters")
+	}
+	for i, f := range r.EventFilters {
+		if f == nil {
+			return fmt.Errorf("null filter idx:%d", i)
+		}
+		if err := f.Compile(); err != nil {
+			return fmt.Errorf("fail to compile idx:%d, err:%v", i, err)
+		}
+	}
+	return nil
+}
+
+// notificationsOf returns notifications for transactions having events
+// matched with the filters.
+func (r *PendingRequest) notificationsOf(pe *service.PendingExecution) ([]*PendingNotification, error) {
+	var ns []*PendingNotification
+	txs := pe.Transactions.Iterator()
+	for rit, idx := pe.Receipts.Iterator(), int32(0); rit.Has(); rit.Next() {
+		rct, err := rit.Get()
+		if err != nil {
+			return nil, err
+		}
+		tx, _, err := txs.Get()
+		if err != nil {
+			return nil, err
+		}
+		var n *PendingNotification
+		for i, f := range r.EventFilters {
+			es, logs, err := f.MatchEvents(rct, r.Logs.Value != 0)
+			if err != nil {
+				return nil, err
+			}
+			if len(es) == 0 {
+				continue
+			}
+			if n == nil {
+				n = &PendingNotification{
+					Type:   PendingTypeEvent,
+					Height: common.HexInt64{Value: pe.Height},
+					ID:     pe.ID,
+					Index:  &common.HexInt32{Value: idx},
+					TxHash: tx.ID(),
+					Events: make([][]common.HexInt32, len(r.EventFilters)),
+				}
+				if r.Logs.Value != 0 {
+					n.Logs = make([][]module.EventLog, len(r.EventFilters))
+				}
+			}
+			n.Events[i] = es
+			if r.Logs.Value != 0 {
+				n.Logs[i] = logs
+			}
+		}
+		if n != nil {
+			ns = append(ns, n)
+		}
+		txs.Next()
+		idx++
+	}
+	return ns, nil
+}
+
+func (wm *wsSessionManager) RunPendingSession(ctx echo.Context) error {
+	var pr PendingRequest
+	wss, err := wm.initSession(ctx, &pr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if err := pr.Compile(); err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		return nil
+	}
+
+	bm := wss.chain.BlockManager()
+	sm := wss.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+	src, ok := sm.(service.PendingExecutionSource)
+	if !ok {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "NotSupported")
+		return nil
+	}
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), err.Error())
+		return nil
+	}
+
+	pch := make(chan *service.PendingExecution, pendingQueueSize)
+	remove := src.PendingExecutions().AddListener(func(pe *service.PendingExecution) {
+		select {
+		case pch <- pe:
+		default:
+			wm.logger.Warnf("drop pending execution height=%d", pe.Height)
+		}
+	})
+	defer remove()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+	// IDs of notified executions for each height
+	notified := make(map[int64][][]byte)
+	h := last.Height() + 1
+	var bch <-chan module.Block
+loop:
+	for {
+		if bch == nil {
+			if bch, err = bm.WaitForBlock(h); err != nil {
+				break loop
+			}
+		}
+		select {
+		case err = <-ech:
+			break loop
+		case pe := <-pch:
+			if pe.Height < h {
+				continue
+			}
+			ns, err := pr.notificationsOf(pe)
+			if err != nil {
+				break loop
+			}
+			if len(ns) == 0 {
+				continue
+			}
+			notified[pe.Height] = append(notified[pe.Height], pe.ID)
+			for _, n := range ns {
+				if err = wss.WriteJSON(n); err != nil {
+					wm.logger.Infof("fail to write json PendingNotification err:%+v\n", err)
+					break loop
+				}
+			}
+		case blk, ok := <-bch:
+			if !ok {
+				break loop
+			}
+			bch = nil
+			id := blk.NormalTransactions().Hash()
+			for _, pid := range notified[h] {
+				n := &PendingNotification{
+					Height: common.HexInt64{Value: h},
+					ID:     pid,
+				}
+				if bytes.Equal(pid, id) {
+					n.Type = PendingTypeConfirmed
+					n.Hash = blk.ID()
+				} else {
+					n.Type = PendingTypeInvalidated
+				}
+				if err = wss.WriteJSON(n); err != nil {
+					wm.logger.Infof("fail to write json PendingNotification err:%+v\n", err)
+					break loop
+				}
+			}
+			delete(notified, h)
+			h++
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
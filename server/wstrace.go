@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+// TraceRequest is the request of the trace session. Timeout is the
+// execution time in millisecond, and zero uses the default.
+type TraceRequest struct {
+	TxHash  common.HexBytes `json:"txHash"`
+	Timeout common.HexInt64 `json:"timeout,omitempty"`
+}
+
+// RunTraceSession executes the transaction again like debug_getTrace, and
+// streams trace logs to the client as they are made, so that heavy
+// transactions are traced without keeping all logs in the node.
+func (wm *wsSessionManager) RunTraceSession(ctx echo.Context) error {
+	if f, ok := ctx.Get("methodFilter").(jsonrpc.MethodFilter); ok && f != nil {
+		if err := f.FilterMethod("debug_getTrace"); err != nil {
+			return ctx.JSON(http.StatusTooManyRequests, err)
+		}
+	}
+
+	var tr TraceRequest
+	wss, err := wm.initSession(ctx, &tr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if len(tr.TxHash) == 0 {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), "no txHash")
+		return nil
+	}
+	if tr.Timeout.Value < 0 {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), "invalid timeout")
+		return nil
+	}
+	limit, _ := ctx.Get("traceLimit").(jsonrpc.TraceLimit)
+
+	// the response for the request is sent before the first frame.
+	var once sync.Once
+	send := func(frame interface{}) error {
+		var err error
+		once.Do(func() {
+			err = wss.response(0, "")
+		})
+		if err != nil {
+			return err
+		}
+		return wss.WriteJSON(frame)
+	}
+
+	ech := make(chan error, 1)
+	wss.RunReadLoop(func(msg []byte) {
+		// no command for the session
+	}, ech)
+
+	timeout := time.Duration(tr.Timeout.Value) * time.Millisecond
+	err = v3.StreamTrace(wss.chain, tr.TxHash, timeout, limit, send, ech)
+	if je, ok := err.(*jsonrpc.Error); ok {
+		_ = wss.response(int(je.Code), je.Message)
+	} else if err != nil {
+		wm.logger.Infof("trace session aborted err=%+v", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	txPoolQueueSize = 256
+)
+
+type TxPoolRequest struct {
+	Full common.HexInt32 `json:"full,omitempty"`
+}
+
+// TxPoolNotification notifies a transaction accepted into the transaction
+// pool. Dropped is the number of transactions dropped before it because
+// the client didn't read notifications fast enough.
+type TxPoolNotification struct {
+	TxHash      common.HexBytes  `json:"txHash"`
+	Transaction interface{}      `json:"transaction,omitempty"`
+	Dropped     *common.HexInt32 `json:"dropped,omitempty"`
+}
+
+// txPoolQueue buffers transactions for the session. The listener can't wait
+// for the client, so it drops transactions on overflow and counts them.
+type txPoolQueue struct {
+	ch      chan module.Transaction
+	dropped int32
+}
+
+func (q *txPoolQueue) push(tx module.Transaction) bool {
+	select {
+	case q.ch <- tx:
+		return true
+	default:
+		atomic.AddInt32(&q.dropped, 1)
+		return false
+	}
+}
+
+func (q *txPoolQueue) takeDropped() int32 {
+	return atomic.SwapInt32(&q.dropped, 0)
+}
+
+func newTxPoolQueue(size int) *txPoolQueue {
+	return &txPoolQueue{ch: make(chan module.Transaction, size)}
+}
+
+func (r *TxPoolRequest) notificationOf(tx module.Transaction, dropped int32) (*TxPoolNotification, error) {
+	n := &TxPoolNotification{TxHash: tx.ID()}
+	if r.Full.Value != 0 {
+		js, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		n.Transaction = js
+	}
+	if dropped > 0 {
+		n.Dropped = &common.HexInt32{Value: dropped}
+	}
+	return n, nil
+}
+
+// RunTxPoolSession notifies transactions accepted into the transaction pool
+// of the channel.
+func (wm *wsSessionManager) RunTxPoolSession(ctx echo.Context) error {
+	var tr TxPoolRequest
+	wss, err := wm.initSession(ctx, &tr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	sm := wss.chain.ServiceManager()
+	if sm == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+	src, ok := sm.(service.TxFeedSource)
+	if !ok {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "NotSupported")
+		return nil
+	}
+
+	q := newTxPoolQueue(txPoolQueueSize)
+	remove := src.TxFeed().AddListener(func(tx module.Transaction) {
+		q.push(tx)
+	})
+	defer remove()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case tx := <-q.ch:
+			dropped := q.takeDropped()
+			if dropped > 0 {
+				wm.logger.Warnf("drop transactions for slow client cnt=%d", dropped)
+			}
+			var n *TxPoolNotification
+			if n, err = tr.notificationOf(tx, dropped); err != nil {
+				break loop
+			}
+			if err = wss.WriteJSON(n); err != nil {
+				wm.logger.Infof("fail to write json TxPoolNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type testPoolTx struct {
+	module.Transaction
+	id []byte
+}
+
+func (tx *testPoolTx) ID() []byte {
+	return tx.id
+}
+
+func (tx *testPoolTx) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{"version": "0x3"}, nil
+}
+
+func TestTxPoolQueue_Push(t *testing.T) {
+	q := newTxPoolQueue(2)
+	assert.True(t, q.push(&testPoolTx{id: []byte{0x01}}))
+	assert.True(t, q.push(&testPoolTx{id: []byte{0x02}}))
+	assert.False(t, q.push(&testPoolTx{id: []byte{0x03}}))
+	assert.False(t, q.push(&testPoolTx{id: []byte{0x04}}))
+
+	assert.EqualValues(t, 2, q.takeDropped())
+	assert.EqualValues(t, 0, q.takeDropped())
+
+	tx := <-q.ch
+	assert.EqualValues(t, []byte{0x01}, tx.ID())
+	assert.True(t, q.push(&testPoolTx{id: []byte{0x05}}))
+}
+
+func TestTxPoolRequest_NotificationOf(t *testing.T) {
+	tx := &testPoolTx{id: []byte{0x01}}
+
+	r := &TxPoolRequest{}
+	n, err := r.notificationOf(tx, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []byte{0x01}, n.TxHash)
+	assert.Nil(t, n.Transaction)
+	assert.Nil(t, n.Dropped)
+
+	r.Full.Value = 1
+	n, err = r.notificationOf(tx, 3)
+	assert.NoError(t, err)
+	assert.NotNil(t, n.Transaction)
+	assert.EqualValues(t, 3, n.Dropped.Value)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	keyAccessIndex      = "access_index."
+	keyAccessIndexStart = "access_index_start"
+)
+
+// AccessHeight is the heights of the last blocks accessing the account and
+// its storage. Both reads and writes by the execution of the blocks are
+// counted, and they are recorded only by the node executing the blocks
+// with access_index of the chain. So it's not a part of the world state.
+type AccessHeight struct {
+	Account int64
+	Storage int64
+}
+
+func accessIndexKey(key []byte) []byte {
+	return append([]byte(keyAccessIndex), key...)
+}
+
+func getAccessHeight(bk db.Bucket, key []byte) (*AccessHeight, error) {
+	ah := new(AccessHeight)
+	bs, err := bk.Get(accessIndexKey(key))
+	if err != nil || bs == nil {
+		return ah, err
+	}
+	if _, err := codec.BC.UnmarshalFromBytes(bs, ah); err != nil {
+		return nil, err
+	}
+	return ah, nil
+}
+
+// storeAccessHeights records the height on the accounts accessed by the
+// block. accesses maps the IDs of the accounts to whether their storages
+// are accessed.
+func storeAccessHeights(dbase db.Database, height int64, accesses map[string]bool) error {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	for id, storage := range accesses {
+		key := state.AccountKeyOf([]byte(id))
+		ah, err := getAccessHeight(bk, key)
+		if err != nil {
+			return err
+		}
+		ah.Account = height
+		if storage {
+			ah.Storage = height
+		}
+		if err := bk.Set(accessIndexKey(key), codec.BC.MustMarshalToBytes(ah)); err != nil {
+			return err
+		}
+	}
+	if bs, err := bk.Get([]byte(keyAccessIndexStart)); err != nil {
+		return err
+	} else if bs == nil {
+		return bk.Set([]byte(keyAccessIndexStart), codec.BC.MustMarshalToBytes(height))
+	}
+	return nil
+}
+
+// accessIndexStart returns the height of the first block recorded in the
+// access index. It returns zero if nothing is recorded.
+func accessIndexStart(dbase db.Database) (int64, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(keyAccessIndexStart))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// accessRecorder collects the accounts accessed by the execution of a
+// block until the result is finalized.
+type accessRecorder struct {
+	lock     sync.Mutex
+	accesses map[string]bool
+}
+
+func newAccessRecorder() *accessRecorder {
+	return &accessRecorder{
+		accesses: make(map[string]bool),
+	}
+}
+
+func (r *accessRecorder) onAccess(id []byte, storage bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := string(id)
+	r.accesses[key] = r.accesses[key] || storage
+}
+
+// accessTrackWorldContext reports accesses to the accounts to the recorder.
+// Futures and contexts for the changed world state report to the same
+// recorder, so that accesses of parallel executions are also collected.
+type accessTrackWorldContext struct {
+	state.WorldContext
+	r *accessRecorder
+}
+
+func (wc *accessTrackWorldContext) GetAccountState(id []byte) state.AccountState {
+	as := wc.WorldContext.GetAccountState(id)
+	if as == nil {
+		return nil
+	}
+	wc.r.onAccess(id, false)
+	return &accessTrackAccountState{
+		AccountState: as,
+		id:           id,
+		r:            wc.r,
+	}
+}
+
+func (wc *accessTrackWorldContext) GetAccountSnapshot(id []byte) state.AccountSnapshot {
+	ass := wc.WorldContext.GetAccountSnapshot(id)
+	if ass != nil {
+		wc.r.onAccess(id, false)
+	}
+	return ass
+}
+
+func (wc *accessTrackWorldContext) GetFuture(lq []state.LockRequest) state.WorldContext {
+	return newAccessTrackWorldContext(wc.WorldContext.GetFuture(lq), wc.r)
+}
+
+func (wc *accessTrackWorldContext) WorldStateChanged(ws state.WorldState) state.WorldContext {
+	return newAccessTrackWorldContext(wc.WorldContext.WorldStateChanged(ws), wc.r)
+}
+
+type accessTrackAccountState struct {
+	state.AccountState
+	id []byte
+	r  *accessRecorder
+}
+
+func (as *accessTrackAccountState) GetValue(k []byte) ([]byte, error) {
+	as.r.onAccess(as.id, true)
+	return as.AccountState.GetValue(k)
+}
+
+func (as *accessTrackAccountState) SetValue(k, v []byte) ([]byte, error) {
+	as.r.onAccess(as.id, true)
+	return as.AccountState.SetValue(k, v)
+}
+
+func (as *accessTrackAccountState) DeleteValue(k []byte) ([]byte, error) {
+	as.r.onAccess(as.id, true)
+	return as.AccountState.DeleteValue(k)
+}
+
+func (as *accessTrackAccountState) GetObjGraph(id []byte, flags bool) (int, []byte, []byte, error) {
+	as.r.onAccess(as.id, true)
+	return as.AccountState.GetObjGraph(id, flags)
+}
+
+func (as *accessTrackAccountState) SetObjGraph(id []byte, flags bool, nextHash int, objGraph []byte) error {
+	as.r.onAccess(as.id, true)
+	return as.AccountState.SetObjGraph(id, flags, nextHash, objGraph)
+}
+
+func newAccessTrackWorldContext(wc state.WorldContext, r *accessRecorder) state.WorldContext {
+	if wc == nil {
+		return nil
+	}
+	return &accessTrackWorldContext{WorldContext: wc, r: r}
+}
+
+func (t *transition) flushAccesses() error {
+	if t.accesses == nil {
+		return nil
+	}
+	accesses := t.accesses.accesses
+	t.accesses = nil
+	return storeAccessHeights(t.db, t.bi.Height(), accesses)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testAccessPlatform struct{}
+
+func (testAccessPlatform) ToRevision(v int) module.Revision {
+	return module.Revision(v)
+}
+
+func TestAccessTrackWorldContext(t *testing.T) {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	r := newAccessRecorder()
+	wc := newAccessTrackWorldContext(
+		state.NewWorldContext(ws, nil, nil, testAccessPlatform{}), r)
+
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	reader := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	wc.GetAccountState(eoa.ID()).SetBalance(big.NewInt(100))
+	_, err := wc.GetAccountState(score.ID()).SetValue([]byte("key"), []byte("value"))
+	assert.NoError(t, err)
+
+	// reads are counted, and futures report to the same recorder
+	future := wc.GetFuture([]state.LockRequest{
+		{ID: string(reader.ID()), Lock: state.AccountReadLock},
+	})
+	_, err = future.GetAccountState(reader.ID()).GetValue([]byte("key"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{
+		string(eoa.ID()):    false,
+		string(score.ID()):  true,
+		string(reader.ID()): true,
+	}, r.accesses)
+}
+
+func TestStoreAccessHeights(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+
+	start, err := accessIndexStart(dbase)
+	assert.NoError(t, err)
+	assert.Zero(t, start)
+
+	id1 := []byte("account1")
+	id2 := []byte("account2")
+	assert.NoError(t, storeAccessHeights(dbase, 10, map[string]bool{
+		string(id1): true,
+		string(id2): false,
+	}))
+	// storage height is kept if only the account is accessed
+	assert.NoError(t, storeAccessHeights(dbase, 20, map[string]bool{
+		string(id1): false,
+	}))
+
+	ah, err := getAccessHeight(bk, state.AccountKeyOf(id1))
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessHeight{Account: 20, Storage: 10}, ah)
+	ah, err = getAccessHeight(bk, state.AccountKeyOf(id2))
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessHeight{Account: 10, Storage: 0}, ah)
+	ah, err = getAccessHeight(bk, state.AccountKeyOf([]byte("unknown")))
+	assert.NoError(t, err)
+	assert.Equal(t, &AccessHeight{}, ah)
+
+	start, err = accessIndexStart(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), start)
+}
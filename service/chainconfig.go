@@ -0,0 +1,88 @@
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+var serviceConfigNames = []struct {
+	flag int64
+	name string
+}{
+	{state.SysConfigFee, "fee"},
+	{state.SysConfigAudit, "audit"},
+	{state.SysConfigDeployerWhiteList, "deployerWhiteList"},
+	{state.SysConfigScorePackageValidator, "scorePackageValidator"},
+	{state.SysConfigMembership, "membership"},
+	{state.SysConfigFeeSharing, "feeSharing"},
+}
+
+type chainConfig struct {
+	sys      containerdb.BytesStoreState
+	value    int64
+	revision module.Revision
+}
+
+func intMapFrom(sys containerdb.BytesStoreState, typesKey, valuesKey string) map[string]interface{} {
+	values := make(map[string]interface{})
+	types := scoredb.NewArrayDB(sys, typesKey)
+	valueDB := scoredb.NewDictDB(sys, valuesKey, 1)
+	for i := 0; i < types.Size(); i++ {
+		name := types.Get(i).String()
+		if v := valueDB.Get(name); v != nil {
+			values[name] = intconv.FormatBigInt(v.BigInt())
+		} else {
+			values[name] = "0x0"
+		}
+	}
+	return values
+}
+
+// RevisionValue returns the value of the revision.
+func (c *chainConfig) RevisionValue() int64 {
+	return c.value
+}
+
+func (c *chainConfig) ToJSON(height int64, version module.JSONVersion) (interface{}, error) {
+	ret := make(map[string]interface{})
+	ret["height"] = intconv.FormatInt(height)
+	ret["revision"] = intconv.FormatInt(c.value)
+	ret["revisionFlags"] = c.revision.FlagNames()
+
+	sysConfig := scoredb.NewVarDB(c.sys, state.VarServiceConfig).Int64()
+	features := make([]string, 0, len(serviceConfigNames))
+	for _, sc := range serviceConfigNames {
+		if sysConfig&sc.flag != 0 {
+			features = append(features, sc.name)
+		}
+	}
+	ret["serviceConfig"] = intconv.FormatInt(sysConfig)
+	ret["features"] = features
+
+	stepPrice := scoredb.NewVarDB(c.sys, state.VarStepPrice).BigInt()
+	if stepPrice == nil {
+		stepPrice = new(big.Int)
+	}
+	ret["stepPrice"] = intconv.FormatBigInt(stepPrice)
+	ret["stepCosts"] = intMapFrom(c.sys, state.VarStepTypes, state.VarStepCosts)
+	ret["maxStepLimits"] = intMapFrom(c.sys, state.VarStepLimitTypes, state.VarStepLimit)
+	return ret, nil
+}
+
+func (m *manager) GetChainConfig(result []byte) (module.ChainConfig, error) {
+	sys, err := m.getSystemByteStoreState(result)
+	if err != nil {
+		return nil, err
+	}
+	value := scoredb.NewVarDB(sys, state.VarRevision).Int64()
+	return &chainConfig{
+		sys:      sys,
+		value:    value,
+		revision: m.plt.ToRevision(int(value)),
+	}, nil
+}
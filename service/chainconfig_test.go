@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestChainConfig_ToJSON(t *testing.T) {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(7))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarServiceConfig).Set(
+		state.SysConfigFee|state.SysConfigFeeSharing))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(12500000000))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeDefault))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeInput))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepCosts, 1).Set(state.StepTypeDefault, 100000))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepLimitTypes).Put(state.StepLimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepLimit, 1).Set(state.StepLimitTypeInvoke, 2500000000))
+
+	cc := &chainConfig{
+		sys:      sys,
+		value:    7,
+		revision: 7 | module.UseChainID,
+	}
+	jso, err := cc.ToJSON(10, module.JSONVersion3)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"height":        "0xa",
+		"revision":      "0x7",
+		"revisionFlags": []string{"UseChainID"},
+		"serviceConfig": "0x21",
+		"features":      []string{"fee", "feeSharing"},
+		"stepPrice":     "0x2e90edd00",
+		"stepCosts": map[string]interface{}{
+			"default": "0x186a0",
+			"input":   "0x0",
+		},
+		"maxStepLimits": map[string]interface{}{
+			"invoke": "0x9502f900",
+		},
+	}, jso)
+}
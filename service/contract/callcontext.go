@@ -13,6 +13,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/hotspot"
 	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/trace"
@@ -107,7 +108,8 @@ type callContext struct {
 	ioStart *time.Time
 	ioTime  time.Duration
 
-	log *trace.Logger
+	log     *trace.Logger
+	tracker *hotspot.Tracker
 }
 
 func prefixForFrame(id int) string {
@@ -117,7 +119,7 @@ func prefixForFrame(id int) string {
 func NewCallContext(ctx Context, limit *big.Int, isQuery bool) CallContext {
 	traceLogger := ctx.GetTraceLogger(module.EPhaseTransaction)
 	frameLogger := traceLogger.WithTPrefix(prefixForFrame(baseFID))
-	return &callContext{
+	cc := &callContext{
 		Context: ctx,
 		nextEID: initialEID,
 		nextFID: firstFID,
@@ -126,6 +128,23 @@ func NewCallContext(ctx Context, limit *big.Int, isQuery bool) CallContext {
 		waiter: make(chan interface{}, 8),
 		log:    traceLogger,
 	}
+	if p, ok := ctx.GetProperty(PropHotspotProfiler).(*hotspot.Profiler); ok && !isQuery {
+		cc.tracker = p.NewTracker(ctx.BlockHeight())
+	}
+	return cc
+}
+
+// targetOf returns the contract and the method called by the handler.
+func targetOf(handler ContractHandler) (module.Address, string) {
+	var addr module.Address
+	var method string
+	if h, ok := handler.(interface{ Target() module.Address }); ok {
+		addr = h.Target()
+	}
+	if h, ok := handler.(interface{ GetMethodName() string }); ok {
+		method = h.GetMethodName()
+	}
+	return addr, method
 }
 
 func (cc *callContext) QueryMode() bool {
@@ -151,6 +170,9 @@ func (cc *callContext) pushFrame(handler ContractHandler, limit *big.Int) *callF
 	frame.fid = cc.nextFID
 	cc.nextFID += 1
 	cc.frame = frame
+	if cc.tracker != nil {
+		cc.tracker.Enter(targetOf(handler))
+	}
 	return frame
 }
 
@@ -173,6 +195,9 @@ func (cc *callContext) popFrame(success bool) *callFrame {
 		frame.parent.mergeLastEIDMap(frame)
 	}
 	cc.frame = frame.parent
+	if cc.tracker != nil {
+		cc.tracker.Leave()
+	}
 	return frame
 }
 
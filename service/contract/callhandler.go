@@ -166,6 +166,17 @@ func (h *CallHandler) TLogStart() {
 	h.Log.TSystemf("INVOKE start score=%s method=%s", h.To, h.name)
 }
 
+// traceEnter notifies the entry of the contract for debugging. It may be
+// blocked by the debugger, so it's handled as an IO task to exclude the time
+// from the timeout.
+func (h *CallHandler) traceEnter(cc CallContext) {
+	if h.Log.TraceMode() == module.TraceModeDebug {
+		cc.DoIOTask(func() {
+			h.Log.OnContractEnter(h.To, h.name)
+		})
+	}
+}
+
 func (h *CallHandler) TLogDone(status error, steps *big.Int, result *codec.TypedObj) {
 	if h.Log.TraceMode() == module.TraceModeInvoke {
 		if status != nil {
@@ -186,6 +197,7 @@ func (h *CallHandler) TLogDone(status error, steps *big.Int, result *codec.Typed
 
 func (h *CallHandler) ExecuteAsync(cc CallContext) (err error) {
 	h.TLogStart()
+	h.traceEnter(cc)
 	defer func() {
 		if err != nil {
 			if err2 := h.ApplyCallSteps(cc); err2 != nil {
@@ -555,6 +567,7 @@ func (h *CallHandler) OnEvent(addr module.Address, indexed, data [][]byte) error
 }
 
 func (h *CallHandler) OnResult(status error, flag int, steps *big.Int, result *codec.TypedObj) {
+	status = scoreresult.WithAddress(status, h.To)
 	h.TLogDone(status, steps, result)
 	h.cc.OnResult(status, ResultFlag(flag), steps, result, nil)
 }
@@ -649,6 +662,7 @@ func (h *TransferAndCallHandler) Prepare(ctx Context) (state.WorldContext, error
 
 func (h *TransferAndCallHandler) ExecuteAsync(cc CallContext) (err error) {
 	h.TLogStart()
+	h.traceEnter(cc)
 	defer func() {
 		if err != nil {
 			if err2 := h.ApplyCallSteps(cc); err2 != nil {
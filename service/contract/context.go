@@ -16,6 +16,7 @@ import (
 
 const (
 	PropInitialSnapshot = "transition.initialSnapshot"
+	PropHotspotProfiler = "transition.hotspotProfiler"
 )
 
 type Context interface {
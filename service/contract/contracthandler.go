@@ -95,3 +95,8 @@ func (h *CommonHandler) TraceLogger() *trace.Logger {
 func (h *CommonHandler) Logger() log.Logger {
 	return h.Log
 }
+
+// Target returns the address of the contract handled.
+func (h *CommonHandler) Target() module.Address {
+	return h.To
+}
@@ -0,0 +1,257 @@
+package contract
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	DefaultContentSizeLimit = 1024 * 1024
+)
+
+const (
+	keyDeployIssues = "deploy_issues."
+)
+
+// DeployChecker checks the content of the contract before approval.
+// Checkers are used only for reporting. They don't change the result of
+// the deployment, so they may be different on each node.
+type DeployChecker interface {
+	Name() string
+	Check(contentType string, code []byte) []string
+}
+
+type DeployIssue struct {
+	Checker string `json:"checker"`
+	Message string `json:"message"`
+}
+
+// DeployCheckConfig is the configuration of the checkers of the node.
+type DeployCheckConfig struct {
+	// MaxSize is the max size of the content in bytes. Zero means
+	// DefaultContentSizeLimit.
+	MaxSize int `json:"max_size,omitempty"`
+
+	// Banned is the list of patterns banned in entries of the archive,
+	// like names of banned APIs (ex. "java/lang/reflect").
+	Banned []string `json:"banned,omitempty"`
+}
+
+func (c *DeployCheckConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxSize < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidMaxSize(size=%d)", c.MaxSize)
+	}
+	for _, p := range c.Banned {
+		if len(p) == 0 {
+			return errors.IllegalArgumentError.New("EmptyBannedPattern")
+		}
+	}
+	return nil
+}
+
+func (c *DeployCheckConfig) checkers() []DeployChecker {
+	size := DefaultContentSizeLimit
+	if c != nil && c.MaxSize > 0 {
+		size = c.MaxSize
+	}
+	checkers := []DeployChecker{NewSizeChecker(size)}
+	if c != nil && len(c.Banned) > 0 {
+		checkers = append(checkers, NewPatternChecker("banned", c.Banned...))
+	}
+	return checkers
+}
+
+var deployCheckers = struct {
+	lock       sync.Mutex
+	configured []DeployChecker
+	registered []DeployChecker
+}{
+	configured: (*DeployCheckConfig)(nil).checkers(),
+}
+
+// ConfigureDeployCheckers replaces the checkers by the configuration.
+// Checkers added by RegisterDeployChecker are kept.
+func ConfigureDeployCheckers(cfg *DeployCheckConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	deployCheckers.lock.Lock()
+	defer deployCheckers.lock.Unlock()
+
+	deployCheckers.configured = cfg.checkers()
+	return nil
+}
+
+func RegisterDeployChecker(c DeployChecker) {
+	deployCheckers.lock.Lock()
+	defer deployCheckers.lock.Unlock()
+
+	deployCheckers.registered = append(deployCheckers.registered, c)
+}
+
+// CheckDeployContent returns issues found by the checkers.
+func CheckDeployContent(contentType string, code []byte) []DeployIssue {
+	deployCheckers.lock.Lock()
+	checkers := append(append([]DeployChecker{}, deployCheckers.configured...),
+		deployCheckers.registered...)
+	deployCheckers.lock.Unlock()
+
+	issues := []DeployIssue{}
+	for _, c := range checkers {
+		for _, msg := range c.Check(contentType, code) {
+			issues = append(issues, DeployIssue{
+				Checker: c.Name(),
+				Message: msg,
+			})
+		}
+	}
+	return issues
+}
+
+type sizeChecker struct {
+	limit int
+}
+
+func (c *sizeChecker) Name() string {
+	return "size"
+}
+
+func (c *sizeChecker) Check(contentType string, code []byte) []string {
+	if len(code) > c.limit {
+		return []string{
+			fmt.Sprintf("TooLargeContent(size=%d,limit=%d)", len(code), c.limit),
+		}
+	}
+	return nil
+}
+
+// NewSizeChecker returns a checker reporting the content larger than limit.
+func NewSizeChecker(limit int) DeployChecker {
+	return &sizeChecker{limit: limit}
+}
+
+type patternChecker struct {
+	name     string
+	patterns [][]byte
+}
+
+func (c *patternChecker) Name() string {
+	return c.name
+}
+
+func (c *patternChecker) checkBytes(name string, bs []byte) []string {
+	var issues []string
+	for _, p := range c.patterns {
+		if bytes.Contains(bs, p) {
+			issues = append(issues, fmt.Sprintf("BannedPattern(entry=%s,pattern=%s)", name, p))
+		}
+	}
+	return issues
+}
+
+func (c *patternChecker) Check(contentType string, code []byte) []string {
+	if contentType != state.CTAppZip && contentType != state.CTAppJava {
+		return nil
+	}
+	zr, err := zip.NewReader(bytes.NewReader(code), int64(len(code)))
+	if err != nil {
+		return []string{fmt.Sprintf("InvalidArchive(err=%s)", err)}
+	}
+	var issues []string
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("InvalidEntry(entry=%s,err=%s)", f.Name, err))
+			continue
+		}
+		bs, err := io.ReadAll(io.LimitReader(r, DefaultContentSizeLimit))
+		r.Close()
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("InvalidEntry(entry=%s,err=%s)", f.Name, err))
+			continue
+		}
+		issues = append(issues, c.checkBytes(f.Name, bs)...)
+	}
+	return issues
+}
+
+// NewPatternChecker returns a checker reporting entries of the archive
+// including any of the patterns. It can be used to find usages of
+// banned APIs with their names (ex. "java/lang/reflect").
+func NewPatternChecker(name string, patterns ...string) DeployChecker {
+	c := &patternChecker{name: name}
+	for _, p := range patterns {
+		c.patterns = append(c.patterns, []byte(p))
+	}
+	return c
+}
+
+func deployIssuesKey(codeHash []byte) []byte {
+	return append([]byte(keyDeployIssues), codeHash...)
+}
+
+// StoreDeployIssues stores issues of the code found by the checkers.
+// Issues are found by the checkers of the node, so they're stored in
+// the database of the node instead of the world state.
+func StoreDeployIssues(dbase db.Database, codeHash []byte, issues []DeployIssue) error {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	bs, err := codec.BC.MarshalToBytes(issues)
+	if err != nil {
+		return err
+	}
+	return bk.Set(deployIssuesKey(codeHash), bs)
+}
+
+// DeployIssuesOf returns issues of the code stored by StoreDeployIssues.
+// It returns nil if the node didn't check the code yet.
+func DeployIssuesOf(dbase db.Database, codeHash []byte) ([]DeployIssue, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get(deployIssuesKey(codeHash))
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	issues := []DeployIssue{}
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// DeployIssuesFor returns issues of the contract waiting for audit.
+// The code is checked on the first query and the issues are stored, so
+// the checks are kept off the execution of transactions, and the code
+// is not checked again.
+func DeployIssuesFor(dbase db.Database, c state.ContractSnapshot) ([]DeployIssue, error) {
+	if issues, err := DeployIssuesOf(dbase, c.CodeHash()); err != nil || issues != nil {
+		return issues, err
+	}
+	code, err := c.Code()
+	if err != nil {
+		return nil, err
+	}
+	issues := CheckDeployContent(c.ContentType(), code)
+	if err := StoreDeployIssues(dbase, c.CodeHash(), issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
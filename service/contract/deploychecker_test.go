@@ -0,0 +1,131 @@
+package contract
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func makeZip(t *testing.T, entries map[string]string) []byte {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestSizeChecker(t *testing.T) {
+	c := NewSizeChecker(4)
+	assert.Empty(t, c.Check(state.CTAppJava, []byte("1234")))
+	assert.Len(t, c.Check(state.CTAppJava, []byte("12345")), 1)
+}
+
+func TestPatternChecker(t *testing.T) {
+	c := NewPatternChecker("banned", "java/lang/reflect")
+
+	code := makeZip(t, map[string]string{
+		"a/A.class": "uses java/lang/reflect/Method",
+		"a/B.class": "uses java/math/BigInteger",
+	})
+	issues := c.Check(state.CTAppJava, code)
+	assert.Len(t, issues, 1)
+	assert.Contains(t, issues[0], "a/A.class")
+
+	assert.Len(t, c.Check(state.CTAppJava, []byte("invalid")), 1)
+	assert.Empty(t, c.Check(state.CTAppSystem, []byte("invalid")))
+}
+
+func TestCheckDeployContent(t *testing.T) {
+	issues := CheckDeployContent(state.CTAppJava, make([]byte, DefaultContentSizeLimit+1))
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "size", issues[0].Checker)
+
+	assert.Empty(t, CheckDeployContent(state.CTAppJava, []byte("small")))
+}
+
+func TestConfigureDeployCheckers(t *testing.T) {
+	defer ConfigureDeployCheckers(nil)
+
+	assert.Error(t, ConfigureDeployCheckers(&DeployCheckConfig{MaxSize: -1}))
+	assert.Error(t, ConfigureDeployCheckers(&DeployCheckConfig{Banned: []string{""}}))
+
+	assert.NoError(t, ConfigureDeployCheckers(&DeployCheckConfig{
+		MaxSize: 4,
+		Banned:  []string{"java/lang/reflect"},
+	}))
+	code := makeZip(t, map[string]string{
+		"a/A.class": "uses java/lang/reflect/Method",
+	})
+	issues := CheckDeployContent(state.CTAppJava, code)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "size", issues[0].Checker)
+	assert.Equal(t, "banned", issues[1].Checker)
+
+	assert.NoError(t, ConfigureDeployCheckers(nil))
+	assert.Empty(t, CheckDeployContent(state.CTAppJava, code))
+}
+
+func TestStoreDeployIssues(t *testing.T) {
+	dbase := db.NewMapDB()
+	issues, err := DeployIssuesOf(dbase, []byte("code1"))
+	assert.NoError(t, err)
+	assert.Nil(t, issues)
+
+	assert.NoError(t, StoreDeployIssues(dbase, []byte("code1"), []DeployIssue{}))
+	issues, err = DeployIssuesOf(dbase, []byte("code1"))
+	assert.NoError(t, err)
+	assert.NotNil(t, issues)
+	assert.Empty(t, issues)
+
+	stored := []DeployIssue{{Checker: "size", Message: "TooLargeContent"}}
+	assert.NoError(t, StoreDeployIssues(dbase, []byte("code2"), stored))
+	issues, err = DeployIssuesOf(dbase, []byte("code2"))
+	assert.NoError(t, err)
+	assert.Equal(t, stored, issues)
+}
+
+type testContractSnapshot struct {
+	state.ContractSnapshot
+	codeHash []byte
+	code     []byte
+	reads    int
+}
+
+func (c *testContractSnapshot) CodeHash() []byte    { return c.codeHash }
+func (c *testContractSnapshot) ContentType() string { return state.CTAppJava }
+func (c *testContractSnapshot) Code() ([]byte, error) {
+	c.reads += 1
+	return c.code, nil
+}
+
+func TestDeployIssuesFor(t *testing.T) {
+	defer ConfigureDeployCheckers(nil)
+	assert.NoError(t, ConfigureDeployCheckers(&DeployCheckConfig{MaxSize: 4}))
+
+	dbase := db.NewMapDB()
+	c := &testContractSnapshot{codeHash: []byte("code1"), code: []byte("12345")}
+	issues, err := DeployIssuesFor(dbase, c)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, 1, c.reads)
+
+	// issues are stored, so the code is not checked again
+	issues, err = DeployIssuesFor(dbase, c)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, 1, c.reads)
+
+	stored, err := DeployIssuesOf(dbase, c.CodeHash())
+	assert.NoError(t, err)
+	assert.Equal(t, issues, stored)
+}
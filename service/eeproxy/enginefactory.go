@@ -5,19 +5,22 @@ import (
 	"github.com/icon-project/goloop/common/log"
 )
 
-func AllocEngines(l log.Logger, names ...string) ([]Engine, error) {
+func AllocEngines(l log.Logger, limits *Limits, names ...string) ([]Engine, error) {
 	l.Infof("Allocate Engines:%s", names)
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
 	engines := make([]Engine, len(names))
 	for i, name := range names {
 		switch name {
 		case "python":
-			if engine, err := NewPythonEE(l); err != nil {
+			if engine, err := NewPythonEE(l, limits); err != nil {
 				return nil, err
 			} else {
 				engines[i] = engine
 			}
 		case "java":
-			if engine, err := NewJavaEE(l); err != nil {
+			if engine, err := NewJavaEE(l, limits); err != nil {
 				return nil, err
 			} else {
 				engines[i] = engine
@@ -33,6 +33,7 @@ type javaExecutionEngine struct {
 	cmd          *exec.Cmd
 	timer        *time.Timer
 	out          *io.PipeWriter
+	limits       *Limits
 
 	conn   ipc.Connection
 	logger log.Logger
@@ -57,6 +58,10 @@ func (e *javaExecutionEngine) runEE(uid string) error {
 func (e *javaExecutionEngine) start() error {
 	out := e.logger.WriterLevel(log.DebugLevel)
 	e.cmd = e.newCmd(out, out)
+	if err := e.limits.apply(e.cmd); err != nil {
+		out.Close()
+		return err
+	}
 	if err := e.cmd.Start(); err != nil {
 		e.logger.Error("Failed to start JAVA EEManager")
 		out.Close()
@@ -231,7 +236,7 @@ func (e *javaExecutionEngine) newCmd(stdout, stderr io.WriteCloser) *exec.Cmd {
 	return cmd
 }
 
-func NewJavaEE(logger log.Logger) (Engine, error) {
+func NewJavaEE(logger log.Logger, limits *Limits) (Engine, error) {
 	binPath, ok := os.LookupEnv("JAVAEE_BIN")
 	if !ok {
 		return nil, errors.IllegalArgumentError.Errorf("JAVAEE_BIN not set!")
@@ -240,6 +245,10 @@ func NewJavaEE(logger log.Logger) (Engine, error) {
 	e.instances = make(map[string]*javaInstance)
 	e.java = "/bin/sh"
 	e.args = []string{binPath}
+	e.limits = limits
+	if limits != nil && limits.Memory > 0 {
+		e.logger.Warnf("Memory limit is not applied to executors of java (use -Xmx of JAVA_OPTS)")
+	}
 	e.logger = logger.WithFields(log.Fields{log.FieldKeyModule: JavaEE})
 	return &e, nil
 }
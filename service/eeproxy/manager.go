@@ -2,11 +2,14 @@ package eeproxy
 
 import (
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/ipc"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service/scoreresult"
 )
 
 type RequestPriority int
@@ -24,11 +27,14 @@ const (
 	ScaleDownError errors.Code = iota + errorBase
 	InvalidUUIDError
 	InvalidAppTypeError
+	RecycleError
 )
 
 type Manager interface {
 	GetExecutor(pr RequestPriority) *Executor
 	SetInstances(total, tx, query int) error
+	Status() []EngineStatus
+	Recycle() error
 	Loop() error
 	Close() error
 }
@@ -44,6 +50,22 @@ type Engine interface {
 	OnClose(conn ipc.Connection) bool
 }
 
+// exitReporter is implemented by engines reporting the reasons of exits
+// of executors.
+type exitReporter interface {
+	ExitReasonOf(uid string, timeout time.Duration) string
+}
+
+// recycleChecker is implemented by engines replacing executors exceeding
+// the memory ceiling for recycling.
+type recycleChecker interface {
+	NeedRecycle(uid string) bool
+}
+
+// exitWaitTimeout is the time to wait for the exit of the executor to get
+// the reason of the disconnection.
+const exitWaitTimeout = time.Second
+
 type Executor struct {
 	priority RequestPriority
 	manager  *executorManager
@@ -72,11 +94,50 @@ func (e *Executor) Kill() {
 	e.Release()
 }
 
+// EngineStatus is a snapshot of executors of an engine.
+type EngineStatus struct {
+	Type      string `json:"type"`
+	Active    int    `json:"active"`
+	Ready     int    `json:"ready"`
+	Busy      int    `json:"busy"`
+	Recycling int    `json:"recycling"`
+	Restarts  int    `json:"restarts"`
+	Recycled  int    `json:"recycled"`
+	OOMKilled int    `json:"oomKilled"`
+}
+
 type engine struct {
 	engine Engine
 	active int
 	ready  *proxy
 	using  *proxy
+
+	restarts  int
+	recycled  int
+	oomKilled int
+
+	metric *metric.EngineMetric
+}
+
+// needRecycle returns whether the released executor should be replaced
+// with new one.
+func (e *engine) needRecycle(uid string) bool {
+	if rc, ok := e.engine.(recycleChecker); ok {
+		return rc.NeedRecycle(uid)
+	}
+	return false
+}
+
+func (e *engine) recordExecutors() {
+	e.metric.OnExecutors(e.active, countProxies(e.ready), countProxies(e.using))
+}
+
+func countProxies(p *proxy) int {
+	cnt := 0
+	for ; p != nil; p = p.next {
+		cnt += 1
+	}
+	return cnt
 }
 
 type executorState struct {
@@ -96,6 +157,8 @@ type executorManager struct {
 	executorLimit  int
 	executorStates [numberOfPriorities]executorState
 
+	recycling map[string]bool
+
 	log log.Logger
 }
 
@@ -108,8 +171,22 @@ func (em *executorManager) onReady(p *proxy) error {
 		em.log.Warnf("InvalidApplicationType(%s)", p.scoreType)
 		return InvalidAppTypeError.Errorf("InvalidApplicationType:%s", p.scoreType)
 	}
+	defer e.recordExecutors()
 
 	if p.detach() {
+		if em.recycling[p.uid] || e.needRecycle(p.uid) {
+			delete(em.recycling, p.uid)
+			e.active -= 1
+			e.recycled += 1
+			e.metric.OnRecycle()
+			em.log.Infof("Recycle proxy=%s-%s (active=%d)",
+				p.scoreType, p.uid, e.active)
+			if _, err := e.engine.Kill(p.uid); err != nil {
+				em.log.Warnf("Fail to kill proxy=%s-%s err=%+v",
+					p.scoreType, p.uid, err)
+			}
+			return RecycleError.Errorf("Recycling(uid=%s)", p.uid)
+		}
 		if e.active > em.executorLimit {
 			e.active -= 1
 			em.log.Infof("Stop proxy=%s-%s (target=%d,active=%d)",
@@ -159,6 +236,9 @@ func (em *executorManager) OnClose(c ipc.Connection) {
 			if p.conn == c {
 				p.detach()
 				e.active -= 1
+				e.restarts += 1
+				e.metric.OnRestart()
+				e.recordExecutors()
 				return
 			}
 		}
@@ -168,7 +248,11 @@ func (em *executorManager) OnClose(c ipc.Connection) {
 				l.CallAfterUnlock(func() {
 					p.OnClose()
 				})
+				delete(em.recycling, p.uid)
 				e.active -= 1
+				e.restarts += 1
+				e.metric.OnRestart()
+				e.recordExecutors()
 				return
 			}
 		}
@@ -178,6 +262,29 @@ func (em *executorManager) OnClose(c ipc.Connection) {
 	}
 }
 
+// closeStatusOf returns the status of the call interrupted by the
+// disconnection of the executor. If the executor is killed for exceeding
+// the memory limit, then the call fails like timeout, so the transaction
+// fails deterministically instead of being retried.
+func (em *executorManager) closeStatusOf(t, uid string) error {
+	em.lock.Lock()
+	e, ok := em.engines[t]
+	em.lock.Unlock()
+
+	if ok {
+		if er, ok := e.engine.(exitReporter); ok {
+			if reason := er.ExitReasonOf(uid, exitWaitTimeout); reason == ExitOutOfMemory {
+				em.lock.Lock()
+				e.oomKilled += 1
+				em.lock.Unlock()
+				e.metric.OnOOMKilled()
+				return scoreresult.TimeoutError.Errorf("%s(uid=%s)", reason, uid)
+			}
+		}
+	}
+	return errors.ExecutionFailError.New("ProxyIsClosed")
+}
+
 func (em *executorManager) Close() error {
 	if err := em.server.Close(); err != nil {
 		return err
@@ -194,9 +301,11 @@ func (em *executorManager) createExecutorInLock(pr RequestPriority) *Executor {
 		ps[name] = e.ready
 	}
 	for i, p := range ps {
+		e := em.engines[i]
 		p.detach()
-		p.attachTo(&em.engines[i].using)
+		p.attachTo(&e.using)
 		p.reserve()
+		e.recordExecutors()
 	}
 	return &Executor{
 		priority: pr,
@@ -255,6 +364,61 @@ func (em *executorManager) SetInstances(total, tx, query int) error {
 			item.close()
 			e.active -= 1
 		}
+		e.recordExecutors()
+	}
+	return nil
+}
+
+func (em *executorManager) Status() []EngineStatus {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	status := make([]EngineStatus, 0, len(em.engines))
+	for name, e := range em.engines {
+		s := EngineStatus{
+			Type:      name,
+			Active:    e.active,
+			Ready:     countProxies(e.ready),
+			Busy:      countProxies(e.using),
+			Restarts:  e.restarts,
+			Recycled:  e.recycled,
+			OOMKilled: e.oomKilled,
+		}
+		for p := e.using; p != nil; p = p.next {
+			if em.recycling[p.uid] {
+				s.Recycling += 1
+			}
+		}
+		status = append(status, s)
+	}
+	return status
+}
+
+// Recycle replaces all executors with new ones. Idle executors are killed
+// immediately, and busy ones are killed on their release. Engines start
+// new executors to keep the number of instances.
+func (em *executorManager) Recycle() error {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	for _, e := range em.engines {
+		for p := e.using; p != nil; p = p.next {
+			em.recycling[p.uid] = true
+		}
+		for e.ready != nil {
+			p := e.ready
+			p.detach()
+			e.active -= 1
+			e.recycled += 1
+			e.metric.OnRecycle()
+			em.log.Infof("Recycle proxy=%s-%s (active=%d)",
+				p.scoreType, p.uid, e.active)
+			if _, err := e.engine.Kill(p.uid); err != nil {
+				em.log.Warnf("Fail to kill proxy=%s-%s err=%+v",
+					p.scoreType, p.uid, err)
+			}
+		}
+		e.recordExecutors()
 	}
 	return nil
 }
@@ -302,12 +466,16 @@ func NewManager(net, addr string, l log.Logger, engines ...Engine) (Manager, err
 		em.executorStates[i].waiter = sync.NewCond(&em.lock)
 	}
 
+	em.recycling = make(map[string]bool)
 	em.engines = make(map[string]*engine)
 	for _, e := range engines {
 		if err := e.Init(net, addr); err != nil {
 			return nil, err
 		}
-		em.engines[e.Type()] = &engine{engine: e}
+		em.engines[e.Type()] = &engine{
+			engine: e,
+			metric: metric.NewEngineMetric(metric.DefaultMetricContext(), e.Type()),
+		}
 	}
 	return em, nil
 }
@@ -0,0 +1,118 @@
+package eeproxy
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type testEngine struct {
+	killed  []string
+	killErr error
+	recycle map[string]bool
+}
+
+func (e *testEngine) Type() string                                        { return "test" }
+func (e *testEngine) Init(net, addr string) error                         { return nil }
+func (e *testEngine) SetInstances(n int) error                            { return nil }
+func (e *testEngine) OnAttach(uid string) bool                            { return true }
+func (e *testEngine) OnEnd(uid string) bool                               { return true }
+func (e *testEngine) OnConnect(conn ipc.Connection, version uint16) error { return nil }
+func (e *testEngine) OnClose(conn ipc.Connection) bool                    { return false }
+
+func (e *testEngine) Kill(uid string) (bool, error) {
+	e.killed = append(e.killed, uid)
+	return true, e.killErr
+}
+
+func (e *testEngine) NeedRecycle(uid string) bool {
+	return e.recycle[uid]
+}
+
+func newTestManager(e Engine) *executorManager {
+	em := &executorManager{
+		log:       log.New(),
+		recycling: make(map[string]bool),
+		engines: map[string]*engine{
+			e.Type(): {
+				engine: e,
+				metric: metric.NewEngineMetric(metric.DefaultMetricContext(), e.Type()),
+			},
+		},
+	}
+	for i := range em.executorStates {
+		em.executorStates[i].waiter = sync.NewCond(&em.lock)
+	}
+	return em
+}
+
+func newTestProxies(t *testing.T, em *executorManager, uids ...string) []*proxy {
+	ps := make([]*proxy, len(uids))
+	for i, uid := range uids {
+		ps[i] = &proxy{
+			mgr:       em,
+			uid:       uid,
+			scoreType: "test",
+			state:     stateReady,
+		}
+		assert.NoError(t, em.onReady(ps[i]))
+	}
+	return ps
+}
+
+func statusOf(em *executorManager) EngineStatus {
+	return em.Status()[0]
+}
+
+func TestExecutorManager_Recycle(t *testing.T) {
+	te := &testEngine{}
+	em := newTestManager(te)
+	assert.NoError(t, em.SetInstances(3, 1, 1))
+	newTestProxies(t, em, "a", "b", "c")
+	assert.Equal(t, EngineStatus{Type: "test", Active: 3, Ready: 3}, statusOf(em))
+
+	ex := em.GetExecutor(ForTransaction)
+	busy := ex.Get("test").(*proxy).uid
+	assert.Equal(t, EngineStatus{Type: "test", Active: 3, Ready: 2, Busy: 1}, statusOf(em))
+
+	// failure of killing an executor doesn't stop recycling others
+	te.killErr = errors.New("KillFailure")
+	assert.NoError(t, em.Recycle())
+	assert.Len(t, te.killed, 2)
+	assert.NotContains(t, te.killed, busy)
+	assert.Equal(t, EngineStatus{
+		Type: "test", Active: 1, Busy: 1, Recycling: 1, Recycled: 2,
+	}, statusOf(em))
+
+	// the busy executor is killed on its release
+	te.killErr = nil
+	ex.Release()
+	assert.Len(t, te.killed, 3)
+	assert.Contains(t, te.killed, busy)
+	assert.Equal(t, EngineStatus{Type: "test", Recycled: 3}, statusOf(em))
+}
+
+func TestExecutorManager_RecycleOnMemory(t *testing.T) {
+	te := &testEngine{recycle: make(map[string]bool)}
+	em := newTestManager(te)
+	assert.NoError(t, em.SetInstances(2, 1, 1))
+	newTestProxies(t, em, "a", "b")
+
+	ex := em.GetExecutor(ForTransaction)
+	ex.Release()
+	assert.Empty(t, te.killed)
+	assert.Equal(t, EngineStatus{Type: "test", Active: 2, Ready: 2}, statusOf(em))
+
+	ex = em.GetExecutor(ForQuery)
+	uid := ex.Get("test").(*proxy).uid
+	te.recycle[uid] = true
+	ex.Release()
+	assert.Equal(t, []string{uid}, te.killed)
+	assert.Equal(t, EngineStatus{Type: "test", Active: 1, Ready: 1, Recycled: 1}, statusOf(em))
+}
@@ -90,6 +90,7 @@ type Proxy interface {
 type proxyManager interface {
 	onReady(p *proxy) error
 	kill(u string) error
+	closeStatusOf(t, uid string) error
 }
 
 type callFrame struct {
@@ -634,8 +635,8 @@ func (p *proxy) OnClose() {
 
 	if p.frame != nil && p.state == stateReserved {
 		frame := p.frame
-		status := errors.ExecutionFailError.New("ProxyIsClosed")
 		l.CallAfterUnlock(func() {
+			status := p.mgr.closeStatusOf(p.scoreType, p.uid)
 			frame.ctx.OnResult(status, 0, new(big.Int), nil)
 		})
 	}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
@@ -46,6 +47,7 @@ type pythonInstance struct {
 	cmd    *exec.Cmd
 	status InstanceStatus
 	out    io.WriteCloser
+	exited <-chan struct{}
 }
 
 type pythonExecutionEngine struct {
@@ -55,6 +57,8 @@ type pythonExecutionEngine struct {
 	target    int
 	instances map[string]*pythonInstance
 	net, addr string
+	limits    *Limits
+	exits     exitReasons
 	logger    log.Logger
 }
 
@@ -128,6 +132,7 @@ func (e *pythonExecutionEngine) run(is *pythonInstance) {
 	for true {
 		err := is.cmd.Wait()
 		e.logger.Tracef("Wait result uid=%s err=%+v\n", is.uid, err)
+		e.exits.done(is.uid)
 
 		e.lock.Lock()
 		if is.status != instanceOnline {
@@ -164,19 +169,58 @@ func (e *pythonExecutionEngine) init(i *pythonInstance) {
 		log.FieldKeyEID: i.uid,
 	}).WriterLevel(log.DebugLevel)
 	e.instances[i.uid] = i
+	i.exited = e.exits.add(i.uid)
 	i.cmd = e.newCmd(i.uid, i.out, i.out)
 	i.status = instanceStopped
 }
 
 func (e *pythonExecutionEngine) start(i *pythonInstance) error {
 	e.logger.Infof("start instance uid=%s", i.uid)
+	if err := e.limits.apply(i.cmd); err != nil {
+		return err
+	}
 	if err := i.cmd.Start(); err != nil {
+		e.exits.done(i.uid)
 		return err
 	}
 	i.status = instanceStarted
+
+	uid := i.uid
+	e.limits.watchMemory(i.cmd.Process, i.exited, func(size int64) {
+		e.logger.Warnf("Kill instance uid=%s for memory=%d limit=%dMiB",
+			uid, size, e.limits.Memory)
+		e.exits.setReason(uid, ExitOutOfMemory)
+	})
 	return nil
 }
 
+// ExitReasonOf returns the reason of the exit of the instance. It waits
+// for the exit in the timeout.
+func (e *pythonExecutionEngine) ExitReasonOf(uid string, timeout time.Duration) string {
+	return e.exits.wait(uid, timeout)
+}
+
+// NeedRecycle returns whether the resident memory of the instance exceeds
+// the ceiling for recycling.
+func (e *pythonExecutionEngine) NeedRecycle(uid string) bool {
+	if e.limits == nil || e.limits.Recycle <= 0 {
+		return false
+	}
+	e.lock.Lock()
+	is, ok := e.instances[uid]
+	e.lock.Unlock()
+	if !ok || is.cmd.Process == nil {
+		return false
+	}
+	size, err := memoryOf(is.cmd.Process.Pid)
+	if err != nil || size <= e.limits.Recycle<<20 {
+		return false
+	}
+	e.logger.Infof("Recycle instance uid=%s for memory=%d ceiling=%dMiB",
+		uid, size, e.limits.Recycle)
+	return true
+}
+
 func (e *pythonExecutionEngine) term(i *pythonInstance) {
 	_ = i.out.Close()
 	delete(e.instances, i.uid)
@@ -201,9 +245,10 @@ func (e *pythonExecutionEngine) OnClose(conn ipc.Connection) bool {
 	return false
 }
 
-func NewPythonEE(logger log.Logger) (Engine, error) {
+func NewPythonEE(logger log.Logger, limits *Limits) (Engine, error) {
 	var e pythonExecutionEngine
 	e.instances = make(map[string]*pythonInstance)
+	e.limits = limits
 	e.python = "python3"
 	e.args = []string{"-u", "-m", "pyexec"}
 	e.logger = logger.WithFields(log.Fields{log.FieldKeyModule: PythonEE})
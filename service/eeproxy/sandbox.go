@@ -0,0 +1,233 @@
+package eeproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Limits are the resource limits of the processes of execution engines.
+type Limits struct {
+	// Memory is the max resident memory of an executor in MiB. The node
+	// kills the executor exceeding it, and the transaction fails.
+	Memory int64 `json:"memory,omitempty"`
+
+	// Recycle is the resident memory of an executor in MiB, over which the
+	// node replaces the executor with new one on its release. The call
+	// running on it isn't affected.
+	Recycle int64 `json:"recycle,omitempty"`
+
+	// CPUs is the number of CPUs where the processes run.
+	CPUs int `json:"cpus,omitempty"`
+
+	// Files is the max number of open files of a process.
+	Files int64 `json:"files,omitempty"`
+
+	// Seccomp is the path of the seccomp filter (BPF program) for the
+	// processes. It's supported on Linux only.
+	Seccomp string `json:"seccomp,omitempty"`
+}
+
+func (l *Limits) IsEmpty() bool {
+	return l == nil || *l == Limits{}
+}
+
+func (l *Limits) Validate() error {
+	if l.IsEmpty() {
+		return nil
+	}
+	if l.Memory < 0 || l.Recycle < 0 || l.CPUs < 0 || l.Files < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidLimits(%+v)", *l)
+	}
+	if l.CPUs > runtime.NumCPU() {
+		return errors.IllegalArgumentError.Errorf(
+			"TooManyCPUs(cpus=%d,max=%d)", l.CPUs, runtime.NumCPU())
+	}
+	return checkLimits(l)
+}
+
+const sandboxEnv = "GOLOOP_EE_SANDBOX"
+
+func (l *Limits) needLauncher() bool {
+	return l != nil && (l.CPUs > 0 || l.Files > 0 || l.Seccomp != "")
+}
+
+// apply makes the command run under the limits. The command is started by
+// the current executable, which applies the limits to itself, then executes
+// the command (see init).
+func (l *Limits) apply(cmd *exec.Cmd) error {
+	if !l.needLauncher() {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	cmd.Args = append([]string{exe, cmd.Path}, cmd.Args...)
+	cmd.Path = exe
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, sandboxEnv+"="+string(bs))
+	return nil
+}
+
+func init() {
+	if v, ok := os.LookupEnv(sandboxEnv); ok {
+		runSandboxed(v)
+	}
+}
+
+// runSandboxed applies the limits to the current process, and executes the
+// command in the arguments. It never returns.
+func runSandboxed(v string) {
+	err := func() error {
+		var l Limits
+		if err := json.Unmarshal([]byte(v), &l); err != nil {
+			return err
+		}
+		if len(os.Args) < 3 {
+			return errors.IllegalArgumentError.New("NoCommand")
+		}
+		// some limits are applied to the calling thread, which executes
+		// the command.
+		runtime.LockOSThread()
+		if err := applyLimits(&l); err != nil {
+			return err
+		}
+		env := make([]string, 0, len(os.Environ()))
+		for _, e := range os.Environ() {
+			if !strings.HasPrefix(e, sandboxEnv+"=") {
+				env = append(env, e)
+			}
+		}
+		return syscall.Exec(os.Args[1], os.Args[2:], env)
+	}()
+	fmt.Fprintf(os.Stderr, "fail to run execution engine in sandbox err=%+v\n", err)
+	os.Exit(126)
+}
+
+const memoryCheckInterval = 100 * time.Millisecond
+
+// watchMemory kills the process if its resident memory exceeds the limit,
+// until done is closed. onExceed is called before it kills the process.
+func (l *Limits) watchMemory(p *os.Process, done <-chan struct{}, onExceed func(size int64)) {
+	if l == nil || l.Memory <= 0 {
+		return
+	}
+	limit := l.Memory << 20
+	go func() {
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			size, err := memoryOf(p.Pid)
+			if err != nil {
+				return
+			}
+			if size > limit {
+				onExceed(size)
+				_ = p.Kill()
+				return
+			}
+		}
+	}()
+}
+
+// Reasons of exits of executors
+const (
+	ExitOutOfMemory = "OutOfMemory"
+)
+
+const exitReasonTTL = time.Minute
+
+type exitStatus struct {
+	done   chan struct{}
+	reason string
+}
+
+// exitReasons keeps the reasons of exits of executors for a while, so the
+// manager can get the reason after the executor is disconnected.
+type exitReasons struct {
+	lock    sync.Mutex
+	entries map[string]*exitStatus
+}
+
+// add adds the entry for the executor. The returned channel is closed on
+// the exit of the executor.
+func (r *exitReasons) add(uid string) <-chan struct{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.entries == nil {
+		r.entries = make(map[string]*exitStatus)
+	}
+	es := &exitStatus{done: make(chan struct{})}
+	r.entries[uid] = es
+	return es.done
+}
+
+func (r *exitReasons) setReason(uid string, reason string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if es, ok := r.entries[uid]; ok {
+		es.reason = reason
+	}
+}
+
+func (r *exitReasons) done(uid string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if es, ok := r.entries[uid]; ok {
+		select {
+		case <-es.done:
+			return
+		default:
+		}
+		close(es.done)
+		time.AfterFunc(exitReasonTTL, func() {
+			r.lock.Lock()
+			defer r.lock.Unlock()
+			delete(r.entries, uid)
+		})
+	}
+}
+
+// wait returns the reason of the exit of the executor. It returns an empty
+// string if the reason is unknown or it's not exited in the timeout.
+func (r *exitReasons) wait(uid string, timeout time.Duration) string {
+	r.lock.Lock()
+	es, ok := r.entries[uid]
+	r.lock.Unlock()
+	if !ok {
+		return ""
+	}
+
+	select {
+	case <-es.done:
+	case <-time.After(timeout):
+		return ""
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return es.reason
+}
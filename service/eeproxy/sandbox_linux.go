@@ -0,0 +1,100 @@
+//go:build linux
+// +build linux
+
+package eeproxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// maxSeccompFilters is BPF_MAXINSNS of Linux.
+const maxSeccompFilters = 4096
+
+// loadSeccompFilter loads the BPF program in native byte order, which is
+// exported by seccomp_export_bpf() of libseccomp.
+func loadSeccompFilter(p string) ([]unix.SockFilter, error) {
+	bs, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	size := int(unsafe.Sizeof(unix.SockFilter{}))
+	if len(bs) == 0 || len(bs)%size != 0 || len(bs)/size > maxSeccompFilters {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidSeccompFilter(path=%s,size=%d)", p, len(bs))
+	}
+	filters := make([]unix.SockFilter, len(bs)/size)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&filters[0])), len(bs)), bs)
+	return filters, nil
+}
+
+func checkLimits(l *Limits) error {
+	if l.Seccomp != "" {
+		if _, err := loadSeccompFilter(l.Seccomp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyLimits(l *Limits) error {
+	if l.Files > 0 {
+		lim := &syscall.Rlimit{Cur: uint64(l.Files), Max: uint64(l.Files)}
+		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, lim); err != nil {
+			return errors.Wrap(err, "FailToSetFileLimit")
+		}
+	}
+	if l.CPUs > 0 {
+		var current, set unix.CPUSet
+		if err := unix.SchedGetaffinity(0, &current); err != nil {
+			return err
+		}
+		for cpu, n := 0, 0; n < l.CPUs && cpu < len(current)*64; cpu++ {
+			if current.IsSet(cpu) {
+				set.Set(cpu)
+				n++
+			}
+		}
+		if err := unix.SchedSetaffinity(0, &set); err != nil {
+			return errors.Wrap(err, "FailToSetAffinity")
+		}
+	}
+	if l.Seccomp != "" {
+		filters, err := loadSeccompFilter(l.Seccomp)
+		if err != nil {
+			return err
+		}
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return errors.Wrap(err, "FailToSetNoNewPrivs")
+		}
+		prog := &unix.SockFprog{
+			Len:    uint16(len(filters)),
+			Filter: &filters[0],
+		}
+		if err := unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER,
+			uintptr(unsafe.Pointer(prog)), 0, 0); err != nil {
+			return errors.Wrap(err, "FailToSetSeccomp")
+		}
+	}
+	return nil
+}
+
+// memoryOf returns the resident memory of the process in bytes.
+func memoryOf(pid int) (int64, error) {
+	bs, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, err
+	}
+	var size, resident int64
+	if _, err := fmt.Sscan(string(bs), &size, &resident); err != nil {
+		return 0, err
+	}
+	return resident * int64(os.Getpagesize()), nil
+}
@@ -0,0 +1,67 @@
+package eeproxy
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Validate(t *testing.T) {
+	assert.NoError(t, (*Limits)(nil).Validate())
+	assert.NoError(t, (&Limits{Memory: 512, CPUs: 1, Files: 256}).Validate())
+	assert.Error(t, (&Limits{Memory: -1}).Validate())
+	assert.Error(t, (&Limits{Recycle: -1}).Validate())
+	assert.Error(t, (&Limits{CPUs: 1 << 20}).Validate())
+	assert.Error(t, (&Limits{Seccomp: "/not/existing/filter.bpf"}).Validate())
+}
+
+func TestLimits_Apply(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "ulimit -n")
+	assert.NoError(t, (&Limits{Files: 64}).apply(cmd))
+	out, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "64", strings.TrimSpace(string(out)))
+}
+
+func TestLimits_WatchMemory(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "sleep 10")
+	assert.NoError(t, cmd.Start())
+
+	var size int64
+	for i := 0; i < 50 && size <= 1<<20; i++ {
+		time.Sleep(10 * time.Millisecond)
+		size, _ = memoryOf(cmd.Process.Pid)
+	}
+	if size <= 1<<20 {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		t.Skipf("resident memory of the shell is too small size=%d", size)
+	}
+
+	var r exitReasons
+	done := r.add("test")
+	limits := &Limits{Memory: 1}
+	limits.watchMemory(cmd.Process, done, func(size int64) {
+		r.setReason("test", ExitOutOfMemory)
+	})
+	go func() {
+		_ = cmd.Wait()
+		r.done("test")
+	}()
+	assert.Equal(t, ExitOutOfMemory, r.wait("test", 5*time.Second))
+}
+
+func TestExitReasons_Wait(t *testing.T) {
+	var r exitReasons
+	assert.Equal(t, "", r.wait("unknown", time.Second))
+
+	r.add("test")
+	assert.Equal(t, "", r.wait("test", 10*time.Millisecond))
+	r.setReason("test", ExitOutOfMemory)
+	r.done("test")
+	r.done("test")
+	assert.Equal(t, ExitOutOfMemory, r.wait("test", time.Second))
+}
@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+package eeproxy
+
+import (
+	"syscall"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func checkLimits(l *Limits) error {
+	if l.Memory > 0 || l.Recycle > 0 || l.CPUs > 0 || l.Seccomp != "" {
+		return errors.UnsupportedError.Errorf("UnsupportedLimits(%+v)", *l)
+	}
+	return nil
+}
+
+func applyLimits(l *Limits) error {
+	if l.Files > 0 {
+		lim := &syscall.Rlimit{Cur: uint64(l.Files), Max: uint64(l.Files)}
+		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, lim); err != nil {
+			return errors.Wrap(err, "FailToSetFileLimit")
+		}
+	}
+	return nil
+}
+
+func memoryOf(pid int) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -1,6 +1,10 @@
 package service
 
-import "github.com/icon-project/goloop/common/errors"
+import (
+	"fmt"
+
+	"github.com/icon-project/goloop/common/errors"
+)
 
 const (
 	DuplicateTransactionError errors.Code = iota + errors.CodeService
@@ -15,6 +19,8 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	TransactionPoolPausedError
+	StateUnavailableError
 )
 
 var (
@@ -24,4 +30,57 @@ var (
 	ErrTransitionInterrupted   = errors.NewBase(TransitionInterruptedError, "TransitionInterrupted")
 	ErrInvalidTransaction      = errors.NewBase(InvalidTransactionError, "InvalidTransaction")
 	ErrCommittedTransaction    = errors.NewBase(CommittedTransactionError, "CommittedTransaction")
+	ErrTransactionPoolPaused   = errors.NewBase(TransactionPoolPausedError, "TransactionPoolPaused")
+)
+
+// Status of the existing transaction for the duplicate transaction.
+const (
+	DuplicatePending   = "pending"
+	DuplicateCommitted = "committed"
 )
+
+type withDuplicate struct {
+	error
+	id     []byte
+	status string
+}
+
+func (e *withDuplicate) Unwrap() error {
+	return e.error
+}
+
+func (e *withDuplicate) Format(f fmt.State, c rune) {
+	if fe, ok := e.error.(fmt.Formatter); ok {
+		fe.Format(f, c)
+	} else {
+		fmt.Fprint(f, e.error.Error())
+	}
+}
+
+// withDuplicateOf attaches the hash and the status of the existing
+// transaction if the error is for the duplicate transaction.
+func withDuplicateOf(e error, id []byte) error {
+	switch {
+	case DuplicateTransactionError.Equals(e):
+		return &withDuplicate{e, id, DuplicatePending}
+	case CommittedTransactionError.Equals(e):
+		return &withDuplicate{e, id, DuplicateCommitted}
+	default:
+		return e
+	}
+}
+
+// DuplicateOf returns the hash and the status of the existing transaction
+// if the transaction is rejected for the duplicate, so that clients can
+// wait for the existing one.
+func DuplicateOf(e error) ([]byte, string, bool) {
+	we := errors.FindCause(e, func(err error) bool {
+		_, ok := err.(*withDuplicate)
+		return ok
+	})
+	if we != nil {
+		d := we.(*withDuplicate)
+		return d.id, d.status, true
+	}
+	return nil, "", false
+}
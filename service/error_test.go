@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestDuplicateOf(t *testing.T) {
+	id := []byte{1, 2, 3}
+
+	err := withDuplicateOf(ErrDuplicateTransaction, id)
+	assert.True(t, DuplicateTransactionError.Equals(err))
+	hash, status, ok := DuplicateOf(errors.Wrap(err, "wrapped"))
+	assert.True(t, ok)
+	assert.Equal(t, id, hash)
+	assert.Equal(t, DuplicatePending, status)
+
+	err = withDuplicateOf(CommittedTransactionError.Errorf("AlreadyCommitted(height=%d)", 10), id)
+	assert.True(t, CommittedTransactionError.Equals(err))
+	hash, status, ok = DuplicateOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, id, hash)
+	assert.Equal(t, DuplicateCommitted, status)
+
+	err = withDuplicateOf(ErrTransactionPoolOverFlow, id)
+	assert.Equal(t, ErrTransactionPoolOverFlow, err)
+	_, _, ok = DuplicateOf(err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// PoolStatus is the status of the transaction pool.
+type PoolStatus struct {
+	// Size is the capacity of the pool.
+	Size int
+	// Used is the number of transactions in the pool.
+	Used int
+}
+
+// GetPoolStatus returns the status of the pool for the group.
+func GetPoolStatus(sm module.ServiceManager, g module.TransactionGroup) (*PoolStatus, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoPoolStatus(sm=%T)", sm)
+	}
+	tp := mgr.tm.getTxPool(g)
+	return &PoolStatus{
+		Size: tp.Size(),
+		Used: tp.Used(),
+	}, nil
+}
+
+// GetStepPrice returns the step price in the state of the result.
+func GetStepPrice(sm module.ServiceManager, result []byte) (*big.Int, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoStepPrice(sm=%T)", sm)
+	}
+	sys, err := mgr.getSystemByteStoreState(result)
+	if err != nil {
+		return nil, err
+	}
+	price := scoredb.NewVarDB(sys, state.VarStepPrice).BigInt()
+	if price == nil {
+		price = new(big.Int)
+	}
+	return price, nil
+}
+
+// ExecuteTransactions executes the transactions in order on the state of the
+// result for estimation. Each transaction is executed with the changes of
+// the previous ones, and it stops after the first transaction failed. So the
+// last receipt may have the failure.
+func ExecuteTransactions(sm module.ServiceManager, result []byte, vh []byte, jss [][]byte, bi module.BlockInfo) ([]module.Receipt, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoBatchExecution(sm=%T)", sm)
+	}
+	return mgr.executeTransactions(result, vh, jss, bi)
+}
+
+// PredictInclusion returns the number of blocks until a new transaction
+// is included, with the number of transactions in the pool (depth), the
+// maximum number of transactions in a block (capacity) and the average
+// number of transactions of the recent blocks.
+//
+// Transactions in the pool are proposed in order of arrival. If the recent
+// blocks include fewer transactions than the capacity, then the average is
+// used as the throughput for the backlog, because the blocks are limited
+// by other than the count (e.g. size or execution time).
+func PredictInclusion(depth, capacity int, average float64) int64 {
+	if capacity <= 0 {
+		capacity = configDefaultMaxTxCount
+	}
+	if depth < capacity {
+		return 1
+	}
+	throughput := float64(capacity)
+	if average >= 1 && average < throughput {
+		throughput = average
+	}
+	return int64(math.Ceil(float64(depth+1) / throughput))
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictInclusion(t *testing.T) {
+	cases := []struct {
+		name     string
+		depth    int
+		capacity int
+		average  float64
+		blocks   int64
+	}{
+		{"Empty", 0, 1500, 0, 1},
+		{"FitInNext", 1499, 1500, 100, 1},
+		{"FullBlocks", 3000, 1500, 1500, 3},
+		{"LimitedBlocks", 2000, 1500, 1000, 3},
+		{"IdleBlocks", 2000, 1500, 0, 2},
+		{"DefaultCapacity", 1500, 0, 0, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.blocks, PredictInclusion(c.depth, c.capacity, c.average))
+		})
+	}
+}
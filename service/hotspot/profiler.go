@@ -0,0 +1,293 @@
+package hotspot
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	DefaultInterval = time.Millisecond
+	DefaultBlocks   = 100
+
+	// configMetricContracts is the number of the hottest contracts of
+	// a block recorded in the metric.
+	configMetricContracts = 5
+)
+
+type key struct {
+	addr   string
+	method string
+}
+
+// Entry is the wall time sampled while the method of the contract was
+// being executed.
+type Entry struct {
+	Address  module.Address
+	Method   string
+	Samples  int64
+	Duration time.Duration
+}
+
+type block struct {
+	height   int64
+	samples  int64
+	duration time.Duration
+	entries  map[key]*Entry
+}
+
+func (b *block) add(addr module.Address, method string, d time.Duration) {
+	k := key{addr.String(), method}
+	e, ok := b.entries[k]
+	if !ok {
+		e = &Entry{Address: addr, Method: method}
+		b.entries[k] = e
+	}
+	e.Samples += 1
+	e.Duration += d
+	b.samples += 1
+	b.duration += d
+}
+
+// Summary is the sampled wall time of contracts in the blocks.
+type Summary struct {
+	From, To int64
+	Blocks   int
+	Samples  int64
+	Duration time.Duration
+
+	// Entries are sorted by duration in descending order.
+	Entries []*Entry
+}
+
+// Profiler samples the contract and the method being executed by each
+// transaction periodically, and it keeps the wall time consumed by them
+// for recent blocks.
+type Profiler struct {
+	interval time.Duration
+	size     int
+	metric   *metric.HotspotMetric
+
+	lock     sync.Mutex
+	trackers map[*Tracker]struct{}
+	running  bool
+	blocks   map[int64]*block
+	last     int64
+}
+
+// NewProfiler returns a profiler keeping samples of the blocks. Samples
+// are taken only while contracts are being executed.
+func NewProfiler(ctx context.Context, interval time.Duration, blocks int) *Profiler {
+	return &Profiler{
+		interval: interval,
+		size:     blocks,
+		metric:   metric.NewHotspotMetric(ctx),
+		trackers: make(map[*Tracker]struct{}),
+		blocks:   make(map[int64]*block),
+	}
+}
+
+// NewTracker returns a tracker of frames of a transaction in the block.
+func (p *Profiler) NewTracker(height int64) *Tracker {
+	return &Tracker{p: p, height: height}
+}
+
+func (p *Profiler) track(t *Tracker) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.trackers[t] = struct{}{}
+	if !p.running {
+		p.running = true
+		go p.run()
+	}
+}
+
+func (p *Profiler) untrack(t *Tracker) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.trackers, t)
+}
+
+// run samples trackers until no contracts are being executed.
+func (p *Profiler) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for now := range ticker.C {
+		if !p.sample(now.Sub(last)) {
+			return
+		}
+		last = now
+	}
+}
+
+func (p *Profiler) sample(d time.Duration) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.trackers) == 0 {
+		p.running = false
+		return false
+	}
+	for t := range p.trackers {
+		addr, method, ok := t.top()
+		if !ok {
+			continue
+		}
+		p.blockOf(t.height).add(addr, method, d)
+	}
+	return true
+}
+
+func (p *Profiler) blockOf(height int64) *block {
+	if b, ok := p.blocks[height]; ok {
+		return b
+	}
+	b := &block{height: height, entries: make(map[key]*Entry)}
+	p.blocks[height] = b
+	if height > p.last {
+		// blocks are executed in order, so the last one is done.
+		if lb, ok := p.blocks[p.last]; ok {
+			p.report(lb)
+		}
+		p.last = height
+		for h := range p.blocks {
+			if h <= p.last-int64(p.size) {
+				delete(p.blocks, h)
+			}
+		}
+	}
+	return b
+}
+
+func (p *Profiler) report(b *block) {
+	p.metric.OnBlock(b.duration)
+	for i, e := range sortEntries(b.entries) {
+		if i >= configMetricContracts {
+			break
+		}
+		p.metric.OnContract(e.Address.String(), e.Duration)
+	}
+}
+
+func sortEntries(entries map[key]*Entry) []*Entry {
+	es := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].Duration != es[j].Duration {
+			return es[i].Duration > es[j].Duration
+		}
+		if es[i].Address.String() != es[j].Address.String() {
+			return es[i].Address.String() < es[j].Address.String()
+		}
+		return es[i].Method < es[j].Method
+	})
+	return es
+}
+
+// LastHeight returns the height of the last block having samples.
+func (p *Profiler) LastHeight() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.last
+}
+
+// Top returns the contracts and the methods consuming the most wall time
+// in the blocks between the heights inclusively. Samples of the same method
+// of the same contract are merged. It returns at most limit entries.
+func (p *Profiler) Top(from, to int64, limit int) *Summary {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s := &Summary{From: from, To: to}
+	entries := make(map[key]*Entry)
+	for h := from; h <= to; h++ {
+		b, ok := p.blocks[h]
+		if !ok {
+			continue
+		}
+		s.Blocks += 1
+		s.Samples += b.samples
+		s.Duration += b.duration
+		for k, be := range b.entries {
+			e, ok := entries[k]
+			if !ok {
+				e = &Entry{Address: be.Address, Method: be.Method}
+				entries[k] = e
+			}
+			e.Samples += be.Samples
+			e.Duration += be.Duration
+		}
+	}
+	s.Entries = sortEntries(entries)
+	if len(s.Entries) > limit {
+		s.Entries = s.Entries[:limit]
+	}
+	return s
+}
+
+// Tracker tracks frames of a transaction. The contract and the method of
+// the top frame is sampled by the profiler.
+type Tracker struct {
+	p      *Profiler
+	height int64
+
+	lock   sync.Mutex
+	frames []frame
+}
+
+type frame struct {
+	addr   module.Address
+	method string
+}
+
+func (t *Tracker) top() (module.Address, string, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	n := len(t.frames)
+	if n == 0 {
+		return nil, "", false
+	}
+	f := t.frames[n-1]
+	return f.addr, f.method, f.addr != nil
+}
+
+// Enter is called when the method of the contract is called. Frames
+// without the contract are not sampled.
+func (t *Tracker) Enter(addr module.Address, method string) {
+	t.lock.Lock()
+	t.frames = append(t.frames, frame{addr, method})
+	first := len(t.frames) == 1
+	t.lock.Unlock()
+
+	if first {
+		t.p.track(t)
+	}
+}
+
+// Leave is called when the last called method returns.
+func (t *Tracker) Leave() {
+	t.lock.Lock()
+	n := len(t.frames)
+	if n == 0 {
+		t.lock.Unlock()
+		return
+	}
+	t.frames = t.frames[:n-1]
+	t.lock.Unlock()
+
+	if n == 1 {
+		t.p.untrack(t)
+	}
+}
@@ -0,0 +1,64 @@
+package hotspot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestProfiler_Top(t *testing.T) {
+	p := NewProfiler(context.Background(), time.Millisecond, 2)
+	addr1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	tr := p.NewTracker(10)
+	tr.Enter(addr1, "transfer")
+	time.Sleep(20 * time.Millisecond)
+	tr.Enter(addr2, "balanceOf")
+	time.Sleep(50 * time.Millisecond)
+	tr.Leave()
+	tr.Leave()
+
+	// frames without the contract are not sampled
+	tr = p.NewTracker(11)
+	tr.Enter(nil, "")
+	time.Sleep(10 * time.Millisecond)
+	tr.Leave()
+
+	s := p.Top(10, 10, 10)
+	assert.Equal(t, 1, s.Blocks)
+	assert.Len(t, s.Entries, 2)
+	assert.True(t, addr2.Equal(s.Entries[0].Address))
+	assert.Equal(t, "balanceOf", s.Entries[0].Method)
+	assert.True(t, s.Entries[0].Duration > s.Entries[1].Duration)
+	assert.Equal(t, s.Duration, s.Entries[0].Duration+s.Entries[1].Duration)
+
+	s = p.Top(10, 10, 1)
+	assert.Len(t, s.Entries, 1)
+
+	s = p.Top(11, 11, 10)
+	assert.Equal(t, 0, s.Blocks)
+	assert.Len(t, s.Entries, 0)
+
+	tr = p.NewTracker(12)
+	tr.Enter(addr1, "transfer")
+	time.Sleep(10 * time.Millisecond)
+	tr.Leave()
+	assert.EqualValues(t, 12, p.LastHeight())
+
+	// samples of old blocks are removed
+	s = p.Top(10, 12, 10)
+	assert.Equal(t, 1, s.Blocks)
+	assert.Len(t, s.Entries, 1)
+
+	// the sampler stops without contracts being executed
+	time.Sleep(10 * time.Millisecond)
+	p.lock.Lock()
+	running := p.running
+	p.lock.Unlock()
+	assert.False(t, running)
+}
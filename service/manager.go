@@ -10,6 +10,7 @@ import (
 	"github.com/icon-project/goloop/btp"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/service/scoreresult"
@@ -27,6 +28,7 @@ import (
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/hotspot"
 	"github.com/icon-project/goloop/service/state"
 )
 
@@ -53,10 +55,17 @@ type manager struct {
 	trc       *transitionResultCache
 	tsc       *TxTimestampChecker
 	syncer    *ssync.Manager
+	pe        *PendingExecutions
+	hsp       *hotspot.Profiler
+	sla       *StepLimitAdvisor
+	lane      priorityLane
 
 	log log.Logger
 
 	skipTxPatch atomic.Value
+
+	// finalized is the *finalizedResult of the last finalized transition.
+	finalized atomic.Value
 }
 
 func NewManager(chain module.Chain, nm module.NetworkManager,
@@ -74,6 +83,7 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		return nil, err
 	}
 	tsc := NewTimestampChecker()
+	tsc.SetWindow(chain.TxTimestampWindow())
 	tim, err := NewTXIDManager(chain.Database(), tsc)
 	if err != nil {
 		logger.Warnf("FAIL to create TXIDManager : %v\n", err)
@@ -81,6 +91,7 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 	}
 	pTxPool := NewTransactionPool(module.TransactionGroupPatch, chain.PatchTxPoolSize(), tim, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal, chain.NormalTxPoolSize(), tim, nMetric, logger)
+	nTxPool.SetLocalFirst(chain.LocalTxFirst())
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, tim, logger)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), plt, logger)
 
@@ -101,7 +112,19 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		log: logger,
 		tsc: tsc,
 		tim: tim,
-	}
+		pe:  NewPendingExecutions(),
+		hsp: hotspot.NewProfiler(chain.MetricContext(),
+			hotspot.DefaultInterval, hotspot.DefaultBlocks),
+		sla: NewStepLimitAdvisor(chain.MetricContext(), chain.TransactionTimeout),
+	}
+	tm.SetSignatureChecker(mgr.checkTxByContract)
+	tm.SetPriorityChecker(chain.PriorityTxPoolSize(), mgr.lane.isPriority)
+	tm.SetAdmissionChecker(func() error {
+		if module.IsComponentPaused(chain, module.ComponentTxPool) {
+			return ErrTransactionPoolPaused
+		}
+		return nil
+	})
 	if nm != nil {
 		mgr.txReactor = NewTransactionReactor(nm, tm)
 	}
@@ -115,6 +138,11 @@ func (m *manager) Start() {
 	}
 }
 
+// SetSyncServing starts or stops serving the state data to the peers.
+func (m *manager) SetSyncServing(serving bool) {
+	m.syncer.SetServing(serving)
+}
+
 func (m *manager) Term() {
 	if m.txReactor != nil {
 		m.txReactor.Stop()
@@ -126,6 +154,16 @@ func (m *manager) Term() {
 	m.db = nil
 }
 
+// PendingExecutions returns notifier of executions of non-finalized blocks.
+func (m *manager) PendingExecutions() *PendingExecutions {
+	return m.pe
+}
+
+// TxFeed returns notifier of transactions accepted into the pool.
+func (m *manager) TxFeed() *TxFeed {
+	return m.tm.Feed()
+}
+
 // ProposeTransition proposes a Transition following the parent Transition.
 // parent transition should have a valid result.
 // Returned Transition always passes validation.
@@ -167,7 +205,7 @@ func (m *manager) ProposeTransition(parent module.Transition, bi module.BlockInf
 func (m *manager) CreateInitialTransition(result []byte,
 	valList module.ValidatorList,
 ) (module.Transition, error) {
-	return newInitTransition(m.db, result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim)
+	return newInitTransition(m.db, result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim, m.pe, m.hsp, m.sla)
 }
 
 // CreateTransition creates a Transition following parent Transition with txs
@@ -290,6 +328,8 @@ func (m *manager) Finalize(t module.Transition, opt int) error {
 				return err
 			}
 			m.tm.NotifyFinalized(tst.patchTransactions, tst.patchReceipts, tst.normalTransactions, tst.normalReceipts)
+			m.finalized.Store(&finalizedResult{tst.Result(), tst.bi})
+			m.lane.update(tst.worldSnapshot)
 			now := time.Now()
 			m.patchMetric.OnFinalize(tst.patchTransactions.Hash(), now)
 			m.normalMetric.OnFinalize(tst.normalTransactions.Hash(), now)
@@ -397,6 +437,7 @@ func (m *manager) SendTransactionAndWait(result []byte, height int64, txi interf
 		}
 	}
 	chn, err := m.tm.AddAndWait(newTx)
+	m.onAddTxError(err)
 	if err == nil {
 		if err := m.txReactor.PropagateTransaction(newTx); err != nil {
 			if !network.NotAvailableError.Equals(err) {
@@ -410,6 +451,14 @@ func (m *manager) SendTransactionAndWait(result []byte, height int64, txi interf
 	return nil, nil, err
 }
 
+// onAddTxError records the transaction rejected for its future timestamp,
+// which is usually caused by the clock skew of the node.
+func (m *manager) onAddTxError(err error) {
+	if FutureTransactionError.Equals(err) {
+		m.normalMetric.OnFutureTx()
+	}
+}
+
 func (m *manager) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	return m.tm.WaitResult(id)
 }
@@ -431,6 +480,28 @@ func (m *manager) preValidateTx(result []byte, height int64, tx transaction.Tran
 	return tx.PreValidate(&worldContextWrapper{wc, height}, false)
 }
 
+func (m *manager) ValidateTransaction(result []byte, height int64, txi interface{}) ([]error, error) {
+	newTx, err := newTransaction(txi)
+	if err != nil {
+		return []error{err}, nil
+	}
+	errs := m.tm.VerifyTxAll(newTx)
+	if err := m.tm.CheckTx(newTx); err != nil {
+		errs = append(errs, err)
+	}
+	wc, err := m.trc.GetWorldContext(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	wcw := &worldContextWrapper{wc, height}
+	if v, ok := transaction.Unwrap(newTx).(transaction.Validator); ok {
+		errs = append(errs, v.PreValidateAll(wcw)...)
+	} else if err := newTx.PreValidate(wcw, false); err != nil {
+		errs = append(errs, err)
+	}
+	return errs, nil
+}
+
 func (m *manager) SendTransaction(result []byte, height int64, txi interface{}) ([]byte, error) {
 	newTx, err := newTransaction(txi)
 	if err != nil {
@@ -445,7 +516,8 @@ func (m *manager) SendTransaction(result []byte, height int64, txi interface{})
 		}
 	}
 	if err := m.tm.Add(newTx, true, true); err != nil {
-		return nil, err
+		m.onAddTxError(err)
+		return nil, withDuplicateOf(err, newTx.ID())
 	}
 
 	if err := m.txReactor.PropagateTransaction(newTx); err != nil {
@@ -460,9 +532,10 @@ func (m *manager) Call(resultHash []byte,
 	vl module.ValidatorList, js []byte, bi module.BlockInfo,
 ) (interface{}, error) {
 	type callJSON struct {
-		To       common.Address  `json:"to"`
-		DataType *string         `json:"dataType"`
-		Data     json.RawMessage `json:"data"`
+		To        common.Address  `json:"to"`
+		DataType  *string         `json:"dataType"`
+		Data      json.RawMessage `json:"data"`
+		StepLimit *common.HexInt  `json:"stepLimit"`
 	}
 
 	var jso callJSON
@@ -473,6 +546,9 @@ func (m *manager) Call(resultHash []byte,
 		return nil, InvalidQueryError.New("InvalidDataType")
 	}
 
+	if err := m.checkState(resultHash); err != nil {
+		return nil, err
+	}
 	var wc state.WorldContext
 	if wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash()); err == nil {
 		ws := state.NewReadOnlyWorldState(wss)
@@ -481,10 +557,25 @@ func (m *manager) Call(resultHash []byte,
 		return nil, err
 	}
 
-	qh, err := NewQueryHandler(m.cm, &jso.To, jso.Data)
+	var stepLimit *big.Int
+	if jso.StepLimit != nil {
+		if jso.StepLimit.Sign() < 0 {
+			return nil, InvalidQueryError.Errorf("InvalidStepLimit(%s)", jso.StepLimit)
+		}
+		stepLimit = jso.StepLimit.Value()
+	}
+	qh, err := NewQueryHandler(m.cm, &jso.To, jso.Data, stepLimit)
 	if err != nil {
 		return nil, err
 	}
+
+	quota := m.chain.Quota()
+	release := quota.AcquireExecutor()
+	defer release()
+	start := time.Now()
+	defer func() {
+		quota.OnExecution(time.Since(start))
+	}()
 	return qh.Query(contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery))
 }
 
@@ -510,6 +601,24 @@ func (m *manager) GetBalance(result []byte, addr module.Address) (*big.Int, erro
 	if err != nil {
 		return nil, err
 	}
+	return balanceOf(wss, addr)
+}
+
+func (m *manager) GetBalances(result []byte, addrs []module.Address) ([]*big.Int, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		if balances[i], err = balanceOf(wss, addr); err != nil {
+			return nil, err
+		}
+	}
+	return balances, nil
+}
+
+func balanceOf(wss state.WorldSnapshot, addr module.Address) (*big.Int, error) {
 	ass := wss.GetAccountSnapshot(addr.ID())
 	if (ass != nil && ass.IsContract()) != addr.IsContract() {
 		return nil, errors.IllegalArgumentError.Errorf(
@@ -582,7 +691,8 @@ func (m *manager) GetAPIInfo(result []byte, addr module.Address) (module.APIInfo
 }
 
 type scoreStatus struct {
-	ass state.AccountSnapshot
+	ass   state.AccountSnapshot
+	dbase db.Database
 }
 
 func contractToJSON(c state.ContractSnapshot, version module.JSONVersion) interface{} {
@@ -619,6 +729,20 @@ func boolToJSON(v bool) interface{} {
 	}
 }
 
+func (s *scoreStatus) CodeHash() []byte {
+	if c := s.ass.Contract(); c != nil {
+		return c.CodeHash()
+	}
+	return nil
+}
+
+func (s *scoreStatus) PendingTxHash() []byte {
+	if c := s.ass.NextContract(); c != nil && c.Status() == state.CSPending {
+		return c.DeployTxHash()
+	}
+	return nil
+}
+
 func (s *scoreStatus) ToJSON(height int64, version module.JSONVersion) (interface{}, error) {
 	ret := make(map[string]interface{})
 	if owner := s.ass.ContractOwner(); owner != nil {
@@ -628,7 +752,15 @@ func (s *scoreStatus) ToJSON(height int64, version module.JSONVersion) (interfac
 		ret["current"] = contractToJSON(c, version)
 	}
 	if c := s.ass.NextContract(); c != nil {
-		ret["next"] = contractToJSON(c, version)
+		next := contractToJSON(c, version).(map[string]interface{})
+		if c.Status() == state.CSPending {
+			if issues, err := contract.DeployIssuesFor(s.dbase, c); err != nil {
+				return nil, err
+			} else {
+				next["checks"] = issues
+			}
+		}
+		ret["next"] = next
 	}
 	dc := dummyDepositContext{height: height}
 	if di, err := s.ass.GetDepositInfo(dc, version); err != nil {
@@ -661,7 +793,68 @@ func (m *manager) GetSCOREStatus(result []byte, addr module.Address) (module.SCO
 		return nil, errors.NotFoundError.Errorf("NoValidContract(addr=%s)", addr)
 	}
 	return &scoreStatus{
+		ass:   ass,
+		dbase: m.db,
+	}, nil
+}
+
+type feeSharingStatus struct {
+	ass state.AccountSnapshot
+	sys containerdb.BytesStoreState
+}
+
+// ToJSON returns the status at the height. Expiration of each deposit is
+// included in deposits. Fee proportion isn't included, because it's not
+// stored in the state but set by the contract on each call.
+func (s *feeSharingStatus) ToJSON(height int64, version module.JSONVersion) (interface{}, error) {
+	ret := make(map[string]interface{})
+	sysConfig := scoredb.NewVarDB(s.sys, state.VarServiceConfig).Int64()
+	ret["feeSharingEnabled"] = boolToJSON((sysConfig & state.SysConfigFeeSharing) != 0)
+	stepPrice := scoredb.NewVarDB(s.sys, state.VarStepPrice).BigInt()
+	if stepPrice == nil {
+		stepPrice = new(big.Int)
+	}
+	ret["stepPrice"] = intconv.FormatBigInt(stepPrice)
+	ret["depositTerm"] = intconv.FormatInt(scoredb.NewVarDB(s.sys, state.VarDepositTerm).Int64())
+	ret["depositIssueRate"] = intconv.FormatBigInt(state.DepositIssueRateFrom(s.sys))
+	ret["useSystemDeposit"] = boolToJSON(s.ass.UseSystemDeposit())
+
+	dc := dummyDepositContext{height: height}
+	di, err := s.ass.GetDepositInfo(dc, version)
+	if err != nil {
+		return nil, scoreresult.New(module.StatusUnknownFailure, "FailOnDepositInfo")
+	}
+	if di != nil {
+		for k, v := range di {
+			ret[k] = v
+		}
+	} else {
+		ret["deposits"] = []interface{}{}
+		ret["availableVirtualStep"] = "0x0"
+		ret["availableDeposit"] = "0x0"
+	}
+	return ret, nil
+}
+
+func (m *manager) GetFeeSharingStatus(result []byte, addr module.Address) (module.FeeSharingStatus, error) {
+	if !addr.IsContract() {
+		return nil, errors.IllegalArgumentError.Errorf("Given Address(%s) isn't contract", addr)
+	}
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	ass := wss.GetAccountSnapshot(addr.ID())
+	if ass == nil || !ass.IsContract() {
+		return nil, errors.NotFoundError.Errorf("NoValidContract(addr=%s)", addr)
+	}
+	sys := containerdb.EmptyBytesStoreState
+	if sass := wss.GetAccountSnapshot(state.SystemID); sass != nil {
+		sys = scoredb.NewStateStoreWith(sass)
+	}
+	return &feeSharingStatus{
 		ass: ass,
+		sys: sys,
 	}, nil
 }
 
@@ -738,6 +931,29 @@ func (m *manager) BTPNetworkTypeFromResult(result []byte, ntid int64) (module.BT
 	return nt, nil
 }
 
+func (m *manager) BTPPublicKeysFromResult(result []byte, addrs []module.Address, dsa string) (map[string][][]byte, error) {
+	as, err := m.getSystemByteStoreState(result)
+	if err != nil {
+		return nil, err
+	}
+	btpContext := state.NewBTPContext(nil, as)
+	var names []string
+	if len(dsa) > 0 {
+		names = []string{dsa}
+	} else {
+		names = btpContext.GetDSANames()
+	}
+	keys := make(map[string][][]byte, len(names))
+	for _, name := range names {
+		pks := make([][]byte, len(addrs))
+		for i, addr := range addrs {
+			pks[i] = btpContext.GetPublicKey(addr, name)
+		}
+		keys[name] = pks
+	}
+	return keys, nil
+}
+
 func (m *manager) BTPNetworkTypeIDsFromResult(result []byte) ([]int64, error) {
 	as, err := m.getSystemByteStoreState(result)
 	if err != nil {
@@ -751,6 +967,27 @@ func (m *manager) BTPNetworkTypeIDsFromResult(result []byte) ([]int64, error) {
 	return ntids, nil
 }
 
+func (m *manager) ExtensionProofFromResult(result []byte, store string, key []byte) (*module.ExtensionProof, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	prover, ok := wss.GetExtensionSnapshot().(state.ExtensionProver)
+	if !ok {
+		return nil, errors.UnsupportedError.New("NoExtensionProof")
+	}
+	root, value, proof, err := prover.GetProof(store, key)
+	if err != nil {
+		return nil, err
+	}
+	return &module.ExtensionProof{
+		Data:  wss.ExtensionData(),
+		Root:  root,
+		Value: value,
+		Proof: proof,
+	}, nil
+}
+
 func (m *manager) BTPDigestFromResult(result []byte) (module.BTPDigest, error) {
 	wss, err := m.trc.GetWorldSnapshot(result, nil)
 	if err != nil {
@@ -840,43 +1077,66 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
-	tx, err := transaction.NewTransactionFromJSON(js)
+	rcts, err := m.executeTransactions(result, vh, [][]byte{js}, bi)
 	if err != nil {
 		return nil, err
 	}
-	if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
-		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidTransaction")
+	return rcts[0], nil
+}
+
+// executeTransactions executes the transactions in order on the specified
+// state for estimation, so that each transaction sees the changes of the
+// previous ones. It stops after the first transaction failed.
+func (m *manager) executeTransactions(result []byte, vh []byte, jss [][]byte, bi module.BlockInfo) ([]module.Receipt, error) {
+	txs := make([]transaction.Transaction, len(jss))
+	for i, js := range jss {
+		tx, err := transaction.NewTransactionFromJSON(js)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+			return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidTransaction")
+		}
+		txs[i] = tx
 	}
 
-	txh, err := tx.GetHandler(m.cm)
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
 	if err != nil {
 		return nil, err
 	}
-	defer txh.Dispose()
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
 
-	var wc state.WorldContext
-	wss, err := m.trc.GetWorldSnapshot(result, vh)
-	if err == nil {
-		ws, err := state.WorldStateFromSnapshot(wss)
+	rcts := make([]module.Receipt, 0, len(txs))
+	for i, tx := range txs {
+		txh, err := tx.GetHandler(m.cm)
 		if err != nil {
 			return nil, err
 		}
-		wc = state.NewWorldContext(ws, bi, nil, m.plt)
-	} else {
-		return nil, err
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     module.TransactionGroupNormal,
+			Index:     int32(i),
+			Hash:      tx.ID(),
+			From:      tx.From(),
+			Timestamp: tx.Timestamp(),
+			Nonce:     tx.Nonce(),
+		})
+		ctx.UpdateSystemInfo()
+		rct, err := txh.Execute(ctx, ctx.GetSnapshot(), true)
+		txh.Dispose()
+		if err != nil {
+			return nil, err
+		}
+		rcts = append(rcts, rct)
+		if rct.Status() != module.StatusSuccess {
+			break
+		}
 	}
-	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
-	ctx.SetTransactionInfo(&state.TransactionInfo{
-		Group:     module.TransactionGroupNormal,
-		Index:     0,
-		Hash:      tx.ID(),
-		From:      tx.From(),
-		Timestamp: tx.Timestamp(),
-		Nonce:     tx.Nonce(),
-	})
-	ctx.UpdateSystemInfo()
-
-	return txh.Execute(ctx, wss, true)
+	return rcts, nil
 }
 
 func (m *manager) AddSyncRequest(id db.BucketID, key []byte) error {
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestManager_GetBalances(t *testing.T) {
+	dbase := db.NewMapDB()
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	empty := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	ws.GetAccountState(eoa.ID()).SetBalance(big.NewInt(100))
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(eoa)
+	as.SetBalance(big.NewInt(50))
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	result := (&transitionResult{StateHash: wss.StateHash()}).Bytes()
+
+	m := &manager{trc: newTransitionResultCache(dbase, &testPlatform{}, 10, 10, log.New())}
+	balances, err := m.GetBalances(result, []module.Address{eoa, score, empty})
+	assert.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(100), big.NewInt(50), big.NewInt(0)}, balances)
+
+	// the prefix of each address shall match the account
+	_, err = m.GetBalances(result, []module.Address{eoa, common.NewContractAddress(eoa.ID())})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = m.GetBalances(result, []module.Address{common.NewAccountAddress(score.ID())})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
+
+type testDepositContext struct {
+	height int64
+	term   int64
+}
+
+func (c *testDepositContext) StepPrice() *big.Int        { return big.NewInt(10) }
+func (c *testDepositContext) BlockHeight() int64         { return c.height }
+func (c *testDepositContext) DepositTerm() int64         { return c.term }
+func (c *testDepositContext) DepositIssueRate() *big.Int { return big.NewInt(8) }
+func (c *testDepositContext) TransactionID() []byte      { return []byte{0x01} }
+
+func TestManager_GetFeeSharingStatus(t *testing.T) {
+	dbase := db.NewMapDB()
+	owner := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	unknown := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarServiceConfig).Set(state.SysConfigFeeSharing))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(10))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarDepositTerm).Set(100))
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(owner)
+	assert.NoError(t, as.AddDeposit(&testDepositContext{height: 10, term: 100}, big.NewInt(1000)))
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	result := (&transitionResult{StateHash: wss.StateHash()}).Bytes()
+
+	m := &manager{trc: newTransitionResultCache(dbase, &testPlatform{}, 10, 10, log.New())}
+	fss, err := m.GetFeeSharingStatus(result, score)
+	assert.NoError(t, err)
+	jso, err := fss.ToJSON(20, module.JSONVersion3)
+	assert.NoError(t, err)
+	status := jso.(map[string]interface{})
+	assert.Equal(t, "0x1", status["feeSharingEnabled"])
+	assert.Equal(t, "0xa", status["stepPrice"])
+	assert.Equal(t, "0x64", status["depositTerm"])
+	assert.Equal(t, "0x0", status["useSystemDeposit"])
+	assert.Equal(t, "0x384", status["availableDeposit"])
+	assert.Equal(t, "0x8", status["availableVirtualStep"])
+	deposits := status["deposits"].([]interface{})
+	if assert.Len(t, deposits, 1) {
+		assert.Equal(t, "0x6e", deposits[0].(map[string]interface{})["expires"])
+	}
+
+	// deposits are not available after the expiration
+	jso, err = fss.ToJSON(110, module.JSONVersion3)
+	assert.NoError(t, err)
+	status = jso.(map[string]interface{})
+	assert.Equal(t, "0x0", status["availableDeposit"])
+	assert.Equal(t, "0x0", status["availableVirtualStep"])
+
+	_, err = m.GetFeeSharingStatus(result, owner)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = m.GetFeeSharingStatus(result, unknown)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	pendingExecutionHistory = 16
+)
+
+// PendingExecution is the result of normal transactions executed for
+// a block which is not finalized yet. Another block may be finalized
+// at the height, so consumers should check it on finalization.
+type PendingExecution struct {
+	Height       int64
+	ID           []byte // hash of normal transactions
+	Transactions module.TransactionList
+	Receipts     module.ReceiptList
+}
+
+type PendingListener func(pe *PendingExecution)
+
+type pendingListener struct {
+	cb PendingListener
+}
+
+// PendingExecutions notifies listeners of executions of non-finalized
+// blocks. Same transactions may be executed more than once for a height
+// (ex. proposal and validation), but they are notified only once.
+type PendingExecutions struct {
+	lock      sync.Mutex
+	listeners map[*pendingListener]struct{}
+	notified  map[string]int64
+}
+
+// AddListener adds the listener and returns the function to remove it.
+// The listener is called in the execution, so it shouldn't be blocked.
+func (p *PendingExecutions) AddListener(cb PendingListener) func() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	l := &pendingListener{cb: cb}
+	p.listeners[l] = struct{}{}
+	return func() {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		delete(p.listeners, l)
+	}
+}
+
+func (p *PendingExecutions) notify(pe *PendingExecution) {
+	p.lock.Lock()
+	key := string(pe.ID)
+	if h, ok := p.notified[key]; (ok && h == pe.Height) || len(p.listeners) == 0 {
+		p.lock.Unlock()
+		return
+	}
+	for k, h := range p.notified {
+		if h+pendingExecutionHistory < pe.Height {
+			delete(p.notified, k)
+		}
+	}
+	p.notified[key] = pe.Height
+	listeners := make([]*pendingListener, 0, len(p.listeners))
+	for l := range p.listeners {
+		listeners = append(listeners, l)
+	}
+	p.lock.Unlock()
+
+	for _, l := range listeners {
+		l.cb(pe)
+	}
+}
+
+func NewPendingExecutions() *PendingExecutions {
+	return &PendingExecutions{
+		listeners: make(map[*pendingListener]struct{}),
+		notified:  make(map[string]int64),
+	}
+}
+
+// PendingExecutionSource is implemented by service managers supporting
+// notifications of pending executions.
+type PendingExecutionSource interface {
+	PendingExecutions() *PendingExecutions
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingExecutions_Notify(t *testing.T) {
+	pes := NewPendingExecutions()
+
+	// no listeners, so it's not recorded
+	pes.notify(&PendingExecution{Height: 1, ID: []byte{0x01}})
+
+	var got []*PendingExecution
+	remove := pes.AddListener(func(pe *PendingExecution) {
+		got = append(got, pe)
+	})
+
+	pes.notify(&PendingExecution{Height: 1, ID: []byte{0x01}})
+	pes.notify(&PendingExecution{Height: 1, ID: []byte{0x01}})
+	pes.notify(&PendingExecution{Height: 1, ID: []byte{0x02}})
+	pes.notify(&PendingExecution{Height: 2, ID: []byte{0x01}})
+	assert.Len(t, got, 3)
+	assert.EqualValues(t, []byte{0x02}, got[1].ID)
+	assert.EqualValues(t, 2, got[2].Height)
+
+	remove()
+	pes.notify(&PendingExecution{Height: 3, ID: []byte{0x03}})
+	assert.Len(t, got, 3)
+}
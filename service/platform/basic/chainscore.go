@@ -576,6 +576,40 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, Revision9, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setTxValidator",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		nil,
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getTxValidator",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Address,
+		},
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setTxValidation",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"enable", scoreapi.Bool, nil, nil},
+		},
+		nil,
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "isTxValidationEnabled",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Bool,
+		},
+	}, Revision10, 0},
 }
 
 func (s *ChainScore) GetAPI() *scoreapi.Info {
@@ -1658,3 +1692,51 @@ func (s *ChainScore) newBTPContext() state.BTPContext {
 	store := s.cc.GetAccountState(state.SystemID)
 	return state.NewBTPContext(s.cc, store)
 }
+
+func (s *ChainScore) Ex_setTxValidator(address module.Address) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	if !address.IsContract() {
+		return scoreresult.New(StatusIllegalArgument, "NotContract")
+	}
+	if as := s.cc.GetAccountState(address.ID()); !as.IsContract() {
+		return scoreresult.New(StatusNotFound, "NoContract")
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	return scoredb.NewVarDB(as, state.VarTxValidator).Set(address)
+}
+
+func (s *ChainScore) Ex_getTxValidator() (module.Address, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return nil, err
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	return scoredb.NewVarDB(as, state.VarTxValidator).Address(), nil
+}
+
+// Ex_setTxValidation makes the transactions of the sender validated by
+// the validator contract.
+func (s *ChainScore) Ex_setTxValidation(enable bool) error {
+	if s.from.IsContract() {
+		return scoreresult.New(module.StatusAccessDenied, "NoPermission")
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	db := scoredb.NewDictDB(as, state.VarTxValidationAccounts, 1)
+	if enable {
+		if scoredb.NewVarDB(as, state.VarTxValidator).Address() == nil {
+			return scoreresult.New(StatusNotFound, "NoTxValidator")
+		}
+		return db.Set(s.from, true)
+	}
+	return db.Delete(s.from)
+}
+
+func (s *ChainScore) Ex_isTxValidationEnabled(address module.Address) (bool, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return false, err
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	v := scoredb.NewDictDB(as, state.VarTxValidationAccounts, 1).Get(address)
+	return v != nil && v.Bool(), nil
+}
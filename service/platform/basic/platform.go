@@ -61,6 +61,10 @@ func (t *platform) NewExtensionSnapshot(database db.Database, raw []byte) state.
 	return nil
 }
 
+func (t *platform) MaxRevision() int {
+	return MaxRevision
+}
+
 func (t *platform) ToRevision(value int) module.Revision {
 	return valueToRevision(value)
 }
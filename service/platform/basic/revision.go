@@ -28,6 +28,7 @@ const (
 	Revision7
 	Revision8
 	Revision9
+	Revision10
 	RevisionReserved
 )
 
@@ -58,6 +59,8 @@ var revisionFlags = []module.Revision{
 	module.UseCompactAPIInfo,
 	// Revision 9
 	module.MultipleFeePayers,
+	// Revision 10
+	module.ContractTxValidation,
 }
 
 func init() {
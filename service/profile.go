@@ -0,0 +1,73 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/hotspot"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// ProfileState measures the size of the world state of the result with
+// the profiler. See state.StateProfiler.Profile for top and cb.
+func ProfileState(sm module.ServiceManager, p *state.StateProfiler, result []byte, top int, cb func(n int64) error) (*state.StateProfile, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoStateProfile(sm=%T)", sm)
+	}
+	wss, err := mgr.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.Profile(wss, top, cb)
+}
+
+// HotspotProfiler returns the profiler of contracts executed by the
+// service manager.
+func HotspotProfiler(sm module.ServiceManager) (*hotspot.Profiler, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoHotspotProfiler(sm=%T)", sm)
+	}
+	return mgr.hsp, nil
+}
+
+// ReceiptsSize returns the size of the patch and the normal receipts of
+// the result including their event logs.
+func ReceiptsSize(sm module.ServiceManager, result []byte) (int64, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return 0, errors.UnsupportedError.Errorf("NoReceiptsSize(sm=%T)", sm)
+	}
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return 0, err
+	}
+	sc := merkle.NewSizeContext(mgr.db)
+	for _, h := range [][]byte{tr.PatchReceiptHash, tr.NormalReceiptHash} {
+		if len(h) > 0 {
+			txresult.NewReceiptListWithBuilder(sc.Builder(), h)
+		}
+	}
+	if err := sc.Run(); err != nil {
+		return 0, err
+	}
+	return sc.Total().Bytes, nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// ProposalCall is a call made by the governance on the approval of the
+// network proposal. Data is the call data like
+// {"method":"setStepPrice","params":{"price":"0x2e90edd00"}}.
+type ProposalCall struct {
+	To   module.Address
+	Data []byte
+}
+
+// SimulatedTransaction has the receipts of the transaction in the pool
+// executed on the current state (Base) and on the state with the proposal
+// applied (Applied).
+type SimulatedTransaction struct {
+	Transaction module.Transaction
+	Base        module.Receipt
+	Applied     module.Receipt
+}
+
+// SimulateProposal applies the calls of the proposal to a copy of the state
+// of the result, then executes up to n transactions at the front of the pool
+// on the copy and on the state without the proposal. Transactions which
+// can't be included in the next block are skipped. The state is not changed.
+func SimulateProposal(sm module.ServiceManager, result []byte, vh []byte, calls []*ProposalCall, n int, bi module.BlockInfo) ([]*SimulatedTransaction, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoProposalSimulation(sm=%T)", sm)
+	}
+	return mgr.simulateProposal(result, vh, calls, n, bi)
+}
+
+func (m *manager) simulateProposal(result []byte, vh []byte, calls []*ProposalCall, n int, bi module.BlockInfo) ([]*SimulatedTransaction, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := m.newSimulationContext(wss, bi)
+	if err != nil {
+		return nil, err
+	}
+	var txs []transaction.Transaction
+	for _, tx := range m.tm.getTxPool(module.TransactionGroupNormal).Front(n) {
+		if err := tx.PreValidate(base, true); err != nil {
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	brcts, err := m.executeForSimulation(base, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.newSimulationContext(wss, bi)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.applyProposal(applied, calls); err != nil {
+		return nil, err
+	}
+	arcts, err := m.executeForSimulation(applied, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	sts := make([]*SimulatedTransaction, len(txs))
+	for i, tx := range txs {
+		sts[i] = &SimulatedTransaction{
+			Transaction: tx,
+			Base:        brcts[i],
+			Applied:     arcts[i],
+		}
+	}
+	return sts, nil
+}
+
+func (m *manager) newSimulationContext(wss state.WorldSnapshot, bi module.BlockInfo) (contract.Context, error) {
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	return contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery), nil
+}
+
+// applyProposal makes the calls of the proposal from the governance, so
+// that the chain SCORE accepts them like the approved proposal.
+func (m *manager) applyProposal(ctx contract.Context, calls []*ProposalCall) error {
+	gov := ctx.Governance()
+	for i, call := range calls {
+		handler, err := m.cm.GetHandler(gov, call.To, big.NewInt(0), contract.CTypeCall, call.Data)
+		if err != nil {
+			return scoreresult.InvalidParameterError.Wrapf(err, "InvalidProposalCall(idx=%d)", i)
+		}
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     module.TransactionGroupNormal,
+			Index:     int32(i),
+			Hash:      make([]byte, 32),
+			From:      gov,
+			Timestamp: ctx.BlockTimeStamp(),
+		})
+		ctx.UpdateSystemInfo()
+		cc := contract.NewCallContext(ctx, ctx.GetStepLimit(state.StepLimitTypeInvoke), false)
+		status, _, _, _ := cc.Call(handler, cc.StepAvailable())
+		cc.Dispose()
+		if status != nil {
+			return scoreresult.InvalidParameterError.Wrapf(status, "ProposalCallFailure(idx=%d)", i)
+		}
+	}
+	return nil
+}
+
+// executeForSimulation executes the transactions in order on the context.
+// Unlike executeTransactions, it continues after failures, because the
+// transactions in the pool are independent of each other.
+func (m *manager) executeForSimulation(ctx contract.Context, txs []transaction.Transaction) ([]module.Receipt, error) {
+	rcts := make([]module.Receipt, 0, len(txs))
+	for i, tx := range txs {
+		txh, err := tx.GetHandler(m.cm)
+		if err != nil {
+			return nil, err
+		}
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     module.TransactionGroupNormal,
+			Index:     int32(i),
+			Hash:      tx.ID(),
+			From:      tx.From(),
+			Timestamp: tx.Timestamp(),
+			Nonce:     tx.Nonce(),
+		})
+		ctx.UpdateSystemInfo()
+		rct, err := txh.Execute(ctx, ctx.GetSnapshot(), false)
+		txh.Dispose()
+		if err != nil {
+			return nil, err
+		}
+		rcts = append(rcts, rct)
+	}
+	return rcts, nil
+}
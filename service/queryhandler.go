@@ -13,8 +13,9 @@ import (
 )
 
 type QueryHandler struct {
-	to   module.Address
-	data []byte
+	to        module.Address
+	data      []byte
+	stepLimit *big.Int
 
 	contractHandler contract.ContractHandler
 }
@@ -47,6 +48,9 @@ func (qh *QueryHandler) Query(ctx contract.Context) (interface{}, error) {
 	}
 
 	limit := ctx.GetStepLimit(state.StepLimitTypeQuery)
+	if qh.stepLimit != nil && qh.stepLimit.Cmp(limit) < 0 {
+		limit = qh.stepLimit
+	}
 	cc := contract.NewCallContext(ctx, limit, true)
 
 	if !cc.ApplySteps(state.StepTypeDefault, 1) {
@@ -70,14 +74,18 @@ func (qh *QueryHandler) Query(ctx contract.Context) (interface{}, error) {
 	return value, nil
 }
 
-func NewQueryHandler(cm contract.ContractManager, to module.Address, data []byte) (*QueryHandler, error) {
+// NewQueryHandler returns a handler for the query. stepLimit can be used to
+// lower the step limit of the query below the limit of the chain, and nil
+// means that the limit of the chain is used.
+func NewQueryHandler(cm contract.ContractManager, to module.Address, data []byte, stepLimit *big.Int) (*QueryHandler, error) {
 	handler, err := cm.GetHandler(nil, to, big.NewInt(0), contract.CTypeCall, data)
 	if err != nil {
 		return nil, errors.InvalidStateError.Wrap(err, "NoSuitableHandler")
 	}
 	return &QueryHandler{
-		to:   to,
-		data: data,
+		to:        to,
+		data:      data,
+		stepLimit: stepLimit,
 
 		contractHandler: handler,
 	}, nil
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+type testQueryContext struct {
+	contract.Context
+	limit int64
+}
+
+func (c *testQueryContext) GetStepLimit(t string) *big.Int {
+	return big.NewInt(c.limit)
+}
+
+// testQueryHandler uses the steps and keeps the steps available for it.
+type testQueryHandler struct {
+	steps     int64
+	available *big.Int
+}
+
+func (h *testQueryHandler) Prepare(ctx contract.Context) (state.WorldContext, error) {
+	return ctx, nil
+}
+
+func (h *testQueryHandler) SetTraceLogger(logger *trace.Logger) {}
+
+func (h *testQueryHandler) TraceLogger() *trace.Logger { return nil }
+
+func (h *testQueryHandler) ExecuteSync(cc contract.CallContext) (error, *codec.TypedObj, module.Address) {
+	h.available = cc.StepAvailable()
+	if !cc.DeductSteps(big.NewInt(h.steps)) {
+		return scoreresult.OutOfStepError.New("NotEnoughSteps"), nil, nil
+	}
+	return nil, common.MustEncodeAny(big.NewInt(1)), nil
+}
+
+type testQueryContractManager struct {
+	contract.ContractManager
+	handler contract.ContractHandler
+}
+
+func (cm *testQueryContractManager) GetHandler(from, to module.Address, value *big.Int, ctype int, data []byte) (contract.ContractHandler, error) {
+	return cm.handler, nil
+}
+
+func newTestQueryContext(score module.Address, limit int64) contract.Context {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(common.MustNewAddressFromString("hx0000000000000000000000000000000000000001"))
+	as.SetAPIInfo(scoreapi.NewInfo([]*scoreapi.Method{{
+		Type:    scoreapi.Function,
+		Name:    "balance",
+		Flags:   scoreapi.FlagReadOnly | scoreapi.FlagExternal,
+		Outputs: []scoreapi.DataType{scoreapi.Integer},
+	}}))
+	wc := state.NewWorldContext(ws, common.NewBlockInfo(1, 0), nil, testAccessPlatform{})
+	return &testQueryContext{
+		Context: contract.NewContext(wc, nil, nil, nil, log.New(), nil, eeproxy.ForQuery),
+		limit:   limit,
+	}
+}
+
+func TestQueryHandler_StepLimit(t *testing.T) {
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	data := []byte(`{"method":"balance"}`)
+	tests := []struct {
+		name      string
+		stepLimit *big.Int
+		available int64
+		success   bool
+	}{
+		{"NoLimit", nil, 1000, true},
+		{"UnderLimit", big.NewInt(200), 200, true},
+		{"OverLimit", big.NewInt(50), 50, false},
+		{"OverChainLimit", big.NewInt(5000), 1000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &testQueryHandler{steps: 100}
+			cm := &testQueryContractManager{handler: h}
+			qh, err := NewQueryHandler(cm, score, data, tt.stepLimit)
+			assert.NoError(t, err)
+
+			ret, err := qh.Query(newTestQueryContext(score, 1000))
+			assert.Equal(t, big.NewInt(tt.available), h.available)
+			if tt.success {
+				assert.NoError(t, err)
+				assert.Equal(t, common.NewHexInt(1), ret)
+			} else {
+				assert.True(t, scoreresult.OutOfStepError.Equals(err))
+			}
+		})
+	}
+}
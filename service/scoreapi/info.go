@@ -95,6 +95,21 @@ func (info *Info) CheckEventData(indexed [][]byte, data [][]byte) error {
 	return m.CheckEventData(indexed, data)
 }
 
+func (info *Info) DecodeEvent(indexed [][]byte, data [][]byte) (*Method, map[string]interface{}, error) {
+	if len(indexed) < 1 {
+		return nil, nil, ErrNoSignature
+	}
+	m := info.GetMethod(string(indexed[0]))
+	if m == nil {
+		return nil, nil, errors.ErrNotFound
+	}
+	params, err := m.DecodeEventData(indexed, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, params, nil
+}
+
 func (info *Info) ToJSON(v module.JSONVersion) (interface{}, error) {
 	jso := make([]interface{}, 0, len(info.methods))
 	for _, method := range info.methods {
@@ -829,6 +829,29 @@ func (a *Method) CheckEventData(indexed [][]byte, data [][]byte) error {
 	return nil
 }
 
+// DecodeEventData returns named parameters of the event after checking
+// indexed and data with CheckEventData.
+func (a *Method) DecodeEventData(indexed [][]byte, data [][]byte) (map[string]interface{}, error) {
+	if err := a.CheckEventData(indexed, data); err != nil {
+		return nil, err
+	}
+	params := make(map[string]interface{}, len(a.Inputs))
+	for i, p := range a.Inputs {
+		var input []byte
+		if i < len(indexed)-1 {
+			input = indexed[i+1]
+		} else {
+			input = data[i+1-len(indexed)]
+		}
+		if v, err := p.Type.ConvertBytesToJSO(input); err != nil {
+			return nil, err
+		} else {
+			params[p.Name] = v
+		}
+	}
+	return params, nil
+}
+
 type inputParameters interface {
 	Get(i int, n string) (json.RawMessage, bool)
 	Size() int
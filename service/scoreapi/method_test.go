@@ -261,3 +261,43 @@ func TestMethod_ConvertParamsToTypedObj(t *testing.T) {
 		})
 	}
 }
+
+func TestMethod_DecodeEventData(t *testing.T) {
+	m := &Method{
+		Type:    Event,
+		Name:    "Transfer",
+		Indexed: 2,
+		Inputs: []Parameter{
+			{Name: "_from", Type: Address},
+			{Name: "_to", Type: Address},
+			{Name: "_value", Type: Integer},
+		},
+	}
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	to := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	value := common.NewHexInt(10)
+	indexed := [][]byte{
+		[]byte(m.Signature()),
+		from.Bytes(),
+		to.Bytes(),
+	}
+	data := [][]byte{value.Bytes()}
+
+	params, err := m.DecodeEventData(indexed, data)
+	if err != nil {
+		t.Fatalf("DecodeEventData() fails err=%+v", err)
+	}
+	if !from.Equal(params["_from"].(*common.Address)) {
+		t.Errorf("DecodeEventData() _from=%v exp=%v", params["_from"], from)
+	}
+	if !to.Equal(params["_to"].(*common.Address)) {
+		t.Errorf("DecodeEventData() _to=%v exp=%v", params["_to"], to)
+	}
+	if params["_value"].(*common.HexInt).Cmp(value.Value()) != 0 {
+		t.Errorf("DecodeEventData() _value=%v exp=%v", params["_value"], value)
+	}
+
+	if _, err := m.DecodeEventData(indexed[:2], data); err == nil {
+		t.Errorf("DecodeEventData() should fail with invalid indexed")
+	}
+}
@@ -1,6 +1,8 @@
 package scoreresult
 
 import (
+	"fmt"
+
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
@@ -60,6 +62,46 @@ func WithStatus(e error, s module.Status) error {
 	return errors.WithCode(e, codeForStatus(s))
 }
 
+type withAddress struct {
+	error
+	address module.Address
+}
+
+func (e *withAddress) Unwrap() error {
+	return e.error
+}
+
+func (e *withAddress) Format(f fmt.State, c rune) {
+	if fe, ok := e.error.(fmt.Formatter); ok {
+		fe.Format(f, c)
+	} else {
+		fmt.Fprint(f, e.error.Error())
+	}
+}
+
+// WithAddress attaches the address of the contract reporting the failure.
+// It keeps the address already attached, so the first one is preserved while
+// the failure is propagated to the callers.
+func WithAddress(e error, addr module.Address) error {
+	if e == nil || addr == nil || AddressOf(e) != nil {
+		return e
+	}
+	return &withAddress{e, addr}
+}
+
+// AddressOf returns the address of the contract reporting the failure, or nil
+// if it's unknown.
+func AddressOf(e error) module.Address {
+	we := errors.FindCause(e, func(err error) bool {
+		_, ok := err.(*withAddress)
+		return ok
+	})
+	if we != nil {
+		return we.(*withAddress).address
+	}
+	return nil
+}
+
 const (
 	Success = errors.CodeSCORE + errors.Code(module.StatusSuccess) + iota
 	UnknownFailureError
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// FindStaleAccounts returns the accounts in the state of the result, which
+// are not accessed since the height. Heights of accesses are recorded with
+// access_index of the chain. See state.FindStaleAccounts for start and limit.
+func FindStaleAccounts(sm module.ServiceManager, result []byte, before int64, start []byte, limit int) ([]*state.StaleAccount, []byte, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf("NoStaleAccounts(sm=%T)", sm)
+	}
+	if !mgr.chain.AccessIndex() {
+		return nil, nil, errors.UnsupportedError.New("AccessIndexDisabled")
+	}
+	wss, err := mgr.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	bk, err := mgr.db.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, nil, err
+	}
+	heightOf := func(key []byte) (int64, int64, error) {
+		ah, err := getAccessHeight(bk, key)
+		if err != nil {
+			return 0, 0, err
+		}
+		return ah.Account, ah.Storage, nil
+	}
+	return state.FindStaleAccounts(wss, heightOf, before, start, limit)
+}
+
+// AccessIndexStart returns the height of the first block recorded in the
+// access index. It returns zero if nothing is recorded.
+func AccessIndexStart(sm module.ServiceManager) (int64, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return 0, errors.UnsupportedError.Errorf("NoAccessIndex(sm=%T)", sm)
+	}
+	return accessIndexStart(mgr.db)
+}
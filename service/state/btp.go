@@ -62,6 +62,7 @@ type BTPContext interface {
 	GetPublicKey(address module.Address, name string) []byte
 	GetPublicKeyMask(address module.Address) int64
 	GetDSAIndex(name string) int
+	GetDSANames() []string
 	GetActiveDSAMask() int64
 }
 
@@ -174,6 +175,15 @@ func (bc *btpContext) GetDSAIndex(name string) int {
 	return -1
 }
 
+func (bc *btpContext) GetDSANames() []string {
+	dbase := scoredb.NewArrayDB(bc.Store(), DSAArrayKey)
+	names := make([]string, dbase.Size())
+	for i := range names {
+		names[i] = dbase.Get(i).String()
+	}
+	return names
+}
+
 func (bc *btpContext) GetActiveDSAMask() int64 {
 	return scoredb.NewVarDB(bc.Store(), ActiveDSAMaskKey).Int64()
 }
@@ -22,6 +22,13 @@ type ExtensionSnapshot interface {
 	NewState(readonly bool) ExtensionState
 }
 
+// ExtensionProver is implemented by the extension snapshots which can prove
+// the values in their stores. It returns the root of the store, the value
+// for the key and the proof of it.
+type ExtensionProver interface {
+	GetProof(store string, key []byte) (root, value []byte, proof [][]byte, err error)
+}
+
 type ExtensionState interface {
 	GetSnapshot() ExtensionSnapshot
 	Reset(snapshot ExtensionSnapshot)
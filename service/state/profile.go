@@ -0,0 +1,242 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+// AccountProfile is the size of the data of an account in bytes.
+type AccountProfile struct {
+	// Key is the key of the account in the world trie, SHA3-256 hash of
+	// the ID of the address.
+	Key      common.HexBytes `json:"key"`
+	Contract bool            `json:"contract"`
+	// Account is the size of the account object.
+	Account int64 `json:"account"`
+	// Entries is the number of entries in the storage.
+	Entries int64 `json:"entries"`
+	// Values is the size of values in the storage.
+	Values int64 `json:"values"`
+	// Trie is the size of the nodes of the storage trie including values.
+	Trie int64 `json:"trie"`
+	// Code is the size of the codes of the current and the next contract.
+	Code int64 `json:"code"`
+
+	hash  []byte
+	codes map[string]int64
+}
+
+// Total returns the size of all the data of the account.
+func (p *AccountProfile) Total() int64 {
+	return p.Account + p.Trie + p.Code
+}
+
+// StateProfile is the size of the data of the world state in bytes.
+type StateProfile struct {
+	StateHash common.HexBytes `json:"stateHash"`
+	Accounts  int64           `json:"accounts"`
+	Contracts int64           `json:"contracts"`
+	// Account is the size of the account objects.
+	Account int64 `json:"account"`
+	// WorldTrie is the size of the nodes of the world trie including
+	// account objects.
+	WorldTrie int64 `json:"worldTrie"`
+	// Entries is the number of entries in the storages.
+	Entries int64 `json:"entries"`
+	// Values is the size of values in the storages.
+	Values int64 `json:"values"`
+	// StorageTrie is the size of the nodes of the storage tries including
+	// values.
+	StorageTrie int64 `json:"storageTrie"`
+	// Code is the size of the codes. Same codes are counted once.
+	Code int64 `json:"code"`
+	// Overhead is the size of the trie nodes excluding account objects
+	// and values.
+	Overhead int64 `json:"overhead"`
+	// Updated is the number of the accounts measured in this profiling.
+	// Others are reused from the previous profiling.
+	Updated int64 `json:"updated"`
+	// Top is the list of the largest accounts.
+	Top []*AccountProfile `json:"top"`
+}
+
+// StateProfiler measures the size of the world state. It keeps the profile
+// of each account, and it measures only the accounts changed after the
+// previous profiling.
+type StateProfiler struct {
+	accounts map[string]*AccountProfile
+}
+
+func NewStateProfiler() *StateProfiler {
+	return &StateProfiler{
+		accounts: make(map[string]*AccountProfile),
+	}
+}
+
+// trieSize returns the size of the nodes of the trie.
+func trieSize(dbase db.Database, hash []byte) (int64, error) {
+	if len(hash) == 0 {
+		return 0, nil
+	}
+	sc := merkle.NewSizeContext(dbase)
+	trie_manager.NewImmutable(sc.Builder().Database(), hash).Resolve(sc.Builder())
+	if err := sc.Run(); err != nil {
+		return 0, err
+	}
+	return sc.SizeOf(db.MerkleTrie).Bytes, nil
+}
+
+func profileAccount(dbase db.Database, key []byte, s *accountSnapshotImpl, bs []byte) (*AccountProfile, error) {
+	p := &AccountProfile{
+		Key:      append([]byte(nil), key...),
+		Contract: s.IsContract(),
+		Account:  int64(len(bs)),
+		hash:     crypto.SHA3Sum256(bs),
+	}
+	if store, ok := s.store.(trie.Immutable); ok {
+		for itr := store.Iterator(); itr.Has(); {
+			value, _, err := itr.Get()
+			if err != nil {
+				return nil, err
+			}
+			p.Entries += 1
+			p.Values += int64(len(value))
+			if err := itr.Next(); err != nil {
+				return nil, err
+			}
+		}
+		size, err := trieSize(dbase, store.Hash())
+		if err != nil {
+			return nil, err
+		}
+		p.Trie = size
+	}
+	for _, c := range []*contract{s.curContract, s.nextContract} {
+		if c == nil || len(c.codeHash) == 0 {
+			continue
+		}
+		if p.codes == nil {
+			p.codes = make(map[string]int64)
+		}
+		if _, ok := p.codes[string(c.codeHash)]; ok {
+			continue
+		}
+		code, err := c.Code()
+		if err != nil {
+			return nil, err
+		}
+		p.codes[string(c.codeHash)] = int64(len(code))
+		p.Code += int64(len(code))
+	}
+	return p, nil
+}
+
+// Profile measures the size of the world state, and returns the profile
+// with top largest accounts. The callback is called for each account with
+// the number of accounts processed, and it stops if the callback returns
+// an error.
+func (p *StateProfiler) Profile(wss WorldSnapshot, top int, cb func(n int64) error) (*StateProfile, error) {
+	ws, ok := wss.(*worldSnapshotImpl)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("UnknownWorldSnapshot(%T)", wss)
+	}
+	dbase := ws.Database()
+	r := &StateProfile{
+		StateHash: ws.StateHash(),
+	}
+	accounts := make(map[string]*AccountProfile, len(p.accounts))
+	codes := make(map[string]int64)
+	for itr := ws.accounts.Iterator(); itr.Has(); {
+		obj, key, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		s, ok := obj.(*accountSnapshotImpl)
+		if !ok {
+			return nil, errors.UnsupportedError.Errorf("UnknownAccount(%T)", obj)
+		}
+		bs := s.Bytes()
+		ap, ok := p.accounts[string(key)]
+		if !ok || !bytes.Equal(ap.hash, crypto.SHA3Sum256(bs)) {
+			if ap, err = profileAccount(dbase, key, s, bs); err != nil {
+				return nil, err
+			}
+			r.Updated += 1
+		}
+		accounts[string(key)] = ap
+
+		r.Accounts += 1
+		if ap.Contract {
+			r.Contracts += 1
+		}
+		r.Account += ap.Account
+		r.Entries += ap.Entries
+		r.Values += ap.Values
+		r.StorageTrie += ap.Trie
+		for h, size := range ap.codes {
+			codes[h] = size
+		}
+		if cb != nil {
+			if err := cb(r.Accounts); err != nil {
+				return nil, err
+			}
+		}
+		if err := itr.Next(); err != nil {
+			return nil, err
+		}
+	}
+	p.accounts = accounts
+
+	for _, size := range codes {
+		r.Code += size
+	}
+	size, err := trieSize(dbase, ws.StateHash())
+	if err != nil {
+		return nil, err
+	}
+	r.WorldTrie = size
+	r.Overhead = r.WorldTrie - r.Account + r.StorageTrie - r.Values
+	r.Top = p.top(top)
+	return r, nil
+}
+
+func (p *StateProfiler) top(n int) []*AccountProfile {
+	list := make([]*AccountProfile, 0, len(p.accounts))
+	for _, ap := range p.accounts {
+		list = append(list, ap)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if ti, tj := list[i].Total(), list[j].Total(); ti != tj {
+			return ti > tj
+		}
+		return bytes.Compare(list[i].Key, list[j].Key) < 0
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestStateProfiler_Profile(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil, nil, nil)
+
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	code := []byte("CODE")
+
+	as := ws.GetAccountState(eoa.ID())
+	as.SetBalance(big.NewInt(100))
+
+	as = ws.GetAccountState(score.ID())
+	as.InitContractAccount(eoa)
+	_, err := as.DeployContract(code, PythonEE, CTAppZip, nil, []byte("TX"))
+	assert.NoError(t, err)
+	_, err = as.SetValue([]byte("key1"), []byte("value1"))
+	assert.NoError(t, err)
+	_, err = as.SetValue([]byte("key2"), []byte("value22"))
+	assert.NoError(t, err)
+
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	p := NewStateProfiler()
+	var progress int64
+	r, err := p.Profile(wss, 1, func(n int64) error {
+		progress = n
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), progress)
+	assert.Equal(t, int64(2), r.Accounts)
+	assert.Equal(t, int64(1), r.Contracts)
+	assert.Equal(t, int64(2), r.Entries)
+	assert.Equal(t, int64(len("value1")+len("value22")), r.Values)
+	assert.Equal(t, int64(len(code)), r.Code)
+	assert.Equal(t, int64(2), r.Updated)
+	assert.True(t, r.WorldTrie > r.Account)
+	assert.True(t, r.StorageTrie > r.Values)
+	assert.Len(t, r.Top, 1)
+	assert.True(t, r.Top[0].Contract)
+
+	r, err = p.Profile(wss, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), r.Updated)
+	assert.Len(t, r.Top, 2)
+
+	as = ws.GetAccountState(eoa.ID())
+	as.SetBalance(big.NewInt(200))
+	wss2 := ws.GetSnapshot()
+	assert.NoError(t, wss2.Flush())
+
+	r2, err := p.Profile(wss2, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), r2.Updated)
+	assert.Equal(t, r.Values, r2.Values)
+	assert.Equal(t, r.StorageTrie, r2.StorageTrie)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// AccountKeyOf returns the key of the account in the world trie.
+func AccountKeyOf(id []byte) []byte {
+	return addressIDToKey(id)
+}
+
+// StaleAccount is the account not accessed since the height.
+type StaleAccount struct {
+	// Key is the key of the account in the world trie, SHA3-256 hash of
+	// the ID of the address.
+	Key      common.HexBytes
+	Contract bool
+	Account  int64
+	Storage  int64
+}
+
+// AccessHeightFunc returns the heights of the last blocks accessing the
+// account of the key and its storage.
+type AccessHeightFunc func(key []byte) (account, storage int64, err error)
+
+// FindStaleAccounts returns the accounts whose last access is before the
+// height. Accounts of which keys are less than start are skipped, and it
+// returns up to limit accounts with the key to continue.
+func FindStaleAccounts(wss WorldSnapshot, heightOf AccessHeightFunc, before int64, start []byte, limit int) ([]*StaleAccount, []byte, error) {
+	ws, ok := wss.(*worldSnapshotImpl)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf("UnknownWorldSnapshot(%T)", wss)
+	}
+	var accounts []*StaleAccount
+	for itr := ws.accounts.Iterator(); itr.Has(); {
+		obj, key, err := itr.Get()
+		if err != nil {
+			return nil, nil, err
+		}
+		if bytes.Compare(key, start) >= 0 {
+			if len(accounts) >= limit {
+				return accounts, append([]byte(nil), key...), nil
+			}
+			s, ok := obj.(*accountSnapshotImpl)
+			if !ok {
+				return nil, nil, errors.UnsupportedError.Errorf("UnknownAccount(%T)", obj)
+			}
+			account, storage, err := heightOf(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			if account < before {
+				accounts = append(accounts, &StaleAccount{
+					Key:      append([]byte(nil), key...),
+					Contract: s.IsContract(),
+					Account:  account,
+					Storage:  storage,
+				})
+			}
+		}
+		if err := itr.Next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return accounts, nil, nil
+}
@@ -0,0 +1,57 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestFindStaleAccounts(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil, nil, nil)
+
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	ws.GetAccountState(addr2.ID()).SetBalance(big.NewInt(200))
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	heights := map[string][2]int64{
+		string(AccountKeyOf(addr2.ID())): {20, 10},
+	}
+	heightOf := func(key []byte) (int64, int64, error) {
+		h := heights[string(key)]
+		return h[0], h[1], nil
+	}
+
+	accounts, next, err := FindStaleAccounts(wss, heightOf, 15, nil, 10)
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, AccountKeyOf(addr1.ID()), []byte(accounts[0].Key))
+	assert.False(t, accounts[0].Contract)
+	assert.Equal(t, int64(0), accounts[0].Account)
+
+	page, next, err := FindStaleAccounts(wss, heightOf, 30, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.NotNil(t, next)
+	accounts = page
+	page, next, err = FindStaleAccounts(wss, heightOf, 30, next, 1)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Nil(t, next)
+	accounts = append(accounts, page...)
+	for _, a := range accounts {
+		if string(a.Key) == string(AccountKeyOf(addr2.ID())) {
+			assert.Equal(t, int64(20), a.Account)
+			assert.Equal(t, int64(10), a.Storage)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+// TxValidatorOf returns the address of the contract validating the
+// transactions of the account. It returns nil if module.ContractTxValidation
+// is not enabled, no validator is set, or the account hasn't opted in.
+func TxValidatorOf(wc WorldContext, from module.Address) module.Address {
+	if from == nil || from.IsContract() || !wc.Revision().Has(module.ContractTxValidation) {
+		return nil
+	}
+	as := scoredb.NewStateStoreWith(wc.GetAccountSnapshot(SystemID))
+	if v := scoredb.NewDictDB(as, VarTxValidationAccounts, 1).Get(from); v == nil || !v.Bool() {
+		return nil
+	}
+	return scoredb.NewVarDB(as, VarTxValidator).Address()
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+type testTxValidationPlatform struct{}
+
+func (testTxValidationPlatform) ToRevision(v int) module.Revision {
+	if v >= 2 {
+		return module.Revision(v) | module.ContractTxValidation
+	}
+	return module.Revision(v)
+}
+
+func TestTxValidatorOf(t *testing.T) {
+	ws := NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	validator := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	as := ws.GetAccountState(SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, VarRevision).Set(1))
+	assert.NoError(t, scoredb.NewVarDB(as, VarTxValidator).Set(validator))
+	assert.NoError(t, scoredb.NewDictDB(as, VarTxValidationAccounts, 1).Set(eoa, true))
+
+	// the revision doesn't enable it
+	wc := NewWorldContext(ws, nil, nil, testTxValidationPlatform{})
+	assert.Nil(t, TxValidatorOf(wc, eoa))
+
+	assert.NoError(t, scoredb.NewVarDB(as, VarRevision).Set(2))
+	wc = NewWorldContext(ws, nil, nil, testTxValidationPlatform{})
+	assert.True(t, validator.Equal(TxValidatorOf(wc, eoa)))
+	assert.Nil(t, TxValidatorOf(wc, validator))
+
+	// the account opts out
+	assert.NoError(t, scoredb.NewDictDB(as, VarTxValidationAccounts, 1).Delete(eoa))
+	assert.Nil(t, TxValidatorOf(wc, eoa))
+}
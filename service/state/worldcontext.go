@@ -5,6 +5,7 @@ import (
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoredb"
@@ -39,6 +40,9 @@ const (
 	VarNextBlockVersion   = "next_block_version"
 	VarEnabledEETypes     = "enabled_ee_types"
 	VarSystemDepositUsage = "system_deposit_usage"
+
+	VarTxValidator          = "tx_validator"
+	VarTxValidationAccounts = "tx_validation_accounts"
 )
 
 const (
@@ -243,7 +247,12 @@ func (c *worldContext) Revision() module.Revision {
 }
 
 func (c *worldContext) DepositIssueRate() *big.Int {
-	ss := scoredb.NewStateStoreWith(c.systemInfo.ass)
+	return DepositIssueRateFrom(scoredb.NewStateStoreWith(c.systemInfo.ass))
+}
+
+// DepositIssueRateFrom returns the deposit issue rate stored in the system
+// storage or the default value if it's not configured.
+func DepositIssueRateFrom(ss containerdb.BytesStoreState) *big.Int {
 	if r := scoredb.NewVarDB(ss, VarDepositIssueRate).BigInt(); r != nil {
 		return r
 	} else {
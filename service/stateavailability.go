@@ -0,0 +1,83 @@
+package service
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// checkState returns StateUnavailableError if the world state of the result
+// is not in the database. States of old blocks may be missing if the chain
+// started from the state of a later block by the state sync.
+func (m *manager) checkState(result []byte) error {
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return InvalidResultError.Wrap(err, "InvalidResult")
+	}
+	if len(tr.StateHash) == 0 {
+		return nil
+	}
+	bk, err := m.db.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	if has, err := bk.Has(tr.StateHash); err != nil {
+		return err
+	} else if !has {
+		return StateUnavailableError.Errorf("StateUnavailable(hash=%#x)", tr.StateHash)
+	}
+	return nil
+}
+
+// CheckStateOf returns StateUnavailableError if the world state of the
+// result can't be queried with the service manager.
+func CheckStateOf(sm module.ServiceManager, result []byte) error {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return errors.UnsupportedError.Errorf("NoStateCheck(sm=%T)", sm)
+	}
+	return mgr.checkState(result)
+}
+
+// StateRangeOf returns the range of heights of blocks from the base to the
+// last, whose world states can be queried. Missing states are only for
+// blocks lower than others, so it finds the lowest one with the state by
+// binary search. It returns StateUnavailableError if no block has the
+// state.
+func StateRangeOf(sm module.ServiceManager, bm module.BlockManager, base int64) (int64, int64, error) {
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	check := func(height int64) (bool, error) {
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			return false, err
+		}
+		if err := CheckStateOf(sm, blk.Result()); err != nil {
+			if StateUnavailableError.Equals(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	if ok, err := check(last.Height()); err != nil {
+		return 0, 0, err
+	} else if !ok {
+		return 0, 0, StateUnavailableError.Errorf(
+			"StateUnavailable(height=%d)", last.Height())
+	}
+	low, high := base, last.Height()
+	for low < high {
+		mid := low + (high-low)/2
+		if ok, err := check(mid); err != nil {
+			return 0, 0, err
+		} else if ok {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, last.Height(), nil
+}
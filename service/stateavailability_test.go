@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type testStateBlock struct {
+	module.Block
+	height int64
+	result []byte
+}
+
+func (b *testStateBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testStateBlock) Result() []byte {
+	return b.result
+}
+
+type testStateBlockManager struct {
+	module.BlockManager
+	blocks []module.Block
+}
+
+func (bm *testStateBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.blocks[len(bm.blocks)-1], nil
+}
+
+func (bm *testStateBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height < 0 || height >= int64(len(bm.blocks)) {
+		return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+	}
+	return bm.blocks[height], nil
+}
+
+func TestStateRangeOf(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, _ := dbase.GetBucket(db.MerkleTrie)
+	mgr := &manager{db: dbase}
+
+	// states of blocks lower than 3 are missing
+	bm := &testStateBlockManager{}
+	for i := 0; i < 6; i++ {
+		node := []byte{byte(i)}
+		hash := crypto.SHA3Sum256(node)
+		if i >= 3 {
+			assert.NoError(t, bk.Set(hash, node))
+		}
+		tr := &transitionResult{StateHash: hash}
+		bm.blocks = append(bm.blocks, &testStateBlock{height: int64(i), result: tr.Bytes()})
+	}
+
+	assert.True(t, StateUnavailableError.Equals(CheckStateOf(mgr, bm.blocks[2].Result())))
+	assert.NoError(t, CheckStateOf(mgr, bm.blocks[3].Result()))
+	assert.NoError(t, CheckStateOf(mgr, nil))
+
+	start, end, err := StateRangeOf(mgr, bm, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, start)
+	assert.EqualValues(t, 5, end)
+
+	// base is higher than the lowest one with the state
+	start, _, err = StateRangeOf(mgr, bm, 4)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, start)
+
+	bm.blocks = bm.blocks[:3]
+	_, _, err = StateRangeOf(mgr, bm, 0)
+	assert.True(t, StateUnavailableError.Equals(err))
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// stateTraceWorldState reports accesses to the storage of the accounts
+// to the callback.
+type stateTraceWorldState struct {
+	state.WorldState
+	cb module.StateTraceCallback
+}
+
+func (ws *stateTraceWorldState) GetAccountState(id []byte) state.AccountState {
+	as := ws.WorldState.GetAccountState(id)
+	if as == nil {
+		return nil
+	}
+	return &stateTraceAccountState{
+		AccountState: as,
+		id:           id,
+		cb:           ws.cb,
+	}
+}
+
+type stateTraceAccountState struct {
+	state.AccountState
+	id []byte
+	cb module.StateTraceCallback
+}
+
+func (as *stateTraceAccountState) address() module.Address {
+	if bytes.Equal(as.id, state.SystemID) {
+		return state.SystemAddress
+	}
+	return common.NewAddressWithTypeAndID(as.IsContract(), as.id)
+}
+
+func (as *stateTraceAccountState) GetValue(k []byte) ([]byte, error) {
+	v, err := as.AccountState.GetValue(k)
+	if err == nil {
+		_ = as.cb.OnStateRead(as.address(), k, v)
+	}
+	return v, err
+}
+
+func (as *stateTraceAccountState) SetValue(k, v []byte) ([]byte, error) {
+	old, err := as.AccountState.SetValue(k, v)
+	if err == nil {
+		_ = as.cb.OnStateWrite(as.address(), k, old, v)
+	}
+	return old, err
+}
+
+func (as *stateTraceAccountState) DeleteValue(k []byte) ([]byte, error) {
+	old, err := as.AccountState.DeleteValue(k)
+	if err == nil && old != nil {
+		_ = as.cb.OnStateWrite(as.address(), k, old, nil)
+	}
+	return old, err
+}
+
+// newStateTraceWorldState returns ws reporting accesses to the storage if
+// the trace requires them.
+func newStateTraceWorldState(ws state.WorldState, ti *module.TraceInfo) state.WorldState {
+	if ti == nil || (ti.TraceMode != module.TraceModeStateDiff &&
+		ti.TraceMode != module.TraceModeDebug) {
+		return ws
+	}
+	if cb, ok := ti.Callback.(module.StateTraceCallback); ok {
+		return &stateTraceWorldState{WorldState: ws, cb: cb}
+	}
+	return ws
+}
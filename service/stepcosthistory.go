@@ -0,0 +1,136 @@
+package service
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	keyStepCostHistory = "step_cost_history"
+
+	// StepCostTypePrice is the type of StepCostChange for the step price.
+	StepCostTypePrice = "stepPrice"
+)
+
+// StepCostChange is a change of the step price or a step cost. The new
+// value is applied to transactions following the transaction(TxHash) in
+// the block(Height). TxHash is nil if the value is changed by the platform
+// at the beginning or the end of the block.
+type StepCostChange struct {
+	Type   string
+	Value  *big.Int
+	Height int64
+	TxHash []byte
+}
+
+func stepCostsOf(ass state.AccountSnapshot) map[string]*big.Int {
+	costs := make(map[string]*big.Int)
+	if ass == nil {
+		return costs
+	}
+	sys := scoredb.NewStateStoreWith(ass)
+	if price := scoredb.NewVarDB(sys, state.VarStepPrice).BigInt(); price != nil {
+		costs[StepCostTypePrice] = price
+	}
+	stepTypes := scoredb.NewArrayDB(sys, state.VarStepTypes)
+	stepCostDB := scoredb.NewDictDB(sys, state.VarStepCosts, 1)
+	for i := 0; i < stepTypes.Size(); i++ {
+		name := stepTypes.Get(i).String()
+		if v := stepCostDB.Get(name); v != nil {
+			costs[name] = v.BigInt()
+		}
+	}
+	return costs
+}
+
+// stepCostChangesOf returns changes of step costs between the snapshots of
+// the system account.
+func stepCostChangesOf(before, after state.AccountSnapshot) []*StepCostChange {
+	if after == nil || (before != nil && !after.StorageChangedAfter(before)) {
+		return nil
+	}
+	oldCosts := stepCostsOf(before)
+	newCosts := stepCostsOf(after)
+	var changes []*StepCostChange
+	for name, value := range newCosts {
+		if old, ok := oldCosts[name]; ok && old.Cmp(value) == 0 {
+			continue
+		}
+		changes = append(changes, &StepCostChange{
+			Type:  name,
+			Value: value,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Type < changes[j].Type
+	})
+	return changes
+}
+
+func loadStepCostHistory(dbase db.Database) ([]*StepCostChange, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get([]byte(keyStepCostHistory))
+	if err != nil {
+		return nil, err
+	}
+	var history []*StepCostChange
+	if bs == nil {
+		return history, nil
+	}
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// storeStepCostChanges appends the changes of the block at the height to
+// the history. Changes recorded for the height or higher are replaced,
+// so that it's safe to store the changes of the block executed again.
+func storeStepCostChanges(dbase db.Database, height int64, changes []*StepCostChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	history, err := loadStepCostHistory(dbase)
+	if err != nil {
+		return err
+	}
+	idx := sort.Search(len(history), func(i int) bool {
+		return history[i].Height >= height
+	})
+	history = append(history[:idx], changes...)
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(keyStepCostHistory), codec.BC.MustMarshalToBytes(history))
+}
+
+// GetStepCostHistory returns changes of the step costs and the step price
+// in order of height. If typ is not empty, then it returns the changes of
+// the type only. The history is recorded while the node executes blocks,
+// so it doesn't include changes of the blocks synchronized from others.
+func GetStepCostHistory(dbase db.Database, typ string, from, to int64) ([]*StepCostChange, error) {
+	history, err := loadStepCostHistory(dbase)
+	if err != nil {
+		return nil, err
+	}
+	changes := []*StepCostChange{}
+	for _, c := range history {
+		if typ != "" && c.Type != typ {
+			continue
+		}
+		if c.Height < from || (to > 0 && c.Height > to) {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestStepCostChangesOf(t *testing.T) {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(10))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeDefault))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepCosts, 1).Set(state.StepTypeDefault, 100))
+	before := sys.GetSnapshot()
+
+	assert.Empty(t, stepCostChangesOf(before, sys.GetSnapshot()))
+
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(20))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeInput))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepCosts, 1).Set(state.StepTypeInput, 200))
+	changes := stepCostChangesOf(before, sys.GetSnapshot())
+	assert.Equal(t, []*StepCostChange{
+		{Type: state.StepTypeInput, Value: big.NewInt(200)},
+		{Type: StepCostTypePrice, Value: big.NewInt(20)},
+	}, changes)
+}
+
+func TestStepCostHistory(t *testing.T) {
+	dbase := db.NewMapDB()
+
+	changes, err := GetStepCostHistory(dbase, "", 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	c1 := &StepCostChange{Type: StepCostTypePrice, Value: big.NewInt(10), Height: 1}
+	c2 := &StepCostChange{Type: state.StepTypeDefault, Value: big.NewInt(100), Height: 5, TxHash: []byte{0x01}}
+	c3 := &StepCostChange{Type: StepCostTypePrice, Value: big.NewInt(20), Height: 5, TxHash: []byte{0x01}}
+	assert.NoError(t, storeStepCostChanges(dbase, 1, []*StepCostChange{c1}))
+	assert.NoError(t, storeStepCostChanges(dbase, 5, []*StepCostChange{c2, c3}))
+	// executing the block again replaces the changes of the block
+	assert.NoError(t, storeStepCostChanges(dbase, 5, []*StepCostChange{c2, c3}))
+
+	changes, err = GetStepCostHistory(dbase, "", 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*StepCostChange{c1, c2, c3}, changes)
+
+	changes, err = GetStepCostHistory(dbase, StepCostTypePrice, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*StepCostChange{c1, c3}, changes)
+
+	changes, err = GetStepCostHistory(dbase, "", 2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []*StepCostChange{c2, c3}, changes)
+
+	changes, err = GetStepCostHistory(dbase, "", 0, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []*StepCostChange{c1}, changes)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	stepLimitHistory = 100
+
+	// configMinStepBlocks is the number of blocks with transactions
+	// required for the suggestion.
+	configMinStepBlocks = 10
+
+	// configStepLimitTargetRatio is the ratio of the transaction timeout
+	// for execution of a transaction using the maximum step limit.
+	configStepLimitTargetRatio = 0.5
+)
+
+type stepExecution struct {
+	height   int64
+	steps    int64
+	duration time.Duration
+}
+
+// StepLimitSuggestion is the maximum step limit for invoke suggested from
+// the throughput of the execution of recent blocks.
+type StepLimitSuggestion struct {
+	From, To       int64
+	Blocks         int
+	Steps          int64
+	Duration       time.Duration
+	StepsPerSecond int64
+	Target         time.Duration
+	Current        *big.Int
+	Suggested      *big.Int
+}
+
+// StepLimitAdvisor measures steps used and time spent for executing
+// transactions of recent blocks, and it suggests the maximum step limit
+// for invoke with which a transaction can be executed in a half of the
+// transaction timeout.
+type StepLimitAdvisor struct {
+	metric  *metric.StepLimitMetric
+	timeout func() time.Duration
+
+	lock    sync.Mutex
+	history [stepLimitHistory]stepExecution
+	index   int
+	count   int
+	current *big.Int
+}
+
+func NewStepLimitAdvisor(ctx context.Context, timeout func() time.Duration) *StepLimitAdvisor {
+	return &StepLimitAdvisor{
+		metric:  metric.NewStepLimitMetric(ctx),
+		timeout: timeout,
+	}
+}
+
+// onExecution records steps used by the transactions of the block and
+// the time spent for them with the current maximum step limit for invoke.
+func (a *StepLimitAdvisor) onExecution(height int64, steps *big.Int, d time.Duration, limit *big.Int) {
+	if steps.Sign() <= 0 || !steps.IsInt64() || d <= 0 {
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.history[a.index] = stepExecution{height, steps.Int64(), d}
+	a.index = (a.index + 1) % len(a.history)
+	if a.count < len(a.history) {
+		a.count += 1
+	}
+	a.current = limit
+
+	if s, err := a.suggestInLock(); err == nil && s.Suggested.IsInt64() {
+		a.metric.OnSuggestion(s.StepsPerSecond, s.Suggested.Int64())
+	}
+}
+
+func (a *StepLimitAdvisor) suggestInLock() (*StepLimitSuggestion, error) {
+	if a.count < configMinStepBlocks {
+		return nil, errors.NotFoundError.Errorf(
+			"NotEnoughBlocks(blocks=%d,required=%d)", a.count, configMinStepBlocks)
+	}
+	s := &StepLimitSuggestion{
+		Blocks:  a.count,
+		Current: a.current,
+	}
+	for i := 0; i < a.count; i++ {
+		e := &a.history[i]
+		if s.From == 0 || e.height < s.From {
+			s.From = e.height
+		}
+		if e.height > s.To {
+			s.To = e.height
+		}
+		s.Steps += e.steps
+		s.Duration += e.duration
+	}
+	sps := new(big.Int).Mul(big.NewInt(s.Steps), big.NewInt(int64(time.Second)))
+	sps.Div(sps, big.NewInt(int64(s.Duration)))
+	s.StepsPerSecond = sps.Int64()
+
+	s.Target = time.Duration(float64(a.timeout()) * configStepLimitTargetRatio)
+	limit := new(big.Int).Mul(sps, big.NewInt(int64(s.Target)))
+	limit.Div(limit, big.NewInt(int64(time.Second)))
+	s.Suggested = roundDownToSignificant(limit, 2)
+	return s, nil
+}
+
+// roundDownToSignificant rounds down the value to the number of
+// significant digits.
+func roundDownToSignificant(v *big.Int, digits int) *big.Int {
+	n := len(v.String()) - digits
+	if n <= 0 {
+		return v
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	r := new(big.Int).Div(v, unit)
+	return r.Mul(r, unit)
+}
+
+// Suggest returns the maximum step limit for invoke suggested from recent
+// blocks. It returns NotFoundError if there are not enough blocks.
+func (a *StepLimitAdvisor) Suggest() (*StepLimitSuggestion, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.suggestInLock()
+}
+
+// StepLimitAdvisorOf returns the step limit advisor of the service
+// manager.
+func StepLimitAdvisorOf(sm module.ServiceManager) (*StepLimitAdvisor, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoStepLimitAdvisor(sm=%T)", sm)
+	}
+	return mgr.sla, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestStepLimitAdvisor_Suggest(t *testing.T) {
+	a := NewStepLimitAdvisor(context.Background(), func() time.Duration {
+		return 5 * time.Second
+	})
+	limit := big.NewInt(2_500_000_000)
+
+	a.onExecution(1, big.NewInt(1_000_000), 100*time.Millisecond, limit)
+	_, err := a.Suggest()
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// blocks without steps are ignored
+	a.onExecution(2, big.NewInt(0), 100*time.Millisecond, limit)
+
+	for h := int64(3); h < 3+configMinStepBlocks; h++ {
+		a.onExecution(h, big.NewInt(3_000_000), 100*time.Millisecond, limit)
+	}
+	s, err := a.Suggest()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, s.From)
+	assert.EqualValues(t, 12, s.To)
+	assert.Equal(t, configMinStepBlocks+1, s.Blocks)
+	assert.EqualValues(t, 31_000_000, s.Steps)
+	assert.EqualValues(t, 28_181_818, s.StepsPerSecond)
+	assert.Equal(t, 2500*time.Millisecond, s.Target)
+	assert.Equal(t, 0, limit.Cmp(s.Current))
+	assert.EqualValues(t, 70_000_000, s.Suggested.Int64())
+
+	for h := int64(13); h < 13+stepLimitHistory; h++ {
+		a.onExecution(h, big.NewInt(1_000_000), 100*time.Millisecond, limit)
+	}
+	s, err = a.Suggest()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 13, s.From)
+	assert.Equal(t, stepLimitHistory, s.Blocks)
+	assert.EqualValues(t, 10_000_000, s.StepsPerSecond)
+	assert.EqualValues(t, 25_000_000, s.Suggested.Int64())
+}
+
+func TestRoundDownToSignificant(t *testing.T) {
+	assert.EqualValues(t, 7, roundDownToSignificant(big.NewInt(7), 2).Int64())
+	assert.EqualValues(t, 12, roundDownToSignificant(big.NewInt(12), 2).Int64())
+	assert.EqualValues(t, 120, roundDownToSignificant(big.NewInt(129), 2).Int64())
+	assert.EqualValues(t, 98_000_000, roundDownToSignificant(big.NewInt(98_765_432), 2).Int64())
+}
@@ -40,6 +40,7 @@ type SyncReactor interface {
 	GetVersion() byte
 	WatchPeers(watcher PeerWatcher) []*peer
 	UnwatchPeers(watcher PeerWatcher) bool
+	setServing(serving bool)
 }
 
 type Platform interface {
@@ -75,6 +76,13 @@ func (m *Manager) UnresolvedRequestCount() int {
 	return m.ds.UnresolvedCount()
 }
 
+// SetServing starts or stops serving the state data to the peers.
+func (m *Manager) SetServing(serving bool) {
+	for _, r := range m.reactors {
+		r.setServing(serving)
+	}
+}
+
 func (m *Manager) Start() {
 	m.ds.Start()
 }
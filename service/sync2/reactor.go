@@ -4,6 +4,7 @@ package sync2
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
@@ -32,6 +33,9 @@ type ReactorCommon struct {
 	readyPool *peerPool
 	watchers  []PeerWatcher
 	sender    DataSender
+
+	// paused is set to 1 while it doesn't serve the requests of the peers.
+	paused int32
 }
 
 func (r *ReactorCommon) OnJoin(id module.PeerID) {
@@ -68,6 +72,18 @@ func (r *ReactorCommon) OnLeave(id module.PeerID) {
 	})
 }
 
+func (r *ReactorCommon) setServing(serving bool) {
+	var paused int32
+	if !serving {
+		paused = 1
+	}
+	atomic.StoreInt32(&r.paused, paused)
+}
+
+func (r *ReactorCommon) isServing() bool {
+	return atomic.LoadInt32(&r.paused) == 0
+}
+
 func (r *ReactorCommon) GetVersion() byte {
 	return r.version
 }
@@ -129,6 +145,10 @@ func (r *ReactorV1) hasNode(msg []byte, id module.PeerID) *result {
 		return nil
 	}
 
+	if !r.isServing() {
+		return &result{hr.ReqID, ErrNoData}
+	}
+
 	status := NoError
 	for _, hash := range [][]byte{hr.StateHash, hr.PatchHash, hr.NormalHash} {
 		if len(hash) == 0 {
@@ -182,6 +202,9 @@ func (r *ReactorV1) requestNode(msg []byte, id module.PeerID) *nodeData {
 	}
 
 	r.logger.Tracef("requestNode() request data reqID=%d, dataLen=%d", req.ReqID, len(req.Hashes))
+	if !r.isServing() {
+		return &nodeData{req.ReqID, ErrNoData, req.Type, nil}
+	}
 	status, values := r._resolveNode(req.Hashes)
 	r.logger.Tracef("requestNode() response data dataLen=%d, status=%d, peer=%s", len(values), status, id)
 	res := &nodeData{req.ReqID, status, req.Type, values}
@@ -66,6 +66,9 @@ func (r *ReactorV2) request(msg []byte, id module.PeerID) *responseData {
 	}
 
 	r.logger.Tracef("request() requestData reqID=%d, dataLen=%d", req.ReqID, len(req.Data))
+	if !r.isServing() {
+		return &responseData{req.ReqID, ErrNoData, nil}
+	}
 	status, data := r._resolveData(req.Data)
 	r.logger.Tracef("request() responseData dataLen=%d, status=%d, peer=%v", len(data), status, id)
 	res := &responseData{req.ReqID, status, data}
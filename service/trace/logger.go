@@ -209,6 +209,18 @@ func (l *Logger) OnFrameExit(success bool, stepUsed *big.Int) {
 	}
 }
 
+func (l *Logger) OnContractEnter(addr module.Address, method string) {
+	if l.TraceMode() != module.TraceModeDebug {
+		return
+	}
+	if cb, ok := l.cb.(module.DebugTraceCallback); ok {
+		if err := cb.OnContractEnter(addr, method); err != nil {
+			l.Warnf("OnContractEnter() error: addr=%s method=%s err=%#v",
+				addr, method, err)
+		}
+	}
+}
+
 func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) {
 	if l.TraceMode() == module.TraceModeNone {
 		return
@@ -0,0 +1,227 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type stateWrite struct {
+	address module.Address
+	key     []byte
+	before  []byte
+	after   []byte
+}
+
+type stateFrame struct {
+	parent *stateFrame
+	depth  int
+	writes []*stateWrite
+}
+
+func (f *stateFrame) mergeWritesToParent() {
+	if f.parent == nil {
+		return
+	}
+	f.parent.writes = append(f.parent.writes, f.writes...)
+}
+
+type stateRead struct {
+	address module.Address
+	key     []byte
+	value   []byte
+}
+
+func stateKeyOf(addr module.Address, key []byte) string {
+	return string(addr.Bytes()) + string(key)
+}
+
+func bytesToJSON(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// StateTracer records the storage keys read and written by a transaction.
+// Writes in the frames failed are discarded as they are reverted.
+type StateTracer struct {
+	txIndex   int
+	txHash    []byte
+	isBlockTx bool
+	reads     []*stateRead
+	readKeys  map[string]bool
+	curFrame  *stateFrame
+	root      *stateFrame
+}
+
+func (st *StateTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if st.curFrame != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curFrame: txIndex=%d txHash=%#x", txIndex, txHash)
+	}
+	st.txIndex = txIndex
+	st.txHash = txHash
+	st.isBlockTx = isBlockTx
+	return st.OnTransactionReset()
+}
+
+func (st *StateTracer) OnTransactionReset() error {
+	st.reads = nil
+	st.readKeys = make(map[string]bool)
+	st.root = &stateFrame{}
+	st.curFrame = st.root
+	return nil
+}
+
+func (st *StateTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	if st.curFrame == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	if st.txIndex != txIndex || !bytes.Equal(st.txHash, txHash) {
+		return errors.InvalidStateError.Errorf(
+			"Invalid txHash: curTxHash=%#x hash=%#x", st.txHash, txHash)
+	}
+	if depth := st.curFrame.depth; depth != 0 {
+		return errors.InvalidStateError.Errorf("Invalid callFrame depth: %d", depth)
+	}
+	st.curFrame = nil
+	return nil
+}
+
+func (st *StateTracer) OnFrameEnter() error {
+	if st.curFrame == nil {
+		return errors.InvalidStateError.New("StateTracer Not Ready")
+	}
+	st.curFrame = &stateFrame{
+		parent: st.curFrame,
+		depth:  st.curFrame.depth + 1,
+	}
+	return nil
+}
+
+func (st *StateTracer) OnFrameExit(success bool) error {
+	curFrame := st.curFrame
+	if curFrame == nil {
+		return errors.InvalidStateError.New("curFrame Not Ready")
+	}
+	if curFrame.depth <= 0 {
+		return errors.InvalidStateError.Errorf("Invalid frameDepth: %d", curFrame.depth)
+	}
+	if success {
+		curFrame.mergeWritesToParent()
+	}
+	st.curFrame = curFrame.parent
+	return nil
+}
+
+// OnStateRead records the value of the key read first in the transaction.
+// Accesses out of the transaction are ignored.
+func (st *StateTracer) OnStateRead(addr module.Address, key, value []byte) error {
+	if st.curFrame == nil {
+		return nil
+	}
+	sk := stateKeyOf(addr, key)
+	if st.readKeys[sk] {
+		return nil
+	}
+	st.readKeys[sk] = true
+	st.reads = append(st.reads, &stateRead{
+		address: addr,
+		key:     key,
+		value:   value,
+	})
+	return nil
+}
+
+// OnStateWrite records the change of the key in the current frame.
+// Accesses out of the transaction are ignored.
+func (st *StateTracer) OnStateWrite(addr module.Address, key, before, after []byte) error {
+	if st.curFrame == nil {
+		return nil
+	}
+	st.curFrame.writes = append(st.curFrame.writes, &stateWrite{
+		address: addr,
+		key:     key,
+		before:  before,
+		after:   after,
+	})
+	return nil
+}
+
+// ToJSON returns the keys read and written by the transaction. Multiple
+// writes on a key are merged into one with the value before the first write
+// and the value after the last write. If maxEntries is positive, reads and
+// writes are limited to maxEntries each. If redact is true, values are
+// omitted and only the keys are returned.
+func (st *StateTracer) ToJSON(maxEntries int, redact bool) map[string]interface{} {
+	if st.root == nil {
+		return nil
+	}
+	truncated := false
+	sr := st.reads
+	if maxEntries > 0 && len(sr) > maxEntries {
+		sr = sr[:maxEntries]
+		truncated = true
+	}
+	reads := make([]interface{}, 0, len(sr))
+	for _, r := range sr {
+		jso := map[string]interface{}{
+			"address": r.address,
+			"key":     bytesToJSON(r.key),
+		}
+		if !redact {
+			jso["value"] = bytesToJSON(r.value)
+		}
+		reads = append(reads, jso)
+	}
+	var merged []*stateWrite
+	idxOf := make(map[string]int)
+	for _, w := range st.root.writes {
+		sk := stateKeyOf(w.address, w.key)
+		if idx, ok := idxOf[sk]; ok {
+			merged[idx].after = w.after
+		} else {
+			idxOf[sk] = len(merged)
+			wc := *w
+			merged = append(merged, &wc)
+		}
+	}
+	if maxEntries > 0 && len(merged) > maxEntries {
+		merged = merged[:maxEntries]
+		truncated = true
+	}
+	writes := make([]interface{}, 0, len(merged))
+	for _, w := range merged {
+		jso := map[string]interface{}{
+			"address": w.address,
+			"key":     bytesToJSON(w.key),
+		}
+		if !redact {
+			jso["before"] = bytesToJSON(w.before)
+			jso["after"] = bytesToJSON(w.after)
+		}
+		writes = append(writes, jso)
+	}
+	prefix := "0x"
+	if st.isBlockTx {
+		prefix = "bx"
+	}
+	result := map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", st.txIndex),
+		"txHash":  prefix + hex.EncodeToString(st.txHash),
+		"reads":   reads,
+		"writes":  writes,
+	}
+	if truncated {
+		result["truncated"] = true
+	}
+	return result
+}
+
+func NewStateTracer() *StateTracer {
+	return &StateTracer{}
+}
@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestStateTracer(t *testing.T) {
+	st := NewStateTracer()
+	score := common.MustNewAddressFromString("cx101")
+	k1 := []byte{0x01}
+	k2 := []byte{0x02}
+	k3 := []byte{0x03}
+
+	// accesses out of the transaction are ignored
+	assert.NoError(t, st.OnStateWrite(score, k1, nil, []byte{0x09}))
+	assert.Nil(t, st.ToJSON(0, false))
+
+	txHash := newRandomHash(32)
+	assert.NoError(t, st.OnTransactionStart(1, txHash, false))
+
+	assert.NoError(t, st.OnStateRead(score, k1, []byte{0x10}))
+	assert.NoError(t, st.OnStateWrite(score, k1, []byte{0x10}, []byte{0x11}))
+	assert.NoError(t, st.OnStateRead(score, k1, []byte{0x11}))
+
+	// writes in the succeeded frame
+	assert.NoError(t, st.OnFrameEnter())
+	assert.NoError(t, st.OnStateWrite(score, k1, []byte{0x11}, []byte{0x12}))
+	assert.NoError(t, st.OnStateWrite(score, k2, nil, []byte{0x20}))
+	assert.NoError(t, st.OnFrameExit(true))
+
+	// writes in the failed frame
+	assert.NoError(t, st.OnFrameEnter())
+	assert.NoError(t, st.OnStateRead(score, k3, []byte{0x30}))
+	assert.NoError(t, st.OnStateWrite(score, k3, []byte{0x30}, nil))
+	assert.NoError(t, st.OnFrameExit(false))
+
+	assert.NoError(t, st.OnTransactionEnd(1, txHash))
+	assert.NoError(t, st.OnStateWrite(score, k3, []byte{0x30}, nil))
+
+	jso := st.ToJSON(0, false)
+	assert.Equal(t, "0x1", jso["txIndex"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x01",
+			"value":   "0x10",
+		},
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x03",
+			"value":   "0x30",
+		},
+	}, jso["reads"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x01",
+			"before":  "0x10",
+			"after":   "0x12",
+		},
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x02",
+			"before":  nil,
+			"after":   "0x20",
+		},
+	}, jso["writes"])
+
+	// limited and redacted
+	jso = st.ToJSON(1, true)
+	assert.Equal(t, true, jso["truncated"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x01",
+		},
+	}, jso["reads"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"address": score,
+			"key":     "0x01",
+		},
+	}, jso["writes"])
+}
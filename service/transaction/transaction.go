@@ -28,6 +28,15 @@ type Transaction interface {
 	IsSkippable() bool
 }
 
+// Validator is implemented by the transaction reporting all the violations
+// at once instead of the first one.
+type Validator interface {
+	// VerifyAll returns all the violations checked by Verify.
+	VerifyAll() []error
+	// PreValidateAll returns all the violations checked by PreValidate.
+	PreValidateAll(wc state.WorldContext) []error
+}
+
 type GenesisTransaction interface {
 	Transaction
 	CID() int
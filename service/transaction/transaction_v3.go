@@ -156,7 +156,7 @@ func (tx *transactionV3) Version() int {
 	return module.TransactionVersion3
 }
 
-func (tx *transactionV3) Verify() error {
+func (tx *transactionV3) verifyFields() error {
 	// value >= 0
 	if tx.Value != nil && tx.Value.Sign() < 0 {
 		return InvalidTxValue.Errorf("InvalidTxValue(%s)", tx.Value.String())
@@ -213,14 +213,31 @@ func (tx *transactionV3) Verify() error {
 		}
 	}
 
+	return nil
+}
+
+func (tx *transactionV3) Verify() error {
+	if err := tx.verifyFields(); err != nil {
+		return err
+	}
 	// signature verification
 	if err := tx.verifySignature(); err != nil {
 		return err
 	}
-
 	return nil
 }
 
+func (tx *transactionV3) VerifyAll() []error {
+	var errs []error
+	if err := tx.verifyFields(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := tx.verifySignature(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 func (tx *transactionV3) ValidateNetwork(nid int) bool {
 	if tx.NID == nil {
 		return true
@@ -228,7 +245,7 @@ func (tx *transactionV3) ValidateNetwork(nid int) bool {
 	return int(tx.NID.Value) == nid
 }
 
-func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
+func (tx *transactionV3) validateStepLimit(wc state.WorldContext) error {
 	if tx.DataType == nil || *tx.DataType != contract.DataTypePatch {
 		// stepLimit >= default step + input steps
 		cnt, err := MeasureBytesOfData(wc.Revision(), tx.Data)
@@ -240,34 +257,58 @@ func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
 			return NotEnoughStepError.Errorf("NotEnoughStep(txStepLimit:%s, minStep:%s)", &tx.StepLimit.Int, minStep)
 		}
 	}
+	return nil
+}
 
-	// balance >= (fee + value)
-	stepPrice := wc.StepPrice()
-
-	trans := new(big.Int).Mul(&tx.StepLimit.Int, stepPrice)
+// transferAmount returns the amount to be transferred from the sender
+// including the maximum fee.
+func (tx *transactionV3) transferAmount(wc state.WorldContext) *big.Int {
+	trans := new(big.Int).Mul(&tx.StepLimit.Int, wc.StepPrice())
 	if tx.Value != nil {
 		trans.Add(trans, &tx.Value.Int)
 	}
+	return trans
+}
 
-	as1 := wc.GetAccountState(tx.From().ID())
-	balance1 := as1.GetBalance()
+func (tx *transactionV3) validateBalance(wc state.WorldContext) error {
+	// balance >= (fee + value)
+	trans := tx.transferAmount(wc)
+	balance1 := wc.GetAccountState(tx.From().ID()).GetBalance()
 	if balance1.Cmp(trans) < 0 {
 		return NotEnoughBalanceError.Errorf("OutOfBalance(balance:%s, value:%s)", balance1, trans)
 	}
+	return nil
+}
 
-	if as1.IsBlocked() {
+func (tx *transactionV3) validateAccounts(wc state.WorldContext) error {
+	if wc.GetAccountState(tx.From().ID()).IsBlocked() {
 		return AccessDeniedError.New("BlockedAccount")
 	}
-
-	as2 := wc.GetAccountState(tx.To().ID())
 	if contract.IsCallableDataType(tx.DataType) {
-		if !as2.CanAcceptTx(wc) {
+		if !wc.GetAccountState(tx.To().ID()).CanAcceptTx(wc) {
 			return ContractNotUsable.New("NotAcceptable")
 		}
 	}
+	return nil
+}
+
+func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
+	if err := tx.validateStepLimit(wc); err != nil {
+		return err
+	}
+	if err := tx.validateBalance(wc); err != nil {
+		return err
+	}
+	if err := tx.validateAccounts(wc); err != nil {
+		return err
+	}
 
 	// for cumulative balance check
 	if update {
+		trans := tx.transferAmount(wc)
+		as1 := wc.GetAccountState(tx.From().ID())
+		balance1 := as1.GetBalance()
+		as2 := wc.GetAccountState(tx.To().ID())
 		as1.SetBalance(new(big.Int).Sub(balance1, trans))
 		if tx.Value != nil {
 			balance2 := as2.GetBalance()
@@ -277,6 +318,20 @@ func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
 	return nil
 }
 
+func (tx *transactionV3) PreValidateAll(wc state.WorldContext) []error {
+	var errs []error
+	for _, validate := range []func(state.WorldContext) error{
+		tx.validateStepLimit,
+		tx.validateBalance,
+		tx.validateAccounts,
+	} {
+		if err := validate(wc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 func (tx *transactionV3) GetHandler(cm contract.ContractManager) (Handler, error) {
 	var value *big.Int
 	if tx.Value != nil {
@@ -284,7 +339,7 @@ func (tx *transactionV3) GetHandler(cm contract.ContractManager) (Handler, error
 	} else {
 		value = big.NewInt(0)
 	}
-	return NewHandler(cm,
+	th, err := newTransactionHandler(cm,
 		tx.Group(),
 		tx.From(),
 		tx.To(),
@@ -292,6 +347,12 @@ func (tx *transactionV3) GetHandler(cm contract.ContractManager) (Handler, error
 		&tx.StepLimit.Int,
 		tx.DataType,
 		tx.Data)
+	if err != nil {
+		return nil, err
+	}
+	th.signature, _ = tx.Signature.MarshalBinary()
+	th.verifySignature = tx.verifySignature
+	return th, nil
 }
 
 func (tx *transactionV3) Group() module.TransactionGroup {
@@ -0,0 +1,34 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionV3_VerifyAll(t *testing.T) {
+	sig := make([]byte, 65)
+	sig[0] = 1
+	sig[32] = 1
+	js := `{
+		"version": "0x3",
+		"from": "hx0000000000000000000000000000000000000001",
+		"to": "hx0000000000000000000000000000000000000002",
+		"value": "-0x1",
+		"stepLimit": "0x100000",
+		"timestamp": "0x5f6d3b0a3f4c0",
+		"nid": "0x1",
+		"signature": "` + base64.StdEncoding.EncodeToString(sig) + `"
+	}`
+	tx, err := NewTransactionFromJSON([]byte(js))
+	assert.NoError(t, err)
+
+	v, ok := Unwrap(tx).(Validator)
+	assert.True(t, ok)
+	errs := v.VerifyAll()
+	assert.Len(t, errs, 2)
+	assert.True(t, InvalidTxValue.Equals(errs[0]))
+	assert.True(t, InvalidSignatureError.Equals(errs[1]))
+	assert.True(t, InvalidTxValue.Equals(tx.Verify()), "Verify returns the first one")
+}
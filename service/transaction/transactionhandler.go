@@ -31,14 +31,27 @@ type transactionHandler struct {
 	stepLimit *big.Int
 	dataType  *string
 	data      []byte
+	signature []byte
+
+	// verifySignature checks whether the transaction is signed by the
+	// sender. It's used only if the validator contract rejects it.
+	verifySignature func() error
 
 	chandler contract.ContractHandler
 
+	// unsigned is set if the validator contract rejects the transaction
+	// which isn't signed by the sender.
+	unsigned bool
+
 	// Assigned at Execute()
 	cc contract.CallContext
 }
 
 func NewHandler(cm contract.ContractManager, group module.TransactionGroup, from, to module.Address, value, stepLimit *big.Int, dataType *string, data []byte) (Handler, error) {
+	return newTransactionHandler(cm, group, from, to, value, stepLimit, dataType, data)
+}
+
+func newTransactionHandler(cm contract.ContractManager, group module.TransactionGroup, from, to module.Address, value, stepLimit *big.Int, dataType *string, data []byte) (*transactionHandler, error) {
 	th := &transactionHandler{
 		group:     group,
 		from:      from,
@@ -77,6 +90,13 @@ func NewHandler(cm contract.ContractManager, group module.TransactionGroup, from
 }
 
 func (th *transactionHandler) Prepare(ctx contract.Context) (state.WorldContext, error) {
+	if th.group == module.TransactionGroupNormal && state.TxValidatorOf(ctx, th.from) != nil {
+		// the validator contract may access any account.
+		lq := []state.LockRequest{
+			{ID: state.WorldIDStr, Lock: state.AccountWriteLock},
+		}
+		return ctx.GetFuture(lq), nil
+	}
 	return th.chandler.Prepare(ctx)
 }
 
@@ -119,6 +139,41 @@ func (th *transactionHandler) checkBlocked(cc contract.CallContext) error {
 	return nil
 }
 
+// validateByContract calls the validator contract for the transaction.
+// It returns the status of the call with the error to re-run the transaction.
+func (th *transactionHandler) validateByContract(cc contract.CallContext, validator module.Address) (error, error) {
+	p := &validationParams{
+		from:      th.from,
+		to:        th.to,
+		value:     th.value,
+		stepLimit: th.stepLimit,
+		dataType:  th.dataType,
+		data:      th.data,
+		txHash:    cc.TransactionID(),
+		signature: th.signature,
+	}
+	data, err := p.callData(TxValidateMethod)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := cc.ContractManager().GetHandler(state.SystemAddress, validator,
+		big.NewInt(0), contract.CTypeCall, data)
+	if err != nil {
+		return scoreresult.AccessDeniedError.Wrap(err, "InvalidTxValidator"), nil
+	}
+	status, used, _, _ := cc.Call(handler, cc.StepAvailable())
+	cc.DeductSteps(used)
+	if code := errors.CodeOf(status); code == errors.ExecutionFailError ||
+		errors.IsCriticalCode(code) {
+		return nil, status
+	}
+	if status != nil {
+		return scoreresult.AccessDeniedError.Wrapf(status,
+			"RejectedByValidator(validator=%s)", validator), nil
+	}
+	return nil, nil
+}
+
 func (th *transactionHandler) DoExecute(cc contract.CallContext, estimate, isPatch bool) (
 	status error,
 	score module.Address,
@@ -128,6 +183,17 @@ func (th *transactionHandler) DoExecute(cc contract.CallContext, estimate, isPat
 		if err := th.checkBalance(cc); err != nil {
 			return err, nil, nil
 		}
+		// steps used by the validator are charged like the transaction.
+		if validator := state.TxValidatorOf(cc, th.from); validator != nil {
+			if status, err := th.validateByContract(cc, validator); err != nil {
+				return nil, nil, err
+			} else if status != nil {
+				// the sender is charged only if it signed the transaction,
+				// otherwise anyone could spend the balance of the sender.
+				th.unsigned = th.verifySignature == nil || th.verifySignature() != nil
+				return status, nil, nil
+			}
+		}
 	}
 	if !cc.ApplySteps(state.StepTypeDefault, 1) {
 		return scoreresult.ErrOutOfStep, nil, nil
@@ -170,6 +236,7 @@ func (th *transactionHandler) Execute(ctx contract.Context, wcs state.WorldSnaps
 	// Set up
 	cc := contract.NewCallContext(ctx, limit, false)
 	th.cc = cc
+	th.unsigned = false
 	logger := cc.FrameLogger()
 	logger.TSystemf("TRANSACTION start from=%s to=%s id=%#x", th.from, th.to, th.cc.TransactionID())
 
@@ -189,6 +256,9 @@ func (th *transactionHandler) Execute(ctx contract.Context, wcs state.WorldSnaps
 	if isPatch {
 		stepPrice = new(big.Int)
 		logger.TSystem("TRANSACTION reset stepPrice=0 msg=\"patch tx\"")
+	} else if th.unsigned {
+		stepPrice = new(big.Int)
+		logger.TSystem("TRANSACTION reset stepPrice=0 msg=\"unsigned tx rejected by validator\"")
 	}
 	minSteps := big.NewInt(cc.StepsFor(state.StepTypeDefault, 1))
 	if stepUsed.Cmp(minSteps) == -1 {
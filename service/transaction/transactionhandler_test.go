@@ -0,0 +1,142 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transaction
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testContractManager struct {
+	contract.ContractManager
+}
+
+func (cm *testContractManager) GetHandler(from, to module.Address, value *big.Int, ctype int, data []byte) (contract.ContractHandler, error) {
+	return nil, nil
+}
+
+// testValidationCallContext returns the status for the call of the
+// validator contract.
+type testValidationCallContext struct {
+	contract.CallContext
+	ws     state.WorldState
+	status error
+}
+
+func (cc *testValidationCallContext) Revision() module.Revision {
+	return module.ContractTxValidation
+}
+
+func (cc *testValidationCallContext) StepPrice() *big.Int {
+	return big.NewInt(0)
+}
+
+func (cc *testValidationCallContext) GetAccountState(id []byte) state.AccountState {
+	return cc.ws.GetAccountState(id)
+}
+
+func (cc *testValidationCallContext) GetAccountSnapshot(id []byte) state.AccountSnapshot {
+	return cc.ws.GetAccountSnapshot(id)
+}
+
+func (cc *testValidationCallContext) ContractManager() contract.ContractManager {
+	return &testContractManager{}
+}
+
+func (cc *testValidationCallContext) TransactionID() []byte {
+	return []byte{0x01}
+}
+
+func (cc *testValidationCallContext) StepAvailable() *big.Int {
+	return big.NewInt(1000)
+}
+
+func (cc *testValidationCallContext) DeductSteps(s *big.Int) bool {
+	return true
+}
+
+func (cc *testValidationCallContext) Call(handler contract.ContractHandler, limit *big.Int) (error, *big.Int, *codec.TypedObj, module.Address) {
+	return cc.status, big.NewInt(0), nil, nil
+}
+
+func TestTransactionHandler_DoExecuteRejected(t *testing.T) {
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	to := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	validator := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	as := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarTxValidator).Set(validator))
+	assert.NoError(t, scoredb.NewDictDB(as, state.VarTxValidationAccounts, 1).Set(from, true))
+
+	cc := &testValidationCallContext{
+		ws:     ws,
+		status: scoreresult.ErrInvalidParameter,
+	}
+	th, err := newTransactionHandler(&testContractManager{}, module.TransactionGroupNormal,
+		from, to, big.NewInt(0), big.NewInt(100), nil, nil)
+	assert.NoError(t, err)
+
+	// the sender pays for the rejected transaction signed by itself
+	th.verifySignature = func() error { return nil }
+	status, _, err := th.DoExecute(cc, false, false)
+	assert.NoError(t, err)
+	assert.True(t, scoreresult.AccessDeniedError.Equals(status))
+	assert.False(t, th.unsigned)
+
+	// but not for one without its signature
+	th.verifySignature = func() error { return InvalidSignatureError.New("Forged") }
+	status, _, err = th.DoExecute(cc, false, false)
+	assert.NoError(t, err)
+	assert.True(t, scoreresult.AccessDeniedError.Equals(status))
+	assert.True(t, th.unsigned)
+}
+
+func TestTransactionV3_GetHandlerVerifySignature(t *testing.T) {
+	sig := make([]byte, 65)
+	sig[0] = 1
+	sig[32] = 1
+	js := `{
+		"version": "0x3",
+		"from": "hx0000000000000000000000000000000000000001",
+		"to": "hx0000000000000000000000000000000000000002",
+		"value": "0x1",
+		"stepLimit": "0x100000",
+		"timestamp": "0x5f6d3b0a3f4c0",
+		"nid": "0x1",
+		"signature": "` + base64.StdEncoding.EncodeToString(sig) + `"
+	}`
+	tx, err := NewTransactionFromJSON([]byte(js))
+	assert.NoError(t, err)
+
+	h, err := tx.GetHandler(&testContractManager{})
+	assert.NoError(t, err)
+	th := h.(*transactionHandler)
+	assert.True(t, InvalidSignatureError.Equals(th.verifySignature()))
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transaction
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// TxValidateMethod is the method of the validator contract called on
+	// the execution of the transaction. The transaction fails if the call
+	// fails. The fee for the steps used is charged only if the transaction
+	// is signed by the sender.
+	TxValidateMethod = "validateTransaction"
+
+	// TxCheckMethod is the read-only method of the validator contract
+	// queried for the transaction failing signature verification on pool
+	// admission. It should return true to accept the transaction.
+	TxCheckMethod = "checkTransaction"
+)
+
+type validationParams struct {
+	from      module.Address
+	to        module.Address
+	value     *big.Int
+	stepLimit *big.Int
+	dataType  *string
+	data      []byte
+	txHash    []byte
+	signature []byte
+}
+
+func (p *validationParams) callData(method string) ([]byte, error) {
+	params := map[string]interface{}{
+		"_from":     p.from.String(),
+		"to":        p.to.String(),
+		"value":     intconv.FormatBigInt(p.value),
+		"stepLimit": intconv.FormatBigInt(p.stepLimit),
+		"txHash":    "0x" + hex.EncodeToString(p.txHash),
+		"signature": "0x" + hex.EncodeToString(p.signature),
+	}
+	if p.dataType != nil {
+		params["dataType"] = *p.dataType
+	}
+	if p.data != nil {
+		params["data"] = "0x" + hex.EncodeToString(p.data)
+	}
+	return json.Marshal(map[string]interface{}{
+		"method": method,
+		"params": params,
+	})
+}
+
+// ValidationCallData returns the data calling the method of the validator
+// contract with the fields of the transaction.
+func ValidationCallData(method string, tx module.Transaction) ([]byte, error) {
+	tx3, ok := Unwrap(tx).(*transactionV3)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoValidationFor(%T)", tx)
+	}
+	sig, err := tx3.Signature.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	value := big.NewInt(0)
+	if tx3.Value != nil {
+		value = &tx3.Value.Int
+	}
+	p := &validationParams{
+		from:      tx3.From(),
+		to:        tx3.To(),
+		value:     value,
+		stepLimit: &tx3.StepLimit.Int,
+		dataType:  tx3.DataType,
+		data:      tx3.Data,
+		txHash:    tx3.TxHash(),
+		signature: sig,
+	}
+	return p.callData(method)
+}
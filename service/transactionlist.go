@@ -29,6 +29,9 @@ type txElement struct {
 	ts    int64
 	err   error
 
+	// sent is the time when the transaction is sent to peers last.
+	sent int64
+
 	list               *transactionList
 	listNext, listPrev *txElement
 	srcNext, srcPrev   *txElement
@@ -118,12 +121,14 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 		return ErrDuplicateTransaction
 	}
 
+	now := time.Now().UnixNano()
 	e := &txElement{
 		value: tx,
 		list:  l,
+		sent:  now,
 	}
 	if ts {
-		e.ts = time.Now().UnixNano()
+		e.ts = now
 	}
 
 	l.idMap[tidBk][tidSlot] = e
@@ -235,6 +240,19 @@ func (l *transactionList) Remove(t *txElement) bool {
 	return true
 }
 
+// TxsOf returns transactions from the address in order of timestamps.
+func (l *transactionList) TxsOf(from module.Address) []transaction.Transaction {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(from.ID()))
+	var txs []transaction.Transaction
+	for t := l.srcMapToLast[uidBk][uidSlot]; t != nil; t = t.srcPrev {
+		txs = append(txs, t.value)
+	}
+	for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+		txs[i], txs[j] = txs[j], txs[i]
+	}
+	return txs
+}
+
 func (l *transactionList) Front() *txElement {
 	return l.listFront
 }
@@ -249,6 +267,14 @@ func (l *transactionList) HasTx(id []byte) bool {
 	return ok
 }
 
+func (l *transactionList) GetTx(id []byte) transaction.Transaction {
+	tidBk, tidSlot := indexAndBucketKeyFromKey(string(id))
+	if e, ok := l.idMap[tidBk][tidSlot]; ok {
+		return e.value
+	}
+	return nil
+}
+
 func (l *transactionList) GetBloom() *TxBloom {
 	if l.listFront == nil {
 		return &TxBloom{}
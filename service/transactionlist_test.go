@@ -16,6 +16,7 @@ type mockTransaction struct {
 	id        []byte
 	from      module.Address
 	timeStamp int64
+	nonce     *big.Int
 }
 
 func (*mockTransaction) Group() module.TransactionGroup {
@@ -51,7 +52,7 @@ func (*mockTransaction) ToJSON(version module.JSONVersion) (interface{}, error)
 }
 
 func (*mockTransaction) PreValidate(wc state.WorldContext, update bool) error {
-	panic("implement me")
+	return nil
 }
 
 func (*mockTransaction) GetHandler(cm contract.ContractManager) (transaction.Handler, error) {
@@ -62,8 +63,8 @@ func (t *mockTransaction) Timestamp() int64 {
 	return t.timeStamp
 }
 
-func (*mockTransaction) Nonce() *big.Int {
-	panic("implement me")
+func (t *mockTransaction) Nonce() *big.Int {
+	return t.nonce
 }
 
 func (t *mockTransaction) To() module.Address {
@@ -212,3 +213,30 @@ func TestTransactionList_TestSort(t *testing.T) {
 		t.Errorf("First item should be tx4 but tx=%x", tx.ID())
 	}
 }
+
+func TestTransactionList_TxsOf(t *testing.T) {
+	from1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	from2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	tx1 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x01}, from1, 1)
+	tx2 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x02}, from1, 2)
+	tx3 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x03}, from1, 3)
+
+	l := newTransactionList()
+	l.Add(tx3, false)
+	l.Add(tx1, false)
+	l.Add(tx2, false)
+
+	txs := l.TxsOf(from1)
+	if len(txs) != 3 || txs[0] != tx1 || txs[1] != tx2 || txs[2] != tx3 {
+		t.Errorf("Transactions should be sorted by timestamp txs=%v", txs)
+	}
+	if txs := l.TxsOf(from2); len(txs) != 0 {
+		t.Errorf("There should be no transactions of from2 txs=%v", txs)
+	}
+
+	l.RemoveTx(tx3)
+	txs = l.TxsOf(from1)
+	if len(txs) != 2 || txs[1] != tx2 {
+		t.Errorf("Last transaction should be tx2 txs=%v", txs)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -28,7 +29,20 @@ type TransactionManager struct {
 
 	callback func()
 
+	// sigChecker accepts the transaction failing signature verification
+	// if it returns nil.
+	sigChecker func(tx transaction.Transaction) error
+
+	// admissionChecker refuses new transactions if it returns an error.
+	admissionChecker func() error
+
+	// priorityChecker returns true for the transaction using the priority
+	// lane.
+	priorityChecker func(tx transaction.Transaction) bool
+
 	txWaiters map[hashValue][]chan<- interface{}
+
+	feed *TxFeed
 }
 
 func (m *TransactionManager) getTxPool(g module.TransactionGroup) *TransactionPool {
@@ -52,6 +66,14 @@ func (m *TransactionManager) HasTx(id []byte) bool {
 	return m.normalTxPool.HasTx(id) || m.patchTxPool.HasTx(id)
 }
 
+// GetTx returns the transaction in the pools, or nil if it doesn't exist.
+func (m *TransactionManager) GetTx(id []byte) transaction.Transaction {
+	if tx := m.normalTxPool.GetTx(id); tx != nil {
+		return tx
+	}
+	return m.patchTxPool.GetTx(id)
+}
+
 func (m *TransactionManager) RemoveTxs(
 	g module.TransactionGroup, l module.TransactionList,
 ) {
@@ -150,6 +172,9 @@ func (m *TransactionManager) OnTxDrops(drops []TxDrop) {
 func (m *TransactionManager) AddAndWait(tx transaction.Transaction) (
 	<-chan interface{}, error,
 ) {
+	if err := m.checkAdmission(); err != nil {
+		return nil, err
+	}
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -186,6 +211,9 @@ func (m *TransactionManager) WaitResult(id []byte) (<-chan interface{}, error) {
 }
 
 func (m *TransactionManager) Add(tx transaction.Transaction, direct bool, verified bool) error {
+	if err := m.checkAdmission(); err != nil {
+		return err
+	}
 	if !verified {
 		if err := m.VerifyTx(tx); err != nil {
 			return err
@@ -204,12 +232,91 @@ func (m *TransactionManager) VerifyTx(tx transaction.Transaction) error {
 		return errors.InvalidNetworkError.Errorf(
 			"ValidateNetwork(nid=%#x) fail", m.nid)
 	}
-	if err := tx.Verify(); err != nil {
+	if err := tx.Verify(); err != nil && !m.acceptSignature(tx, err) {
 		return InvalidTransactionError.Wrap(err,
 			"Failed to verify transaction")
 	}
 	return nil
 }
+
+// SetSignatureChecker sets the checker for the transactions failing
+// signature verification.
+func (m *TransactionManager) SetSignatureChecker(checker func(tx transaction.Transaction) error) {
+	m.sigChecker = checker
+}
+
+// SetAdmissionChecker sets the checker refusing new transactions.
+func (m *TransactionManager) SetAdmissionChecker(checker func() error) {
+	m.admissionChecker = checker
+}
+
+// SetPriorityChecker sets the checker for the transactions using the
+// priority lane of the normal transaction pool and propagation.
+func (m *TransactionManager) SetPriorityChecker(size int, checker func(tx transaction.Transaction) bool) {
+	m.priorityChecker = checker
+	m.normalTxPool.SetPriorityLane(size, checker)
+}
+
+// IsPriority returns true if the transaction uses the priority lane.
+func (m *TransactionManager) IsPriority(tx transaction.Transaction) bool {
+	return m.priorityChecker != nil && m.priorityChecker(tx)
+}
+
+func (m *TransactionManager) checkAdmission() error {
+	if m.admissionChecker == nil {
+		return nil
+	}
+	return m.admissionChecker()
+}
+
+func (m *TransactionManager) acceptSignature(tx transaction.Transaction, err error) bool {
+	if m.sigChecker == nil || !transaction.InvalidSignatureError.Equals(err) {
+		return false
+	}
+	if err := m.sigChecker(tx); err != nil {
+		m.log.Debugf("Fail to check signature tx=%#x err=%+v", tx.ID(), err)
+		return false
+	}
+	return true
+}
+
+// VerifyTxAll returns all the violations found by VerifyTx.
+func (m *TransactionManager) VerifyTxAll(tx transaction.Transaction) []error {
+	var errs []error
+	if !tx.ValidateNetwork(m.nid) {
+		errs = append(errs, errors.InvalidNetworkError.Errorf(
+			"ValidateNetwork(nid=%#x) fail", m.nid))
+	}
+	if v, ok := transaction.Unwrap(tx).(transaction.Validator); ok {
+		for _, err := range v.VerifyAll() {
+			if m.acceptSignature(tx, err) {
+				continue
+			}
+			errs = append(errs, InvalidTransactionError.Wrap(err,
+				"Failed to verify transaction"))
+		}
+	} else if err := tx.Verify(); err != nil && !m.acceptSignature(tx, err) {
+		errs = append(errs, InvalidTransactionError.Wrap(err,
+			"Failed to verify transaction"))
+	}
+	return errs
+}
+
+// CheckTx checks whether the transaction can be added to the pool without
+// adding it. It checks timestamp and duplication.
+func (m *TransactionManager) CheckTx(tx transaction.Transaction) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.tim.CheckTXForAdd(tx); err != nil {
+		return err
+	}
+	if m.getTxPool(tx.Group()).HasTx(tx.ID()) {
+		return ErrDuplicateTransaction
+	}
+	return nil
+}
+
 func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool) error {
 	if err := m.tim.CheckTXForAdd(tx); err != nil {
 		return err
@@ -219,6 +326,7 @@ func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool)
 	if err := pool.Add(tx, direct); err != nil {
 		return err
 	}
+	m.feed.notify(tx)
 	if m.callback != nil {
 		cb := m.callback
 		m.callback = nil
@@ -237,6 +345,11 @@ func (m *TransactionManager) Wait(wc state.WorldContext, cb func()) bool {
 	return true
 }
 
+// Feed returns notifier of transactions accepted into the pool.
+func (m *TransactionManager) Feed() *TxFeed {
+	return m.feed
+}
+
 func (m *TransactionManager) GetBloomOf(g module.TransactionGroup) *TxBloom {
 	pool := m.getTxPool(g)
 	return pool.GetBloom()
@@ -247,6 +360,13 @@ func (m *TransactionManager) FilterTransactions(g module.TransactionGroup, bloom
 	return pool.FilterTransactions(bloom, max)
 }
 
+// StaleTxs returns up to max transactions in the pools which have not been
+// sent to peers since the time. Patch transactions come first.
+func (m *TransactionManager) StaleTxs(since, now time.Time, max int) []staleTx {
+	txs := m.patchTxPool.StaleTxs(since, now, max)
+	return append(txs, m.normalTxPool.StaleTxs(since, now, max-len(txs))...)
+}
+
 func (m *TransactionManager) Logger() log.Logger {
 	return m.log
 }
@@ -265,6 +385,7 @@ func NewTransactionManager(nid int, tsc *TxTimestampChecker, ptp *TransactionPoo
 		tim:          tim,
 		log:          logger,
 		txWaiters:    map[hashValue][]chan<- interface{}{},
+		feed:         NewTxFeed(),
 	}
 	ptp.SetTxManager(txm)
 	ntp.SetTxManager(txm)
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionManager_Admission(t *testing.T) {
+	m := &TransactionManager{}
+	assert.NoError(t, m.checkAdmission())
+
+	paused := true
+	m.SetAdmissionChecker(func() error {
+		if paused {
+			return ErrTransactionPoolPaused
+		}
+		return nil
+	})
+
+	// transactions are refused before they're verified
+	err := m.Add(nil, true, false)
+	assert.True(t, TransactionPoolPausedError.Equals(err))
+	_, err = m.AddAndWait(nil)
+	assert.True(t, TransactionPoolPausedError.Equals(err))
+
+	paused = false
+	assert.NoError(t, m.checkAdmission())
+}
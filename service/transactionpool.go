@@ -32,6 +32,11 @@ const (
 	configDefaultMaxTxBytesInABlock = 1024 * 1024
 	configDefaultTxSliceCapacity    = 1024
 	configDefaultMaxTxCount         = 1500
+
+	// configLocalTxFirstRatio is the max percentage of transactions of a
+	// block, which are collected first for the transactions sent through
+	// this node.
+	configLocalTxFirstRatio = 50
 )
 
 type Monitor interface {
@@ -39,6 +44,8 @@ type Monitor interface {
 	OnAddTx(n int, user bool)
 	OnRemoveTx(n int, user bool)
 	OnCommit(id []byte, ts time.Time, d time.Duration)
+	OnAddPriorityTx(n int)
+	OnPriorityLaneUpdated(used int)
 }
 
 type TxWaiterManager interface {
@@ -69,6 +76,16 @@ type TransactionPool struct {
 
 	list *transactionList
 
+	// lane has IDs of the transactions in the priority lane. They are
+	// not limited by the size, and they are collected first.
+	laneSize int
+	priority func(tx transaction.Transaction) bool
+	lane     map[string]struct{}
+
+	// localFirst makes the transactions sent through this node collected
+	// before others, which are not in the priority lane.
+	localFirst bool
+
 	mutex sync.Mutex
 
 	txm     TxWaiterManager
@@ -83,6 +100,7 @@ func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager
 		size:    size,
 		tim:     tim,
 		list:    newTransactionList(),
+		lane:    make(map[string]struct{}),
 		txm:     dummyTxWaiterManager{},
 		monitor: m,
 		pcm:     dummyPoolCapacityMonitor{},
@@ -91,6 +109,38 @@ func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager
 	return pool
 }
 
+// SetPriorityLane sets the size of the priority lane and the checker of
+// the transactions using the lane. Transactions exceeding the size of the
+// lane use the pool as others.
+func (tp *TransactionPool) SetPriorityLane(size int, checker func(tx transaction.Transaction) bool) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.laneSize = size
+	tp.priority = checker
+}
+
+// SetLocalFirst sets whether the transactions sent through this node are
+// collected before others up to configLocalTxFirstRatio percent of a block.
+func (tp *TransactionPool) SetLocalFirst(yn bool) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.localFirst = yn
+}
+
+func (tp *TransactionPool) removeFromLaneInLock(id []byte) {
+	if _, ok := tp.lane[string(id)]; ok {
+		delete(tp.lane, string(id))
+		tp.monitor.OnPriorityLaneUpdated(len(tp.lane))
+	}
+}
+
+func (tp *TransactionPool) inLaneInLock(id []byte) bool {
+	_, ok := tp.lane[string(id)]
+	return ok
+}
+
 func (tp *TransactionPool) DropOldTXs(bts int64) {
 	lock := common.LockForAutoCall(&tp.mutex)
 	defer lock.Unlock()
@@ -104,6 +154,7 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 		tx := iter.Value()
 		if tx.Timestamp() <= bts {
 			tp.list.Remove(iter)
+			tp.removeFromLaneInLock(tx.ID())
 			direct := iter.ts != 0
 			if iter.err == nil {
 				iter.err = ExpiredTransactionError.Errorf(
@@ -147,42 +198,87 @@ func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCou
 	dropped := make([]*txElement, 0, configDefaultTxSliceCapacity)
 	poolSize := tp.list.Len()
 	txSize := int(0)
-	for e := tp.list.Front(); e != nil && txSize < maxBytes && len(txs) < maxCount; e = e.Next() {
-		tx := e.Value()
-		if err := tsr.CheckTx(tx); err != nil {
-			if ExpiredTransactionError.Equals(err) {
+	full := false
+	// visited keeps transactions appended or dropped by the previous pass,
+	// so that others may be collected again by the next pass.
+	var visited map[*txElement]bool
+	drop := func(e *txElement) {
+		dropped = append(dropped, e)
+		if visited != nil {
+			visited[e] = true
+		}
+	}
+	collect := func(match func(e *txElement) bool, maxBytes, maxCount int) {
+		for e := tp.list.Front(); e != nil && !full && txSize < maxBytes && len(txs) < maxCount; e = e.Next() {
+			if !match(e) || visited[e] {
+				continue
+			}
+			tx := e.Value()
+			if err := tsr.CheckTx(tx); err != nil {
+				if ExpiredTransactionError.Equals(err) {
+					if e.err == nil {
+						e.err = err
+					}
+					drop(e)
+				}
+				continue
+			}
+			if has, err := tp.tim.HasRecent(tx.ID()); err != nil {
+				continue
+			} else if has {
+				e.err = errors.InvalidStateError.New("AlreadyProcessed")
+				drop(e)
+				continue
+			}
+			if err := tx.PreValidate(wc, true); err != nil {
 				if e.err == nil {
 					e.err = err
+					tp.log.Debugf("PREVALIDATE FAIL: id=%#x from=%s reason=%v",
+						tx.ID(), tx.From().String(), err)
+				}
+				if !transaction.NotEnoughBalanceError.Equals(err) || e.ts == 0 {
+					drop(e)
 				}
-				dropped = append(dropped, e)
+				continue
 			}
-			continue
-		}
-		if has, err := tp.tim.HasRecent(tx.ID()); err != nil {
-			continue
-		} else if has {
-			e.err = errors.InvalidStateError.New("AlreadyProcessed")
-			dropped = append(dropped, e)
-			continue
-		}
-		if err := tx.PreValidate(wc, true); err != nil {
-			if e.err == nil {
-				e.err = err
-				tp.log.Debugf("PREVALIDATE FAIL: id=%#x from=%s reason=%v",
-					tx.ID(), tx.From().String(), err)
+			bs := tx.Bytes()
+			if txSize+len(bs) > maxBytes {
+				full = true
+				break
 			}
-			if !transaction.NotEnoughBalanceError.Equals(err) || e.ts == 0 {
-				dropped = append(dropped, e)
+			txSize += len(bs)
+			txs = append(txs, tx)
+			if visited != nil {
+				visited[e] = true
 			}
-			continue
 		}
-		bs := tx.Bytes()
-		if txSize+len(bs) > maxBytes {
-			break
+	}
+	inLane := func(e *txElement) bool {
+		return tp.inLaneInLock(e.Value().ID())
+	}
+	notInLane := func(e *txElement) bool {
+		return !inLane(e)
+	}
+	if len(tp.lane) > 0 {
+		collect(inLane, maxBytes, maxCount)
+	}
+	if tp.localFirst {
+		localBytes := txSize + maxBytes*configLocalTxFirstRatio/100
+		if localBytes > maxBytes {
+			localBytes = maxBytes
+		}
+		localCount := len(txs) + maxCount*configLocalTxFirstRatio/100
+		if localCount > maxCount {
+			localCount = maxCount
 		}
-		txSize += len(bs)
-		txs = append(txs, tx)
+		visited = make(map[*txElement]bool)
+		collect(func(e *txElement) bool {
+			return e.ts != 0 && notInLane(e)
+		}, localBytes, localCount)
+		// the limit for local transactions doesn't make the block full.
+		full = false
 	}
+	collect(notInLane, maxBytes, maxCount)
 	lock.Unlock()
 
 	if len(dropped) > 0 {
@@ -224,12 +320,18 @@ func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
 
-	if tp.list.Len() >= tp.size {
+	lane := tp.priority != nil && len(tp.lane) < tp.laneSize && tp.priority(tx)
+	if !lane && tp.list.Len()-len(tp.lane) >= tp.size {
 		return ErrTransactionPoolOverFlow
 	}
 
 	err := tp.list.Add(tx, direct)
 	if err == nil {
+		if lane {
+			tp.lane[string(tx.ID())] = struct{}{}
+			tp.monitor.OnAddPriorityTx(len(tx.Bytes()))
+			tp.monitor.OnPriorityLaneUpdated(len(tp.lane))
+		}
 		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
 		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
 	}
@@ -257,6 +359,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 			continue
 		}
 		if ok, ts := tp.list.RemoveTx(t); ok {
+			tp.removeFromLaneInLock(t.ID())
 			if ts != 0 {
 				duration += now.Sub(time.Unix(0, ts))
 				count += 1
@@ -280,6 +383,52 @@ func (tp *TransactionPool) HasTx(tid []byte) bool {
 	return tp.list.HasTx(tid)
 }
 
+func (tp *TransactionPool) GetTx(tid []byte) transaction.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	return tp.list.GetTx(tid)
+}
+
+// TxsOf returns transactions from the address in the pool in order of
+// timestamps.
+func (tp *TransactionPool) TxsOf(from module.Address) []transaction.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	return tp.list.TxsOf(from)
+}
+
+// Front returns up to max transactions at the front of the pool in order
+// of the pool, which would be proposed first. The pool is not changed.
+func (tp *TransactionPool) Front(max int) []transaction.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	txs := make([]transaction.Transaction, 0, max)
+	for e := tp.list.Front(); e != nil && len(txs) < max; e = e.Next() {
+		txs = append(txs, e.Value())
+	}
+	return txs
+}
+
+// StaleTxs returns up to max transactions which have not been sent to peers
+// since the time, in order of the pool. They are marked as sent at now.
+func (tp *TransactionPool) StaleTxs(since, now time.Time, max int) []staleTx {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	var txs []staleTx
+	for e := tp.list.Front(); e != nil && len(txs) < max; e = e.Next() {
+		if e.sent >= since.UnixNano() {
+			continue
+		}
+		txs = append(txs, staleTx{e.Value(), time.Unix(0, e.sent)})
+		e.sent = now.UnixNano()
+	}
+	return txs
+}
+
 func (tp *TransactionPool) Size() int {
 	return tp.size
 }
@@ -321,6 +470,7 @@ func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 	for _, e := range txs {
 		if tp.list.Remove(e) {
 			tx := e.Value()
+			tp.removeFromLaneInLock(tx.ID())
 			direct := e.ts != 0
 			if e.err == nil {
 				tp.log.Panicf("No reason to drop the tx=<%#x>", tx.ID())
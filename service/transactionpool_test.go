@@ -1,13 +1,18 @@
 package service
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
 )
 
 type mockMonitor struct {
@@ -29,6 +34,14 @@ func (m *mockMonitor) OnCommit(id []byte, ts time.Time, d time.Duration) {
 	// do nothing
 }
 
+func (m *mockMonitor) OnAddPriorityTx(n int) {
+	// do nothing
+}
+
+func (m *mockMonitor) OnPriorityLaneUpdated(used int) {
+	// do nothing
+}
+
 func TestTransactionPool_Add(t *testing.T) {
 	dbase := db.NewMapDB()
 	tsc := NewTimestampChecker()
@@ -43,3 +56,120 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionPool_PriorityLane(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 1, tim, &mockMonitor{}, log.New())
+
+	gov := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	user := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	pool.SetPriorityLane(1, func(tx transaction.Transaction) bool {
+		return tx.From().Equal(gov)
+	})
+
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx1"), user, 1), true))
+	assert.Equal(t, ErrTransactionPoolOverFlow,
+		pool.Add(newMockTransaction([]byte("tx2"), user, 2), true))
+
+	// priority transaction uses the lane even if the pool is full
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx3"), gov, 3), true))
+	assert.Equal(t, 2, pool.Used())
+
+	// lane is also full
+	assert.Equal(t, ErrTransactionPoolOverFlow,
+		pool.Add(newMockTransaction([]byte("tx4"), gov, 4), true))
+
+	pool.DropOldTXs(3)
+	assert.Equal(t, 0, pool.Used())
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx4"), gov, 4), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx5"), gov, 5), true))
+	assert.Equal(t, ErrTransactionPoolOverFlow,
+		pool.Add(newMockTransaction([]byte("tx6"), user, 6), true))
+}
+
+func TestTransactionPool_Front(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 100, tim, &mockMonitor{}, log.New())
+
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	for i := 1; i <= 3; i++ {
+		tx := newMockTransaction([]byte(fmt.Sprintf("tx%d", i)), addr, int64(i))
+		assert.NoError(t, pool.Add(tx, true))
+	}
+
+	txs := pool.Front(2)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, []byte("tx1"), txs[0].ID())
+	assert.Equal(t, []byte("tx2"), txs[1].ID())
+	assert.Len(t, pool.Front(10), 3)
+	assert.Equal(t, 3, pool.Used())
+}
+
+type testWorldContext struct {
+	state.WorldContext
+}
+
+func (testWorldContext) BlockTimeStamp() int64 {
+	return 0
+}
+
+func (testWorldContext) TransactionTimestampThreshold() int64 {
+	return 0
+}
+
+func TestTransactionPool_LocalFirst(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 100, tim, &mockMonitor{}, log.New())
+
+	var relayed, local []module.Transaction
+	for i := 0; i < 6; i++ {
+		addr := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i+1))
+		tx := newMockTransaction([]byte(fmt.Sprintf("tx%d", i)), addr, int64(i))
+		direct := i >= 3
+		assert.NoError(t, pool.Add(tx, direct))
+		if direct {
+			local = append(local, tx)
+		} else {
+			relayed = append(relayed, tx)
+		}
+	}
+
+	txs, _ := pool.Candidate(testWorldContext{}, 0, 4)
+	assert.Equal(t, append(relayed, local[0]), txs)
+
+	// local transactions up to the half of the block come first
+	pool.SetLocalFirst(true)
+	txs, _ = pool.Candidate(testWorldContext{}, 0, 4)
+	assert.Equal(t, []module.Transaction{local[0], local[1], relayed[0], relayed[1]}, txs)
+
+	// others fill the rest of the block
+	txs, _ = pool.Candidate(testWorldContext{}, 0, 6)
+	assert.Equal(t, []module.Transaction{local[0], local[1], local[2], relayed[0], relayed[1], relayed[2]}, txs)
+}
+
+func TestTransactionPool_LocalFirstOverflow(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 100, tim, &mockMonitor{}, log.New())
+	pool.SetLocalFirst(true)
+
+	var txs []module.Transaction
+	for i := 0; i < 4; i++ {
+		addr := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i+1))
+		tx := newMockTransaction([]byte(fmt.Sprintf("tx%d", i)), addr, int64(i))
+		assert.NoError(t, pool.Add(tx, i > 0))
+		txs = append(txs, tx)
+	}
+
+	// tx3 overflows the limit for local transactions, but it fits the block
+	candidates, size := pool.Candidate(testWorldContext{}, 14, 0)
+	assert.Equal(t, []module.Transaction{txs[1], txs[2], txs[0], txs[3]}, candidates)
+	assert.Equal(t, 12, size)
+}
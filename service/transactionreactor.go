@@ -1,6 +1,11 @@
 package service
 
 import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
@@ -9,13 +14,29 @@ import (
 
 const (
 	ReactorName     = "transaction"
+	ReactorNameV1   = "transaction1"
 	ReactorPriority = 4
+
+	// ReactorNamePriority is the name of the reactor for the priority lane.
+	// It has higher priority than other transactions.
+	ReactorNamePriority     = "transaction.priority"
+	ReactorPriorityPriority = 3
 )
 
 const (
 	protoPropagateTransaction = module.ProtocolInfo(0x1001)
 	protoRequestTransaction   = module.ProtocolInfo(0x1100)
 	protoResponseTransaction  = module.ProtocolInfo(0x1200)
+	protoAnnounceTransaction  = module.ProtocolInfo(0x1300)
+	protoPullTransaction      = module.ProtocolInfo(0x1400)
+)
+
+const (
+	// txAnnounceThreshold is the size of transactions to be announced by
+	// its hash instead of its body to the peers supporting it.
+	txAnnounceThreshold = 2 * 1024
+	txPullTimeout       = 3 * time.Second
+	txPullsMax          = 4096
 )
 
 var (
@@ -24,17 +45,66 @@ var (
 		protoRequestTransaction,
 		protoResponseTransaction,
 	}
+	subProtocolsV1 = []module.ProtocolInfo{
+		protoPropagateTransaction,
+		protoRequestTransaction,
+		protoResponseTransaction,
+		protoAnnounceTransaction,
+		protoPullTransaction,
+	}
+	protoTransactionV1   = module.NewProtocolInfo(module.ProtoTransaction.ID(), 1)
+	subProtocolsPriority = []module.ProtocolInfo{
+		protoPropagateTransaction,
+	}
 )
 
+// versionPriority is the pseudo version of the priority lane used for
+// excluding the lane on relaying.
+const versionPriority = 2
+
 type TransactionReactor struct {
-	nm         module.NetworkManager
-	membership module.ProtocolHandler
-	tm         *TransactionManager
-	log        log.Logger
-	ts         *TransactionShare
+	nm           module.NetworkManager
+	membership   module.ProtocolHandler
+	membershipV1 module.ProtocolHandler
+	membershipP  module.ProtocolHandler
+	tm           *TransactionManager
+	log          log.Logger
+	ts           *TransactionShare
+	rb           *txRebroadcaster
+	pulls        txPulls
+	v1           *transactionReactorV1
+	priority     *transactionReactorPriority
+}
+
+// transactionReactorV1 handles the version 1 of the protocol which
+// propagates large transactions by announcing its hash. Then the receivers
+// pull the transaction if they don't have it.
+type transactionReactorV1 struct {
+	*TransactionReactor
+}
+
+func (r *transactionReactorV1) OnReceive(subProtocol module.ProtocolInfo, buf []byte, peerId module.PeerID) (bool, error) {
+	return r.onReceive(1, subProtocol, buf, peerId)
+}
+
+// transactionReactorPriority handles the priority lane which propagates
+// governance and patch transactions ahead of other transactions.
+type transactionReactorPriority struct {
+	*TransactionReactor
+}
+
+func (r *transactionReactorPriority) OnReceive(subProtocol module.ProtocolInfo, buf []byte, peerId module.PeerID) (bool, error) {
+	if subProtocol != protoPropagateTransaction {
+		return false, nil
+	}
+	return r.onReceive(versionPriority, subProtocol, buf, peerId)
 }
 
 func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []byte, peerId module.PeerID) (bool, error) {
+	return r.onReceive(0, subProtocol, buf, peerId)
+}
+
+func (r *TransactionReactor) onReceive(version int, subProtocol module.ProtocolInfo, buf []byte, peerId module.PeerID) (bool, error) {
 	switch subProtocol {
 	case protoPropagateTransaction:
 		tx, err := transaction.NewTransaction(buf)
@@ -47,6 +117,12 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		if err := r.tm.Add(tx, false, false); err != nil {
 			return false, err
 		}
+		// network relays it to the peers of the same version.
+		if err := r.propagate(tx, version); err != nil {
+			if !network.NotAvailableError.Equals(err) {
+				r.log.Debugf("Fail to propagate transaction err=%+v", err)
+			}
+		}
 		return true, nil
 	case protoResponseTransaction:
 		tx, err := transaction.NewTransaction(buf)
@@ -55,6 +131,7 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 			r.log.Debugf("Failed to unmarshal transaction. buf=%x, err=%+v", buf, err)
 			return false, err
 		}
+		r.pulls.done(tx.ID())
 
 		if err := r.tm.Add(tx, false, false); err != nil {
 			r.log.Debugf("Fail to add transaction id=%#x from=%s err=%+v",
@@ -69,15 +146,72 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		return false, nil
 	case protoRequestTransaction:
 		return r.ts.HandleRequestTransaction(buf, peerId)
+	case protoAnnounceTransaction:
+		return r.handleAnnounceTransaction(buf, peerId)
+	case protoPullTransaction:
+		return r.handlePullTransaction(buf, peerId)
+	}
+	return false, nil
+}
+
+func (r *TransactionReactor) handleAnnounceTransaction(buf []byte, peerId module.PeerID) (bool, error) {
+	if len(buf) != crypto.HashLen {
+		r.log.Warnf("InvalidPacket(AnnounceTransaction) from=%s", peerId.String())
+		return false, errors.IllegalArgumentError.Errorf("InvalidTxID(len=%d)", len(buf))
+	}
+	if r.tm.HasTx(buf) || !r.pulls.start(buf) {
+		return false, nil
+	}
+	if err := r.membershipV1.Unicast(protoPullTransaction, buf, peerId); err != nil {
+		r.pulls.done(buf)
+		r.log.Debugf("Fail to pull transaction id=%#x from=%s err=%+v",
+			buf, peerId.String(), err)
+	}
+	return false, nil
+}
+
+func (r *TransactionReactor) handlePullTransaction(buf []byte, peerId module.PeerID) (bool, error) {
+	if len(buf) != crypto.HashLen {
+		r.log.Warnf("InvalidPacket(PullTransaction) from=%s", peerId.String())
+		return false, errors.IllegalArgumentError.Errorf("InvalidTxID(len=%d)", len(buf))
+	}
+	tx := r.tm.GetTx(buf)
+	if tx == nil {
+		return false, nil
+	}
+	if err := r.membershipV1.Unicast(protoResponseTransaction, tx.Bytes(), peerId); err != nil {
+		r.log.Debugf("Fail to send pulled transaction id=%#x to=%s err=%+v",
+			buf, peerId.String(), err)
 	}
 	return false, nil
 }
 
 func (r *TransactionReactor) PropagateTransaction(tx transaction.Transaction) error {
-	if r != nil && r.membership != nil {
-		return r.membership.Multicast(protoPropagateTransaction, tx.Bytes(), module.ROLE_VALIDATOR)
+	return r.propagate(tx, -1)
+}
+
+// propagate sends the transaction to the validators except the peers of the
+// version.
+func (r *TransactionReactor) propagate(tx transaction.Transaction, except int) error {
+	if r == nil || r.membership == nil {
+		return nil
+	}
+	var errs []error
+	bs := tx.Bytes()
+	if except != versionPriority && r.membershipP != nil && r.tm.IsPriority(tx) {
+		errs = append(errs, r.membershipP.Multicast(protoPropagateTransaction, bs, module.ROLE_VALIDATOR))
 	}
-	return nil
+	if except != 0 {
+		errs = append(errs, r.membership.Multicast(protoPropagateTransaction, bs, module.ROLE_VALIDATOR))
+	}
+	if except != 1 && r.membershipV1 != nil {
+		if len(bs) < txAnnounceThreshold {
+			errs = append(errs, r.membershipV1.Multicast(protoPropagateTransaction, bs, module.ROLE_VALIDATOR))
+		} else {
+			errs = append(errs, r.membershipV1.Multicast(protoAnnounceTransaction, tx.ID(), module.ROLE_VALIDATOR))
+		}
+	}
+	return sendErrorOf(errs...)
 }
 
 func (r *TransactionReactor) OnFailure(err error, pi module.ProtocolInfo, b []byte) {
@@ -86,20 +220,28 @@ func (r *TransactionReactor) OnFailure(err error, pi module.ProtocolInfo, b []by
 
 func (r *TransactionReactor) OnJoin(id module.PeerID) {
 	r.ts.HandleJoin(id)
+	r.rb.onJoin(id)
 }
 
 func (r *TransactionReactor) OnLeave(id module.PeerID) {
 	r.ts.HandleLeave(id)
+	r.rb.onLeave(id)
 }
 
 func (r *TransactionReactor) Start(wallet module.Wallet) {
 	r.membership, _ = r.nm.RegisterReactor(ReactorName, module.ProtoTransaction, r, subProtocols, ReactorPriority, module.NotRegisteredProtocolPolicyClose)
-	r.ts.Start(r.membership, wallet)
+	r.membershipV1, _ = r.nm.RegisterReactor(ReactorNameV1, protoTransactionV1, r.v1, subProtocolsV1, ReactorPriority, module.NotRegisteredProtocolPolicyClose)
+	r.membershipP, _ = r.nm.RegisterReactor(ReactorNamePriority, module.ProtoPriorityTransaction, r.priority, subProtocolsPriority, ReactorPriorityPriority, module.NotRegisteredProtocolPolicyClose)
+	r.ts.Start(protocolHandlers{r.membershipV1, r.membership}, wallet)
+	r.rb.Start(protocolHandlers{r.membershipV1, r.membership})
 	r.tm.SetPoolCapacityMonitor(r.ts)
 }
 
 func (r *TransactionReactor) Stop() {
+	r.rb.Stop()
 	r.ts.Stop()
+	_ = r.nm.UnregisterReactor(r.priority)
+	_ = r.nm.UnregisterReactor(r.v1)
 	_ = r.nm.UnregisterReactor(r)
 }
 
@@ -109,6 +251,101 @@ func NewTransactionReactor(nm module.NetworkManager, tm *TransactionManager) *Tr
 		nm:  nm,
 		log: tm.Logger(),
 		ts:  NewTransactionShare(tm),
+		rb:  newTxRebroadcaster(tm),
 	}
+	ra.v1 = &transactionReactorV1{ra}
+	ra.priority = &transactionReactorPriority{ra}
 	return ra
 }
+
+// txPulls keeps transactions being pulled, so that it pulls a transaction
+// only once even if it's announced by multiple peers.
+type txPulls struct {
+	lock sync.Mutex
+	m    map[string]time.Time
+}
+
+// start returns true if the transaction needs to be pulled.
+func (p *txPulls) start(id []byte) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+	if p.m == nil {
+		p.m = make(map[string]time.Time)
+	} else if len(p.m) >= txPullsMax {
+		for k, ts := range p.m {
+			if now.Sub(ts) >= txPullTimeout {
+				delete(p.m, k)
+			}
+		}
+	}
+	if ts, ok := p.m[string(id)]; ok && now.Sub(ts) < txPullTimeout {
+		return false
+	}
+	if len(p.m) >= txPullsMax {
+		return false
+	}
+	p.m[string(id)] = now
+	return true
+}
+
+func (p *txPulls) done(id []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.m, string(id))
+}
+
+// protocolHandlers sends messages through the handlers of all versions, so
+// each peer gets them through the version negotiated with it.
+type protocolHandlers []module.ProtocolHandler
+
+func (hs protocolHandlers) Broadcast(pi module.ProtocolInfo, b []byte, bt module.BroadcastType) error {
+	var errs []error
+	for _, h := range hs {
+		errs = append(errs, h.Broadcast(pi, b, bt))
+	}
+	return sendErrorOf(errs...)
+}
+
+func (hs protocolHandlers) Multicast(pi module.ProtocolInfo, b []byte, role module.Role) error {
+	var errs []error
+	for _, h := range hs {
+		errs = append(errs, h.Multicast(pi, b, role))
+	}
+	return sendErrorOf(errs...)
+}
+
+func (hs protocolHandlers) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID) error {
+	var err error
+	for _, h := range hs {
+		if err = h.Unicast(pi, b, id); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (hs protocolHandlers) GetPeers() []module.PeerID {
+	var peers []module.PeerID
+	for _, h := range hs {
+		peers = append(peers, h.GetPeers()...)
+	}
+	return peers
+}
+
+// sendErrorOf returns nil if any of sending succeeds. Otherwise, it returns
+// the error preferring others to NotAvailableError.
+func sendErrorOf(errs ...error) error {
+	var ret error
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+		if ret == nil || network.NotAvailableError.Equals(ret) {
+			ret = err
+		}
+	}
+	return ret
+}
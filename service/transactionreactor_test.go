@@ -0,0 +1,191 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
+)
+
+type testSentMessage struct {
+	pi module.ProtocolInfo
+	b  []byte
+	id module.PeerID
+}
+
+type testProtocolHandler struct {
+	peers []module.PeerID
+	sent  []testSentMessage
+}
+
+func (h *testProtocolHandler) hasPeer(id module.PeerID) bool {
+	for _, p := range h.peers {
+		if p.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *testProtocolHandler) Broadcast(pi module.ProtocolInfo, b []byte, bt module.BroadcastType) error {
+	return h.Multicast(pi, b, module.ROLE_NORMAL)
+}
+
+func (h *testProtocolHandler) Multicast(pi module.ProtocolInfo, b []byte, role module.Role) error {
+	if len(h.peers) == 0 {
+		return network.ErrNotAvailable
+	}
+	h.sent = append(h.sent, testSentMessage{pi, b, nil})
+	return nil
+}
+
+func (h *testProtocolHandler) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID) error {
+	if !h.hasPeer(id) {
+		return network.ErrNotAvailable
+	}
+	h.sent = append(h.sent, testSentMessage{pi, b, id})
+	return nil
+}
+
+func (h *testProtocolHandler) GetPeers() []module.PeerID {
+	return h.peers
+}
+
+func newTestTransactionReactor(ph, ph1 *testProtocolHandler) (*TransactionReactor, *TransactionPool) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc)
+	logger := log.New()
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 5000, tim, &mockMonitor{}, logger)
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, logger)
+	r := NewTransactionReactor(nil, tm)
+	r.membership = ph
+	r.membershipV1 = ph1
+	return r, ntp
+}
+
+func TestTransactionReactor_AnnounceAndPull(t *testing.T) {
+	peer1 := network.NewPeerIDFromAddress(common.MustNewAddressFromString("hx1111111111111111111111111111111111111111"))
+	peer2 := network.NewPeerIDFromAddress(common.MustNewAddressFromString("hx2222222222222222222222222222222222222222"))
+	ph := &testProtocolHandler{peers: []module.PeerID{peer2}}
+	ph1 := &testProtocolHandler{peers: []module.PeerID{peer1}}
+	r, ntp := newTestTransactionReactor(ph, ph1)
+
+	id := crypto.SHA3Sum256([]byte("tx1"))
+	_, err := r.OnReceive(protoAnnounceTransaction, id[:4], peer1)
+	assert.Error(t, err)
+
+	// pull only once for announcements from multiple peers
+	for i := 0; i < 2; i++ {
+		rebroadcast, err := r.v1.OnReceive(protoAnnounceTransaction, id, peer1)
+		assert.NoError(t, err)
+		assert.False(t, rebroadcast)
+	}
+	assert.Equal(t, []testSentMessage{{protoPullTransaction, id, peer1}}, ph1.sent)
+	assert.Empty(t, ph.sent)
+
+	// no pull for the transaction in the pool
+	r.pulls.done(id)
+	tx := newMockTransaction(id, common.MustNewAddressFromString("hx1111111111111111111111111111111111111111"), 0)
+	tx.NID = 1
+	assert.NoError(t, ntp.Add(tx, true))
+	_, err = r.v1.OnReceive(protoAnnounceTransaction, id, peer1)
+	assert.NoError(t, err)
+	assert.Len(t, ph1.sent, 1)
+
+	// respond to pull with the transaction
+	_, err = r.v1.OnReceive(protoPullTransaction, id, peer1)
+	assert.NoError(t, err)
+	assert.Equal(t, testSentMessage{protoResponseTransaction, id, peer1}, ph1.sent[1])
+	_, err = r.v1.OnReceive(protoPullTransaction, crypto.SHA3Sum256([]byte("tx2")), peer1)
+	assert.NoError(t, err)
+	assert.Len(t, ph1.sent, 2)
+}
+
+func TestTransactionReactor_Propagate(t *testing.T) {
+	ph := &testProtocolHandler{}
+	ph1 := &testProtocolHandler{}
+	r, _ := newTestTransactionReactor(ph, ph1)
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+
+	small := newMockTransaction(crypto.SHA3Sum256([]byte("small")), addr, 0)
+	assert.True(t, network.NotAvailableError.Equals(r.PropagateTransaction(small)))
+
+	peer := network.NewPeerIDFromAddress(addr)
+	ph.peers = []module.PeerID{peer}
+	ph1.peers = []module.PeerID{peer}
+	assert.NoError(t, r.PropagateTransaction(small))
+	assert.Equal(t, []testSentMessage{{protoPropagateTransaction, small.Bytes(), nil}}, ph.sent)
+	assert.Equal(t, []testSentMessage{{protoPropagateTransaction, small.Bytes(), nil}}, ph1.sent)
+
+	// only the hash of large one is sent to the peers supporting announcement
+	ph.sent, ph1.sent = nil, nil
+	large := newMockTransaction(make([]byte, txAnnounceThreshold), addr, 0)
+	assert.NoError(t, r.PropagateTransaction(large))
+	assert.Equal(t, []testSentMessage{{protoPropagateTransaction, large.Bytes(), nil}}, ph.sent)
+	assert.Equal(t, []testSentMessage{{protoAnnounceTransaction, large.ID(), nil}}, ph1.sent)
+
+	// network relays it to the peers of the version where it comes from
+	ph.sent, ph1.sent = nil, nil
+	assert.NoError(t, r.propagate(large, 1))
+	assert.Len(t, ph.sent, 1)
+	assert.Empty(t, ph1.sent)
+}
+
+func TestProtocolHandlers_Unicast(t *testing.T) {
+	peer := network.NewPeerIDFromAddress(common.MustNewAddressFromString("hx1111111111111111111111111111111111111111"))
+	ph := &testProtocolHandler{peers: []module.PeerID{peer}}
+	ph1 := &testProtocolHandler{}
+	hs := protocolHandlers{ph1, ph}
+
+	assert.NoError(t, hs.Unicast(protoResponseTransaction, []byte("tx"), peer))
+	assert.Len(t, ph.sent, 1)
+	assert.Empty(t, ph1.sent)
+	assert.Equal(t, []module.PeerID{peer}, hs.GetPeers())
+
+	ph.peers = nil
+	assert.Error(t, hs.Unicast(protoResponseTransaction, []byte("tx"), peer))
+	assert.Error(t, hs.Broadcast(protoRequestTransaction, []byte("bloom"), module.BROADCAST_CHILDREN))
+}
+
+func TestTransactionReactor_Rebroadcast(t *testing.T) {
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	peer1 := network.NewPeerIDFromAddress(addr)
+	peer2 := network.NewPeerIDFromAddress(common.MustNewAddressFromString("hx2222222222222222222222222222222222222222"))
+	ph := &testProtocolHandler{peers: []module.PeerID{peer1, peer2}}
+	r, ntp := newTestTransactionReactor(ph, &testProtocolHandler{})
+	r.rb.ph = ph
+	r.OnJoin(peer1)
+
+	tx := newMockTransaction(crypto.SHA3Sum256([]byte("tx1")), addr, 0)
+	tx.NID = 1
+	sent := time.Now()
+	assert.NoError(t, ntp.Add(tx, true))
+
+	// not yet stale
+	assert.Equal(t, 0, r.rb.rebroadcast(time.Now()))
+
+	// sent to the peer joined after it was sent only
+	time.Sleep(time.Millisecond)
+	r.OnJoin(peer2)
+	now := sent.Add(txRebroadcastAge + time.Second)
+	assert.Equal(t, 1, r.rb.rebroadcast(now))
+	assert.Equal(t, []testSentMessage{{protoPropagateTransaction, tx.Bytes(), peer2}}, ph.sent)
+
+	// stale again, but no peers joined after that
+	now = now.Add(txRebroadcastAge + time.Second)
+	assert.Equal(t, 0, r.rb.rebroadcast(now))
+	assert.Len(t, ph.sent, 1)
+
+	// the left peer is not used
+	r.OnLeave(peer2)
+	assert.Empty(t, r.rb.peersJoinedAfter(sent, txRebroadcastPeersMax))
+}
@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/hotspot"
 	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/state"
 	ssync "github.com/icon-project/goloop/service/sync2"
@@ -86,6 +88,9 @@ type transitionContext struct {
 	tsc   *TxTimestampChecker
 	sass  state.AccountSnapshot
 	tim   TXIDManager
+	pe    *PendingExecutions
+	hsp   *hotspot.Profiler
+	sla   *StepLimitAdvisor
 }
 
 func (tc *transitionContext) onWorldFinalize(wss state.WorldSnapshot) {
@@ -149,6 +154,19 @@ type transition struct {
 	ntxIDs   TXIDLogger
 	ptxCount int
 	ntxCount int
+
+	// failureData keeps revert payloads of failed transactions by
+	// their hashes until the result is finalized.
+	failureData map[string][]byte
+
+	// stepCostChanges keeps changes of step costs by the order of the
+	// transaction making them until the result is finalized.
+	stepCostLock    sync.Mutex
+	stepCostChanges map[int][]*StepCostChange
+
+	// accesses keeps the accounts accessed by the execution until the
+	// result is finalized. It's nil unless access_index is enabled.
+	accesses *accessRecorder
 }
 
 func patchTransition(t *transition, bi module.BlockInfo, patchTXs module.TransactionList) *transition {
@@ -233,6 +251,9 @@ func newInitTransition(dbase db.Database,
 	logger log.Logger, plt base.Platform,
 	tsc *TxTimestampChecker,
 	tim TXIDManager,
+	pe *PendingExecutions,
+	hsp *hotspot.Profiler,
+	sla *StepLimitAdvisor,
 ) (*transition, error) {
 	wss, err := newWorldSnapshot(dbase, plt, result, validatorList)
 	if err != nil {
@@ -254,6 +275,9 @@ func newInitTransition(dbase db.Database,
 			plt:   plt,
 			tsc:   tsc,
 			tim:   tim,
+			pe:    pe,
+			hsp:   hsp,
+			sla:   sla,
 		},
 		step:          stepComplete,
 		result:        result,
@@ -407,8 +431,14 @@ func (t *transition) newWorldContext(execution bool) (state.WorldContext, error)
 	}
 	if execution {
 		ws.EnableNodeCache()
+		ws = newStateTraceWorldState(ws, t.ti)
+	}
+	wc := state.NewWorldContext(ws, t.bi, t.csi, t.plt)
+	if execution && t.ti == nil && t.chain != nil && t.chain.AccessIndex() {
+		t.accesses = newAccessRecorder()
+		wc = newAccessTrackWorldContext(wc, t.accesses)
 	}
-	return state.NewWorldContext(ws, t.bi, t.csi, t.plt), nil
+	return wc, nil
 }
 
 func (t *transition) newContractContext(wc state.WorldContext) contract.Context {
@@ -416,7 +446,11 @@ func (t *transition) newContractContext(wc state.WorldContext) contract.Context
 	if t.ti != nil {
 		priority = eeproxy.ForQuery
 	}
-	return contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti, priority)
+	ctx := contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti, priority)
+	if t.hsp != nil && t.ti == nil {
+		ctx.SetProperty(contract.PropHotspotProfiler, t.hsp)
+	}
+	return ctx
 }
 
 func (t *transition) reportValidation(e error) bool {
@@ -661,10 +695,13 @@ func (t *transition) doExecute(alreadyValidated bool) {
 
 	ctx.GetBTPState().StoreValidators(ctx.GetValidatorState())
 
+	t.stepCostChanges = make(map[int][]*StepCostChange)
+	sysBegin := ctx.GetAccountSnapshot(state.SystemID)
 	if err := t.plt.OnExecutionBegin(ctx, t.log); err != nil {
 		t.reportExecution(err)
 		return
 	}
+	t.addStepCostChanges(-1, nil, sysBegin, ctx.GetAccountSnapshot(state.SystemID))
 	patchReceipts := make([]txresult.Receipt, t.ptxCount)
 	if err := t.executeTxsSequential(t.patchTransactions, ctx, patchReceipts); err != nil {
 		t.reportExecution(err)
@@ -702,6 +739,15 @@ func (t *transition) doExecute(alreadyValidated bool) {
 			}
 		}
 	}
+	t.failureData = make(map[string][]byte)
+	if err := t.collectFailureData(t.patchTransactions, patchReceipts); err != nil {
+		t.reportExecution(err)
+		return
+	}
+	if err := t.collectFailureData(t.normalTransactions, normalReceipts); err != nil {
+		t.reportExecution(err)
+		return
+	}
 	t.patchReceipts = txresult.NewReceiptListFromSlice(t.db, patchReceipts)
 	t.normalReceipts = txresult.NewReceiptListFromSlice(t.db, normalReceipts)
 
@@ -711,10 +757,12 @@ func (t *transition) doExecute(alreadyValidated bool) {
 	tr.SetBalance(new(big.Int).Add(tb, gatheredFee))
 
 	er := NewExecutionResult(t.patchReceipts, t.normalReceipts, virtualFee, gatheredFee)
+	sysEnd := ctx.GetAccountSnapshot(state.SystemID)
 	if err = t.onPlatformExecutionEnd(ctx, er); err != nil {
 		t.reportExecution(err)
 		return
 	}
+	t.addStepCostChanges(t.ptxCount+t.ntxCount, nil, sysEnd, ctx.GetAccountSnapshot(state.SystemID))
 
 	bc := state.NewBTPContext(ctx, ctx.GetAccountState(state.SystemID))
 	if bs, err := ctx.GetBTPState().BuildAndApplySection(bc, btpMsgs); err != nil {
@@ -746,6 +794,20 @@ func (t *transition) doExecute(alreadyValidated bool) {
 	}
 	t.result = tresult.Bytes()
 
+	if t.sla != nil && t.ti == nil && t.ntxCount > 0 {
+		t.sla.onExecution(t.bi.Height(), cumulativeSteps, txDuration,
+			ctx.GetStepLimit(state.StepLimitTypeInvoke))
+	}
+
+	if t.pe != nil && t.ti == nil && t.ntxCount > 0 {
+		t.pe.notify(&PendingExecution{
+			Height:       t.bi.Height(),
+			ID:           t.normalTransactions.Hash(),
+			Transactions: t.normalTransactions,
+			Receipts:     t.normalReceipts,
+		})
+	}
+
 	t.reportExecution(nil)
 }
 
@@ -776,7 +838,11 @@ func (t *transition) validateTxs(l module.TransactionList, wc state.WorldContext
 			return errors.InvalidNetworkError.New("InvalidNetworkID")
 		}
 		if err := tx.Verify(); err != nil {
-			return err
+			// the validator contract verifies it on execution.
+			if !transaction.InvalidSignatureError.Equals(err) ||
+				state.TxValidatorOf(wc, tx.From()) == nil {
+				return err
+			}
 		}
 		if err := tsr.CheckTx(tx); err != nil {
 			return err
@@ -802,6 +868,76 @@ func (t *transition) executeTxs(l module.TransactionList, ctx contract.Context,
 	return t.executeTxsSequential(l, ctx, rctBuf)
 }
 
+func (t *transition) collectFailureData(l module.TransactionList, rcts []txresult.Receipt) error {
+	idx := 0
+	for i := l.Iterator(); i.Has(); i.Next() {
+		tx, _, err := i.Get()
+		if err != nil {
+			return err
+		}
+		if idx < len(rcts) && rcts[idx] != nil {
+			if data := txresult.FailureDataOf(rcts[idx]); data != nil {
+				t.failureData[string(tx.ID())] = data
+			}
+		}
+		idx++
+	}
+	return nil
+}
+
+// txOrder returns the order of the transaction in the block. Patch
+// transactions are executed before normal transactions.
+func (t *transition) txOrder(group module.TransactionGroup, index int) int {
+	if group == module.TransactionGroupNormal {
+		return t.ptxCount + index
+	}
+	return index
+}
+
+// addStepCostChanges records changes of step costs made by the transaction
+// at the order. before and after are snapshots of the system account.
+func (t *transition) addStepCostChanges(order int, txHash []byte, before, after state.AccountSnapshot) {
+	changes := stepCostChangesOf(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	height := t.bi.Height()
+	for _, c := range changes {
+		c.Height = height
+		c.TxHash = txHash
+	}
+	t.stepCostLock.Lock()
+	defer t.stepCostLock.Unlock()
+	t.stepCostChanges[order] = changes
+}
+
+func (t *transition) flushStepCostChanges() error {
+	if len(t.stepCostChanges) == 0 {
+		return nil
+	}
+	orders := make([]int, 0, len(t.stepCostChanges))
+	for order := range t.stepCostChanges {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+	var changes []*StepCostChange
+	for _, order := range orders {
+		changes = append(changes, t.stepCostChanges[order]...)
+	}
+	t.stepCostChanges = nil
+	return storeStepCostChanges(t.db, t.bi.Height(), changes)
+}
+
+func (t *transition) flushFailureData() error {
+	for id, data := range t.failureData {
+		if err := txresult.StoreFailureData(t.db, []byte(id), data); err != nil {
+			return err
+		}
+	}
+	t.failureData = nil
+	return nil
+}
+
 func (t *transition) finalizeNormalTransaction() error {
 	if err := t.commitTXIDs(module.TransactionGroupNormal); err != nil {
 		return err
@@ -836,6 +972,15 @@ func (t *transition) finalizeResult(noFlush bool, keepParent bool) error {
 			if err := t.normalReceipts.Flush(); err != nil {
 				return err
 			}
+			if err := t.flushFailureData(); err != nil {
+				return err
+			}
+			if err := t.flushStepCostChanges(); err != nil {
+				return err
+			}
+			if err := t.flushAccesses(); err != nil {
+				return err
+			}
 		}
 	}
 	if !keepParent {
@@ -910,7 +1055,7 @@ func NewInitTransition(
 	if err != nil {
 		return nil, err
 	}
-	if tr, err := newInitTransition(db, result, vl, cm, em, chain, logger, plt, tsc, tim); err != nil {
+	if tr, err := newInitTransition(db, result, vl, cm, em, chain, logger, plt, tsc, tim, nil, nil, nil); err != nil {
 		return nil, err
 	} else {
 		return tr, nil
@@ -2,6 +2,7 @@ package service
 
 import (
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
@@ -49,6 +50,7 @@ func newExecutionContext(n int) *executionContext {
 
 func (t *transition) executeTxsConcurrent(level int, l module.TransactionList, ctx contract.Context, rctBuf []txresult.Receipt) error {
 	ec := newExecutionContext(level)
+	quota := t.chain.Quota()
 
 	cnt := 0
 	for i := l.Iterator(); i.Has(); i.Next() {
@@ -80,6 +82,7 @@ func (t *transition) executeTxsConcurrent(level int, l module.TransactionList, c
 
 		ec.Ready()
 		go func(ctx contract.Context, wc state.WorldContext, txo transaction.Transaction, cnt int, rb *txresult.Receipt) {
+			ts := time.Now()
 			wvs := ctx.WorldVirtualState()
 			wvss := wvs.GetSnapshot()
 			for retry := 0; ; retry++ {
@@ -92,13 +95,18 @@ func (t *transition) executeTxsConcurrent(level int, l module.TransactionList, c
 					From:      txo.From(),
 				})
 				ctx.UpdateSystemInfo()
+				sysBefore := ctx.GetAccountSnapshot(state.SystemID)
+				release := quota.AcquireExecutor()
 				rct, err := txh.Execute(ctx, wvss, false)
+				release()
 				txh.Dispose()
 				if err == nil {
 					err = t.plt.OnTransactionEnd(ctx, t.log, rct)
 				}
 				if err == nil {
 					*rb = rct
+					t.addStepCostChanges(t.txOrder(txo.Group(), cnt), txo.ID(),
+						sysBefore, ctx.GetAccountSnapshot(state.SystemID))
 					break
 				}
 
@@ -130,6 +138,7 @@ func (t *transition) executeTxsConcurrent(level int, l module.TransactionList, c
 				ctx = t.newContractContext(wc)
 			}
 			wvs.Commit()
+			quota.OnExecution(time.Since(ts))
 			ec.Done()
 		}(ctx, wc, txo, cnt, &rctBuf[cnt])
 
@@ -14,6 +14,7 @@ import (
 
 func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract.Context, rctBuf []txresult.Receipt) error {
 	skipping := ctx.SkipTransactionEnabled()
+	quota := t.chain.Quota()
 	cnt := 0
 
 	for i := l.Iterator(); i.Has(); i.Next() {
@@ -47,6 +48,7 @@ func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract
 		}
 		ctx.SetTransactionInfo(txInfo)
 		wcs := ctx.GetSnapshot()
+		sysBefore := ctx.GetAccountSnapshot(state.SystemID)
 		traceLogger := ctx.GetTraceLogger(module.EPhaseTransaction)
 		traceLogger.OnTransactionStart(cnt, txo.ID())
 
@@ -57,7 +59,9 @@ func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract
 				return err
 			}
 			ctx.UpdateSystemInfo()
+			release := quota.AcquireExecutor()
 			rct, err := txh.Execute(ctx, wcs, false)
+			release()
 			txh.Dispose()
 			if err == nil {
 				if err = t.plt.OnTransactionEnd(ctx, t.log, rct); err == nil {
@@ -82,9 +86,11 @@ func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract
 			traceLogger.OnTransactionReset()
 		}
 
+		t.addStepCostChanges(t.txOrder(txo.Group(), cnt), txo.ID(), sysBefore, ctx.GetAccountSnapshot(state.SystemID))
 		traceLogger.OnTransactionEnd(cnt, txo.ID(), txInfo.From, ctx.Treasury(), ctx.Revision(), rctBuf[cnt])
 		duration := time.Since(ts)
 		t.log.Tracef("END   TX <0x%x> duration=%s", txo.ID(), duration)
+		quota.OnExecution(duration)
 		cnt++
 	}
 	return nil
@@ -29,10 +29,26 @@ func CheckTxTimestamp(min, max int64, tx transaction.Transaction) error {
 
 type TxTimestampChecker struct {
 	threshold int64
+	window    int64
 }
 
 func (c *TxTimestampChecker) CheckWithCurrent(min int64, tx transaction.Transaction) error {
-	return CheckTxTimestamp(min, (time.Now().UnixNano()/1000)+c.Threshold(), tx)
+	return CheckTxTimestamp(min, (time.Now().UnixNano()/1000)+c.Window(), tx)
+}
+
+// SetWindow sets the maximum difference of the timestamp of a new
+// transaction from the current time. It uses the threshold if it's zero.
+func (c *TxTimestampChecker) SetWindow(d time.Duration) {
+	atomic.StoreInt64(&c.window, DurationToTimestamp(d))
+}
+
+// Window returns the maximum difference of the timestamp of a new
+// transaction from the current time.
+func (c *TxTimestampChecker) Window() int64 {
+	if w := atomic.LoadInt64(&c.window); w > 0 {
+		return w
+	}
+	return c.Threshold()
 }
 
 func (c *TxTimestampChecker) SetThreshold(d time.Duration) {
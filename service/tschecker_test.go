@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxTimestampChecker_Window(t *testing.T) {
+	tsc := NewTimestampChecker()
+	assert.Equal(t, ConfigTXTimestampThresholdDefault, tsc.Window())
+
+	tsc.SetThreshold(time.Minute)
+	assert.Equal(t, DurationToTimestamp(time.Minute), tsc.Window())
+
+	tsc.SetWindow(10 * time.Minute)
+	assert.Equal(t, DurationToTimestamp(10*time.Minute), tsc.Window())
+	assert.Equal(t, DurationToTimestamp(time.Minute), tsc.Threshold())
+
+	tsc.SetWindow(0)
+	assert.Equal(t, DurationToTimestamp(time.Minute), tsc.Window())
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type TxListener func(tx module.Transaction)
+
+type txListener struct {
+	cb TxListener
+}
+
+// TxFeed notifies listeners of transactions accepted into the transaction
+// pool, whether they are sent to this node or received from peers.
+type TxFeed struct {
+	lock      sync.Mutex
+	listeners map[*txListener]struct{}
+}
+
+// AddListener adds the listener and returns the function to remove it.
+// The listener is called while the transaction manager is locked, so it
+// shouldn't be blocked.
+func (f *TxFeed) AddListener(cb TxListener) func() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	l := &txListener{cb: cb}
+	f.listeners[l] = struct{}{}
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		delete(f.listeners, l)
+	}
+}
+
+func (f *TxFeed) notify(tx module.Transaction) {
+	f.lock.Lock()
+	if len(f.listeners) == 0 {
+		f.lock.Unlock()
+		return
+	}
+	listeners := make([]*txListener, 0, len(f.listeners))
+	for l := range f.listeners {
+		listeners = append(listeners, l)
+	}
+	f.lock.Unlock()
+
+	for _, l := range listeners {
+		l.cb(tx)
+	}
+}
+
+func NewTxFeed() *TxFeed {
+	return &TxFeed{
+		listeners: make(map[*txListener]struct{}),
+	}
+}
+
+// TxFeedSource is implemented by service managers supporting
+// notifications of transactions accepted into the pool.
+type TxFeedSource interface {
+	TxFeed() *TxFeed
+}
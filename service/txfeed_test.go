@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestTxFeed_Notify(t *testing.T) {
+	feed := NewTxFeed()
+
+	// no listeners
+	feed.notify(nil)
+
+	var got1, got2 int
+	remove1 := feed.AddListener(func(tx module.Transaction) {
+		got1++
+	})
+	remove2 := feed.AddListener(func(tx module.Transaction) {
+		got2++
+	})
+
+	feed.notify(nil)
+	feed.notify(nil)
+	assert.Equal(t, 2, got1)
+	assert.Equal(t, 2, got2)
+
+	remove1()
+	feed.notify(nil)
+	assert.Equal(t, 2, got1)
+	assert.Equal(t, 3, got2)
+
+	remove2()
+	feed.notify(nil)
+	assert.Equal(t, 3, got2)
+}
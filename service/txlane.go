@@ -0,0 +1,55 @@
+package service
+
+import (
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+var defaultGovernance = common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+
+type governanceInfo struct {
+	address module.Address
+	owner   module.Address
+}
+
+// priorityLane selects transactions using the priority lane of propagation
+// and the transaction pool, so that congestion of user transactions doesn't
+// delay governance actions. Patch transactions and the transactions to the
+// governance contract from its owner use the lane.
+type priorityLane struct {
+	governance atomic.Value
+}
+
+// update updates the governance contract and its owner with the world of
+// the last finalized block.
+func (l *priorityLane) update(wss state.WorldSnapshot) {
+	var gov module.Address
+	if as := scoredb.NewStateStoreWith(wss.GetAccountSnapshot(state.SystemID)); as != nil {
+		gov = scoredb.NewVarDB(as, state.VarGovernance).Address()
+	}
+	if gov == nil {
+		gov = defaultGovernance
+	}
+	gi := &governanceInfo{address: gov}
+	if as := wss.GetAccountSnapshot(gov.ID()); as != nil && as.IsContract() {
+		gi.owner = as.ContractOwner()
+	}
+	l.governance.Store(gi)
+}
+
+func (l *priorityLane) isPriority(tx transaction.Transaction) bool {
+	if tx.Group() == module.TransactionGroupPatch {
+		return true
+	}
+	gi, ok := l.governance.Load().(*governanceInfo)
+	if !ok || gi.owner == nil {
+		return false
+	}
+	to, from := tx.To(), tx.From()
+	return to != nil && from != nil && to.Equal(gi.address) && from.Equal(gi.owner)
+}
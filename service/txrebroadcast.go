@@ -0,0 +1,138 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	txRebroadcastInterval = 10 * time.Second
+	// txRebroadcastAge is the time which transactions stay in the pool
+	// without being sent before they are sent again.
+	txRebroadcastAge = 30 * time.Second
+	// txRebroadcastTxsMax and txRebroadcastPeersMax limit the number of
+	// transactions for each interval and the number of peers for each
+	// transaction.
+	txRebroadcastTxsMax   = 50
+	txRebroadcastPeersMax = 4
+)
+
+// staleTx is the transaction in the pool with the time when it's sent to
+// peers last.
+type staleTx struct {
+	tx   transaction.Transaction
+	sent time.Time
+}
+
+// txRebroadcaster sends transactions staying long in the pool again to the
+// peers joined after they were sent. Transactions accepted just before
+// peers leave may not reach other validators, and they would stay in the
+// pool of this node only. Receivers relay them to the validators.
+type txRebroadcaster struct {
+	lock  sync.Mutex
+	peers map[string]txPeer
+
+	tm  *TransactionManager
+	ph  module.ProtocolHandler
+	log log.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type txPeer struct {
+	id     module.PeerID
+	joined time.Time
+}
+
+func (rb *txRebroadcaster) onJoin(id module.PeerID) {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	rb.peers[id.String()] = txPeer{id, time.Now()}
+}
+
+func (rb *txRebroadcaster) onLeave(id module.PeerID) {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	delete(rb.peers, id.String())
+}
+
+// peersJoinedAfter returns up to max peers joined after the time.
+func (rb *txRebroadcaster) peersJoinedAfter(t time.Time, max int) []module.PeerID {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	var peers []module.PeerID
+	for _, p := range rb.peers {
+		if len(peers) >= max {
+			break
+		}
+		if p.joined.After(t) {
+			peers = append(peers, p.id)
+		}
+	}
+	return peers
+}
+
+// rebroadcast sends stale transactions to the peers joined after they were
+// sent, and returns the number of sent messages. Transactions without such
+// peers are also marked as sent, because all the peers have seen them.
+func (rb *txRebroadcaster) rebroadcast(now time.Time) int {
+	txs := rb.tm.StaleTxs(now.Add(-txRebroadcastAge), now, txRebroadcastTxsMax)
+	cnt := 0
+	for _, stx := range txs {
+		for _, id := range rb.peersJoinedAfter(stx.sent, txRebroadcastPeersMax) {
+			if err := rb.ph.Unicast(protoPropagateTransaction, stx.tx.Bytes(), id); err != nil {
+				rb.log.Debugf("Fail to rebroadcast transaction id=%#x to=%s err=%+v",
+					stx.tx.ID(), id.String(), err)
+				continue
+			}
+			cnt += 1
+		}
+	}
+	if cnt > 0 {
+		rb.log.Debugf("Rebroadcast transactions stale=%d sent=%d", len(txs), cnt)
+	}
+	return cnt
+}
+
+func (rb *txRebroadcaster) run() {
+	defer close(rb.done)
+
+	ticker := time.NewTicker(txRebroadcastInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			rb.rebroadcast(now)
+		case <-rb.stop:
+			return
+		}
+	}
+}
+
+func (rb *txRebroadcaster) Start(ph module.ProtocolHandler) {
+	rb.ph = ph
+	go rb.run()
+}
+
+func (rb *txRebroadcaster) Stop() {
+	close(rb.stop)
+	<-rb.done
+}
+
+func newTxRebroadcaster(tm *TransactionManager) *txRebroadcaster {
+	return &txRebroadcaster{
+		peers: make(map[string]txPeer),
+		tm:    tm,
+		log:   tm.Logger(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
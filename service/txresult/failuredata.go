@@ -0,0 +1,42 @@
+package txresult
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+// FailureDataOf returns the payload carried by the revert of the receipt.
+// It returns nil if the receipt is not reverted by the contract or the
+// reason of the failure is not available.
+func FailureDataOf(r Receipt) []byte {
+	if r.Status() < module.StatusReverted {
+		return nil
+	}
+	reason := r.Reason()
+	if reason == nil {
+		return nil
+	}
+	if msg := reason.Error(); len(msg) > 0 {
+		return []byte(msg)
+	}
+	return nil
+}
+
+// StoreFailureData stores the failure data of the transaction.
+func StoreFailureData(dbase db.Database, txHash []byte, data []byte) error {
+	bk, err := dbase.GetBucket(db.FailureDataByHash)
+	if err != nil {
+		return err
+	}
+	return bk.Set(txHash, data)
+}
+
+// GetFailureData returns the failure data of the transaction stored by
+// StoreFailureData. It returns nil if there is no data for the transaction.
+func GetFailureData(dbase db.Database, txHash []byte) ([]byte, error) {
+	bk, err := dbase.GetBucket(db.FailureDataByHash)
+	if err != nil {
+		return nil, err
+	}
+	return bk.Get(txHash)
+}
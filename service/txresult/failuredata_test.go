@@ -0,0 +1,43 @@
+package txresult
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestFailureData(t *testing.T) {
+	dbase := db.NewMapDB()
+	to := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	zero := big.NewInt(0)
+
+	r1 := NewReceipt(dbase, module.LatestRevision, to)
+	r1.SetResult(module.StatusReverted+1, zero, zero, nil)
+	r1.SetReason(errors.New("insufficient allowance"))
+	assert.Equal(t, []byte("insufficient allowance"), FailureDataOf(r1))
+
+	r2 := NewReceipt(dbase, module.LatestRevision, to)
+	r2.SetResult(module.StatusOutOfStep, zero, zero, nil)
+	r2.SetReason(errors.New("out of step"))
+	assert.Nil(t, FailureDataOf(r2))
+
+	r3 := NewReceipt(dbase, module.LatestRevision, to)
+	r3.SetResult(module.StatusReverted, zero, zero, nil)
+	assert.Nil(t, FailureDataOf(r3))
+
+	txHash := []byte{0x01, 0x02}
+	data, err := GetFailureData(dbase, txHash)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	assert.NoError(t, StoreFailureData(dbase, txHash, FailureDataOf(r1)))
+	data, err = GetFailureData(dbase, txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("insufficient allowance"), data)
+}
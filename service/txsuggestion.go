@@ -0,0 +1,63 @@
+package service
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// TxSuggestion is the suggested timestamp and nonce for the next transaction
+// of the address. Transactions with the same parameters have the same hash,
+// so a wallet sending them rapidly may have the transaction refused as a
+// duplicate of the pending one. The suggestion follows pending transactions
+// of the address to avoid it.
+type TxSuggestion struct {
+	Pending       int
+	LastTimestamp int64
+	Timestamp     int64
+	Nonce         *big.Int
+}
+
+// suggestTx returns the suggestion for the pending transactions of the
+// address in order of timestamps. The timestamp is the current time or the
+// next of the last pending one, and the nonce is the next of the highest
+// nonce of pending ones, or zero if they don't have one.
+func suggestTx(txs []transaction.Transaction, now int64) *TxSuggestion {
+	s := &TxSuggestion{
+		Pending:   len(txs),
+		Timestamp: now,
+		Nonce:     new(big.Int),
+	}
+	for _, tx := range txs {
+		if ts := tx.Timestamp(); ts > s.LastTimestamp {
+			s.LastTimestamp = ts
+		}
+		if nonce := tx.Nonce(); nonce != nil && nonce.Cmp(s.Nonce) >= 0 {
+			s.Nonce.Add(nonce, big.NewInt(1))
+		}
+	}
+	if s.LastTimestamp >= s.Timestamp {
+		s.Timestamp = s.LastTimestamp + 1
+	}
+	return s
+}
+
+// SuggestTx returns the suggestion for the next normal transaction of the
+// address.
+func (m *TransactionManager) SuggestTx(from module.Address) *TxSuggestion {
+	txs := m.normalTxPool.TxsOf(from)
+	return suggestTx(txs, time.Now().UnixNano()/int64(time.Microsecond))
+}
+
+// TxSuggestionOf returns the suggestion for the next transaction of the
+// address with the transaction pool of the service manager.
+func TxSuggestionOf(sm module.ServiceManager, from module.Address) (*TxSuggestion, error) {
+	mgr, ok := sm.(*manager)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoTxSuggestion(sm=%T)", sm)
+	}
+	return mgr.tm.SuggestTx(from), nil
+}
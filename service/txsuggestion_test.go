@@ -0,0 +1,34 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+func TestSuggestTx(t *testing.T) {
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+
+	s := suggestTx(nil, 100)
+	assert.Equal(t, 0, s.Pending)
+	assert.EqualValues(t, 0, s.LastTimestamp)
+	assert.EqualValues(t, 100, s.Timestamp)
+	assert.EqualValues(t, 0, s.Nonce.Int64())
+
+	tx1 := newMockTransaction([]byte{0x01}, from, 90)
+	tx1.nonce = big.NewInt(5)
+	tx2 := newMockTransaction([]byte{0x02}, from, 100)
+	s = suggestTx([]transaction.Transaction{tx1, tx2}, 100)
+	assert.Equal(t, 2, s.Pending)
+	assert.EqualValues(t, 100, s.LastTimestamp)
+	assert.EqualValues(t, 101, s.Timestamp)
+	assert.EqualValues(t, 6, s.Nonce.Int64())
+
+	s = suggestTx([]transaction.Transaction{tx1}, 100)
+	assert.EqualValues(t, 90, s.LastTimestamp)
+	assert.EqualValues(t, 100, s.Timestamp)
+}
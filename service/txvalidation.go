@@ -0,0 +1,66 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+type finalizedResult struct {
+	result []byte
+	bi     module.BlockInfo
+}
+
+// checkTxByContract asks the validator contract of the sender whether the
+// transaction failing signature verification can be added to the pool.
+// It uses the state of the last finalized block.
+func (m *manager) checkTxByContract(tx transaction.Transaction) error {
+	fr, ok := m.finalized.Load().(*finalizedResult)
+	if !ok {
+		return errors.InvalidStateError.New("NoFinalizedResult")
+	}
+	wss, err := m.trc.GetWorldSnapshot(fr.result, nil)
+	if err != nil {
+		return err
+	}
+	wc := state.NewWorldContext(state.NewReadOnlyWorldState(wss), fr.bi, nil, m.plt)
+	validator := state.TxValidatorOf(wc, tx.From())
+	if validator == nil {
+		return transaction.InvalidSignatureError.New("NoTxValidator")
+	}
+	data, err := transaction.ValidationCallData(transaction.TxCheckMethod, tx)
+	if err != nil {
+		return err
+	}
+	qh, err := NewQueryHandler(m.cm, validator, data, nil)
+	if err != nil {
+		return err
+	}
+	ret, err := qh.Query(contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery))
+	if err != nil {
+		return err
+	}
+	if ret != "0x1" {
+		return transaction.InvalidSignatureError.Errorf("RejectedByValidator(validator=%s)", validator)
+	}
+	return nil
+}
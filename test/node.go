@@ -86,7 +86,7 @@ func NewNode(t *testing.T, o ...FixtureOption) *Node {
 	ctx.Platform = plt
 	cm, err := plt.NewContractManager(c.Database(), path.Join(base, ContractPath), c.Logger())
 	assert.NoError(t, err)
-	ee, err := eeproxy.AllocEngines(c.Logger(), "python")
+	ee, err := eeproxy.AllocEngines(c.Logger(), nil, "python")
 	assert.NoError(t, err)
 	em, err := eeproxy.NewManager("unix", path.Join(base, EESocketPath), c.Logger(), ee...)
 	assert.NoError(t, err)